@@ -72,6 +72,11 @@ type InferenceModelSpec struct {
 	// Names can be reserved without an underlying model configured in the pool.
 	// This can be done by specifying a target model and setting the weight to zero,
 	// an error will be returned specifying that no valid target model is found.
+	// A ModelName containing "*" is a pattern, where "*" matches any sequence of characters, e.g.
+	// "llama-3.1-*" matches the requests for all the llama-3.1 fine-tunes. A request is served by the
+	// InferenceModel whose ModelName is exactly the requested model if any, or else by the one with
+	// the most specific matching pattern, i.e. the pattern with the most non-wildcard characters,
+	// the oldest one winning ties.
 	//
 	// +kubebuilder:validation:MaxLength=256
 	// +kubebuilder:validation:Required
@@ -89,6 +94,18 @@ type InferenceModelSpec struct {
 	// +optional
 	Criticality *Criticality `json:"criticality,omitempty"`
 
+	// FairShareWeight is the weight of the model in the fair share of the pool among the models of
+	// the same Criticality. When the pool is saturated and requests are queued, the queued requests
+	// of the models of a Criticality are dispatched in proportion to the weights of the models, so a
+	// model with a burst of requests can't starve the others.
+	//
+	// Defaults to 1 when unset.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000000
+	FairShareWeight *int32 `json:"fairShareWeight,omitempty"`
+
 	// TargetModels allow multiple versions of a model for traffic splitting.
 	// If not specified, the target model name is defaulted to the modelName parameter.
 	// modelName is often in reference to a LoRA adapter.
@@ -102,6 +119,134 @@ type InferenceModelSpec struct {
 	//
 	// +kubebuilder:validation:Required
 	PoolRef PoolObjectReference `json:"poolRef"`
+
+	// Lifecycle controls when the model is served, e.g. to prepare the launch of a model name or to
+	// sunset one. The requests for a model that isn't active are rejected with its InactiveResponse,
+	// while the InferenceModel keeps its name reserved in the pool.
+	//
+	// The model is active when unset.
+	//
+	// +optional
+	Lifecycle *ModelLifecycle `json:"lifecycle,omitempty"`
+
+	// Matches route the requests matching any of them to the model, whatever the model field of
+	// their body, for the clients that can't set the model name. The model field of the body of a
+	// matching request is set to ModelName. The matches of an InferenceModel whose ModelName is a
+	// pattern are ignored.
+	//
+	// When several InferenceModels match a request, the one matching the longest path prefix, then
+	// the most headers, takes precedence, and the oldest one, based on creation timestamp, wins
+	// ties.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	Matches []ModelMatch `json:"matches,omitempty"`
+
+	// Aliases are other model names accepted for the model, e.g. the vendor-style names used by
+	// the clients. The model field of the body of a request for an alias is rewritten to ModelName
+	// before the request is forwarded. A model name that is the ModelName of an InferenceModel
+	// takes precedence over the aliases, and the oldest InferenceModel, based on creation
+	// timestamp, wins when several share an alias. The aliases of an InferenceModel whose
+	// ModelName is a pattern are ignored.
+	//
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=256
+	// +kubebuilder:validation:XValidation:message="aliases must not be patterns",rule="self.all(alias, !alias.contains('*'))"
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ModelMatch matches the requests by their attributes other than the model field of their body.
+// A request matches if it matches all the set conditions, at least one of which must be set.
+//
+// +kubebuilder:validation:XValidation:message="pathPrefix or headers must be set",rule="has(self.pathPrefix) || (has(self.headers) && size(self.headers) > 0)"
+type ModelMatch struct {
+	// PathPrefix matches the requests whose path, without its query, starts with the given path
+	// elements, e.g. "/food-review" matches "/food-review" and "/food-review/v1/completions", but
+	// not "/food-reviews".
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:Pattern=`^/`
+	PathPrefix *string `json:"pathPrefix,omitempty"`
+
+	// Headers matches the requests having all the given headers with exactly the given values.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	Headers []HeaderMatch `json:"headers,omitempty"`
+}
+
+// HeaderMatch matches a request header by its exact value.
+type HeaderMatch struct {
+	// Name is the name of the header, which is case insensitive.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9!#$%&'*+\-.^_\x60|~]+$`
+	Name string `json:"name"`
+
+	// Value is the exact value of the header.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=4096
+	Value string `json:"value"`
+}
+
+// ModelLifecycle controls when an InferenceModel is served. The model is active when it isn't
+// paused, and within its activation window, if any.
+//
+// +kubebuilder:validation:XValidation:message="activeFrom must be before activeUntil",rule="!has(self.activeFrom) || !has(self.activeUntil) || self.activeFrom < self.activeUntil"
+type ModelLifecycle struct {
+	// Paused deactivates the model, whatever its activation window.
+	//
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// ActiveFrom is the time from which the model is active, e.g. the launch time of a model name.
+	//
+	// +optional
+	ActiveFrom *metav1.Time `json:"activeFrom,omitempty"`
+
+	// ActiveUntil is the time from which the model is no longer active, e.g. the sunset time of a
+	// model name.
+	//
+	// +optional
+	ActiveUntil *metav1.Time `json:"activeUntil,omitempty"`
+
+	// InactiveResponse is the response to the requests for the model while it isn't active.
+	//
+	// Defaults to a 503 (Service Unavailable) response describing why the model isn't active.
+	//
+	// +optional
+	InactiveResponse *InactiveModelResponse `json:"inactiveResponse,omitempty"`
+}
+
+// InactiveModelResponse is the response to the requests for an InferenceModel that isn't active.
+type InactiveModelResponse struct {
+	// StatusCode is the HTTP status code of the response, e.g. 404 (Not Found) or 410 (Gone) for a
+	// model name that was sunset.
+	//
+	// Defaults to 503 (Service Unavailable) when unset.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=400
+	// +kubebuilder:validation:Maximum=599
+	StatusCode *int32 `json:"statusCode,omitempty"`
+
+	// Message is the body of the response, e.g. pointing the clients to the model replacing a model
+	// that was sunset.
+	//
+	// Defaults to a message describing why the model isn't active when unset.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message,omitempty"`
 }
 
 // PoolObjectReference identifies an API object within the namespace of the
@@ -185,6 +330,8 @@ type InferenceModelStatus struct {
 	// Known condition types are:
 	//
 	// * "Accepted"
+	// * "Servable"
+	// * "AdapterAvailable"
 	//
 	// +optional
 	// +listType=map
@@ -192,6 +339,22 @@ type InferenceModelStatus struct {
 	// +kubebuilder:validation:MaxItems=8
 	// +kubebuilder:default={{type: "Ready", status: "Unknown", reason:"Pending", message:"Waiting for controller", lastTransitionTime: "1970-01-01T00:00:00Z"}}
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// PoolRef is the InferencePool whose endpoint picker serves the model.
+	//
+	// +optional
+	PoolRef *PoolObjectReference `json:"poolRef,omitempty"`
+
+	// CapableEndpoints is the number of endpoints of the pool that can currently serve the model.
+	//
+	// +optional
+	CapableEndpoints *int32 `json:"capableEndpoints,omitempty"`
+
+	// AdapterEndpoints is the number of endpoints of the pool that currently advertise one of the
+	// target models as a loaded LoRA adapter.
+	//
+	// +optional
+	AdapterEndpoints *int32 `json:"adapterEndpoints,omitempty"`
 }
 
 // InferenceModelConditionType is a type of condition for the InferenceModel.
@@ -227,3 +390,66 @@ const (
 	// ModelReasonPending is the initial state, and indicates that the controller has not yet reconciled the InferenceModel.
 	ModelReasonPending InferenceModelConditionReason = "Pending"
 )
+
+const (
+	// ModelConditionServable indicates if any endpoint of the pool can currently serve the model,
+	// and if not, why.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "EndpointsAvailable"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "NoEndpoints"
+	// * "EndpointsCordoned"
+	// * "AdapterNotLoadable"
+	// * "Paused"
+	// * "Inactive"
+	//
+	ModelConditionServable InferenceModelConditionType = "Servable"
+
+	// ModelReasonEndpointsAvailable is used when at least one endpoint of the pool can serve the model.
+	ModelReasonEndpointsAvailable InferenceModelConditionReason = "EndpointsAvailable"
+
+	// ModelReasonNoEndpoints is used when the pool has no ready endpoints.
+	ModelReasonNoEndpoints InferenceModelConditionReason = "NoEndpoints"
+
+	// ModelReasonEndpointsCordoned is used when all the endpoints of the pool are cordoned.
+	ModelReasonEndpointsCordoned InferenceModelConditionReason = "EndpointsCordoned"
+
+	// ModelReasonAdapterNotLoadable is used when the target models are LoRA adapters that no
+	// available endpoint has loaded or is able to load.
+	ModelReasonAdapterNotLoadable InferenceModelConditionReason = "AdapterNotLoadable"
+
+	// ModelReasonPaused is used when the lifecycle of the model pauses it.
+	ModelReasonPaused InferenceModelConditionReason = "Paused"
+
+	// ModelReasonInactive is used when the current time is outside of the activation window of the
+	// lifecycle of the model.
+	ModelReasonInactive InferenceModelConditionReason = "Inactive"
+)
+
+const (
+	// ModelConditionAdapterAvailable indicates if any available endpoint of the pool advertises one
+	// of the target models as a loaded LoRA adapter, so the rollout of a new adapter can be gated on
+	// it. It's False for the models whose target models are base models rather than adapters.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "AdapterLoaded"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "AdapterNotLoaded"
+	//
+	ModelConditionAdapterAvailable InferenceModelConditionType = "AdapterAvailable"
+
+	// ModelReasonAdapterLoaded is used when at least one available endpoint has loaded one of the
+	// target models as an adapter.
+	ModelReasonAdapterLoaded InferenceModelConditionReason = "AdapterLoaded"
+
+	// ModelReasonAdapterNotLoaded is used when no available endpoint has loaded any of the target
+	// models as an adapter.
+	ModelReasonAdapterNotLoaded InferenceModelConditionReason = "AdapterNotLoaded"
+)
@@ -155,6 +155,10 @@ type InferencePoolStatus struct {
 	// A maximum of 32 Gateways will be represented in this list. An empty list
 	// means the route has not been attached to any Gateway.
 	//
+	// The parent whose parentRef has the "Status" kind and the "default" name
+	// is not a Gateway. It holds the conditions reported by the endpoint picker
+	// of the pool.
+	//
 	// +kubebuilder:validation:MaxItems=32
 	Parents []PoolStatus `json:"parent,omitempty"`
 }
@@ -170,6 +174,8 @@ type PoolStatus struct {
 	//
 	// * "Accepted"
 	// * "ResolvedRefs"
+	// * "EndpointsReady"
+	// * "Saturated"
 	//
 	// +optional
 	// +listType=map
@@ -244,3 +250,65 @@ const (
 	// or API group, or a reference to a resource that can not be found.
 	InferencePoolReasonInvalidExtensionRef InferencePoolReason = "InvalidExtensionRef"
 )
+
+const (
+	// PoolStatusEndpointPickerKind and PoolStatusEndpointPickerName identify the
+	// parent of the InferencePool status that holds the conditions reported by
+	// the endpoint picker, rather than by a Gateway.
+	PoolStatusEndpointPickerKind = "Status"
+	PoolStatusEndpointPickerName = "default"
+)
+
+const (
+	// This condition indicates whether the endpoints selected by the
+	// InferencePool report their metrics to the endpoint picker.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "EndpointsReady"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "NoEndpoints"
+	// * "MetricsUnavailable"
+	InferencePoolConditionEndpointsReady InferencePoolConditionType = "EndpointsReady"
+
+	// This reason is used with the "EndpointsReady" condition when at least one
+	// endpoint reported its metrics recently.
+	InferencePoolReasonEndpointsReady InferencePoolReason = "EndpointsReady"
+
+	// This reason is used with the "EndpointsReady" and "Saturated" conditions
+	// when the InferencePool selects no ready endpoint.
+	InferencePoolReasonNoEndpoints InferencePoolReason = "NoEndpoints"
+
+	// This reason is used with the "EndpointsReady" condition when none of the
+	// endpoints reported its metrics recently.
+	InferencePoolReasonMetricsUnavailable InferencePoolReason = "MetricsUnavailable"
+)
+
+const (
+	// This condition indicates whether all the endpoints of the InferencePool
+	// are above the saturation thresholds of the endpoint picker, in which case
+	// the requests that can be queued or shed are.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "Saturated"
+	//
+	// Possible reasons for this condition to be False are:
+	//
+	// * "CapacityAvailable"
+	//
+	// Possible reasons for this condition to be Unknown are:
+	//
+	// * "NoEndpoints"
+	InferencePoolConditionSaturated InferencePoolConditionType = "Saturated"
+
+	// This reason is used with the "Saturated" condition when no endpoint has
+	// capacity for new requests.
+	InferencePoolReasonSaturated InferencePoolReason = "Saturated"
+
+	// This reason is used with the "Saturated" condition when at least one
+	// endpoint has capacity for new requests.
+	InferencePoolReasonCapacityAvailable InferencePoolReason = "CapacityAvailable"
+)
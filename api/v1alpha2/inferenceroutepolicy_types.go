@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InferenceRoutePolicy is the Schema for the InferenceRoutePolicies API. It's a policy attached to
+// an HTTPRoute, or to a rule of an HTTPRoute, customizing how the endpoint picker handles the
+// requests of the route.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Target Kind",type=string,JSONPath=`.spec.targetRef.kind`
+// +kubebuilder:printcolumn:name="Target Name",type=string,JSONPath=`.spec.targetRef.name`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +genclient
+type InferenceRoutePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec InferenceRoutePolicySpec `json:"spec,omitempty"`
+}
+
+// InferenceRoutePolicyList contains a list of InferenceRoutePolicy.
+//
+// +kubebuilder:object:root=true
+type InferenceRoutePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InferenceRoutePolicy `json:"items"`
+}
+
+// InferenceRoutePolicySpec defines how the endpoint picker handles the requests of the target
+// route. The gateway identifies the route of each request to the endpoint picker, see the
+// implementer's guide.
+//
+// A policy targeting a rule of a route takes precedence over a policy targeting the whole route.
+// Among the policies with the same target, the oldest one, based on creation timestamp, is
+// applied.
+type InferenceRoutePolicySpec struct {
+	// TargetRef identifies the HTTPRoute, in the namespace of the policy, the policy applies to.
+	//
+	// +kubebuilder:validation:Required
+	TargetRef RouteTargetReference `json:"targetRef"`
+
+	// DefaultCriticality is the criticality of the requests of the route for the InferenceModels
+	// that don't define one.
+	//
+	// +optional
+	DefaultCriticality *Criticality `json:"defaultCriticality,omitempty"`
+
+	// SchedulingProfile is the name of the scheduling profile the requests of the route are
+	// scheduled with, instead of the profiles selected by the profile picker of the endpoint picker.
+	// The profile picker is used if the endpoint picker has no profile of this name.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	SchedulingProfile *string `json:"schedulingProfile,omitempty"`
+
+	// Streaming defines whether the requests of the route may stream their response. If Disabled,
+	// the requests asking for a streamed response are rejected. Defaults to Allowed.
+	//
+	// +optional
+	Streaming *StreamingMode `json:"streaming,omitempty"`
+
+	// MaxOutputTokens caps the number of tokens the requests of the route may generate: the
+	// max_tokens and max_completion_tokens of the requests are lowered to it, and max_tokens is set
+	// to it for the requests that set neither.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxOutputTokens *int32 `json:"maxOutputTokens,omitempty"`
+}
+
+// RouteTargetReference identifies an HTTPRoute, or a rule of an HTTPRoute, within the namespace of
+// the referrer.
+type RouteTargetReference struct {
+	// Group is the group of the referent.
+	//
+	// +optional
+	// +kubebuilder:default="gateway.networking.k8s.io"
+	Group Group `json:"group,omitempty"`
+
+	// Kind is kind of the referent.
+	//
+	// +optional
+	// +kubebuilder:default="HTTPRoute"
+	// +kubebuilder:validation:Enum=HTTPRoute
+	Kind Kind `json:"kind,omitempty"`
+
+	// Name is the name of the referent.
+	//
+	// +kubebuilder:validation:Required
+	Name ObjectName `json:"name"`
+
+	// SectionName is the name of the rule of the route the policy applies to. If not set, the policy
+	// applies to all the rules of the route.
+	//
+	// +optional
+	SectionName *SectionName `json:"sectionName,omitempty"`
+}
+
+// SectionName is the name of a section of a Kubernetes resource, e.g. the name of a rule of an
+// HTTPRoute.
+//
+// +kubebuilder:validation:MinLength=1
+// +kubebuilder:validation:MaxLength=253
+// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+type SectionName string
+
+// StreamingMode defines whether requests may stream their response.
+// +kubebuilder:validation:Enum=Allowed;Disabled
+type StreamingMode string
+
+const (
+	// StreamingAllowed allows the requests to stream their response.
+	StreamingAllowed StreamingMode = "Allowed"
+
+	// StreamingDisabled rejects the requests asking for a streamed response.
+	StreamingDisabled StreamingMode = "Disabled"
+)
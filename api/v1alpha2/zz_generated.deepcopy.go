@@ -112,6 +112,41 @@ func (in *ExtensionReference) DeepCopy() *ExtensionReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderMatch) DeepCopyInto(out *HeaderMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderMatch.
+func (in *HeaderMatch) DeepCopy() *HeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InactiveModelResponse) DeepCopyInto(out *InactiveModelResponse) {
+	*out = *in
+	if in.StatusCode != nil {
+		in, out := &in.StatusCode, &out.StatusCode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InactiveModelResponse.
+func (in *InactiveModelResponse) DeepCopy() *InactiveModelResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(InactiveModelResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InferenceModel) DeepCopyInto(out *InferenceModel) {
 	*out = *in
@@ -179,6 +214,11 @@ func (in *InferenceModelSpec) DeepCopyInto(out *InferenceModelSpec) {
 		*out = new(Criticality)
 		**out = **in
 	}
+	if in.FairShareWeight != nil {
+		in, out := &in.FairShareWeight, &out.FairShareWeight
+		*out = new(int32)
+		**out = **in
+	}
 	if in.TargetModels != nil {
 		in, out := &in.TargetModels, &out.TargetModels
 		*out = make([]TargetModel, len(*in))
@@ -187,6 +227,23 @@ func (in *InferenceModelSpec) DeepCopyInto(out *InferenceModelSpec) {
 		}
 	}
 	out.PoolRef = in.PoolRef
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(ModelLifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]ModelMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceModelSpec.
@@ -209,6 +266,21 @@ func (in *InferenceModelStatus) DeepCopyInto(out *InferenceModelStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PoolRef != nil {
+		in, out := &in.PoolRef, &out.PoolRef
+		*out = new(PoolObjectReference)
+		**out = **in
+	}
+	if in.CapableEndpoints != nil {
+		in, out := &in.CapableEndpoints, &out.CapableEndpoints
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AdapterEndpoints != nil {
+		in, out := &in.AdapterEndpoints, &out.AdapterEndpoints
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceModelStatus.
@@ -325,6 +397,153 @@ func (in *InferencePoolStatus) DeepCopy() *InferencePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceRoutePolicy) DeepCopyInto(out *InferenceRoutePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceRoutePolicy.
+func (in *InferenceRoutePolicy) DeepCopy() *InferenceRoutePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceRoutePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InferenceRoutePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceRoutePolicyList) DeepCopyInto(out *InferenceRoutePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InferenceRoutePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceRoutePolicyList.
+func (in *InferenceRoutePolicyList) DeepCopy() *InferenceRoutePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceRoutePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InferenceRoutePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferenceRoutePolicySpec) DeepCopyInto(out *InferenceRoutePolicySpec) {
+	*out = *in
+	in.TargetRef.DeepCopyInto(&out.TargetRef)
+	if in.DefaultCriticality != nil {
+		in, out := &in.DefaultCriticality, &out.DefaultCriticality
+		*out = new(Criticality)
+		**out = **in
+	}
+	if in.SchedulingProfile != nil {
+		in, out := &in.SchedulingProfile, &out.SchedulingProfile
+		*out = new(string)
+		**out = **in
+	}
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(StreamingMode)
+		**out = **in
+	}
+	if in.MaxOutputTokens != nil {
+		in, out := &in.MaxOutputTokens, &out.MaxOutputTokens
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferenceRoutePolicySpec.
+func (in *InferenceRoutePolicySpec) DeepCopy() *InferenceRoutePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InferenceRoutePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelLifecycle) DeepCopyInto(out *ModelLifecycle) {
+	*out = *in
+	if in.ActiveFrom != nil {
+		in, out := &in.ActiveFrom, &out.ActiveFrom
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveUntil != nil {
+		in, out := &in.ActiveUntil, &out.ActiveUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.InactiveResponse != nil {
+		in, out := &in.InactiveResponse, &out.InactiveResponse
+		*out = new(InactiveModelResponse)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelLifecycle.
+func (in *ModelLifecycle) DeepCopy() *ModelLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelMatch) DeepCopyInto(out *ModelMatch) {
+	*out = *in
+	if in.PathPrefix != nil {
+		in, out := &in.PathPrefix, &out.PathPrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HeaderMatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelMatch.
+func (in *ModelMatch) DeepCopy() *ModelMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolObjectReference) DeepCopyInto(out *PoolObjectReference) {
 	*out = *in
@@ -363,6 +582,26 @@ func (in *PoolStatus) DeepCopy() *PoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteTargetReference) DeepCopyInto(out *RouteTargetReference) {
+	*out = *in
+	if in.SectionName != nil {
+		in, out := &in.SectionName, &out.SectionName
+		*out = new(SectionName)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTargetReference.
+func (in *RouteTargetReference) DeepCopy() *RouteTargetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteTargetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetModel) DeepCopyInto(out *TargetModel) {
 	*out = *in
@@ -64,6 +64,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&InferenceModelList{},
 		&InferencePool{},
 		&InferencePoolList{},
+		&InferenceRoutePolicy{},
+		&InferenceRoutePolicyList{},
 	)
 	// AddToGroupVersion allows the serialization of client types like ListOptions.
 	v1.AddToGroupVersion(scheme, SchemeGroupVersion)
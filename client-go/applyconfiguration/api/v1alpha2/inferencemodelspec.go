@@ -24,10 +24,14 @@ import (
 // InferenceModelSpecApplyConfiguration represents a declarative configuration of the InferenceModelSpec type for use
 // with apply.
 type InferenceModelSpecApplyConfiguration struct {
-	ModelName    *string                                `json:"modelName,omitempty"`
-	Criticality  *apiv1alpha2.Criticality               `json:"criticality,omitempty"`
-	TargetModels []TargetModelApplyConfiguration        `json:"targetModels,omitempty"`
-	PoolRef      *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
+	ModelName       *string                                `json:"modelName,omitempty"`
+	Criticality     *apiv1alpha2.Criticality               `json:"criticality,omitempty"`
+	FairShareWeight *int32                                 `json:"fairShareWeight,omitempty"`
+	TargetModels    []TargetModelApplyConfiguration        `json:"targetModels,omitempty"`
+	PoolRef         *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
+	Lifecycle       *ModelLifecycleApplyConfiguration      `json:"lifecycle,omitempty"`
+	Matches         []ModelMatchApplyConfiguration         `json:"matches,omitempty"`
+	Aliases         []string                               `json:"aliases,omitempty"`
 }
 
 // InferenceModelSpecApplyConfiguration constructs a declarative configuration of the InferenceModelSpec type for use with
@@ -52,6 +56,14 @@ func (b *InferenceModelSpecApplyConfiguration) WithCriticality(value apiv1alpha2
 	return b
 }
 
+// WithFairShareWeight sets the FairShareWeight field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FairShareWeight field is set to the value of the last call.
+func (b *InferenceModelSpecApplyConfiguration) WithFairShareWeight(value int32) *InferenceModelSpecApplyConfiguration {
+	b.FairShareWeight = &value
+	return b
+}
+
 // WithTargetModels adds the given value to the TargetModels field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the TargetModels field.
@@ -72,3 +84,34 @@ func (b *InferenceModelSpecApplyConfiguration) WithPoolRef(value *PoolObjectRefe
 	b.PoolRef = value
 	return b
 }
+
+// WithLifecycle sets the Lifecycle field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Lifecycle field is set to the value of the last call.
+func (b *InferenceModelSpecApplyConfiguration) WithLifecycle(value *ModelLifecycleApplyConfiguration) *InferenceModelSpecApplyConfiguration {
+	b.Lifecycle = value
+	return b
+}
+
+// WithMatches adds the given value to the Matches field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Matches field.
+func (b *InferenceModelSpecApplyConfiguration) WithMatches(values ...*ModelMatchApplyConfiguration) *InferenceModelSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithMatches")
+		}
+		b.Matches = append(b.Matches, *values[i])
+	}
+	return b
+}
+
+// WithAliases adds the given value to the Aliases field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Aliases field.
+func (b *InferenceModelSpecApplyConfiguration) WithAliases(values ...string) *InferenceModelSpecApplyConfiguration {
+	for i := range values {
+		b.Aliases = append(b.Aliases, values[i])
+	}
+	return b
+}
@@ -24,7 +24,10 @@ import (
 // InferenceModelStatusApplyConfiguration represents a declarative configuration of the InferenceModelStatus type for use
 // with apply.
 type InferenceModelStatusApplyConfiguration struct {
-	Conditions []v1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	Conditions       []v1.ConditionApplyConfiguration       `json:"conditions,omitempty"`
+	PoolRef          *PoolObjectReferenceApplyConfiguration `json:"poolRef,omitempty"`
+	CapableEndpoints *int32                                 `json:"capableEndpoints,omitempty"`
+	AdapterEndpoints *int32                                 `json:"adapterEndpoints,omitempty"`
 }
 
 // InferenceModelStatusApplyConfiguration constructs a declarative configuration of the InferenceModelStatus type for use with
@@ -45,3 +48,27 @@ func (b *InferenceModelStatusApplyConfiguration) WithConditions(values ...*v1.Co
 	}
 	return b
 }
+
+// WithPoolRef sets the PoolRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the PoolRef field is set to the value of the last call.
+func (b *InferenceModelStatusApplyConfiguration) WithPoolRef(value *PoolObjectReferenceApplyConfiguration) *InferenceModelStatusApplyConfiguration {
+	b.PoolRef = value
+	return b
+}
+
+// WithCapableEndpoints sets the CapableEndpoints field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the CapableEndpoints field is set to the value of the last call.
+func (b *InferenceModelStatusApplyConfiguration) WithCapableEndpoints(value int32) *InferenceModelStatusApplyConfiguration {
+	b.CapableEndpoints = &value
+	return b
+}
+
+// WithAdapterEndpoints sets the AdapterEndpoints field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the AdapterEndpoints field is set to the value of the last call.
+func (b *InferenceModelStatusApplyConfiguration) WithAdapterEndpoints(value int32) *InferenceModelStatusApplyConfiguration {
+	b.AdapterEndpoints = &value
+	return b
+}
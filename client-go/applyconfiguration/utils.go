@@ -39,6 +39,10 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &apiv1alpha2.ExtensionConnectionApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("ExtensionReference"):
 		return &apiv1alpha2.ExtensionReferenceApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("HeaderMatch"):
+		return &apiv1alpha2.HeaderMatchApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("InactiveModelResponse"):
+		return &apiv1alpha2.InactiveModelResponseApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("InferenceModel"):
 		return &apiv1alpha2.InferenceModelApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("InferenceModelSpec"):
@@ -51,10 +55,20 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &apiv1alpha2.InferencePoolSpecApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("InferencePoolStatus"):
 		return &apiv1alpha2.InferencePoolStatusApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("InferenceRoutePolicy"):
+		return &apiv1alpha2.InferenceRoutePolicyApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("InferenceRoutePolicySpec"):
+		return &apiv1alpha2.InferenceRoutePolicySpecApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("ModelLifecycle"):
+		return &apiv1alpha2.ModelLifecycleApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("ModelMatch"):
+		return &apiv1alpha2.ModelMatchApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("PoolObjectReference"):
 		return &apiv1alpha2.PoolObjectReferenceApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("PoolStatus"):
 		return &apiv1alpha2.PoolStatusApplyConfiguration{}
+	case v1alpha2.SchemeGroupVersion.WithKind("RouteTargetReference"):
+		return &apiv1alpha2.RouteTargetReferenceApplyConfiguration{}
 	case v1alpha2.SchemeGroupVersion.WithKind("TargetModel"):
 		return &apiv1alpha2.TargetModelApplyConfiguration{}
 
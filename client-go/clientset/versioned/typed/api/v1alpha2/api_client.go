@@ -29,6 +29,7 @@ type InferenceV1alpha2Interface interface {
 	RESTClient() rest.Interface
 	InferenceModelsGetter
 	InferencePoolsGetter
+	InferenceRoutePoliciesGetter
 }
 
 // InferenceV1alpha2Client is used to interact with features provided by the inference.networking.x-k8s.io group.
@@ -44,6 +45,10 @@ func (c *InferenceV1alpha2Client) InferencePools(namespace string) InferencePool
 	return newInferencePools(c, namespace)
 }
 
+func (c *InferenceV1alpha2Client) InferenceRoutePolicies(namespace string) InferenceRoutePolicyInterface {
+	return newInferenceRoutePolicies(c, namespace)
+}
+
 // NewForConfig creates a new InferenceV1alpha2Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
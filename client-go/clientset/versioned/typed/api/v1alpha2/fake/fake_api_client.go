@@ -35,6 +35,10 @@ func (c *FakeInferenceV1alpha2) InferencePools(namespace string) v1alpha2.Infere
 	return newFakeInferencePools(c, namespace)
 }
 
+func (c *FakeInferenceV1alpha2) InferenceRoutePolicies(namespace string) v1alpha2.InferenceRoutePolicyInterface {
+	return newFakeInferenceRoutePolicies(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeInferenceV1alpha2) RESTClient() rest.Interface {
@@ -20,3 +20,5 @@ package v1alpha2
 type InferenceModelExpansion interface{}
 
 type InferencePoolExpansion interface{}
+
+type InferenceRoutePolicyExpansion interface{}
@@ -27,6 +27,8 @@ type Interface interface {
 	InferenceModels() InferenceModelInformer
 	// InferencePools returns a InferencePoolInformer.
 	InferencePools() InferencePoolInformer
+	// InferenceRoutePolicies returns a InferenceRoutePolicyInformer.
+	InferenceRoutePolicies() InferenceRoutePolicyInformer
 }
 
 type version struct {
@@ -49,3 +51,8 @@ func (v *version) InferenceModels() InferenceModelInformer {
 func (v *version) InferencePools() InferencePoolInformer {
 	return &inferencePoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// InferenceRoutePolicies returns a InferenceRoutePolicyInformer.
+func (v *version) InferenceRoutePolicies() InferenceRoutePolicyInformer {
+	return &inferenceRoutePolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -56,6 +56,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Inference().V1alpha2().InferenceModels().Informer()}, nil
 	case v1alpha2.SchemeGroupVersion.WithResource("inferencepools"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Inference().V1alpha2().InferencePools().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("inferenceroutepolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Inference().V1alpha2().InferenceRoutePolicies().Informer()}, nil
 
 	}
 
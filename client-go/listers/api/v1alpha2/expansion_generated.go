@@ -32,3 +32,11 @@ type InferencePoolListerExpansion interface{}
 // InferencePoolNamespaceListerExpansion allows custom methods to be added to
 // InferencePoolNamespaceLister.
 type InferencePoolNamespaceListerExpansion interface{}
+
+// InferenceRoutePolicyListerExpansion allows custom methods to be added to
+// InferenceRoutePolicyLister.
+type InferenceRoutePolicyListerExpansion interface{}
+
+// InferenceRoutePolicyNamespaceListerExpansion allows custom methods to be added to
+// InferenceRoutePolicyNamespaceLister.
+type InferenceRoutePolicyNamespaceListerExpansion interface{}
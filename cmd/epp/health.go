@@ -23,17 +23,22 @@ import (
 	"google.golang.org/grpc/codes"
 	healthPb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
-	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
+// syncedPools is implemented by the datastore of a pool, and by the set of the pools served with a
+// pool selector.
+type syncedPools interface {
+	PoolHasSynced() bool
+}
+
 type healthServer struct {
-	logger    logr.Logger
-	datastore datastore.Datastore
+	logger logr.Logger
+	pools  syncedPools
 }
 
 func (s *healthServer) Check(ctx context.Context, in *healthPb.HealthCheckRequest) (*healthPb.HealthCheckResponse, error) {
-	if !s.datastore.PoolHasSynced() {
+	if !s.pools.PoolHasSynced() {
 		s.logger.V(logutil.DEFAULT).Info("gRPC health check not serving", "service", in.Service)
 		return &healthPb.HealthCheckResponse{Status: healthPb.HealthCheckResponse_NOT_SERVING}, nil
 	}
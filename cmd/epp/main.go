@@ -17,9 +17,18 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,8 +36,13 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	healthPb "google.golang.org/grpc/health/grpc_health_v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -36,18 +50,42 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"sigs.k8s.io/gateway-api-inference-extension/internal/runnable"
+	tlsutil "sigs.k8s.io/gateway-api-inference-extension/internal/tls"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/accesslog"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/custommetrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/debugstream"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/endpointsource"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/failover"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handoff"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics/collectors"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metricspush"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/multipool"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/prewarm"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestlookup"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/routepolicy"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/classifier"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/filter"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/anomaly"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/concurrencylimit"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/retryantiaffinity"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/sessionaffinity"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/ttft"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/weightadapter"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
 	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/scorer"
 	runserver "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/server"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/throughput"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/timeline"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tracing"
 	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
@@ -80,10 +118,72 @@ var (
 		"poolNamespace",
 		runserver.DefaultPoolNamespace,
 		"Namespace of the InferencePool this Endpoint Picker is associated with.")
+	poolSelector = flag.String(
+		"poolSelector",
+		"",
+		"Label selector of the InferencePools of the pool namespace this Endpoint Picker serves, e.g. 'tier=shared', "+
+			"instead of the single pool given by poolName. The gateway identifies the pool of each request in the "+
+			"x-gateway-inference-pool metadata key, or request header with --trustGatewayHeaders.")
+	endpointsFile = flag.String(
+		"endpointsFile",
+		"",
+		"Path to a YAML file listing model servers outside of the cluster, e.g. on VMs, that are part of the pool "+
+			"along with its pods. The file is checked for changes, e.g. when written by a service discovery agent.")
+	endpointSliceService = flag.String(
+		"endpointSliceService",
+		"",
+		"Service (namespace/name) whose ready EndpointSlice endpoints are part of the pool along with its pods, "+
+			"typically in the cluster given by endpointSliceKubeconfig.")
+	endpointSliceKubeconfig = flag.String(
+		"endpointSliceKubeconfig",
+		"",
+		"Path to the kubeconfig of the cluster of endpointSliceService. If not set, the cluster of the Endpoint Picker.")
+	endpointSlicePortName = flag.String(
+		"endpointSlicePortName",
+		"",
+		"Name of the EndpointSlice port the endpoints of endpointSliceService serve on. If not set, they serve on "+
+			"the target port of the pool.")
+	standbyPoolName = flag.String(
+		"standbyPoolName",
+		"",
+		"Name of a warm standby InferencePool, in the namespace of the primary pool, that traffic fails over to when the "+
+			"primary pool is unhealthy. If not set, failover is disabled.")
+	schedulerConfigFile = flag.String(
+		"schedulerConfigFile",
+		"",
+		"Path to a YAML or JSON file declaring the scheduler profiles and their plugins, e.g. mounted from a ConfigMap. "+
+			"If set, it takes precedence over the scheduler configured through environment variables.")
+	stateHandoffPort = flag.Int(
+		"stateHandoffPort",
+		0,
+		"The gRPC port serving the state of the scheduler plugins to the Endpoint Picker replacing this one during an "+
+			"upgrade. The port is unauthenticated, so it must not be exposed outside of the cluster. 0 disables it.")
+	stateHandoffSource = flag.String(
+		"stateHandoffSource",
+		"",
+		"Address (host:port) of the state handoff port of the Endpoint Picker this one replaces. The state of its scheduler "+
+			"plugins is imported on startup. If not set, the plugins start with an empty state.")
+	validateOnly = flag.Bool(
+		"validateOnly",
+		false,
+		"Validates the configuration of the Endpoint Picker given by its flags, environment variables and scheduler "+
+			"config file, prints the effective configuration and exits, with a non-zero status if it's invalid. The "+
+			"InferencePool and InferenceModel manifests given as arguments are checked as well. It doesn't connect to "+
+			"the cluster, so it can be used as a pre-deploy check.")
 	refreshMetricsInterval = flag.Duration(
 		"refreshMetricsInterval",
 		runserver.DefaultRefreshMetricsInterval,
 		"interval to refresh metrics")
+	refreshMetricsMinInterval = flag.Duration(
+		"refreshMetricsMinInterval",
+		0,
+		"Minimum interval to refresh the metrics of a pod. If set with refreshMetricsMaxInterval, the interval adapts to "+
+			"the load of each pod, from refreshMetricsInterval: it's halved when the queue or the KV cache utilization "+
+			"of the pod changes, and doubled when the pod is idle.")
+	refreshMetricsMaxInterval = flag.Duration(
+		"refreshMetricsMaxInterval",
+		0,
+		"Maximum interval to refresh the metrics of a pod, with refreshMetricsMinInterval.")
 	refreshPrometheusMetricsInterval = flag.Duration(
 		"refreshPrometheusMetricsInterval",
 		runserver.DefaultRefreshPrometheusMetricsInterval,
@@ -95,7 +195,35 @@ var (
 		"certPath", "", "The path to the certificate for secure serving. The certificate and private key files "+
 			"are assumed to be named tls.crt and tls.key, respectively. If not set, and secureServing is enabled, "+
 			"then a self-signed certificate is used.")
+	customMetricsPort = flag.Int(
+		"customMetricsPort",
+		0,
+		"The port serving the custom metrics API (custom.metrics.k8s.io) over TLS, to be registered with an APIService "+
+			"so HorizontalPodAutoscalers can scale on the pool saturation and per-model metrics. The certificate of "+
+			"--certPath is used. If not set, the custom metrics API is disabled.")
+	metricsPushPort = flag.Int(
+		"metricsPushPort",
+		0,
+		"The HTTP port where the model servers, or sidecars of them, push their metrics, which are then used instead of "+
+			"the scraped ones while fresh. The pods are identified by the source address of the pushes. The port is "+
+			"unauthenticated, so it must not be exposed outside of the cluster. 0 disables it.")
+	customMetricsClientCAFile = flag.String(
+		"customMetricsClientCAFile",
+		"",
+		"The path to the CA verifying the client certificates of the custom metrics API requests, i.e. the requestheader "+
+			"client CA of the kube-apiserver aggregation layer. If not set, client certificates are not required.")
 	// metric flags
+	modelServerMetrics = flag.String("modelServerMetrics",
+		backendmetrics.ModelServerVLLM,
+		"The model server whose metrics are scraped, e.g. vllm, triton-tensorrt-llm, sglang or llamacpp. It gives the "+
+			"defaults of the metric flags that aren't set. The metric flags name a metric, with optional labels, e.g. "+
+			"'metric{label=value}', and may sum its series, e.g. 'sum(metric)', and divide it by another metric.")
+	modelServerMetricsFile = flag.String("modelServerMetricsFile",
+		"",
+		"Path to a YAML file declaring the metrics of more model servers by name, e.g. mounted from a ConfigMap. In the "+
+			"pools mixing model servers, the pods are labeled with their model server in "+
+			"inference.networking.x-k8s.io/model-server, and the pods of unknown model servers are scheduled by their "+
+			"queue size only.")
 	totalQueuedRequestsMetric = flag.String("totalQueuedRequestsMetric",
 		"vllm:num_requests_waiting",
 		"Prometheus metric for the number of queued requests.")
@@ -106,12 +234,66 @@ var (
 	loraInfoMetric = flag.String("loraInfoMetric",
 		"vllm:lora_requests_info",
 		"Prometheus metric for the LoRA info metrics (must be in vLLM label format).")
+	// KV cache block metrics
+	kvCacheFreeBlocksMetric = flag.String("kvCacheFreeBlocksMetric",
+		"",
+		"Prometheus metric for the number of free KV cache blocks. If not set, the KV cache block metrics are not scraped.")
+	kvCacheFragmentationMetric = flag.String("kvCacheFragmentationMetric",
+		"",
+		"Prometheus metric for the fraction of the free KV cache blocks that can't be allocated to new sequences "+
+			"(from 0 to 1). Optional with --kvCacheFreeBlocksMetric.")
+	// Capacity metrics
+	maxConcurrencyMetric = flag.String("maxConcurrencyMetric",
+		"",
+		"Prometheus metric for the maximum number of concurrent requests a model server admits, e.g. vllm:max_num_seqs "+
+			"if exposed. Used by the max-concurrency filter for the pods not annotated with their maximum concurrency.")
+	// GPU metrics
+	gpuUtilizationMetric = flag.String("gpuUtilizationMetric",
+		"",
+		"Prometheus metric for the GPU utilization percentage (from 0 to 100), e.g. DCGM_FI_DEV_GPU_UTIL. "+
+			"If not set, the GPU metrics are not scraped.")
+	gpuMemoryFreeMetric = flag.String("gpuMemoryFreeMetric",
+		"",
+		"Prometheus metric for the free GPU memory, e.g. DCGM_FI_DEV_FB_FREE. Required with --gpuUtilizationMetric.")
+	gpuMemoryUsedMetric = flag.String("gpuMemoryUsedMetric",
+		"",
+		"Prometheus metric for the used GPU memory, in the unit of --gpuMemoryFreeMetric, e.g. DCGM_FI_DEV_FB_USED. "+
+			"Required with --gpuUtilizationMetric.")
+	gpuMetricsPort = flag.Int("gpuMetricsPort",
+		0,
+		"The port of the GPU metrics exporter, e.g. 9400 for the DCGM exporter. If not set, the GPU metrics are scraped "+
+			"from the model server.")
+	gpuMetricsOnNode = flag.Bool("gpuMetricsOnNode",
+		false,
+		"Whether the GPU metrics exporter runs on the node of the pods (e.g. the DCGM exporter DaemonSet) rather than "+
+			"in the pods. The GPU metrics of a pod are then selected by their pod and namespace labels.")
+	gpuMetricsRefreshInterval = flag.Duration("gpuMetricsRefreshInterval",
+		backendmetrics.DefaultGPUMetricsRefreshInterval,
+		"The minimum interval between two scrapes of the GPU metrics of a pod.")
+	responseTextCaptureLimit = flag.Int("responseTextCaptureLimit",
+		0,
+		fmt.Sprintf("The number of bytes of the text generated in the responses that is captured for the scheduler plugins "+
+			"analyzing it, the end of the longer texts being captured. If not set, the text is not captured, unless the "+
+			"scheduler config file declares the response-anomaly plugin, in which case %d bytes are.", runserver.DefaultResponseTextCaptureLimit))
+	trustGatewayHeaders = flag.Bool("trustGatewayHeaders",
+		false,
+		"Whether the gateway overwrites the x-gateway-inference-* headers identifying the route, the listener and the pool "+
+			"of the requests. If set, those headers are read when the filter metadata doesn't identify them. Otherwise they "+
+			"are ignored, as the clients could set them.")
+	loadReports = flag.Bool("loadReports",
+		false,
+		"Ask the model servers for an ORCA load report (endpoint-load-metrics header) in every response, and update the "+
+			"queue and KV cache metrics of the pods with the reported ones between two scrapes.")
 
 	setupLog = ctrl.Log.WithName("setup")
 
 	// Environment variables
 	schedulerV2           = envutil.GetEnvString("EXPERIMENTAL_USE_SCHEDULER_V2", "false", setupLog)
 	prefixCacheScheduling = envutil.GetEnvString("ENABLE_PREFIX_CACHE_SCHEDULING", "false", setupLog)
+	scorerWeightAdapter   = envutil.GetEnvString("ENABLE_SCORER_WEIGHT_ADAPTATION", "false", setupLog)
+	debugStream           = envutil.GetEnvString("ENABLE_DEBUG_STREAM", "false", setupLog)
+	accessLogIngestion    = envutil.GetEnvString("ENABLE_ACCESS_LOG_INGESTION", "false", setupLog)
+	routePolicies         = envutil.GetEnvString("ENABLE_ROUTE_POLICIES", "false", setupLog)
 )
 
 func loadPrefixCacheConfig() prefix.Config {
@@ -124,6 +306,234 @@ func loadPrefixCacheConfig() prefix.Config {
 	}
 }
 
+func loadSessionAffinityConfig() sessionaffinity.Config {
+	baseLogger := log.Log.WithName("env-config")
+
+	return sessionaffinity.Config{
+		SessionTTL: envutil.GetEnvDuration("SESSION_AFFINITY_TTL", sessionaffinity.DefaultSessionTTL, baseLogger),
+	}
+}
+
+func loadTTFTEstimateConfig() ttft.Config {
+	baseLogger := log.Log.WithName("env-config")
+
+	return ttft.Config{
+		ThroughputWindow:      envutil.GetEnvDuration("TTFT_ESTIMATE_THROUGHPUT_WINDOW", ttft.DefaultThroughputWindow, baseLogger),
+		RunningSequenceWeight: envutil.GetEnvFloat("TTFT_ESTIMATE_RUNNING_SEQUENCE_WEIGHT", ttft.DefaultRunningSequenceWeight, baseLogger),
+	}
+}
+
+func loadQueueTrendWindow() time.Duration {
+	return envutil.GetEnvDuration("QUEUE_TREND_WINDOW", scorer.DefaultQueueTrendWindow, log.Log.WithName("env-config"))
+}
+
+func loadKVCacheBlockSize() int {
+	return envutil.GetEnvInt("KV_CACHE_BLOCK_SIZE", scorer.DefaultKVCacheBlockSize, log.Log.WithName("env-config"))
+}
+
+func loadSaturationTiers() filter.SaturationTiers {
+	baseLogger := log.Log.WithName("env-config")
+
+	tiers := filter.DefaultSaturationTiers()
+	load := func(tier string, thresholds *filter.SaturationThresholds) {
+		prefix := "SATURATION_" + strings.ToUpper(tier)
+		thresholds.QueueThreshold = envutil.GetEnvInt(prefix+"_QUEUE_THRESHOLD", thresholds.QueueThreshold, baseLogger)
+		thresholds.KVCacheThreshold = envutil.GetEnvFloat(prefix+"_KV_CACHE_THRESHOLD", thresholds.KVCacheThreshold, baseLogger)
+	}
+	load(filter.SaturationTierCritical, &tiers.Critical)
+	load(filter.SaturationTierStandard, &tiers.Standard)
+	load(filter.SaturationTierSheddable, &tiers.Sheddable)
+	return tiers
+}
+
+func loadSizeClassTokens() (medium, large int) {
+	baseLogger := log.Log.WithName("env-config")
+
+	return envutil.GetEnvInt("SIZE_CLASS_MEDIUM_TOKENS", classifier.DefaultMediumSizeTokens, baseLogger),
+		envutil.GetEnvInt("SIZE_CLASS_LARGE_TOKENS", classifier.DefaultLargeSizeTokens, baseLogger)
+}
+
+func loadTenantHeader() string {
+	return envutil.GetEnvString("TENANT_HEADER", classifier.DefaultTenantHeader, log.Log.WithName("env-config"))
+}
+
+func loadRetryAntiAffinityConfig() retryantiaffinity.Config {
+	baseLogger := log.Log.WithName("env-config")
+
+	return retryantiaffinity.Config{
+		AttemptTTL: envutil.GetEnvDuration("RETRY_ANTI_AFFINITY_TTL", retryantiaffinity.DefaultAttemptTTL, baseLogger),
+	}
+}
+
+func loadConcurrencyLimitConfig() concurrencylimit.Config {
+	baseLogger := log.Log.WithName("env-config")
+
+	return concurrencylimit.Config{
+		DefaultMaxConcurrency: envutil.GetEnvInt("MAX_CONCURRENCY_DEFAULT", 0, baseLogger),
+		RequestTTL:            envutil.GetEnvDuration("MAX_CONCURRENCY_REQUEST_TTL", concurrencylimit.DefaultRequestTTL, baseLogger),
+	}
+}
+
+// loadResponseAnomalyConfig loads the configuration of the response anomaly plugin. The detectors
+// are listed by name in the RESPONSE_ANOMALY_DETECTORS environment variable (comma separated), all
+// the built-in ones if not set.
+func loadResponseAnomalyConfig() (detectors []string, threshold int, window, cooldown time.Duration) {
+	baseLogger := log.Log.WithName("env-config")
+
+	for _, name := range strings.Split(envutil.GetEnvString("RESPONSE_ANOMALY_DETECTORS", "", baseLogger), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			detectors = append(detectors, name)
+		}
+	}
+	return detectors,
+		envutil.GetEnvInt("RESPONSE_ANOMALY_THRESHOLD", anomaly.DefaultThreshold, baseLogger),
+		envutil.GetEnvDuration("RESPONSE_ANOMALY_WINDOW", anomaly.DefaultWindow, baseLogger),
+		envutil.GetEnvDuration("RESPONSE_ANOMALY_COOLDOWN", anomaly.DefaultCooldown, baseLogger)
+}
+
+func loadWeightAdapterConfig() weightadapter.Config {
+	baseLogger := log.Log.WithName("env-config")
+
+	return weightadapter.Config{
+		MinWeight:         envutil.GetEnvInt("SCORER_WEIGHT_ADAPTATION_MIN_WEIGHT", weightadapter.DefaultMinWeight, baseLogger),
+		MaxWeight:         envutil.GetEnvInt("SCORER_WEIGHT_ADAPTATION_MAX_WEIGHT", weightadapter.DefaultMaxWeight, baseLogger),
+		AdjustmentSamples: envutil.GetEnvInt("SCORER_WEIGHT_ADAPTATION_SAMPLES", weightadapter.DefaultAdjustmentSamples, baseLogger),
+		Tolerance:         envutil.GetEnvFloat("SCORER_WEIGHT_ADAPTATION_TOLERANCE", weightadapter.DefaultTolerance, baseLogger),
+	}
+}
+
+func loadDecisionReuseConfig() scheduling.DecisionReuseConfig {
+	baseLogger := log.Log.WithName("env-config")
+
+	return scheduling.DecisionReuseConfig{
+		MaxCyclesPerSecond: envutil.GetEnvInt("SCHEDULER_DECISION_REUSE_MAX_CYCLES_PER_SECOND", 0, baseLogger),
+		Freshness:          envutil.GetEnvDuration("SCHEDULER_DECISION_REUSE_FRESHNESS", scheduling.DefaultDecisionReuseFreshness, baseLogger),
+		HistorySize:        envutil.GetEnvInt("SCHEDULER_DECISION_REUSE_HISTORY_SIZE", scheduling.DefaultDecisionReuseHistorySize, baseLogger),
+	}
+}
+
+// loadSchedulerEnvironment returns the environment of the scheduler, whose random numbers are
+// drawn from SCHEDULER_RANDOM_SEED if set, e.g. to reproduce the decisions of a replica.
+func loadSchedulerEnvironment() framework.Environment {
+	env := framework.NewEnvironment()
+	if seed := envutil.GetEnvInt("SCHEDULER_RANDOM_SEED", 0, log.Log.WithName("env-config")); seed != 0 {
+		env.Rand = framework.NewRand(int64(seed))
+	}
+	return env
+}
+
+// loadModelFamilyShardingConfig reads the model families the pods are partitioned by. The
+// partitioning is disabled when no family is configured.
+func loadModelFamilyShardingConfig() (scheduling.ModelFamilyShardingConfig, error) {
+	baseLogger := log.Log.WithName("env-config")
+
+	families, err := scheduling.ParseModelFamilies(envutil.GetEnvString("MODEL_FAMILY_SHARDS", "", baseLogger))
+	if err != nil {
+		return scheduling.ModelFamilyShardingConfig{}, fmt.Errorf("invalid MODEL_FAMILY_SHARDS: %w", err)
+	}
+	return scheduling.ModelFamilyShardingConfig{
+		Label:    envutil.GetEnvString("MODEL_FAMILY_LABEL", scheduling.DefaultModelFamilyLabel, baseLogger),
+		Families: families,
+	}, nil
+}
+
+func loadBlueGreenConfig() (blueSelector, greenSelector string, defaultGreenWeight int) {
+	baseLogger := log.Log.WithName("env-config")
+
+	return envutil.GetEnvString("BLUE_GREEN_BLUE_SELECTOR", filter.DefaultBlueSelector, baseLogger),
+		envutil.GetEnvString("BLUE_GREEN_GREEN_SELECTOR", filter.DefaultGreenSelector, baseLogger),
+		envutil.GetEnvInt("BLUE_GREEN_DEFAULT_GREEN_WEIGHT", 0, baseLogger)
+}
+
+// poolAnnotations returns the annotations of the InferencePool of the given datastore, nil if it's
+// not synced yet.
+func poolAnnotations(ds datastore.Datastore) map[string]string {
+	pool, err := ds.PoolGet()
+	if err != nil {
+		return nil
+	}
+	return pool.Annotations
+}
+
+// loadPluginParameters returns the parameters of the plugins configurable through environment
+// variables, encoded as in the scheduler config file.
+func loadPluginParameters() (map[string]json.RawMessage, error) {
+	prefixCache := loadPrefixCacheConfig()
+	sessionAffinity := loadSessionAffinityConfig()
+	retryAntiAffinity := loadRetryAntiAffinityConfig()
+	concurrencyLimit := loadConcurrencyLimitConfig()
+	ttftEstimate := loadTTFTEstimateConfig()
+	tiers := loadSaturationTiers()
+	mediumSizeTokens, largeSizeTokens := loadSizeClassTokens()
+	blueSelector, greenSelector, defaultGreenWeight := loadBlueGreenConfig()
+	anomalyDetectors, anomalyThreshold, anomalyWindow, anomalyCooldown := loadResponseAnomalyConfig()
+	duration := func(d time.Duration) metav1.Duration { return metav1.Duration{Duration: d} }
+	thresholds := func(t filter.SaturationThresholds) map[string]any {
+		return map[string]any{"queueThreshold": t.QueueThreshold, "kvCacheThreshold": t.KVCacheThreshold}
+	}
+
+	parameters := map[string]map[string]any{
+		"prefix-cache": {
+			"hashBlockSize":          prefixCache.HashBlockSize,
+			"maxPrefixBlocksToMatch": prefixCache.MaxPrefixBlocksToMatch,
+			"lruIndexerCapacity":     prefixCache.LRUIndexerCapacity,
+		},
+		"session-affinity":    {"sessionTTL": duration(sessionAffinity.SessionTTL)},
+		"retry-anti-affinity": {"attemptTTL": duration(retryAntiAffinity.AttemptTTL)},
+		"max-concurrency": {
+			"defaultMaxConcurrency": concurrencyLimit.DefaultMaxConcurrency,
+			"requestTTL":            duration(concurrencyLimit.RequestTTL),
+		},
+		"response-anomaly": {
+			"detectors": anomalyDetectors,
+			"threshold": anomalyThreshold,
+			"window":    duration(anomalyWindow),
+			"cooldown":  duration(anomalyCooldown),
+		},
+		"ttft-estimate": {
+			"throughputWindow":      duration(ttftEstimate.ThroughputWindow),
+			"runningSequenceWeight": ttftEstimate.RunningSequenceWeight,
+		},
+		"queue-trend":      {"window": duration(loadQueueTrendWindow())},
+		"kv-fragmentation": {"blockSize": loadKVCacheBlockSize()},
+		"saturation": {
+			"critical":  thresholds(tiers.Critical),
+			"standard":  thresholds(tiers.Standard),
+			"sheddable": thresholds(tiers.Sheddable),
+		},
+		"size-class": {"mediumSizeTokens": mediumSizeTokens, "largeSizeTokens": largeSizeTokens},
+		"tenant":     {"header": loadTenantHeader()},
+		"blue-green": {
+			"blueSelector":       blueSelector,
+			"greenSelector":      greenSelector,
+			"defaultGreenWeight": defaultGreenWeight,
+		},
+	}
+	encoded := make(map[string]json.RawMessage, len(parameters))
+	for name, params := range parameters {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the parameters of plugin '%s': %w", name, err)
+		}
+		encoded[name] = data
+	}
+	return encoded, nil
+}
+
+// pluginHandle is the framework.Handle of the plugins of the scheduler config file.
+type pluginHandle struct {
+	ds         datastore.Datastore
+	parameters map[string]json.RawMessage
+}
+
+func (h *pluginHandle) DefaultParameters(name string) json.RawMessage {
+	return h.parameters[name]
+}
+
+func (h *pluginHandle) PoolAnnotations() map[string]string {
+	return poolAnnotations(h.ds)
+}
+
 func main() {
 	if err := run(); err != nil {
 		os.Exit(1)
@@ -151,6 +561,10 @@ func run() error {
 	})
 	setupLog.Info("Flags processed", "flags", flags)
 
+	if *validateOnly {
+		return validateConfig(os.Stdout, flag.Args())
+	}
+
 	// Init runtime.
 	cfg, err := ctrl.GetConfig()
 	if err != nil {
@@ -159,24 +573,56 @@ func run() error {
 	}
 
 	// Set up mapper for metric scraping.
-	mapping, err := backendmetrics.NewMetricMapping(
-		*totalQueuedRequestsMetric,
-		*kvCacheUsagePercentageMetric,
-		*loraInfoMetric,
-	)
+	pmc, err := newPodMetricsClient()
 	if err != nil {
 		setupLog.Error(err, "Failed to create metric mapping from flags.")
 		return err
 	}
-	verifyMetricMapping(*mapping, setupLog)
-
-	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.PodMetricsClientImpl{MetricMapping: mapping}, *refreshMetricsInterval)
+	pmf := backendmetrics.NewPodMetricsFactory(pmc, *refreshMetricsInterval)
+	if *refreshMetricsMinInterval > 0 {
+		pmf.SetRefreshIntervalBounds(*refreshMetricsMinInterval, *refreshMetricsMaxInterval)
+	}
+	pmf.SetPushedMetricsTTL(envutil.GetEnvDuration("METRICS_PUSH_TTL", backendmetrics.DefaultPushedMetricsTTL, setupLog))
 	// Setup runner.
 	ctx := ctrl.SetupSignalHandler()
 
+	// The spans are exported via OTLP if configured by the standard OTEL_* environment variables.
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		setupLog.Error(err, "Failed to set up tracing")
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "Failed to shut down tracing")
+		}
+	}()
+
 	datastore := datastore.NewDatastore(ctx, pmf)
 
-	customCollectors := []prometheus.Collector{collectors.NewInferencePoolMetricsCollector(datastore)}
+	// With a pool selector, the selected pools are served instead, each with a datastore of its own.
+	pools, err := newPoolSet(ctx, pmf)
+	if err != nil {
+		setupLog.Error(err, "Failed to parse the pool selector")
+		return err
+	}
+	// The access-log ingestion and the prewarming only apply to a single pool.
+	if pools != nil && (accessLogIngestion == "true" || prewarm.LoadConfigFromEnv().Enabled()) {
+		err := fmt.Errorf("the access-log ingestion and the prewarming require the %q flag", "poolName")
+		setupLog.Error(err, "Invalid configuration")
+		return err
+	}
+	poolCollector := collectors.NewInferencePoolMetricsCollector(datastore)
+	if pools != nil {
+		poolCollector = collectors.NewInferencePoolsMetricsCollector(pools.Datastores)
+	}
+
+	// The throughput is only tracked for the requests whose listener is identified by the gateway.
+	throughputTracker := throughput.NewTracker(envutil.GetEnvDuration("LISTENER_THROUGHPUT_WINDOW", throughput.DefaultWindow, setupLog))
+	customCollectors := []prometheus.Collector{
+		poolCollector,
+		collectors.NewListenerThroughputCollector(throughputTracker),
+	}
 	metrics.Register(customCollectors...)
 	metrics.RecordInferenceExtensionInfo()
 	// Register metrics handler.
@@ -184,43 +630,145 @@ func run() error {
 	// More info:
 	// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.1/pkg/metrics/server
 	// - https://book.kubebuilder.io/reference/metrics.html
+	poolNamespacedName := types.NamespacedName{
+		Name:      *poolName,
+		Namespace: *poolNamespace,
+	}
+	adminHandlers := runserver.NewAdminHandlers(datastore, poolNamespacedName.Namespace)
+
+	// With a standby pool, requests are scheduled on the pods of the pool selected by the failover.
+	routingDatastore := datastore
+	standbyPoolNamespacedName := types.NamespacedName{
+		Name:      *standbyPoolName,
+		Namespace: *poolNamespace,
+	}
+	standbyDatastore, poolFailover := setupStandbyFailover(ctx, pmf, datastore, poolNamespacedName, standbyPoolNamespacedName)
+	if poolFailover != nil {
+		routingDatastore = poolFailover
+		adminHandlers[failover.AdminPath] = poolFailover
+	}
+
+	// The debug stream pushes the pods state and the scheduling decisions to a live dashboard.
+	var debugStreamHub *debugstream.Hub
+	if debugStream == "true" {
+		debugStreamHub = debugstream.NewHub(routingDatastore, envutil.GetEnvDuration("DEBUG_STREAM_INTERVAL", debugstream.DefaultInterval, setupLog))
+		for path, handler := range debugStreamHub.Handlers() {
+			adminHandlers[path] = handler
+		}
+	}
+
+	// The gateway posts its access-log records, so that the outcome of the requests that failed open
+	// is recorded as well.
+	var accessLogIngester *accesslog.Ingester
+	if accessLogIngestion == "true" {
+		accessLogIngester = accesslog.NewIngester()
+		adminHandlers[accesslog.IngestPath] = accessLogIngester
+	}
+
+	// The pods joining the pool after a scale-up are warmed up before they take user requests.
+	var prewarmer *prewarm.Prewarmer
+	if prewarmConfig := prewarm.LoadConfigFromEnv(); prewarmConfig.Enabled() {
+		prewarmer = prewarm.NewPrewarmer(prewarmConfig, routingDatastore, prewarm.DefaultCheckInterval)
+	}
+
+	// The records of the recent requests are retained, so that the operators can look up why a request
+	// was slow or shed.
+	var requestLookup *requestlookup.Store
+	if requestLookupConfig := requestlookup.LoadConfigFromEnv(); requestLookupConfig.Enabled() {
+		requestLookup = requestlookup.NewStore(requestLookupConfig)
+		adminHandlers[requestlookup.Path] = requestLookup
+	}
+
+	// The timelines of the slow requests are retained for investigations.
+	var timelineRecorder *timeline.Recorder
+	if timelineConfig := timeline.LoadConfigFromEnv(); timelineConfig.Enabled() {
+		timelineRecorder = timeline.NewRecorder(timelineConfig)
+		adminHandlers[timeline.Path] = timelineRecorder
+	}
+
 	metricsServerOptions := metricsserver.Options{
 		BindAddress:    fmt.Sprintf(":%d", *metricsPort),
 		FilterProvider: filters.WithAuthenticationAndAuthorization,
+		// The admin endpoints (e.g. pod cordoning) are served along with the metrics endpoint.
+		ExtraHandlers: adminHandlers,
 	}
 
-	poolNamespacedName := types.NamespacedName{
-		Name:      *poolName,
-		Namespace: *poolNamespace,
-	}
-	mgr, err := runserver.NewDefaultManager(poolNamespacedName, cfg, metricsServerOptions)
+	mgr, err := runserver.NewDefaultManagerWithStandbyPool(poolNamespacedName, *standbyPoolName, cfg, metricsServerOptions)
 	if err != nil {
 		setupLog.Error(err, "Failed to create controller manager")
 		return err
 	}
+	if err := registerEndpointSources(mgr, datastore, cfg); err != nil {
+		return err
+	}
+	if debugStreamHub != nil {
+		if err := mgr.Add(debugStreamHub); err != nil {
+			setupLog.Error(err, "Failed to register debug stream")
+			return err
+		}
+	}
 
-	scheduler := scheduling.NewScheduler(datastore)
+	scheduler := scheduling.NewScheduler(routingDatastore)
+	textCaptureLimit := *responseTextCaptureLimit
 	if schedulerV2 == "true" {
-		queueScorerWeight := envutil.GetEnvInt("QUEUE_SCORE_WEIGHT", scorer.DefaultQueueScorerWeight, setupLog)
-		kvCacheScorerWeight := envutil.GetEnvInt("KV_CACHE_SCORE_WEIGHT", scorer.DefaultKVCacheScorerWeight, setupLog)
+		schedulerConfig, err := newSchedulerV2Config(debugStreamHub, requestLookup)
+		if err != nil {
+			return err
+		}
+		scheduler = scheduling.NewSchedulerWithConfig(routingDatastore, schedulerConfig)
+	}
+	if *schedulerConfigFile != "" {
+		data, err := os.ReadFile(*schedulerConfigFile)
+		if err != nil {
+			setupLog.Error(err, "Failed to read scheduler config file", "path", *schedulerConfigFile)
+			return err
+		}
+		loadConfig := schedulerConfigLoader(routingDatastore)
+		schedulerConfig, err := loadConfig(data)
+		if err != nil {
+			setupLog.Error(err, "Failed to load scheduler config file", "path", *schedulerConfigFile)
+			return err
+		}
+		scheduler = scheduling.NewSchedulerWithConfig(routingDatastore, schedulerConfig)
+		if textCaptureLimit == 0 && declaresPlugin(data, "response-anomaly") {
+			textCaptureLimit = runserver.DefaultResponseTextCaptureLimit
+		}
 
-		schedulerProfile := framework.NewSchedulerProfile().
-			WithFilters(filter.NewSheddableCapacityFilter()).
-			WithScorers(framework.NewWeightedScorer(&scorer.QueueScorer{}, queueScorerWeight),
-				framework.NewWeightedScorer(&scorer.KVCacheScorer{}, kvCacheScorerWeight)).
-			WithPicker(picker.NewMaxScorePicker())
-
-		if prefixCacheScheduling == "true" {
-			prefixScorerWeight := envutil.GetEnvInt("PREFIX_CACHE_SCORE_WEIGHT", prefix.DefaultScorerWeight, setupLog)
-			if err := schedulerProfile.AddPlugins(framework.NewWeightedScorer(prefix.New(loadPrefixCacheConfig()), prefixScorerWeight)); err != nil {
-				setupLog.Error(err, "Failed to register scheduler plugins")
+		// Reload the scheduler config when the file changes, unless disabled.
+		if interval := envutil.GetEnvDuration("SCHEDULER_CONFIG_FILE_CHECK_INTERVAL", scheduling.DefaultConfigFileCheckInterval, setupLog); interval > 0 {
+			watcher := scheduling.NewConfigFileWatcher(*schedulerConfigFile, data, interval, scheduler, loadConfig)
+			if err := mgr.Add(watcher); err != nil {
+				setupLog.Error(err, "Failed to register scheduler config file watcher")
 				return err
 			}
 		}
+	}
 
-		schedulerConfig := scheduling.NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{"schedulerv2": schedulerProfile})
-		scheduler = scheduling.NewSchedulerWithConfig(datastore, schedulerConfig)
+	// Import the state of the scheduler plugins of the Endpoint Picker being replaced. The Endpoint
+	// Picker starts anyway if it fails, as the plugins relearn their state from the traffic.
+	if *stateHandoffSource != "" {
+		importCtx, cancel := context.WithTimeout(ctx, envutil.GetEnvDuration("STATE_HANDOFF_TIMEOUT", handoff.DefaultImportTimeout, setupLog))
+		if err := handoff.Import(importCtx, *stateHandoffSource, scheduler); err != nil {
+			setupLog.Error(err, "Failed to import the state of the scheduler plugins", "source", *stateHandoffSource)
+		}
+		cancel()
+	}
+	if *stateHandoffPort != 0 {
+		srv := grpc.NewServer()
+		handoff.NewServer(scheduler).Register(srv)
+		if err := mgr.Add(runnable.NoLeaderElection(runnable.GRPCServer("state-handoff", srv, *stateHandoffPort))); err != nil {
+			setupLog.Error(err, "Failed to register state handoff server")
+			return err
+		}
 	}
+
+	// The InferenceRoutePolicies customize the handling of the requests per route. Their CRD must be
+	// installed to enable them.
+	var routePolicyStore *routepolicy.Store
+	if routePolicies == "true" {
+		routePolicyStore = routepolicy.NewStore()
+	}
+
 	serverRunner := &runserver.ExtProcServerRunner{
 		GrpcPort:                                 *grpcPort,
 		DestinationEndpointHintMetadataNamespace: *destinationEndpointHintMetadataNamespace,
@@ -231,6 +779,23 @@ func run() error {
 		CertPath:                                 *certPath,
 		RefreshPrometheusMetricsInterval:         *refreshPrometheusMetricsInterval,
 		Scheduler:                                scheduler,
+		DirectorConfig:                           requestcontrol.LoadConfigFromEnv(),
+		StandbyPoolNamespacedName:                standbyPoolNamespacedName,
+		StandbyDatastore:                         standbyDatastore,
+		Failover:                                 poolFailover,
+		AccessLogIngester:                        accessLogIngester,
+		Prewarmer:                                prewarmer,
+		TimelineRecorder:                         timelineRecorder,
+		RequestLookup:                            requestLookup,
+		ThroughputTracker:                        throughputTracker,
+		RoutePolicies:                            routePolicyStore,
+		ResponseTextCaptureLimit:                 textCaptureLimit,
+		LoadReports:                              *loadReports,
+		TrustGatewayHeaders:                      *trustGatewayHeaders,
+	}
+	if pools != nil {
+		serverRunner.Pools = pools
+		serverRunner.NewPoolScheduler = poolSchedulerFactory(requestLookup)
 	}
 	if err := serverRunner.SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "Failed to setup ext-proc controllers")
@@ -238,10 +803,28 @@ func run() error {
 	}
 
 	// Register health server.
-	if err := registerHealthServer(mgr, ctrl.Log.WithName("health"), datastore, *grpcHealthPort); err != nil {
+	var healthPools syncedPools = datastore
+	if pools != nil {
+		healthPools = pools
+	}
+	if err := registerHealthServer(mgr, ctrl.Log.WithName("health"), healthPools, *grpcHealthPort); err != nil {
 		return err
 	}
 
+	// Register custom metrics server.
+	if *customMetricsPort != 0 {
+		if err := registerCustomMetricsServer(mgr, ctrl.Log.WithName("custom-metrics"), routingDatastore, *customMetricsPort); err != nil {
+			return err
+		}
+	}
+
+	// Register metrics push server.
+	if *metricsPushPort != 0 {
+		if err := registerMetricsPushServer(mgr, pushedMetricsDatastores(datastore, standbyDatastore, pools), *metricsPushPort); err != nil {
+			return err
+		}
+	}
+
 	// Register ext-proc server.
 	if err := mgr.Add(serverRunner.AsRunnable(ctrl.Log.WithName("ext-proc"))); err != nil {
 		setupLog.Error(err, "Failed to register ext-proc gRPC server")
@@ -258,6 +841,165 @@ func run() error {
 	return nil
 }
 
+// newSchedulerV2Config builds the config of the experimental scheduler from the environment
+// variables. The other plugins are declared in the scheduler config file. The decisions are pushed
+// to the given debug stream hub and request lookup store, if not nil.
+func newSchedulerV2Config(debugStreamHub *debugstream.Hub, requestLookup *requestlookup.Store) (*scheduling.SchedulerConfig, error) {
+	queueScorerWeight := envutil.GetEnvInt("QUEUE_SCORE_WEIGHT", scorer.DefaultQueueScorerWeight, setupLog)
+	kvCacheScorerWeight := envutil.GetEnvInt("KV_CACHE_SCORE_WEIGHT", scorer.DefaultKVCacheScorerWeight, setupLog)
+
+	schedulerProfile := framework.NewSchedulerProfile().
+		WithFilters(filter.NewSheddableCapacityFilter()).
+		WithScorers(framework.NewWeightedScorer(&scorer.QueueScorer{}, queueScorerWeight),
+			framework.NewWeightedScorer(&scorer.KVCacheScorer{}, kvCacheScorerWeight)).
+		WithPicker(picker.NewMaxScorePicker().WithFallbacks(envutil.GetEnvInt("MAX_FALLBACK_ENDPOINTS", 0, setupLog))).
+		WithTimeout(envutil.GetEnvDuration("SCHEDULER_CYCLE_TIMEOUT", 0, setupLog)).
+		WithCandidateSampling(envutil.GetEnvInt("SCHEDULER_CANDIDATE_SAMPLE_SIZE", 0, setupLog)).
+		WithScoreFloor(envutil.GetEnvFloat("SCHEDULER_SCORE_FLOOR", 0, setupLog),
+			framework.ScoreFloorPolicy(envutil.GetEnvString("SCHEDULER_SCORE_FLOOR_POLICY", string(framework.ScoreFloorPickBest), setupLog))).
+		WithPluginPanicPolicy(framework.PluginPanicPolicy(envutil.GetEnvString("SCHEDULER_PLUGIN_PANIC_POLICY", string(framework.PluginPanicSkip), setupLog)))
+
+	if prefixCacheScheduling == "true" {
+		prefixScorerWeight := envutil.GetEnvInt("PREFIX_CACHE_SCORE_WEIGHT", prefix.DefaultScorerWeight, setupLog)
+		if err := schedulerProfile.AddPlugins(framework.NewWeightedScorer(prefix.New(loadPrefixCacheConfig()), prefixScorerWeight)); err != nil {
+			setupLog.Error(err, "Failed to register scheduler plugins")
+			return nil, err
+		}
+	}
+
+	// The weight adapter is registered last, so it adapts all the scorers of the profile.
+	if scorerWeightAdapter == "true" {
+		if err := schedulerProfile.AddPlugins(weightadapter.New(loadWeightAdapterConfig(), schedulerProfile.Scorers()...)); err != nil {
+			setupLog.Error(err, "Failed to register scheduler plugins")
+			return nil, err
+		}
+	}
+
+	if debugStreamHub != nil {
+		if err := schedulerProfile.AddPlugins(debugstream.NewDecisionPlugin(debugStreamHub, schedulerProfile.Scorers()...)); err != nil {
+			setupLog.Error(err, "Failed to register scheduler plugins")
+			return nil, err
+		}
+	}
+
+	if requestLookup != nil {
+		if err := schedulerProfile.AddPlugins(requestlookup.NewDecisionPlugin(requestLookup, schedulerProfile.Scorers()...)); err != nil {
+			setupLog.Error(err, "Failed to register scheduler plugins")
+			return nil, err
+		}
+	}
+
+	profiles := map[string]*framework.SchedulerProfile{"schedulerv2": schedulerProfile}
+	var profilePicker framework.ProfilePicker = profilepicker.NewAllProfilesPicker()
+	// The requests of the round-robin models are spread evenly over the pods, regardless of their scores.
+	if modelProfiles := loadRoundRobinModelProfiles("round-robin"); len(modelProfiles) > 0 {
+		profiles["round-robin"] = framework.NewSchedulerProfile().
+			WithFilters(filter.NewSheddableCapacityFilter()).
+			WithPicker(picker.NewRoundRobinPicker())
+		profilePicker = profilepicker.NewModelProfilePicker("schedulerv2", modelProfiles)
+	}
+
+	shardingConfig, err := loadModelFamilyShardingConfig()
+	if err != nil {
+		setupLog.Error(err, "Failed to load the model family sharding config")
+		return nil, err
+	}
+	return scheduling.NewSchedulerConfig(profilePicker, profiles).
+		WithDecisionReuse(loadDecisionReuseConfig()).
+		WithModelFamilySharding(shardingConfig).
+		WithEnvironment(loadSchedulerEnvironment()).
+		WithProfileTimeout(envutil.GetEnvDuration("SCHEDULER_PROFILE_TIMEOUT", 0, setupLog)), nil
+}
+
+// modelServers returns the metrics of the registered model servers and of the ones declared in the
+// model servers metrics file, by name.
+func modelServers() (map[string]backendmetrics.ModelServerMetrics, error) {
+	servers := map[string]backendmetrics.ModelServerMetrics{}
+	for _, name := range backendmetrics.RegisteredModelServers() {
+		servers[name], _ = backendmetrics.LookupModelServer(name)
+	}
+	if *modelServerMetricsFile == "" {
+		return servers, nil
+	}
+	data, err := os.ReadFile(*modelServerMetricsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model server metrics file: %w", err)
+	}
+	config, err := backendmetrics.LoadModelServersConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	for name, server := range config.ModelServers {
+		if _, ok := servers[name]; ok {
+			return nil, fmt.Errorf("model server %q of the model server metrics file is already registered", name)
+		}
+		servers[name] = server
+	}
+	return servers, nil
+}
+
+// newPodMetricsClient returns the client scraping the metrics of the model servers, as mapped by
+// the metric flags.
+func newPodMetricsClient() (backendmetrics.PodMetricsClient, error) {
+	servers, err := modelServers()
+	if err != nil {
+		return nil, err
+	}
+	server, ok := servers[*modelServerMetrics]
+	if !ok {
+		return nil, fmt.Errorf("unknown model server %q, expected one of %v", *modelServerMetrics, slices.Sorted(maps.Keys(servers)))
+	}
+	// The metric flags that are set override the metrics of the model server.
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "totalQueuedRequestsMetric":
+			server.TotalQueuedRequests = *totalQueuedRequestsMetric
+		case "kvCacheUsagePercentageMetric":
+			server.KVCacheUtilization = *kvCacheUsagePercentageMetric
+		case "loraInfoMetric":
+			server.LoraRequestInfo = *loraInfoMetric
+		case "kvCacheFreeBlocksMetric":
+			server.KVCacheFreeBlocks = *kvCacheFreeBlocksMetric
+		case "kvCacheFragmentationMetric":
+			server.KVCacheFragmentation = *kvCacheFragmentationMetric
+		case "maxConcurrencyMetric":
+			server.MaxConcurrency = *maxConcurrencyMetric
+		}
+	})
+	mapping, err := server.NewMetricMapping()
+	if err != nil {
+		return nil, err
+	}
+	verifyMetricMapping(*mapping, setupLog)
+
+	// The pods labeled with another model server are scraped with its metrics.
+	modelServerMappings := map[string]*backendmetrics.MetricMapping{*modelServerMetrics: mapping}
+	for name, server := range servers {
+		if name == *modelServerMetrics {
+			continue
+		}
+		if modelServerMappings[name], err = server.NewMetricMapping(); err != nil {
+			return nil, fmt.Errorf("model server %q: %w", name, err)
+		}
+	}
+
+	var pmc backendmetrics.PodMetricsClient = &backendmetrics.PodMetricsClientImpl{MetricMapping: mapping, ModelServerMappings: modelServerMappings}
+	gpuMapping, err := backendmetrics.NewGPUMetricMapping(*gpuUtilizationMetric, *gpuMemoryFreeMetric, *gpuMemoryUsedMetric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GPU metric mapping: %w", err)
+	}
+	if gpuMapping != nil {
+		pmc = &backendmetrics.GPUMetricsClient{
+			PodMetricsClient: pmc,
+			Mapping:          gpuMapping,
+			Port:             int32(*gpuMetricsPort),
+			OnNode:           *gpuMetricsOnNode,
+			RefreshInterval:  *gpuMetricsRefreshInterval,
+		}
+	}
+	return pmc, nil
+}
+
 func initLogging(opts *zap.Options) {
 	// Unless -zap-log-level is explicitly set, use -v
 	useV := true
@@ -276,12 +1018,24 @@ func initLogging(opts *zap.Options) {
 	ctrl.SetLogger(logger)
 }
 
+// loadRoundRobinModelProfiles maps the target models listed in the ROUND_ROBIN_MODELS environment
+// variable (comma separated) to the given profile.
+func loadRoundRobinModelProfiles(profile string) map[string]string {
+	modelProfiles := map[string]string{}
+	for _, model := range strings.Split(envutil.GetEnvString("ROUND_ROBIN_MODELS", "", setupLog), ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			modelProfiles[model] = profile
+		}
+	}
+	return modelProfiles
+}
+
 // registerHealthServer adds the Health gRPC server as a Runnable to the given manager.
-func registerHealthServer(mgr manager.Manager, logger logr.Logger, ds datastore.Datastore, port int) error {
+func registerHealthServer(mgr manager.Manager, logger logr.Logger, pools syncedPools, port int) error {
 	srv := grpc.NewServer()
 	healthPb.RegisterHealthServer(srv, &healthServer{
-		logger:    logger,
-		datastore: ds,
+		logger: logger,
+		pools:  pools,
 	})
 	if err := mgr.Add(
 		runnable.NoLeaderElection(runnable.GRPCServer("health", srv, port))); err != nil {
@@ -291,9 +1045,226 @@ func registerHealthServer(mgr manager.Manager, logger logr.Logger, ds datastore.
 	return nil
 }
 
+// registerEndpointSources adds the sources of the endpoints outside of the cluster, if any, as Runnables
+// to the given manager, keeping their endpoints in the given datastore.
+func registerEndpointSources(mgr manager.Manager, ds datastore.Datastore, cfg *rest.Config) error {
+	interval := envutil.GetEnvDuration("ENDPOINT_SOURCE_REFRESH_INTERVAL", endpointsource.DefaultRefreshInterval, setupLog)
+	sources := []datastore.EndpointSource{}
+	if *endpointsFile != "" {
+		sources = append(sources, endpointsource.NewFileSource(*endpointsFile, interval))
+	}
+	if *endpointSliceService != "" {
+		namespace, name, _ := strings.Cut(*endpointSliceService, "/")
+		if *endpointSliceKubeconfig != "" {
+			var err error
+			if cfg, err = clientcmd.BuildConfigFromFlags("", *endpointSliceKubeconfig); err != nil {
+				setupLog.Error(err, "Failed to load the kubeconfig of the EndpointSlices", "path", *endpointSliceKubeconfig)
+				return err
+			}
+		}
+		c, err := client.New(cfg, client.Options{})
+		if err != nil {
+			setupLog.Error(err, "Failed to create the client of the EndpointSlices")
+			return err
+		}
+		service := types.NamespacedName{Namespace: namespace, Name: name}
+		sources = append(sources, endpointsource.NewEndpointSliceSource(c, service, *endpointSlicePortName, interval))
+	}
+	for _, source := range sources {
+		if err := mgr.Add(runnable.NoLeaderElection(manager.RunnableFunc(func(ctx context.Context) error {
+			return datastore.RunEndpointSource(ctx, ds, source)
+		}))); err != nil {
+			setupLog.Error(err, "Failed to register endpoint source", "source", source.Name())
+			return err
+		}
+	}
+	return nil
+}
+
+// registerCustomMetricsServer adds the custom metrics API server as a Runnable to the given manager.
+func registerCustomMetricsServer(mgr manager.Manager, logger logr.Logger, ds datastore.Datastore, port int) error {
+	detector, err := saturationdetector.NewDetector(saturationdetector.LoadConfigFromEnv(), ds, logger)
+	if err != nil {
+		setupLog.Error(err, "Failed to create saturation detector")
+		return err
+	}
+	cert, err := tlsutil.LoadOrCreateTLSCertificate(*certPath, logger)
+	if err != nil {
+		setupLog.Error(err, "Failed to load custom metrics server certificate")
+		return err
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if *customMetricsClientCAFile != "" {
+		caPEM, err := os.ReadFile(*customMetricsClientCAFile)
+		if err != nil {
+			setupLog.Error(err, "Failed to read custom metrics client CA")
+			return err
+		}
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(caPEM) {
+			err := fmt.Errorf("no certificate found in %s", *customMetricsClientCAFile)
+			setupLog.Error(err, "Failed to parse custom metrics client CA")
+			return err
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	srv := &http.Server{
+		Handler:   custommetrics.NewProvider(ds, detector),
+		TLSConfig: tlsConfig,
+	}
+	if err := mgr.Add(
+		runnable.NoLeaderElection(runnable.HTTPServer("custom-metrics", srv, port))); err != nil {
+		setupLog.Error(err, "Failed to register custom metrics server")
+		return err
+	}
+	return nil
+}
+
+// registerMetricsPushServer adds the server receiving the metrics pushed by the model servers as a
+// Runnable to the given manager.
+func registerMetricsPushServer(mgr manager.Manager, datastores func() []datastore.Datastore, port int) error {
+	mux := http.NewServeMux()
+	mux.Handle(metricspush.Path, metricspush.NewReceiver(datastores))
+	srv := &http.Server{Handler: mux}
+	if err := mgr.Add(
+		runnable.NoLeaderElection(runnable.HTTPServer("metrics-push", srv, port))); err != nil {
+		setupLog.Error(err, "Failed to register metrics push server")
+		return err
+	}
+	return nil
+}
+
+// pushedMetricsDatastores returns the datastores holding the pods that may push their metrics: the
+// ones of the selected pools if any, otherwise the ones of the primary and standby pools.
+func pushedMetricsDatastores(primary, standby datastore.Datastore, pools *multipool.Set) func() []datastore.Datastore {
+	if pools != nil {
+		return pools.Datastores
+	}
+	datastores := []datastore.Datastore{primary}
+	if standby != nil {
+		datastores = append(datastores, standby)
+	}
+	return func() []datastore.Datastore { return datastores }
+}
+
+// schedulerConfigLoader returns a loader building the scheduler config declared in a config file.
+// The plugins that are configurable through environment variables default to that configuration,
+// which the parameters of the config file override.
+func schedulerConfigLoader(ds datastore.Datastore) scheduling.ConfigLoader {
+	return func(data []byte) (*scheduling.SchedulerConfig, error) {
+		pluginsConfig, err := scheduling.LoadPluginsConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		parameters, err := loadPluginParameters()
+		if err != nil {
+			return nil, err
+		}
+		schedulerConfig, err := scheduling.NewSchedulerConfigFromPlugins(pluginsConfig, &pluginHandle{ds: ds, parameters: parameters})
+		if err != nil {
+			return nil, err
+		}
+		shardingConfig, err := loadModelFamilyShardingConfig()
+		if err != nil {
+			return nil, err
+		}
+		return schedulerConfig.
+			WithDecisionReuse(loadDecisionReuseConfig()).
+			WithModelFamilySharding(shardingConfig).
+			WithEnvironment(loadSchedulerEnvironment()).
+			WithProfileTimeout(envutil.GetEnvDuration("SCHEDULER_PROFILE_TIMEOUT", 0, setupLog)), nil
+	}
+}
+
+// declaresPlugin returns whether a profile of the given scheduler config file declares the plugin
+// of the given name.
+func declaresPlugin(data []byte, name string) bool {
+	pluginsConfig, err := scheduling.LoadPluginsConfig(data)
+	if err != nil {
+		return false
+	}
+	for _, profile := range pluginsConfig.Profiles {
+		for _, plugin := range profile.Plugins {
+			if plugin.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setupStandbyFailover creates the datastore of the standby pool and the failover routing between the
+// primary and standby pools. It returns nils if no standby pool is configured.
+func setupStandbyFailover(ctx context.Context, pmf *backendmetrics.PodMetricsFactory, primary datastore.Datastore,
+	primaryName, standbyName types.NamespacedName) (datastore.Datastore, *failover.Failover) {
+	if standbyName.Name == "" {
+		return nil, nil
+	}
+	standby := datastore.NewDatastore(ctx, pmf)
+	return standby, failover.New(primary, standby, primaryName, standbyName, failover.LoadConfigFromEnv())
+}
+
+// newPoolSet returns the set of the InferencePools selected by the pool selector, or nil if the
+// Endpoint Picker serves a single pool.
+func newPoolSet(ctx context.Context, pmf *backendmetrics.PodMetricsFactory) (*multipool.Set, error) {
+	if *poolSelector == "" {
+		return nil, nil
+	}
+	selector, err := labels.Parse(*poolSelector)
+	if err != nil {
+		return nil, err
+	}
+	return multipool.NewSet(ctx, *poolNamespace, selector, func(ctx context.Context) datastore.Datastore {
+		return datastore.NewDatastore(ctx, pmf)
+	}), nil
+}
+
+// poolSchedulerFactory returns the factory of the schedulers of the pools served with a pool
+// selector, which are configured as the scheduler of a single pool, except that the scheduler
+// config file is not reloaded and the decisions are not pushed to the debug stream.
+func poolSchedulerFactory(requestLookup *requestlookup.Store) func(datastore.Datastore) (requestcontrol.Scheduler, error) {
+	return func(ds datastore.Datastore) (requestcontrol.Scheduler, error) {
+		schedulerConfig, err := newPoolSchedulerConfig(ds, requestLookup)
+		if err != nil {
+			return nil, err
+		}
+		return scheduling.NewSchedulerWithConfig(ds, schedulerConfig), nil
+	}
+}
+
+// newPoolSchedulerConfig builds the scheduler config of the pool of the given datastore from the
+// scheduler config file, or else from the environment variables.
+func newPoolSchedulerConfig(ds datastore.Datastore, requestLookup *requestlookup.Store) (*scheduling.SchedulerConfig, error) {
+	if *schedulerConfigFile != "" {
+		data, err := os.ReadFile(*schedulerConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scheduler config file: %w", err)
+		}
+		return schedulerConfigLoader(ds)(data)
+	}
+	if schedulerV2 == "true" {
+		return newSchedulerV2Config(nil, requestLookup)
+	}
+	return scheduling.NewDefaultSchedulerConfig(), nil
+}
+
 func validateFlags() error {
-	if *poolName == "" {
-		return fmt.Errorf("required %q flag not set", "poolName")
+	switch {
+	case *poolName == "" && *poolSelector == "":
+		return fmt.Errorf("required %q or %q flag not set", "poolName", "poolSelector")
+	case *poolName != "" && *poolSelector != "":
+		return fmt.Errorf("%q and %q flags are mutually exclusive", "poolName", "poolSelector")
+	case *poolSelector != "" && *standbyPoolName != "":
+		return fmt.Errorf("%q flag requires the %q flag", "standbyPoolName", "poolName")
+	case *poolSelector != "" && (*endpointsFile != "" || *endpointSliceService != ""):
+		return fmt.Errorf("%q and %q flags require the %q flag", "endpointsFile", "endpointSliceService", "poolName")
+	case (*refreshMetricsMinInterval > 0) != (*refreshMetricsMaxInterval > 0):
+		return fmt.Errorf("%q and %q flags must be set together", "refreshMetricsMinInterval", "refreshMetricsMaxInterval")
+	case *refreshMetricsMinInterval > *refreshMetricsMaxInterval:
+		return fmt.Errorf("%q flag must not exceed the %q flag", "refreshMetricsMinInterval", "refreshMetricsMaxInterval")
+	case *endpointSliceService != "" && len(strings.Split(*endpointSliceService, "/")) != 2:
+		return fmt.Errorf("invalid %q flag %q, expected namespace/name", "endpointSliceService", *endpointSliceService)
 	}
 
 	return nil
@@ -0,0 +1,274 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/endpointsource"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
+)
+
+// effectiveConfig is the configuration of the Endpoint Picker printed by the validateOnly mode.
+type effectiveConfig struct {
+	Flags     map[string]string             `json:"flags"`
+	Scheduler *scheduling.ConfigDescription `json:"scheduler,omitempty"`
+	Warnings  []string                      `json:"warnings,omitempty"`
+	Errors    []string                      `json:"errors,omitempty"`
+}
+
+// validateConfig builds the configuration of the Endpoint Picker as run does, without connecting
+// to the cluster, checks its consistency and the one of the given InferencePool and InferenceModel
+// manifest files, and prints the effective configuration to out. It returns an error if any
+// problem was found.
+func validateConfig(out io.Writer, manifests []string) error {
+	config := effectiveConfig{Flags: map[string]string{}}
+	flag.VisitAll(func(f *flag.Flag) {
+		config.Flags[f.Name] = f.Value.String()
+	})
+
+	var errs []error
+	schedulerConfig, err := newValidatedSchedulerConfig()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("scheduler: %w", err))
+	} else {
+		description := schedulerConfig.Describe()
+		config.Scheduler = &description
+		warnings, err := schedulerConfig.Validate()
+		for _, warning := range warnings {
+			config.Warnings = append(config.Warnings, "scheduler: "+warning)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scheduler: %w", err))
+		}
+	}
+	if *endpointsFile != "" {
+		if data, err := os.ReadFile(*endpointsFile); err != nil {
+			errs = append(errs, fmt.Errorf("failed to read endpoints file: %w", err))
+		} else if _, err := endpointsource.ParseEndpointsFile(data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(manifests) > 0 {
+		warnings, err := validateManifests(manifests)
+		config.Warnings = append(config.Warnings, warnings...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, err := range errs {
+		// The joined errors are listed one per line.
+		config.Errors = append(config.Errors, strings.Split(err.Error(), "\n")...)
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to print the effective configuration: %w", err)
+	}
+	if _, err := out.Write(data); err != nil {
+		return err
+	}
+	if err := errors.Join(errs...); err != nil {
+		setupLog.Error(err, "Invalid configuration")
+		return err
+	}
+	setupLog.Info("Valid configuration", "warnings", len(config.Warnings))
+	return nil
+}
+
+// newValidatedSchedulerConfig builds the scheduler config as run does, with an empty datastore. The
+// plugins pushing the decisions to the debug stream and to the request lookup store are left out.
+func newValidatedSchedulerConfig() (*scheduling.SchedulerConfig, error) {
+	pmc, err := newPodMetricsClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric mapping from flags: %w", err)
+	}
+	ds := datastore.NewDatastore(context.Background(), backendmetrics.NewPodMetricsFactory(pmc, *refreshMetricsInterval))
+	return newPoolSchedulerConfig(ds, nil)
+}
+
+// validateManifests checks the InferencePool and InferenceModel manifests in the given YAML or JSON
+// files against the pool flags: the served pools must be declared, and the InferenceModels
+// referencing them must be consistent. The other objects are ignored.
+func validateManifests(paths []string) (warnings []string, err error) {
+	var errs []error
+	selector, err := labels.Parse(*poolSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool selector: %w", err)
+	}
+	var pools []*v1alpha2.InferencePool
+	var models []*v1alpha2.InferenceModel
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read manifest file: %w", err))
+			continue
+		}
+		reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+		for {
+			document, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				break
+			}
+			pool, model, err := decodeManifest(document)
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			case pool != nil:
+				pools = append(pools, pool)
+			case model != nil:
+				models = append(models, model)
+			}
+		}
+	}
+
+	// The pool and the models are looked up in the namespace of the pool, as the manifests may leave
+	// the namespace to kubectl.
+	inNamespace := func(object metav1.Object) bool {
+		return object.GetNamespace() == "" || object.GetNamespace() == *poolNamespace
+	}
+	served := map[string]bool{}
+	standbyFound := false
+	for _, pool := range pools {
+		if !inNamespace(pool) {
+			continue
+		}
+		switch {
+		case *poolSelector == "" && pool.Name == *poolName, *poolSelector != "" && selector.Matches(labels.Set(pool.Labels)):
+			served[pool.Name] = true
+		case *standbyPoolName != "" && pool.Name == *standbyPoolName:
+			standbyFound = true
+		default:
+			continue
+		}
+		if len(pool.Spec.Selector) == 0 {
+			errs = append(errs, fmt.Errorf("InferencePool '%s': the selector is empty", pool.Name))
+		}
+		if pool.Spec.TargetPortNumber < 1 || pool.Spec.TargetPortNumber > 65535 {
+			errs = append(errs, fmt.Errorf("InferencePool '%s': invalid target port number %d", pool.Name, pool.Spec.TargetPortNumber))
+		}
+	}
+	switch {
+	case *poolSelector != "" && len(served) == 0:
+		errs = append(errs, fmt.Errorf("no InferencePool of namespace '%s' matching '%s' is declared in the manifests", *poolNamespace, *poolSelector))
+	case *poolSelector == "" && len(served) == 0:
+		errs = append(errs, fmt.Errorf("InferencePool '%s/%s' is not declared in the manifests", *poolNamespace, *poolName))
+	}
+	if *standbyPoolName != "" && !standbyFound {
+		warnings = append(warnings, fmt.Sprintf("standby InferencePool '%s/%s' is not declared in the manifests", *poolNamespace, *standbyPoolName))
+	}
+
+	modelNames := map[string]string{} // pool/model name -> InferenceModel name
+	for _, model := range models {
+		poolRef := string(model.Spec.PoolRef.Name)
+		if !inNamespace(model) || !served[poolRef] {
+			continue
+		}
+		key := poolRef + "/" + model.Spec.ModelName
+		if other, ok := modelNames[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("InferenceModels '%s' and '%s' both declare the model '%s', only the oldest one is served",
+				other, model.Name, model.Spec.ModelName))
+		} else {
+			modelNames[key] = model.Name
+		}
+		if err := validateModelSpec(model); err != nil {
+			errs = append(errs, fmt.Errorf("InferenceModel '%s': %w", model.Name, err))
+		}
+	}
+	return warnings, errors.Join(errs...)
+}
+
+// decodeManifest decodes the given YAML or JSON document if it's an InferencePool or an
+// InferenceModel, and returns nils otherwise.
+func decodeManifest(document []byte) (*v1alpha2.InferencePool, *v1alpha2.InferenceModel, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal(document, &typeMeta); err != nil {
+		return nil, nil, err
+	}
+	if typeMeta.GroupVersionKind().Group != v1alpha2.GroupName {
+		return nil, nil, nil
+	}
+	switch typeMeta.Kind {
+	case "InferencePool":
+		pool := &v1alpha2.InferencePool{}
+		if err := yaml.UnmarshalStrict(document, pool); err != nil {
+			return nil, nil, fmt.Errorf("invalid InferencePool: %w", err)
+		}
+		return pool, nil, nil
+	case "InferenceModel":
+		model := &v1alpha2.InferenceModel{}
+		if err := yaml.UnmarshalStrict(document, model); err != nil {
+			return nil, nil, fmt.Errorf("invalid InferenceModel: %w", err)
+		}
+		return nil, model, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// validateModelSpec checks the spec of the given InferenceModel, as the admission of the CRD would.
+func validateModelSpec(model *v1alpha2.InferenceModel) error {
+	var errs []error
+	if model.Spec.ModelName == "" {
+		errs = append(errs, errors.New("the model name is required"))
+	}
+	if criticality := model.Spec.Criticality; criticality != nil {
+		switch *criticality {
+		case v1alpha2.Critical, v1alpha2.Standard, v1alpha2.Sheddable:
+		default:
+			errs = append(errs, fmt.Errorf("unknown criticality '%s'", *criticality))
+		}
+	}
+	weighted := 0
+	targets := map[string]bool{}
+	for _, target := range model.Spec.TargetModels {
+		if targets[target.Name] {
+			errs = append(errs, fmt.Errorf("duplicate target model '%s'", target.Name))
+		}
+		targets[target.Name] = true
+		if target.Weight != nil {
+			weighted++
+			if *target.Weight < 1 || *target.Weight > 1000000 {
+				errs = append(errs, fmt.Errorf("target model '%s' has an invalid weight %d", target.Name, *target.Weight))
+			}
+		}
+	}
+	if weighted != 0 && weighted != len(model.Spec.TargetModels) {
+		errs = append(errs, errors.New("the weights must be set on all the target models or on none"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Command loadgen sends synthetic OpenAI-compatible traffic to a gateway, as described by a
+// config file (see loadgen.Config), and prints the summary of the run. The result of every
+// request can be written as newline-delimited JSON, tagged with the x-request-id it was sent with,
+// to be correlated with the logs and the request lookup endpoint of EPP.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/loadgen"
+)
+
+var (
+	configFile = flag.String(
+		"config",
+		"",
+		"The path of the load generator config file (required)")
+	target = flag.String(
+		"target",
+		"",
+		"The base URL of the gateway, overriding the target of the config file")
+	runID = flag.String(
+		"runID",
+		"",
+		"The ID of the run, prefixing the x-request-id of the requests. Defaults to loadgen-<unix time>")
+	resultsFile = flag.String(
+		"results",
+		"",
+		"The path of the file the results of the requests are written to as newline-delimited JSON, - for stdout")
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	flag.Parse()
+	if *configFile == "" {
+		return fmt.Errorf("--config is required")
+	}
+	data, err := os.ReadFile(*configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the config file: %w", err)
+	}
+	config, err := loadgen.LoadConfig(data)
+	if err != nil {
+		return err
+	}
+	if *target != "" {
+		config.Target = *target
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid load generator config: %w", err)
+	}
+	if *runID == "" {
+		*runID = "loadgen-" + strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	var results io.Writer
+	switch *resultsFile {
+	case "":
+	case "-":
+		results = os.Stdout
+	default:
+		f, err := os.Create(*resultsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create the results file: %w", err)
+		}
+		defer f.Close()
+		results = f
+	}
+
+	// An interrupt stops sending requests, and the summary covers the requests sent so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "Sending the requests of run %s to %s\n", *runID, config.Target)
+	summary, err := loadgen.NewRunner(config, *runID, results).Run(ctx)
+	if summary != nil {
+		if writeErr := summary.Write(os.Stderr); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
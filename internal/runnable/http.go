@@ -0,0 +1,60 @@
+package runnable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// HTTPServer converts the given HTTP server into a runnable. The server serves TLS if its
+// TLSConfig is set.
+// The server name is just being used for logging.
+func HTTPServer(name string, srv *http.Server, port int) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		// Use "name" key as that is what manager.Server does as well.
+		log := ctrl.Log.WithValues("name", name)
+		log.Info("HTTP server starting")
+
+		// Start listening.
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			log.Error(err, "HTTP server failed to listen")
+			return err
+		}
+
+		log.Info("HTTP server listening", "port", port)
+
+		// Shutdown on context closed.
+		// Make sure the goroutine does not leak.
+		doneCh := make(chan struct{})
+		defer close(doneCh)
+		go func() {
+			select {
+			case <-ctx.Done():
+				log.Info("HTTP server shutting down")
+				if err := srv.Shutdown(context.Background()); err != nil {
+					log.Error(err, "HTTP server failed to shut down")
+				}
+			case <-doneCh:
+			}
+		}()
+
+		// Keep serving until terminated.
+		if srv.TLSConfig != nil {
+			err = srv.ServeTLS(lis, "", "")
+		} else {
+			err = srv.Serve(lis)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(err, "HTTP server failed")
+			return err
+		}
+		log.Info("HTTP server terminated")
+		return nil
+	})
+}
@@ -71,3 +71,12 @@ func CreateSelfSignedTLSCertificate(logger logr.Logger) (tls.Certificate, error)
 
 	return tls.X509KeyPair(certBytes, keyBytes)
 }
+
+// LoadOrCreateTLSCertificate loads the cert and key named tls.crt and tls.key in the given
+// directory, or creates a self-signed cert if the directory is empty.
+func LoadOrCreateTLSCertificate(certPath string, logger logr.Logger) (tls.Certificate, error) {
+	if certPath != "" {
+		return tls.LoadX509KeyPair(certPath+"/tls.crt", certPath+"/tls.key")
+	}
+	return CreateSelfSignedTLSCertificate(logger)
+}
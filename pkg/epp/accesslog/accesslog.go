@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package accesslog ingests the access-log records of the gateway, so EPP learns the outcome of
+// the requests whose responses didn't flow back through ext-proc, e.g. when the gateway fails
+// open or the response processing is skipped.
+//
+// The records are posted as newline-delimited JSON, e.g. with an Envoy HTTP access logger using a
+// JSON format such as:
+//
+//	request_id: "%REQ(X-REQUEST-ID)%"
+//	model: "%REQ(X-GATEWAY-MODEL-NAME)%"
+//	upstream_host: "%UPSTREAM_HOST%"
+//	response_code: "%RESPONSE_CODE%"
+//	bytes_received: "%BYTES_RECEIVED%"
+//	bytes_sent: "%BYTES_SENT%"
+//	duration_ms: "%DURATION%"
+//	response_duration_ms: "%RESPONSE_DURATION%"
+package accesslog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// IngestPath is the path of the access-log ingestion endpoint.
+const IngestPath = "/admin/access-log"
+
+// maxRecordSize is the maximum size of a single record.
+const maxRecordSize = 64 * 1024
+
+// Record is an access-log record of the gateway.
+type Record struct {
+	// RequestID is the x-request-id of the request, used to skip the requests EPP observed
+	// through ext-proc already.
+	RequestID string `json:"request_id"`
+	// Model is the model of the request, as requested by the client.
+	Model string `json:"model"`
+	// TargetModel is the model the request was sent to, if known.
+	TargetModel string `json:"target_model,omitempty"`
+	// UpstreamHost is the address of the model server the request was sent to, if any.
+	UpstreamHost string `json:"upstream_host,omitempty"`
+	// ResponseCode is the HTTP status code returned to the client, 0 if there was no response.
+	ResponseCode  Number `json:"response_code"`
+	BytesReceived Number `json:"bytes_received"`
+	BytesSent     Number `json:"bytes_sent"`
+	// DurationMillis is the total duration of the request.
+	DurationMillis Number `json:"duration_ms"`
+	// ResponseDurationMillis is the time until the first byte of the response was received from
+	// the model server, i.e. the time to first token of streamed responses.
+	ResponseDurationMillis Number `json:"response_duration_ms"`
+}
+
+// Number is an integer field of a Record. Access loggers may render it as a JSON number or string,
+// and render missing values as "-" (e.g. the response duration when there was no response), which
+// is parsed as 0.
+type Number int64
+
+// UnmarshalJSON parses the number from a JSON number or string.
+func (n *Number) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "" || raw == "-" || raw == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*n = Number(v)
+	return nil
+}
+
+// Duration returns the total duration of the request.
+func (r *Record) Duration() time.Duration {
+	return time.Duration(r.DurationMillis) * time.Millisecond
+}
+
+// ResponseDuration returns the time until the first byte of the response.
+func (r *Record) ResponseDuration() time.Duration {
+	return time.Duration(r.ResponseDurationMillis) * time.Millisecond
+}
+
+// Succeeded returns true if the request got a successful response.
+func (r *Record) Succeeded() bool {
+	return r.ResponseCode >= 200 && r.ResponseCode < 300
+}
+
+// Recorder records the outcome of the requests of the access-log records.
+type Recorder interface {
+	// RecordAccessLog records the outcome of the request of the given record, unless it was
+	// observed already. Returns false if the record was skipped.
+	RecordAccessLog(ctx context.Context, record *Record) bool
+}
+
+// IngestResult is the response of the ingestion endpoint.
+type IngestResult struct {
+	Recorded int `json:"recorded"`
+	Skipped  int `json:"skipped"`
+}
+
+// NewIngester initializes a new Ingester and returns its pointer.
+func NewIngester() *Ingester {
+	return &Ingester{}
+}
+
+// Ingester serves the access-log ingestion endpoint, passing the records to its Recorder.
+type Ingester struct {
+	recorder atomic.Pointer[Recorder]
+}
+
+// SetRecorder sets the Recorder of the ingested records. Records are rejected until it's set.
+func (i *Ingester) SetRecorder(recorder Recorder) {
+	i.recorder.Store(&recorder)
+}
+
+// ServeHTTP serves the ingestion endpoint, which accepts POST requests with newline-delimited
+// JSON records, and returns the IngestResult.
+func (i *Ingester) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recorder := i.recorder.Load()
+	if recorder == nil {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	result := IngestResult{}
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 4096), maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			http.Error(w, fmt.Sprintf("invalid record at line %d: %v", line, err), http.StatusBadRequest)
+			return
+		}
+		if (*recorder).RecordAccessLog(r.Context(), record) {
+			result.Recorded++
+		} else {
+			result.Skipped++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to read records: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.FromContext(r.Context()).V(logutil.VERBOSE).Info("Ingested access-log records", "recorded", result.Recorded, "skipped", result.Skipped)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
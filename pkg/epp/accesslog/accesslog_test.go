@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeRecorder struct {
+	observed map[string]bool
+	records  []Record
+}
+
+func (f *fakeRecorder) RecordAccessLog(_ context.Context, record *Record) bool {
+	if f.observed[record.RequestID] {
+		return false
+	}
+	f.records = append(f.records, *record)
+	return true
+}
+
+func TestIngester(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		noRecorder  bool
+		wantCode    int
+		wantResult  IngestResult
+		wantRecords []Record
+	}{
+		{
+			name:   "records and skips",
+			method: http.MethodPost,
+			body: `{"request_id":"a","model":"m","response_code":"503","bytes_sent":"0","duration_ms":"12","response_duration_ms":"-"}
+
+{"request_id":"b","model":"m","target_model":"m-v2","response_code":200,"bytes_sent":42,"duration_ms":120,"response_duration_ms":30}
+{"request_id":"observed","model":"m","response_code":"200"}
+`,
+			wantCode:   http.StatusOK,
+			wantResult: IngestResult{Recorded: 2, Skipped: 1},
+			wantRecords: []Record{
+				{RequestID: "a", Model: "m", ResponseCode: 503, DurationMillis: 12},
+				{RequestID: "b", Model: "m", TargetModel: "m-v2", ResponseCode: 200, BytesSent: 42, DurationMillis: 120, ResponseDurationMillis: 30},
+			},
+		},
+		{
+			name:     "invalid record",
+			method:   http.MethodPost,
+			body:     `{"request_id":"a","model":"m","response_code":"abc"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "wrong method",
+			method:   http.MethodGet,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "no recorder",
+			method:     http.MethodPost,
+			body:       `{"request_id":"a","model":"m","response_code":"200"}`,
+			noRecorder: true,
+			wantCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ingester := NewIngester()
+			recorder := &fakeRecorder{observed: map[string]bool{"observed": true}}
+			if !test.noRecorder {
+				ingester.SetRecorder(recorder)
+			}
+
+			rec := httptest.NewRecorder()
+			ingester.ServeHTTP(rec, httptest.NewRequest(test.method, IngestPath, strings.NewReader(test.body)))
+
+			if rec.Code != test.wantCode {
+				t.Fatalf("Unexpected status code, want %d, got %d: %s", test.wantCode, rec.Code, rec.Body.String())
+			}
+			if test.wantCode != http.StatusOK {
+				return
+			}
+			result := IngestResult{}
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode the result: %v", err)
+			}
+			if diff := cmp.Diff(test.wantResult, result); diff != "" {
+				t.Errorf("Unexpected result (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantRecords, recorder.records); diff != "" {
+				t.Errorf("Unexpected records (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordSucceeded(t *testing.T) {
+	for code, want := range map[Number]bool{0: false, 200: true, 204: true, 429: false, 503: false} {
+		record := &Record{ResponseCode: code}
+		if got := record.Succeeded(); got != want {
+			t.Errorf("Succeeded() for response code %d: want %v, got %v", code, want, got)
+		}
+	}
+}
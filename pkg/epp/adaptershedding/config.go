@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptershedding
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultMaxAdaptersFraction and DefaultMaxSingleAdapterFraction are zero, i.e. adapter
+	// shedding is disabled by default.
+	DefaultMaxAdaptersFraction      = 0.0
+	DefaultMaxSingleAdapterFraction = 0.0
+	// DefaultSaturationThreshold is the fraction of pods without good capacity from which the pool
+	// is considered saturated.
+	DefaultSaturationThreshold = 0.5
+)
+
+// Environment variable names for adapter shedding configuration
+const (
+	EnvMaxAdaptersFraction      = "ADAPTER_SHEDDING_MAX_ADAPTERS_FRACTION"
+	EnvMaxSingleAdapterFraction = "ADAPTER_SHEDDING_MAX_SINGLE_ADAPTER_FRACTION"
+	EnvSaturationThreshold      = "ADAPTER_SHEDDING_SATURATION_THRESHOLD"
+)
+
+// Config holds the configuration of the adapter Shedder.
+type Config struct {
+	// MaxAdaptersFraction is the maximum fraction (0.0 to 1.0) of the requests in flight that the
+	// requests for LoRA adapters may take while the pool is saturated. Zero means no limit.
+	MaxAdaptersFraction float64
+	// MaxSingleAdapterFraction is the maximum fraction (0.0 to 1.0) of the requests in flight that
+	// the requests for any single LoRA adapter may take while the pool is saturated. Zero means no
+	// limit.
+	MaxSingleAdapterFraction float64
+	// SaturationThreshold is the fraction (0.0 to 1.0) of pods without good capacity, as defined by
+	// the saturation detector, from which the limits are enforced.
+	SaturationThreshold float64
+	// SaturationDetector configures what good capacity means.
+	SaturationDetector *saturationdetector.Config
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		MaxAdaptersFraction:      DefaultMaxAdaptersFraction,
+		MaxSingleAdapterFraction: DefaultMaxSingleAdapterFraction,
+		SaturationThreshold:      DefaultSaturationThreshold,
+		SaturationDetector: &saturationdetector.Config{
+			QueueDepthThreshold:       saturationdetector.DefaultQueueDepthThreshold,
+			KVCacheUtilThreshold:      saturationdetector.DefaultKVCacheUtilThreshold,
+			MetricsStalenessThreshold: saturationdetector.DefaultMetricsStalenessThreshold,
+		},
+	}
+}
+
+// Enabled returns true if any of the limits is set.
+func (c *Config) Enabled() bool {
+	return c.MaxAdaptersFraction > 0 || c.MaxSingleAdapterFraction > 0
+}
+
+// LoadConfigFromEnv loads the adapter shedding Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("adapter-shedding-config")
+
+	cfg := &Config{}
+
+	cfg.MaxAdaptersFraction = envutil.GetEnvFloat(EnvMaxAdaptersFraction, DefaultMaxAdaptersFraction, logger)
+	if cfg.MaxAdaptersFraction < 0 || cfg.MaxAdaptersFraction > 1 {
+		cfg.MaxAdaptersFraction = DefaultMaxAdaptersFraction
+	}
+
+	cfg.MaxSingleAdapterFraction = envutil.GetEnvFloat(EnvMaxSingleAdapterFraction, DefaultMaxSingleAdapterFraction, logger)
+	if cfg.MaxSingleAdapterFraction < 0 || cfg.MaxSingleAdapterFraction > 1 {
+		cfg.MaxSingleAdapterFraction = DefaultMaxSingleAdapterFraction
+	}
+
+	cfg.SaturationThreshold = envutil.GetEnvFloat(EnvSaturationThreshold, DefaultSaturationThreshold, logger)
+	if cfg.SaturationThreshold < 0 || cfg.SaturationThreshold > 1 {
+		cfg.SaturationThreshold = DefaultSaturationThreshold
+	}
+
+	cfg.SaturationDetector = saturationdetector.LoadConfigFromEnv()
+
+	logger.Info("Adapter shedding configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
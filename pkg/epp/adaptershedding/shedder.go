@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adaptershedding protects the traffic of the base model of a pool from LoRA adapter
+// storms: while the pool is saturated, the requests for adapters are shed first once the adapters
+// take more than their share of the requests in flight, so that the capacity left goes to the
+// base model traffic.
+//
+// The requests for an adapter are the requests whose target model is reported as a LoRA adapter
+// (running or waiting) by any pod of the pool.
+package adaptershedding
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// Datastore provides access to the pod metrics.
+type Datastore interface {
+	PodGetAll() []backendmetrics.PodMetrics
+}
+
+// Shedder tracks the requests in flight per target model, and sheds the requests for adapters
+// that exceed the configured shares while the pool is saturated.
+type Shedder struct {
+	config    *Config
+	datastore Datastore
+	detector  *saturationdetector.Detector
+
+	mu               sync.Mutex
+	models           map[string]*modelInFlight // key: target model
+	total            int
+	adaptersInFlight int
+}
+
+type modelInFlight struct {
+	count   int
+	adapter bool // whether the model was an adapter when its first request in flight was admitted
+}
+
+// NewShedder creates a new adapter Shedder.
+func NewShedder(config *Config, datastore Datastore, logger logr.Logger) (*Shedder, error) {
+	detector, err := saturationdetector.NewDetector(config.SaturationDetector, datastore, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Shedder{
+		config:    config,
+		datastore: datastore,
+		detector:  detector,
+		models:    map[string]*modelInFlight{},
+	}, nil
+}
+
+// Admit returns false if the request for the given target model must be shed. Otherwise, the
+// request is counted in flight until Done is called. Critical requests are never shed, but they
+// are counted in flight.
+func (s *Shedder) Admit(ctx context.Context, targetModel string, critical bool) bool {
+	adapter := s.isAdapter(targetModel)
+
+	s.mu.Lock()
+	model, ok := s.models[targetModel]
+	if !ok {
+		model = &modelInFlight{}
+		s.models[targetModel] = model
+	}
+	if model.count == 0 {
+		model.adapter = adapter
+	}
+	overShare := model.adapter && !critical && s.overShare(model)
+	s.mu.Unlock()
+
+	// The saturation is only checked for the requests over their share, as it's costlier.
+	if overShare && s.detector.Saturation(ctx) >= s.config.SaturationThreshold {
+		log.FromContext(ctx).V(logutil.DEBUG).Info("Shedding adapter request", "targetModel", targetModel)
+		metrics.RecordAdapterShedRequest(targetModel)
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	model.count++
+	s.total++
+	if model.adapter {
+		s.adaptersInFlight++
+	}
+	return true
+}
+
+// Done marks the end of a request admitted for the given target model.
+func (s *Shedder) Done(targetModel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	model, ok := s.models[targetModel]
+	if !ok || model.count == 0 {
+		return
+	}
+	model.count--
+	s.total--
+	if model.adapter {
+		s.adaptersInFlight--
+	}
+	if model.count == 0 {
+		delete(s.models, targetModel)
+	}
+}
+
+// overShare returns true if admitting a request for the given adapter exceeds the share of the
+// adapters or of the single adapter. One request in flight is always within a share, so the
+// adapters are not starved while there are few requests in flight. Must be called with the lock
+// held.
+func (s *Shedder) overShare(model *modelInFlight) bool {
+	total := float64(s.total + 1)
+	if s.config.MaxAdaptersFraction > 0 && float64(s.adaptersInFlight+1) > max(1, s.config.MaxAdaptersFraction*total) {
+		return true
+	}
+	return s.config.MaxSingleAdapterFraction > 0 && float64(model.count+1) > max(1, s.config.MaxSingleAdapterFraction*total)
+}
+
+// isAdapter returns true if any pod reports the given model as a LoRA adapter.
+func (s *Shedder) isAdapter(targetModel string) bool {
+	for _, pod := range s.datastore.PodGetAll() {
+		podMetrics := pod.GetMetrics()
+		if podMetrics == nil {
+			continue
+		}
+		if _, ok := podMetrics.ActiveModels[targetModel]; ok {
+			return true
+		}
+		if _, ok := podMetrics.WaitingModels[targetModel]; ok {
+			return true
+		}
+	}
+	return false
+}
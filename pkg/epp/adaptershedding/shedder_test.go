@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptershedding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+)
+
+type fakeDatastore struct {
+	pods []*backendmetrics.FakePodMetrics
+}
+
+func (f *fakeDatastore) PodGetAll() []backendmetrics.PodMetrics {
+	pm := make([]backendmetrics.PodMetrics, 0, len(f.pods))
+	for _, pod := range f.pods {
+		pm = append(pm, pod)
+	}
+	return pm
+}
+
+func TestShedder(t *testing.T) {
+	newDatastore := func(queueSize int) *fakeDatastore {
+		return &fakeDatastore{pods: []*backendmetrics.FakePodMetrics{{
+			Pod: &backend.Pod{NamespacedName: types.NamespacedName{Name: "pod1"}},
+			Metrics: &backendmetrics.MetricsState{
+				WaitingQueueSize: queueSize,
+				ActiveModels:     map[string]int{"adapter-a": 0, "adapter-b": 0},
+				UpdateTime:       time.Now().Add(time.Hour), // never stale during the test
+			},
+		}}}
+	}
+	type request struct {
+		targetModel string
+		critical    bool
+		want        bool
+	}
+
+	tests := []struct {
+		name      string
+		config    *Config
+		queueSize int
+		requests  []request
+	}{
+		{
+			name:      "adapters share enforced while saturated",
+			config:    &Config{MaxAdaptersFraction: 0.5, SaturationThreshold: 0.5},
+			queueSize: 100,
+			requests: []request{
+				{targetModel: "base", want: true},
+				{targetModel: "adapter-a", want: true},
+				{targetModel: "adapter-b", want: false}, // 2 of 3 in flight
+				{targetModel: "adapter-b", critical: true, want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "base", want: true},
+			},
+		},
+		{
+			name:      "single adapter share enforced while saturated",
+			config:    &Config{MaxSingleAdapterFraction: 0.25, SaturationThreshold: 0.5},
+			queueSize: 100,
+			requests: []request{
+				{targetModel: "adapter-a", want: true}, // a single request is always within the share
+				{targetModel: "adapter-a", want: false},
+				{targetModel: "adapter-b", want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "base", want: true},
+				{targetModel: "adapter-a", want: true}, // 2 of 8 in flight
+			},
+		},
+		{
+			name:      "no shedding without saturation",
+			config:    &Config{MaxAdaptersFraction: 0.1, SaturationThreshold: 0.5},
+			queueSize: 0,
+			requests: []request{
+				{targetModel: "adapter-a", want: true},
+				{targetModel: "adapter-a", want: true},
+				{targetModel: "adapter-b", want: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.SaturationDetector = NewDefaultConfig().SaturationDetector
+			shedder, err := NewShedder(test.config, newDatastore(test.queueSize), logr.Discard())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for i, req := range test.requests {
+				if got := shedder.Admit(context.Background(), req.targetModel, req.critical); got != req.want {
+					t.Errorf("Request #%d for %s: Admit() = %v, want %v", i, req.targetModel, got, req.want)
+				}
+			}
+		})
+	}
+}
+
+func TestShedderDone(t *testing.T) {
+	config := NewDefaultConfig()
+	config.MaxSingleAdapterFraction = 0.1
+	ds := &fakeDatastore{pods: []*backendmetrics.FakePodMetrics{{
+		Pod:     &backend.Pod{NamespacedName: types.NamespacedName{Name: "pod1"}},
+		Metrics: &backendmetrics.MetricsState{WaitingQueueSize: 100, ActiveModels: map[string]int{"adapter-a": 0}, UpdateTime: time.Now().Add(time.Hour)},
+	}}}
+	shedder, err := NewShedder(config, ds, logr.Discard())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !shedder.Admit(context.Background(), "adapter-a", false) {
+		t.Fatal("Expected the first request to be admitted")
+	}
+	if shedder.Admit(context.Background(), "adapter-a", false) {
+		t.Fatal("Expected the second request to be shed")
+	}
+	shedder.Done("adapter-a")
+	if !shedder.Admit(context.Background(), "adapter-a", false) {
+		t.Error("Expected a request to be admitted once the previous one is done")
+	}
+}
@@ -20,18 +20,23 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	podutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/pod"
 )
 
 // FakePodMetrics is an implementation of PodMetrics that doesn't run the async refresh loop.
 type FakePodMetrics struct {
 	Pod     *backend.Pod
 	Metrics *MetricsState
+	History *MetricsHistory
+	// InFlight is the count of the requests routed to the pod, nil if they aren't counted.
+	InFlight *RequestsInFlight
 }
 
 func (fpm *FakePodMetrics) String() string {
@@ -44,8 +49,33 @@ func (fpm *FakePodMetrics) GetPod() *backend.Pod {
 func (fpm *FakePodMetrics) GetMetrics() *MetricsState {
 	return fpm.Metrics
 }
+func (fpm *FakePodMetrics) GetMetricsHistory() *MetricsHistory {
+	return fpm.History
+}
+func (fpm *FakePodMetrics) GetRequestsInFlight() *RequestsInFlight {
+	return fpm.InFlight
+}
 func (fpm *FakePodMetrics) UpdatePod(pod *corev1.Pod) {
 	fpm.Pod = toInternalPod(pod)
+	fpm.Pod.Cordoned = podutil.IsPodCordoned(pod)
+}
+func (fpm *FakePodMetrics) SetCordoned(cordoned bool) {
+	fpm.Pod.Cordoned = cordoned
+}
+func (fpm *FakePodMetrics) RecordAdapterLoaded(adapter string) {
+	if fpm.Metrics == nil {
+		fpm.Metrics = newMetricsState()
+	}
+	if fpm.Metrics.ActiveModels == nil {
+		fpm.Metrics.ActiveModels = make(map[string]int)
+	}
+	fpm.Metrics.ActiveModels[adapter] = 0
+}
+func (fpm *FakePodMetrics) PushMetrics(pushed *PushedMetrics) {
+	if fpm.Metrics == nil {
+		fpm.Metrics = newMetricsState()
+	}
+	pushed.applyTo(fpm.Metrics, time.Now())
 }
 func (fpm *FakePodMetrics) StopRefreshLoop() {} // noop
 
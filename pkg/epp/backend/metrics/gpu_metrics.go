@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/multierr"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+)
+
+const (
+	// DefaultGPUMetricsRefreshInterval bounds how often the GPU metrics are scraped, as GPU exporters
+	// usually update them less often than the model servers update their metrics.
+	DefaultGPUMetricsRefreshInterval = time.Second
+
+	// podLabel and namespaceLabel are the labels of the GPU metrics giving the pod using the GPU,
+	// as exported by the DCGM exporter with its Kubernetes mapping enabled.
+	podLabel       = "pod"
+	namespaceLabel = "namespace"
+)
+
+// GPUMetricMapping holds the MetricSpecs of the GPU metrics. All of them are required.
+type GPUMetricMapping struct {
+	// Utilization is the percentage [0, 100] of the time a GPU was busy, e.g. DCGM_FI_DEV_GPU_UTIL.
+	Utilization *MetricSpec
+	// MemoryFree and MemoryUsed are the free and used memory of a GPU in the same unit, e.g.
+	// DCGM_FI_DEV_FB_FREE and DCGM_FI_DEV_FB_USED.
+	MemoryFree *MetricSpec
+	MemoryUsed *MetricSpec
+}
+
+// NewGPUMetricMapping creates a GPUMetricMapping from string values. It returns nil if none of them
+// is set, i.e. if the GPU metrics are not scraped.
+func NewGPUMetricMapping(utilizationStr, memoryFreeStr, memoryUsedStr string) (*GPUMetricMapping, error) {
+	if utilizationStr == "" && memoryFreeStr == "" && memoryUsedStr == "" {
+		return nil, nil
+	}
+	if utilizationStr == "" || memoryFreeStr == "" || memoryUsedStr == "" {
+		return nil, fmt.Errorf("the GPU utilization, free memory and used memory metrics must be set together")
+	}
+	utilizationSpec, err := stringToMetricSpec(utilizationStr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing GPUUtilization: %w", err)
+	}
+	memoryFreeSpec, err := stringToMetricSpec(memoryFreeStr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing GPUMemoryFree: %w", err)
+	}
+	memoryUsedSpec, err := stringToMetricSpec(memoryUsedStr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing GPUMemoryUsed: %w", err)
+	}
+	return &GPUMetricMapping{
+		Utilization: utilizationSpec,
+		MemoryFree:  memoryFreeSpec,
+		MemoryUsed:  memoryUsedSpec,
+	}, nil
+}
+
+// GPUMetricsClient is a PodMetricsClient adding the GPU metrics of the pods to the metrics fetched
+// by another PodMetricsClient. The GPU metrics are scraped from an exporter running next to the
+// model server (e.g. as a sidecar), on the node of the pod (e.g. the DCGM exporter DaemonSet), or
+// from the model server itself.
+type GPUMetricsClient struct {
+	PodMetricsClient
+	Mapping *GPUMetricMapping
+	// Port is the port of the GPU metrics exporter. Zero means the GPU metrics are served by the
+	// model server, on the target port of the pool.
+	Port int32
+	// OnNode is set if the exporter runs on the node of the pod, and exports the metrics of all the
+	// GPUs of the node. The metrics are then scraped from the host IP of the pod, and the metrics of
+	// the GPUs of the pod are selected by their pod and namespace labels.
+	OnNode bool
+	// RefreshInterval bounds how often the GPU metrics of a pod are scraped.
+	RefreshInterval time.Duration
+}
+
+// FetchMetrics fetches the metrics of the pod with the wrapped PodMetricsClient, and adds its GPU
+// metrics if they were last scraped more than RefreshInterval ago.
+func (c *GPUMetricsClient) FetchMetrics(ctx context.Context, pod *backend.Pod, existing *MetricsState, port int32) (*MetricsState, error) {
+	updated, errs := c.PodMetricsClient.FetchMetrics(ctx, pod, existing, port)
+	if updated == nil || time.Since(updated.GPUMetricsUpdateTime) < c.RefreshInterval {
+		return updated, errs
+	}
+
+	address, gpuPort := pod.Address, port
+	if c.Port != 0 {
+		gpuPort = c.Port
+	}
+	var podLabels map[string]string
+	if c.OnNode {
+		address = pod.HostIP
+		podLabels = map[string]string{podLabel: pod.NamespacedName.Name, namespaceLabel: pod.NamespacedName.Namespace}
+	}
+	metricFamilies, err := scrape(ctx, "http://"+address+":"+strconv.Itoa(int(gpuPort))+"/metrics")
+	if err != nil {
+		return updated, multierr.Append(errs, fmt.Errorf("failed to fetch GPU metrics of %s: %w", pod.NamespacedName, err))
+	}
+	return updated, multierr.Append(errs, c.promToGPUMetrics(metricFamilies, podLabels, updated))
+}
+
+// promToGPUMetrics updates the GPU metrics of the given MetricsState with the scraped Prometheus
+// metrics of the GPUs matching the given labels: the utilization is averaged over the GPUs, and the
+// memory headroom is the free fraction of their total memory.
+func (c *GPUMetricsClient) promToGPUMetrics(metricFamilies map[string]*dto.MetricFamily, podLabels map[string]string, updated *MetricsState) error {
+	utilization, err := gpuMetricValues(metricFamilies, c.Mapping.Utilization, podLabels)
+	if err != nil {
+		return err
+	}
+	memoryFree, err := gpuMetricValues(metricFamilies, c.Mapping.MemoryFree, podLabels)
+	if err != nil {
+		return err
+	}
+	memoryUsed, err := gpuMetricValues(metricFamilies, c.Mapping.MemoryUsed, podLabels)
+	if err != nil {
+		return err
+	}
+
+	free, total := sum(memoryFree), sum(memoryFree)+sum(memoryUsed)
+	if total <= 0 {
+		return fmt.Errorf("no GPU memory reported by %q and %q", c.Mapping.MemoryFree.MetricName, c.Mapping.MemoryUsed.MetricName)
+	}
+	updated.GPUUtilization = min(max(sum(utilization)/float64(len(utilization))/100, 0), 1)
+	updated.GPUMemoryHeadroom = free / total
+	updated.GPUMetricsUpdateTime = time.Now()
+	return nil
+}
+
+// gpuMetricValues returns the values of the metrics of the given spec that also match the given
+// labels, one per GPU.
+func gpuMetricValues(metricFamilies map[string]*dto.MetricFamily, spec *MetricSpec, podLabels map[string]string) ([]float64, error) {
+	mf, ok := metricFamilies[spec.MetricName]
+	if !ok {
+		return nil, fmt.Errorf("metric family %q not found", spec.MetricName)
+	}
+	values := []float64{}
+	for _, m := range mf.GetMetric() {
+		if labelsMatch(m.GetLabel(), spec.Labels) && labelsMatch(m.GetLabel(), podLabels) {
+			values = append(values, gaugeOrUntypedValue(m))
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no matching metric found for %q with labels %+v", spec.MetricName, podLabels)
+	}
+	return values, nil
+}
+
+// gaugeOrUntypedValue returns the value of a gauge, or of an untyped metric as exporters don't
+// always declare the type of their metrics.
+func gaugeOrUntypedValue(m *dto.Metric) float64 {
+	if m.GetUntyped() != nil {
+		return m.GetUntyped().GetValue()
+	}
+	return m.GetGauge().GetValue()
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+)
+
+var dcgmMapping = &GPUMetricMapping{
+	Utilization: &MetricSpec{MetricName: "DCGM_FI_DEV_GPU_UTIL"},
+	MemoryFree:  &MetricSpec{MetricName: "DCGM_FI_DEV_FB_FREE"},
+	MemoryUsed:  &MetricSpec{MetricName: "DCGM_FI_DEV_FB_USED"},
+}
+
+func TestNewGPUMetricMapping(t *testing.T) {
+	mapping, err := NewGPUMetricMapping("", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, mapping)
+
+	_, err = NewGPUMetricMapping("DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_FB_FREE", "")
+	assert.Error(t, err)
+
+	mapping, err = NewGPUMetricMapping("DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_FB_FREE{gpu=0}", "DCGM_FI_DEV_FB_USED")
+	assert.NoError(t, err)
+	assert.Equal(t, &MetricSpec{MetricName: "DCGM_FI_DEV_FB_FREE", Labels: map[string]string{"gpu": "0"}}, mapping.MemoryFree)
+}
+
+func TestPromToGPUMetrics(t *testing.T) {
+	pod1 := map[string]string{podLabel: "pod1", namespaceLabel: "default"}
+	pod1GPU1 := map[string]string{podLabel: "pod1", namespaceLabel: "default", "gpu": "1"}
+	pod2 := map[string]string{podLabel: "pod2", namespaceLabel: "default"}
+	metricFamilies := map[string]*dto.MetricFamily{
+		"DCGM_FI_DEV_GPU_UTIL": makeMetricFamily("DCGM_FI_DEV_GPU_UTIL",
+			makeMetric(pod1, 40, 0), makeMetric(pod1GPU1, 80, 0), makeMetric(pod2, 100, 0)),
+		"DCGM_FI_DEV_FB_FREE": makeMetricFamily("DCGM_FI_DEV_FB_FREE",
+			makeMetric(pod1, 1000, 0), makeMetric(pod1GPU1, 3000, 0), makeMetric(pod2, 0, 0)),
+		"DCGM_FI_DEV_FB_USED": makeMetricFamily("DCGM_FI_DEV_FB_USED",
+			makeMetric(pod1, 7000, 0), makeMetric(pod1GPU1, 5000, 0), makeMetric(pod2, 8000, 0)),
+	}
+	client := &GPUMetricsClient{Mapping: dcgmMapping}
+
+	// The metrics of the two GPUs of pod1 are aggregated.
+	state := newMetricsState()
+	assert.NoError(t, client.promToGPUMetrics(metricFamilies, map[string]string{podLabel: "pod1", namespaceLabel: "default"}, state))
+	assert.InDelta(t, 0.6, state.GPUUtilization, 0.0001)
+	assert.InDelta(t, 0.25, state.GPUMemoryHeadroom, 0.0001)
+	assert.False(t, state.GPUMetricsUpdateTime.IsZero())
+
+	// A pod without GPU metrics is an error, and keeps its metrics.
+	state = newMetricsState()
+	assert.Error(t, client.promToGPUMetrics(metricFamilies, map[string]string{podLabel: "pod3", namespaceLabel: "default"}, state))
+	assert.True(t, state.GPUMetricsUpdateTime.IsZero())
+}
+
+// staticPodMetricsClient returns a clone of the existing metrics.
+type staticPodMetricsClient struct{}
+
+func (staticPodMetricsClient) FetchMetrics(_ context.Context, _ *backend.Pod, existing *MetricsState, _ int32) (*MetricsState, error) {
+	return existing.Clone(), nil
+}
+
+func TestGPUMetricsClientFetchMetrics(t *testing.T) {
+	scrapes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scrapes++
+		_, _ = fmt.Fprint(w, "DCGM_FI_DEV_GPU_UTIL{gpu=\"0\"} 50\nDCGM_FI_DEV_FB_FREE{gpu=\"0\"} 6000\nDCGM_FI_DEV_FB_USED{gpu=\"0\"} 2000\n")
+	}))
+	defer server.Close()
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	assert.NoError(t, err)
+
+	client := &GPUMetricsClient{
+		PodMetricsClient: staticPodMetricsClient{},
+		Mapping:          dcgmMapping,
+		Port:             int32(port),
+		RefreshInterval:  time.Minute,
+	}
+	pod := &backend.Pod{NamespacedName: types.NamespacedName{Name: "pod1", Namespace: "default"}, Address: host}
+
+	updated, err := client.FetchMetrics(context.Background(), pod, newMetricsState(), 8000)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, updated.GPUUtilization, 0.0001)
+	assert.InDelta(t, 0.75, updated.GPUMemoryHeadroom, 0.0001)
+
+	// The GPU metrics are not scraped again within the refresh interval.
+	_, err = client.FetchMetrics(context.Background(), pod, updated, 8000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, scrapes)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	orcav3 "github.com/cncf/xds/go/xds/data/orca/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// LoadReportHeader is the response header carrying the ORCA load report of the model server,
+	// in the TEXT or JSON format.
+	LoadReportHeader = "endpoint-load-metrics"
+	// LoadReportBinaryHeader is the response header carrying the ORCA load report of the model
+	// server as a base64 encoded protobuf.
+	LoadReportBinaryHeader = "endpoint-load-metrics-bin"
+	// LoadReportFormatHeader is the request header asking the model server for an ORCA load report
+	// in the given format.
+	LoadReportFormatHeader = "endpoint-load-metrics-format"
+	// LoadReportFormatText is the TEXT format of the ORCA load reports.
+	LoadReportFormatText = "TEXT"
+)
+
+// Names of the ORCA named metrics reported by the model servers, e.g. vLLM.
+const (
+	loadReportWaitingQueueSize = "num_requests_waiting"
+	loadReportRunningQueueSize = "num_requests_running"
+	loadReportKVCacheUsage     = "kv_cache_usage_perc"
+)
+
+// ParseLoadReport returns the metrics of the ORCA load report carried by the given response headers,
+// or nil if the headers carry none or it has none of the named metrics of the model servers.
+func ParseLoadReport(headers map[string]string) (*PushedMetrics, error) {
+	report := &orcav3.OrcaLoadReport{}
+	if value, ok := headers[LoadReportBinaryHeader]; ok {
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", LoadReportBinaryHeader, err)
+		}
+		if err := proto.Unmarshal(data, report); err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", LoadReportBinaryHeader, err)
+		}
+	} else if value, ok := headers[LoadReportHeader]; ok {
+		format, data, _ := strings.Cut(strings.TrimSpace(value), " ")
+		var err error
+		switch format {
+		case LoadReportFormatText:
+			report, err = parseTextLoadReport(data)
+		case "JSON":
+			err = protojson.Unmarshal([]byte(data), report)
+		default:
+			err = fmt.Errorf("unsupported format %q", format)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s header: %w", LoadReportHeader, err)
+		}
+	} else {
+		return nil, nil
+	}
+
+	pushed := &PushedMetrics{Piggybacked: true}
+	found := false
+	if value, ok := report.NamedMetrics[loadReportWaitingQueueSize]; ok {
+		waiting := int(value)
+		pushed.WaitingQueueSize = &waiting
+		found = true
+	}
+	if value, ok := report.NamedMetrics[loadReportRunningQueueSize]; ok {
+		running := int(value)
+		pushed.RunningQueueSize = &running
+		found = true
+	}
+	if value, ok := report.NamedMetrics[loadReportKVCacheUsage]; ok {
+		pushed.KVCacheUsagePercent = &value
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return pushed, pushed.Validate()
+}
+
+// parseTextLoadReport parses the metrics of an ORCA load report in the TEXT format, i.e. a comma
+// separated list of key=value pairs, e.g. "cpu_utilization=0.3, named_metrics.kv_cache_usage_perc=0.4".
+// Only the named metrics are kept.
+func parseTextLoadReport(data string) (*orcav3.OrcaLoadReport, error) {
+	report := &orcav3.OrcaLoadReport{NamedMetrics: map[string]float64{}}
+	for _, pair := range strings.Split(data, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid metric %q, must be key=value", pair)
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of metric %q: %w", key, err)
+		}
+		if name, ok := strings.CutPrefix(strings.TrimSpace(key), "named_metrics."); ok {
+			report.NamedMetrics[name] = number
+		}
+	}
+	return report, nil
+}
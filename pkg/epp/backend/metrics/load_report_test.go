@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/base64"
+	"testing"
+
+	orcav3 "github.com/cncf/xds/go/xds/data/orca/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseLoadReport(t *testing.T) {
+	binary, err := proto.Marshal(&orcav3.OrcaLoadReport{
+		CpuUtilization: 0.5,
+		NamedMetrics:   map[string]float64{"num_requests_running": 8},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal load report: %v", err)
+	}
+	intPtr := func(i int) *int { return &i }
+	floatPtr := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    *PushedMetrics
+		wantErr bool
+	}{
+		{
+			name:    "no load report",
+			headers: map[string]string{"content-type": "application/json"},
+		},
+		{
+			name: "text",
+			headers: map[string]string{
+				LoadReportHeader: "TEXT cpu_utilization=0.3, named_metrics.kv_cache_usage_perc=0.4, named_metrics.num_requests_waiting=2",
+			},
+			want: &PushedMetrics{WaitingQueueSize: intPtr(2), KVCacheUsagePercent: floatPtr(0.4), Piggybacked: true},
+		},
+		{
+			name: "json",
+			headers: map[string]string{
+				LoadReportHeader: `JSON {"cpu_utilization": 0.3, "named_metrics": {"num_requests_waiting": 5}}`,
+			},
+			want: &PushedMetrics{WaitingQueueSize: intPtr(5), Piggybacked: true},
+		},
+		{
+			name:    "binary",
+			headers: map[string]string{LoadReportBinaryHeader: base64.StdEncoding.EncodeToString(binary)},
+			want:    &PushedMetrics{RunningQueueSize: intPtr(8), Piggybacked: true},
+		},
+		{
+			name:    "no named metric of the model servers",
+			headers: map[string]string{LoadReportHeader: "TEXT cpu_utilization=0.3, named_metrics.foo=1"},
+		},
+		{
+			name:    "unsupported format",
+			headers: map[string]string{LoadReportHeader: "YAML cpu_utilization: 0.3"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid text",
+			headers: map[string]string{LoadReportHeader: "TEXT named_metrics.num_requests_waiting"},
+			wantErr: true,
+		},
+		{
+			name:    "out of range metric",
+			headers: map[string]string{LoadReportHeader: "TEXT named_metrics.kv_cache_usage_perc=4"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseLoadReport(test.headers)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
@@ -38,6 +40,11 @@ const (
 
 type PodMetricsClientImpl struct {
 	MetricMapping *MetricMapping
+	// ModelServerMappings are the mappings of the pods labeled with the ModelServerLabel, by model
+	// server. The pods without the label are scraped with MetricMapping, and the pods labeled with a
+	// model server without a mapping are scraped for their queue size only, with the first of the
+	// queue metrics of the mappings they expose.
+	ModelServerMappings map[string]*MetricMapping
 }
 
 // FetchMetrics fetches metrics from a given pod, clones the existing metrics object and returns an updated one.
@@ -46,28 +53,74 @@ func (p *PodMetricsClientImpl) FetchMetrics(ctx context.Context, pod *backend.Po
 	// TODO(https://github.com/kubernetes-sigs/gateway-api-inference-extension/issues/16): Consume this from InferencePool config.
 	url := "http://" + pod.Address + ":" + strconv.Itoa(int(port)) + "/metrics"
 
+	metricFamilies, err := scrape(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metrics from %s: %w", pod.NamespacedName, err)
+	}
+	modelServer, ok := pod.Labels[ModelServerLabel]
+	if !ok {
+		return p.promToPodMetrics(metricFamilies, existing)
+	}
+	if mapping, ok := p.ModelServerMappings[modelServer]; ok {
+		return (&PodMetricsClientImpl{MetricMapping: mapping}).promToPodMetrics(metricFamilies, existing)
+	}
+	return p.promToQueueDepth(metricFamilies, existing)
+}
+
+// promToQueueDepth updates the queue size of the pod metrics with the first queue metric of the
+// known mappings found in the scraped Prometheus metrics, and marks the metrics as QueueDepthOnly.
+func (p *PodMetricsClientImpl) promToQueueDepth(metricFamilies map[string]*dto.MetricFamily, existing *MetricsState) (*MetricsState, error) {
+	updated := existing.Clone()
+	updated.QueueDepthOnly = true
+	for _, spec := range p.queueSpecs() {
+		if queued, err := p.getMetric(metricFamilies, *spec); err == nil {
+			updated.WaitingQueueSize = int(queued.GetGauge().GetValue())
+			return updated, nil
+		}
+	}
+	return updated, fmt.Errorf("no known queue metric found")
+}
+
+// queueSpecs returns the queue metrics of MetricMapping and then of the ModelServerMappings, by
+// model server name.
+func (p *PodMetricsClientImpl) queueSpecs() []*MetricSpec {
+	specs := []*MetricSpec{}
+	if p.MetricMapping.TotalQueuedRequests != nil {
+		specs = append(specs, p.MetricMapping.TotalQueuedRequests)
+	}
+	names := make([]string, 0, len(p.ModelServerMappings))
+	for name := range p.ModelServerMappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if spec := p.ModelServerMappings[name].TotalQueuedRequests; spec != nil {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// scrape fetches and parses the Prometheus metrics served at the given URL.
+func scrape(ctx context.Context, url string) (map[string]*dto.MetricFamily, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch metrics from %s: %w", pod.NamespacedName, err)
+		return nil, err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code from %s: %v", pod.NamespacedName, resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
 	}
 
 	parser := expfmt.TextParser{}
-	metricFamilies, err := parser.TextToMetricFamilies(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return p.promToPodMetrics(metricFamilies, existing)
+	return parser.TextToMetricFamilies(resp.Body)
 }
 
 // promToPodMetrics updates internal pod metrics with scraped Prometheus metrics.
@@ -77,6 +130,7 @@ func (p *PodMetricsClientImpl) promToPodMetrics(
 ) (*MetricsState, error) {
 	var errs error
 	updated := existing.Clone()
+	updated.QueueDepthOnly = false
 
 	if p.MetricMapping.TotalQueuedRequests != nil {
 		queued, err := p.getMetric(metricFamilies, *p.MetricMapping.TotalQueuedRequests)
@@ -96,6 +150,19 @@ func (p *PodMetricsClientImpl) promToPodMetrics(
 		}
 	}
 
+	if p.MetricMapping.KVCacheFreeBlocks != nil {
+		errs = multierr.Append(errs, p.promToKVCacheBlocks(metricFamilies, updated))
+	}
+
+	if p.MetricMapping.MaxConcurrency != nil {
+		maxConcurrency, err := p.getMetric(metricFamilies, *p.MetricMapping.MaxConcurrency)
+		if err == nil {
+			updated.MaxConcurrency = max(int(gaugeOrUntypedValue(maxConcurrency)), 0)
+		} else {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
 	// Handle LoRA metrics (only if all LoRA MetricSpecs are present)
 	if p.MetricMapping.LoraRequestInfo != nil {
 		loraMetrics, err := p.getLatestLoraMetric(metricFamilies)
@@ -130,12 +197,34 @@ func (p *PodMetricsClientImpl) promToPodMetrics(
 					}
 				}
 			}
+			updated.markLoaded(time.Now(), updated.ActiveModels)
 		}
 	}
 
 	return updated, errs
 }
 
+// promToKVCacheBlocks updates the KV cache block metrics of the given MetricsState. The
+// fragmentation is zero if its metric isn't configured.
+func (p *PodMetricsClientImpl) promToKVCacheBlocks(metricFamilies map[string]*dto.MetricFamily, updated *MetricsState) error {
+	freeBlocks, err := p.getMetric(metricFamilies, *p.MetricMapping.KVCacheFreeBlocks)
+	if err != nil {
+		return err
+	}
+	fragmentation := 0.0
+	if p.MetricMapping.KVCacheFragmentation != nil {
+		metric, err := p.getMetric(metricFamilies, *p.MetricMapping.KVCacheFragmentation)
+		if err != nil {
+			return err
+		}
+		fragmentation = min(max(gaugeOrUntypedValue(metric), 0), 1)
+	}
+	updated.KVCacheFreeBlocks = max(int(gaugeOrUntypedValue(freeBlocks)), 0)
+	updated.KVCacheFragmentation = fragmentation
+	updated.KVCacheBlocksUpdateTime = time.Now()
+	return nil
+}
+
 // getLatestLoraMetric gets latest lora metric series in gauge metric family `vllm:lora_requests_info`
 // reason its specially fetched is because each label key value pair permutation generates new series
 // and only most recent is useful. The value of each series is the creation timestamp so we can
@@ -184,8 +273,16 @@ func (p *PodMetricsClientImpl) getLatestLoraMetric(metricFamilies map[string]*dt
 	return latest, nil // Convert nanoseconds to time.Time
 }
 
-// getMetric retrieves a specific metric based on MetricSpec.
+// getMetric retrieves a specific metric based on MetricSpec. The metrics summed or divided are
+// returned as a gauge of the resulting value.
 func (p *PodMetricsClientImpl) getMetric(metricFamilies map[string]*dto.MetricFamily, spec MetricSpec) (*dto.Metric, error) {
+	if spec.Sum || spec.Divisor != nil {
+		value, err := metricValue(metricFamilies, spec)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.Metric{Gauge: &dto.Gauge{Value: &value}}, nil
+	}
 	mf, ok := metricFamilies[spec.MetricName]
 	if !ok {
 		return nil, fmt.Errorf("metric family %q not found", spec.MetricName)
@@ -198,6 +295,57 @@ func (p *PodMetricsClientImpl) getMetric(metricFamilies map[string]*dto.MetricFa
 	return getLatestMetric(mf, &spec)
 }
 
+// metricValue returns the value of the metric of the given spec, summed and divided as specified.
+func metricValue(metricFamilies map[string]*dto.MetricFamily, spec MetricSpec) (float64, error) {
+	value, err := seriesValue(metricFamilies, spec)
+	if err != nil || spec.Divisor == nil {
+		return value, err
+	}
+	divisor, err := seriesValue(metricFamilies, *spec.Divisor)
+	if err != nil {
+		return 0, err
+	}
+	if divisor == 0 {
+		return 0, fmt.Errorf("divisor %q of %q is zero", spec.Divisor.MetricName, spec.MetricName)
+	}
+	return value / divisor, nil
+}
+
+// seriesValue returns the sum of the values of the series matching the given spec if it's summed,
+// or else the value of the latest one, ignoring the divisor of the spec.
+func seriesValue(metricFamilies map[string]*dto.MetricFamily, spec MetricSpec) (float64, error) {
+	mf, ok := metricFamilies[spec.MetricName]
+	if !ok || len(mf.GetMetric()) == 0 {
+		return 0, fmt.Errorf("metric family %q not found", spec.MetricName)
+	}
+	if !spec.Sum {
+		m, err := getLatestMetric(mf, &spec)
+		if err != nil {
+			return 0, err
+		}
+		return sampleValue(m), nil
+	}
+	total, matched := 0.0, false
+	for _, m := range mf.GetMetric() {
+		if labelsMatch(m.GetLabel(), spec.Labels) {
+			total += sampleValue(m)
+			matched = true
+		}
+	}
+	if !matched {
+		return 0, fmt.Errorf("no matching metric found for %q with labels %+v", spec.MetricName, spec.Labels)
+	}
+	return total, nil
+}
+
+// sampleValue returns the value of a gauge, counter or untyped metric.
+func sampleValue(m *dto.Metric) float64 {
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+	return gaugeOrUntypedValue(m)
+}
+
 // getLabeledMetric gets the latest metric with matching labels.
 func getLatestMetric(mf *dto.MetricFamily, spec *MetricSpec) (*dto.Metric, error) {
 	var latestMetric *dto.Metric
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultMetricsHistorySize is the number of metrics samples kept per pod.
+const DefaultMetricsHistorySize = 20
+
+// MetricFunc extracts a numeric metric from a MetricsState.
+type MetricFunc func(*MetricsState) float64
+
+var (
+	WaitingQueueSizeMetric MetricFunc = func(m *MetricsState) float64 { return float64(m.WaitingQueueSize) }
+	RunningQueueSizeMetric MetricFunc = func(m *MetricsState) float64 { return float64(m.RunningQueueSize) }
+	KVCacheUsageMetric     MetricFunc = func(m *MetricsState) float64 { return m.KVCacheUsagePercent }
+)
+
+// NewMetricsHistory initializes a new MetricsHistory keeping the given number of samples, or
+// DefaultMetricsHistorySize if not positive, and returns its pointer.
+func NewMetricsHistory(size int) *MetricsHistory {
+	if size <= 0 {
+		size = DefaultMetricsHistorySize
+	}
+	return &MetricsHistory{samples: make([]*MetricsState, size)}
+}
+
+// MetricsHistory keeps the recent metrics samples of a pod in a ring buffer, so the plugins
+// depending on the trend of the metrics (e.g. the growth of the queue) share the same history
+// instead of each keeping their own. The samples are shared and must not be modified.
+// All the methods are safe for concurrent use, and a nil MetricsHistory holds no samples.
+type MetricsHistory struct {
+	mu      sync.RWMutex
+	samples []*MetricsState
+	next    int // index of the next sample to write
+	count   int
+}
+
+// Add records a sample, overwriting the oldest one if the history is full.
+func (h *MetricsHistory) Add(sample *MetricsState) {
+	if h == nil || sample == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+	h.count = min(h.count+1, len(h.samples))
+}
+
+// String returns the number of samples, without reading them unsafely when a snapshot of the pods
+// is printed.
+func (h *MetricsHistory) String() string {
+	if h == nil {
+		return ""
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return fmt.Sprintf("%d samples", h.count)
+}
+
+// Samples returns the samples, from the oldest to the newest.
+func (h *MetricsHistory) Samples() []*MetricsState {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.window(0)
+}
+
+// window returns the samples updated within the given duration before the newest one, or all the
+// samples if the duration isn't positive, from the oldest to the newest. It must be called with mu held.
+func (h *MetricsHistory) window(duration time.Duration) []*MetricsState {
+	samples := make([]*MetricsState, 0, h.count)
+	start := (h.next - h.count + len(h.samples)) % len(h.samples)
+	for i := range h.count {
+		samples = append(samples, h.samples[(start+i)%len(h.samples)])
+	}
+	if duration <= 0 || len(samples) == 0 {
+		return samples
+	}
+	since := samples[len(samples)-1].UpdateTime.Add(-duration)
+	for len(samples) > 1 && samples[0].UpdateTime.Before(since) {
+		samples = samples[1:]
+	}
+	return samples
+}
+
+// Rate returns the change per second of the metric between the oldest and the newest samples
+// within the given duration before the newest sample (or of all the samples if the duration isn't
+// positive). It returns false if there aren't two samples apart in time to compare.
+func (h *MetricsHistory) Rate(metric MetricFunc, duration time.Duration) (float64, bool) {
+	if h == nil {
+		return 0, false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	samples := h.window(duration)
+	if len(samples) < 2 {
+		return 0, false
+	}
+	oldest, newest := samples[0], samples[len(samples)-1]
+	elapsed := newest.UpdateTime.Sub(oldest.UpdateTime).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return (metric(newest) - metric(oldest)) / elapsed, true
+}
+
+// MovingAverage returns the average of the metric over the samples within the given duration
+// before the newest sample (or over all the samples if the duration isn't positive). It returns
+// false if there are no samples.
+func (h *MetricsHistory) MovingAverage(metric MetricFunc, duration time.Duration) (float64, bool) {
+	if h == nil {
+		return 0, false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	samples := h.window(duration)
+	if len(samples) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, sample := range samples {
+		sum += metric(sample)
+	}
+	return sum / float64(len(samples)), true
+}
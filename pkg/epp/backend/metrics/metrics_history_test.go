@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsHistory(t *testing.T) {
+	start := time.Now()
+	sample := func(seconds int, waitingQueueSize int) *MetricsState {
+		return &MetricsState{WaitingQueueSize: waitingQueueSize, UpdateTime: start.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	h := NewMetricsHistory(3)
+	if _, ok := h.Rate(WaitingQueueSizeMetric, 0); ok {
+		t.Errorf("Expected no rate without samples")
+	}
+	if _, ok := h.MovingAverage(WaitingQueueSizeMetric, 0); ok {
+		t.Errorf("Expected no average without samples")
+	}
+	h.Add(sample(0, 100))
+	if _, ok := h.Rate(WaitingQueueSizeMetric, 0); ok {
+		t.Errorf("Expected no rate with a single sample")
+	}
+
+	// The oldest sample is overwritten once the history is full.
+	for i, size := range []int{2, 4, 8} {
+		h.Add(sample(i+1, size))
+	}
+	samples := h.Samples()
+	if len(samples) != 3 || samples[0].WaitingQueueSize != 2 || samples[2].WaitingQueueSize != 8 {
+		t.Errorf("Unexpected samples %v", samples)
+	}
+
+	tests := []struct {
+		name        string
+		duration    time.Duration
+		wantRate    float64
+		wantAverage float64
+	}{
+		{name: "all samples", duration: 0, wantRate: 3, wantAverage: 14.0 / 3},
+		{name: "recent samples", duration: time.Second, wantRate: 4, wantAverage: 6},
+		{name: "window longer than the history", duration: time.Hour, wantRate: 3, wantAverage: 14.0 / 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rate, ok := h.Rate(WaitingQueueSizeMetric, test.duration); !ok || rate != test.wantRate {
+				t.Errorf("Got rate %v (%t), want %v", rate, ok, test.wantRate)
+			}
+			if average, ok := h.MovingAverage(WaitingQueueSizeMetric, test.duration); !ok || average != test.wantAverage {
+				t.Errorf("Got average %v (%t), want %v", average, ok, test.wantAverage)
+			}
+		})
+	}
+
+	// A nil history holds no samples.
+	var none *MetricsHistory
+	none.Add(sample(0, 1))
+	if _, ok := none.MovingAverage(WaitingQueueSizeMetric, 0); ok || len(none.Samples()) != 0 {
+		t.Errorf("Expected no samples in a nil history")
+	}
+}
@@ -25,6 +25,12 @@ import (
 type MetricSpec struct {
 	MetricName string
 	Labels     map[string]string // Label name -> Label value
+	// Sum sums the values of all the series matching the labels, e.g. of the series of each model
+	// served by a Triton server, instead of taking the latest one.
+	Sum bool
+	// Divisor, if set, is the metric the value of the metric is divided by, e.g. to get the fraction
+	// of the KV cache blocks in use from the used and the total numbers of blocks.
+	Divisor *MetricSpec
 }
 
 // MetricMapping holds named MetricSpecs.
@@ -32,6 +38,14 @@ type MetricMapping struct {
 	TotalQueuedRequests *MetricSpec
 	KVCacheUtilization  *MetricSpec
 	LoraRequestInfo     *MetricSpec
+	// KVCacheFreeBlocks is the number of free KV cache blocks, and KVCacheFragmentation the fraction
+	// [0, 1] of them that can't be allocated to new sequences. They are optional, as only some model
+	// servers expose them.
+	KVCacheFreeBlocks    *MetricSpec
+	KVCacheFragmentation *MetricSpec
+	// MaxConcurrency is the maximum number of concurrent requests of the model server, e.g. its
+	// maximum number of running sequences. It's optional.
+	MaxConcurrency *MetricSpec
 }
 
 // stringToMetricSpec converts a string to a MetricSpec.
@@ -40,11 +54,46 @@ type MetricMapping struct {
 //	"metric_name"
 //	"metric_name{label1=value1}"
 //	"metric_name{label1=value1,label2=value2}"
+//	"sum(metric_name{label1=value1})"
+//	"sum(metric_name{label1=used})/sum(metric_name{label1=max})"
 func stringToMetricSpec(specStr string) (*MetricSpec, error) {
 	if specStr == "" {
 		return nil, nil // Allow empty strings to represent nil MetricSpecs
 	}
 	specStr = strings.TrimSpace(specStr)
+	if slash := indexOutsideLabels(specStr, '/'); slash != -1 {
+		spec, err := stringToMetricSpec(specStr[:slash])
+		if err != nil {
+			return nil, err
+		}
+		divisor, err := stringToMetricSpec(specStr[slash+1:])
+		if err != nil {
+			return nil, err
+		}
+		if spec == nil || divisor == nil {
+			return nil, fmt.Errorf("invalid metric spec string: %q, missing dividend or divisor", specStr)
+		}
+		if spec.Divisor != nil || divisor.Divisor != nil {
+			return nil, fmt.Errorf("invalid metric spec string: %q, only one division is supported", specStr)
+		}
+		spec.Divisor = divisor
+		return spec, nil
+	}
+	if inner, ok := strings.CutPrefix(specStr, "sum("); ok {
+		inner, ok = strings.CutSuffix(inner, ")")
+		if !ok {
+			return nil, fmt.Errorf("invalid metric spec string: %q, missing closing parenthesis", specStr)
+		}
+		spec, err := stringToMetricSpec(inner)
+		if err != nil {
+			return nil, err
+		}
+		if spec == nil || spec.Sum {
+			return nil, fmt.Errorf("invalid metric spec string: %q, invalid sum", specStr)
+		}
+		spec.Sum = true
+		return spec, nil
+	}
 	metricName := specStr
 	labels := make(map[string]string)
 
@@ -92,6 +141,55 @@ func stringToMetricSpec(specStr string) (*MetricSpec, error) {
 	}, nil
 }
 
+// indexOutsideLabels returns the index of the first occurrence of c in s outside of a label block,
+// or -1 if there's none.
+func indexOutsideLabels(s string, c byte) int {
+	inLabels := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			inLabels = true
+		case '}':
+			inLabels = false
+		case c:
+			if !inLabels {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// SetKVCacheBlockMetrics sets the MetricSpecs of the free KV cache blocks and of their
+// fragmentation from string values. The empty values leave the metrics unscraped.
+func (m *MetricMapping) SetKVCacheBlockMetrics(freeBlocksStr, fragmentationStr string) error {
+	freeBlocksSpec, err := stringToMetricSpec(freeBlocksStr)
+	if err != nil {
+		return fmt.Errorf("error parsing KVCacheFreeBlocks: %w", err)
+	}
+	fragmentationSpec, err := stringToMetricSpec(fragmentationStr)
+	if err != nil {
+		return fmt.Errorf("error parsing KVCacheFragmentation: %w", err)
+	}
+	if fragmentationSpec != nil && freeBlocksSpec == nil {
+		return fmt.Errorf("the KV cache fragmentation metric requires the free KV cache blocks metric")
+	}
+	m.KVCacheFreeBlocks = freeBlocksSpec
+	m.KVCacheFragmentation = fragmentationSpec
+	return nil
+}
+
+// SetMaxConcurrencyMetric sets the MetricSpec of the maximum number of concurrent requests from a
+// string value. The empty value leaves the metric unscraped.
+func (m *MetricMapping) SetMaxConcurrencyMetric(maxConcurrencyStr string) error {
+	spec, err := stringToMetricSpec(maxConcurrencyStr)
+	if err != nil {
+		return fmt.Errorf("error parsing MaxConcurrency: %w", err)
+	}
+	m.MaxConcurrency = spec
+	return nil
+}
+
 // NewMetricMapping creates a MetricMapping from string values.
 func NewMetricMapping(queuedStr, kvUsageStr, loraReqInfoStr string) (*MetricMapping, error) {
 	queuedSpec, err := stringToMetricSpec(queuedStr)
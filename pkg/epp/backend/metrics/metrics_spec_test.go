@@ -144,6 +144,53 @@ func TestStringToMetricSpec(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "sum",
+			input: "sum(my_metric{label1=value1})",
+			want: &MetricSpec{
+				MetricName: "my_metric",
+				Labels:     map[string]string{"label1": "value1"},
+				Sum:        true,
+			},
+		},
+		{
+			name:  "division",
+			input: "sum(my_metric{type=used}) / my_metric{type=max}",
+			want: &MetricSpec{
+				MetricName: "my_metric",
+				Labels:     map[string]string{"type": "used"},
+				Sum:        true,
+				Divisor:    &MetricSpec{MetricName: "my_metric", Labels: map[string]string{"type": "max"}},
+			},
+		},
+		{
+			name:  "slash in label value",
+			input: "my_metric{model=org/model}",
+			want: &MetricSpec{
+				MetricName: "my_metric",
+				Labels:     map[string]string{"model": "org/model"},
+			},
+		},
+		{
+			name:    "missing divisor",
+			input:   "my_metric/",
+			wantErr: true,
+		},
+		{
+			name:    "several divisions",
+			input:   "a/b/c",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed sum",
+			input:   "sum(my_metric",
+			wantErr: true,
+		},
+		{
+			name:    "nested sum",
+			input:   "sum(sum(my_metric))",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -162,6 +209,9 @@ func TestStringToMetricSpec(t *testing.T) {
 				if !reflect.DeepEqual(got.Labels, tt.want.Labels) {
 					t.Errorf("stringToMetricSpec() got Labels = %v, want %v", got.Labels, tt.want.Labels)
 				}
+				if got.Sum != tt.want.Sum || !reflect.DeepEqual(got.Divisor, tt.want.Divisor) {
+					t.Errorf("stringToMetricSpec() got Sum = %v, Divisor = %+v, want %v, %+v", got.Sum, got.Divisor, tt.want.Sum, tt.want.Divisor)
+				}
 			} else if tt.want != got { // handles if one is nil and the other isn't
 				t.Errorf("stringToMetricSpec() = %v, want %v", got, tt.want)
 			}
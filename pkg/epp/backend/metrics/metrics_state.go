@@ -17,7 +17,10 @@ limitations under the License.
 package metrics
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -35,11 +38,37 @@ type MetricsState struct {
 	ActiveModels  map[string]int
 	WaitingModels map[string]int
 	// MaxActiveModels is the maximum number of models that can be loaded to GPU.
-	MaxActiveModels         int
+	MaxActiveModels int
+	// LoadedModels is the set of LoRA adapters presumed loaded to GPU, with the last time each of
+	// them was reported active. Model servers only report the adapters of their running requests,
+	// while they keep the idle adapters loaded until the slot of the least recently used one is
+	// needed for another adapter, so the MaxActiveModels most recently active adapters are presumed
+	// loaded.
+	LoadedModels            map[string]time.Time
 	RunningQueueSize        int
 	WaitingQueueSize        int
 	KVCacheUsagePercent     float64
 	KvCacheMaxTokenCapacity int
+	// GPUUtilization is the fraction [0, 1] of the time the GPUs of the pod were busy, averaged over
+	// its GPUs, and GPUMemoryHeadroom the fraction [0, 1] of their memory that is free. They are
+	// only scraped if GPU metrics are configured, as of GPUMetricsUpdateTime, which is zero if they
+	// were never scraped.
+	GPUUtilization       float64
+	GPUMemoryHeadroom    float64
+	GPUMetricsUpdateTime time.Time
+	// KVCacheFreeBlocks is the number of free KV cache blocks of the pod, and KVCacheFragmentation
+	// the fraction [0, 1] of them that can't be allocated to new sequences. They are only scraped
+	// if the model server exposes them, as of KVCacheBlocksUpdateTime, which is zero if they were
+	// never scraped.
+	KVCacheFreeBlocks       int
+	KVCacheFragmentation    float64
+	KVCacheBlocksUpdateTime time.Time
+	// MaxConcurrency is the maximum number of concurrent requests the model server reports it can
+	// run, zero if it isn't scraped.
+	MaxConcurrency int
+	// QueueDepthOnly is set when the model server of the pod has no known metrics, in which case only
+	// its queue size is scraped, the other metrics being zero.
+	QueueDepthOnly bool
 
 	// UpdateTime record the last time when the metrics were updated.
 	UpdateTime time.Time
@@ -67,14 +96,58 @@ func (s *MetricsState) Clone() *MetricsState {
 	for key, value := range s.WaitingModels {
 		waitingModels[key] = value
 	}
+	var loadedModels map[string]time.Time
+	if s.LoadedModels != nil {
+		loadedModels = make(map[string]time.Time, len(s.LoadedModels))
+		for key, value := range s.LoadedModels {
+			loadedModels[key] = value
+		}
+	}
 	return &MetricsState{
 		ActiveModels:            activeModels,
 		WaitingModels:           waitingModels,
 		MaxActiveModels:         s.MaxActiveModels,
+		LoadedModels:            loadedModels,
 		RunningQueueSize:        s.RunningQueueSize,
 		WaitingQueueSize:        s.WaitingQueueSize,
 		KVCacheUsagePercent:     s.KVCacheUsagePercent,
 		KvCacheMaxTokenCapacity: s.KvCacheMaxTokenCapacity,
+		GPUUtilization:          s.GPUUtilization,
+		GPUMemoryHeadroom:       s.GPUMemoryHeadroom,
+		GPUMetricsUpdateTime:    s.GPUMetricsUpdateTime,
+		KVCacheFreeBlocks:       s.KVCacheFreeBlocks,
+		KVCacheFragmentation:    s.KVCacheFragmentation,
+		KVCacheBlocksUpdateTime: s.KVCacheBlocksUpdateTime,
+		MaxConcurrency:          s.MaxConcurrency,
+		QueueDepthOnly:          s.QueueDepthOnly,
 		UpdateTime:              s.UpdateTime,
 	}
 }
+
+// markLoaded records the given models as active at the given time in the loaded models, and
+// forgets the least recently active ones beyond MaxActiveModels, as the model server would have
+// evicted them.
+func (s *MetricsState) markLoaded(now time.Time, models map[string]int) {
+	if len(models) == 0 && (s.MaxActiveModels <= 0 || len(s.LoadedModels) <= s.MaxActiveModels) {
+		return
+	}
+	if s.LoadedModels == nil {
+		s.LoadedModels = make(map[string]time.Time, len(models))
+	}
+	for model := range models {
+		s.LoadedModels[model] = now
+	}
+	if s.MaxActiveModels <= 0 || len(s.LoadedModels) <= s.MaxActiveModels {
+		return
+	}
+	byRecency := make([]string, 0, len(s.LoadedModels))
+	for model := range s.LoadedModels {
+		byRecency = append(byRecency, model)
+	}
+	slices.SortFunc(byRecency, func(a, b string) int {
+		return cmp.Or(s.LoadedModels[b].Compare(s.LoadedModels[a]), strings.Compare(a, b))
+	})
+	for _, model := range byRecency[s.MaxActiveModels:] {
+		delete(s.LoadedModels, model)
+	}
+}
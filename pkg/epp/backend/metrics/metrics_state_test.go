@@ -0,0 +1,46 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkLoaded(t *testing.T) {
+	start := time.Now()
+	state := &MetricsState{MaxActiveModels: 2}
+
+	state.markLoaded(start, map[string]int{"lora1": 0})
+	state.markLoaded(start.Add(time.Second), map[string]int{"lora2": 0})
+	assert.Equal(t, map[string]time.Time{"lora1": start, "lora2": start.Add(time.Second)}, state.LoadedModels)
+
+	// The idle adapters stay loaded until their slot is needed.
+	state.markLoaded(start.Add(2*time.Second), map[string]int{})
+	assert.Len(t, state.LoadedModels, 2)
+
+	// The least recently active adapter is evicted.
+	state.markLoaded(start.Add(3*time.Second), map[string]int{"lora3": 0})
+	assert.Equal(t, map[string]time.Time{"lora2": start.Add(time.Second), "lora3": start.Add(3 * time.Second)}, state.LoadedModels)
+
+	// Fewer slots evict the least recently active adapters.
+	state.MaxActiveModels = 1
+	state.markLoaded(start.Add(4*time.Second), nil)
+	assert.Equal(t, map[string]time.Time{"lora3": start.Add(3 * time.Second)}, state.LoadedModels)
+}
@@ -23,8 +23,10 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/multierr"
 	"google.golang.org/protobuf/proto"
@@ -408,6 +410,7 @@ func TestPromToPodMetrics(t *testing.T) {
 				ActiveModels:        map[string]int{"lora1": 0, "lora2": 0},
 				WaitingModels:       map[string]int{"lora3": 0},
 				MaxActiveModels:     3,
+				LoadedModels:        map[string]time.Time{"lora1": {}, "lora2": {}},
 			},
 		},
 		{
@@ -477,12 +480,114 @@ func TestPromToPodMetrics(t *testing.T) {
 				assert.EqualError(t, err, tc.expectedErr.Error())
 			} else {
 				assert.NoError(t, err)
+				// The loaded models are marked with the time of the scrape.
+				for model := range updated.LoadedModels {
+					updated.LoadedModels[model] = time.Time{}
+				}
 				assert.Equal(t, tc.expectedMetrics, updated)
 			}
 		})
 	}
 }
 
+func TestPromToKVCacheBlocks(t *testing.T) {
+	metricFamilies := map[string]*dto.MetricFamily{
+		"free_blocks": makeMetricFamily("free_blocks", makeMetric(nil, 120, 1000)),
+		"block_fragmentation": makeMetricFamily("block_fragmentation",
+			makeMetric(map[string]string{"pool": "gpu"}, 0.25, 1000),
+			makeMetric(map[string]string{"pool": "cpu"}, 0.9, 1000),
+		),
+	}
+	mapping := &MetricMapping{}
+	if err := mapping.SetKVCacheBlockMetrics("free_blocks", "block_fragmentation{pool=gpu}"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p := &PodMetricsClientImpl{MetricMapping: mapping}
+
+	updated, err := p.promToPodMetrics(metricFamilies, &MetricsState{})
+	assert.NoError(t, err)
+	assert.Equal(t, 120, updated.KVCacheFreeBlocks)
+	assert.Equal(t, 0.25, updated.KVCacheFragmentation)
+	assert.False(t, updated.KVCacheBlocksUpdateTime.IsZero())
+
+	// The block metrics are left as they were when they are missing.
+	existing := updated
+	updated, err = p.promToPodMetrics(map[string]*dto.MetricFamily{}, existing)
+	assert.EqualError(t, err, "metric family \"free_blocks\" not found")
+	assert.Equal(t, existing.KVCacheBlocksUpdateTime, updated.KVCacheBlocksUpdateTime)
+
+	// The fragmentation requires the free blocks.
+	assert.Error(t, (&MetricMapping{}).SetKVCacheBlockMetrics("", "block_fragmentation"))
+}
+
+func TestPromToMaxConcurrency(t *testing.T) {
+	metricFamilies := map[string]*dto.MetricFamily{
+		"max_num_seqs": makeMetricFamily("max_num_seqs", makeMetric(nil, 64, 1000)),
+	}
+	mapping := &MetricMapping{}
+	if err := mapping.SetMaxConcurrencyMetric("max_num_seqs"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p := &PodMetricsClientImpl{MetricMapping: mapping}
+
+	updated, err := p.promToPodMetrics(metricFamilies, &MetricsState{})
+	assert.NoError(t, err)
+	assert.Equal(t, 64, updated.MaxConcurrency)
+
+	// The maximum concurrency is left as it was when it's missing.
+	updated, err = p.promToPodMetrics(map[string]*dto.MetricFamily{}, updated)
+	assert.EqualError(t, err, "metric family \"max_num_seqs\" not found")
+	assert.Equal(t, 64, updated.MaxConcurrency)
+}
+
+func TestPromToPodMetricsTriton(t *testing.T) {
+	exposition := `# HELP nv_trt_llm_request_metrics TRT LLM request metrics
+# TYPE nv_trt_llm_request_metrics gauge
+nv_trt_llm_request_metrics{model="llama",request_type="waiting",version="1"} 3
+nv_trt_llm_request_metrics{model="llama",request_type="max",version="1"} 64
+nv_trt_llm_request_metrics{model="mistral",request_type="waiting",version="1"} 2
+nv_trt_llm_request_metrics{model="mistral",request_type="max",version="1"} 32
+# HELP nv_trt_llm_kv_cache_block_metrics TRT LLM KV cache block metrics
+# TYPE nv_trt_llm_kv_cache_block_metrics gauge
+nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type="used",model="llama",version="1"} 300
+nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type="free",model="llama",version="1"} 700
+nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type="max",model="llama",version="1"} 1000
+nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type="used",model="mistral",version="1"} 100
+nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type="free",model="mistral",version="1"} 900
+nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type="max",model="mistral",version="1"} 1000
+`
+	metricFamilies, err := (&expfmt.TextParser{}).TextToMetricFamilies(strings.NewReader(exposition))
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	server, ok := LookupModelServer(ModelServerTriton)
+	if !ok {
+		t.Fatalf("Model server %q is not registered", ModelServerTriton)
+	}
+	mapping, err := server.NewMetricMapping()
+	if err != nil {
+		t.Fatalf("Unexpected mapping error: %v", err)
+	}
+	p := &PodMetricsClientImpl{MetricMapping: mapping}
+
+	updated, err := p.promToPodMetrics(metricFamilies, &MetricsState{})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, updated.WaitingQueueSize)
+	assert.InDelta(t, 0.2, updated.KVCacheUsagePercent, 1e-9)
+	assert.Equal(t, 1600, updated.KVCacheFreeBlocks)
+	assert.Equal(t, 96, updated.MaxConcurrency)
+
+	// The utilization is left as it was when the total number of blocks is zero.
+	delete(metricFamilies, "nv_trt_llm_request_metrics")
+	for _, m := range metricFamilies["nv_trt_llm_kv_cache_block_metrics"].GetMetric() {
+		m.GetGauge().Value = proto.Float64(0)
+	}
+	updated, err = p.promToPodMetrics(metricFamilies, updated)
+	assert.Error(t, err)
+	assert.Equal(t, 5, updated.WaitingQueueSize)
+	assert.InDelta(t, 0.2, updated.KVCacheUsagePercent, 1e-9)
+}
+
 // TestFetchMetrics is a basic integration test. It assumes
 // there's no server running on the specified port.
 func TestFetchMetrics(t *testing.T) {
@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ModelServerVLLM is the name of the metrics of vLLM, the default model server.
+	ModelServerVLLM = "vllm"
+	// ModelServerTriton is the name of the metrics of the TensorRT-LLM backend of Triton.
+	ModelServerTriton = "triton-tensorrt-llm"
+	// ModelServerSGLang is the name of the metrics of SGLang.
+	ModelServerSGLang = "sglang"
+	// ModelServerLlamaCpp is the name of the metrics of the llama.cpp server, started with --metrics.
+	ModelServerLlamaCpp = "llamacpp"
+)
+
+// ModelServerLabel is the pod label giving the model server of a pod, in the pools mixing model
+// servers. The pods without it run the model server given by the modelServerMetrics flag.
+const ModelServerLabel = "inference.networking.x-k8s.io/model-server"
+
+// ModelServerMetrics holds the specs of the metrics of a kind of model server, in the format of the
+// metric flags, e.g. "sum(metric_name{label1=value1})". The empty specs leave the metrics unscraped.
+type ModelServerMetrics struct {
+	TotalQueuedRequests  string `json:"totalQueuedRequests,omitempty"`
+	KVCacheUtilization   string `json:"kvCacheUtilization,omitempty"`
+	LoraRequestInfo      string `json:"loraRequestInfo,omitempty"`
+	KVCacheFreeBlocks    string `json:"kvCacheFreeBlocks,omitempty"`
+	KVCacheFragmentation string `json:"kvCacheFragmentation,omitempty"`
+	MaxConcurrency       string `json:"maxConcurrency,omitempty"`
+}
+
+// ModelServersConfig is the content of the file declaring the metrics of more model servers, e.g.
+// mounted from a ConfigMap:
+//
+//	modelServers:
+//	  tgi:
+//	    totalQueuedRequests: tgi_queue_size
+type ModelServersConfig struct {
+	ModelServers map[string]ModelServerMetrics `json:"modelServers"`
+}
+
+// LoadModelServersConfig parses the given YAML or JSON ModelServersConfig, and checks the metric
+// specs of its model servers.
+func LoadModelServersConfig(data []byte) (*ModelServersConfig, error) {
+	config := &ModelServersConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse model servers config: %w", err)
+	}
+	for name, metrics := range config.ModelServers {
+		if name == "" {
+			return nil, fmt.Errorf("model server name is required")
+		}
+		if metrics.TotalQueuedRequests == "" {
+			return nil, fmt.Errorf("model server '%s' has no queue metric", name)
+		}
+		if _, err := metrics.NewMetricMapping(); err != nil {
+			return nil, fmt.Errorf("model server '%s': %w", name, err)
+		}
+	}
+	return config, nil
+}
+
+// NewMetricMapping creates the MetricMapping of the model server.
+func (m ModelServerMetrics) NewMetricMapping() (*MetricMapping, error) {
+	mapping, err := NewMetricMapping(m.TotalQueuedRequests, m.KVCacheUtilization, m.LoraRequestInfo)
+	if err != nil {
+		return nil, err
+	}
+	if err := mapping.SetKVCacheBlockMetrics(m.KVCacheFreeBlocks, m.KVCacheFragmentation); err != nil {
+		return nil, err
+	}
+	if err := mapping.SetMaxConcurrencyMetric(m.MaxConcurrency); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+var modelServers = struct {
+	sync.RWMutex
+	metrics map[string]ModelServerMetrics
+}{
+	metrics: map[string]ModelServerMetrics{
+		ModelServerVLLM: {
+			TotalQueuedRequests: "vllm:num_requests_waiting",
+			KVCacheUtilization:  "vllm:gpu_cache_usage_perc",
+			LoraRequestInfo:     "vllm:lora_requests_info",
+		},
+		// The TensorRT-LLM backend reports its requests and KV cache blocks per model, which are
+		// summed over the models of the server. The KV cache utilization is computed from the used
+		// and total blocks, as the fraction block type is only reported by the recent versions. Note the nv_inference_queue_duration_us metric of
+		// Triton is the cumulative time spent queued by the requests, not the number of requests
+		// queued, so it isn't used.
+		ModelServerTriton: {
+			TotalQueuedRequests: "sum(nv_trt_llm_request_metrics{request_type=waiting})",
+			KVCacheUtilization: "sum(nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type=used})/" +
+				"sum(nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type=max})",
+			KVCacheFreeBlocks: "sum(nv_trt_llm_kv_cache_block_metrics{kv_cache_block_type=free})",
+			MaxConcurrency:    "sum(nv_trt_llm_request_metrics{request_type=max})",
+		},
+		ModelServerSGLang: {
+			TotalQueuedRequests: "sglang:num_queue_reqs",
+			KVCacheUtilization:  "sglang:token_usage",
+		},
+		ModelServerLlamaCpp: {
+			TotalQueuedRequests: "llamacpp:requests_deferred",
+			KVCacheUtilization:  "llamacpp:kv_cache_usage_ratio",
+		},
+	},
+}
+
+// RegisterModelServer registers the metrics of a kind of model server under the given name, so it
+// can be selected by the modelServerMetrics flag. It's meant to be called from an init function, and
+// panics if the name is empty or already registered.
+func RegisterModelServer(name string, metrics ModelServerMetrics) {
+	if name == "" {
+		panic("model server name is required")
+	}
+	modelServers.Lock()
+	defer modelServers.Unlock()
+	if _, ok := modelServers.metrics[name]; ok {
+		panic(fmt.Sprintf("model server '%s' is already registered", name))
+	}
+	modelServers.metrics[name] = metrics
+}
+
+// LookupModelServer returns the metrics of the model server registered under the given name, if any.
+func LookupModelServer(name string) (ModelServerMetrics, bool) {
+	modelServers.RLock()
+	defer modelServers.RUnlock()
+	metrics, ok := modelServers.metrics[name]
+	return metrics, ok
+}
+
+// RegisteredModelServers returns the sorted names of the registered model servers.
+func RegisteredModelServers() []string {
+	modelServers.RLock()
+	defer modelServers.RUnlock()
+	names := make([]string, 0, len(modelServers.metrics))
+	for name := range modelServers.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
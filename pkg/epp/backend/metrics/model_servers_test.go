@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadModelServersConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]ModelServerMetrics
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: `
+modelServers:
+  tgi:
+    totalQueuedRequests: tgi_queue_size
+    kvCacheUtilization: sum(tgi_kv_used)/sum(tgi_kv_total)
+`,
+			want: map[string]ModelServerMetrics{
+				"tgi": {TotalQueuedRequests: "tgi_queue_size", KVCacheUtilization: "sum(tgi_kv_used)/sum(tgi_kv_total)"},
+			},
+		},
+		{
+			name:    "unknown field",
+			data:    "modelServers:\n  tgi:\n    queue: tgi_queue_size\n",
+			wantErr: true,
+		},
+		{
+			name:    "no queue metric",
+			data:    "modelServers:\n  tgi:\n    kvCacheUtilization: tgi_kv\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid metric",
+			data:    "modelServers:\n  tgi:\n    totalQueuedRequests: tgi_queue_size{model}\n",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := LoadModelServersConfig([]byte(test.data))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Unexpected error: %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(test.want, got.ModelServers); diff != "" {
+				t.Errorf("Unexpected model servers (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestModelServerMappings(t *testing.T) {
+	parse := func(exposition string) map[string]*dto.MetricFamily {
+		metricFamilies, err := (&expfmt.TextParser{}).TextToMetricFamilies(strings.NewReader(exposition))
+		if err != nil {
+			t.Fatalf("Unexpected parse error: %v", err)
+		}
+		return metricFamilies
+	}
+	mapping := func(name string) *MetricMapping {
+		server, ok := LookupModelServer(name)
+		if !ok {
+			t.Fatalf("Model server %q is not registered", name)
+		}
+		mapping, err := server.NewMetricMapping()
+		if err != nil {
+			t.Fatalf("Unexpected mapping error: %v", err)
+		}
+		return mapping
+	}
+
+	sglang := parse(`# TYPE sglang:num_queue_reqs gauge
+sglang:num_queue_reqs{model_name="llama"} 4
+# TYPE sglang:token_usage gauge
+sglang:token_usage{model_name="llama"} 0.25
+`)
+	updated, err := (&PodMetricsClientImpl{MetricMapping: mapping(ModelServerSGLang)}).promToPodMetrics(sglang, &MetricsState{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, updated.WaitingQueueSize)
+	assert.InDelta(t, 0.25, updated.KVCacheUsagePercent, 1e-9)
+
+	llamacpp := parse(`# TYPE llamacpp:requests_deferred gauge
+llamacpp:requests_deferred 2
+# TYPE llamacpp:kv_cache_usage_ratio gauge
+llamacpp:kv_cache_usage_ratio 0.5
+`)
+	updated, err = (&PodMetricsClientImpl{MetricMapping: mapping(ModelServerLlamaCpp)}).promToPodMetrics(llamacpp, &MetricsState{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.WaitingQueueSize)
+	assert.InDelta(t, 0.5, updated.KVCacheUsagePercent, 1e-9)
+
+	// A pod of an unknown model server is scraped for the first queue metric of the mappings it exposes.
+	client := &PodMetricsClientImpl{
+		MetricMapping: mapping(ModelServerVLLM),
+		ModelServerMappings: map[string]*MetricMapping{
+			ModelServerSGLang:   mapping(ModelServerSGLang),
+			ModelServerLlamaCpp: mapping(ModelServerLlamaCpp),
+		},
+	}
+	updated, err = client.promToQueueDepth(sglang, &MetricsState{KVCacheUsagePercent: 0.7})
+	assert.NoError(t, err)
+	assert.True(t, updated.QueueDepthOnly)
+	assert.Equal(t, 4, updated.WaitingQueueSize)
+
+	updated, err = client.promToQueueDepth(map[string]*dto.MetricFamily{}, updated)
+	assert.EqualError(t, err, "no known queue metric found")
+	assert.True(t, updated.QueueDepthOnly)
+	assert.Equal(t, 4, updated.WaitingQueueSize)
+
+	// The metrics aren't QueueDepthOnly anymore once the model server is known.
+	updated, err = (&PodMetricsClientImpl{MetricMapping: mapping(ModelServerSGLang)}).promToPodMetrics(sglang, updated)
+	assert.NoError(t, err)
+	assert.False(t, updated.QueueDepthOnly)
+}
@@ -29,24 +29,52 @@ import (
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	podutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/pod"
 )
 
 const (
 	fetchMetricsTimeout = 5 * time.Second
+	// adapterLoadGracePeriod is how long an adapter recorded as loaded is kept in the metrics of the
+	// pod until the scraped metrics report it. Model servers report the adapters of the running and
+	// waiting requests, so a freshly loaded adapter shows up only once it serves requests.
+	adapterLoadGracePeriod = 30 * time.Second
 )
 
 type podMetrics struct {
-	pod      atomic.Pointer[backend.Pod]
-	metrics  atomic.Pointer[MetricsState]
+	pod     atomic.Pointer[backend.Pod]
+	metrics atomic.Pointer[MetricsState]
+	history *MetricsHistory
+	// inFlight is the live count of the requests routed to the pod.
+	inFlight *RequestsInFlight
 	pmc      PodMetricsClient
 	ds       Datastore
-	interval time.Duration
+	// interval is only accessed by the refresh loop once it's started.
+	interval refreshInterval
+	// onChange is called after the pod or the metrics are replaced, nil if the datastore doesn't
+	// need to know.
+	onChange func(PodMetrics)
 
 	startOnce sync.Once // ensures the refresh loop goroutine is started only once
 	stopOnce  sync.Once // ensures the done channel is closed only once
 	done      chan struct{}
 
 	logger logr.Logger
+
+	// podMu serializes the updates of pod, so the cordon state is not lost when the pod object and
+	// the admin cordon are updated concurrently.
+	podMu                sync.Mutex
+	cordonedByAnnotation bool
+	cordonedByAdmin      bool
+
+	// metricsMu serializes the updates of metrics, so an adapter recorded as loaded is not lost when
+	// it's recorded concurrently with a metrics refresh.
+	metricsMu      sync.Mutex
+	loadedAdapters map[string]time.Time // adapter name -> expiry of the optimistic record
+
+	// pushedAt is the time of the last metrics pushed by the pod, in Unix nanoseconds, 0 if it never
+	// pushed. The metrics aren't scraped while the pushed ones are fresher than pushedMetricsTTL.
+	pushedAt         atomic.Int64
+	pushedMetricsTTL time.Duration
 }
 
 type PodMetricsClient interface {
@@ -65,8 +93,101 @@ func (pm *podMetrics) GetMetrics() *MetricsState {
 	return pm.metrics.Load()
 }
 
-func (pm *podMetrics) UpdatePod(pod *corev1.Pod) {
-	pm.pod.Store(toInternalPod(pod))
+func (pm *podMetrics) GetMetricsHistory() *MetricsHistory {
+	return pm.history
+}
+
+func (pm *podMetrics) GetRequestsInFlight() *RequestsInFlight {
+	return pm.inFlight
+}
+
+func (pm *podMetrics) UpdatePod(in *corev1.Pod) {
+	pm.podMu.Lock()
+	defer pm.podMu.Unlock()
+	pm.cordonedByAnnotation = podutil.IsPodCordoned(in)
+	pod := toInternalPod(in)
+	pod.Cordoned = pm.cordonedByAnnotation || pm.cordonedByAdmin
+	pm.storePod(pod)
+}
+
+// SetCordoned cordons or uncordons the pod, independently of the cordon annotation of the pod.
+func (pm *podMetrics) SetCordoned(cordoned bool) {
+	pm.podMu.Lock()
+	defer pm.podMu.Unlock()
+	pm.cordonedByAdmin = cordoned
+	pod := pm.GetPod().Clone()
+	pod.Cordoned = pm.cordonedByAnnotation || pm.cordonedByAdmin
+	pm.storePod(pod)
+}
+
+// RecordAdapterLoaded optimistically adds the given adapter to the active models of the pod,
+// without waiting for the next metrics refresh. The adapter is kept in the active models across
+// refreshes until the scraped metrics report it, or until adapterLoadGracePeriod elapses.
+func (pm *podMetrics) RecordAdapterLoaded(adapter string) {
+	pm.metricsMu.Lock()
+	defer pm.metricsMu.Unlock()
+	if pm.loadedAdapters == nil {
+		pm.loadedAdapters = make(map[string]time.Time)
+	}
+	pm.loadedAdapters[adapter] = time.Now().Add(adapterLoadGracePeriod)
+	pm.storeMetrics(pm.withLoadedAdapters(pm.GetMetrics().Clone()))
+}
+
+// PushMetrics sets the metrics pushed by the pod, and stops scraping them until the pushed metrics
+// are older than the pushed metrics TTL of the factory, unless they're piggybacked on a response.
+func (pm *podMetrics) PushMetrics(pushed *PushedMetrics) {
+	now := time.Now()
+	pm.metricsMu.Lock()
+	updated := pm.GetMetrics().Clone()
+	pushed.applyTo(updated, now)
+	pm.storeMetrics(pm.withLoadedAdapters(updated))
+	if !pushed.Piggybacked {
+		pm.pushedAt.Store(now.UnixNano())
+	}
+	pm.metricsMu.Unlock()
+	pm.logger.V(logutil.TRACE).Info("Pushed metrics", "updated", updated)
+	pm.history.Add(updated)
+}
+
+// pushedRecently returns whether the pod pushed its metrics within the pushed metrics TTL.
+func (pm *podMetrics) pushedRecently() bool {
+	pushedAt := pm.pushedAt.Load()
+	return pushedAt != 0 && time.Since(time.Unix(0, pushedAt)) < pm.pushedMetricsTTL
+}
+
+func (pm *podMetrics) storePod(pod *backend.Pod) {
+	pm.pod.Store(pod)
+	if pm.onChange != nil {
+		pm.onChange(pm)
+	}
+}
+
+func (pm *podMetrics) storeMetrics(metrics *MetricsState) {
+	pm.metrics.Store(metrics)
+	if pm.onChange != nil {
+		pm.onChange(pm)
+	}
+}
+
+// withLoadedAdapters adds the adapters recorded as loaded to the active models of the given
+// metrics, and forgets the ones that are reported by the metrics or expired. It must be called
+// with metricsMu held.
+func (pm *podMetrics) withLoadedAdapters(metrics *MetricsState) *MetricsState {
+	now := time.Now()
+	recorded := map[string]int{}
+	for adapter, expiry := range pm.loadedAdapters {
+		if _, ok := metrics.ActiveModels[adapter]; ok || now.After(expiry) {
+			delete(pm.loadedAdapters, adapter)
+			continue
+		}
+		if metrics.ActiveModels == nil {
+			metrics.ActiveModels = make(map[string]int)
+		}
+		metrics.ActiveModels[adapter] = 0
+		recorded[adapter] = 0
+	}
+	metrics.markLoaded(now, recorded)
+	return metrics
 }
 
 func toInternalPod(pod *corev1.Pod) *backend.Pod {
@@ -79,8 +200,12 @@ func toInternalPod(pod *corev1.Pod) *backend.Pod {
 			Name:      pod.Name,
 			Namespace: pod.Namespace,
 		},
-		Address: pod.Status.PodIP,
-		Labels:  labels,
+		Address:        pod.Status.PodIP,
+		HostIP:         pod.Status.HostIP,
+		Labels:         labels,
+		Role:           backend.PodRoleFromLabels(labels),
+		MaxConcurrency: podutil.MaxConcurrency(pod),
+		Port:           podutil.TargetPort(pod),
 	}
 }
 
@@ -90,18 +215,22 @@ func (pm *podMetrics) startRefreshLoop(ctx context.Context) {
 	pm.startOnce.Do(func() {
 		go func() {
 			pm.logger.V(logutil.DEFAULT).Info("Starting refresher", "pod", pm.GetPod())
-			ticker := time.NewTicker(pm.interval)
-			defer ticker.Stop()
+			timer := time.NewTimer(pm.interval.current)
+			defer timer.Stop()
 			for {
 				select {
 				case <-pm.done:
 					return
 				case <-ctx.Done():
 					return
-				case <-ticker.C: // refresh metrics periodically
+				case <-timer.C: // refresh metrics periodically
+					previous := pm.GetMetrics()
 					if err := pm.refreshMetrics(); err != nil {
 						pm.logger.V(logutil.TRACE).Error(err, "Failed to refresh metrics", "pod", pm.GetPod())
+						timer.Reset(pm.interval.current)
+						continue
 					}
+					timer.Reset(pm.interval.next(previous, pm.GetMetrics()))
 				}
 			}
 		}()
@@ -109,6 +238,9 @@ func (pm *podMetrics) startRefreshLoop(ctx context.Context) {
 }
 
 func (pm *podMetrics) refreshMetrics() error {
+	if pm.pushedRecently() {
+		return nil
+	}
 	pool, err := pm.ds.PoolGet()
 	if err != nil {
 		// No inference pool or not initialize.
@@ -116,7 +248,8 @@ func (pm *podMetrics) refreshMetrics() error {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), fetchMetricsTimeout)
 	defer cancel()
-	updated, err := pm.pmc.FetchMetrics(ctx, pm.GetPod(), pm.GetMetrics(), pool.Spec.TargetPortNumber)
+	pod := pm.GetPod()
+	updated, err := pm.pmc.FetchMetrics(ctx, pod, pm.GetMetrics(), pod.TargetPort(pool.Spec.TargetPortNumber))
 	if err != nil {
 		pm.logger.V(logutil.TRACE).Info("Failed to refreshed metrics:", "err", err)
 	}
@@ -129,9 +262,21 @@ func (pm *podMetrics) refreshMetrics() error {
 	// this case, the updated metrics object will have partial updates. A partial update is
 	// considered better than no updates.
 	if updated != nil {
+		if updated.QueueDepthOnly && !pm.GetMetrics().QueueDepthOnly {
+			pm.logger.V(logutil.DEFAULT).Info("No metrics known for the model server of the pod, scheduling it by its queue size only",
+				"modelServer", pod.Labels[ModelServerLabel])
+		}
 		updated.UpdateTime = time.Now()
+		pm.metricsMu.Lock()
+		if pm.pushedRecently() {
+			// The pod pushed fresher metrics during the scrape.
+			pm.metricsMu.Unlock()
+			return nil
+		}
 		pm.logger.V(logutil.TRACE).Info("Refreshed metrics", "updated", updated)
-		pm.metrics.Store(updated)
+		pm.storeMetrics(pm.withLoadedAdapters(updated))
+		pm.metricsMu.Unlock()
+		pm.history.Add(updated)
 	}
 
 	return nil
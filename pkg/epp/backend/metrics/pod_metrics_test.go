@@ -74,6 +74,8 @@ func TestMetricsRefresh(t *testing.T) {
 		assert.True(collect, cmp.Equal(pm.GetMetrics(), initial, cmpopts.IgnoreFields(MetricsState{}, "UpdateTime")))
 	}
 	assert.EventuallyWithT(t, condition, time.Second, time.Millisecond)
+	// The refreshed metrics are kept in the history as well.
+	assert.NotEmpty(t, pm.GetMetricsHistory().Samples())
 
 	// Stop the loop, and simulate metric update again, this time the PodMetrics won't get the
 	// new update.
@@ -84,6 +86,69 @@ func TestMetricsRefresh(t *testing.T) {
 	assert.EventuallyWithT(t, condition, time.Second, time.Millisecond)
 }
 
+func TestRecordAdapterLoaded(t *testing.T) {
+	ctx := context.Background()
+	pmc := &FakePodMetricsClient{}
+	pmf := NewPodMetricsFactory(pmc, time.Millisecond)
+	pm := pmf.NewPodMetrics(ctx, pod1, &fakeDataStore{})
+	defer pm.StopRefreshLoop()
+
+	namespacedName := types.NamespacedName{Name: pod1.Name, Namespace: pod1.Namespace}
+	pmc.SetRes(map[types.NamespacedName]*MetricsState{namespacedName: initial})
+	assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+		assert.Equal(collect, initial.ActiveModels, pm.GetMetrics().ActiveModels)
+	}, time.Second, time.Millisecond)
+
+	// The loaded adapter is reflected right away, and survives the following refreshes although
+	// the model server doesn't report it yet.
+	pm.RecordAdapterLoaded("baz")
+	wantActiveModels := map[string]int{"foo": 1, "bar": 1, "baz": 0}
+	assert.Equal(t, wantActiveModels, pm.GetMetrics().ActiveModels)
+	time.Sleep(pmf.refreshMetricsInterval * 10)
+	assert.Equal(t, wantActiveModels, pm.GetMetrics().ActiveModels)
+	assert.Equal(t, map[string]int{"foo": 1, "bar": 1}, initial.ActiveModels, "scraped metrics must not be mutated")
+
+	// Once the model server reports the adapter, the scraped metrics are used as is.
+	reported := initial.Clone()
+	reported.ActiveModels["baz"] = 1
+	pmc.SetRes(map[types.NamespacedName]*MetricsState{namespacedName: reported})
+	assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+		assert.Equal(collect, reported.ActiveModels, pm.GetMetrics().ActiveModels)
+	}, time.Second, time.Millisecond)
+}
+
+func TestPushMetrics(t *testing.T) {
+	ctx := context.Background()
+	pmc := &FakePodMetricsClient{}
+	pmf := NewPodMetricsFactory(pmc, time.Millisecond)
+	pmf.SetPushedMetricsTTL(100 * time.Millisecond)
+	pm := pmf.NewPodMetrics(ctx, pod1, &fakeDataStore{})
+	defer pm.StopRefreshLoop()
+
+	namespacedName := types.NamespacedName{Name: pod1.Name, Namespace: pod1.Namespace}
+	pmc.SetRes(map[types.NamespacedName]*MetricsState{namespacedName: initial})
+	assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+		assert.Equal(collect, initial.KVCacheUsagePercent, pm.GetMetrics().KVCacheUsagePercent)
+	}, time.Second, time.Millisecond)
+
+	// The pushed metrics are reflected right away, and the fields that aren't pushed are kept.
+	waiting, kvCache := 7, 0.5
+	pm.PushMetrics(&PushedMetrics{WaitingQueueSize: &waiting, KVCacheUsagePercent: &kvCache})
+	got := pm.GetMetrics()
+	assert.Equal(t, 7, got.WaitingQueueSize)
+	assert.Equal(t, 0.5, got.KVCacheUsagePercent)
+	assert.Equal(t, initial.ActiveModels, got.ActiveModels)
+
+	// The metrics aren't scraped while the pushed ones are fresh.
+	time.Sleep(pmf.refreshMetricsInterval * 10)
+	assert.Equal(t, 7, pm.GetMetrics().WaitingQueueSize)
+
+	// Once the pod stops pushing, its metrics are scraped again.
+	assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+		assert.Equal(collect, initial.WaitingQueueSize, pm.GetMetrics().WaitingQueueSize)
+	}, time.Second, time.Millisecond)
+}
+
 type fakeDataStore struct{}
 
 func (f *fakeDataStore) PoolGet() (*v1alpha2.InferencePool, error) {
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPushedMetricsTTL is how long the metrics pushed by a pod are considered fresh, during which
+// the metrics of the pod are not scraped.
+const DefaultPushedMetricsTTL = 5 * time.Second
+
+// PushedMetrics are the metrics pushed by a model server, or a sidecar of it, so they're reflected
+// right away instead of at the next scrape. The fields that aren't set keep their last scraped or
+// pushed values.
+type PushedMetrics struct {
+	WaitingQueueSize *int `json:"waitingQueueSize,omitempty"`
+	RunningQueueSize *int `json:"runningQueueSize,omitempty"`
+	// KVCacheUsagePercent is the fraction [0, 1] of the KV cache in use.
+	KVCacheUsagePercent *float64 `json:"kvCacheUsagePercent,omitempty"`
+	KVCacheFreeBlocks   *int     `json:"kvCacheFreeBlocks,omitempty"`
+	MaxConcurrency      *int     `json:"maxConcurrency,omitempty"`
+	// ActiveModels and WaitingModels are the models, including LoRA adapters, of the running and
+	// waiting requests. An empty list clears them, while a null or missing list keeps them.
+	ActiveModels  []string `json:"activeModels"`
+	WaitingModels []string `json:"waitingModels"`
+	// Piggybacked is set for the metrics reported in the responses of the pod, which are partial
+	// and irregular, so the metrics of the pod keep being scraped.
+	Piggybacked bool `json:"-"`
+}
+
+// Validate returns an error if the pushed metrics are out of range.
+func (p *PushedMetrics) Validate() error {
+	var errs []error
+	for _, field := range []struct {
+		name  string
+		value *int
+	}{
+		{"waitingQueueSize", p.WaitingQueueSize},
+		{"runningQueueSize", p.RunningQueueSize},
+		{"kvCacheFreeBlocks", p.KVCacheFreeBlocks},
+		{"maxConcurrency", p.MaxConcurrency},
+	} {
+		if field.value != nil && *field.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %d", field.name, *field.value))
+		}
+	}
+	if p.KVCacheUsagePercent != nil && (*p.KVCacheUsagePercent < 0 || *p.KVCacheUsagePercent > 1) {
+		errs = append(errs, fmt.Errorf("kvCacheUsagePercent must be within [0, 1], got %v", *p.KVCacheUsagePercent))
+	}
+	return errors.Join(errs...)
+}
+
+// applyTo sets the pushed metrics in the given metrics.
+func (p *PushedMetrics) applyTo(metrics *MetricsState, now time.Time) {
+	if p.WaitingQueueSize != nil {
+		metrics.WaitingQueueSize = *p.WaitingQueueSize
+	}
+	if p.RunningQueueSize != nil {
+		metrics.RunningQueueSize = *p.RunningQueueSize
+	}
+	if p.KVCacheUsagePercent != nil {
+		metrics.KVCacheUsagePercent = *p.KVCacheUsagePercent
+		// The KV cache utilization is known, even if the model server has no known metrics.
+		metrics.QueueDepthOnly = false
+	}
+	if p.KVCacheFreeBlocks != nil {
+		metrics.KVCacheFreeBlocks = *p.KVCacheFreeBlocks
+		metrics.KVCacheBlocksUpdateTime = now
+	}
+	if p.MaxConcurrency != nil {
+		metrics.MaxConcurrency = *p.MaxConcurrency
+	}
+	if p.ActiveModels != nil {
+		metrics.ActiveModels = modelSet(p.ActiveModels)
+		metrics.markLoaded(now, metrics.ActiveModels)
+	}
+	if p.WaitingModels != nil {
+		metrics.WaitingModels = modelSet(p.WaitingModels)
+	}
+	metrics.UpdateTime = now
+}
+
+func modelSet(models []string) map[string]int {
+	set := make(map[string]int, len(models))
+	for _, model := range models {
+		set[model] = 0
+	}
+	return set
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushedMetricsValidate(t *testing.T) {
+	negative, tooHigh := -1, 1.5
+	assert.NoError(t, (&PushedMetrics{}).Validate())
+	err := (&PushedMetrics{RunningQueueSize: &negative, KVCacheUsagePercent: &tooHigh}).Validate()
+	assert.ErrorContains(t, err, "runningQueueSize must not be negative")
+	assert.ErrorContains(t, err, "kvCacheUsagePercent must be within [0, 1]")
+}
+
+func TestPushedMetricsApplyTo(t *testing.T) {
+	now := time.Now()
+	freeBlocks := 100
+	metrics := &MetricsState{
+		WaitingQueueSize: 3,
+		ActiveModels:     map[string]int{"foo": 1},
+		WaitingModels:    map[string]int{"bar": 1},
+		QueueDepthOnly:   true,
+	}
+	kvCache := 0.3
+	(&PushedMetrics{
+		KVCacheUsagePercent: &kvCache,
+		KVCacheFreeBlocks:   &freeBlocks,
+		ActiveModels:        []string{"baz"},
+		WaitingModels:       []string{},
+	}).applyTo(metrics, now)
+
+	assert.Equal(t, &MetricsState{
+		WaitingQueueSize:        3,
+		KVCacheUsagePercent:     0.3,
+		KVCacheFreeBlocks:       100,
+		KVCacheBlocksUpdateTime: now,
+		ActiveModels:            map[string]int{"baz": 0},
+		LoadedModels:            map[string]time.Time{"baz": now},
+		WaitingModels:           map[string]int{},
+		UpdateTime:              now,
+	}, metrics)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math"
+	"time"
+)
+
+// kvCacheUsageChangeThreshold is the change of the KV cache utilization of a pod between two
+// refreshes past which its metrics are considered changing.
+const kvCacheUsageChangeThreshold = 0.05
+
+// refreshInterval adapts the interval between the refreshes of the metrics of a pod to its load:
+// it's halved, down to min, when the queue or the KV cache utilization of the pod changed since the
+// previous refresh, and doubled, up to max, when the pod is idle, i.e. its queue is empty and its
+// metrics didn't change. Otherwise it's kept as it is. The interval is fixed if min is zero.
+type refreshInterval struct {
+	current  time.Duration
+	min, max time.Duration
+}
+
+// next returns the interval until the refresh following the one that updated the metrics from
+// previous to updated.
+func (r *refreshInterval) next(previous, updated *MetricsState) time.Duration {
+	if r.min == 0 || previous == nil || updated == nil {
+		return r.current
+	}
+	changed := updated.WaitingQueueSize != previous.WaitingQueueSize ||
+		math.Abs(updated.KVCacheUsagePercent-previous.KVCacheUsagePercent) >= kvCacheUsageChangeThreshold
+	switch {
+	case changed:
+		r.current = max(r.current/2, r.min)
+	case updated.WaitingQueueSize == 0:
+		r.current = min(r.current*2, r.max)
+	}
+	return r.current
+}
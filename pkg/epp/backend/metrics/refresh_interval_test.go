@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshIntervalNext(t *testing.T) {
+	idle := &MetricsState{}
+	busy := &MetricsState{WaitingQueueSize: 5, KVCacheUsagePercent: 0.5}
+	tests := []struct {
+		name     string
+		interval refreshInterval
+		previous *MetricsState
+		updated  *MetricsState
+		want     time.Duration
+	}{
+		{
+			name:     "fixed interval",
+			interval: refreshInterval{current: 50 * time.Millisecond},
+			previous: idle,
+			updated:  busy,
+			want:     50 * time.Millisecond,
+		},
+		{
+			name:     "queue changed",
+			interval: refreshInterval{current: 40 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: idle,
+			updated:  busy,
+			want:     20 * time.Millisecond,
+		},
+		{
+			name:     "KV cache changed",
+			interval: refreshInterval{current: 40 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: busy,
+			updated:  &MetricsState{WaitingQueueSize: 5, KVCacheUsagePercent: 0.6},
+			want:     20 * time.Millisecond,
+		},
+		{
+			name:     "KV cache changed a little",
+			interval: refreshInterval{current: 40 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: busy,
+			updated:  &MetricsState{WaitingQueueSize: 5, KVCacheUsagePercent: 0.51},
+			want:     40 * time.Millisecond,
+		},
+		{
+			name:     "changed at min",
+			interval: refreshInterval{current: 15 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: idle,
+			updated:  busy,
+			want:     10 * time.Millisecond,
+		},
+		{
+			name:     "idle",
+			interval: refreshInterval{current: 40 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: idle,
+			updated:  idle,
+			want:     80 * time.Millisecond,
+		},
+		{
+			name:     "idle at max",
+			interval: refreshInterval{current: 800 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: idle,
+			updated:  idle,
+			want:     time.Second,
+		},
+		{
+			name:     "steady load",
+			interval: refreshInterval{current: 40 * time.Millisecond, min: 10 * time.Millisecond, max: time.Second},
+			previous: busy,
+			updated:  busy,
+			want:     40 * time.Millisecond,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.interval.next(test.previous, test.updated); got != test.want {
+				t.Errorf("Unexpected interval: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRefreshIntervalBounds(t *testing.T) {
+	pmf := NewPodMetricsFactory(&FakePodMetricsClient{}, 50*time.Millisecond)
+	pmf.SetRefreshIntervalBounds(100*time.Millisecond, time.Second)
+	pm := pmf.NewPodMetrics(t.Context(), pod1, &fakeDataStore{}).(*podMetrics)
+	defer pm.StopRefreshLoop()
+	// The initial interval is within the bounds.
+	if pm.interval.current != 100*time.Millisecond {
+		t.Errorf("Unexpected initial interval: got %v, want %v", pm.interval.current, 100*time.Millisecond)
+	}
+}
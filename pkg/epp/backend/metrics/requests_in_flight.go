@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "sync/atomic"
+
+// RequestsInFlight counts the requests routed to a pod by the endpoint picker whose processing
+// didn't end yet, and their outstanding tokens. Unlike the scraped metrics, the counts are live and
+// include the requests the model server didn't report yet, e.g. the ones still in transit to it.
+type RequestsInFlight struct {
+	count  atomic.Int64
+	tokens atomic.Int64
+}
+
+// Inc counts a request routed to the pod.
+func (r *RequestsInFlight) Inc() {
+	r.count.Add(1)
+}
+
+// Dec uncounts a request that ended.
+func (r *RequestsInFlight) Dec() {
+	r.count.Add(-1)
+}
+
+// Count returns the number of requests in flight, 0 if r is nil.
+func (r *RequestsInFlight) Count() int {
+	if r == nil {
+		return 0
+	}
+	return int(r.count.Load())
+}
+
+// AddTokens adds the given number of tokens, negative to remove tokens, to the outstanding tokens.
+func (r *RequestsInFlight) AddTokens(tokens int) {
+	r.tokens.Add(int64(tokens))
+}
+
+// OutstandingTokens returns the estimated number of tokens the requests in flight have yet to
+// process, i.e. the prompt tokens of the requests without a response yet and the output tokens
+// yet to be generated, 0 if r is nil.
+func (r *RequestsInFlight) OutstandingTokens() int {
+	if r == nil {
+		return 0
+	}
+	return int(r.tokens.Load())
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync/atomic"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+)
+
+// snapshotGenerations is shared by all the datastores, so the snapshots of different datastores
+// never have the same generation.
+var snapshotGenerations atomic.Uint64
+
+// NextSnapshotGeneration returns a new snapshot generation, greater than all the previous ones.
+func NextSnapshotGeneration() uint64 {
+	return snapshotGenerations.Add(1)
+}
+
+// PodSnapshot is an immutable view of the pods of a datastore and of their metrics. It's shared
+// by all the readers that take a snapshot until the pods change, so neither the snapshot nor the
+// pods and metrics it refers to may be modified.
+type PodSnapshot struct {
+	// Generation identifies the snapshot. A datastore takes a new snapshot with a new generation
+	// only after a pod is added or removed, or the pod or the metrics of a pod are replaced.
+	Generation uint64
+	Pods       []PodState
+}
+
+// PodState is the state of a pod at the time a snapshot was taken.
+type PodState struct {
+	Pod     *backend.Pod
+	Metrics *MetricsState
+	// History is the live history of the metrics of the pod, or nil if it isn't kept.
+	History *MetricsHistory
+	// InFlight is the live count of the requests routed to the pod, or nil if they aren't counted.
+	InFlight *RequestsInFlight
+}
+
+// NewPodSnapshot takes a snapshot of the given pods with a new generation. The pods and metrics
+// are not copied, as the pod metrics replace them rather than updating them in place.
+func NewPodSnapshot(pods []PodMetrics) *PodSnapshot {
+	states := make([]PodState, 0, len(pods))
+	for _, pod := range pods {
+		states = append(states, PodState{Pod: pod.GetPod(), Metrics: pod.GetMetrics(), History: pod.GetMetricsHistory(), InFlight: pod.GetRequestsInFlight()})
+	}
+	return &PodSnapshot{Generation: NextSnapshotGeneration(), Pods: states}
+}
+
+// PodChangeNotifier is implemented by the datastores that cache a snapshot of their pods, or that
+// notify others of the changes of their pods. The pod metrics created for such a datastore call
+// PodChanged with themselves after replacing their pod or metrics.
+type PodChangeNotifier interface {
+	PodChanged(pm PodMetrics)
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 )
@@ -31,26 +32,59 @@ func NewPodMetricsFactory(pmc PodMetricsClient, refreshMetricsInterval time.Dura
 	return &PodMetricsFactory{
 		pmc:                    pmc,
 		refreshMetricsInterval: refreshMetricsInterval,
+		pushedMetricsTTL:       DefaultPushedMetricsTTL,
 	}
 }
 
 type PodMetricsFactory struct {
 	pmc                    PodMetricsClient
 	refreshMetricsInterval time.Duration
+	// minRefreshInterval and maxRefreshInterval bound the adaptive refresh interval of the pods, zero
+	// if the interval is fixed.
+	minRefreshInterval time.Duration
+	maxRefreshInterval time.Duration
+	// pushedMetricsTTL is how long the metrics pushed by the pods suspend their scraping.
+	pushedMetricsTTL time.Duration
+}
+
+// SetPushedMetricsTTL sets how long the metrics pushed by the pods created afterwards are considered
+// fresh. Their metrics are scraped again once their last push is older.
+func (f *PodMetricsFactory) SetPushedMetricsTTL(ttl time.Duration) {
+	f.pushedMetricsTTL = ttl
+}
+
+// SetRefreshIntervalBounds makes the metrics of the pods created afterwards refresh at an interval
+// adapting to the load of each pod, from the refresh interval of the factory and within the given
+// bounds: faster when their queue or KV cache utilization changes, and slower when they're idle.
+func (f *PodMetricsFactory) SetRefreshIntervalBounds(minInterval, maxInterval time.Duration) {
+	f.minRefreshInterval = minInterval
+	f.maxRefreshInterval = maxInterval
 }
 
 func (f *PodMetricsFactory) NewPodMetrics(parentCtx context.Context, in *corev1.Pod, ds Datastore) PodMetrics {
-	pod := toInternalPod(in)
 	pm := &podMetrics{
-		pmc:       f.pmc,
-		ds:        ds,
-		interval:  f.refreshMetricsInterval,
-		startOnce: sync.Once{},
-		stopOnce:  sync.Once{},
-		done:      make(chan struct{}),
-		logger:    log.FromContext(parentCtx).WithValues("pod", pod.NamespacedName),
+		pmc:      f.pmc,
+		ds:       ds,
+		history:  NewMetricsHistory(DefaultMetricsHistorySize),
+		inFlight: &RequestsInFlight{},
+		interval: refreshInterval{
+			current: f.refreshMetricsInterval,
+			min:     f.minRefreshInterval,
+			max:     f.maxRefreshInterval,
+		},
+		pushedMetricsTTL: f.pushedMetricsTTL,
+		startOnce:        sync.Once{},
+		stopOnce:         sync.Once{},
+		done:             make(chan struct{}),
+		logger:           log.FromContext(parentCtx).WithValues("pod", types.NamespacedName{Name: in.Name, Namespace: in.Namespace}),
+	}
+	if pm.interval.min != 0 {
+		pm.interval.current = min(max(pm.interval.current, pm.interval.min), pm.interval.max)
+	}
+	if notifier, ok := ds.(PodChangeNotifier); ok {
+		pm.onChange = notifier.PodChanged
 	}
-	pm.pod.Store(pod)
+	pm.UpdatePod(in)
 	pm.metrics.Store(newMetricsState())
 
 	pm.startRefreshLoop(parentCtx)
@@ -60,7 +94,19 @@ func (f *PodMetricsFactory) NewPodMetrics(parentCtx context.Context, in *corev1.
 type PodMetrics interface {
 	GetPod() *backend.Pod
 	GetMetrics() *MetricsState
+	// GetMetricsHistory returns the recent metrics of the pod, or nil if they aren't kept.
+	GetMetricsHistory() *MetricsHistory
+	// GetRequestsInFlight returns the live count of the requests routed to the pod, or nil if they
+	// aren't counted.
+	GetRequestsInFlight() *RequestsInFlight
 	UpdatePod(*corev1.Pod)
+	SetCordoned(bool)
+	// RecordAdapterLoaded reflects an adapter load in the metrics right away, so the requests
+	// for the adapter are routed to the pod without waiting for the next metrics refresh.
+	RecordAdapterLoaded(adapter string)
+	// PushMetrics sets the metrics pushed by the pod, which are used instead of scraped ones while
+	// they're fresh.
+	PushMetrics(pushed *PushedMetrics)
 	StopRefreshLoop()
 	String() string
 }
@@ -19,10 +19,68 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// RoleLabel is the pod label giving the role of a model server pod in the pool.
+const RoleLabel = "inference.networking.x-k8s.io/role"
+
+// PodRole is the part a model server pod plays in serving the requests, e.g. in disaggregated
+// prefill/decode or speculative decoding topologies.
+type PodRole string
+
+const (
+	// PodRoleGeneral pods serve whole requests. It's the role of the pods without a valid RoleLabel.
+	PodRoleGeneral PodRole = "general"
+	// PodRolePrefill pods compute the KV cache of the prompts, which is then transferred to decode pods.
+	PodRolePrefill PodRole = "prefill"
+	// PodRoleDecode pods generate the responses from the KV cache computed by prefill pods.
+	PodRoleDecode PodRole = "decode"
+	// PodRoleDraft pods serve the draft model proposing tokens in speculative decoding.
+	PodRoleDraft PodRole = "draft"
+)
+
+// PodRoleFromLabels returns the role given by the RoleLabel of the given pod labels, or
+// PodRoleGeneral if the label is missing or isn't a known role.
+func PodRoleFromLabels(labels map[string]string) PodRole {
+	switch role := PodRole(labels[RoleLabel]); role {
+	case PodRolePrefill, PodRoleDecode, PodRoleDraft:
+		return role
+	default:
+		return PodRoleGeneral
+	}
+}
+
 type Pod struct {
 	NamespacedName types.NamespacedName
 	Address        string
-	Labels         map[string]string
+	// HostIP is the address of the node of the pod, where node exporters (e.g. the GPU metrics
+	// exporter) are reached.
+	HostIP string
+	Labels map[string]string
+	// Role is derived from the RoleLabel of the pod. The zero value is equivalent to PodRoleGeneral.
+	Role PodRole
+	// Cordoned pods are excluded from scheduling new requests, while requests already being served
+	// by the pod are unaffected.
+	Cordoned bool
+	// MaxConcurrency is the maximum number of concurrent requests the pod is annotated to serve, zero
+	// if it isn't annotated.
+	MaxConcurrency int
+	// Port is the port the pod serves on, if it isn't the target port of the pool. Zero otherwise.
+	Port int32
+}
+
+// TargetPort returns the port of the pod, defaulting to the given target port of the pool.
+func (p *Pod) TargetPort(poolPort int32) int32 {
+	if p == nil || p.Port == 0 {
+		return poolPort
+	}
+	return p.Port
+}
+
+// GetRole returns the role of the pod, defaulting to PodRoleGeneral.
+func (p *Pod) GetRole() PodRole {
+	if p == nil || p.Role == "" {
+		return PodRoleGeneral
+	}
+	return p.Role
 }
 
 func (p *Pod) String() string {
@@ -45,7 +103,12 @@ func (p *Pod) Clone() *Pod {
 			Name:      p.NamespacedName.Name,
 			Namespace: p.NamespacedName.Namespace,
 		},
-		Address: p.Address,
-		Labels:  clonedLabels,
+		Address:        p.Address,
+		HostIP:         p.HostIP,
+		Labels:         clonedLabels,
+		Role:           p.Role,
+		Cordoned:       p.Cordoned,
+		MaxConcurrency: p.MaxConcurrency,
+		Port:           p.Port,
 	}
 }
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backoff advises the clients of the shed requests when to retry them, with a Retry-After
+// that grows with the saturation of the pool along a curve per priority. The curves of the lower
+// priorities are typically steeper, so the clients of the sheddable requests back off longer than
+// the clients of the critical ones, and the pool recovers for the higher priorities first.
+package backoff
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+)
+
+// SaturationDetector tells how saturated the pool is, from 0 to 1.
+type SaturationDetector interface {
+	Saturation(ctx context.Context) float64
+}
+
+// Backoff computes the Retry-After of the shed requests.
+type Backoff struct {
+	config   *Config
+	detector SaturationDetector
+}
+
+// NewBackoff creates a new Backoff over the saturation of the pods of the given datastore.
+func NewBackoff(config *Config, datastore saturationdetector.Datastore, logger logr.Logger) (*Backoff, error) {
+	detector, err := saturationdetector.NewDetector(config.SaturationDetector, datastore, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newBackoff(config, detector), nil
+}
+
+func newBackoff(config *Config, detector SaturationDetector) *Backoff {
+	return &Backoff{config: config, detector: detector}
+}
+
+// RetryAfter returns the delay after which a shed request of the given priority is advised to be
+// retried given the current saturation of the pool, and false if the priority has no curve.
+func (b *Backoff) RetryAfter(ctx context.Context, priority flowcontrol.Priority) (time.Duration, bool) {
+	curve, ok := b.config.Curves[priority]
+	if !ok {
+		return 0, false
+	}
+	return curve.At(b.detector.Saturation(ctx)), true
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
+)
+
+type fakeDetector struct {
+	saturation float64
+}
+
+func (d *fakeDetector) Saturation(_ context.Context) float64 {
+	return d.saturation
+}
+
+func TestRetryAfter(t *testing.T) {
+	config := &Config{Curves: map[flowcontrol.Priority]Curve{
+		flowcontrol.PriorityStandard:  {Min: time.Second, Max: 5 * time.Second, Exponent: 1},
+		flowcontrol.PrioritySheddable: {Min: 2 * time.Second, Max: 30 * time.Second, Exponent: 2},
+	}}
+	detector := &fakeDetector{}
+	b := newBackoff(config, detector)
+
+	tests := []struct {
+		priority   flowcontrol.Priority
+		saturation float64
+		want       time.Duration
+		wantOk     bool
+	}{
+		{priority: flowcontrol.PriorityStandard, saturation: 0, want: time.Second, wantOk: true},
+		{priority: flowcontrol.PriorityStandard, saturation: 0.5, want: 3 * time.Second, wantOk: true},
+		{priority: flowcontrol.PriorityStandard, saturation: 1, want: 5 * time.Second, wantOk: true},
+		{priority: flowcontrol.PrioritySheddable, saturation: 0.5, want: 9 * time.Second, wantOk: true},
+		{priority: flowcontrol.PrioritySheddable, saturation: 1, want: 30 * time.Second, wantOk: true},
+		// The saturation is clamped to [0, 1].
+		{priority: flowcontrol.PrioritySheddable, saturation: 2, want: 30 * time.Second, wantOk: true},
+		{priority: flowcontrol.PriorityCritical, saturation: 1, want: 0, wantOk: false},
+	}
+	for _, test := range tests {
+		detector.saturation = test.saturation
+		got, ok := b.RetryAfter(context.Background(), test.priority)
+		if got != test.want || ok != test.wantOk {
+			t.Errorf("RetryAfter(%s) at saturation %v = %v, %v, want %v, %v", test.priority, test.saturation, got, ok, test.want, test.wantOk)
+		}
+	}
+}
+
+func TestParseCurve(t *testing.T) {
+	tests := []struct {
+		curve   string
+		want    Curve
+		wantErr bool
+	}{
+		{curve: "1s,5s", want: Curve{Min: time.Second, Max: 5 * time.Second, Exponent: 1}},
+		{curve: "2s, 30s, 2", want: Curve{Min: 2 * time.Second, Max: 30 * time.Second, Exponent: 2}},
+		{curve: "1s", wantErr: true},
+		{curve: "1s,5s,2,3", wantErr: true},
+		{curve: "one,5s", wantErr: true},
+		{curve: "0s,5s", wantErr: true},
+		{curve: "5s,1s", wantErr: true},
+		{curve: "1s,5s,0", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseCurve(test.curve)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseCurve(%q) returned error %v, want error %v", test.curve, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseCurve(%q) = %+v, want %+v", test.curve, got, test.want)
+		}
+	}
+}
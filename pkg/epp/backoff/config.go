@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Environment variable names for the backoff configuration. The curve variables are suffixed
+// with the priority: RETRY_AFTER_CURVE_<CRITICAL|STANDARD|SHEDDABLE>.
+const (
+	EnvCurvePrefix = "RETRY_AFTER_CURVE_"
+)
+
+// Curve maps the saturation of the pool, from 0 (no pod saturated) to 1 (all pods saturated), to
+// the delay after which a shed request is advised to be retried.
+type Curve struct {
+	// Min is the delay when no pod is saturated.
+	Min time.Duration
+	// Max is the delay when all the pods are saturated.
+	Max time.Duration
+	// Exponent shapes the curve between Min and Max: 1 is linear, above 1 the delay grows mostly
+	// as the pool gets fully saturated, below 1 it grows mostly at the onset of the saturation.
+	Exponent float64
+}
+
+// At returns the delay of the curve at the given saturation.
+func (c Curve) At(saturation float64) time.Duration {
+	saturation = min(max(saturation, 0), 1)
+	return c.Min + time.Duration(float64(c.Max-c.Min)*math.Pow(saturation, c.Exponent))
+}
+
+// Config holds the configuration of the Backoff.
+type Config struct {
+	// Curves are the Retry-After curves, by priority. The requests of the priorities without curve
+	// keep the Retry-After they were shed with.
+	Curves map[flowcontrol.Priority]Curve
+	// SaturationDetector configures when the pods are saturated.
+	SaturationDetector *saturationdetector.Config
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Curves: map[flowcontrol.Priority]Curve{},
+		SaturationDetector: &saturationdetector.Config{
+			QueueDepthThreshold:       saturationdetector.DefaultQueueDepthThreshold,
+			KVCacheUtilThreshold:      saturationdetector.DefaultKVCacheUtilThreshold,
+			MetricsStalenessThreshold: saturationdetector.DefaultMetricsStalenessThreshold,
+		},
+	}
+}
+
+// Enabled returns true if any priority has a curve.
+func (c *Config) Enabled() bool {
+	return len(c.Curves) > 0
+}
+
+// LoadConfigFromEnv loads the Backoff Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("backoff-config")
+
+	cfg := NewDefaultConfig()
+
+	for _, priority := range flowcontrol.Priorities {
+		env := EnvCurvePrefix + strings.ToUpper(priority.String())
+		value := envutil.GetEnvString(env, "", logger)
+		if value == "" {
+			continue
+		}
+		curve, err := ParseCurve(value)
+		if err != nil {
+			logger.Error(err, "Ignoring invalid Retry-After curve", "env", env)
+			continue
+		}
+		cfg.Curves[priority] = curve
+	}
+
+	cfg.SaturationDetector = saturationdetector.LoadConfigFromEnv()
+
+	logger.Info("Backoff configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
+
+// ParseCurve parses a curve formatted as <min>,<max>[,<exponent>], where min and max are
+// durations and the exponent defaults to 1, e.g. "2s,30s,2".
+func ParseCurve(s string) (Curve, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Curve{}, fmt.Errorf("invalid curve %q, expected <min>,<max>[,<exponent>]", s)
+	}
+	curve := Curve{Exponent: 1}
+	var err error
+	if curve.Min, err = time.ParseDuration(strings.TrimSpace(parts[0])); err != nil {
+		return Curve{}, fmt.Errorf("invalid min of curve %q: %w", s, err)
+	}
+	if curve.Max, err = time.ParseDuration(strings.TrimSpace(parts[1])); err != nil {
+		return Curve{}, fmt.Errorf("invalid max of curve %q: %w", s, err)
+	}
+	if len(parts) == 3 {
+		if curve.Exponent, err = strconv.ParseFloat(strings.TrimSpace(parts[2]), 64); err != nil {
+			return Curve{}, fmt.Errorf("invalid exponent of curve %q: %w", s, err)
+		}
+	}
+	if curve.Min <= 0 || curve.Max < curve.Min {
+		return Curve{}, fmt.Errorf("invalid curve %q, expected 0 < min <= max", s)
+	}
+	if curve.Exponent <= 0 {
+		return Curve{}, fmt.Errorf("invalid curve %q, expected a positive exponent", s)
+	}
+	return curve, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capability evaluates whether any endpoint of the pool can serve an InferenceModel, so
+// that a model that can't be served is reported through its status, a metric and an early
+// response, rather than a generic scheduling failure.
+//
+// An endpoint can serve a target model if it isn't cordoned and either reports the target model
+// as a running or waiting LoRA adapter, supports LoRA adapters (i.e. reports a positive maximum
+// number of adapters), or the target model isn't reported as an adapter by any endpoint of the
+// pool, in which case it is assumed to be the base model served by every endpoint.
+//
+// A model isn't served either while it isn't active according to its lifecycle, i.e. while it's
+// paused or outside of its activation window.
+package capability
+
+import (
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+)
+
+// Evaluation is the result of the evaluation of the endpoints able to serve a model.
+type Evaluation struct {
+	// CapableEndpoints is the number of endpoints that can serve the model.
+	CapableEndpoints int
+	// AdapterEndpoints is the number of available endpoints that have loaded one of the target
+	// models as an adapter.
+	AdapterEndpoints int
+	// Reason is why the model can or can't be served.
+	Reason v1alpha2.InferenceModelConditionReason
+	// Message is a human readable description of the evaluation.
+	Message string
+}
+
+// Servable returns whether at least one endpoint can serve the model.
+func (e Evaluation) Servable() bool {
+	return e.CapableEndpoints > 0
+}
+
+// EvaluateTargetModel evaluates the endpoints able to serve the given target model.
+func EvaluateTargetModel(pods []backendmetrics.PodMetrics, targetModel string) Evaluation {
+	return evaluate(podStates(pods), []string{targetModel})
+}
+
+// EvaluateModel evaluates the endpoints able to serve any of the target models of the given
+// InferenceModel, or its model name if it has no target models.
+func EvaluateModel(pods []backendmetrics.PodMetrics, model *v1alpha2.InferenceModel) Evaluation {
+	targetModels := []string{}
+	for _, target := range model.Spec.TargetModels {
+		if target.Weight == nil || *target.Weight > 0 {
+			targetModels = append(targetModels, target.Name)
+		}
+	}
+	if len(targetModels) == 0 {
+		targetModels = append(targetModels, model.Spec.ModelName)
+	}
+	return evaluate(podStates(pods), targetModels)
+}
+
+// maxCachedTargetModels bounds the number of target models whose evaluation is cached per snapshot.
+const maxCachedTargetModels = 1024
+
+// Cache caches the evaluations of the target models for the last snapshot of the pods, so the
+// requests don't evaluate every endpoint of the pool while the pods and their metrics don't change.
+type Cache struct {
+	mu          sync.Mutex
+	generation  uint64
+	evaluations map[string]Evaluation
+}
+
+// NewCache creates a new Cache.
+func NewCache() *Cache {
+	return &Cache{evaluations: map[string]Evaluation{}}
+}
+
+// EvaluateTargetModel evaluates the endpoints of the given snapshot able to serve the given target
+// model, reusing the evaluation of a previous request if the snapshot didn't change.
+func (c *Cache) EvaluateTargetModel(snapshot *backendmetrics.PodSnapshot, targetModel string) Evaluation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if snapshot.Generation != c.generation {
+		c.generation = snapshot.Generation
+		clear(c.evaluations)
+	}
+	if evaluation, ok := c.evaluations[targetModel]; ok {
+		return evaluation
+	}
+	evaluation := evaluate(snapshot.Pods, []string{targetModel})
+	if len(c.evaluations) < maxCachedTargetModels {
+		c.evaluations[targetModel] = evaluation
+	}
+	return evaluation
+}
+
+// podStates returns the current state of the given pods.
+func podStates(pods []backendmetrics.PodMetrics) []backendmetrics.PodState {
+	states := make([]backendmetrics.PodState, 0, len(pods))
+	for _, pod := range pods {
+		states = append(states, backendmetrics.PodState{Pod: pod.GetPod(), Metrics: pod.GetMetrics()})
+	}
+	return states
+}
+
+func evaluate(pods []backendmetrics.PodState, targetModels []string) Evaluation {
+	if len(pods) == 0 {
+		return Evaluation{Reason: v1alpha2.ModelReasonNoEndpoints, Message: "the pool has no ready endpoints"}
+	}
+
+	adapters := map[string]bool{} // the target models reported as adapters by any endpoint
+	for _, pod := range pods {
+		metrics := pod.Metrics
+		for _, targetModel := range targetModels {
+			if hasAdapter(metrics, targetModel) {
+				adapters[targetModel] = true
+			}
+		}
+	}
+
+	available, capable, loaded := 0, 0, 0
+	for _, pod := range pods {
+		if pod.Pod.Cordoned {
+			continue
+		}
+		available++
+		metrics := pod.Metrics
+		for _, targetModel := range targetModels {
+			if hasAdapter(metrics, targetModel) {
+				loaded++
+				break
+			}
+		}
+		for _, targetModel := range targetModels {
+			if !adapters[targetModel] || hasAdapter(metrics, targetModel) || (metrics != nil && metrics.MaxActiveModels > 0) {
+				capable++
+				break
+			}
+		}
+	}
+
+	switch {
+	case available == 0:
+		return Evaluation{Reason: v1alpha2.ModelReasonEndpointsCordoned, Message: fmt.Sprintf("all the %d endpoints of the pool are cordoned", len(pods))}
+	case capable == 0:
+		return Evaluation{Reason: v1alpha2.ModelReasonAdapterNotLoadable, Message: fmt.Sprintf("none of the %d available endpoints has loaded or is able to load the adapters %v", available, targetModels)}
+	default:
+		return Evaluation{CapableEndpoints: capable, AdapterEndpoints: loaded, Reason: v1alpha2.ModelReasonEndpointsAvailable, Message: fmt.Sprintf("%d of the %d endpoints of the pool can serve the model", capable, len(pods))}
+	}
+}
+
+func hasAdapter(metrics *backendmetrics.MetricsState, adapter string) bool {
+	if metrics == nil {
+		return false
+	}
+	_, active := metrics.ActiveModels[adapter]
+	_, waiting := metrics.WaitingModels[adapter]
+	return active || waiting
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+func fakePod(name string, cordoned bool, metrics *backendmetrics.MetricsState) backendmetrics.PodMetrics {
+	return &backendmetrics.FakePodMetrics{
+		Pod:     &backend.Pod{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}, Cordoned: cordoned},
+		Metrics: metrics,
+	}
+}
+
+func TestEvaluateModel(t *testing.T) {
+	withAdapter := &backendmetrics.MetricsState{ActiveModels: map[string]int{"sql-lora": 1}, WaitingModels: map[string]int{}, MaxActiveModels: 1}
+	withoutLoRA := &backendmetrics.MetricsState{ActiveModels: map[string]int{}, WaitingModels: map[string]int{}}
+	withLoRA := &backendmetrics.MetricsState{ActiveModels: map[string]int{}, WaitingModels: map[string]int{}, MaxActiveModels: 2}
+
+	tests := []struct {
+		name        string
+		pods        []backendmetrics.PodMetrics
+		model       *v1alpha2.InferenceModel
+		wantCapable int
+		wantAdapter int
+		wantReason  v1alpha2.InferenceModelConditionReason
+	}{
+		{
+			name:       "no pods",
+			model:      testutil.MakeInferenceModel("m").ModelName("base").ObjRef(),
+			wantReason: v1alpha2.ModelReasonNoEndpoints,
+		},
+		{
+			name:       "all pods cordoned",
+			pods:       []backendmetrics.PodMetrics{fakePod("p1", true, withoutLoRA), fakePod("p2", true, withoutLoRA)},
+			model:      testutil.MakeInferenceModel("m").ModelName("base").ObjRef(),
+			wantReason: v1alpha2.ModelReasonEndpointsCordoned,
+		},
+		{
+			name:        "base model served by every available pod",
+			pods:        []backendmetrics.PodMetrics{fakePod("p1", false, withoutLoRA), fakePod("p2", true, withoutLoRA), fakePod("p3", false, withLoRA)},
+			model:       testutil.MakeInferenceModel("m").ModelName("base").ObjRef(),
+			wantCapable: 2,
+			wantReason:  v1alpha2.ModelReasonEndpointsAvailable,
+		},
+		{
+			name:        "adapter loaded or loadable",
+			pods:        []backendmetrics.PodMetrics{fakePod("p1", false, withAdapter), fakePod("p2", false, withoutLoRA), fakePod("p3", false, withLoRA)},
+			model:       testutil.MakeInferenceModel("m").ModelName("sql").TargetModel("sql-lora").ObjRef(),
+			wantCapable: 2,
+			wantAdapter: 1,
+			wantReason:  v1alpha2.ModelReasonEndpointsAvailable,
+		},
+		{
+			name:       "adapter only loaded on a cordoned pod",
+			pods:       []backendmetrics.PodMetrics{fakePod("p1", true, withAdapter), fakePod("p2", false, withoutLoRA)},
+			model:      testutil.MakeInferenceModel("m").ModelName("sql").TargetModel("sql-lora").ObjRef(),
+			wantReason: v1alpha2.ModelReasonAdapterNotLoadable,
+		},
+		{
+			name:        "any target model is enough",
+			pods:        []backendmetrics.PodMetrics{fakePod("p1", true, withAdapter), fakePod("p2", false, withoutLoRA)},
+			model:       testutil.MakeInferenceModel("m").ModelName("sql").TargetModel("sql-lora").TargetModel("base").ObjRef(),
+			wantCapable: 1,
+			wantReason:  v1alpha2.ModelReasonEndpointsAvailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := EvaluateModel(test.pods, test.model)
+			assert.Equal(t, test.wantCapable, got.CapableEndpoints)
+			assert.Equal(t, test.wantAdapter, got.AdapterEndpoints)
+			assert.Equal(t, test.wantReason, got.Reason)
+			assert.NotEmpty(t, got.Message)
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	withoutLoRA := &backendmetrics.MetricsState{ActiveModels: map[string]int{}, WaitingModels: map[string]int{}}
+	cache := NewCache()
+
+	snapshot := backendmetrics.NewPodSnapshot([]backendmetrics.PodMetrics{fakePod("p1", false, withoutLoRA)})
+	got := cache.EvaluateTargetModel(snapshot, "base")
+	assert.Equal(t, 1, got.CapableEndpoints)
+	// The evaluation is reused while the snapshot doesn't change.
+	snapshot.Pods = nil
+	assert.Equal(t, got, cache.EvaluateTargetModel(snapshot, "base"))
+
+	// A new snapshot is evaluated again.
+	snapshot = backendmetrics.NewPodSnapshot([]backendmetrics.PodMetrics{fakePod("p1", true, withoutLoRA)})
+	got = cache.EvaluateTargetModel(snapshot, "base")
+	assert.Equal(t, 0, got.CapableEndpoints)
+	assert.Equal(t, v1alpha2.ModelReasonEndpointsCordoned, got.Reason)
+}
+
+type fakeDatastore struct {
+	pods   []backendmetrics.PodMetrics
+	models []*v1alpha2.InferenceModel
+}
+
+func (ds *fakeDatastore) PodGetAll() []backendmetrics.PodMetrics  { return ds.pods }
+func (ds *fakeDatastore) ModelGetAll() []*v1alpha2.InferenceModel { return ds.models }
+
+func TestReporter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha2.Install(scheme)
+	model := testutil.MakeInferenceModel("m").Namespace("default").ModelName("base").ObjRef()
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(model).WithStatusSubresource(model).Build()
+	ds := &fakeDatastore{}
+	r := NewReporter(fakeClient, ds, 0)
+
+	// report evaluates the models, and returns the Servable condition written in the status.
+	var status v1alpha2.InferenceModelStatus
+	report := func() *metav1.Condition {
+		got := &v1alpha2.InferenceModel{}
+		if err := fakeClient.Get(t.Context(), client.ObjectKeyFromObject(model), got); err != nil {
+			t.Fatalf("Failed to get the model: %v", err)
+		}
+		// The datastore holds the latest model, as kept by the InferenceModel reconciler.
+		ds.models = []*v1alpha2.InferenceModel{got}
+		r.Report(t.Context())
+		if err := fakeClient.Get(t.Context(), client.ObjectKeyFromObject(model), got); err != nil {
+			t.Fatalf("Failed to get the model: %v", err)
+		}
+		status = got.Status
+		return meta.FindStatusCondition(got.Status.Conditions, string(v1alpha2.ModelConditionServable))
+	}
+
+	condition := report()
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, string(v1alpha2.ModelReasonNoEndpoints), condition.Reason)
+	}
+
+	ds.pods = []backendmetrics.PodMetrics{fakePod("p1", false, &backendmetrics.MetricsState{})}
+	condition = report()
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, string(v1alpha2.ModelReasonEndpointsAvailable), condition.Reason)
+	}
+	assert.Equal(t, &model.Spec.PoolRef, status.PoolRef)
+	assert.Equal(t, ptr.To[int32](1), status.CapableEndpoints)
+	assert.Equal(t, ptr.To[int32](0), status.AdapterEndpoints)
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, string(v1alpha2.ModelConditionAdapterAvailable)))
+
+	// The endpoints that loaded a target model as an adapter are reported, and the endpoints that
+	// neither loaded it nor support adapters are no longer able to serve the model.
+	got := &v1alpha2.InferenceModel{}
+	assert.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(model), got))
+	got.Spec.TargetModels = []v1alpha2.TargetModel{{Name: "sql-lora"}}
+	assert.NoError(t, fakeClient.Update(t.Context(), got))
+	ds.pods = append(ds.pods, fakePod("p2", false, &backendmetrics.MetricsState{ActiveModels: map[string]int{"sql-lora": 1}}))
+	report()
+	assert.Equal(t, ptr.To[int32](1), status.CapableEndpoints)
+	assert.Equal(t, ptr.To[int32](1), status.AdapterEndpoints)
+	adapterCondition := meta.FindStatusCondition(status.Conditions, string(v1alpha2.ModelConditionAdapterAvailable))
+	if assert.NotNil(t, adapterCondition) {
+		assert.Equal(t, metav1.ConditionTrue, adapterCondition.Status)
+		assert.Equal(t, string(v1alpha2.ModelReasonAdapterLoaded), adapterCondition.Reason)
+	}
+
+	// An unchanged evaluation doesn't update the status.
+	assert.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(model), got))
+	resourceVersion := got.ResourceVersion
+	report()
+	assert.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(model), got))
+	assert.Equal(t, resourceVersion, got.ResourceVersion)
+
+	// A paused model isn't servable, whatever its endpoints.
+	got.Spec.Lifecycle = &v1alpha2.ModelLifecycle{Paused: true}
+	assert.NoError(t, fakeClient.Update(t.Context(), got))
+	condition = report()
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, string(v1alpha2.ModelReasonPaused), condition.Reason)
+	}
+}
+
+func TestEvaluateLifecycle(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := metav1.NewTime(now.Add(-time.Hour))
+	after := metav1.NewTime(now.Add(time.Hour))
+	statusCode := int32(410)
+
+	tests := []struct {
+		name       string
+		lifecycle  *v1alpha2.ModelLifecycle
+		wantActive bool
+		wantReason v1alpha2.InferenceModelConditionReason
+		wantErr    errutil.Error
+	}{
+		{
+			name:       "no lifecycle",
+			wantActive: true,
+		},
+		{
+			name:       "within the activation window",
+			lifecycle:  &v1alpha2.ModelLifecycle{ActiveFrom: &before, ActiveUntil: &after},
+			wantActive: true,
+		},
+		{
+			name:       "paused",
+			lifecycle:  &v1alpha2.ModelLifecycle{Paused: true, ActiveFrom: &before},
+			wantReason: v1alpha2.ModelReasonPaused,
+			wantErr:    errutil.Error{Code: errutil.ModelInactive, Msg: "model food-review is paused"},
+		},
+		{
+			name:       "not active yet",
+			lifecycle:  &v1alpha2.ModelLifecycle{ActiveFrom: &after},
+			wantReason: v1alpha2.ModelReasonInactive,
+			wantErr:    errutil.Error{Code: errutil.ModelInactive, Msg: "model food-review is not active until 2025-06-01T13:00:00Z"},
+		},
+		{
+			name: "sunset with a custom response",
+			lifecycle: &v1alpha2.ModelLifecycle{
+				ActiveUntil:      &before,
+				InactiveResponse: &v1alpha2.InactiveModelResponse{StatusCode: &statusCode, Message: "use food-review-2"},
+			},
+			wantReason: v1alpha2.ModelReasonInactive,
+			wantErr:    errutil.Error{Code: errutil.ModelInactive, Msg: "use food-review-2", StatusCode: 410},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wrapper := testutil.MakeInferenceModel("m").ModelName("food-review")
+			if test.lifecycle != nil {
+				wrapper.Lifecycle(*test.lifecycle)
+			}
+			model := wrapper.ObjRef()
+			evaluation, active := EvaluateLifecycle(model, now)
+			assert.Equal(t, test.wantActive, active)
+			if active {
+				return
+			}
+			assert.Equal(t, test.wantReason, evaluation.Reason)
+			assert.False(t, evaluation.Servable())
+			assert.Equal(t, test.wantErr, InactiveModelError(model, evaluation))
+		})
+	}
+}
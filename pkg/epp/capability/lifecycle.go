@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capability
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+// EvaluateLifecycle evaluates whether the given InferenceModel is active at the given time
+// according to its lifecycle. If it isn't, the returned evaluation tells why.
+func EvaluateLifecycle(model *v1alpha2.InferenceModel, now time.Time) (Evaluation, bool) {
+	lifecycle := model.Spec.Lifecycle
+	switch {
+	case lifecycle == nil:
+		return Evaluation{}, true
+	case lifecycle.Paused:
+		return Evaluation{Reason: v1alpha2.ModelReasonPaused, Message: fmt.Sprintf("model %s is paused", model.Spec.ModelName)}, false
+	case lifecycle.ActiveFrom != nil && now.Before(lifecycle.ActiveFrom.Time):
+		return Evaluation{Reason: v1alpha2.ModelReasonInactive, Message: fmt.Sprintf("model %s is not active until %s",
+			model.Spec.ModelName, lifecycle.ActiveFrom.UTC().Format(time.RFC3339))}, false
+	case lifecycle.ActiveUntil != nil && !now.Before(lifecycle.ActiveUntil.Time):
+		return Evaluation{Reason: v1alpha2.ModelReasonInactive, Message: fmt.Sprintf("model %s is no longer active since %s",
+			model.Spec.ModelName, lifecycle.ActiveUntil.UTC().Format(time.RFC3339))}, false
+	default:
+		return Evaluation{}, true
+	}
+}
+
+// InactiveModelError returns the error rejecting the requests for the given InferenceModel, which
+// isn't active for the reason of the given evaluation, with the response set by its lifecycle.
+func InactiveModelError(model *v1alpha2.InferenceModel, evaluation Evaluation) errutil.Error {
+	err := errutil.Error{Code: errutil.ModelInactive, Msg: evaluation.Message}
+	if response := model.Spec.Lifecycle.InactiveResponse; response != nil {
+		if response.StatusCode != nil {
+			err.StatusCode = int(*response.StatusCode)
+		}
+		if response.Message != "" {
+			err.Msg = response.Message
+		}
+	}
+	return err
+}
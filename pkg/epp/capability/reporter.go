@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capability
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// DefaultReportInterval is how often the endpoints able to serve the models are evaluated.
+const DefaultReportInterval = 5 * time.Second
+
+// Datastore provides access to the pods and the models of the pool.
+type Datastore interface {
+	PodGetAll() []backendmetrics.PodMetrics
+	ModelGetAll() []*v1alpha2.InferenceModel
+}
+
+// Reporter periodically evaluates the endpoints able to serve each InferenceModel of the pool,
+// and reports them through the inference_model_capable_endpoints metric and the Servable
+// condition of the InferenceModel status, unless the model isn't active according to its
+// lifecycle, in which case the condition tells so. The status also reports the pool serving the
+// model, the number of endpoints able to serve it, and the number of endpoints that have loaded
+// it as an adapter, along with the AdapterAvailable condition.
+type Reporter struct {
+	client    client.Client
+	datastore Datastore
+	interval  time.Duration
+
+	reported map[string]bool                                        // the model names whose metric is reported
+	written  map[types.NamespacedName]v1alpha2.InferenceModelStatus // the last status written for each model
+}
+
+// NewReporter creates a new Reporter. If the client is nil, the status of the InferenceModels
+// isn't updated.
+func NewReporter(c client.Client, datastore Datastore, interval time.Duration) *Reporter {
+	if interval <= 0 {
+		interval = DefaultReportInterval
+	}
+	return &Reporter{
+		client:    c,
+		datastore: datastore,
+		interval:  interval,
+		reported:  map[string]bool{},
+		written:   map[types.NamespacedName]v1alpha2.InferenceModelStatus{},
+	}
+}
+
+// SetupWithManager registers the periodic evaluation with the given manager.
+func (r *Reporter) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica reports the metric
+// of the models it serves, and the status is only written when the condition changes.
+func (r *Reporter) NeedLeaderElection() bool {
+	return false
+}
+
+// Start evaluates the models every interval until the context is done.
+func (r *Reporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.Report(ctx)
+		}
+	}
+}
+
+// Report evaluates the endpoints able to serve each model, and reports them.
+func (r *Reporter) Report(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	pods := r.datastore.PodGetAll()
+
+	current := map[string]bool{}
+	keys := map[types.NamespacedName]bool{}
+	for _, model := range r.datastore.ModelGetAll() {
+		endpoints := EvaluateModel(pods, model)
+		current[model.Spec.ModelName] = true
+		keys[client.ObjectKeyFromObject(model)] = true
+		metrics.RecordInferenceModelCapableEndpoints(model.Spec.ModelName, endpoints.CapableEndpoints)
+		if !endpoints.Servable() {
+			logger.V(logutil.DEFAULT).Info("No endpoint can serve the model", "model", model.Spec.ModelName, "reason", endpoints.Reason, "message", endpoints.Message)
+		}
+		// The Servable condition of a model that isn't active tells why, whatever its endpoints.
+		servable := endpoints
+		if inactive, active := EvaluateLifecycle(model, time.Now()); !active {
+			servable = inactive
+		}
+		if r.client != nil {
+			if err := r.updateStatus(ctx, model, servable, endpoints); err != nil {
+				logger.V(logutil.DEFAULT).Error(err, "Failed to update the InferenceModel status", "inferenceModel", client.ObjectKeyFromObject(model))
+			}
+		}
+	}
+
+	for modelName := range r.reported {
+		if !current[modelName] {
+			metrics.DeleteInferenceModelCapableEndpoints(modelName)
+		}
+	}
+	r.reported = current
+	for key := range r.written {
+		if !keys[key] {
+			delete(r.written, key)
+		}
+	}
+}
+
+// updateStatus sets the Servable condition of the model from the servable evaluation, and the
+// endpoints serving the model from the endpoints evaluation, unless they are already set.
+func (r *Reporter) updateStatus(ctx context.Context, model *v1alpha2.InferenceModel, servable, endpoints Evaluation) error {
+	status := reportedStatus(model, servable, endpoints)
+
+	key := client.ObjectKeyFromObject(model)
+	if sameStatus(model.Status, status) {
+		r.written[key] = status
+		return nil
+	}
+	if written, ok := r.written[key]; ok && sameStatus(written, status) {
+		// The status was written, but the datastore doesn't hold the updated model yet.
+		return nil
+	}
+
+	updated := model.DeepCopy()
+	for _, condition := range status.Conditions {
+		meta.SetStatusCondition(&updated.Status.Conditions, condition)
+	}
+	updated.Status.PoolRef = status.PoolRef
+	updated.Status.CapableEndpoints = status.CapableEndpoints
+	updated.Status.AdapterEndpoints = status.AdapterEndpoints
+	if err := r.client.Status().Patch(ctx, updated, client.MergeFromWithOptions(model, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	r.written[key] = status
+	return nil
+}
+
+// reportedStatus returns the part of the status of the model that is reported by the Reporter.
+func reportedStatus(model *v1alpha2.InferenceModel, servable, endpoints Evaluation) v1alpha2.InferenceModelStatus {
+	servableCondition := metav1.Condition{
+		Type:               string(v1alpha2.ModelConditionServable),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: model.Generation,
+		Reason:             string(servable.Reason),
+		Message:            servable.Message,
+	}
+	if !servable.Servable() {
+		servableCondition.Status = metav1.ConditionFalse
+	}
+
+	adapterCondition := metav1.Condition{
+		Type:               string(v1alpha2.ModelConditionAdapterAvailable),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: model.Generation,
+		Reason:             string(v1alpha2.ModelReasonAdapterLoaded),
+		Message:            fmt.Sprintf("%d endpoints of the pool have loaded the model as an adapter", endpoints.AdapterEndpoints),
+	}
+	if endpoints.AdapterEndpoints == 0 {
+		adapterCondition.Status = metav1.ConditionFalse
+		adapterCondition.Reason = string(v1alpha2.ModelReasonAdapterNotLoaded)
+		adapterCondition.Message = "no available endpoint of the pool has loaded the model as an adapter"
+	}
+
+	poolRef := model.Spec.PoolRef
+	return v1alpha2.InferenceModelStatus{
+		Conditions:       []metav1.Condition{servableCondition, adapterCondition},
+		PoolRef:          &poolRef,
+		CapableEndpoints: ptr.To(int32(endpoints.CapableEndpoints)),
+		AdapterEndpoints: ptr.To(int32(endpoints.AdapterEndpoints)),
+	}
+}
+
+// sameStatus returns whether the existing status holds the given reported status.
+func sameStatus(existing, status v1alpha2.InferenceModelStatus) bool {
+	for _, condition := range status.Conditions {
+		if !sameCondition(meta.FindStatusCondition(existing.Conditions, condition.Type), condition) {
+			return false
+		}
+	}
+	return ptr.Equal(existing.PoolRef, status.PoolRef) && ptr.Equal(existing.CapableEndpoints, status.CapableEndpoints) &&
+		ptr.Equal(existing.AdapterEndpoints, status.AdapterEndpoints)
+}
+
+func sameCondition(existing *metav1.Condition, condition metav1.Condition) bool {
+	return existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration
+}
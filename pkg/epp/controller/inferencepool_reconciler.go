@@ -20,10 +20,12 @@ import (
 	"context"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
@@ -36,6 +38,12 @@ type InferencePoolReconciler struct {
 	client.Client
 	Record    record.EventRecorder
 	Datastore datastore.Datastore
+	// PoolNamespacedName, if set, restricts the reconciler to the given pool. It is required when the
+	// manager caches more than one InferencePool.
+	PoolNamespacedName types.NamespacedName
+	// ControllerName, if set, overrides the default controller name. It is required to be unique
+	// when more than one InferencePoolReconciler is registered with the manager.
+	ControllerName string
 }
 
 func (c *InferencePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -69,7 +77,15 @@ func (c *InferencePoolReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 }
 
 func (c *InferencePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha2.InferencePool{}).
-		Complete(c)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.InferencePool{})
+	if c.PoolNamespacedName.Name != "" {
+		b = b.WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == c.PoolNamespacedName.Name && obj.GetNamespace() == c.PoolNamespacedName.Namespace
+		}))
+	}
+	if c.ControllerName != "" {
+		b = b.Named(c.ControllerName)
+	}
+	return b.Complete(c)
 }
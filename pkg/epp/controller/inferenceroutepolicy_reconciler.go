@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/routepolicy"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// InferenceRoutePolicyReconciler keeps the InferenceRoutePolicies of the namespace of the pool in
+// the Store.
+type InferenceRoutePolicyReconciler struct {
+	client.Client
+	Store *routepolicy.Store
+}
+
+func (c *InferenceRoutePolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).V(logutil.DEFAULT).WithValues("inferenceRoutePolicy", req.NamespacedName)
+
+	policy := &v1alpha2.InferenceRoutePolicy{}
+	if err := c.Get(ctx, req.NamespacedName, policy); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "Unable to get InferenceRoutePolicy")
+			return ctrl.Result{}, err
+		}
+		logger.Info("InferenceRoutePolicy removed")
+		c.Store.Delete(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	if !policy.DeletionTimestamp.IsZero() {
+		logger.Info("InferenceRoutePolicy removed")
+		c.Store.Delete(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Added/Updated InferenceRoutePolicy", "targetRef", policy.Spec.TargetRef)
+	c.Store.Set(policy)
+	return ctrl.Result{}, nil
+}
+
+func (c *InferenceRoutePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.InferenceRoutePolicy{}).
+		Complete(c)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/routepolicy"
+)
+
+func TestInferenceRoutePolicyReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha2.Install(scheme)
+
+	policy := &v1alpha2.InferenceRoutePolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "policy"},
+		Spec: v1alpha2.InferenceRoutePolicySpec{
+			TargetRef:       v1alpha2.RouteTargetReference{Name: "route"},
+			MaxOutputTokens: ptr.To[int32](100),
+		},
+	}
+	route := routepolicy.Route{Namespace: "ns1", Name: "route"}
+	name := types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+	store := routepolicy.NewStore()
+	reconciler := &InferenceRoutePolicyReconciler{Client: fakeClient, Store: store}
+
+	if _, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: name}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spec := store.Get(route); spec == nil || *spec.MaxOutputTokens != 100 {
+		t.Errorf("Unexpected policy of the route after the policy was added: %v", spec)
+	}
+
+	if err := fakeClient.Delete(t.Context(), policy); err != nil {
+		t.Fatalf("Unexpected error deleting the policy: %v", err)
+	}
+	if _, err := reconciler.Reconcile(t.Context(), ctrl.Request{NamespacedName: name}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spec := store.Get(route); spec != nil {
+		t.Errorf("Unexpected policy of the route after the policy was deleted: %v", spec)
+	}
+}
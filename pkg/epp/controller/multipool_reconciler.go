@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/multipool"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// InferencePoolSetReconciler keeps the pools of a multipool.Set in sync with the InferencePools
+// selected by the Set: the selected pools are added to the Set along with their InferenceModels,
+// and the pools that are deleted or no longer selected are removed from it.
+type InferencePoolSetReconciler struct {
+	client.Client
+	Record record.EventRecorder
+	Pools  *multipool.Set
+}
+
+func (c *InferencePoolSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("inferencePool", req.NamespacedName).V(logutil.DEFAULT)
+	ctx = ctrl.LoggerInto(ctx, logger)
+
+	logger.Info("Reconciling InferencePool")
+
+	infPool := &v1alpha2.InferencePool{}
+	if err := c.Get(ctx, req.NamespacedName, infPool); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "Unable to get InferencePool")
+			return ctrl.Result{}, err
+		}
+		infPool = nil
+	}
+	if infPool == nil || !infPool.DeletionTimestamp.IsZero() || !c.Pools.Selects(req.NamespacedName, infPool.Labels) {
+		if c.Pools.Remove(req.NamespacedName) {
+			logger.Info("InferencePool removed from the served pools")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	added := c.Pools.Get(req.NamespacedName) == nil
+	pool := c.Pools.GetOrAdd(req.NamespacedName)
+	if err := pool.Datastore.PoolSet(ctx, c.Client, infPool); err != nil {
+		logger.Error(err, "Failed to update datastore")
+		return ctrl.Result{}, err
+	}
+	if added {
+		// The InferenceModels of the pool reconciled before the pool was added were skipped.
+		if err := c.loadModels(ctx, pool); err != nil {
+			logger.Error(err, "Failed to load the InferenceModels of the pool")
+			return ctrl.Result{}, err
+		}
+		logger.Info("InferencePool added to the served pools")
+	}
+	return ctrl.Result{}, nil
+}
+
+// loadModels sets the InferenceModels referencing the given pool in its datastore.
+func (c *InferencePoolSetReconciler) loadModels(ctx context.Context, pool *multipool.Pool) error {
+	var models v1alpha2.InferenceModelList
+	if err := c.List(ctx, &models, client.InNamespace(pool.Name.Namespace)); err != nil {
+		return fmt.Errorf("listing the InferenceModels of the pool: %w", err)
+	}
+	for i := range models.Items {
+		model := &models.Items[i]
+		if string(model.Spec.PoolRef.Name) == pool.Name.Name && model.DeletionTimestamp.IsZero() {
+			pool.Datastore.ModelSetIfOlder(model)
+		}
+	}
+	return nil
+}
+
+func (c *InferencePoolSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.InferencePool{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == c.Pools.Namespace()
+		})).
+		Named("inferencepool-set").
+		Complete(c)
+}
+
+// InferenceModelSetReconciler keeps the InferenceModels of the pools of a multipool.Set in sync,
+// as an InferenceModelReconciler per pool would.
+type InferenceModelSetReconciler struct {
+	client.Client
+	Record record.EventRecorder
+	Pools  *multipool.Set
+}
+
+func (c *InferenceModelSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Every pool reconciles the model, so the model is also removed from the pool it no longer
+	// references.
+	var errs []error
+	for _, pool := range c.Pools.Pools() {
+		if !pool.Datastore.PoolHasSynced() {
+			continue
+		}
+		reconciler := &InferenceModelReconciler{
+			Client:             c.Client,
+			Record:             c.Record,
+			Datastore:          pool.Datastore,
+			PoolNamespacedName: pool.Name,
+		}
+		if _, err := reconciler.Reconcile(ctx, req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return ctrl.Result{}, errors.Join(errs...)
+}
+
+func (c *InferenceModelSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(ctx, &v1alpha2.InferenceModel{}, datastore.ModelNameIndexKey, indexInferenceModelsByModelName); err != nil {
+		return fmt.Errorf("setting index on ModelName for InferenceModel: %w", err)
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.InferenceModel{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == c.Pools.Namespace()
+		})).
+		Named("inferencemodel-set").
+		Complete(c)
+}
+
+// PodSetReconciler keeps the pods of the pools of a multipool.Set in sync, as a PodReconciler per
+// pool would.
+type PodSetReconciler struct {
+	client.Client
+	Record record.EventRecorder
+	Pools  *multipool.Set
+}
+
+func (c *PodSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var errs []error
+	for _, pool := range c.Pools.Pools() {
+		reconciler := &PodReconciler{
+			Client:    c.Client,
+			Datastore: pool.Datastore,
+			Record:    c.Record,
+		}
+		if _, err := reconciler.Reconcile(ctx, req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return ctrl.Result{}, errors.Join(errs...)
+}
+
+func (c *PodSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	filter := predicate.Funcs{
+		CreateFunc: func(ce event.CreateEvent) bool {
+			return c.poolLabelsMatch(ce.Object.(*corev1.Pod))
+		},
+		UpdateFunc: func(ue event.UpdateEvent) bool {
+			return c.poolLabelsMatch(ue.ObjectOld.(*corev1.Pod)) || c.poolLabelsMatch(ue.ObjectNew.(*corev1.Pod))
+		},
+		DeleteFunc: func(de event.DeleteEvent) bool {
+			return c.poolLabelsMatch(de.Object.(*corev1.Pod))
+		},
+		GenericFunc: func(ge event.GenericEvent) bool {
+			return c.poolLabelsMatch(ge.Object.(*corev1.Pod))
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithEventFilter(filter).
+		Named("pod-set").
+		Complete(c)
+}
+
+// poolLabelsMatch returns whether the given pod matches the selector of any pool of the Set.
+func (c *PodSetReconciler) poolLabelsMatch(pod *corev1.Pod) bool {
+	for _, pool := range c.Pools.Pools() {
+		if pool.Datastore.PoolLabelsMatch(pod.GetLabels()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/multipool"
+	utiltest "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+func TestPoolSetReconcilers(t *testing.T) {
+	// As for the InferencePoolReconciler, the steps depend on each other.
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha2.Install(scheme)
+
+	shared := map[string]string{"tier": "shared"}
+	sharedPool := utiltest.MakeInferencePool("shared").Namespace("pool1-ns").Labels(shared).
+		Selector(selector_v1).TargetPortNumber(8080).ObjRef()
+	otherPool := utiltest.MakeInferencePool("other").Namespace("pool1-ns").
+		Selector(selector_v2).TargetPortNumber(8080).ObjRef()
+	model := utiltest.MakeInferenceModel("model1").Namespace("pool1-ns").ModelName("fake model1").
+		PoolName(sharedPool.Name).ObjRef()
+	initialObjects := []client.Object{sharedPool, otherPool, model}
+	for i := range pods {
+		initialObjects = append(initialObjects, pods[i])
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(initialObjects...).
+		WithIndex(&v1alpha2.InferenceModel{}, datastore.ModelNameIndexKey, indexInferenceModelsByModelName).
+		Build()
+
+	ctx := context.Background()
+	selector, _ := labels.Parse("tier=shared")
+	pools := multipool.NewSet(ctx, "pool1-ns", selector, func(ctx context.Context) datastore.Datastore {
+		return datastore.NewDatastore(ctx, pmf)
+	})
+	poolReconciler := &InferencePoolSetReconciler{Client: fakeClient, Pools: pools}
+	modelReconciler := &InferenceModelSetReconciler{Client: fakeClient, Pools: pools}
+	podReconciler := &PodSetReconciler{Client: fakeClient, Pools: pools}
+	sharedName := types.NamespacedName{Name: sharedPool.Name, Namespace: sharedPool.Namespace}
+	otherName := types.NamespacedName{Name: otherPool.Name, Namespace: otherPool.Namespace}
+
+	// Step 1: only the selected pool is added, with its ready pods and its models.
+	for _, name := range []types.NamespacedName{sharedName, otherName} {
+		if _, err := poolReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: name}); err != nil {
+			t.Errorf("Unexpected InferencePool reconcile error: %v", err)
+		}
+	}
+	if got := len(pools.Pools()); got != 1 {
+		t.Fatalf("Unexpected number of pools: got %d, want 1", got)
+	}
+	ds := pools.Get(sharedName).Datastore
+	if diff := diffStore(ds, diffStoreParams{wantPool: sharedPool, wantPods: []string{"pod1", "pod2"}, wantModels: []*v1alpha2.InferenceModel{model}}); diff != "" {
+		t.Errorf("Unexpected diff (+got/-want): %s", diff)
+	}
+
+	// Step 2: the models and pods are reconciled in the pools.
+	if err := fakeClient.Delete(ctx, model); err != nil {
+		t.Errorf("Unexpected model delete error: %v", err)
+	}
+	if _, err := modelReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: model.Name, Namespace: model.Namespace}}); err != nil {
+		t.Errorf("Unexpected InferenceModel reconcile error: %v", err)
+	}
+	if err := fakeClient.Delete(ctx, pods[0]); err != nil {
+		t.Errorf("Unexpected pod delete error: %v", err)
+	}
+	if _, err := podReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: pods[0].Name, Namespace: pods[0].Namespace}}); err != nil {
+		t.Errorf("Unexpected Pod reconcile error: %v", err)
+	}
+	if diff := diffStore(ds, diffStoreParams{wantPool: sharedPool, wantPods: []string{"pod2"}}); diff != "" {
+		t.Errorf("Unexpected diff (+got/-want): %s", diff)
+	}
+
+	// Step 3: the pool is removed once it's no longer selected.
+	sharedPool.Labels = nil
+	if err := fakeClient.Update(ctx, sharedPool); err != nil {
+		t.Errorf("Unexpected pool update error: %v", err)
+	}
+	if _, err := poolReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: sharedName}); err != nil {
+		t.Errorf("Unexpected InferencePool reconcile error: %v", err)
+	}
+	if got := len(pools.Pools()); got != 0 {
+		t.Errorf("Unexpected number of pools: got %d, want 0", got)
+	}
+	if diff := diffStore(ds, diffStoreParams{}); diff != "" {
+		t.Errorf("Unexpected diff (+got/-want): %s", diff)
+	}
+}
@@ -38,6 +38,9 @@ type PodReconciler struct {
 	client.Client
 	Datastore datastore.Datastore
 	Record    record.EventRecorder
+	// ControllerName, if set, overrides the default controller name. It is required to be unique
+	// when more than one PodReconciler is registered with the manager.
+	ControllerName string
 }
 
 func (c *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -84,10 +87,13 @@ func (c *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return c.Datastore.PoolLabelsMatch(pod.GetLabels())
 		},
 	}
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{}).
-		WithEventFilter(filter).
-		Complete(c)
+		WithEventFilter(filter)
+	if c.ControllerName != "" {
+		b = b.Named(c.ControllerName)
+	}
+	return b.Complete(c)
 }
 
 func (c *PodReconciler) updateDatastore(logger logr.Logger, pod *corev1.Pod) {
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custommetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// APIPath is the path the custom metrics API is served under.
+const APIPath = "/apis/" + GroupVersion
+
+// ServeHTTP serves the custom metrics API, i.e. the API resource list at APIPath, and the metric
+// values at APIPath/namespaces/{namespace}/{resource}/{name}/{metric}, where name may be "*" to
+// get the metric of all the objects matching the "labelSelector" query parameter.
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeStatus(w, http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed, "method not allowed")
+		return
+	}
+	path, ok := strings.CutPrefix(r.URL.Path, APIPath)
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("path %q not found", r.URL.Path))
+		return
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		writeJSON(w, apiResourceList())
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 5 || parts[0] != "namespaces" {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("path %q not found", r.URL.Path))
+		return
+	}
+	namespace, resourceName, name, metricName := parts[1], parts[2], parts[3], parts[4]
+
+	selector := labels.Everything()
+	if raw := r.URL.Query().Get("labelSelector"); raw != "" {
+		var err error
+		if selector, err = labels.Parse(raw); err != nil {
+			writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("invalid label selector: %v", err))
+			return
+		}
+	}
+
+	now := metav1.NewTime(time.Now())
+	list := &MetricValueList{
+		TypeMeta: metav1.TypeMeta{Kind: "MetricValueList", APIVersion: GroupVersion},
+		Items:    []MetricValue{},
+	}
+	for _, obj := range p.objects(r.Context(), resourceName, namespace, selector) {
+		if name != "*" && obj.ref.Name != name {
+			continue
+		}
+		value, ok := obj.metrics[metricName]
+		if !ok {
+			continue
+		}
+		list.Items = append(list.Items, MetricValue{
+			DescribedObject: obj.ref,
+			Metric:          MetricIdentifier{Name: metricName},
+			Timestamp:       now,
+			Value:           *resource.NewMilliQuantity(int64(value()*1000), resource.DecimalSI),
+		})
+	}
+	if name != "*" && len(list.Items) == 0 {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound,
+			fmt.Sprintf("the server could not find the metric %s for %s %s/%s", metricName, resourceName, namespace, name))
+		return
+	}
+
+	log.FromContext(r.Context()).V(logutil.TRACE).Info("Serving custom metric", "metric", metricName, "resource", resourceName,
+		"namespace", namespace, "name", name, "items", len(list.Items))
+	writeJSON(w, list)
+}
+
+// apiResourceList returns the metrics served, as API resources named {resource}/{metric}.
+func apiResourceList() *metav1.APIResourceList {
+	list := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: GroupVersion,
+	}
+	for resourceName, metricNames := range metricNames() {
+		for _, metricName := range metricNames {
+			list.APIResources = append(list.APIResources, metav1.APIResource{
+				Name:       resourceName + "/" + metricName,
+				Namespaced: true,
+				Kind:       "MetricValueList",
+				Verbs:      []string{"get"},
+			})
+		}
+	}
+	sort.Slice(list.APIResources, func(i, j int) bool {
+		return list.APIResources[i].Name < list.APIResources[j].Name
+	})
+	return list
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(&metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(obj)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custommetrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+func newTestProvider(t *testing.T) *Provider {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	pmc := &backendmetrics.FakePodMetricsClient{}
+	pmc.SetRes(map[types.NamespacedName]*backendmetrics.MetricsState{
+		{Name: "pod-0", Namespace: "default"}: {WaitingQueueSize: 2, KVCacheUsagePercent: 0.2},
+		{Name: "pod-1", Namespace: "default"}: {WaitingQueueSize: 10, KVCacheUsagePercent: 0.4},
+	})
+	ds := datastore.NewDatastore(t.Context(), backendmetrics.NewPodMetricsFactory(pmc, 10*time.Millisecond))
+
+	pool := testutil.MakeInferencePool("pool").Namespace("default").Selector(map[string]string{"app": "vllm"}).ObjRef()
+	if err := ds.PoolSet(t.Context(), fakeClient, pool); err != nil {
+		t.Fatalf("Failed to set pool: %v", err)
+	}
+	for _, name := range []string{"pod-0", "pod-1"} {
+		ds.PodUpdateOrAddIfNotExist(testutil.MakePod(name).Namespace("default").
+			Labels(map[string]string{"app": "vllm"}).ReadyCondition().ObjRef())
+	}
+	ds.ModelSetIfOlder(testutil.MakeInferenceModel("sql-model").Namespace("default").ModelName("sql-lora").ObjRef())
+	assert.Eventually(t, func() bool {
+		for _, pod := range ds.PodGetAll() {
+			if pod.GetMetrics().KVCacheUsagePercent == 0 {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	detector, err := saturationdetector.NewDetector(&saturationdetector.Config{
+		QueueDepthThreshold:       5,
+		KVCacheUtilThreshold:      0.8,
+		MetricsStalenessThreshold: time.Minute,
+	}, ds, logr.Discard())
+	if err != nil {
+		t.Fatalf("Failed to create saturation detector: %v", err)
+	}
+	return NewProvider(ds, detector)
+}
+
+func TestServeHTTP(t *testing.T) {
+	metrics.Register()
+	metrics.IncRunningRequests("sql-lora")
+	metrics.IncRunningRequests("sql-lora")
+	t.Cleanup(metrics.Reset)
+
+	provider := newTestProvider(t)
+
+	tests := []struct {
+		name          string
+		path          string
+		wantCode      int
+		wantResources int
+		wantValues    map[string]string
+	}{
+		{
+			name:          "API resource list",
+			path:          APIPath,
+			wantCode:      http.StatusOK,
+			wantResources: 5,
+		},
+		{
+			name:       "pool saturation",
+			path:       APIPath + "/namespaces/default/" + InferencePoolResource + "/pool/" + PoolSaturationMetric,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]string{"pool": "500m"},
+		},
+		{
+			name:       "pool average queue size",
+			path:       APIPath + "/namespaces/default/" + InferencePoolResource + "/pool/" + PoolAverageQueueSizeMetric,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]string{"pool": "6"},
+		},
+		{
+			name:       "pool average kv cache utilization",
+			path:       APIPath + "/namespaces/default/" + InferencePoolResource + "/pool/" + PoolAverageKVCacheUtilizationMetric,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]string{"pool": "300m"},
+		},
+		{
+			name:       "model running requests of all models",
+			path:       APIPath + "/namespaces/default/" + InferenceModelResource + "/*/" + ModelRunningRequestsMetric,
+			wantCode:   http.StatusOK,
+			wantValues: map[string]string{"sql-model": "2"},
+		},
+		{
+			name:       "no model matching the selector",
+			path:       APIPath + "/namespaces/default/" + InferenceModelResource + "/*/" + ModelRunningRequestsMetric + "?labelSelector=app%3Dother",
+			wantCode:   http.StatusOK,
+			wantValues: map[string]string{},
+		},
+		{
+			name:     "pool in another namespace",
+			path:     APIPath + "/namespaces/other/" + InferencePoolResource + "/pool/" + PoolSaturationMetric,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "unknown metric",
+			path:     APIPath + "/namespaces/default/" + InferencePoolResource + "/pool/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid path",
+			path:     APIPath + "/pool",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			provider.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+			assert.Equal(t, test.wantCode, rec.Code)
+			if test.wantCode != http.StatusOK {
+				return
+			}
+
+			if test.wantResources > 0 {
+				var list metav1.APIResourceList
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+				assert.Equal(t, GroupVersion, list.GroupVersion)
+				assert.Len(t, list.APIResources, test.wantResources)
+				return
+			}
+
+			var list MetricValueList
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			values := map[string]string{}
+			for _, item := range list.Items {
+				values[item.DescribedObject.Name] = item.Value.String()
+			}
+			assert.Equal(t, test.wantValues, values)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package custommetrics implements the custom metrics API (custom.metrics.k8s.io), exposing the
+// signals EPP aggregates over the pool, so a HorizontalPodAutoscaler can scale the model server
+// Deployment on them without a Prometheus adapter.
+package custommetrics
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+)
+
+// The resources described by the metrics, as used in the custom metrics API paths.
+const (
+	InferencePoolResource  = "inferencepools." + v1alpha2.GroupName
+	InferenceModelResource = "inferencemodels." + v1alpha2.GroupName
+)
+
+// The metrics of the InferencePool.
+const (
+	// PoolSaturationMetric is the fraction [0, 1] of the pods of the pool that are saturated, see
+	// saturationdetector.Detector.Saturation.
+	PoolSaturationMetric                = "inference_pool_saturation"
+	PoolAverageQueueSizeMetric          = "inference_pool_average_queue_size"
+	PoolAverageKVCacheUtilizationMetric = "inference_pool_average_kv_cache_utilization"
+	PoolReadyPodsMetric                 = "inference_pool_ready_pods"
+)
+
+// The metrics of the InferenceModels.
+const (
+	// ModelRunningRequestsMetric is the number of requests for the model that are in flight through
+	// this EPP, either queued or running on the model servers.
+	ModelRunningRequestsMetric = "inference_model_running_requests"
+)
+
+// object is an object described by the metrics, with the function computing each of its metrics.
+type object struct {
+	ref     ObjectReference
+	labels  map[string]string
+	metrics map[string]func() float64
+}
+
+// NewProvider initializes a new Provider and returns its pointer.
+func NewProvider(ds datastore.Datastore, detector *saturationdetector.Detector) *Provider {
+	return &Provider{
+		datastore: ds,
+		detector:  detector,
+	}
+}
+
+// Provider computes the custom metrics of the pool and its models from the datastore.
+type Provider struct {
+	datastore datastore.Datastore
+	detector  *saturationdetector.Detector
+}
+
+// metricNames returns the names of the metrics, keyed by the resource they describe.
+func metricNames() map[string][]string {
+	return map[string][]string{
+		InferencePoolResource: {
+			PoolSaturationMetric,
+			PoolAverageQueueSizeMetric,
+			PoolAverageKVCacheUtilizationMetric,
+			PoolReadyPodsMetric,
+		},
+		InferenceModelResource: {
+			ModelRunningRequestsMetric,
+		},
+	}
+}
+
+// objects returns the objects of the given resource in the given namespace, matching the given
+// selector.
+func (p *Provider) objects(ctx context.Context, resource, namespace string, selector labels.Selector) []object {
+	var objects []object
+	switch resource {
+	case InferencePoolResource:
+		pool, err := p.datastore.PoolGet()
+		if err != nil || pool.Namespace != namespace {
+			return nil
+		}
+		objects = append(objects, p.poolObject(ctx, pool))
+	case InferenceModelResource:
+		for _, model := range p.datastore.ModelGetAll() {
+			if model.Namespace == namespace {
+				objects = append(objects, modelObject(model))
+			}
+		}
+	}
+
+	matching := []object{}
+	for _, obj := range objects {
+		if selector.Matches(labels.Set(obj.labels)) {
+			matching = append(matching, obj)
+		}
+	}
+	return matching
+}
+
+func (p *Provider) poolObject(ctx context.Context, pool *v1alpha2.InferencePool) object {
+	pods := p.datastore.PodGetAll()
+	average := func(value func(*backendmetrics.MetricsState) float64) func() float64 {
+		return func() float64 {
+			if len(pods) == 0 {
+				return 0
+			}
+			total := float64(0)
+			for _, pod := range pods {
+				total += value(pod.GetMetrics())
+			}
+			return total / float64(len(pods))
+		}
+	}
+
+	return object{
+		ref:    ObjectReference{Kind: "InferencePool", Namespace: pool.Namespace, Name: pool.Name, APIVersion: v1alpha2.GroupVersion.String()},
+		labels: pool.Labels,
+		metrics: map[string]func() float64{
+			PoolSaturationMetric: func() float64 { return p.detector.Saturation(ctx) },
+			PoolAverageQueueSizeMetric: average(func(m *backendmetrics.MetricsState) float64 {
+				return float64(m.WaitingQueueSize)
+			}),
+			PoolAverageKVCacheUtilizationMetric: average(func(m *backendmetrics.MetricsState) float64 {
+				return m.KVCacheUsagePercent
+			}),
+			PoolReadyPodsMetric: func() float64 { return float64(len(pods)) },
+		},
+	}
+}
+
+func modelObject(model *v1alpha2.InferenceModel) object {
+	return object{
+		ref:    ObjectReference{Kind: "InferenceModel", Namespace: model.Namespace, Name: model.Name, APIVersion: v1alpha2.GroupVersion.String()},
+		labels: model.Labels,
+		metrics: map[string]func() float64{
+			ModelRunningRequestsMetric: func() float64 { return metrics.RunningRequests(model.Spec.ModelName) },
+		},
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custommetrics
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types below mirror the custom.metrics.k8s.io/v1beta2 API types of k8s.io/metrics, which are
+// all that is needed of that module to serve the API.
+
+const (
+	GroupName    = "custom.metrics.k8s.io"
+	Version      = "v1beta2"
+	GroupVersion = GroupName + "/" + Version
+)
+
+// MetricValueList is a list of values for a given metric for some set of objects.
+type MetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MetricValue `json:"items"`
+}
+
+// MetricValue is the metric value for some object.
+type MetricValue struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// DescribedObject is a reference to the described object.
+	DescribedObject ObjectReference `json:"describedObject"`
+
+	Metric MetricIdentifier `json:"metric"`
+
+	// Timestamp indicates the time at which the metrics were produced.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// WindowSeconds indicates the window ([Timestamp-Window, Timestamp]) from which these metrics
+	// were calculated, when returning rate metrics calculated from cumulative metrics (or zero for
+	// non-calculated instantaneous metrics).
+	WindowSeconds *int64 `json:"windowSeconds,omitempty"`
+
+	// Value is the value of the metric for this object.
+	Value resource.Quantity `json:"value"`
+}
+
+// MetricIdentifier identifies a metric by name and, optionally, selector.
+type MetricIdentifier struct {
+	// Name is the name of the given metric.
+	Name string `json:"name"`
+	// Selector represents the label selector that could be used to select this metric, and will
+	// generally just be the selector passed in to the query used to fetch this metric.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ObjectReference is the reference to the object a metric value describes.
+type ObjectReference struct {
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+}
@@ -21,7 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -56,6 +58,9 @@ type Datastore interface {
 	// InferenceModel operations
 	ModelSetIfOlder(infModel *v1alpha2.InferenceModel) bool
 	ModelGet(modelName string) *v1alpha2.InferenceModel
+	// ModelMatch returns the InferenceModel whose matches match the request with the given headers,
+	// including its path pseudo-header, or nil if none does.
+	ModelMatch(headers map[string]string) *v1alpha2.InferenceModel
 	ModelDelete(namespacedName types.NamespacedName) *v1alpha2.InferenceModel
 	ModelResync(ctx context.Context, ctrlClient client.Client, modelName string) (bool, error)
 	ModelGetAll() []*v1alpha2.InferenceModel
@@ -65,8 +70,23 @@ type Datastore interface {
 	PodGetAll() []backendmetrics.PodMetrics
 	// PodList lists pods matching the given predicate.
 	PodList(predicate func(backendmetrics.PodMetrics) bool) []backendmetrics.PodMetrics
+	// PodSnapshot returns an immutable snapshot of all the pods and their metrics. The snapshot is
+	// taken again only after the pods changed, so it's cheap to call on every request.
+	PodSnapshot() *backendmetrics.PodSnapshot
 	PodUpdateOrAddIfNotExist(pod *corev1.Pod) bool
 	PodDelete(namespacedName types.NamespacedName)
+	// PodSetCordoned cordons or uncordons a pod from routing, regardless of its cordon annotation.
+	// Returns false if the pod is not in the datastore.
+	PodSetCordoned(namespacedName types.NamespacedName, cordoned bool) bool
+	// PodRecordAdapterLoaded reflects an adapter that was loaded on a pod in its metrics right away,
+	// rather than after the next metrics refresh. Returns false if the pod is not in the datastore.
+	PodRecordAdapterLoaded(namespacedName types.NamespacedName, adapter string) bool
+	// EndpointsSet replaces the endpoints of the given source, which are kept as pods of the pool
+	// along with the pods of the cluster, and across the resyncs of the pool.
+	EndpointsSet(ctx context.Context, source string, endpoints []Endpoint)
+	// Subscribe returns a subscription to the changes of the pods and of their metrics, whose
+	// events are buffered up to the given size. The subscription must be closed once done.
+	Subscribe(bufferSize int) *Subscription
 
 	// Clears the store state, happens when the pool gets deleted.
 	Clear()
@@ -78,7 +98,9 @@ func NewDatastore(parentCtx context.Context, pmf *backendmetrics.PodMetricsFacto
 		poolAndModelsMu: sync.RWMutex{},
 		models:          make(map[string]*v1alpha2.InferenceModel),
 		pods:            &sync.Map{},
+		endpoints:       make(map[string][]Endpoint),
 		pmf:             pmf,
+		subscribers:     make(map[*Subscription]struct{}),
 	}
 	return store
 }
@@ -91,9 +113,29 @@ type datastore struct {
 	pool            *v1alpha2.InferencePool
 	// key: InferenceModel.Spec.ModelName, value: *InferenceModel
 	models map[string]*v1alpha2.InferenceModel
+	// key: the name of an EndpointSource, value: the endpoints it reported last. They're kept when
+	// the store is cleared, and added back when the pool is set again.
+	endpoints map[string][]Endpoint
 	// key: types.NamespacedName, value: backendmetrics.PodMetrics
 	pods *sync.Map
 	pmf  *backendmetrics.PodMetricsFactory
+
+	// podsEpoch is incremented whenever a pod is added or removed, or the pod or the metrics of a
+	// pod are replaced.
+	podsEpoch atomic.Uint64
+	// snapshotMu serializes taking the snapshot, so concurrent requests take it only once.
+	snapshotMu sync.Mutex
+	snapshot   atomic.Pointer[epochSnapshot]
+
+	// subscribersMu is used to synchronize access to the subscribers set.
+	subscribersMu sync.RWMutex
+	subscribers   map[*Subscription]struct{}
+}
+
+// epochSnapshot is a snapshot of the pods along with the epoch they were in when it was taken.
+type epochSnapshot struct {
+	epoch    uint64
+	snapshot *backendmetrics.PodSnapshot
 }
 
 func (ds *datastore) Clear() {
@@ -103,10 +145,13 @@ func (ds *datastore) Clear() {
 	ds.models = make(map[string]*v1alpha2.InferenceModel)
 	// stop all pods go routines before clearing the pods map.
 	ds.pods.Range(func(_, v any) bool {
-		v.(backendmetrics.PodMetrics).StopRefreshLoop()
+		pm := v.(backendmetrics.PodMetrics)
+		pm.StopRefreshLoop()
+		ds.publish(PodEvent{Type: PodDeleted, Pod: pm.GetPod(), Metrics: pm.GetMetrics()})
 		return true
 	})
 	ds.pods.Clear()
+	ds.invalidateSnapshot()
 }
 
 // /// InferencePool APIs ///
@@ -214,10 +259,64 @@ func (ds *datastore) ModelResync(ctx context.Context, c client.Client, modelName
 	return true, nil
 }
 
+// ModelGet returns the InferenceModel of the given model name. An InferenceModel whose model name
+// is exactly the given one takes precedence over the ones having it as an alias, the oldest of
+// which wins, and then over the ones whose model name is a matching pattern (see
+// IsModelNamePattern), among which the most specific pattern takes precedence.
 func (ds *datastore) ModelGet(modelName string) *v1alpha2.InferenceModel {
 	ds.poolAndModelsMu.RLock()
 	defer ds.poolAndModelsMu.RUnlock()
-	return ds.models[modelName]
+	if model, ok := ds.models[modelName]; ok {
+		return model
+	}
+
+	var aliased *v1alpha2.InferenceModel
+	for name, model := range ds.models {
+		if !IsModelNamePattern(name) && slices.Contains(model.Spec.Aliases, modelName) &&
+			(aliased == nil || olderModel(model, aliased)) {
+			aliased = model
+		}
+	}
+	if aliased != nil {
+		return aliased
+	}
+
+	var match *v1alpha2.InferenceModel
+	for pattern, model := range ds.models {
+		if IsModelNamePattern(pattern) && matchModelNamePattern(pattern, modelName) &&
+			(match == nil || moreSpecificModelPattern(model, match)) {
+			match = model
+		}
+	}
+	return match
+}
+
+// ModelMatch returns the InferenceModel whose matches match the request with the given headers.
+// The InferenceModel matching the longest path prefix, then the most headers, takes precedence,
+// and the oldest one wins ties. The matches of the InferenceModels whose model name is a pattern
+// are ignored.
+func (ds *datastore) ModelMatch(headers map[string]string) *v1alpha2.InferenceModel {
+	ds.poolAndModelsMu.RLock()
+	defer ds.poolAndModelsMu.RUnlock()
+	path := requestPath(headers)
+	var (
+		found     *v1alpha2.InferenceModel
+		foundBest modelMatch
+	)
+	for modelName, model := range ds.models {
+		if len(model.Spec.Matches) == 0 || IsModelNamePattern(modelName) {
+			continue
+		}
+		best, ok := matchModel(model, path, headers)
+		if !ok {
+			continue
+		}
+		if found == nil || best.moreSpecific(foundBest) ||
+			(!foundBest.moreSpecific(best) && olderModel(model, found)) {
+			found, foundBest = model, best
+		}
+	}
+	return found
 }
 
 func (ds *datastore) ModelDelete(namespacedName types.NamespacedName) *v1alpha2.InferenceModel {
@@ -262,6 +361,39 @@ func (ds *datastore) PodList(predicate func(backendmetrics.PodMetrics) bool) []b
 	return res
 }
 
+func (ds *datastore) PodSnapshot() *backendmetrics.PodSnapshot {
+	if cached := ds.snapshot.Load(); cached != nil && cached.epoch == ds.podsEpoch.Load() {
+		return cached.snapshot
+	}
+
+	ds.snapshotMu.Lock()
+	defer ds.snapshotMu.Unlock()
+	// The epoch is read before listing the pods, so the snapshot is at least as recent as the epoch.
+	epoch := ds.podsEpoch.Load()
+	if cached := ds.snapshot.Load(); cached != nil && cached.epoch == epoch {
+		return cached.snapshot
+	}
+	snapshot := backendmetrics.NewPodSnapshot(ds.PodGetAll())
+	ds.snapshot.Store(&epochSnapshot{epoch: epoch, snapshot: snapshot})
+	return snapshot
+}
+
+// PodChanged invalidates the snapshot of the pods and notifies the subscribers. It's called by the
+// pod metrics after they replace their pod or metrics.
+func (ds *datastore) PodChanged(pm backendmetrics.PodMetrics) {
+	ds.invalidateSnapshot()
+	// A pod that is not stored yet is notified once it's added, and a pod that was deleted may
+	// still refresh its metrics once, which is not notified after its deletion.
+	pod := pm.GetPod()
+	if current, ok := ds.pods.Load(pod.NamespacedName); ok && current == pm {
+		ds.publish(PodEvent{Type: PodUpdated, Pod: pod, Metrics: pm.GetMetrics()})
+	}
+}
+
+func (ds *datastore) invalidateSnapshot() {
+	ds.podsEpoch.Add(1)
+}
+
 func (ds *datastore) PodUpdateOrAddIfNotExist(pod *corev1.Pod) bool {
 	namespacedName := types.NamespacedName{
 		Name:      pod.Name,
@@ -272,10 +404,12 @@ func (ds *datastore) PodUpdateOrAddIfNotExist(pod *corev1.Pod) bool {
 	if !ok {
 		pm = ds.pmf.NewPodMetrics(ds.parentCtx, pod, ds)
 		ds.pods.Store(namespacedName, pm)
+		ds.invalidateSnapshot()
 	} else {
 		pm = existing.(backendmetrics.PodMetrics)
 	}
-	// Update pod properties if anything changed.
+	// Update pod properties if anything changed. This notifies the subscribers of the pod, including
+	// when it was just added.
 	pm.UpdatePod(pod)
 	return ok
 }
@@ -283,11 +417,31 @@ func (ds *datastore) PodUpdateOrAddIfNotExist(pod *corev1.Pod) bool {
 func (ds *datastore) PodDelete(namespacedName types.NamespacedName) {
 	v, ok := ds.pods.LoadAndDelete(namespacedName)
 	if ok {
+		ds.invalidateSnapshot()
 		pmr := v.(backendmetrics.PodMetrics)
 		pmr.StopRefreshLoop()
+		ds.publish(PodEvent{Type: PodDeleted, Pod: pmr.GetPod(), Metrics: pmr.GetMetrics()})
 	}
 }
 
+func (ds *datastore) PodSetCordoned(namespacedName types.NamespacedName, cordoned bool) bool {
+	v, ok := ds.pods.Load(namespacedName)
+	if !ok {
+		return false
+	}
+	v.(backendmetrics.PodMetrics).SetCordoned(cordoned)
+	return true
+}
+
+func (ds *datastore) PodRecordAdapterLoaded(namespacedName types.NamespacedName, adapter string) bool {
+	v, ok := ds.pods.Load(namespacedName)
+	if !ok {
+		return false
+	}
+	v.(backendmetrics.PodMetrics).RecordAdapterLoaded(adapter)
+	return true
+}
+
 func (ds *datastore) podResyncAll(ctx context.Context, ctrlClient client.Client) error {
 	logger := log.FromContext(ctx)
 	podList := &corev1.PodList{}
@@ -315,6 +469,9 @@ func (ds *datastore) podResyncAll(ctx context.Context, ctrlClient client.Client)
 	// Remove pods that don't belong to the pool or not ready any more.
 	ds.pods.Range(func(k, v any) bool {
 		pm := v.(backendmetrics.PodMetrics)
+		if _, ok := pm.GetPod().Labels[EndpointSourceLabel]; ok {
+			return true
+		}
 		if exist := activePods[pm.GetPod().NamespacedName.Name]; !exist {
 			logger.V(logutil.VERBOSE).Info("Removing pod", "pod", pm.GetPod())
 			ds.PodDelete(pm.GetPod().NamespacedName)
@@ -322,6 +479,9 @@ func (ds *datastore) podResyncAll(ctx context.Context, ctrlClient client.Client)
 		return true
 	})
 
+	for source, endpoints := range ds.endpoints {
+		ds.endpointsSync(source, endpoints)
+	}
 	return nil
 }
 
@@ -30,9 +30,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	podutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/pod"
 	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
 )
 
@@ -271,6 +273,136 @@ var (
 	}
 )
 
+func TestModelGetPattern(t *testing.T) {
+	exact := testutil.MakeInferenceModel("exact").
+		CreationTimestamp(metav1.Unix(1003, 0)).
+		ModelName("llama-3.1-8b").ObjRef()
+	family := testutil.MakeInferenceModel("family").
+		CreationTimestamp(metav1.Unix(1002, 0)).
+		ModelName("llama-3.1-*").ObjRef()
+	llama := testutil.MakeInferenceModel("llama").
+		CreationTimestamp(metav1.Unix(1001, 0)).
+		ModelName("llama-*").ObjRef()
+	// As specific as family, but older.
+	sqlAdapters := testutil.MakeInferenceModel("sql-adapters").
+		CreationTimestamp(metav1.Unix(1000, 0)).
+		ModelName("llama*b-sql").ObjRef()
+
+	ds := NewDatastore(context.Background(), nil)
+	for _, model := range []*v1alpha2.InferenceModel{exact, family, llama, sqlAdapters} {
+		ds.ModelSetIfOlder(model)
+	}
+
+	tests := []struct {
+		modelName string
+		want      *v1alpha2.InferenceModel
+	}{
+		{modelName: "llama-3.1-8b", want: exact},
+		{modelName: "llama-3.1-70b", want: family},
+		{modelName: "llama-3.1-", want: family},
+		{modelName: "llama-3.2-1b", want: llama},
+		{modelName: "llama-3.1-8b-sql", want: sqlAdapters},
+		{modelName: "mistral-7b"},
+		{modelName: "llama"},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, ds.ModelGet(test.modelName)); diff != "" {
+			t.Errorf("Unexpected InferenceModel for %q (-want +got): %v", test.modelName, diff)
+		}
+	}
+}
+
+func TestModelGetAlias(t *testing.T) {
+	exact := testutil.MakeInferenceModel("exact").
+		CreationTimestamp(metav1.Unix(1003, 0)).
+		ModelName("gpt-4o").ObjRef()
+	local := testutil.MakeInferenceModel("local").
+		CreationTimestamp(metav1.Unix(1001, 0)).
+		ModelName("/models/llama-3.1-8b").
+		Aliases("gpt-4o-mini", "gpt-4o").ObjRef()
+	// Shares an alias with local, but is newer.
+	newer := testutil.MakeInferenceModel("newer").
+		CreationTimestamp(metav1.Unix(1002, 0)).
+		ModelName("/models/qwen-2.5-7b").
+		Aliases("gpt-4o-mini", "gpt-3.5-turbo").ObjRef()
+	// The aliases of a pattern are ignored.
+	family := testutil.MakeInferenceModel("family").
+		CreationTimestamp(metav1.Unix(1000, 0)).
+		ModelName("gpt-*").
+		Aliases("claude").ObjRef()
+
+	ds := NewDatastore(context.Background(), nil)
+	for _, model := range []*v1alpha2.InferenceModel{exact, local, newer, family} {
+		ds.ModelSetIfOlder(model)
+	}
+
+	tests := []struct {
+		modelName string
+		want      *v1alpha2.InferenceModel
+	}{
+		{modelName: "gpt-4o", want: exact},
+		{modelName: "gpt-4o-mini", want: local},
+		{modelName: "gpt-3.5-turbo", want: newer},
+		{modelName: "/models/llama-3.1-8b", want: local},
+		{modelName: "gpt-4", want: family},
+		{modelName: "claude"},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, ds.ModelGet(test.modelName)); diff != "" {
+			t.Errorf("Unexpected InferenceModel for %q (-want +got): %v", test.modelName, diff)
+		}
+	}
+}
+
+func TestModelMatch(t *testing.T) {
+	header := func(name, value string) []v1alpha2.HeaderMatch {
+		return []v1alpha2.HeaderMatch{{Name: name, Value: value}}
+	}
+	foodReview := testutil.MakeInferenceModel("food-review").
+		CreationTimestamp(metav1.Unix(1000, 0)).
+		ModelName("food-review").
+		Match(v1alpha2.ModelMatch{PathPrefix: ptr.To("/food-review")}).
+		Match(v1alpha2.ModelMatch{Headers: header("X-Model-Alias", "food")}).ObjRef()
+	foodReviewCanary := testutil.MakeInferenceModel("food-review-canary").
+		CreationTimestamp(metav1.Unix(1001, 0)).
+		ModelName("food-review-canary").
+		Match(v1alpha2.ModelMatch{PathPrefix: ptr.To("/food-review/"), Headers: header("x-canary", "true")}).ObjRef()
+	// Matches as specific as food-review's, but newer.
+	newer := testutil.MakeInferenceModel("newer").
+		CreationTimestamp(metav1.Unix(1002, 0)).
+		ModelName("newer").
+		Match(v1alpha2.ModelMatch{Headers: header("x-model-alias", "food")}).ObjRef()
+	// The matches of a pattern are ignored.
+	pattern := testutil.MakeInferenceModel("pattern").
+		CreationTimestamp(metav1.Unix(999, 0)).
+		ModelName("food-*").
+		Match(v1alpha2.ModelMatch{PathPrefix: ptr.To("/")}).ObjRef()
+
+	ds := NewDatastore(context.Background(), nil)
+	for _, model := range []*v1alpha2.InferenceModel{foodReview, foodReviewCanary, newer, pattern} {
+		ds.ModelSetIfOlder(model)
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    *v1alpha2.InferenceModel
+	}{
+		{name: "path prefix", headers: map[string]string{":path": "/food-review/v1/completions"}, want: foodReview},
+		{name: "path equal to the prefix", headers: map[string]string{":path": "/food-review?stream=true"}, want: foodReview},
+		{name: "path not starting with the prefix elements", headers: map[string]string{":path": "/food-reviews/v1/completions"}},
+		{name: "header", headers: map[string]string{":path": "/v1/completions", "x-model-alias": "food"}, want: foodReview},
+		{name: "header value mismatch", headers: map[string]string{":path": "/v1/completions", "x-model-alias": "Food"}},
+		{name: "longest path prefix", headers: map[string]string{":path": "/food-review/v1/completions", "x-canary": "true"}, want: foodReviewCanary},
+		{name: "no match", headers: map[string]string{":path": "/v1/completions"}},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, ds.ModelMatch(test.headers)); diff != "" {
+			t.Errorf("Unexpected InferenceModel for %s (-want +got): %v", test.name, diff)
+		}
+	}
+}
+
 func TestMetrics(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -446,3 +578,118 @@ func TestPods(t *testing.T) {
 		})
 	}
 }
+
+func TestPodSetCordoned(t *testing.T) {
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := NewDatastore(t.Context(), pmf)
+	ds.PodUpdateOrAddIfNotExist(pod1)
+
+	isCordoned := func() bool {
+		pods := ds.PodGetAll()
+		assert.Len(t, pods, 1)
+		return pods[0].GetPod().Cordoned
+	}
+	assert.False(t, isCordoned())
+
+	// Cordoning by the admin API survives pod updates.
+	assert.True(t, ds.PodSetCordoned(pod1NamespacedName, true))
+	ds.PodUpdateOrAddIfNotExist(pod1)
+	assert.True(t, isCordoned())
+	assert.True(t, ds.PodSetCordoned(pod1NamespacedName, false))
+	assert.False(t, isCordoned())
+
+	// The cordon annotation is honored, and not overridden by uncordoning through the admin API.
+	annotated := pod1.DeepCopy()
+	annotated.Annotations = map[string]string{podutil.CordonAnnotationKey: "true"}
+	ds.PodUpdateOrAddIfNotExist(annotated)
+	assert.True(t, isCordoned())
+	assert.True(t, ds.PodSetCordoned(pod1NamespacedName, false))
+	assert.True(t, isCordoned())
+	ds.PodUpdateOrAddIfNotExist(pod1)
+	assert.False(t, isCordoned())
+
+	assert.False(t, ds.PodSetCordoned(pod2NamespacedName, true))
+}
+
+func TestPodSnapshot(t *testing.T) {
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := NewDatastore(t.Context(), pmf)
+	ds.PodUpdateOrAddIfNotExist(pod1)
+
+	first := ds.PodSnapshot()
+	assert.Len(t, first.Pods, 1)
+	assert.Same(t, first, ds.PodSnapshot(), "the snapshot is reused while the pods don't change")
+
+	ds.PodUpdateOrAddIfNotExist(pod2)
+	second := ds.PodSnapshot()
+	assert.Len(t, second.Pods, 2)
+	assert.Greater(t, second.Generation, first.Generation)
+	assert.Len(t, first.Pods, 1, "a snapshot is not modified when the pods change")
+
+	assert.True(t, ds.PodRecordAdapterLoaded(pod1NamespacedName, "sql-lora"))
+	third := ds.PodSnapshot()
+	assert.Greater(t, third.Generation, second.Generation)
+	for _, pod := range third.Pods {
+		if pod.Pod.NamespacedName == pod1NamespacedName {
+			assert.Contains(t, pod.Metrics.ActiveModels, "sql-lora")
+		}
+	}
+	for _, pod := range second.Pods {
+		assert.NotContains(t, pod.Metrics.ActiveModels, "sql-lora")
+	}
+
+	assert.True(t, ds.PodSetCordoned(pod2NamespacedName, true))
+	assert.NotSame(t, third, ds.PodSnapshot())
+
+	ds.PodDelete(pod1NamespacedName)
+	assert.Len(t, ds.PodSnapshot().Pods, 1)
+}
+
+func TestSubscribe(t *testing.T) {
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Hour)
+	ds := NewDatastore(t.Context(), pmf)
+	sub := ds.Subscribe(4)
+
+	next := func() PodEvent {
+		select {
+		case event := <-sub.Events():
+			return event
+		default:
+			t.Fatal("no event delivered")
+			return PodEvent{}
+		}
+	}
+
+	ds.PodUpdateOrAddIfNotExist(pod1)
+	event := next()
+	assert.Equal(t, PodUpdated, event.Type)
+	assert.Equal(t, pod1NamespacedName, event.Pod.NamespacedName)
+	assert.NotNil(t, event.Metrics)
+
+	assert.True(t, ds.PodRecordAdapterLoaded(pod1NamespacedName, "sql-lora"))
+	event = next()
+	assert.Equal(t, PodUpdated, event.Type)
+	assert.Contains(t, event.Metrics.ActiveModels, "sql-lora")
+
+	ds.PodDelete(pod1NamespacedName)
+	event = next()
+	assert.Equal(t, PodDeleted, event.Type)
+	assert.Equal(t, pod1NamespacedName, event.Pod.NamespacedName)
+
+	// A slow subscriber doesn't block the datastore, its events are dropped instead.
+	for range 3 {
+		ds.PodUpdateOrAddIfNotExist(pod1)
+		ds.PodDelete(pod1NamespacedName)
+	}
+	assert.Len(t, sub.Events(), 4)
+	assert.Equal(t, uint64(2), sub.Dropped())
+
+	sub.Close()
+	sub.Close()
+	ds.PodUpdateOrAddIfNotExist(pod1)
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, 4, received, "no event is delivered after the subscription is closed")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"maps"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	podutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/pod"
+)
+
+// EndpointSourceLabel is the label of the pods of the datastore added from an EndpointSource,
+// holding the name of the source. The pods watched in the cluster don't have it.
+const EndpointSourceLabel = "inference.networking.x-k8s.io/endpoint-source"
+
+// Endpoint is a model server reported by an EndpointSource, e.g. a VM or a pod of another
+// cluster. It's added to the datastore as a pod of the pool.
+type Endpoint struct {
+	// Name identifies the endpoint in its source.
+	Name string `json:"name"`
+	// Address is the IP address of the endpoint.
+	Address string `json:"address"`
+	// Port is the port the endpoint serves on, defaulting to the target port of the pool.
+	Port int32 `json:"port,omitempty"`
+	// Labels are the labels of the endpoint, e.g. its role, as the labels of a pod.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are the annotations of the endpoint, e.g. its max concurrency, as the
+	// annotations of a pod.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// EndpointSource discovers the model servers of the pool outside of the pods of the cluster,
+// e.g. from a static configuration, the EndpointSlices of another cluster or a service discovery
+// API.
+type EndpointSource interface {
+	// Name identifies the source. The pods of its endpoints are named <source>/<endpoint>.
+	Name() string
+	// Run reports the endpoints of the source until ctx is done. Each call to update replaces the
+	// endpoints previously reported.
+	Run(ctx context.Context, update func([]Endpoint)) error
+}
+
+// RunEndpointSource runs the given source, keeping its endpoints in the given datastore.
+func RunEndpointSource(ctx context.Context, ds Datastore, source EndpointSource) error {
+	return source.Run(ctx, func(endpoints []Endpoint) {
+		ds.EndpointsSet(ctx, source.Name(), endpoints)
+	})
+}
+
+func (ds *datastore) EndpointsSet(ctx context.Context, source string, endpoints []Endpoint) {
+	ds.poolAndModelsMu.Lock()
+	defer ds.poolAndModelsMu.Unlock()
+	ds.endpoints[source] = endpoints
+	log.FromContext(ctx).V(logutil.DEFAULT).Info("Endpoints updated", "source", source, "count", len(endpoints))
+	if ds.pool != nil {
+		ds.endpointsSync(source, endpoints)
+	}
+}
+
+// endpointsSync adds or updates the pods of the given endpoints of a source, and deletes the
+// pods of the source whose endpoints are gone. It's called with poolAndModelsMu held and the pool set.
+func (ds *datastore) endpointsSync(source string, endpoints []Endpoint) {
+	active := make(map[types.NamespacedName]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		pod := endpointPod(ds.pool.Namespace, source, endpoint)
+		active[types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}] = true
+		ds.PodUpdateOrAddIfNotExist(pod)
+	}
+	ds.pods.Range(func(k, v any) bool {
+		pod := v.(backendmetrics.PodMetrics).GetPod()
+		if pod.Labels[EndpointSourceLabel] == source && !active[pod.NamespacedName] {
+			ds.PodDelete(pod.NamespacedName)
+		}
+		return true
+	})
+}
+
+// endpointPod returns the pod standing for the given endpoint of a source in the pool of the
+// given namespace.
+func endpointPod(namespace, source string, endpoint Endpoint) *corev1.Pod {
+	labels := maps.Clone(endpoint.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[EndpointSourceLabel] = source
+	annotations := maps.Clone(endpoint.Annotations)
+	if endpoint.Port != 0 {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[podutil.TargetPortAnnotationKey] = strconv.Itoa(int(endpoint.Port))
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source + "/" + endpoint.Name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Status: corev1.PodStatus{PodIP: endpoint.Address},
+	}
+}
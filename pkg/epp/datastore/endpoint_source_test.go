@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+func TestEndpointsSet(t *testing.T) {
+	selector := map[string]string{"app": "vllm"}
+	pool := testutil.MakeInferencePool("pool").Namespace("default").Selector(selector).TargetPortNumber(8080).ObjRef()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", Labels: selector},
+		Status: corev1.PodStatus{
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ctx := context.Background()
+	ds := NewDatastore(ctx, pmf)
+
+	// The endpoints are kept until the pool is set.
+	ds.EndpointsSet(ctx, "file", []Endpoint{
+		{Name: "vm-1", Address: "10.1.0.1", Port: 8000, Labels: map[string]string{"role": "decode"}},
+		{Name: "vm-2", Address: "10.1.0.2"},
+	})
+	if got := len(ds.PodGetAll()); got != 0 {
+		t.Errorf("Unexpected number of pods before the pool is set: got %d, want 0", got)
+	}
+
+	check := func(want map[string]string) {
+		t.Helper()
+		got := map[string]string{}
+		for _, pm := range ds.PodGetAll() {
+			pod := pm.GetPod()
+			got[pod.NamespacedName.String()] = pod.Address + ":" + strconv.Itoa(int(pod.TargetPort(pool.Spec.TargetPortNumber)))
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Unexpected pods (-want +got): %s", diff)
+		}
+	}
+
+	// The endpoints are added along with the pods of the pool.
+	if err := ds.PoolSet(ctx, fakeClient, pool); err != nil {
+		t.Fatalf("Unexpected PoolSet error: %v", err)
+	}
+	check(map[string]string{
+		"default/pod":       "10.0.0.1:8080",
+		"default/file/vm-1": "10.1.0.1:8000",
+		"default/file/vm-2": "10.1.0.2:8080",
+	})
+	for _, pm := range ds.PodGetAll() {
+		if pm.GetPod().NamespacedName.Name == "file/vm-1" {
+			want := map[string]string{"role": "decode", EndpointSourceLabel: "file"}
+			if diff := cmp.Diff(want, pm.GetPod().Labels); diff != "" {
+				t.Errorf("Unexpected labels (-want +got): %s", diff)
+			}
+		}
+	}
+
+	// The endpoints of a source replace its previous endpoints only.
+	ds.EndpointsSet(ctx, "file", []Endpoint{{Name: "vm-2", Address: "10.1.0.3"}})
+	ds.EndpointsSet(ctx, "endpointslice", []Endpoint{{Name: "remote", Address: "10.2.0.1"}})
+	check(map[string]string{
+		"default/pod":                  "10.0.0.1:8080",
+		"default/file/vm-2":            "10.1.0.3:8080",
+		"default/endpointslice/remote": "10.2.0.1:8080",
+	})
+
+	// The endpoints survive the resyncs of the pool.
+	updated := pool.DeepCopy()
+	updated.Spec.Selector = map[v1alpha2.LabelKey]v1alpha2.LabelValue{"app": "other"}
+	if err := ds.PoolSet(ctx, fakeClient, updated); err != nil {
+		t.Fatalf("Unexpected PoolSet error: %v", err)
+	}
+	check(map[string]string{
+		"default/file/vm-2":            "10.1.0.3:8080",
+		"default/endpointslice/remote": "10.2.0.1:8080",
+	})
+
+	// The endpoints are added back when the pool is set again after it was deleted.
+	ds.Clear()
+	check(map[string]string{})
+	ds.EndpointsSet(ctx, "endpointslice", nil)
+	if err := ds.PoolSet(ctx, fakeClient, pool); err != nil {
+		t.Fatalf("Unexpected PoolSet error: %v", err)
+	}
+	check(map[string]string{
+		"default/pod":       "10.0.0.1:8080",
+		"default/file/vm-2": "10.1.0.3:8080",
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"strings"
+
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+)
+
+// pathHeader is the pseudo-header carrying the path of the request.
+const pathHeader = ":path"
+
+// modelMatch is how specific the most specific match of an InferenceModel matching a request is.
+type modelMatch struct {
+	pathPrefix int // the length of the path prefix, 0 if it isn't set
+	headers    int // the number of headers
+}
+
+// matchModel returns whether any of the matches of the given InferenceModel matches the request
+// with the given path and headers, and the most specific matching one.
+func matchModel(model *v1alpha2.InferenceModel, path string, headers map[string]string) (modelMatch, bool) {
+	var best modelMatch
+	matched := false
+	for _, match := range model.Spec.Matches {
+		if !matchRequest(match, path, headers) {
+			continue
+		}
+		current := modelMatch{headers: len(match.Headers)}
+		if match.PathPrefix != nil {
+			current.pathPrefix = len(*match.PathPrefix)
+		}
+		if !matched || current.moreSpecific(best) {
+			best, matched = current, true
+		}
+	}
+	return best, matched
+}
+
+// matchRequest returns whether the request with the given path and headers matches all the set
+// conditions of the given match.
+func matchRequest(match v1alpha2.ModelMatch, path string, headers map[string]string) bool {
+	if match.PathPrefix == nil && len(match.Headers) == 0 {
+		return false
+	}
+	if match.PathPrefix != nil && !matchPathPrefix(*match.PathPrefix, path) {
+		return false
+	}
+	for _, header := range match.Headers {
+		if value, ok := headers[strings.ToLower(header.Name)]; !ok || value != header.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPathPrefix returns whether the given path starts with the path elements of the given prefix.
+func matchPathPrefix(prefix, path string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	rest := path[len(prefix):]
+	return rest == "" || rest[0] == '/'
+}
+
+// requestPath returns the path of the request with the given headers, without its query.
+func requestPath(headers map[string]string) string {
+	path, _, _ := strings.Cut(headers[pathHeader], "?")
+	return path
+}
+
+// moreSpecific returns whether m takes precedence over other: the longest path prefix wins, then
+// the most headers.
+func (m modelMatch) moreSpecific(other modelMatch) bool {
+	if m.pathPrefix != other.pathPrefix {
+		return m.pathPrefix > other.pathPrefix
+	}
+	return m.headers > other.headers
+}
+
+// olderModel returns whether a is older than b, based on creation timestamp, and on the model
+// name if they were created at the same time.
+func olderModel(a, b *v1alpha2.InferenceModel) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Spec.ModelName < b.Spec.ModelName
+}
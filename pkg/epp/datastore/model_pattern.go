@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"strings"
+
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+)
+
+// ModelNameWildcard is the wildcard of the InferenceModel model name patterns, matching any
+// sequence of characters (including none), e.g. "llama-3.1-*" matches "llama-3.1-8b-sql".
+const ModelNameWildcard = "*"
+
+// IsModelNamePattern returns whether the given InferenceModel model name is a pattern.
+func IsModelNamePattern(modelName string) bool {
+	return strings.Contains(modelName, ModelNameWildcard)
+}
+
+// matchModelNamePattern returns whether the given model name matches the given pattern.
+func matchModelNamePattern(pattern, modelName string) bool {
+	parts := strings.Split(pattern, ModelNameWildcard)
+	if len(parts) == 1 {
+		return pattern == modelName
+	}
+	// The first part is a prefix and the last part a suffix, and the parts in between are found in
+	// order, as early as possible, in what's left.
+	first, last := parts[0], parts[len(parts)-1]
+	if len(modelName) < len(first)+len(last) || !strings.HasPrefix(modelName, first) || !strings.HasSuffix(modelName, last) {
+		return false
+	}
+	rest := modelName[len(first) : len(modelName)-len(last)]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return true
+}
+
+// moreSpecificModelPattern returns whether the model name pattern of a takes precedence over the
+// one of b: the pattern with the most literal characters is the most specific, and the oldest
+// InferenceModel wins ties.
+func moreSpecificModelPattern(a, b *v1alpha2.InferenceModel) bool {
+	aLiterals := len(a.Spec.ModelName) - strings.Count(a.Spec.ModelName, ModelNameWildcard)
+	bLiterals := len(b.Spec.ModelName) - strings.Count(b.Spec.ModelName, ModelNameWildcard)
+	if aLiterals != bLiterals {
+		return aLiterals > bLiterals
+	}
+	return olderModel(a, b)
+}
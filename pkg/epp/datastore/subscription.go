@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+)
+
+// DefaultSubscriptionBufferSize is the number of events buffered for a subscriber when the
+// requested buffer size is not positive.
+const DefaultSubscriptionBufferSize = 64
+
+// PodEventType is the type of a change of a pod.
+type PodEventType string
+
+const (
+	// PodUpdated is the type of the events of a pod that was added, or whose pod or metrics were
+	// replaced.
+	PodUpdated PodEventType = "Updated"
+	// PodDeleted is the type of the events of a pod that was removed from the datastore.
+	PodDeleted PodEventType = "Deleted"
+)
+
+// PodEvent is a change of a pod of the datastore. The pod and metrics are the ones of the pod at
+// the time of the change, and must not be modified.
+type PodEvent struct {
+	Type    PodEventType
+	Pod     *backend.Pod
+	Metrics *backendmetrics.MetricsState
+}
+
+// Subscription delivers the changes of the pods of a datastore to a subscriber. The events are
+// buffered, and the ones that don't fit in the buffer because the subscriber is too slow to
+// receive them are dropped rather than blocking the datastore, so a subscriber that needs the
+// full state should read it from the datastore again after Dropped increased.
+type Subscription struct {
+	events  chan PodEvent
+	dropped atomic.Uint64
+
+	// mu serializes the sends with the closing of the events channel.
+	mu     sync.RWMutex
+	closed bool
+	ds     *datastore
+}
+
+// Events returns the channel of the events, which is closed once the subscription is closed.
+func (s *Subscription) Events() <-chan PodEvent {
+	return s.events
+}
+
+// Dropped returns the number of events dropped since the subscription was made.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the delivery of the events and closes the events channel. It's safe to call more
+// than once.
+func (s *Subscription) Close() {
+	s.ds.unsubscribe(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// send delivers the event if the buffer has room for it, and drops it otherwise.
+func (s *Subscription) send(event PodEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+func (ds *datastore) Subscribe(bufferSize int) *Subscription {
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriptionBufferSize
+	}
+	sub := &Subscription{events: make(chan PodEvent, bufferSize), ds: ds}
+	ds.subscribersMu.Lock()
+	defer ds.subscribersMu.Unlock()
+	ds.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (ds *datastore) unsubscribe(sub *Subscription) {
+	ds.subscribersMu.Lock()
+	defer ds.subscribersMu.Unlock()
+	delete(ds.subscribers, sub)
+}
+
+// publish delivers the event to all the subscribers, without waiting for any of them.
+func (ds *datastore) publish(event PodEvent) {
+	ds.subscribersMu.RLock()
+	defer ds.subscribersMu.RUnlock()
+	for sub := range ds.subscribers {
+		sub.send(event)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debugstream streams the state of the pods and the scheduling decisions in real time, to
+// power a live dashboard for demos and incident response.
+package debugstream
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+)
+
+const (
+	// DefaultInterval is the default interval the pods snapshot is diffed at.
+	DefaultInterval = time.Second
+	// subscriberBuffer is the number of events buffered for a subscriber. Events are dropped for
+	// subscribers that fall further behind, so a slow dashboard never slows down the scheduling.
+	subscriberBuffer = 256
+)
+
+const (
+	EventTypeSnapshot = "snapshot"
+	EventTypeDecision = "decision"
+)
+
+// Event is a streamed event: either a snapshot diff or a scheduling decision.
+type Event struct {
+	Type     string        `json:"type"`
+	Time     time.Time     `json:"time"`
+	Snapshot *SnapshotDiff `json:"snapshot,omitempty"`
+	Decision *Decision     `json:"decision,omitempty"`
+}
+
+// PodState is the streamed state of a pod.
+type PodState struct {
+	Name                string   `json:"name"`
+	Address             string   `json:"address"`
+	Cordoned            bool     `json:"cordoned,omitempty"`
+	WaitingQueueSize    int      `json:"waitingQueueSize"`
+	RunningQueueSize    int      `json:"runningQueueSize"`
+	KVCacheUsagePercent float64  `json:"kvCacheUsagePercent"`
+	ActiveModels        []string `json:"activeModels,omitempty"`
+}
+
+// SnapshotDiff holds the pods that were added or changed, and the names of the pods that were
+// removed since the previous snapshot. The first snapshot sent to a subscriber holds all the pods.
+type SnapshotDiff struct {
+	Updated []PodState `json:"updated,omitempty"`
+	Removed []string   `json:"removed,omitempty"`
+}
+
+// Decision is a streamed scheduling decision.
+type Decision struct {
+	RequestID   string `json:"requestId"`
+	TargetModel string `json:"targetModel"`
+	TargetPod   string `json:"targetPod"`
+	// Scores holds the raw score each scorer gave to each candidate pod, keyed by scorer then pod.
+	Scores map[string]map[string]float64 `json:"scores,omitempty"`
+	// Weights holds the weight of each scorer.
+	Weights map[string]int `json:"weights,omitempty"`
+}
+
+// Datastore is the source of the pods snapshot.
+type Datastore interface {
+	PodGetAll() []backendmetrics.PodMetrics
+}
+
+// Hub diffs the pods snapshot periodically and broadcasts the diffs, along with the published
+// scheduling decisions, to its subscribers. Nothing is computed while there are no subscribers.
+type Hub struct {
+	datastore Datastore
+	interval  time.Duration
+
+	mu          sync.Mutex
+	pods        map[string]PodState // the last snapshot, key: pod name
+	subscribers map[chan Event]bool
+}
+
+// NewHub initializes a new Hub and returns its pointer.
+func NewHub(datastore Datastore, interval time.Duration) *Hub {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Hub{
+		datastore:   datastore,
+		interval:    interval,
+		pods:        map[string]PodState{},
+		subscribers: map[chan Event]bool{},
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica serves its own stream.
+func (h *Hub) NeedLeaderElection() bool {
+	return false
+}
+
+// Start diffs the pods snapshot every interval until the context is done.
+func (h *Hub) Start(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if h.hasSubscribers() {
+				h.refreshSnapshot()
+			}
+		}
+	}
+}
+
+// Subscribe registers a new subscriber and returns its events, starting with a full snapshot, and
+// a function to unsubscribe.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	h.refreshSnapshot()
+
+	events := make(chan Event, subscriberBuffer)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	full := &SnapshotDiff{Updated: make([]PodState, 0, len(h.pods))}
+	for _, pod := range h.pods {
+		full.Updated = append(full.Updated, pod)
+	}
+	sort.Slice(full.Updated, func(i, j int) bool { return full.Updated[i].Name < full.Updated[j].Name })
+	events <- Event{Type: EventTypeSnapshot, Time: time.Now(), Snapshot: full}
+	h.subscribers[events] = true
+
+	return events, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.subscribers[events] {
+			delete(h.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// PublishDecision broadcasts the given scheduling decision.
+func (h *Hub) PublishDecision(decision *Decision) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.broadcast(Event{Type: EventTypeDecision, Time: time.Now(), Decision: decision})
+}
+
+// hasSubscribers returns true if at least one subscriber is registered.
+func (h *Hub) hasSubscribers() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers) > 0
+}
+
+// refreshSnapshot diffs the current pods against the last snapshot and broadcasts the diff.
+func (h *Hub) refreshSnapshot() {
+	current := map[string]PodState{}
+	for _, pm := range h.datastore.PodGetAll() {
+		state := toPodState(pm)
+		current[state.Name] = state
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	diff := &SnapshotDiff{}
+	for name, state := range current {
+		if previous, ok := h.pods[name]; !ok || !reflect.DeepEqual(previous, state) {
+			diff.Updated = append(diff.Updated, state)
+		}
+	}
+	for name := range h.pods {
+		if _, ok := current[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	h.pods = current
+	if len(diff.Updated) == 0 && len(diff.Removed) == 0 {
+		return
+	}
+	sort.Slice(diff.Updated, func(i, j int) bool { return diff.Updated[i].Name < diff.Updated[j].Name })
+	sort.Strings(diff.Removed)
+	h.broadcast(Event{Type: EventTypeSnapshot, Time: time.Now(), Snapshot: diff})
+}
+
+// broadcast must be called with the lock held.
+func (h *Hub) broadcast(event Event) {
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- event:
+		default: // the subscriber fell behind, drop the event
+		}
+	}
+}
+
+func toPodState(pm backendmetrics.PodMetrics) PodState {
+	pod := pm.GetPod()
+	state := PodState{
+		Name:     pod.NamespacedName.String(),
+		Address:  pod.Address,
+		Cordoned: pod.Cordoned,
+	}
+	if metrics := pm.GetMetrics(); metrics != nil {
+		state.WaitingQueueSize = metrics.WaitingQueueSize
+		state.RunningQueueSize = metrics.RunningQueueSize
+		state.KVCacheUsagePercent = metrics.KVCacheUsagePercent
+		for model := range metrics.ActiveModels {
+			state.ActiveModels = append(state.ActiveModels, model)
+		}
+		sort.Strings(state.ActiveModels)
+	}
+	return state
+}
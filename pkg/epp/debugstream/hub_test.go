@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugstream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+type fakeDatastore struct {
+	mu   sync.Mutex
+	pods []backendmetrics.PodMetrics
+}
+
+func (f *fakeDatastore) PodGetAll() []backendmetrics.PodMetrics {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pods
+}
+
+func (f *fakeDatastore) setPods(pods ...backendmetrics.PodMetrics) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pods = pods
+}
+
+func newPod(name string, waitingQueueSize int) *backendmetrics.FakePodMetrics {
+	return &backendmetrics.FakePodMetrics{
+		Pod:     &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name, Namespace: "default"}},
+		Metrics: &backendmetrics.MetricsState{WaitingQueueSize: waitingQueueSize, ActiveModels: map[string]int{"foo": 1}},
+	}
+}
+
+func receive(t *testing.T, events <-chan Event) Event {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for an event")
+		return Event{}
+	}
+}
+
+func TestHub(t *testing.T) {
+	ds := &fakeDatastore{}
+	ds.setPods(newPod("pod1", 0), newPod("pod2", 0))
+	hub := NewHub(ds, time.Hour)
+
+	events, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	// The first event is the full snapshot.
+	event := receive(t, events)
+	want := &SnapshotDiff{Updated: []PodState{
+		{Name: "default/pod1", ActiveModels: []string{"foo"}},
+		{Name: "default/pod2", ActiveModels: []string{"foo"}},
+	}}
+	if diff := cmp.Diff(want, event.Snapshot); diff != "" {
+		t.Errorf("Unexpected snapshot (-want +got): %v", diff)
+	}
+
+	// Only the changed and removed pods are sent afterwards.
+	ds.setPods(newPod("pod1", 5), newPod("pod3", 0))
+	hub.refreshSnapshot()
+	event = receive(t, events)
+	want = &SnapshotDiff{
+		Updated: []PodState{
+			{Name: "default/pod1", WaitingQueueSize: 5, ActiveModels: []string{"foo"}},
+			{Name: "default/pod3", ActiveModels: []string{"foo"}},
+		},
+		Removed: []string{"default/pod2"},
+	}
+	if diff := cmp.Diff(want, event.Snapshot); diff != "" {
+		t.Errorf("Unexpected snapshot diff (-want +got): %v", diff)
+	}
+
+	// Nothing is sent if nothing changed.
+	hub.refreshSnapshot()
+	select {
+	case event := <-events:
+		t.Errorf("Unexpected event %+v", event)
+	default:
+	}
+
+	// Decisions are published by the plugin, along with the scores.
+	scorer := framework.NewWeightedScorer(&testScorer{}, 2)
+	plugin := NewDecisionPlugin(hub, scorer)
+	pods := types.ToSchedulerPodMetrics(ds.PodGetAll())
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: "req", TargetModel: "foo"}, nil, pods)
+	framework.ScorerScoresStateKey(scorer.Name()).Write(ctx.CycleState, framework.ScorerScores{pods[0]: 0.5, pods[1]: 1})
+	plugin.PostCycle(ctx, &types.Result{TargetPod: pods[1]})
+	event = receive(t, events)
+	wantDecision := &Decision{
+		RequestID:   "req",
+		TargetModel: "foo",
+		TargetPod:   "default/pod3",
+		Scores:      map[string]map[string]float64{"test-scorer": {"default/pod1": 0.5, "default/pod3": 1}},
+		Weights:     map[string]int{"test-scorer": 2},
+	}
+	if diff := cmp.Diff(wantDecision, event.Decision); diff != "" {
+		t.Errorf("Unexpected decision (-want +got): %v", diff)
+	}
+
+	unsubscribe()
+	assert.False(t, hub.hasSubscribers())
+}
+
+func TestServeStream(t *testing.T) {
+	ds := &fakeDatastore{}
+	ds.setPods(newPod("pod1", 0))
+	hub := NewHub(ds, time.Hour)
+	server := httptest.NewServer(hub.Handlers()[StreamPath])
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: snapshot\n", line)
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, `data: {"type":"snapshot"`), line)
+	assert.Contains(t, line, `"name":"default/pod1"`)
+}
+
+type testScorer struct{}
+
+func (s *testScorer) Name() string { return "test-scorer" }
+
+func (s *testScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	return nil
+}
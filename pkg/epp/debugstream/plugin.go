@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugstream
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.PostCycle = &DecisionPlugin{}
+
+// NewDecisionPlugin initializes a new DecisionPlugin and returns its pointer.
+// The scores of the given scorers are included in the published decisions.
+func NewDecisionPlugin(hub *Hub, scorers ...*framework.WeightedScorer) *DecisionPlugin {
+	return &DecisionPlugin{
+		hub:     hub,
+		scorers: scorers,
+	}
+}
+
+// DecisionPlugin publishes the scheduling decisions to the Hub, along with the scores that led to
+// them.
+type DecisionPlugin struct {
+	hub     *Hub
+	scorers []*framework.WeightedScorer
+}
+
+// Name returns the name of the plugin.
+func (p *DecisionPlugin) Name() string {
+	return "debug-stream"
+}
+
+// PostCycle publishes the decision of the scheduling cycle, if anyone is subscribed.
+func (p *DecisionPlugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	if res == nil || res.TargetPod == nil || !p.hub.hasSubscribers() {
+		return
+	}
+	decision := &Decision{TargetPod: res.TargetPod.GetPod().NamespacedName.String()}
+	if ctx.Req != nil {
+		decision.RequestID = ctx.Req.RequestId
+		decision.TargetModel = ctx.Req.TargetModel
+	}
+	decision.Scores, decision.Weights = framework.CaptureScores(ctx.CycleState, p.scorers)
+	p.hub.PublishDecision(decision)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointsource
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// NewEndpointSliceSource returns an EndpointSliceSource reporting the ready endpoints of the given
+// service, listed with the given client at the given interval. If portName isn't empty, the
+// endpoints serve on the port of that name, otherwise on the target port of the pool.
+func NewEndpointSliceSource(c client.Reader, service types.NamespacedName, portName string, interval time.Duration) *EndpointSliceSource {
+	return &EndpointSliceSource{client: c, service: service, portName: portName, interval: interval}
+}
+
+// EndpointSliceSource reports the endpoints of the EndpointSlices of a service, typically of
+// another cluster, e.g. a cluster of dedicated GPU nodes, whose pods are reachable from the
+// gateway. The slices are listed periodically rather than watched, so the source only needs
+// read access to the EndpointSlices of the service.
+type EndpointSliceSource struct {
+	client   client.Reader
+	service  types.NamespacedName
+	portName string
+	interval time.Duration
+}
+
+// Name returns "endpointslice".
+func (s *EndpointSliceSource) Name() string {
+	return "endpointslice"
+}
+
+// Run reports the endpoints of the service, and then again each time they change.
+func (s *EndpointSliceSource) Run(ctx context.Context, update func([]datastore.Endpoint)) error {
+	logger := log.FromContext(ctx).WithValues("service", s.service)
+	var last []datastore.Endpoint
+	check := func() {
+		slices := &discoveryv1.EndpointSliceList{}
+		if err := s.client.List(ctx, slices, client.InNamespace(s.service.Namespace),
+			client.MatchingLabels{discoveryv1.LabelServiceName: s.service.Name}); err != nil {
+			logger.V(logutil.DEFAULT).Error(err, "Failed to list EndpointSlices, keeping the current endpoints")
+			return
+		}
+		endpoints := EndpointsFromSlices(slices.Items, s.portName)
+		if last != nil && reflect.DeepEqual(endpoints, last) {
+			return
+		}
+		last = endpoints
+		update(endpoints)
+	}
+
+	check()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// EndpointsFromSlices returns the ready endpoints of the given EndpointSlices, sorted by name, one
+// per address. The endpoints are named after the pods they target, or their address otherwise. If
+// portName isn't empty, the slices without a port of that name are skipped.
+func EndpointsFromSlices(slices []discoveryv1.EndpointSlice, portName string) []datastore.Endpoint {
+	endpoints := []datastore.Endpoint{}
+	seen := map[string]bool{}
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		var port int32
+		if portName != "" {
+			for _, p := range slice.Ports {
+				if p.Name != nil && *p.Name == portName && p.Port != nil {
+					port = *p.Port
+				}
+			}
+			if port == 0 {
+				continue
+			}
+		}
+		for _, endpoint := range slice.Endpoints {
+			// As for the pods of the cluster, only the ready endpoints are routed to. A nil
+			// condition means ready.
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			for i, address := range endpoint.Addresses {
+				name := address
+				if endpoint.TargetRef != nil && endpoint.TargetRef.Name != "" && i == 0 {
+					name = endpoint.TargetRef.Name
+				}
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				ep := datastore.Endpoint{Name: name, Address: address, Port: port}
+				if endpoint.Zone != nil {
+					ep.Labels = map[string]string{corev1.LabelTopologyZone: *endpoint.Zone}
+				}
+				endpoints = append(endpoints, ep)
+			}
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Name < endpoints[j].Name })
+	return endpoints
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointsource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+)
+
+func TestParseEndpointsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []datastore.Endpoint
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: `
+endpoints:
+- name: vm-1
+  address: 10.0.0.1
+  port: 8000
+  labels:
+    inference.networking.x-k8s.io/role: decode
+- name: vm-2
+  address: 10.0.0.2
+`,
+			want: []datastore.Endpoint{
+				{Name: "vm-1", Address: "10.0.0.1", Port: 8000, Labels: map[string]string{"inference.networking.x-k8s.io/role": "decode"}},
+				{Name: "vm-2", Address: "10.0.0.2"},
+			},
+		},
+		{
+			name: "empty",
+			data: "endpoints: []",
+			want: []datastore.Endpoint{},
+		},
+		{
+			name:    "unknown field",
+			data:    "endpoints:\n- name: vm-1\n  address: 10.0.0.1\n  host: vm-1\n",
+			wantErr: true,
+		},
+		{
+			name:    "no address",
+			data:    "endpoints:\n- name: vm-1\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate name",
+			data:    "endpoints:\n- name: vm-1\n  address: 10.0.0.1\n- name: vm-1\n  address: 10.0.0.2\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid port",
+			data:    "endpoints:\n- name: vm-1\n  address: 10.0.0.1\n  port: 70000\n",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseEndpointsFile([]byte(test.data))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Unexpected error: %v, want error: %v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected endpoints (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("endpoints:\n- name: vm-1\n  address: 10.0.0.1\n")
+
+	updates := make(chan []datastore.Endpoint, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = NewFileSource(path, 10*time.Millisecond).Run(ctx, func(endpoints []datastore.Endpoint) {
+			updates <- endpoints
+		})
+	}()
+	next := func() []datastore.Endpoint {
+		select {
+		case endpoints := <-updates:
+			return endpoints
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the endpoints")
+			return nil
+		}
+	}
+
+	if diff := cmp.Diff([]datastore.Endpoint{{Name: "vm-1", Address: "10.0.0.1"}}, next()); diff != "" {
+		t.Errorf("Unexpected endpoints (-want +got): %s", diff)
+	}
+	// An invalid file is ignored, and the next valid one is reported.
+	write("endpoints:\n- name: vm-2\n")
+	write("endpoints:\n- name: vm-2\n  address: 10.0.0.2\n")
+	if diff := cmp.Diff([]datastore.Endpoint{{Name: "vm-2", Address: "10.0.0.2"}}, next()); diff != "" {
+		t.Errorf("Unexpected endpoints (-want +got): %s", diff)
+	}
+}
+
+func TestEndpointsFromSlices(t *testing.T) {
+	slice := func(name string, addressType discoveryv1.AddressType, ports []discoveryv1.EndpointPort, endpoints ...discoveryv1.Endpoint) discoveryv1.EndpointSlice {
+		return discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: name, Namespace: "remote", Labels: map[string]string{discoveryv1.LabelServiceName: "vllm"}},
+			AddressType: addressType,
+			Ports:       ports,
+			Endpoints:   endpoints,
+		}
+	}
+	ports := []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To[int32](8000)}}
+	slices := []discoveryv1.EndpointSlice{
+		slice("vllm-a", discoveryv1.AddressTypeIPv4, ports,
+			discoveryv1.Endpoint{
+				Addresses: []string{"10.0.0.1"},
+				TargetRef: &corev1.ObjectReference{Name: "vllm-0"},
+				Zone:      ptr.To("zone-a"),
+			},
+			discoveryv1.Endpoint{
+				Addresses:  []string{"10.0.0.2"},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+			},
+			discoveryv1.Endpoint{
+				Addresses:  []string{"10.0.0.3"},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+			}),
+		slice("vllm-b", discoveryv1.AddressTypeFQDN, ports,
+			discoveryv1.Endpoint{Addresses: []string{"vllm.example.com"}}),
+		slice("vllm-c", discoveryv1.AddressTypeIPv4, nil,
+			discoveryv1.Endpoint{Addresses: []string{"10.0.0.4"}}),
+	}
+
+	tests := []struct {
+		name     string
+		portName string
+		want     []datastore.Endpoint
+	}{
+		{
+			name: "pool target port",
+			want: []datastore.Endpoint{
+				{Name: "10.0.0.3", Address: "10.0.0.3"},
+				{Name: "10.0.0.4", Address: "10.0.0.4"},
+				{Name: "vllm-0", Address: "10.0.0.1", Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"}},
+			},
+		},
+		{
+			name:     "named port",
+			portName: "http",
+			want: []datastore.Endpoint{
+				{Name: "10.0.0.3", Address: "10.0.0.3", Port: 8000},
+				{Name: "vllm-0", Address: "10.0.0.1", Port: 8000, Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, EndpointsFromSlices(slices, test.portName)); diff != "" {
+				t.Errorf("Unexpected endpoints (-want +got): %s", diff)
+			}
+		})
+	}
+
+	// The source lists the slices of the service only.
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	other := slice("other", discoveryv1.AddressTypeIPv4, nil, discoveryv1.Endpoint{Addresses: []string{"10.0.1.1"}})
+	other.Labels[discoveryv1.LabelServiceName] = "other"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&slices[0], &other).Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan []datastore.Endpoint, 1)
+	source := NewEndpointSliceSource(fakeClient, types.NamespacedName{Namespace: "remote", Name: "vllm"}, "", time.Hour)
+	go func() {
+		_ = source.Run(ctx, func(endpoints []datastore.Endpoint) { updates <- endpoints })
+	}()
+	want := []datastore.Endpoint{
+		{Name: "10.0.0.3", Address: "10.0.0.3"},
+		{Name: "vllm-0", Address: "10.0.0.1", Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"}},
+	}
+	select {
+	case got := <-updates:
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Unexpected endpoints (-want +got): %s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the endpoints")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package endpointsource provides the datastore.EndpointSource implementations discovering the
+// model servers of a pool outside of the pods of the cluster.
+package endpointsource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultRefreshInterval is the default interval between two refreshes of the endpoints of a source.
+const DefaultRefreshInterval = 10 * time.Second
+
+// FileConfig is the content of the file of a FileSource, e.g.:
+//
+//	endpoints:
+//	- name: gpu-vm-1
+//	  address: 10.0.0.12
+//	  port: 8000
+//	  labels:
+//	    inference.networking.x-k8s.io/role: decode
+type FileConfig struct {
+	Endpoints []datastore.Endpoint `json:"endpoints"`
+}
+
+// NewFileSource returns a FileSource reading the endpoints from the file of the given path, checked
+// for changes at the given interval.
+func NewFileSource(path string, interval time.Duration) *FileSource {
+	return &FileSource{path: path, interval: interval}
+}
+
+// FileSource reports the endpoints statically listed in a file, e.g. mounted from a ConfigMap or
+// written by a service discovery agent. As for the scheduler config file, the file is polled rather
+// than watched, and an invalid file is reported and ignored, i.e. the endpoints are kept.
+type FileSource struct {
+	path     string
+	interval time.Duration
+}
+
+// Name returns "file".
+func (s *FileSource) Name() string {
+	return "file"
+}
+
+// Run reports the endpoints of the file, and then again each time the content of the file changes.
+func (s *FileSource) Run(ctx context.Context, update func([]datastore.Endpoint)) error {
+	logger := log.FromContext(ctx).WithValues("path", s.path)
+	var last []byte
+	check := func() {
+		data, err := os.ReadFile(s.path)
+		if err != nil {
+			logger.V(logutil.DEFAULT).Error(err, "Failed to read endpoints file")
+			return
+		}
+		if last != nil && bytes.Equal(data, last) {
+			return
+		}
+		last = data
+		endpoints, err := ParseEndpointsFile(data)
+		if err != nil {
+			logger.V(logutil.DEFAULT).Error(err, "Failed to load endpoints file, keeping the current endpoints")
+			return
+		}
+		update(endpoints)
+	}
+
+	check()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// ParseEndpointsFile parses the content of the file of a FileSource.
+func ParseEndpointsFile(data []byte) ([]datastore.Endpoint, error) {
+	config := &FileConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse endpoints file: %w", err)
+	}
+	names := make(map[string]bool, len(config.Endpoints))
+	for i, endpoint := range config.Endpoints {
+		if endpoint.Name == "" {
+			return nil, fmt.Errorf("endpoint %d has no name", i)
+		}
+		if names[endpoint.Name] {
+			return nil, fmt.Errorf("duplicate endpoint '%s'", endpoint.Name)
+		}
+		names[endpoint.Name] = true
+		if endpoint.Address == "" {
+			return nil, fmt.Errorf("endpoint '%s' has no address", endpoint.Name)
+		}
+		if endpoint.Port < 0 || endpoint.Port > 65535 {
+			return nil, fmt.Errorf("endpoint '%s' has an invalid port %d", endpoint.Name, endpoint.Port)
+		}
+	}
+	return config.Endpoints, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AdminPath is the path of the failover admin endpoint.
+const AdminPath = "/admin/failover"
+
+// Status is the response of the failover admin endpoint.
+type Status struct {
+	Mode       Mode   `json:"mode"`
+	FailedOver bool   `json:"failedOver"`
+	Primary    string `json:"primary"`
+	Standby    string `json:"standby"`
+}
+
+// ServeHTTP serves the failover admin endpoint. GET returns the failover Status, and POST sets the
+// routing mode given by the "mode" query parameter, e.g. POST /admin/failover?mode=auto fails the
+// traffic back to the primary pool after an automatic failover.
+func (f *Failover) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if err := f.SetMode(r.Context(), Mode(r.URL.Query().Get("mode"))); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(Status{
+		Mode:       f.Mode(),
+		FailedOver: f.IsFailedOver(),
+		Primary:    f.primaryName.String(),
+		Standby:    f.standbyName.String(),
+	})
+}
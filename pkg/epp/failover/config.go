@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultMinReadyPods is the number of ready pods below which the primary pool is considered
+	// unhealthy, i.e. by default traffic fails over only when the primary pool has no ready pods.
+	DefaultMinReadyPods = 1
+	// DefaultAutoFailback disables failing back to the primary pool automatically, so that a
+	// flapping primary pool doesn't move the traffic back and forth. Fail-back is done through the
+	// admin endpoint instead.
+	DefaultAutoFailback = false
+	// DefaultCheckInterval is the interval the health of the pools is evaluated at.
+	DefaultCheckInterval = time.Second
+)
+
+// Environment variable names for standby failover configuration
+const (
+	EnvFailoverMinReadyPods  = "STANDBY_FAILOVER_MIN_READY_PODS"
+	EnvFailoverAutoFailback  = "STANDBY_FAILOVER_AUTO_FAILBACK"
+	EnvFailoverCheckInterval = "STANDBY_FAILOVER_CHECK_INTERVAL"
+)
+
+// Config holds the configuration for the standby pool Failover.
+type Config struct {
+	// MinReadyPods is the number of ready pods the primary pool needs to have to be considered healthy.
+	MinReadyPods int
+	// AutoFailback makes the traffic go back to the primary pool as soon as it's healthy again.
+	AutoFailback bool
+	// CheckInterval is the interval the health of the pools is evaluated at.
+	CheckInterval time.Duration
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		MinReadyPods:  DefaultMinReadyPods,
+		AutoFailback:  DefaultAutoFailback,
+		CheckInterval: DefaultCheckInterval,
+	}
+}
+
+// LoadConfigFromEnv loads standby failover Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("failover-config")
+
+	cfg := &Config{}
+
+	cfg.MinReadyPods = envutil.GetEnvInt(EnvFailoverMinReadyPods, DefaultMinReadyPods, logger)
+	if cfg.MinReadyPods <= 0 {
+		cfg.MinReadyPods = DefaultMinReadyPods
+	}
+
+	autoFailback, err := strconv.ParseBool(envutil.GetEnvString(EnvFailoverAutoFailback, strconv.FormatBool(DefaultAutoFailback), logger))
+	if err != nil {
+		autoFailback = DefaultAutoFailback
+	}
+	cfg.AutoFailback = autoFailback
+
+	cfg.CheckInterval = envutil.GetEnvDuration(EnvFailoverCheckInterval, DefaultCheckInterval, logger)
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultCheckInterval
+	}
+
+	logger.Info("Standby failover configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
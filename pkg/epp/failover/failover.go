@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package failover routes the traffic of an InferencePool to a warm standby InferencePool when
+// the primary pool becomes unhealthy.
+package failover
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// Mode is the routing mode of the Failover.
+type Mode string
+
+const (
+	// ModeAuto routes to the primary pool, and fails over to the standby pool automatically when the
+	// primary pool becomes unhealthy.
+	ModeAuto Mode = "auto"
+	// ModePrimary pins the traffic to the primary pool.
+	ModePrimary Mode = "primary"
+	// ModeStandby pins the traffic to the standby pool.
+	ModeStandby Mode = "standby"
+)
+
+// Event reasons recorded on the InferencePools.
+const (
+	ReasonFailedOver = "FailedOver"
+	ReasonFailedBack = "FailedBack"
+)
+
+// Failover is a Datastore that serves the pool and pods of the primary datastore, or those of the
+// standby datastore when the traffic is failed over. The remaining operations, notably the
+// InferenceModel ones, are always served by the primary datastore.
+//
+// The primary pool is unhealthy when it's not synced or has less ready (uncordoned) pods than the
+// configured minimum. In ModeAuto, the traffic fails over to the standby pool when the primary pool
+// is unhealthy and the standby pool has more ready pods. Unless AutoFailback is configured, the
+// traffic stays on the standby pool until it is failed back manually by setting the mode again.
+type Failover struct {
+	datastore.Datastore
+	standby     datastore.Datastore
+	primaryName types.NamespacedName
+	standbyName types.NamespacedName
+	config      *Config
+	recorder    record.EventRecorder
+
+	mu         sync.RWMutex
+	mode       Mode
+	failedOver bool
+}
+
+// New initializes a new Failover and returns its pointer.
+func New(primary, standby datastore.Datastore, primaryName, standbyName types.NamespacedName, config *Config) *Failover {
+	if config == nil {
+		config = NewDefaultConfig()
+	}
+	f := &Failover{
+		Datastore:   primary,
+		standby:     standby,
+		primaryName: primaryName,
+		standbyName: standbyName,
+		config:      config,
+		mode:        ModeAuto,
+	}
+	metrics.RecordPoolFailoverActive(primaryName.Name, standbyName.Name, false)
+	return f
+}
+
+// SetupWithManager records the failover events with the recorder of the given manager, and
+// registers the periodic health evaluation with it.
+func (f *Failover) SetupWithManager(mgr ctrl.Manager) error {
+	f.recorder = mgr.GetEventRecorderFor("failover")
+	return mgr.Add(f)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica routes traffic, so
+// every replica evaluates the health of the pools.
+func (f *Failover) NeedLeaderElection() bool {
+	return false
+}
+
+// Start evaluates the health of the pools every CheckInterval until the context is done.
+func (f *Failover) Start(ctx context.Context) error {
+	ticker := time.NewTicker(f.config.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			f.Check(ctx)
+		}
+	}
+}
+
+// PoolGet returns the pool the traffic is routed to.
+func (f *Failover) PoolGet() (*v1alpha2.InferencePool, error) {
+	return f.active().PoolGet()
+}
+
+// PodGetAll returns the pods of the pool the traffic is routed to.
+func (f *Failover) PodGetAll() []backendmetrics.PodMetrics {
+	return f.active().PodGetAll()
+}
+
+// PodSnapshot returns a snapshot of the pods of the pool the traffic is routed to.
+func (f *Failover) PodSnapshot() *backendmetrics.PodSnapshot {
+	return f.active().PodSnapshot()
+}
+
+// PodList lists the pods of the pool the traffic is routed to, matching the given predicate.
+func (f *Failover) PodList(predicate func(backendmetrics.PodMetrics) bool) []backendmetrics.PodMetrics {
+	return f.active().PodList(predicate)
+}
+
+// Mode returns the current routing mode.
+func (f *Failover) Mode() Mode {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.mode
+}
+
+// IsFailedOver returns true if the traffic is routed to the standby pool.
+func (f *Failover) IsFailedOver() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.routesToStandby()
+}
+
+// SetMode sets the routing mode. Setting any mode clears a previous automatic failover, so setting
+// ModeAuto fails the traffic back to the primary pool, and it fails over again only if the primary
+// pool is still unhealthy.
+func (f *Failover) SetMode(ctx context.Context, mode Mode) error {
+	switch mode {
+	case ModeAuto, ModePrimary, ModeStandby:
+	default:
+		return fmt.Errorf("invalid failover mode %q", mode)
+	}
+	f.mu.Lock()
+	wasStandby := f.routesToStandby()
+	f.mode = mode
+	f.failedOver = false
+	isStandby := f.routesToStandby()
+	f.mu.Unlock()
+
+	log.FromContext(ctx).V(logutil.DEFAULT).Info("Failover mode set", "mode", mode, "primary", f.primaryName, "standby", f.standbyName)
+	f.recordTransition(wasStandby, isStandby, "failover mode set to "+string(mode))
+	f.Check(ctx)
+	return nil
+}
+
+// Check evaluates the health of the pools, and fails the traffic over (or back, if AutoFailback is
+// configured) accordingly. It's a no-op unless the mode is ModeAuto.
+func (f *Failover) Check(ctx context.Context) {
+	primaryReady, primaryHealthy := f.readyPods(f.Datastore)
+	standbyReady, standbyHealthy := f.readyPods(f.standby)
+	primaryHealthy = primaryHealthy && primaryReady >= f.config.MinReadyPods
+	standbyHealthy = standbyHealthy && standbyReady > primaryReady
+
+	f.mu.Lock()
+	if f.mode != ModeAuto {
+		f.mu.Unlock()
+		return
+	}
+	wasFailedOver := f.failedOver
+	switch {
+	case !f.failedOver && !primaryHealthy && standbyHealthy:
+		f.failedOver = true
+	case f.failedOver && primaryHealthy && f.config.AutoFailback:
+		f.failedOver = false
+	}
+	isFailedOver := f.failedOver
+	f.mu.Unlock()
+
+	if wasFailedOver == isFailedOver {
+		return
+	}
+	logger := log.FromContext(ctx).WithValues("primary", f.primaryName, "standby", f.standbyName,
+		"primaryReadyPods", primaryReady, "standbyReadyPods", standbyReady)
+	if isFailedOver {
+		logger.V(logutil.DEFAULT).Info("Primary pool is unhealthy, failing over to the standby pool")
+		metrics.RecordPoolFailover(f.primaryName.Name, f.standbyName.Name)
+	} else {
+		logger.V(logutil.DEFAULT).Info("Primary pool is healthy again, failing back to it")
+	}
+	f.recordTransition(wasFailedOver, isFailedOver,
+		fmt.Sprintf("primary pool has %d ready pods, standby pool has %d ready pods", primaryReady, standbyReady))
+}
+
+// readyPods returns the number of uncordoned pods of the given datastore, and whether its pool is synced.
+func (f *Failover) readyPods(ds datastore.Datastore) (int, bool) {
+	if !ds.PoolHasSynced() {
+		return 0, false
+	}
+	return len(ds.PodList(func(pm backendmetrics.PodMetrics) bool { return !pm.GetPod().Cordoned })), true
+}
+
+func (f *Failover) active() datastore.Datastore {
+	if f.IsFailedOver() {
+		return f.standby
+	}
+	return f.Datastore
+}
+
+// routesToStandby must be called with the lock held.
+func (f *Failover) routesToStandby() bool {
+	return f.mode == ModeStandby || (f.mode == ModeAuto && f.failedOver)
+}
+
+// recordTransition reports a change of the pool the traffic is routed to, if any, via metrics and
+// an event on both pools.
+func (f *Failover) recordTransition(wasStandby, isStandby bool, message string) {
+	if wasStandby == isStandby {
+		return
+	}
+	metrics.RecordPoolFailoverActive(f.primaryName.Name, f.standbyName.Name, isStandby)
+	if f.recorder == nil {
+		return
+	}
+	eventType, reason, message := corev1.EventTypeNormal, ReasonFailedBack,
+		fmt.Sprintf("Traffic failed back from standby pool %s to primary pool %s: %s", f.standbyName, f.primaryName, message)
+	if isStandby {
+		eventType, reason, message = corev1.EventTypeWarning, ReasonFailedOver,
+			fmt.Sprintf("Traffic failed over from primary pool %s to standby pool %s: %s", f.primaryName, f.standbyName, message)
+	}
+	for _, ds := range []datastore.Datastore{f.Datastore, f.standby} {
+		if pool, err := ds.PoolGet(); err == nil {
+			f.recorder.Event(pool, eventType, reason, message)
+		}
+	}
+}
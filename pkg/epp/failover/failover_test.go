@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+var (
+	primaryName = types.NamespacedName{Name: "primary", Namespace: "default"}
+	standbyName = types.NamespacedName{Name: "standby", Namespace: "default"}
+)
+
+func newDatastore(t *testing.T, poolName types.NamespacedName) datastore.Datastore {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	pool := testutil.MakeInferencePool(poolName.Name).Namespace(poolName.Namespace).
+		Selector(map[string]string{"app": poolName.Name}).ObjRef()
+	if err := ds.PoolSet(t.Context(), fakeClient, pool); err != nil {
+		t.Fatalf("Failed to set pool: %v", err)
+	}
+	return ds
+}
+
+func addPod(ds datastore.Datastore, poolName types.NamespacedName, podName string) {
+	ds.PodUpdateOrAddIfNotExist(testutil.MakePod(podName).Namespace(poolName.Namespace).
+		Labels(map[string]string{"app": poolName.Name}).ReadyCondition().ObjRef())
+}
+
+func activePool(t *testing.T, f *Failover) string {
+	pool, err := f.PoolGet()
+	assert.NoError(t, err)
+	return pool.Name
+}
+
+func TestFailover(t *testing.T) {
+	primary, standby := newDatastore(t, primaryName), newDatastore(t, standbyName)
+	addPod(primary, primaryName, "primary-0")
+	addPod(standby, standbyName, "standby-0")
+	addPod(standby, standbyName, "standby-1")
+
+	f := New(primary, standby, primaryName, standbyName, &Config{MinReadyPods: 1, CheckInterval: time.Second})
+
+	// The primary pool is healthy.
+	f.Check(t.Context())
+	assert.False(t, f.IsFailedOver())
+	assert.Equal(t, primaryName.Name, activePool(t, f))
+	assert.Len(t, f.PodGetAll(), 1)
+
+	// The primary pool loses its last ready pod.
+	primary.PodDelete(types.NamespacedName{Name: "primary-0", Namespace: primaryName.Namespace})
+	f.Check(t.Context())
+	assert.True(t, f.IsFailedOver())
+	assert.Equal(t, standbyName.Name, activePool(t, f))
+	assert.Len(t, f.PodGetAll(), 2)
+
+	// Without auto fail-back, the traffic stays on the standby pool after the primary pool recovers.
+	addPod(primary, primaryName, "primary-0")
+	f.Check(t.Context())
+	assert.True(t, f.IsFailedOver())
+
+	// Manual fail-back.
+	assert.NoError(t, f.SetMode(t.Context(), ModeAuto))
+	assert.False(t, f.IsFailedOver())
+	assert.Equal(t, primaryName.Name, activePool(t, f))
+
+	// Pinning the traffic to a pool disables the automatic failover.
+	assert.NoError(t, f.SetMode(t.Context(), ModeStandby))
+	assert.True(t, f.IsFailedOver())
+	assert.NoError(t, f.SetMode(t.Context(), ModePrimary))
+	primary.PodDelete(types.NamespacedName{Name: "primary-0", Namespace: primaryName.Namespace})
+	f.Check(t.Context())
+	assert.False(t, f.IsFailedOver())
+
+	assert.Error(t, f.SetMode(t.Context(), Mode("invalid")))
+}
+
+func TestFailoverRequiresHealthierStandby(t *testing.T) {
+	primary, standby := newDatastore(t, primaryName), newDatastore(t, standbyName)
+	addPod(primary, primaryName, "primary-0")
+	addPod(standby, standbyName, "standby-0")
+
+	f := New(primary, standby, primaryName, standbyName, &Config{MinReadyPods: 2, CheckInterval: time.Second})
+
+	// The primary pool is below the threshold, but the standby pool isn't any better.
+	f.Check(t.Context())
+	assert.False(t, f.IsFailedOver())
+
+	addPod(standby, standbyName, "standby-1")
+	f.Check(t.Context())
+	assert.True(t, f.IsFailedOver())
+}
+
+func TestAutoFailback(t *testing.T) {
+	primary, standby := newDatastore(t, primaryName), newDatastore(t, standbyName)
+	addPod(standby, standbyName, "standby-0")
+
+	f := New(primary, standby, primaryName, standbyName, &Config{MinReadyPods: 1, AutoFailback: true, CheckInterval: time.Second})
+
+	f.Check(t.Context())
+	assert.True(t, f.IsFailedOver())
+
+	addPod(primary, primaryName, "primary-0")
+	f.Check(t.Context())
+	assert.False(t, f.IsFailedOver())
+}
+
+func TestServeHTTP(t *testing.T) {
+	primary, standby := newDatastore(t, primaryName), newDatastore(t, standbyName)
+	addPod(primary, primaryName, "primary-0")
+	f := New(primary, standby, primaryName, standbyName, NewDefaultConfig())
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantCode   int
+		wantStatus *Status
+	}{
+		{
+			name:     "get status",
+			method:   http.MethodGet,
+			target:   AdminPath,
+			wantCode: http.StatusOK,
+			wantStatus: &Status{Mode: ModeAuto, FailedOver: false,
+				Primary: primaryName.String(), Standby: standbyName.String()},
+		},
+		{
+			name:     "pin standby",
+			method:   http.MethodPost,
+			target:   AdminPath + "?mode=standby",
+			wantCode: http.StatusOK,
+			wantStatus: &Status{Mode: ModeStandby, FailedOver: true,
+				Primary: primaryName.String(), Standby: standbyName.String()},
+		},
+		{
+			name:     "invalid mode",
+			method:   http.MethodPost,
+			target:   AdminPath + "?mode=other",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodDelete,
+			target:   AdminPath,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			f.ServeHTTP(rec, httptest.NewRequest(test.method, test.target, nil))
+			assert.Equal(t, test.wantCode, rec.Code)
+			if test.wantStatus != nil {
+				got := &Status{}
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(got))
+				assert.Equal(t, test.wantStatus, got)
+			}
+		})
+	}
+}
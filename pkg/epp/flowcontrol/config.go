@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowcontrol
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultMaxQueueSize is zero, i.e. the requests are not queued and flow control is disabled
+	// by default.
+	DefaultMaxQueueSize = 0
+	// DefaultQueueTTL is the default maximum time a request waits in a queue.
+	DefaultQueueTTL = 10 * time.Second
+	// DefaultDispatchInterval is the default interval at which the saturation of the pool is
+	// checked to dispatch the queued requests. It's the refresh interval of the pod metrics.
+	DefaultDispatchInterval = 50 * time.Millisecond
+	// DefaultDispatchBatchSize is the default maximum number of queued requests dispatched at every
+	// interval, so the pool isn't flooded before its metrics reflect the dispatched requests.
+	DefaultDispatchBatchSize = 8
+	// DefaultRetryAfter is the default delay after which the rejected requests are advised to be
+	// retried.
+	DefaultRetryAfter = time.Second
+	// DefaultFairShareQuantum is the default cost, in tokens, a flow of weight one is credited at
+	// every round of the fair dispatch of the requests of a priority.
+	DefaultFairShareQuantum = 1024
+)
+
+// Environment variable names for flow control configuration. The per-queue variables are
+// prefixed with FLOW_CONTROL_<CRITICAL|STANDARD|SHEDDABLE>.
+const (
+	EnvMaxQueueSizeSuffix = "_MAX_QUEUE_SIZE"
+	EnvQueueTTLSuffix     = "_QUEUE_TTL"
+	EnvDispatchInterval   = "FLOW_CONTROL_DISPATCH_INTERVAL"
+	EnvDispatchBatchSize  = "FLOW_CONTROL_DISPATCH_BATCH_SIZE"
+	EnvRetryAfter         = "FLOW_CONTROL_RETRY_AFTER"
+	EnvFairShareQuantum   = "FLOW_CONTROL_FAIR_SHARE_QUANTUM"
+)
+
+// QueueConfig is the configuration of the queue of a priority.
+type QueueConfig struct {
+	// MaxSize is the maximum number of requests waiting in the queue. The requests arriving when
+	// the queue is full are rejected. Zero disables the queue: the requests of the priority are
+	// never held back.
+	MaxSize int
+	// TTL is the maximum time a request waits in the queue before it's rejected.
+	TTL time.Duration
+}
+
+// Config holds the configuration of the flow Controller.
+type Config struct {
+	// Queues are the configurations of the queues, by priority.
+	Queues map[Priority]QueueConfig
+	// DispatchInterval is the interval at which the saturation of the pool is checked to dispatch
+	// the queued requests.
+	DispatchInterval time.Duration
+	// DispatchBatchSize is the maximum number of queued requests dispatched at every interval.
+	DispatchBatchSize int
+	// RetryAfter is the delay after which the rejected requests are advised to be retried.
+	RetryAfter time.Duration
+	// FairShareQuantum is the cost a flow of weight one is credited at every round of the fair
+	// dispatch of the requests of a priority. The smaller, the finer the fairness, at the expense
+	// of more rounds.
+	FairShareQuantum int
+	// SaturationDetector configures when the pool is saturated.
+	SaturationDetector *saturationdetector.Config
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	queues := map[Priority]QueueConfig{}
+	for _, priority := range Priorities {
+		queues[priority] = QueueConfig{MaxSize: DefaultMaxQueueSize, TTL: DefaultQueueTTL}
+	}
+	return &Config{
+		Queues:            queues,
+		DispatchInterval:  DefaultDispatchInterval,
+		DispatchBatchSize: DefaultDispatchBatchSize,
+		RetryAfter:        DefaultRetryAfter,
+		FairShareQuantum:  DefaultFairShareQuantum,
+		SaturationDetector: &saturationdetector.Config{
+			QueueDepthThreshold:       saturationdetector.DefaultQueueDepthThreshold,
+			KVCacheUtilThreshold:      saturationdetector.DefaultKVCacheUtilThreshold,
+			MetricsStalenessThreshold: saturationdetector.DefaultMetricsStalenessThreshold,
+		},
+	}
+}
+
+// Enabled returns true if any of the queues is enabled.
+func (c *Config) Enabled() bool {
+	for _, queue := range c.Queues {
+		if queue.MaxSize > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadConfigFromEnv loads the flow control Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("flow-control-config")
+
+	cfg := NewDefaultConfig()
+
+	for _, priority := range Priorities {
+		prefix := "FLOW_CONTROL_" + priority.envName()
+		queue := QueueConfig{
+			MaxSize: envutil.GetEnvInt(prefix+EnvMaxQueueSizeSuffix, DefaultMaxQueueSize, logger),
+			TTL:     envutil.GetEnvDuration(prefix+EnvQueueTTLSuffix, DefaultQueueTTL, logger),
+		}
+		if queue.MaxSize < 0 {
+			queue.MaxSize = DefaultMaxQueueSize
+		}
+		if queue.TTL <= 0 {
+			queue.TTL = DefaultQueueTTL
+		}
+		cfg.Queues[priority] = queue
+	}
+
+	cfg.DispatchInterval = envutil.GetEnvDuration(EnvDispatchInterval, DefaultDispatchInterval, logger)
+	if cfg.DispatchInterval <= 0 {
+		cfg.DispatchInterval = DefaultDispatchInterval
+	}
+
+	cfg.DispatchBatchSize = envutil.GetEnvInt(EnvDispatchBatchSize, DefaultDispatchBatchSize, logger)
+	if cfg.DispatchBatchSize <= 0 {
+		cfg.DispatchBatchSize = DefaultDispatchBatchSize
+	}
+
+	cfg.RetryAfter = envutil.GetEnvDuration(EnvRetryAfter, DefaultRetryAfter, logger)
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = DefaultRetryAfter
+	}
+
+	cfg.FairShareQuantum = envutil.GetEnvInt(EnvFairShareQuantum, DefaultFairShareQuantum, logger)
+	if cfg.FairShareQuantum <= 0 {
+		cfg.FairShareQuantum = DefaultFairShareQuantum
+	}
+
+	cfg.SaturationDetector = saturationdetector.LoadConfigFromEnv()
+
+	logger.Info("Flow control configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flowcontrol holds back the requests while the pool is saturated, rather than sending
+// them to pods that can't serve them in time. The held requests wait in a queue per priority
+// (the criticality of their InferenceModel), and are dispatched once the pool has capacity again,
+// the higher priorities first. Within a priority, the requests are dispatched fairly across their
+// flows (their InferenceModels) in proportion to the weights of the flows, so a noisy model can't
+// starve the other models of its priority. The requests are rejected with a Retry-After hint when their queue
+// is full, or when they waited longer than the TTL of their queue.
+package flowcontrol
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// Priority is the priority of a request, derived from the criticality of its InferenceModel.
+type Priority int
+
+const (
+	PrioritySheddable Priority = iota
+	PriorityStandard
+	PriorityCritical
+)
+
+// Priorities are all the priorities, from the highest to the lowest.
+var Priorities = []Priority{PriorityCritical, PriorityStandard, PrioritySheddable}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityStandard:
+		return "standard"
+	case PrioritySheddable:
+		return "sheddable"
+	default:
+		return fmt.Sprintf("priority(%d)", int(p))
+	}
+}
+
+func (p Priority) envName() string {
+	return strings.ToUpper(p.String())
+}
+
+// The reasons the requests are rejected, as recorded in the metrics.
+const (
+	rejectedQueueFull  = "queue_full"
+	rejectedTTLExpired = "ttl_expired"
+	// shedCancelled is the reason of the queued requests cancelled by their client, which are only
+	// recorded in the time the shed requests waited.
+	shedCancelled = "cancelled"
+)
+
+// DefaultWeight is the weight of the flows without weight.
+const DefaultWeight = 1
+
+// Request describes a request to admit.
+type Request struct {
+	// Priority is the priority of the request.
+	Priority Priority
+	// Flow is the flow the request belongs to, e.g. its InferenceModel. The queued requests of a
+	// priority are dispatched fairly across their flows.
+	Flow string
+	// Weight is the weight of the flow in its fair share of the dispatched requests, DefaultWeight
+	// if not positive.
+	Weight int
+	// Cost is the cost of the request charged to its flow, e.g. its estimated number of tokens,
+	// so the flows share the pool rather than the number of requests. One if not positive.
+	Cost int
+}
+
+// SaturationDetector tells whether the pool is saturated.
+type SaturationDetector interface {
+	IsSaturated(ctx context.Context) bool
+}
+
+// Controller queues the requests while the pool is saturated, and dispatches them by priority
+// once it's not.
+type Controller struct {
+	config   *Config
+	detector SaturationDetector
+
+	mu          sync.Mutex
+	queues      map[Priority]*fairQueue
+	dispatching bool // whether the dispatch loop is running
+}
+
+// waiter is a queued request.
+type waiter struct {
+	queued   time.Time
+	deadline time.Time
+	cost     int
+	// done receives the outcome of the request: nil once it's dispatched, or the error it's
+	// rejected with.
+	done chan error
+	// flow is the flow of the waiter in its queue, and element its element in the requests of the
+	// flow, nil once it left the queue.
+	flow    *flow
+	element *list.Element
+}
+
+// NewController creates a new flow Controller.
+func NewController(config *Config, detector SaturationDetector) *Controller {
+	queues := map[Priority]*fairQueue{}
+	for _, priority := range Priorities {
+		queues[priority] = newFairQueue(config.FairShareQuantum)
+	}
+	return &Controller{
+		config:   config,
+		detector: detector,
+		queues:   queues,
+	}
+}
+
+// Admit returns once the given request may be scheduled. It returns right away unless the pool is
+// saturated or requests of the same or a higher priority are already queued, in which case the
+// request is queued until it's dispatched. The returned error is the reason the request was
+// rejected, or the error of the context if it was done first.
+func (c *Controller) Admit(ctx context.Context, req Request) error {
+	priority := req.Priority
+	queueConfig := c.config.Queues[priority]
+	if queueConfig.MaxSize <= 0 {
+		return nil
+	}
+	saturated := c.detector.IsSaturated(ctx)
+
+	c.mu.Lock()
+	// The requests don't overtake the queued requests of the same or a higher priority.
+	if !saturated && !c.queuedFrom(priority) {
+		c.mu.Unlock()
+		return nil
+	}
+	queue := c.queues[priority]
+	if queue.Len() >= queueConfig.MaxSize {
+		c.mu.Unlock()
+		metrics.RecordFlowControlRejectedRequest(priority.String(), rejectedQueueFull)
+		return errutil.Error{
+			Code:       errutil.InferencePoolResourceExhausted,
+			Msg:        fmt.Sprintf("the queue of the %s requests is full", priority),
+			RetryAfter: c.config.RetryAfter,
+		}
+	}
+	weight, cost := req.Weight, req.Cost
+	if weight <= 0 {
+		weight = DefaultWeight
+	}
+	if cost <= 0 {
+		cost = 1
+	}
+	now := time.Now()
+	w := &waiter{queued: now, deadline: now.Add(queueConfig.TTL), cost: cost, done: make(chan error, 1)}
+	queue.push(w, req.Flow, weight)
+	c.recordQueueSizes()
+	if !c.dispatching {
+		c.dispatching = true
+		go c.dispatch(log.IntoContext(context.Background(), log.FromContext(ctx)))
+	}
+	c.mu.Unlock()
+	log.FromContext(ctx).V(logutil.DEBUG).Info("Queued the request while the pool is saturated", "priority", priority, "flow", req.Flow)
+
+	select {
+	case err := <-w.done:
+		return err
+	case <-ctx.Done():
+		c.mu.Lock()
+		queued := w.element != nil
+		if queued {
+			c.queues[priority].remove(w)
+			c.recordQueueSizes()
+		}
+		c.mu.Unlock()
+		if !queued {
+			// The request was dispatched or rejected meanwhile.
+			return <-w.done
+		}
+		metrics.RecordFlowControlShedAfterQueue(priority.String(), shedCancelled, time.Since(w.queued))
+		return ctx.Err()
+	}
+}
+
+// queuedFrom returns whether requests of the given priority or higher are queued.
+func (c *Controller) queuedFrom(priority Priority) bool {
+	for _, p := range Priorities {
+		if p >= priority && c.queues[p].Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch rejects the expired requests and dispatches the queued requests while the pool isn't
+// saturated, at every dispatch interval, until the queues are empty.
+func (c *Controller) dispatch(ctx context.Context) {
+	ticker := time.NewTicker(c.config.DispatchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		saturated := c.detector.IsSaturated(ctx)
+
+		c.mu.Lock()
+		c.expire(time.Now())
+		if !saturated {
+			c.release(c.config.DispatchBatchSize)
+		}
+		c.recordQueueSizes()
+		if !c.queuedFrom(PrioritySheddable) {
+			c.dispatching = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+	}
+}
+
+// expire rejects the requests that waited in their queue beyond its TTL.
+func (c *Controller) expire(now time.Time) {
+	for _, priority := range Priorities {
+		for _, w := range c.queues[priority].expire(now) {
+			w.done <- errutil.Error{
+				Code:       errutil.ServiceUnavailable,
+				Msg:        fmt.Sprintf("the pool remained saturated for the TTL of the queue of the %s requests", priority),
+				RetryAfter: c.config.RetryAfter,
+			}
+			metrics.RecordFlowControlRejectedRequest(priority.String(), rejectedTTLExpired)
+			metrics.RecordFlowControlShedAfterQueue(priority.String(), rejectedTTLExpired, now.Sub(w.queued))
+		}
+	}
+}
+
+// release dispatches up to n queued requests, the higher priorities first.
+func (c *Controller) release(n int) {
+	for _, priority := range Priorities {
+		queue := c.queues[priority]
+		for ; n > 0 && queue.Len() > 0; n-- {
+			queue.pop().done <- nil
+		}
+	}
+}
+
+func (c *Controller) recordQueueSizes() {
+	for _, priority := range Priorities {
+		metrics.RecordFlowControlQueueSize(priority.String(), c.queues[priority].Len())
+	}
+}
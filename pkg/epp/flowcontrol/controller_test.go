@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowcontrol
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+type fakeDetector struct {
+	saturated atomic.Bool
+}
+
+func (d *fakeDetector) IsSaturated(context.Context) bool {
+	return d.saturated.Load()
+}
+
+func newTestController(maxSize int, ttl time.Duration) (*Controller, *fakeDetector) {
+	config := NewDefaultConfig()
+	for _, priority := range Priorities {
+		config.Queues[priority] = QueueConfig{MaxSize: maxSize, TTL: ttl}
+	}
+	config.DispatchInterval = 5 * time.Millisecond
+	config.DispatchBatchSize = 1
+	detector := &fakeDetector{}
+	return NewController(config, detector), detector
+}
+
+// waitQueued waits until the given number of requests are queued.
+func waitQueued(t *testing.T, c *Controller, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		queued := 0
+		for _, queue := range c.queues {
+			queued += queue.Len()
+		}
+		c.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d queued requests", n)
+}
+
+func TestAdmitNotSaturated(t *testing.T) {
+	c, _ := newTestController(1, time.Minute)
+	for _, priority := range Priorities {
+		if err := c.Admit(context.Background(), Request{Priority: priority}); err != nil {
+			t.Errorf("Unexpected error for a %s request: %v", priority, err)
+		}
+	}
+}
+
+func TestAdmitQueueFull(t *testing.T) {
+	c, detector := newTestController(1, time.Minute)
+	detector.saturated.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Admit(ctx, Request{Priority: PriorityStandard}) }()
+	waitQueued(t, c, 1)
+
+	err := c.Admit(context.Background(), Request{Priority: PriorityStandard})
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.InferencePoolResourceExhausted || e.RetryAfter != DefaultRetryAfter {
+		t.Errorf("Got error %v, want a resource exhausted error with a Retry-After", err)
+	}
+}
+
+func TestAdmitPriorityOrder(t *testing.T) {
+	c, detector := newTestController(10, time.Minute)
+	detector.saturated.Store(true)
+
+	var mu sync.Mutex
+	order := []Priority{}
+	var wg sync.WaitGroup
+	admit := func(priority Priority) {
+		defer wg.Done()
+		if err := c.Admit(context.Background(), Request{Priority: priority}); err != nil {
+			t.Errorf("Unexpected error for a %s request: %v", priority, err)
+			return
+		}
+		mu.Lock()
+		order = append(order, priority)
+		mu.Unlock()
+	}
+	for i, priority := range []Priority{PrioritySheddable, PriorityStandard, PriorityCritical} {
+		wg.Add(1)
+		go admit(priority)
+		waitQueued(t, c, i+1)
+	}
+
+	detector.saturated.Store(false)
+	wg.Wait()
+	want := []Priority{PriorityCritical, PriorityStandard, PrioritySheddable}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Got dispatch order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestAdmitTTLExpired(t *testing.T) {
+	c, detector := newTestController(1, 20*time.Millisecond)
+	detector.saturated.Store(true)
+
+	err := c.Admit(context.Background(), Request{Priority: PrioritySheddable})
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.ServiceUnavailable || e.RetryAfter != DefaultRetryAfter {
+		t.Errorf("Got error %v, want a service unavailable error with a Retry-After", err)
+	}
+	waitQueued(t, c, 0)
+}
+
+func TestAdmitContextDone(t *testing.T) {
+	c, detector := newTestController(1, time.Minute)
+	detector.saturated.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Admit(ctx, Request{Priority: PriorityCritical}) }()
+	waitQueued(t, c, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error %v, want %v", err, context.Canceled)
+	}
+	waitQueued(t, c, 0)
+}
+
+func TestAdmitDisabledQueue(t *testing.T) {
+	c, detector := newTestController(0, time.Minute)
+	detector.saturated.Store(true)
+	if err := c.Admit(context.Background(), Request{Priority: PrioritySheddable}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
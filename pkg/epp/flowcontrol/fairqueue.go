@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowcontrol
+
+import (
+	"container/list"
+	"time"
+)
+
+// fairQueue is the queue of a priority. Its requests are grouped by flow, e.g. by InferenceModel,
+// and dispatched by deficit round robin across the flows: each flow in turn is credited a quantum
+// of cost in proportion to its weight, and dispatches its requests while their cost fits in its
+// credit, so a flow queueing many requests can't starve the others.
+type fairQueue struct {
+	quantum int
+	flows   map[string]*flow
+	// active are the flows with queued requests, in round robin order.
+	active *list.List // of *flow
+	size   int
+}
+
+// flow is the queued requests of a flow.
+type flow struct {
+	name    string
+	weight  int
+	deficit int
+	// requests are the queued requests of the flow, in arrival order.
+	requests *list.List // of *waiter
+	// element is the element of the flow in the active flows.
+	element *list.Element
+}
+
+func newFairQueue(quantum int) *fairQueue {
+	return &fairQueue{
+		quantum: quantum,
+		flows:   map[string]*flow{},
+		active:  list.New(),
+	}
+}
+
+// Len returns the number of queued requests.
+func (q *fairQueue) Len() int {
+	return q.size
+}
+
+// push queues the given request at the end of the given flow, whose weight is updated.
+func (q *fairQueue) push(w *waiter, flowName string, weight int) {
+	f, ok := q.flows[flowName]
+	if !ok {
+		// The flows joining the round start without credit.
+		f = &flow{name: flowName, requests: list.New()}
+		f.element = q.active.PushBack(f)
+		q.flows[flowName] = f
+	}
+	f.weight = weight
+	w.flow = f
+	w.element = f.requests.PushBack(w)
+	q.size++
+}
+
+// remove removes the given queued request.
+func (q *fairQueue) remove(w *waiter) {
+	f := w.flow
+	f.requests.Remove(w.element)
+	w.element = nil
+	q.size--
+	if f.requests.Len() == 0 {
+		// The credit of the flows isn't kept while they are idle.
+		q.active.Remove(f.element)
+		delete(q.flows, f.name)
+	}
+}
+
+// pop removes and returns the next request to dispatch, nil if the queue is empty.
+func (q *fairQueue) pop() *waiter {
+	if q.size == 0 {
+		return nil
+	}
+	for {
+		element := q.active.Front()
+		f := element.Value.(*flow)
+		w := f.requests.Front().Value.(*waiter)
+		if w.cost <= f.deficit {
+			f.deficit -= w.cost
+			q.remove(w)
+			return w
+		}
+		// The flow has spent its credit for this round.
+		f.deficit += f.weight * q.quantum
+		q.active.MoveToBack(element)
+	}
+}
+
+// expire removes and returns the requests whose deadline is before the given time.
+func (q *fairQueue) expire(now time.Time) []*waiter {
+	var expired []*waiter
+	for element := q.active.Front(); element != nil; {
+		f := element.Value.(*flow)
+		element = element.Next()
+		// The requests of a queue have the same TTL, so the oldest of a flow expire first.
+		for f.requests.Len() > 0 {
+			w := f.requests.Front().Value.(*waiter)
+			if now.Before(w.deadline) {
+				break
+			}
+			q.remove(w)
+			expired = append(expired, w)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowcontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFairQueuePop(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []testFlowRequest
+		want     []string
+	}{
+		{
+			name: "same weights and costs alternate between the flows",
+			requests: []testFlowRequest{
+				{flow: "a", weight: 1, cost: 1}, {flow: "a", weight: 1, cost: 1}, {flow: "a", weight: 1, cost: 1},
+				{flow: "b", weight: 1, cost: 1},
+			},
+			want: []string{"a", "b", "a", "a"},
+		},
+		{
+			name: "dispatched in proportion to the weights",
+			requests: []testFlowRequest{
+				{flow: "a", weight: 1, cost: 1}, {flow: "a", weight: 1, cost: 1}, {flow: "a", weight: 1, cost: 1},
+				{flow: "b", weight: 2, cost: 1}, {flow: "b", weight: 2, cost: 1}, {flow: "b", weight: 2, cost: 1},
+				{flow: "b", weight: 2, cost: 1},
+			},
+			want: []string{"a", "b", "b", "a", "b", "b", "a"},
+		},
+		{
+			name: "costly requests use more of the share of their flow",
+			requests: []testFlowRequest{
+				{flow: "a", weight: 1, cost: 2}, {flow: "a", weight: 1, cost: 2},
+				{flow: "b", weight: 1, cost: 1}, {flow: "b", weight: 1, cost: 1}, {flow: "b", weight: 1, cost: 1},
+				{flow: "b", weight: 1, cost: 1},
+			},
+			want: []string{"b", "a", "b", "b", "a", "b"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := newFairQueue(1)
+			for _, req := range test.requests {
+				q.push(&waiter{cost: req.cost}, req.flow, req.weight)
+			}
+			got := []string{}
+			for q.Len() > 0 {
+				got = append(got, q.pop().flow.name)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected dispatch order (-want +got): %s", diff)
+			}
+			if q.pop() != nil || len(q.flows) != 0 || q.active.Len() != 0 {
+				t.Errorf("Got a non-empty queue after dispatching all its requests")
+			}
+		})
+	}
+}
+
+func TestFairQueueExpire(t *testing.T) {
+	now := time.Now()
+	q := newFairQueue(1)
+	expired := &waiter{deadline: now, cost: 1}
+	q.push(expired, "a", 1)
+	q.push(&waiter{deadline: now.Add(time.Second), cost: 1}, "a", 1)
+	q.push(&waiter{deadline: now, cost: 1}, "b", 1)
+
+	if got := q.expire(now); len(got) != 2 || got[0] != expired {
+		t.Errorf("Got %d expired requests, want the 2 requests past their deadline", len(got))
+	}
+	if q.Len() != 1 || len(q.flows) != 1 || expired.element != nil {
+		t.Errorf("Got %d queued requests in %d flows, want 1 request in 1 flow", q.Len(), len(q.flows))
+	}
+}
+
+type testFlowRequest struct {
+	flow   string
+	weight int
+	cost   int
+}
@@ -19,6 +19,7 @@ package handlers
 import (
 	"context"
 	"strconv"
+	"strings"
 	"time"
 
 	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -44,7 +45,7 @@ func (s *StreamingServer) HandleRequestHeaders(ctx context.Context, reqCtx *Requ
 		if err != nil {
 			return err
 		}
-		reqCtx.TargetEndpoint = pod.Address + ":" + strconv.Itoa(int(pool.Spec.TargetPortNumber))
+		reqCtx.TargetEndpoint = pod.Address + ":" + strconv.Itoa(int(pod.TargetPort(pool.Spec.TargetPortNumber)))
 		reqCtx.RequestSize = 0
 		reqCtx.reqHeaderResp = s.generateRequestHeaderResponse(reqCtx)
 		return nil
@@ -60,6 +61,18 @@ func (s *StreamingServer) HandleRequestHeaders(ctx context.Context, reqCtx *Requ
 	return nil
 }
 
+// extractGatewayAttribute returns the attribute of the request identified by the gateway under the
+// given key, e.g. its route, in the filter metadata of the ext-proc request, under the destination
+// endpoint hint metadata namespace, or else in the request headers if the gateway overwrites them.
+// The headers are ignored otherwise, as the clients could set them.
+func (s *StreamingServer) extractGatewayAttribute(req *extProcPb.ProcessingRequest, reqCtx *RequestContext, key string) string {
+	metadata := req.GetMetadataContext().GetFilterMetadata()[s.destinationEndpointHintMetadataNamespace]
+	if value := metadata.GetFields()[key].GetStringValue(); value != "" || !s.trustGatewayHeaders {
+		return value
+	}
+	return reqCtx.Request.Headers[key]
+}
+
 func (s *StreamingServer) generateRequestBodyResponses(requestBodyBytes []byte) []*extProcPb.ProcessingResponse {
 	commonResponses := buildCommonResponses(requestBodyBytes, bodyByteLimit, true)
 	responses := []*extProcPb.ProcessingResponse{}
@@ -91,7 +104,7 @@ func (s *StreamingServer) generateRequestHeaderResponse(reqCtx *RequestContext)
 				},
 			},
 		},
-		DynamicMetadata: s.generateMetadata(reqCtx.TargetEndpoint),
+		DynamicMetadata: s.generateMetadata(reqCtx),
 	}
 }
 
@@ -128,16 +141,30 @@ func (s *StreamingServer) generateHeaders(reqCtx *RequestContext) []*configPb.He
 	return headers
 }
 
-func (s *StreamingServer) generateMetadata(endpoint string) *structpb.Struct {
+func (s *StreamingServer) generateMetadata(reqCtx *RequestContext) *structpb.Struct {
 	targetEndpointValue := &structpb.Struct{
 		Fields: map[string]*structpb.Value{
 			s.destinationEndpointHintKey: {
 				Kind: &structpb.Value_StringValue{
-					StringValue: endpoint,
+					StringValue: reqCtx.TargetEndpoint,
 				},
 			},
 		},
 	}
+	if len(reqCtx.FallbackEndpoints) > 0 {
+		targetEndpointValue.Fields[s.destinationEndpointHintKey+fallbackEndpointsKeySuffix] = &structpb.Value{
+			Kind: &structpb.Value_StringValue{
+				StringValue: strings.Join(reqCtx.FallbackEndpoints, ","),
+			},
+		}
+	}
+	if len(reqCtx.PrewarmEndpoints) > 0 {
+		targetEndpointValue.Fields[s.destinationEndpointHintKey+prewarmEndpointsKeySuffix] = &structpb.Value{
+			Kind: &structpb.Value_StringValue{
+				StringValue: strings.Join(reqCtx.PrewarmEndpoints, ","),
+			},
+		}
+	}
 	dynamicMetadata := targetEndpointValue
 	if s.destinationEndpointHintMetadataNamespace != "" {
 		// If a namespace is defined, wrap the selected endpoint with that.
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"testing"
+
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGenerateMetadata(t *testing.T) {
+	tests := []struct {
+		name              string
+		namespace         string
+		fallbackEndpoints []string
+		prewarmEndpoints  []string
+		want              map[string]any
+	}{
+		{
+			name:      "target endpoint only",
+			namespace: "envoy.lb",
+			want: map[string]any{
+				"envoy.lb": map[string]any{"x-gateway-destination-endpoint": "10.0.0.1:8000"},
+			},
+		},
+		{
+			name:              "with fallback endpoints",
+			namespace:         "envoy.lb",
+			fallbackEndpoints: []string{"10.0.0.2:8000", "10.0.0.3:8000"},
+			want: map[string]any{
+				"envoy.lb": map[string]any{
+					"x-gateway-destination-endpoint":          "10.0.0.1:8000",
+					"x-gateway-destination-endpoint-fallback": "10.0.0.2:8000,10.0.0.3:8000",
+				},
+			},
+		},
+		{
+			name:             "with prewarm endpoints",
+			namespace:        "envoy.lb",
+			prewarmEndpoints: []string{"10.0.0.4:8000"},
+			want: map[string]any{
+				"envoy.lb": map[string]any{
+					"x-gateway-destination-endpoint":         "10.0.0.1:8000",
+					"x-gateway-destination-endpoint-prewarm": "10.0.0.4:8000",
+				},
+			},
+		},
+		{
+			name:              "without metadata namespace",
+			fallbackEndpoints: []string{"10.0.0.2:8000"},
+			want: map[string]any{
+				"x-gateway-destination-endpoint":          "10.0.0.1:8000",
+				"x-gateway-destination-endpoint-fallback": "10.0.0.2:8000",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewStreamingServer(test.namespace, "x-gateway-destination-endpoint", nil, nil)
+			reqCtx := &RequestContext{TargetEndpoint: "10.0.0.1:8000", FallbackEndpoints: test.fallbackEndpoints, PrewarmEndpoints: test.prewarmEndpoints}
+			got := server.generateMetadata(reqCtx).AsMap()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected metadata (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestExtractGatewayAttribute(t *testing.T) {
+	metadata := func(key, value string) *configPb.Metadata {
+		fields, _ := structpb.NewStruct(map[string]any{key: value})
+		return &configPb.Metadata{FilterMetadata: map[string]*structpb.Struct{"envoy.lb": fields}}
+	}
+	tests := []struct {
+		name           string
+		key            string
+		headers        map[string]string
+		trustedHeaders bool
+		metadata       *configPb.Metadata
+		want           string
+	}{
+		{
+			name: "no route",
+			key:  "x-gateway-inference-route",
+		},
+		{
+			name:    "route in the untrusted headers",
+			key:     "x-gateway-inference-route",
+			headers: map[string]string{"x-gateway-inference-route": "ns/route/rule"},
+		},
+		{
+			name:           "route in the trusted headers",
+			key:            "x-gateway-inference-route",
+			headers:        map[string]string{"x-gateway-inference-route": "ns/route/rule"},
+			trustedHeaders: true,
+			want:           "ns/route/rule",
+		},
+		{
+			name:     "route in the metadata",
+			key:      "x-gateway-inference-route",
+			metadata: metadata("x-gateway-inference-route", "ns/route"),
+			want:     "ns/route",
+		},
+		{
+			name:           "metadata takes precedence over the headers",
+			key:            "x-gateway-inference-route",
+			headers:        map[string]string{"x-gateway-inference-route": "ns/route/rule"},
+			trustedHeaders: true,
+			metadata:       metadata("x-gateway-inference-route", "ns/route"),
+			want:           "ns/route",
+		},
+		{
+			name:     "listener in the metadata",
+			key:      "x-gateway-inference-listener",
+			headers:  map[string]string{"x-gateway-inference-route": "ns/route/rule"},
+			metadata: metadata("x-gateway-inference-listener", "ns/gateway/http"),
+			want:     "ns/gateway/http",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewStreamingServer("envoy.lb", "x-gateway-destination-endpoint", nil, nil).WithGatewayHeaders(test.trustedHeaders)
+			reqCtx := &RequestContext{Request: &Request{Headers: test.headers}}
+			got := server.extractGatewayAttribute(&extProcPb.ProcessingRequest{MetadataContext: test.metadata}, reqCtx, test.key)
+			if got != test.want {
+				t.Errorf("extractGatewayAttribute() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
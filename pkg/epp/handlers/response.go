@@ -19,18 +19,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"strings"
+	"unicode/utf8"
 
 	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
-)
-
-const (
-	streamingRespPrefix = "data: "
-	streamingEndMsg     = "data: [DONE]"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 )
 
 // HandleResponseBody always returns the requestContext even in the error case, as the request context is used in error handling.
@@ -55,6 +51,16 @@ func (s *StreamingServer) HandleResponseBody(
 		reqCtx.Usage = usage
 		logger.V(logutil.VERBOSE).Info("Response generated", "usage", reqCtx.Usage)
 	}
+	choices, _ := response["choices"].([]interface{})
+	for _, choice := range choices {
+		if finishReason, _ := choice.(map[string]interface{})["finish_reason"].(string); finishReason != "" {
+			reqCtx.FinishReason = finishReason
+			break
+		}
+	}
+	if choices != nil {
+		s.captureResponseText(reqCtx, choiceText(choices))
+	}
 	reqCtx.ResponseSize = len(responseBytes)
 	// ResponseComplete is to indicate the response is complete. In non-streaming
 	// case, it will be set to be true once the response is processed; in
@@ -63,7 +69,8 @@ func (s *StreamingServer) HandleResponseBody(
 	// will add the processing for streaming case.
 	reqCtx.ResponseComplete = true
 
-	reqCtx.respBodyResp = generateResponseBodyResponses(responseBytes, true)
+	// The body only ends the stream if no trailers follow it.
+	reqCtx.respBodyResp = generateResponseBodyResponses(responseBytes, reqCtx.respTrailerResp == nil)
 	return reqCtx, nil
 }
 
@@ -73,11 +80,104 @@ func (s *StreamingServer) HandleResponseBodyModelStreaming(
 	reqCtx *RequestContext,
 	responseText string,
 ) {
-	if strings.Contains(responseText, streamingEndMsg) {
-		resp := parseRespForUsage(ctx, responseText)
-		reqCtx.Usage = resp.Usage
-		metrics.RecordInputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, resp.Usage.PromptTokens)
-		metrics.RecordOutputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, resp.Usage.CompletionTokens)
+	if reqCtx.sseParser == nil {
+		reqCtx.sseParser = &sseUsageParser{text: s.responseTextLimit > 0}
+	}
+	reqCtx.ResponseSize += len(responseText)
+	usage, err := reqCtx.sseParser.feed([]byte(responseText))
+	s.recordUsage(ctx, reqCtx, usage, err)
+	s.captureResponseText(reqCtx, usage.text)
+}
+
+// choiceText returns the text generated in the first of the given choices of a completions or
+// chat completions response.
+func choiceText(choices []interface{}) string {
+	for _, choice := range choices {
+		choice, _ := choice.(map[string]interface{})
+		if index, _ := choice["index"].(float64); index != 0 {
+			continue
+		}
+		if text, ok := choice["text"].(string); ok {
+			return text
+		}
+		message, _ := choice["message"].(map[string]interface{})
+		content, _ := message["content"].(string)
+		return content
+	}
+	return ""
+}
+
+// captureResponseText appends the given generated text to the text captured from the response, if
+// the server captures it. Only the end of the text is kept beyond the capture limit.
+func (s *StreamingServer) captureResponseText(reqCtx *RequestContext, text string) {
+	if s.responseTextLimit <= 0 {
+		return
+	}
+	reqCtx.ResponseTextCaptured = true
+	reqCtx.ResponseText += text
+	if excess := len(reqCtx.ResponseText) - s.responseTextLimit; excess > 0 {
+		// The text is cut at the start of a character.
+		for excess < len(reqCtx.ResponseText) && !utf8.RuneStart(reqCtx.ResponseText[excess]) {
+			excess++
+		}
+		reqCtx.ResponseText = reqCtx.ResponseText[excess:]
+	}
+}
+
+// HandleResponseBodyGRPC handles a chunk of a gRPC or gRPC-Web response, which is passed through
+// as is. The usage is parsed from the JSON encoded messages, and the status from the trailers of
+// the gRPC-Web responses, which come in the body.
+func (s *StreamingServer) HandleResponseBodyGRPC(ctx context.Context, reqCtx *RequestContext, chunk []byte) {
+	reqCtx.ResponseSize += len(chunk)
+	usage, trailers, err := reqCtx.grpcParser.feed(chunk)
+	s.recordUsage(ctx, reqCtx, usage, err)
+	if trailers != nil {
+		s.handleTrailers(ctx, reqCtx, trailers)
+	}
+}
+
+// HandleResponseTrailers handles the trailers of a response, which end it if its body didn't, e.g.
+// for the gRPC responses.
+func (s *StreamingServer) HandleResponseTrailers(ctx context.Context, reqCtx *RequestContext, resp *extProcPb.ProcessingRequest_ResponseTrailers) {
+	trailers := map[string]string{}
+	for _, header := range resp.ResponseTrailers.Trailers.GetHeaders() {
+		if header.RawValue != nil {
+			trailers[header.Key] = string(header.RawValue)
+		} else {
+			trailers[header.Key] = header.Value
+		}
+	}
+	s.handleTrailers(ctx, reqCtx, trailers)
+	reqCtx.respTrailerResp = generateResponseTrailerResponse()
+}
+
+func (s *StreamingServer) handleTrailers(ctx context.Context, reqCtx *RequestContext, trailers map[string]string) {
+	if reqCtx.Response.Trailers == nil {
+		reqCtx.Response.Trailers = map[string]string{}
+	}
+	for key, value := range trailers {
+		reqCtx.Response.Trailers[key] = value
+	}
+	if grpcStatusFailed(trailers) {
+		log.FromContext(ctx).V(logutil.VERBOSE).Info("Response failed", "grpcStatus", trailers[grpcStatusKey])
+		reqCtx.ResponseStatusCode = errutil.ModelServerError
+	}
+}
+
+// recordUsage records the usage and the finish reason parsed from a chunk of a response, if it has
+// them. The parse errors are only logged, as the response is passed through whatever its content.
+func (s *StreamingServer) recordUsage(ctx context.Context, reqCtx *RequestContext, usage parsedUsage, err error) {
+	logger := log.FromContext(ctx)
+	if err != nil {
+		logger.V(logutil.DEFAULT).Error(err, "Error parsing the usage of the response")
+	}
+	reqCtx.streamedMessages += usage.messages
+	if usage.finishReason != "" {
+		reqCtx.FinishReason = usage.finishReason
+	}
+	if usage.usage != nil {
+		reqCtx.Usage = *usage.usage
+		logger.V(logutil.VERBOSE).Info("Response generated", "usage", reqCtx.Usage, "finishReason", reqCtx.FinishReason)
 	}
 }
 
@@ -92,6 +192,12 @@ func (s *StreamingServer) HandleResponseHeaders(ctx context.Context, reqCtx *Req
 
 	reqCtx, err := s.director.HandleResponse(ctx, reqCtx)
 
+	// A response without a body ends with its headers, which for gRPC hold the trailers as well,
+	// i.e. a trailers-only response.
+	if resp.ResponseHeaders.EndOfStream && grpcStatusFailed(reqCtx.Response.Headers) {
+		reqCtx.ResponseStatusCode = errutil.ModelServerError
+	}
+
 	return reqCtx, err
 }
 
@@ -125,6 +231,14 @@ func generateResponseBodyResponses(responseBodyBytes []byte, setEoS bool) []*ext
 	return responses
 }
 
+func generateResponseTrailerResponse() *extProcPb.ProcessingResponse {
+	return &extProcPb.ProcessingResponse{
+		Response: &extProcPb.ProcessingResponse_ResponseTrailers{
+			ResponseTrailers: &extProcPb.TrailersResponse{},
+		},
+	}
+}
+
 func (s *StreamingServer) generateResponseHeaders(reqCtx *RequestContext) []*configPb.HeaderValueOption {
 	// can likely refactor these two bespoke headers to be updated in PostDispatch, to centralize logic.
 	headers := []*configPb.HeaderValueOption{
@@ -146,45 +260,15 @@ func (s *StreamingServer) generateResponseHeaders(reqCtx *RequestContext) []*con
 			},
 		})
 	}
-	return headers
-}
-
-// Example message if "stream_options": {"include_usage": "true"} is included in the request:
-// data: {"id":"...","object":"text_completion","created":1739400043,"model":"food-review-0","choices":[],
-// "usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}
-//
-// data: [DONE]
-//
-// Noticed that vLLM returns two entries in one response.
-// We need to strip the `data:` prefix and next Data: [DONE] from the message to fetch response data.
-//
-// If include_usage is not included in the request, `data: [DONE]` is returned separately, which
-// indicates end of streaming.
-func parseRespForUsage(
-	ctx context.Context,
-	responseText string,
-) ResponseBody {
-	response := ResponseBody{}
-	logger := log.FromContext(ctx)
-
-	lines := strings.Split(responseText, "\n")
-	for _, line := range lines {
-		if !strings.HasPrefix(line, streamingRespPrefix) {
-			continue
-		}
-		content := strings.TrimPrefix(line, streamingRespPrefix)
-		if content == "[DONE]" {
-			continue
-		}
-
-		byteSlice := []byte(content)
-		if err := json.Unmarshal(byteSlice, &response); err != nil {
-			logger.Error(err, "unmarshaling response body")
-			continue
-		}
+	if reqCtx.SchedulingTrace != "" {
+		headers = append(headers, &configPb.HeaderValueOption{
+			Header: &configPb.HeaderValue{
+				Key:      requtil.SchedulingTraceIDHeaderKey,
+				RawValue: []byte(reqCtx.SchedulingTrace),
+			},
+		})
 	}
-
-	return response
+	return headers
 }
 
 type ResponseBody struct {
@@ -0,0 +1,308 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	grpcContentType = "application/grpc"
+	// grpcWebTextContentType is the gRPC-Web variant whose frames are base64 encoded.
+	grpcWebTextContentType = "application/grpc-web-text"
+	grpcWebContentType     = "application/grpc-web"
+	grpcStatusKey          = "grpc-status"
+
+	grpcFrameHeaderLen = 5
+	// grpcTrailersFlag marks the gRPC-Web frame carrying the trailers, which are sent in the body
+	// as HTTP/1.1 has no trailers in practice.
+	grpcTrailersFlag = 0x80
+	// grpcCompressedFlag marks a frame whose message is compressed.
+	grpcCompressedFlag = 0x01
+)
+
+// parsedUsage is what is parsed from the messages of a response: the usage of the response, nil if
+// none was parsed, the reason its generation finished, empty if none was parsed, the number of
+// messages parsed, the end of stream marker excluded, and the text generated in the messages, only
+// parsed if requested.
+type parsedUsage struct {
+	usage        *Usage
+	finishReason string
+	messages     int
+	text         string
+}
+
+// merge overrides the parsed fields with the ones parsed from later messages.
+func (p *parsedUsage) merge(later parsedUsage) {
+	if later.usage != nil {
+		p.usage = later.usage
+	}
+	if later.finishReason != "" {
+		p.finishReason = later.finishReason
+	}
+	p.messages += later.messages
+	p.text += later.text
+}
+
+// sseUsageParser extracts the usage from a server-sent events stream.
+//
+// Example message if "stream_options": {"include_usage": "true"} is included in the request:
+// data: {"id":"...","object":"text_completion","created":1739400043,"model":"food-review-0","choices":[],
+// "usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}
+//
+// data: [DONE]
+//
+// The usage event and `data: [DONE]` may come in the same body chunk or in different ones, and
+// the gateway may split the stream anywhere, in the middle of an event included, so the incomplete
+// last line of a chunk is kept until the next chunk completes it. The chunks often hold whole events
+// without their trailing newlines though, so a last line holding a whole event is parsed right away.
+type sseUsageParser struct {
+	partial []byte
+	// text is true if the text generated in the events is parsed as well, in which case all the
+	// events are decoded.
+	text bool
+}
+
+// feed parses the complete lines of the chunk, and returns the usage of the last event having one.
+func (p *sseUsageParser) feed(chunk []byte) (parsedUsage, error) {
+	data := append(p.partial, chunk...)
+	p.partial = nil
+	last := data[bytes.LastIndexByte(data, '\n')+1:]
+	if !sseLineComplete(last) {
+		p.partial = bytes.Clone(last)
+		data = data[:len(data)-len(last)]
+	}
+	return parseSSEUsage(data, p.text)
+}
+
+// flush parses what is left of the stream once it ended, as the last line may lack its newline.
+func (p *sseUsageParser) flush() (parsedUsage, error) {
+	data := p.partial
+	p.partial = nil
+	return parseSSEUsage(data, p.text)
+}
+
+// sseLineComplete returns true if the line is empty or holds a whole data event.
+func sseLineComplete(line []byte) bool {
+	if len(line) == 0 {
+		return true
+	}
+	content, ok := bytes.CutPrefix(bytes.TrimRight(line, "\r"), []byte("data:"))
+	content = bytes.TrimSpace(content)
+	return ok && (string(content) == "[DONE]" || json.Valid(content))
+}
+
+func parseSSEUsage(data []byte, text bool) (parsedUsage, error) {
+	var usage parsedUsage
+	var errs []error
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		// The space after the field name is optional, and the lines may end with a CRLF.
+		content, ok := bytes.CutPrefix(bytes.TrimRight(line, "\r"), []byte("data:"))
+		if !ok {
+			continue
+		}
+		content = bytes.TrimPrefix(content, []byte(" "))
+		if string(bytes.TrimSpace(content)) == "[DONE]" {
+			continue
+		}
+		usage.messages++
+		if lineUsage, err := parseUsage(content, text); err != nil {
+			errs = append(errs, err)
+		} else {
+			usage.merge(lineUsage)
+		}
+	}
+	if len(errs) > 0 {
+		return usage, fmt.Errorf("failed to parse %d event(s) of the stream: %v", len(errs), errs)
+	}
+	return usage, nil
+}
+
+// parseUsage returns the usage and the finish reason of a JSON message, and the text generated in
+// its first choice if requested. Unless the text is requested, the messages are only decoded if
+// they might have the usage or the finish reason, as most of the streamed messages carry a single
+// token.
+func parseUsage(message []byte, text bool) (parsedUsage, error) {
+	if !text && !bytes.Contains(message, []byte(`"usage"`)) && !hasFinishReason(message) {
+		return parsedUsage{}, nil
+	}
+	response := struct {
+		Usage   *Usage `json:"usage"`
+		Choices []struct {
+			Index        int     `json:"index"`
+			FinishReason *string `json:"finish_reason"`
+			// Text is the text of the completions, and Delta the one of the streamed chat
+			// completions.
+			Text  string `json:"text"`
+			Delta struct {
+				Content any `json:"content"`
+			} `json:"delta"`
+		} `json:"choices"`
+	}{}
+	if err := json.Unmarshal(message, &response); err != nil {
+		return parsedUsage{}, err
+	}
+	parsed := parsedUsage{usage: response.Usage}
+	for _, choice := range response.Choices {
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			parsed.finishReason = *choice.FinishReason
+			break
+		}
+	}
+	if text {
+		for _, choice := range response.Choices {
+			if choice.Index == 0 {
+				content, _ := choice.Delta.Content.(string)
+				parsed.text = choice.Text + content
+				break
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// hasFinishReason returns true if the message might set a finish reason, i.e. has a finish_reason
+// field that isn't null, as the streamed messages carry a null one until the last token.
+func hasFinishReason(message []byte) bool {
+	key := []byte(`"finish_reason"`)
+	for {
+		i := bytes.Index(message, key)
+		if i < 0 {
+			return false
+		}
+		message = bytes.TrimLeft(message[i+len(key):], " \t\r\n")
+		if value, ok := bytes.CutPrefix(message, []byte(":")); ok && bytes.HasPrefix(bytes.TrimLeft(value, " \t\r\n"), []byte(`"`)) {
+			return true
+		}
+	}
+}
+
+// grpcFrameParser splits a gRPC or gRPC-Web response body into its length-prefixed messages. The
+// body may be split into chunks anywhere, so the incomplete frame ending a chunk is kept until the
+// next chunks complete it.
+type grpcFrameParser struct {
+	// text is true for application/grpc-web-text, whose frames are base64 encoded.
+	text bool
+	// json is true if the messages are JSON encoded, e.g. application/grpc-web+json, in which case
+	// they are parsed for the usage.
+	json bool
+
+	encoded []byte // base64 characters not decoded yet, for the text variant
+	buf     []byte
+}
+
+// newGRPCFrameParser returns a parser for the given response content type, or nil if the response
+// isn't a gRPC or gRPC-Web one.
+func newGRPCFrameParser(contentType string) *grpcFrameParser {
+	contentType, _, _ = strings.Cut(strings.ToLower(contentType), ";")
+	contentType = strings.TrimSpace(contentType)
+	if contentType != grpcContentType && !strings.HasPrefix(contentType, grpcContentType+"+") &&
+		!strings.HasPrefix(contentType, grpcWebContentType) {
+		return nil
+	}
+	return &grpcFrameParser{
+		text: strings.HasPrefix(contentType, grpcWebTextContentType),
+		json: strings.HasSuffix(contentType, "+json"),
+	}
+}
+
+// feed parses the complete frames of the chunk. It returns the usage of the last message having
+// one, and the trailers if the chunk completed the gRPC-Web trailers frame.
+func (p *grpcFrameParser) feed(chunk []byte) (parsedUsage, map[string]string, error) {
+	if p.text {
+		decoded, err := p.decodeText(chunk)
+		if err != nil {
+			return parsedUsage{}, nil, err
+		}
+		chunk = decoded
+	}
+	p.buf = append(p.buf, chunk...)
+
+	var usage parsedUsage
+	var trailers map[string]string
+	for len(p.buf) >= grpcFrameHeaderLen {
+		flags := p.buf[0]
+		length := int(binary.BigEndian.Uint32(p.buf[1:grpcFrameHeaderLen]))
+		if len(p.buf)-grpcFrameHeaderLen < length {
+			break
+		}
+		payload := p.buf[grpcFrameHeaderLen : grpcFrameHeaderLen+length]
+		switch {
+		case flags&grpcTrailersFlag != 0:
+			trailers = parseGRPCWebTrailers(payload)
+		case p.json && flags&grpcCompressedFlag == 0:
+			usage.messages++
+			messageUsage, err := parseUsage(payload, false)
+			if err != nil {
+				return usage, trailers, err
+			}
+			usage.merge(messageUsage)
+		default:
+			usage.messages++
+		}
+		p.buf = p.buf[grpcFrameHeaderLen+length:]
+	}
+	p.buf = bytes.Clone(p.buf)
+	return usage, trailers, nil
+}
+
+// decodeText decodes the complete base64 quanta of the chunk. Each quantum is decoded on its own,
+// as the gRPC-Web messages are encoded separately and so may have padding in the middle of the body.
+func (p *grpcFrameParser) decodeText(chunk []byte) ([]byte, error) {
+	for _, c := range chunk {
+		if c != '\r' && c != '\n' {
+			p.encoded = append(p.encoded, c)
+		}
+	}
+	decoded := []byte{}
+	quantum := make([]byte, 3)
+	for len(p.encoded) >= 4 {
+		n, err := base64.StdEncoding.Decode(quantum, p.encoded[:4])
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, quantum[:n]...)
+		p.encoded = p.encoded[4:]
+	}
+	p.encoded = bytes.Clone(p.encoded)
+	return decoded, nil
+}
+
+// parseGRPCWebTrailers parses the trailers frame of a gRPC-Web response, which holds the trailers
+// in the HTTP/1.1 header format, e.g. "grpc-status: 0\r\ngrpc-message: OK\r\n".
+func parseGRPCWebTrailers(payload []byte) map[string]string {
+	trailers := map[string]string{}
+	for _, line := range strings.Split(string(payload), "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !ok {
+			continue
+		}
+		trailers[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return trailers
+}
+
+// grpcStatusFailed returns true if the grpc-status of a response is set to an error code.
+func grpcStatusFailed(headers map[string]string) bool {
+	code, ok := headers[grpcStatusKey]
+	return ok && code != "0"
+}
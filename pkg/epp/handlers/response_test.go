@@ -18,10 +18,13 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
@@ -154,3 +157,334 @@ func TestHandleStreamedResponseBody(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleStreamedResponseBodyChunks(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	tests := []struct {
+		name   string
+		chunks []string
+		want   Usage
+	}{
+		{
+			name: "usage and done in separate chunks",
+			chunks: []string{
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"total_tokens\":17,\"completion_tokens\":10}}\n\n",
+				"data: [DONE]\n\n",
+			},
+			want: Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name: "usage split across chunks",
+			chunks: []string{
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"total_",
+				"tokens\":17,\"completion_tokens\":10}}\n\ndata: [DONE]\n\n",
+			},
+			want: Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name: "crlf line endings without space after data",
+			chunks: []string{
+				"data:{\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"total_tokens\":5,\"completion_tokens\":2}}\r\n\r\n",
+			},
+			want: Usage{PromptTokens: 3, TotalTokens: 5, CompletionTokens: 2},
+		},
+		{
+			name: "last usage wins",
+			chunks: []string{
+				"data: {\"usage\":{\"prompt_tokens\":3,\"total_tokens\":4,\"completion_tokens\":1}}\n\n",
+				"data: {\"usage\":{\"prompt_tokens\":3,\"total_tokens\":5,\"completion_tokens\":2}}\n\n",
+				"data: {\"usage\":null}\n\n",
+			},
+			want: Usage{PromptTokens: 3, TotalTokens: 5, CompletionTokens: 2},
+		},
+		{
+			name: "events without newlines",
+			chunks: []string{
+				"data: {\"choices\":[{\"text\":\"hi\"}],\"usage\":null}",
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"total_tokens\":17,\"completion_tokens\":10}}",
+				"data: [DONE]",
+			},
+			want: Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+		},
+		{
+			name: "last line without newline",
+			chunks: []string{
+				"data: {\"usage\":{\"prompt_tokens\":3,\"total_tokens\":5,\"completion_tokens\":2}}",
+			},
+			want: Usage{PromptTokens: 3, TotalTokens: 5, CompletionTokens: 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{}
+			reqCtx := &RequestContext{modelServerStreaming: true}
+			for _, chunk := range test.chunks {
+				server.HandleResponseBodyModelStreaming(ctx, reqCtx, chunk)
+			}
+			usage, err := reqCtx.sseParser.flush()
+			server.recordUsage(ctx, reqCtx, usage, err)
+
+			if diff := cmp.Diff(test.want, reqCtx.Usage); diff != "" {
+				t.Errorf("HandleResponseBodyModelStreaming returned unexpected usage, diff(-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestHandleResponseFinishReason(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{
+			name: "null finish reasons until the last token",
+			chunks: []string{
+				"data: {\"choices\":[{\"text\":\"hi\",\"finish_reason\":null}]}\n\n",
+				"data: {\"choices\":[{\"text\":\"!\",\"finish_reason\" : \"length\"}]}\n\n",
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"total_tokens\":5,\"completion_tokens\":2}}\n\n",
+				"data: [DONE]\n\n",
+			},
+			want: "length",
+		},
+		{
+			name: "finish reason in the usage event",
+			chunks: []string{
+				"data: {\"choices\":[{\"text\":\"hi\",\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":3,\"total_tokens\":4,\"completion_tokens\":1}}\n\n",
+			},
+			want: "stop",
+		},
+		{
+			name: "no finish reason",
+			chunks: []string{
+				"data: {\"choices\":[{\"text\":\"finish_reason\",\"finish_reason\":null}]}\n\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{}
+			reqCtx := &RequestContext{modelServerStreaming: true}
+			for _, chunk := range test.chunks {
+				server.HandleResponseBodyModelStreaming(ctx, reqCtx, chunk)
+			}
+			usage, err := reqCtx.sseParser.flush()
+			server.recordUsage(ctx, reqCtx, usage, err)
+
+			if reqCtx.FinishReason != test.want {
+				t.Errorf("HandleResponseBodyModelStreaming recorded finish reason %q, want %q", reqCtx.FinishReason, test.want)
+			}
+		})
+	}
+
+	reqCtx := &RequestContext{}
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("Error unmarshaling response body: %v", err)
+	}
+	if _, err := (&StreamingServer{}).HandleResponseBody(ctx, reqCtx, response); err != nil {
+		t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+	}
+	if reqCtx.FinishReason != "length" {
+		t.Errorf("HandleResponseBody recorded finish reason %q, want %q", reqCtx.FinishReason, "length")
+	}
+}
+
+func TestCaptureResponseText(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	tests := []struct {
+		name         string
+		limit        int
+		chunks       []string
+		wantText     string
+		wantCaptured bool
+	}{
+		{
+			name:  "streamed chat completion",
+			limit: 100,
+			chunks: []string{
+				"data: {\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\"}}]}\n\n",
+				"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\ndata: {\"choices\":[{\"index\":0,\"delta\":{\"cont",
+				"ent\":\" world\"},\"finish_reason\":\"stop\"}]}\n\n",
+				"data: [DONE]\n\n",
+			},
+			wantText:     "Hello world",
+			wantCaptured: true,
+		},
+		{
+			name:  "streamed completion of the first choice only",
+			limit: 100,
+			chunks: []string{
+				"data: {\"choices\":[{\"index\":1,\"text\":\"other\"},{\"index\":0,\"text\":\"first\"}]}\n\n",
+				"data: {\"choices\":[{\"index\":1,\"text\":\" choice\"}]}",
+			},
+			wantText:     "first",
+			wantCaptured: true,
+		},
+		{
+			name:  "end of the text kept at the limit, from a character start",
+			limit: 4,
+			chunks: []string{
+				"data: {\"choices\":[{\"index\":0,\"text\":\"Hello\"}]}\n\n",
+				"data: {\"choices\":[{\"index\":0,\"text\":\" wörld\"}]}\n\n",
+			},
+			wantText:     "rld",
+			wantCaptured: true,
+		},
+		{
+			name:  "empty response",
+			limit: 100,
+			chunks: []string{
+				"data: {\"choices\":[{\"index\":0,\"text\":\"\",\"finish_reason\":\"stop\"}]}\n\n",
+			},
+			wantCaptured: true,
+		},
+		{
+			name: "not captured",
+			chunks: []string{
+				"data: {\"choices\":[{\"index\":0,\"text\":\"Hello\"}]}\n\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := (&StreamingServer{}).WithResponseTextCapture(test.limit)
+			reqCtx := &RequestContext{modelServerStreaming: true}
+			for _, chunk := range test.chunks {
+				server.HandleResponseBodyModelStreaming(ctx, reqCtx, chunk)
+			}
+			usage, err := reqCtx.sseParser.flush()
+			server.recordUsage(ctx, reqCtx, usage, err)
+			server.captureResponseText(reqCtx, usage.text)
+
+			if reqCtx.ResponseText != test.wantText || reqCtx.ResponseTextCaptured != test.wantCaptured {
+				t.Errorf("Captured text %q (captured: %t), want %q (captured: %t)", reqCtx.ResponseText, reqCtx.ResponseTextCaptured, test.wantText, test.wantCaptured)
+			}
+		})
+	}
+
+	for _, body := range []string{
+		`{"choices":[{"index":0,"text":"Hello","finish_reason":"stop"}]}`,
+		`{"choices":[{"index":0,"message":{"role":"assistant","content":"Hello"},"finish_reason":"stop"}]}`,
+	} {
+		reqCtx := &RequestContext{}
+		var response map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &response); err != nil {
+			t.Fatalf("Error unmarshaling response body: %v", err)
+		}
+		if _, err := (&StreamingServer{}).WithResponseTextCapture(100).HandleResponseBody(ctx, reqCtx, response); err != nil {
+			t.Fatalf("HandleResponseBody returned unexpected error: %v", err)
+		}
+		if reqCtx.ResponseText != "Hello" || !reqCtx.ResponseTextCaptured {
+			t.Errorf("HandleResponseBody captured text %q (captured: %t) from %s, want %q", reqCtx.ResponseText, reqCtx.ResponseTextCaptured, body, "Hello")
+		}
+	}
+}
+
+func grpcFrame(flags byte, payload string) []byte {
+	frame := []byte{flags, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func TestHandleResponseBodyGRPC(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	usageMessage := `{"usage":{"prompt_tokens":7,"total_tokens":17,"completion_tokens":10}}`
+	body := append(grpcFrame(0, `{"text":"hello"}`), grpcFrame(0, usageMessage)...)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		chunks         [][]byte
+		wantUsage      Usage
+		wantTrailers   map[string]string
+		wantStatusCode string
+	}{
+		{
+			name:        "grpc-web json split in chunks",
+			contentType: "application/grpc-web+json",
+			chunks: [][]byte{
+				body[:3],
+				body[3:25],
+				append(body[25:], grpcFrame(grpcTrailersFlag, "grpc-status: 0\r\ngrpc-message: OK\r\n")...),
+			},
+			wantUsage:    Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+			wantTrailers: map[string]string{"grpc-status": "0", "grpc-message": "OK"},
+		},
+		{
+			name:        "grpc-web failed status",
+			contentType: "application/grpc-web+proto",
+			chunks: [][]byte{
+				append(grpcFrame(0, "\x0a\x02hi"), grpcFrame(grpcTrailersFlag, "grpc-status: 14\r\n")...),
+			},
+			wantTrailers:   map[string]string{"grpc-status": "14"},
+			wantStatusCode: errutil.ModelServerError,
+		},
+		{
+			name:        "grpc-web-text encoded per message",
+			contentType: "application/grpc-web-text+json",
+			chunks: [][]byte{
+				[]byte(base64.StdEncoding.EncodeToString(grpcFrame(0, usageMessage))[:10]),
+				[]byte(base64.StdEncoding.EncodeToString(grpcFrame(0, usageMessage))[10:] +
+					base64.StdEncoding.EncodeToString(grpcFrame(grpcTrailersFlag, "grpc-status: 0\r\n"))),
+			},
+			wantUsage:    Usage{PromptTokens: 7, TotalTokens: 17, CompletionTokens: 10},
+			wantTrailers: map[string]string{"grpc-status": "0"},
+		},
+		{
+			name:         "grpc proto messages are passed through",
+			contentType:  "application/grpc",
+			chunks:       [][]byte{grpcFrame(0, "\x0a\x02hi")},
+			wantTrailers: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &StreamingServer{}
+			reqCtx := &RequestContext{
+				Response:   &Response{Headers: map[string]string{}, Trailers: map[string]string{}},
+				grpcParser: newGRPCFrameParser(test.contentType),
+			}
+			if reqCtx.grpcParser == nil {
+				t.Fatalf("Content type %q isn't detected as gRPC", test.contentType)
+			}
+			for _, chunk := range test.chunks {
+				server.HandleResponseBodyGRPC(ctx, reqCtx, chunk)
+			}
+
+			if diff := cmp.Diff(test.wantUsage, reqCtx.Usage); diff != "" {
+				t.Errorf("HandleResponseBodyGRPC returned unexpected usage, diff(-want, +got): %v", diff)
+			}
+			if diff := cmp.Diff(test.wantTrailers, reqCtx.Response.Trailers); diff != "" {
+				t.Errorf("HandleResponseBodyGRPC returned unexpected trailers, diff(-want, +got): %v", diff)
+			}
+			if reqCtx.ResponseStatusCode != test.wantStatusCode {
+				t.Errorf("HandleResponseBodyGRPC set status code %q, want %q", reqCtx.ResponseStatusCode, test.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestNewGRPCFrameParser(t *testing.T) {
+	for contentType, want := range map[string]*grpcFrameParser{
+		"application/grpc":                    {},
+		"application/grpc+proto":              {},
+		"application/grpc-web":                {},
+		"application/grpc-web+json":           {json: true},
+		"application/grpc-web-text":           {text: true},
+		"Application/gRPC-Web-Text+json; a=b": {text: true, json: true},
+		"application/json":                    nil,
+		"application/grpcfoo":                 nil,
+		"text/event-stream; charset=utf-8":    nil,
+	} {
+		got := newGRPCFrameParser(contentType)
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(grpcFrameParser{})); diff != "" {
+			t.Errorf("newGRPCFrameParser(%q) returned unexpected parser, diff(-want, +got): %v", contentType, diff)
+		}
+	}
+}
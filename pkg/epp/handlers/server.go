@@ -19,19 +19,32 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	configPb "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestlookup"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/throughput"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/timeline"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tracing"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
@@ -40,6 +53,15 @@ import (
 const (
 	// Certain envoy implementations set a max limit of 64Kb per streamed chunk, intentionally setting this lower for a safe margin.
 	bodyByteLimit = 62000
+
+	// fallbackEndpointsKeySuffix is appended to the destination endpoint hint key to form the
+	// metadata key of the fallback endpoints, i.e. x-gateway-destination-endpoint-fallback by default.
+	fallbackEndpointsKeySuffix = "-fallback"
+	// prewarmEndpointsKeySuffix is appended to the destination endpoint hint key to form the
+	// metadata key of the endpoints that recently joined the pool, which the gateway may open
+	// connections to ahead of routing requests to them, i.e. x-gateway-destination-endpoint-prewarm
+	// by default.
+	prewarmEndpointsKeySuffix = "-prewarm"
 )
 
 func NewStreamingServer(destinationEndpointHintMetadataNamespace, destinationEndpointHintKey string, datastore Datastore, director Director) *StreamingServer {
@@ -54,6 +76,11 @@ func NewStreamingServer(destinationEndpointHintMetadataNamespace, destinationEnd
 type Director interface {
 	HandleRequest(ctx context.Context, reqCtx *RequestContext) (*RequestContext, error)
 	HandleResponse(ctx context.Context, reqCtx *RequestContext) (*RequestContext, error)
+	HandleResponseComplete(ctx context.Context, reqCtx *RequestContext)
+	// HandleResponseProgress is invoked after every chunk of a streamed response.
+	HandleResponseProgress(ctx context.Context, reqCtx *RequestContext)
+	// HandleRequestEnd is invoked once the processing of the request ended, whatever its outcome.
+	HandleRequestEnd(ctx context.Context, reqCtx *RequestContext)
 	GetRandomPod() *backend.Pod
 }
 
@@ -72,6 +99,48 @@ type StreamingServer struct {
 	destinationEndpointHintMetadataNamespace string
 	datastore                                Datastore
 	director                                 Director
+	timelines                                *timeline.Recorder   // nil unless slow request timelines are recorded
+	throughput                               *throughput.Tracker  // nil unless the throughput per listener is tracked
+	requests                                 *requestlookup.Store // nil unless the requests can be looked up
+	responseTextLimit                        int                  // zero unless the generated text of the responses is captured
+	trustGatewayHeaders                      bool                 // whether the gateway overwrites the headers of the attributes it identifies
+}
+
+// WithGatewayHeaders makes the server read the attributes of the requests identified by the gateway,
+// e.g. their route, from the request headers when they are missing from the filter metadata. It must
+// only be set if the gateway overwrites those headers, as the clients could set them otherwise.
+func (s *StreamingServer) WithGatewayHeaders(trusted bool) *StreamingServer {
+	s.trustGatewayHeaders = trusted
+	return s
+}
+
+// WithTimelineRecorder makes the server record the timeline of every request with the given
+// Recorder, which retains those of the slow requests.
+func (s *StreamingServer) WithTimelineRecorder(recorder *timeline.Recorder) *StreamingServer {
+	s.timelines = recorder
+	return s
+}
+
+// WithThroughputTracker makes the server account the output tokens of the responses to the gateway
+// listener of their requests in the given Tracker.
+func (s *StreamingServer) WithThroughputTracker(tracker *throughput.Tracker) *StreamingServer {
+	s.throughput = tracker
+	return s
+}
+
+// WithRequestLookup makes the server retain the record of every request with an ID in the given
+// Store, so it can be looked up.
+func (s *StreamingServer) WithRequestLookup(store *requestlookup.Store) *StreamingServer {
+	s.requests = store
+	return s
+}
+
+// WithResponseTextCapture makes the server capture the text generated in the responses, up to the
+// given number of bytes at their end, so the plugins can analyze it once the responses completed.
+// The streamed events are all decoded to capture their text, instead of only the last ones.
+func (s *StreamingServer) WithResponseTextCapture(limit int) *StreamingServer {
+	s.responseTextLimit = limit
+	return s
 }
 
 // RequestContext stores context information during the life time of an HTTP request.
@@ -79,22 +148,70 @@ type StreamingServer struct {
 // Specifically, there are fields related to the ext-proc protocol, and then fields related to the lifecycle of the request.
 // We should split these apart as this monolithic object exposes too much data to too many layers.
 type RequestContext struct {
-	TargetPod                 string
+	TargetPod string
+	// TargetPodRequestsInFlight is the count of the requests in flight of the target pod the request
+	// is counted in until it ends, nil if it isn't.
+	TargetPodRequestsInFlight *backendmetrics.RequestsInFlight
+	// TargetPodOutstandingTokens is the number of tokens of the request counted as outstanding for
+	// its target pod in TargetPodRequestsInFlight.
+	TargetPodOutstandingTokens int
+	// EstimatedPromptTokens and EstimatedOutputTokens are the estimated number of tokens of the
+	// prompt and of the response of the request.
+	EstimatedPromptTokens     int
+	EstimatedOutputTokens     int
 	TargetEndpoint            string
+	FallbackEndpoints         []string // retried by the gateway if the target endpoint can't be reached, in order of preference
+	PrewarmEndpoints          []string // recently joined the pool, hinted to the gateway to open connections to
 	Model                     string
 	ResolvedTargetModel       string
 	RequestReceivedTimestamp  time.Time
 	ResponseCompleteTimestamp time.Time
-	RequestSize               int
-	Usage                     Usage
-	ResponseSize              int
-	ResponseComplete          bool
-	ResponseStatusCode        string
-	RequestRunning            bool
-	Request                   *Request
+	// ResponseFirstChunkTimestamp is when the first chunk of the response body was received, which
+	// approximates the time to first token of streamed responses.
+	ResponseFirstChunkTimestamp time.Time
+	RequestSize                 int
+	Usage                       Usage
+	// QueueTime is the time the request waited in the flow control queue.
+	QueueTime time.Duration
+	// FinishReason is why the model server finished generating the response, e.g. "stop" or
+	// "length", as reported in its first choice that has one. Empty if not reported.
+	FinishReason       string
+	ResponseSize       int
+	ResponseComplete   bool
+	ResponseStatusCode string
+	RequestRunning     bool
+	// ResponseText is the text generated in the first choice of the response, or its end if longer
+	// than the capture limit. ResponseTextCaptured is false if the server doesn't capture the text,
+	// or the response isn't a completions or chat completions one.
+	ResponseText         string
+	ResponseTextCaptured bool
+	// Route is the route of the request identified by the gateway, as "namespace/name" or
+	// "namespace/name/rule". Empty if the gateway doesn't identify the route.
+	Route string
+	// Listener is the gateway listener the request was received on, as
+	// "namespace/gateway/listener". Empty if the gateway doesn't identify the listener.
+	Listener string
+	// QuotaClient is the client the request is counted in flight for by the quotas, with the lease
+	// QuotaLease, empty if the request isn't.
+	QuotaClient string
+	QuotaLease  string
+	// QuotaNamespace is the namespace the request is counted in flight for by the namespace quotas,
+	// with the lease QuotaNamespaceLease, empty if the request isn't.
+	QuotaNamespace      string
+	QuotaNamespaceLease string
+	// SchedulingTrace is the summary of the scheduling trace of the request stamped into its
+	// response headers, empty if none.
+	SchedulingTrace string
+	Request         *Request
 
 	RequestState         StreamRequestState
 	modelServerStreaming bool
+	// sseParser and grpcParser keep the incomplete parts of the streamed response bodies across
+	// their chunks. grpcParser is set for the gRPC and gRPC-Web responses only.
+	sseParser  *sseUsageParser
+	grpcParser *grpcFrameParser
+	// streamedMessages is the number of messages streamed in the response so far.
+	streamedMessages int
 
 	Response *Response
 
@@ -113,6 +230,9 @@ type Request struct {
 }
 type Response struct {
 	Headers map[string]string
+	// Trailers are the trailers of the response, including those of the gRPC-Web responses that
+	// are sent in the body.
+	Trailers map[string]string
 }
 type StreamRequestState int
 
@@ -133,6 +253,11 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 	loggerTrace := logger.V(logutil.TRACE)
 	loggerTrace.Info("Processing")
 
+	tl := s.timelines.Start()
+	if tl != nil {
+		ctx = timeline.NewContext(ctx, tl)
+	}
+
 	// Create request context to share states during life time of an HTTP request.
 	// See https://github.com/envoyproxy/envoy/issues/17540.
 	reqCtx := &RequestContext{
@@ -142,18 +267,26 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			Body:    make(map[string]interface{}),
 		},
 		Response: &Response{
-			Headers: make(map[string]string),
+			Headers:  make(map[string]string),
+			Trailers: make(map[string]string),
 		},
 	}
 
 	var body []byte
-	var responseBody map[string]interface{}
 
 	// Create error handling var as each request should only report once for
 	// error metrics. This doesn't cover the error "Cannot receive stream request" because
 	// such errors might happen even though response is processed.
 	var err error
+	// disconnected is set if the stream ended without a response being sent back to Envoy.
+	disconnected := false
+	// requestSpan is the tracing span of the request, started with its headers.
+	var requestSpan trace.Span
 	defer func(error, *RequestContext) {
+		cancelledStage := ""
+		if disconnected || errors.Is(err, context.Canceled) {
+			cancelledStage = s.recordCancellation(ctx, reqCtx)
+		}
 		if reqCtx.ResponseStatusCode != "" {
 			metrics.RecordRequestErrCounter(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.ResponseStatusCode)
 		} else if err != nil {
@@ -162,17 +295,38 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 		if reqCtx.RequestRunning {
 			metrics.DecRunningRequests(reqCtx.Model)
 		}
+		// The stream context may be cancelled already, and the end of the request must still be
+		// handled, e.g. to release its quota leases.
+		s.director.HandleRequestEnd(context.WithoutCancel(ctx), reqCtx)
+		if tl != nil {
+			tl.RequestID = reqCtx.Request.Headers[requtil.RequestIdHeaderKey]
+			tl.Model = reqCtx.Model
+			tl.TargetModel = reqCtx.ResolvedTargetModel
+			tl.TargetPod = reqCtx.TargetPod
+			tl.StatusCode = reqCtx.ResponseStatusCode
+			s.timelines.Finish(tl)
+		}
+		if s.requests != nil {
+			s.requests.Add(lookupRecord(reqCtx, err, cancelledStage))
+		}
+		if requestSpan != nil {
+			requestSpan.SetAttributes(attribute.String("epp.model", reqCtx.Model), attribute.String("epp.target_model", reqCtx.ResolvedTargetModel),
+				attribute.String("epp.target_pod", reqCtx.TargetPod), attribute.String("epp.status", reqCtx.ResponseStatusCode))
+			tracing.End(requestSpan, err)
+		}
 	}(err, reqCtx)
 
 	for {
 		select {
 		case <-ctx.Done():
+			disconnected = true
 			return ctx.Err()
 		default:
 		}
 
 		req, recvErr := srv.Recv()
 		if recvErr == io.EOF || status.Code(recvErr) == codes.Canceled {
+			disconnected = true
 			return nil
 		}
 		if recvErr != nil {
@@ -182,6 +336,15 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			return status.Errorf(codes.Unknown, "cannot receive stream request: %v", err)
 		}
 
+		// The span of the request is a child of the span propagated by the gateway in its headers,
+		// and each phase of the request a child of the span of the request.
+		if v, ok := req.Request.(*extProcPb.ProcessingRequest_RequestHeaders); ok && requestSpan == nil {
+			ctx, requestSpan = tracing.StartRequest(ctx, headerCarrier(v.RequestHeaders.GetHeaders().GetHeaders()))
+		}
+		parentSpan := trace.SpanFromContext(ctx)
+		var phaseSpan trace.Span
+		ctx, phaseSpan = tracing.Tracer().Start(ctx, phaseName(req))
+
 		switch v := req.Request.(type) {
 		case *extProcPb.ProcessingRequest_RequestHeaders:
 			if requestId := requtil.ExtractHeaderValue(v, requtil.RequestIdHeaderKey); len(requestId) > 0 {
@@ -190,6 +353,9 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 				ctx = log.IntoContext(ctx, logger)
 			}
 			err = s.HandleRequestHeaders(ctx, reqCtx, v)
+			reqCtx.Route = s.extractGatewayAttribute(req, reqCtx, requtil.RouteKey)
+			reqCtx.Listener = s.extractGatewayAttribute(req, reqCtx, requtil.ListenerKey)
+			tl.Mark(timeline.EventRequestHeaders)
 		case *extProcPb.ProcessingRequest_RequestBody:
 			loggerTrace.Info("Incoming body chunk", "EoS", v.RequestBody.EndOfStream)
 			// In the stream case, we can receive multiple request bodies.
@@ -207,6 +373,7 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 
 				// Body stream complete. Allocate empty slice for response to use.
 				body = []byte{}
+				tl.Mark(timeline.EventRequestParsed)
 
 				reqCtx, err = s.director.HandleRequest(ctx, reqCtx)
 				if err != nil {
@@ -239,9 +406,13 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 				} else if header.Key == "content-type" && strings.Contains(value, "text/event-stream") {
 					reqCtx.modelServerStreaming = true
 					loggerTrace.Info("model server is streaming response")
+				} else if header.Key == "content-type" {
+					// gRPC responses are streamed in length-prefixed frames.
+					reqCtx.grpcParser = newGRPCFrameParser(value)
 				}
 			}
 			reqCtx.RequestState = ResponseRecieved
+			tl.Mark(timeline.EventResponseHeaders)
 
 			var responseErr error
 			reqCtx, responseErr = s.HandleResponseHeaders(ctx, reqCtx, v)
@@ -250,18 +421,28 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 			}
 			reqCtx.respHeaderResp = s.generateResponseHeaderResponse(reqCtx)
 
+			// No body nor trailers follow a response ending with its headers.
+			if v.ResponseHeaders.EndOfStream {
+				loggerTrace.Info("response completed with its headers")
+				s.completeResponse(ctx, reqCtx)
+			}
+
 		case *extProcPb.ProcessingRequest_ResponseBody:
-			if reqCtx.modelServerStreaming {
+			if reqCtx.ResponseFirstChunkTimestamp.IsZero() {
+				reqCtx.ResponseFirstChunkTimestamp = time.Now()
+				tl.Mark(timeline.EventFirstChunk)
+			}
+			if reqCtx.grpcParser != nil || reqCtx.modelServerStreaming {
 				// Currently we punt on response parsing if the modelServer is streaming, and we just passthrough.
-
-				responseText := string(v.ResponseBody.Body)
-				s.HandleResponseBodyModelStreaming(ctx, reqCtx, responseText)
+				if reqCtx.grpcParser != nil {
+					s.HandleResponseBodyGRPC(ctx, reqCtx, v.ResponseBody.Body)
+				} else {
+					s.HandleResponseBodyModelStreaming(ctx, reqCtx, string(v.ResponseBody.Body))
+				}
+				s.director.HandleResponseProgress(ctx, reqCtx)
 				if v.ResponseBody.EndOfStream {
 					loggerTrace.Info("stream completed")
-
-					reqCtx.ResponseCompleteTimestamp = time.Now()
-					metrics.RecordRequestLatencies(ctx, reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
-					metrics.RecordResponseSizes(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.ResponseSize)
+					s.completeResponse(ctx, reqCtx)
 				}
 
 				reqCtx.respBodyResp = generateResponseBodyResponses(v.ResponseBody.Body, v.ResponseBody.EndOfStream)
@@ -271,32 +452,23 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 				// Message is buffered, we can read and decode.
 				if v.ResponseBody.EndOfStream {
 					loggerTrace.Info("stream completed")
-					// Don't send a 500 on a response error. Just let the message passthrough and log our error for debugging purposes.
-					// We assume the body is valid JSON, err messages are not guaranteed to be json, and so capturing and sending a 500 obfuscates the response message.
-					// Using the standard 'err' var will send an immediate error response back to the caller.
-					var responseErr error
-					responseErr = json.Unmarshal(body, &responseBody)
-					if responseErr != nil {
-						logger.V(logutil.DEFAULT).Error(responseErr, "Error unmarshaling request body", "body", string(body))
-						reqCtx.respBodyResp = generateResponseBodyResponses(body, true)
-						break
-					}
-
-					reqCtx, responseErr = s.HandleResponseBody(ctx, reqCtx, responseBody)
-					if responseErr != nil {
-						logger.V(logutil.DEFAULT).Error(responseErr, "Failed to process response body", "request", req)
-					} else if reqCtx.ResponseComplete {
-						reqCtx.ResponseCompleteTimestamp = time.Now()
-						metrics.RecordRequestLatencies(ctx, reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
-						metrics.RecordResponseSizes(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.ResponseSize)
-						metrics.RecordInputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.Usage.PromptTokens)
-						metrics.RecordOutputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.Usage.CompletionTokens)
-					}
+					s.handleBufferedResponseBody(ctx, reqCtx, body)
 				}
 			}
 		case *extProcPb.ProcessingRequest_ResponseTrailers:
-			// This is currently unused.
+			// The trailers end the response if its body didn't, in which case the buffered body is
+			// handled now, and sent before the trailers.
+			loggerTrace.Info("response trailers received")
+			s.HandleResponseTrailers(ctx, reqCtx, v)
+			if reqCtx.ResponseCompleteTimestamp.IsZero() {
+				if !reqCtx.modelServerStreaming && reqCtx.grpcParser == nil && len(body) > 0 {
+					s.handleBufferedResponseBody(ctx, reqCtx, body)
+				}
+				s.completeResponse(ctx, reqCtx)
+			}
 		}
+		phaseSpan.End()
+		ctx = trace.ContextWithSpan(ctx, parentSpan)
 
 		// Handle the err and fire an immediate response.
 		if err != nil {
@@ -318,6 +490,197 @@ func (s *StreamingServer) Process(srv extProcPb.ExternalProcessor_ProcessServer)
 	}
 }
 
+// phaseName returns the name of the tracing span of the phase of the given ext-proc request.
+func phaseName(req *extProcPb.ProcessingRequest) string {
+	switch req.Request.(type) {
+	case *extProcPb.ProcessingRequest_RequestHeaders:
+		return "ext_proc.request_headers"
+	case *extProcPb.ProcessingRequest_RequestBody:
+		return "ext_proc.request_body"
+	case *extProcPb.ProcessingRequest_RequestTrailers:
+		return "ext_proc.request_trailers"
+	case *extProcPb.ProcessingRequest_ResponseHeaders:
+		return "ext_proc.response_headers"
+	case *extProcPb.ProcessingRequest_ResponseBody:
+		return "ext_proc.response_body"
+	case *extProcPb.ProcessingRequest_ResponseTrailers:
+		return "ext_proc.response_trailers"
+	default:
+		return "ext_proc.unknown"
+	}
+}
+
+// headerCarrier reads the tracing context propagated by the headers of an ext-proc request.
+type headerCarrier []*configPb.HeaderValue
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range c {
+		if strings.EqualFold(header.Key, key) {
+			if len(header.RawValue) > 0 {
+				return string(header.RawValue)
+			}
+			return header.Value
+		}
+	}
+	return ""
+}
+
+// Set is a no-op, the carrier is only read.
+func (c headerCarrier) Set(string, string) {}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(c))
+	for i, header := range c {
+		keys[i] = strings.ToLower(header.Key)
+	}
+	return keys
+}
+
+// handleBufferedResponseBody handles the whole body of a non-streamed response.
+func (s *StreamingServer) handleBufferedResponseBody(ctx context.Context, reqCtx *RequestContext, body []byte) {
+	logger := log.FromContext(ctx)
+	// Don't send a 500 on a response error. Just let the message passthrough and log our error for debugging purposes.
+	// We assume the body is valid JSON, err messages are not guaranteed to be json, and so capturing and sending a 500 obfuscates the response message.
+	// Using the standard 'err' var will send an immediate error response back to the caller.
+	var responseBody map[string]interface{}
+	responseErr := json.Unmarshal(body, &responseBody)
+	if responseErr != nil {
+		logger.V(logutil.DEFAULT).Error(responseErr, "Error unmarshaling request body", "body", string(body))
+		reqCtx.respBodyResp = generateResponseBodyResponses(body, reqCtx.respTrailerResp == nil)
+		return
+	}
+
+	reqCtx, responseErr = s.HandleResponseBody(ctx, reqCtx, responseBody)
+	if responseErr != nil {
+		logger.V(logutil.DEFAULT).Error(responseErr, "Failed to process response body")
+	} else if reqCtx.ResponseComplete {
+		s.completeResponse(ctx, reqCtx)
+	}
+}
+
+// completeResponse records the metrics of the completed response, and lets the director handle its
+// completion. It only runs once per response, whichever of its headers, body or trailers end it.
+func (s *StreamingServer) completeResponse(ctx context.Context, reqCtx *RequestContext) {
+	if !reqCtx.ResponseCompleteTimestamp.IsZero() {
+		return
+	}
+	if reqCtx.sseParser != nil {
+		usage, err := reqCtx.sseParser.flush()
+		s.recordUsage(ctx, reqCtx, usage, err)
+		s.captureResponseText(reqCtx, usage.text)
+	}
+	reqCtx.ResponseComplete = true
+	reqCtx.ResponseCompleteTimestamp = time.Now()
+	timeline.FromContext(ctx).Mark(timeline.EventResponseComplete)
+	metrics.RecordRequestLatencies(ctx, reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.RequestReceivedTimestamp, reqCtx.ResponseCompleteTimestamp)
+	metrics.RecordResponseSizes(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.ResponseSize)
+	metrics.RecordInputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.Usage.PromptTokens)
+	metrics.RecordOutputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, reqCtx.Usage.CompletionTokens)
+	if reqCtx.Listener != "" {
+		metrics.RecordListenerOutputTokens(reqCtx.Listener, reqCtx.Usage.CompletionTokens)
+		if s.throughput != nil {
+			s.throughput.Record(reqCtx.Listener, reqCtx.Usage.CompletionTokens)
+		}
+	}
+	s.director.HandleResponseComplete(ctx, reqCtx)
+}
+
+// IsResponseStreaming returns true if the model server streams the response, as server-sent events
+// or gRPC messages.
+func (r *RequestContext) IsResponseStreaming() bool {
+	return r.modelServerStreaming || r.grpcParser != nil
+}
+
+// The stages of the requests whose client disconnected, as recorded in the metrics.
+const (
+	cancelledInAdmission      = "admission"
+	cancelledAwaitingResponse = "awaiting_response"
+	cancelledStreaming        = "streaming"
+
+	// wastedClientDisconnected is the reason of the output tokens generated for the requests whose
+	// client disconnected.
+	wastedClientDisconnected = "client_disconnected"
+)
+
+// recordCancellation records the request if its client disconnected before its response completed,
+// along with the output tokens generated for nothing, and returns the stage it reached. The requests
+// disconnected before their body was received cost nothing, and aren't recorded.
+func (s *StreamingServer) recordCancellation(ctx context.Context, reqCtx *RequestContext) string {
+	if reqCtx.Model == "" || reqCtx.ResponseComplete {
+		return ""
+	}
+	stage := reqCtx.cancellationStage()
+	tokens := reqCtx.wastedOutputTokens()
+	timeline.FromContext(ctx).Mark(timeline.EventCancelled)
+	metrics.RecordCancelledRequest(reqCtx.Model, reqCtx.ResolvedTargetModel, stage)
+	metrics.RecordWastedOutputTokens(reqCtx.Model, reqCtx.ResolvedTargetModel, wastedClientDisconnected, tokens)
+	log.FromContext(ctx).V(logutil.DEBUG).Info("Client disconnected before the response completed", "stage", stage, "wastedOutputTokens", tokens)
+	return stage
+}
+
+// lookupRecord returns the record of an ended request retained for lookups, given the error it was
+// rejected with and the stage it reached if its client disconnected.
+func lookupRecord(reqCtx *RequestContext, err error, cancelledStage string) *requestlookup.Record {
+	record := &requestlookup.Record{
+		RequestID:   reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Model:       reqCtx.Model,
+		TargetModel: reqCtx.ResolvedTargetModel,
+		Arrival:     reqCtx.RequestReceivedTimestamp,
+		QueueTime:   reqCtx.QueueTime,
+		TargetPod:   reqCtx.TargetPod,
+		StatusCode:  reqCtx.ResponseStatusCode,
+	}
+	if record.StatusCode == "" && err != nil {
+		record.StatusCode = errutil.CanonicalCode(err)
+		// The bad requests are left out, as their message holds the body of the request.
+		if record.StatusCode != errutil.BadRequest {
+			record.Error = err.Error()
+		}
+	}
+	if reqCtx.TargetPod == "" {
+		return record
+	}
+	record.Response = &requestlookup.Response{
+		PromptTokens:     reqCtx.Usage.PromptTokens,
+		CompletionTokens: reqCtx.Usage.CompletionTokens,
+		FinishReason:     reqCtx.FinishReason,
+		Size:             reqCtx.ResponseSize,
+		CancelledStage:   cancelledStage,
+	}
+	if !reqCtx.ResponseCompleteTimestamp.IsZero() {
+		record.Response.Latency = reqCtx.ResponseCompleteTimestamp.Sub(reqCtx.RequestReceivedTimestamp)
+	}
+	if !reqCtx.ResponseFirstChunkTimestamp.IsZero() {
+		record.Response.TimeToFirstToken = reqCtx.ResponseFirstChunkTimestamp.Sub(reqCtx.RequestReceivedTimestamp)
+	}
+	return record
+}
+
+// cancellationStage returns the stage the request reached before its client disconnected.
+func (r *RequestContext) cancellationStage() string {
+	switch {
+	case r.TargetPod == "":
+		return cancelledInAdmission
+	case r.ResponseFirstChunkTimestamp.IsZero():
+		return cancelledAwaitingResponse
+	default:
+		return cancelledStreaming
+	}
+}
+
+// wastedOutputTokens returns the output tokens generated so far for a request whose client
+// disconnected. Those are the streamed messages unless the usage was reported, as the model servers
+// stream a token per message.
+func (r *RequestContext) wastedOutputTokens() int {
+	return r.GeneratedTokens()
+}
+
+// GeneratedTokens returns the number of output tokens generated so far, as reported by the usage
+// of the response, or approximated by the number of messages streamed, each carrying about a token.
+func (r *RequestContext) GeneratedTokens() int {
+	return max(r.Usage.CompletionTokens, r.streamedMessages)
+}
+
 // updateStateAndSendIfNeeded checks state and can send mutiple responses in a single pass, but only if ordered properly.
 // Order of requests matter in FULL_DUPLEX_STREAMING. For both request and response, the order of response sent back MUST be: Header->Body->Trailer, with trailer being optional.
 func (r *RequestContext) updateStateAndSendIfNeeded(srv extProcPb.ExternalProcessor_ProcessServer, logger logr.Logger) error {
@@ -373,11 +736,17 @@ func (r *RequestContext) updateStateAndSendIfNeeded(srv extProcPb.ExternalProces
 		// Dump the response so a new stream message can begin
 		r.respBodyResp = nil
 	}
+	// The trailers end the body, whether its last chunk had the end of stream set or not, or there
+	// was no body at all.
+	if r.RequestState == HeaderResponseResponseComplete && len(r.respBodyResp) == 0 && r.respTrailerResp != nil {
+		r.RequestState = BodyResponseResponsesComplete
+	}
 	if r.RequestState == BodyResponseResponsesComplete && r.respTrailerResp != nil {
-		// Trailers in requests are not guaranteed
+		// Trailers in responses are not guaranteed
 		if err := srv.Send(r.respTrailerResp); err != nil {
 			return status.Errorf(codes.Unknown, "failed to send response back to Envoy: %v", err)
 		}
+		r.RequestState = TrailerResponseResponsesComplete
 	}
 	return nil
 }
@@ -398,6 +767,41 @@ func BuildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
 				},
 			},
 		}
+	// This code can be returned when no endpoint of the pool can serve the requested model.
+	case errutil.NoCapableEndpoints:
+		resp = &extProcPb.ProcessingResponse{
+			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
+				ImmediateResponse: &extProcPb.ImmediateResponse{
+					Status: &envoyTypePb.HttpStatus{
+						Code: envoyTypePb.StatusCode_ServiceUnavailable,
+					},
+				},
+			},
+		}
+	// This code can be returned by flow control when a request waited too long for the pool to have
+	// capacity.
+	case errutil.ServiceUnavailable:
+		resp = &extProcPb.ProcessingResponse{
+			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
+				ImmediateResponse: &extProcPb.ImmediateResponse{
+					Status: &envoyTypePb.HttpStatus{
+						Code: envoyTypePb.StatusCode_ServiceUnavailable,
+					},
+				},
+			},
+		}
+	// This code can be returned when the requests of a model exceed its rate limits, or the requests
+	// of a client its quotas.
+	case errutil.RateLimited:
+		resp = &extProcPb.ProcessingResponse{
+			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
+				ImmediateResponse: &extProcPb.ImmediateResponse{
+					Status: &envoyTypePb.HttpStatus{
+						Code: envoyTypePb.StatusCode_TooManyRequests,
+					},
+				},
+			},
+		}
 	// This code can be returned by when EPP processes the request and run into server-side errors.
 	case errutil.Internal:
 		resp = &extProcPb.ProcessingResponse{
@@ -420,6 +824,24 @@ func BuildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
 				},
 			},
 		}
+	// This code can be returned when the requested model is paused or outside of its activation
+	// window, with the status and message set by its lifecycle.
+	case errutil.ModelInactive:
+		e := err.(errutil.Error) // the codes other than Unknown are only returned for errutil.Error
+		code := envoyTypePb.StatusCode_ServiceUnavailable
+		if e.StatusCode > 0 {
+			code = envoyTypePb.StatusCode(e.StatusCode)
+		}
+		resp = &extProcPb.ProcessingResponse{
+			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
+				ImmediateResponse: &extProcPb.ImmediateResponse{
+					Status: &envoyTypePb.HttpStatus{
+						Code: code,
+					},
+					Body: []byte(e.Msg),
+				},
+			},
+		}
 	case errutil.BadConfiguration:
 		resp = &extProcPb.ProcessingResponse{
 			Response: &extProcPb.ProcessingResponse_ImmediateResponse{
@@ -434,9 +856,31 @@ func BuildErrResponse(err error) (*extProcPb.ProcessingResponse, error) {
 		return nil, status.Errorf(status.Code(err), "failed to handle request: %v", err)
 	}
 
-	if err.Error() != "" {
+	if e, ok := err.(errutil.Error); ok && e.Body != "" {
+		resp.Response.(*extProcPb.ProcessingResponse_ImmediateResponse).ImmediateResponse.Body = []byte(e.Body)
+	} else if err.Error() != "" && errutil.CanonicalCode(err) != errutil.ModelInactive {
 		resp.Response.(*extProcPb.ProcessingResponse_ImmediateResponse).ImmediateResponse.Body = []byte(err.Error())
 	}
+	if e, ok := err.(errutil.Error); ok && (e.RetryAfter > 0 || e.Headers != nil) {
+		headers := []*configPb.HeaderValueOption{}
+		if e.RetryAfter > 0 {
+			// Retry-After is a number of seconds, rounded up so the client doesn't retry too early.
+			seconds := int64(math.Ceil(e.RetryAfter.Seconds()))
+			headers = append(headers, &configPb.HeaderValueOption{
+				Header: &configPb.HeaderValue{Key: "Retry-After", RawValue: []byte(strconv.FormatInt(seconds, 10))},
+			})
+		}
+		if e.Headers != nil {
+			for _, key := range slices.Sorted(maps.Keys(*e.Headers)) {
+				headers = append(headers, &configPb.HeaderValueOption{
+					Header: &configPb.HeaderValue{Key: key, RawValue: []byte((*e.Headers)[key])},
+				})
+			}
+		}
+		resp.Response.(*extProcPb.ProcessingResponse_ImmediateResponse).ImmediateResponse.Headers = &extProcPb.HeaderMutation{
+			SetHeaders: headers,
+		}
+	}
 
 	return resp, nil
 }
@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
 	"testing"
+	"time"
+
+	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/google/go-cmp/cmp"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
 func TestBuildCommonResponses(t *testing.T) {
@@ -62,6 +69,120 @@ func TestBuildCommonResponses(t *testing.T) {
 	}
 }
 
+func TestBuildErrResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    envoyTypePb.StatusCode
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			name:     "resource exhausted",
+			err:      errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "no capacity"},
+			wantCode: envoyTypePb.StatusCode_TooManyRequests,
+		},
+		{
+			name:        "queue full",
+			err:         errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "queue full", RetryAfter: 1500 * time.Millisecond},
+			wantCode:    envoyTypePb.StatusCode_TooManyRequests,
+			wantHeaders: map[string]string{"Retry-After": "2"},
+		},
+		{
+			name:        "queue TTL expired",
+			err:         errutil.Error{Code: errutil.ServiceUnavailable, Msg: "TTL expired", RetryAfter: time.Second},
+			wantCode:    envoyTypePb.StatusCode_ServiceUnavailable,
+			wantHeaders: map[string]string{"Retry-After": "1"},
+		},
+		{
+			name: "rate limited",
+			err: errutil.Error{Code: errutil.RateLimited, Msg: "rate limited", RetryAfter: 200 * time.Millisecond,
+				Headers: &errutil.Headers{"x-ratelimit-limit-requests": "10", "x-ratelimit-remaining-requests": "0"}},
+			wantCode: envoyTypePb.StatusCode_TooManyRequests,
+			wantHeaders: map[string]string{
+				"Retry-After":                    "1",
+				"x-ratelimit-limit-requests":     "10",
+				"x-ratelimit-remaining-requests": "0",
+			},
+		},
+		{
+			name: "quota exceeded",
+			err: errutil.Error{Code: errutil.RateLimited, Msg: "quota exceeded",
+				Headers: &errutil.Headers{"content-type": "application/json"}, Body: `{"error":{"type":"quota_exceeded"}}`},
+			wantCode:    envoyTypePb.StatusCode_TooManyRequests,
+			wantHeaders: map[string]string{"content-type": "application/json"},
+			wantBody:    `{"error":{"type":"quota_exceeded"}}`,
+		},
+		{
+			name:     "model inactive",
+			err:      errutil.Error{Code: errutil.ModelInactive, Msg: "model food-review is paused"},
+			wantCode: envoyTypePb.StatusCode_ServiceUnavailable,
+			wantBody: "model food-review is paused",
+		},
+		{
+			name:     "model inactive with a custom status",
+			err:      errutil.Error{Code: errutil.ModelInactive, Msg: "use food-review-2", StatusCode: 410},
+			wantCode: envoyTypePb.StatusCode_Gone,
+			wantBody: "use food-review-2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := BuildErrResponse(test.err)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			immediate := resp.GetImmediateResponse()
+			if code := immediate.GetStatus().GetCode(); code != test.wantCode {
+				t.Errorf("Got status %v, want %v", code, test.wantCode)
+			}
+			var headers map[string]string
+			for _, header := range immediate.GetHeaders().GetSetHeaders() {
+				if headers == nil {
+					headers = map[string]string{}
+				}
+				headers[header.GetHeader().GetKey()] = string(header.GetHeader().GetRawValue())
+			}
+			if diff := cmp.Diff(test.wantHeaders, headers); diff != "" {
+				t.Errorf("Unexpected headers (-want +got): %s", diff)
+			}
+			if test.wantBody != "" && string(immediate.GetBody()) != test.wantBody {
+				t.Errorf("Got body %q, want %q", immediate.GetBody(), test.wantBody)
+			}
+		})
+	}
+}
+
+func TestCancelledRequest(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	server := &StreamingServer{}
+
+	reqCtx := &RequestContext{Model: "food-review", modelServerStreaming: true}
+	if stage := reqCtx.cancellationStage(); stage != cancelledInAdmission {
+		t.Errorf("Got stage %q before scheduling, want %q", stage, cancelledInAdmission)
+	}
+	reqCtx.TargetPod = "default/pod1"
+	if stage := reqCtx.cancellationStage(); stage != cancelledAwaitingResponse {
+		t.Errorf("Got stage %q before the response, want %q", stage, cancelledAwaitingResponse)
+	}
+
+	reqCtx.ResponseFirstChunkTimestamp = time.Now()
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, "data: {\"choices\":[{\"text\":\"a\"}]}\n\ndata: {\"choices\":[{\"text\":\"b\"}]}\n\n")
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, "data: {\"choices\":[{\"text\":\"c\"}]}\n\n")
+	if stage := reqCtx.cancellationStage(); stage != cancelledStreaming {
+		t.Errorf("Got stage %q while streaming, want %q", stage, cancelledStreaming)
+	}
+	if tokens := reqCtx.wastedOutputTokens(); tokens != 3 {
+		t.Errorf("Got %d wasted output tokens, want 3", tokens)
+	}
+
+	// The end of the stream isn't a token, and the reported usage prevails.
+	server.HandleResponseBodyModelStreaming(ctx, reqCtx, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"total_tokens\":8,\"completion_tokens\":5}}\n\ndata: [DONE]\n\n")
+	if tokens := reqCtx.wastedOutputTokens(); tokens != 5 {
+		t.Errorf("Got %d wasted output tokens, want 5", tokens)
+	}
+}
+
 func generateBytes(count int) []byte {
 	arr := make([]byte, count)
 	_, _ = rand.Read(arr)
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handoff hands the state of the scheduler plugins over from an endpoint picker to the one
+// replacing it during an upgrade, e.g. the session affinities and the latency models learned from
+// the responses, so the routing quality doesn't degrade while the new endpoint picker relearns them.
+//
+// The endpoint picker being replaced serves the state on a gRPC port, and its successor imports it
+// once before it starts serving requests. The requests in flight aren't handed over, as they
+// complete through the endpoint picker that sent them, and the queues of the model servers they
+// occupy are observed by the new one through the scraped metrics.
+package handoff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// ServiceName is the name of the gRPC service serving the state.
+	ServiceName = "inference.networking.x-k8s.io.handoff.v1.StateHandoff"
+	// SnapshotVersion is the version of the format of the snapshots. The snapshots of other
+	// versions are rejected.
+	SnapshotVersion = 1
+	// DefaultImportTimeout bounds the import of the state, so an unreachable predecessor doesn't
+	// hold up the startup of the endpoint picker.
+	DefaultImportTimeout = 10 * time.Second
+
+	exportMethod = "/" + ServiceName + "/Export"
+	// maxSnapshotSize bounds the size of the snapshots received, which exceeds the default limit of
+	// gRPC messages with large prefix cache indexes.
+	maxSnapshotSize = 256 << 20
+)
+
+// StateExporter exports the state of the Stateful scheduler plugins, keyed by plugin name.
+type StateExporter interface {
+	ExportState() (map[string]json.RawMessage, error)
+}
+
+// StateImporter imports the state of the Stateful scheduler plugins, keyed by plugin name.
+type StateImporter interface {
+	ImportState(ctx context.Context, state map[string]json.RawMessage) error
+}
+
+// Snapshot is the state handed over, serialized as JSON.
+type Snapshot struct {
+	Version int                        `json:"version"`
+	Plugins map[string]json.RawMessage `json:"plugins"`
+}
+
+// exportServer is the interface of the gRPC service, which has no generated code as its messages
+// are well-known types.
+type exportServer interface {
+	Export(ctx context.Context, req *emptypb.Empty) (*wrapperspb.BytesValue, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*exportServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Export",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := &emptypb.Empty{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(exportServer).Export(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: exportMethod}
+				handler := func(ctx context.Context, req any) (any, error) {
+					return srv.(exportServer).Export(ctx, req.(*emptypb.Empty))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// Server serves the state exported by a StateExporter.
+type Server struct {
+	exporter StateExporter
+}
+
+var _ exportServer = &Server{}
+
+// NewServer returns a Server serving the state exported by the given StateExporter.
+func NewServer(exporter StateExporter) *Server {
+	return &Server{exporter: exporter}
+}
+
+// Register registers the state handoff service with the given gRPC server.
+func (s *Server) Register(srv *grpc.Server) {
+	srv.RegisterService(&serviceDesc, s)
+}
+
+// Export returns the Snapshot of the current state.
+func (s *Server) Export(ctx context.Context, _ *emptypb.Empty) (*wrapperspb.BytesValue, error) {
+	plugins, err := s.exporter.ExportState()
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to export the state of the scheduler plugins")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	data, err := json.Marshal(Snapshot{Version: SnapshotVersion, Plugins: plugins})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.FromContext(ctx).V(logutil.DEFAULT).Info("Exported the state of the scheduler plugins", "plugins", len(plugins), "bytes", len(data))
+	return wrapperspb.Bytes(data), nil
+}
+
+// Fetch returns the Snapshot served at the given address.
+func Fetch(ctx context.Context, address string) (*Snapshot, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp := &wrapperspb.BytesValue{}
+	if err := conn.Invoke(ctx, exportMethod, &emptypb.Empty{}, resp, grpc.MaxCallRecvMsgSize(maxSnapshotSize)); err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(resp.GetValue(), snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, SnapshotVersion)
+	}
+	return snapshot, nil
+}
+
+// Import fetches the Snapshot served at the given address, and imports it into the given
+// StateImporter.
+func Import(ctx context.Context, address string, importer StateImporter) error {
+	snapshot, err := Fetch(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the state from %s: %w", address, err)
+	}
+	return importer.ImportState(ctx, snapshot.Plugins)
+}
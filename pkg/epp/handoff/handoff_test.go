@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type fakeScheduler struct {
+	state     map[string]json.RawMessage
+	exportErr error
+}
+
+func (s *fakeScheduler) ExportState() (map[string]json.RawMessage, error) {
+	return s.state, s.exportErr
+}
+
+func (s *fakeScheduler) ImportState(_ context.Context, state map[string]json.RawMessage) error {
+	s.state = state
+	return nil
+}
+
+type fixedServer struct {
+	snapshot string
+}
+
+func (s *fixedServer) Export(_ context.Context, _ *emptypb.Empty) (*wrapperspb.BytesValue, error) {
+	return wrapperspb.Bytes([]byte(s.snapshot)), nil
+}
+
+// serve serves the given export server on a local port, and returns its address.
+func serve(t *testing.T, register func(*grpc.Server)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	register(srv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestImport(t *testing.T) {
+	state := map[string]json.RawMessage{
+		"session-affinity": json.RawMessage(`{"thread_1":{"pod":"default/pod1"}}`),
+		"prefix-cache":     json.RawMessage(`{"servers":[]}`),
+	}
+	address := serve(t, NewServer(&fakeScheduler{state: state}).Register)
+
+	importer := &fakeScheduler{}
+	if err := Import(context.Background(), address, importer); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(state, importer.state); diff != "" {
+		t.Errorf("Unexpected imported state (-want +got): %v", diff)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(*grpc.Server)
+	}{
+		{
+			name:     "export error",
+			register: NewServer(&fakeScheduler{exportErr: errors.New("export failed")}).Register,
+		},
+		{
+			name: "unsupported version",
+			register: func(srv *grpc.Server) {
+				srv.RegisterService(&serviceDesc, &fixedServer{snapshot: `{"version":2,"plugins":{}}`})
+			},
+		},
+		{
+			name: "invalid snapshot",
+			register: func(srv *grpc.Server) {
+				srv.RegisterService(&serviceDesc, &fixedServer{snapshot: `{"version":`})
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address := serve(t, test.register)
+			importer := &fakeScheduler{}
+			if err := Import(context.Background(), address, importer); err == nil {
+				t.Error("Expected an error")
+			}
+			if importer.state != nil {
+				t.Errorf("Expected no state to be imported, got %v", importer.state)
+			}
+		})
+	}
+}
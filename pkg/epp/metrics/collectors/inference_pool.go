@@ -33,10 +33,34 @@ var (
 			"model_server_pod",
 		}, nil,
 	)
+	descInferencePoolPerPodRequestsInFlight = prometheus.NewDesc(
+		"inference_pool_per_pod_requests_in_flight",
+		metricsutil.HelpMsgWithStability("The number of requests routed to each underlying pod by this endpoint picker that didn't end yet.", compbasemetrics.ALPHA),
+		[]string{
+			"name",
+			"model_server_pod",
+		}, nil,
+	)
+	descInferencePoolPerPodOutstandingTokens = prometheus.NewDesc(
+		"inference_pool_per_pod_outstanding_tokens",
+		metricsutil.HelpMsgWithStability("The estimated number of tokens the requests in flight to each underlying pod have yet to process.", compbasemetrics.ALPHA),
+		[]string{
+			"name",
+			"model_server_pod",
+		}, nil,
+	)
+	descInferencePoolPerPodQueueDepthOnly = prometheus.NewDesc(
+		"inference_pool_per_pod_queue_depth_only",
+		metricsutil.HelpMsgWithStability("Whether the pod is scheduled by its queue size only, as no metrics are known for its model server (1) or not (0).", compbasemetrics.ALPHA),
+		[]string{
+			"name",
+			"model_server_pod",
+		}, nil,
+	)
 )
 
 type inferencePoolMetricsCollector struct {
-	ds datastore.Datastore
+	datastores func() []datastore.Datastore
 }
 
 // Check if inferencePoolMetricsCollector implements necessary interface
@@ -45,24 +69,41 @@ var _ prometheus.Collector = &inferencePoolMetricsCollector{}
 // NewInferencePoolMetricsCollector implements the prometheus.Collector interface and
 // exposes metrics about inference pool.
 func NewInferencePoolMetricsCollector(ds datastore.Datastore) prometheus.Collector {
+	return NewInferencePoolsMetricsCollector(func() []datastore.Datastore {
+		return []datastore.Datastore{ds}
+	})
+}
+
+// NewInferencePoolsMetricsCollector is NewInferencePoolMetricsCollector for the pools of the
+// datastores returned by the given function, e.g. when the Endpoint Picker serves several pools.
+func NewInferencePoolsMetricsCollector(datastores func() []datastore.Datastore) prometheus.Collector {
 	return &inferencePoolMetricsCollector{
-		ds: ds,
+		datastores: datastores,
 	}
 }
 
 // DescribeWithStability implements the prometheus.Collector interface.
 func (c *inferencePoolMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- descInferencePoolPerPodQueueSize
+	ch <- descInferencePoolPerPodRequestsInFlight
+	ch <- descInferencePoolPerPodOutstandingTokens
+	ch <- descInferencePoolPerPodQueueDepthOnly
 }
 
 // CollectWithStability implements the prometheus.Collector interface.
 func (c *inferencePoolMetricsCollector) Collect(ch chan<- prometheus.Metric) {
-	pool, err := c.ds.PoolGet()
+	for _, ds := range c.datastores() {
+		collectInferencePool(ch, ds)
+	}
+}
+
+func collectInferencePool(ch chan<- prometheus.Metric, ds datastore.Datastore) {
+	pool, err := ds.PoolGet()
 	if err != nil {
 		return
 	}
 
-	podMetrics := c.ds.PodGetAll()
+	podMetrics := ds.PodGetAll()
 	if len(podMetrics) == 0 {
 		return
 	}
@@ -75,5 +116,30 @@ func (c *inferencePoolMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 			pool.Name,
 			pod.GetPod().NamespacedName.Name,
 		)
+		ch <- prometheus.MustNewConstMetric(
+			descInferencePoolPerPodRequestsInFlight,
+			prometheus.GaugeValue,
+			float64(pod.GetRequestsInFlight().Count()),
+			pool.Name,
+			pod.GetPod().NamespacedName.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			descInferencePoolPerPodOutstandingTokens,
+			prometheus.GaugeValue,
+			float64(pod.GetRequestsInFlight().OutstandingTokens()),
+			pool.Name,
+			pod.GetPod().NamespacedName.Name,
+		)
+		queueDepthOnly := 0.0
+		if pod.GetMetrics().QueueDepthOnly {
+			queueDepthOnly = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			descInferencePoolPerPodQueueDepthOnly,
+			prometheus.GaugeValue,
+			queueDepthOnly,
+			pool.Name,
+			pod.GetPod().NamespacedName.Name,
+		)
 	}
 }
@@ -51,9 +51,7 @@ func TestNoMetricsCollected(t *testing.T) {
 	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
 	datastore := datastore.NewDatastore(context.Background(), pmf)
 
-	collector := &inferencePoolMetricsCollector{
-		ds: datastore,
-	}
+	collector := NewInferencePoolMetricsCollector(datastore)
 
 	if err := testutil.CollectAndCompare(collector, strings.NewReader(""), ""); err != nil {
 		t.Fatal(err)
@@ -87,9 +85,7 @@ func TestMetricsCollected(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
-	collector := &inferencePoolMetricsCollector{
-		ds: ds,
-	}
+	collector := NewInferencePoolMetricsCollector(ds)
 	err := testutil.CollectAndCompare(collector, strings.NewReader(`
 		# HELP inference_pool_per_pod_queue_size [ALPHA] The total number of requests pending in the model server queue for each underlying pod.
 		# TYPE inference_pool_per_pod_queue_size gauge
@@ -98,4 +94,14 @@ func TestMetricsCollected(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	ds.PodGetAll()[0].GetRequestsInFlight().Inc()
+	err = testutil.CollectAndCompare(collector, strings.NewReader(`
+		# HELP inference_pool_per_pod_requests_in_flight [ALPHA] The number of requests routed to each underlying pod by this endpoint picker that didn't end yet.
+		# TYPE inference_pool_per_pod_requests_in_flight gauge
+		inference_pool_per_pod_requests_in_flight{model_server_pod="pod1",name="test-pool"} 1
+`), "inference_pool_per_pod_requests_in_flight")
+	if err != nil {
+		t.Fatal(err)
+	}
 }
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	compbasemetrics "k8s.io/component-base/metrics"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/throughput"
+	metricsutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/metrics"
+)
+
+var (
+	descListenerOutputTokensPerSecond = prometheus.NewDesc(
+		"inference_extension_listener_output_tokens_per_second",
+		metricsutil.HelpMsgWithStability("The output tokens generated per second for the requests received on each gateway listener.", compbasemetrics.ALPHA),
+		[]string{
+			"listener",
+		}, nil,
+	)
+)
+
+type listenerThroughputCollector struct {
+	tracker *throughput.Tracker
+}
+
+// Check if listenerThroughputCollector implements necessary interface
+var _ prometheus.Collector = &listenerThroughputCollector{}
+
+// NewListenerThroughputCollector implements the prometheus.Collector interface and
+// exposes the throughput of the gateway listeners tracked by the given tracker.
+func NewListenerThroughputCollector(tracker *throughput.Tracker) prometheus.Collector {
+	return &listenerThroughputCollector{
+		tracker: tracker,
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *listenerThroughputCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descListenerOutputTokensPerSecond
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *listenerThroughputCollector) Collect(ch chan<- prometheus.Metric) {
+	for listener, rate := range c.tracker.Rates() {
+		ch <- prometheus.MustNewConstMetric(
+			descListenerOutputTokensPerSecond,
+			prometheus.GaugeValue,
+			rate,
+			listener,
+		)
+	}
+}
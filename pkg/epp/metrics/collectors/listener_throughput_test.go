@@ -0,0 +1,44 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/testutil"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/throughput"
+)
+
+func TestListenerThroughputCollected(t *testing.T) {
+	tracker := throughput.NewTracker(time.Minute)
+	collector := NewListenerThroughputCollector(tracker)
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(""), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker.Record("default/gateway/http", 90)
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+		# HELP inference_extension_listener_output_tokens_per_second [ALPHA] The output tokens generated per second for the requests received on each gateway listener.
+		# TYPE inference_extension_listener_output_tokens_per_second gauge
+		inference_extension_listener_output_tokens_per_second{listener="default/gateway/http"} 1.5
+`), "inference_extension_listener_output_tokens_per_second")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -18,10 +18,12 @@ package metrics
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	compbasemetrics "k8s.io/component-base/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -55,6 +57,15 @@ var (
 		[]string{"model_name", "target_model_name"},
 	)
 
+	requestClasses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "request_classes_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of inference model requests broken out for each model, target model and class of the requests, as labeled by the classifiers.", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name", "target_model_name", "workload_type", "size_class", "criticality"},
+	)
+
 	requestErrCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: InferenceModelComponent,
@@ -135,6 +146,15 @@ var (
 		[]string{"model_name"},
 	)
 
+	inferenceModelCapableEndpoints = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "capable_endpoints",
+			Help:      metricsutil.HelpMsgWithStability("The number of endpoints of the pool that can currently serve each model.", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name"},
+	)
+
 	// NTPOT - Normalized Time Per Output Token
 	NormalizedTimePerOutputToken = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -177,6 +197,33 @@ var (
 		[]string{"name"},
 	)
 
+	inferencePoolFailoverActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferencePoolComponent,
+			Name:      "failover_active",
+			Help:      metricsutil.HelpMsgWithStability("Whether traffic of the inference server pool is failed over to its standby pool (1) or not (0).", compbasemetrics.ALPHA),
+		},
+		[]string{"name", "standby_name"},
+	)
+
+	inferencePoolFailovers = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferencePoolComponent,
+			Name:      "failovers_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of automatic failovers of the inference server pool to its standby pool.", compbasemetrics.ALPHA),
+		},
+		[]string{"name", "standby_name"},
+	)
+
+	inferencePoolPrewarmRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferencePoolComponent,
+			Name:      "prewarm_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of prewarm requests sent to the pods joining the inference server pool, by outcome.", compbasemetrics.ALPHA),
+		},
+		[]string{"name", "outcome"},
+	)
+
 	// Scheduler Metrics
 	SchedulerE2ELatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -199,7 +246,166 @@ var (
 				0.0001, 0.0002, 0.0005, 0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1,
 			},
 		},
-		[]string{"plugin_type", "plugin_name"},
+		[]string{"profile_name", "plugin_type", "plugin_name"},
+	)
+
+	SchedulerProfileLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_profile_duration_seconds",
+			Help:      metricsutil.HelpMsgWithStability("Scheduler profile cycle latency distribution in seconds for each profile.", compbasemetrics.ALPHA),
+			Buckets: []float64{
+				0.0001, 0.0002, 0.0005, 0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1,
+			},
+		},
+		[]string{"profile_name"},
+	)
+
+	SchedulerProfileDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_profile_decisions_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of scheduler profile cycles, by whether they scheduled the request, shed it or failed.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "outcome"},
+	)
+
+	SchedulerFilterRemainingPods = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_filter_remaining_pods",
+			Help:      metricsutil.HelpMsgWithStability("Distribution of the number of candidate pods remaining after each filter plugin.", compbasemetrics.ALPHA),
+			Buckets:   []float64{0, 1, 2, 4, 8, 16, 32, 64, 128, 256},
+		},
+		[]string{"profile_name", "plugin_name"},
+	)
+
+	SchedulerScorerScores = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_scorer_scores",
+			Help:      metricsutil.HelpMsgWithStability("Distribution of the scores given to the candidate pods by each scorer plugin, before normalization and weighting.", compbasemetrics.ALPHA),
+			// Buckets from 0.0 to 1.0 in increments
+			Buckets: []float64{0.0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0},
+		},
+		[]string{"profile_name", "plugin_name"},
+	)
+
+	SchedulerPicks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_picks_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of the pods picked by the scheduler profile cycles, by pod.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "model_server_pod"},
+	)
+
+	SchedulerRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_rejections_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of scheduler profile cycles that shed or rejected the request, by reason.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "reason"},
+	)
+
+	SchedulerPluginBudgetViolations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_plugin_budget_violations_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of scheduler plugin runs skipped or aborted because the scheduling cycle exceeded its time budget.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "plugin_type", "plugin_name"},
+	)
+
+	SchedulerPluginPanics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_plugin_panics_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of scheduler plugin runs that panicked.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "plugin_type", "plugin_name"},
+	)
+
+	SchedulerExtensionFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_extension_failures_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of failed calls of the scheduler extensions, by extension address, method and reason.", compbasemetrics.ALPHA),
+		},
+		[]string{"address", "method", "reason"},
+	)
+
+	SchedulerWasmFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_wasm_failures_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of failed calls of the WebAssembly scheduler plugins, by plugin name and reason.", compbasemetrics.ALPHA),
+		},
+		[]string{"plugin_name", "reason"},
+	)
+
+	SchedulerResponseAnomalies = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_response_anomalies_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of degenerate responses flagged by the response anomaly detectors, by detector.", compbasemetrics.ALPHA),
+		},
+		[]string{"detector"},
+	)
+
+	SchedulerAnomalyCooldowns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_anomaly_cooldowns_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of pods put in cooldown for producing degenerate responses.", compbasemetrics.ALPHA),
+		},
+		[]string{},
+	)
+
+	SchedulerReusedDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_reused_decisions_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests scheduled by reusing a recent scheduling decision instead of running a scheduling cycle.", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name"},
+	)
+
+	SchedulerScoreCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_score_cache_lookups_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of lookups of the cached scores of identical requests, by profile and by whether they hit.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "result"},
+	)
+
+	SchedulerProfileFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_profile_failures_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of scheduler profile cycles that failed, by how the failure was handled.", compbasemetrics.ALPHA),
+		},
+		[]string{"profile_name", "outcome"},
+	)
+
+	SchedulerConfigReloads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_config_reloads_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of scheduler configuration reloads at runtime.", compbasemetrics.ALPHA),
+		},
+		[]string{"status"},
+	)
+
+	SchedulerScorerWeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferenceExtension,
+			Name:      "scheduler_scorer_weight",
+			Help:      metricsutil.HelpMsgWithStability("Current effective weight of each scheduler scorer plugin.", compbasemetrics.ALPHA),
+		},
+		[]string{"plugin_name"},
 	)
 
 	// Prefix indexer Metrics
@@ -233,6 +439,156 @@ var (
 		[]string{},
 	)
 
+	// SLO Metrics
+	sloRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "slo_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests with a time to first token SLO, broken out by whether the SLO was met.", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name", "slo_met"},
+	)
+
+	sloBurnRate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "slo_error_budget_burn_rate",
+			Help:      metricsutil.HelpMsgWithStability("Rate at which the SLO error budget is consumed over the window, where 1 consumes exactly the budget.", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name", "window"},
+	)
+
+	// Adapter shedding Metrics
+	adapterShedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "adapter_shed_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of LoRA adapter requests shed because adapter traffic exceeded its share of the saturated pool.", compbasemetrics.ALPHA),
+		},
+		[]string{"target_model_name"},
+	)
+
+	// Flow control Metrics
+	flowControlQueueSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferenceExtension,
+			Name:      "flow_control_queue_size",
+			Help:      metricsutil.HelpMsgWithStability("Number of requests waiting in the flow control queue of each priority while the pool is saturated.", compbasemetrics.ALPHA),
+		},
+		[]string{"priority"},
+	)
+
+	flowControlRejectedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "flow_control_rejected_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests rejected by flow control, broken out by priority and reason (queue_full or ttl_expired).", compbasemetrics.ALPHA),
+		},
+		[]string{"priority", "reason"},
+	)
+
+	// Rate limit Metrics
+	rateLimitedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "rate_limited_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests rejected because their model exceeded its rate limit, broken out by limit (requests or tokens).", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name", "limit"},
+	)
+
+	// Quota Metrics
+	quotaRejectedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "quota_rejected_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests rejected because their client exceeded its quota, broken out by limit (concurrent_requests or token_budget).", compbasemetrics.ALPHA),
+		},
+		[]string{"limit"},
+	)
+	namespaceQuotaRejectedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "namespace_quota_rejected_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests rejected because their namespace exceeded its aggregate quota, broken out by namespace and limit (concurrent_requests or tokens_per_minute).", compbasemetrics.ALPHA),
+		},
+		[]string{"namespace", "limit"},
+	)
+	namespaceQuotaRequestsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferenceExtension,
+			Name:      "namespace_quota_requests_in_flight",
+			Help:      metricsutil.HelpMsgWithStability("Number of requests of the namespace in flight, across the replicas sharing the quotas, as last counted by this replica.", compbasemetrics.ALPHA),
+		},
+		[]string{"namespace"},
+	)
+	namespaceQuotaTokens = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "namespace_quota_tokens_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of tokens charged to the quota of the namespace by this replica.", compbasemetrics.ALPHA),
+		},
+		[]string{"namespace"},
+	)
+
+	// Throughput Metrics
+	listenerOutputTokens = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "listener_output_tokens_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of output tokens generated for the requests received on each gateway listener.", compbasemetrics.ALPHA),
+		},
+		[]string{"listener"},
+	)
+
+	// Wasted work Metrics
+	cancelledRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "cancelled_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests whose client disconnected before their response completed, broken out by stage (admission, awaiting_response or streaming).", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name", "target_model_name", "stage"},
+	)
+
+	wastedOutputTokens = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceModelComponent,
+			Name:      "wasted_output_tokens_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of output tokens generated for requests without delivering them, broken out by reason.", compbasemetrics.ALPHA),
+		},
+		[]string{"model_name", "target_model_name", "reason"},
+	)
+
+	flowControlShedQueueDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: InferenceExtension,
+			Name:      "flow_control_shed_queue_duration_seconds",
+			Help:      metricsutil.HelpMsgWithStability("Distribution of the time the requests shed after queuing waited in the flow control queue, broken out by priority and reason (ttl_expired or cancelled).", compbasemetrics.ALPHA),
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300},
+		},
+		[]string{"priority", "reason"},
+	)
+
+	// Blue/green Metrics
+	blueGreenRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: InferenceExtension,
+			Name:      "blue_green_requests_total",
+			Help:      metricsutil.HelpMsgWithStability("Counter of requests scheduled to each color of a blue/green deployment.", compbasemetrics.ALPHA),
+		},
+		[]string{"color"},
+	)
+
+	blueGreenWeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: InferenceExtension,
+			Name:      "blue_green_weight",
+			Help:      metricsutil.HelpMsgWithStability("Percentage of requests targeted to each color of a blue/green deployment.", compbasemetrics.ALPHA),
+		},
+		[]string{"color"},
+	)
+
 	// Info Metrics
 	InferenceExtensionInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -250,6 +606,7 @@ var registerMetrics sync.Once
 func Register(customCollectors ...prometheus.Collector) {
 	registerMetrics.Do(func() {
 		metrics.Registry.MustRegister(requestCounter)
+		metrics.Registry.MustRegister(requestClasses)
 		metrics.Registry.MustRegister(requestErrCounter)
 		metrics.Registry.MustRegister(requestLatencies)
 		metrics.Registry.MustRegister(requestSizes)
@@ -257,16 +614,53 @@ func Register(customCollectors ...prometheus.Collector) {
 		metrics.Registry.MustRegister(inputTokens)
 		metrics.Registry.MustRegister(outputTokens)
 		metrics.Registry.MustRegister(runningRequests)
+		metrics.Registry.MustRegister(inferenceModelCapableEndpoints)
 		metrics.Registry.MustRegister(NormalizedTimePerOutputToken)
 		metrics.Registry.MustRegister(inferencePoolAvgKVCache)
 		metrics.Registry.MustRegister(inferencePoolAvgQueueSize)
 		metrics.Registry.MustRegister(inferencePoolReadyPods)
+		metrics.Registry.MustRegister(inferencePoolFailoverActive)
+		metrics.Registry.MustRegister(inferencePoolFailovers)
+		metrics.Registry.MustRegister(inferencePoolPrewarmRequests)
 		metrics.Registry.MustRegister(SchedulerPluginProcessingLatencies)
 		metrics.Registry.MustRegister(SchedulerE2ELatency)
+		metrics.Registry.MustRegister(SchedulerProfileLatency)
+		metrics.Registry.MustRegister(SchedulerProfileDecisions)
+		metrics.Registry.MustRegister(SchedulerScorerWeight)
+		metrics.Registry.MustRegister(SchedulerPluginBudgetViolations)
+		metrics.Registry.MustRegister(SchedulerPluginPanics)
+		metrics.Registry.MustRegister(SchedulerFilterRemainingPods)
+		metrics.Registry.MustRegister(SchedulerScorerScores)
+		metrics.Registry.MustRegister(SchedulerPicks)
+		metrics.Registry.MustRegister(SchedulerRejections)
+		metrics.Registry.MustRegister(SchedulerExtensionFailures)
+		metrics.Registry.MustRegister(SchedulerWasmFailures)
+		metrics.Registry.MustRegister(SchedulerResponseAnomalies)
+		metrics.Registry.MustRegister(SchedulerAnomalyCooldowns)
+		metrics.Registry.MustRegister(SchedulerReusedDecisions)
+		metrics.Registry.MustRegister(SchedulerScoreCacheLookups)
+		metrics.Registry.MustRegister(SchedulerProfileFailures)
+		metrics.Registry.MustRegister(SchedulerConfigReloads)
 		metrics.Registry.MustRegister(InferenceExtensionInfo)
 		metrics.Registry.MustRegister(PrefixCacheSize)
 		metrics.Registry.MustRegister(PrefixCacheHitRatio)
 		metrics.Registry.MustRegister(PrefixCacheHitLength)
+		metrics.Registry.MustRegister(sloRequests)
+		metrics.Registry.MustRegister(sloBurnRate)
+		metrics.Registry.MustRegister(adapterShedRequests)
+		metrics.Registry.MustRegister(flowControlQueueSize)
+		metrics.Registry.MustRegister(flowControlRejectedRequests)
+		metrics.Registry.MustRegister(rateLimitedRequests)
+		metrics.Registry.MustRegister(quotaRejectedRequests)
+		metrics.Registry.MustRegister(namespaceQuotaRejectedRequests)
+		metrics.Registry.MustRegister(namespaceQuotaRequestsInFlight)
+		metrics.Registry.MustRegister(namespaceQuotaTokens)
+		metrics.Registry.MustRegister(listenerOutputTokens)
+		metrics.Registry.MustRegister(cancelledRequests)
+		metrics.Registry.MustRegister(wastedOutputTokens)
+		metrics.Registry.MustRegister(flowControlShedQueueDuration)
+		metrics.Registry.MustRegister(blueGreenRequests)
+		metrics.Registry.MustRegister(blueGreenWeight)
 		for _, collector := range customCollectors {
 			metrics.Registry.MustRegister(collector)
 		}
@@ -276,6 +670,7 @@ func Register(customCollectors ...prometheus.Collector) {
 // Just for integration test
 func Reset() {
 	requestCounter.Reset()
+	requestClasses.Reset()
 	requestErrCounter.Reset()
 	requestLatencies.Reset()
 	requestSizes.Reset()
@@ -283,16 +678,53 @@ func Reset() {
 	inputTokens.Reset()
 	outputTokens.Reset()
 	runningRequests.Reset()
+	inferenceModelCapableEndpoints.Reset()
 	NormalizedTimePerOutputToken.Reset()
 	inferencePoolAvgKVCache.Reset()
 	inferencePoolAvgQueueSize.Reset()
 	inferencePoolReadyPods.Reset()
+	inferencePoolFailoverActive.Reset()
+	inferencePoolFailovers.Reset()
+	inferencePoolPrewarmRequests.Reset()
 	SchedulerPluginProcessingLatencies.Reset()
 	SchedulerE2ELatency.Reset()
+	SchedulerProfileLatency.Reset()
+	SchedulerProfileDecisions.Reset()
+	SchedulerScorerWeight.Reset()
+	SchedulerPluginBudgetViolations.Reset()
+	SchedulerPluginPanics.Reset()
+	SchedulerFilterRemainingPods.Reset()
+	SchedulerScorerScores.Reset()
+	SchedulerPicks.Reset()
+	SchedulerRejections.Reset()
+	SchedulerExtensionFailures.Reset()
+	SchedulerWasmFailures.Reset()
+	SchedulerResponseAnomalies.Reset()
+	SchedulerAnomalyCooldowns.Reset()
+	SchedulerReusedDecisions.Reset()
+	SchedulerScoreCacheLookups.Reset()
+	SchedulerProfileFailures.Reset()
+	SchedulerConfigReloads.Reset()
 	InferenceExtensionInfo.Reset()
 	PrefixCacheSize.Reset()
 	PrefixCacheHitRatio.Reset()
 	PrefixCacheHitLength.Reset()
+	sloRequests.Reset()
+	sloBurnRate.Reset()
+	adapterShedRequests.Reset()
+	flowControlQueueSize.Reset()
+	flowControlRejectedRequests.Reset()
+	rateLimitedRequests.Reset()
+	quotaRejectedRequests.Reset()
+	namespaceQuotaRejectedRequests.Reset()
+	namespaceQuotaRequestsInFlight.Reset()
+	namespaceQuotaTokens.Reset()
+	listenerOutputTokens.Reset()
+	cancelledRequests.Reset()
+	wastedOutputTokens.Reset()
+	flowControlShedQueueDuration.Reset()
+	blueGreenRequests.Reset()
+	blueGreenWeight.Reset()
 }
 
 // RecordRequstCounter records the number of requests.
@@ -300,6 +732,11 @@ func RecordRequestCounter(modelName, targetModelName string) {
 	requestCounter.WithLabelValues(modelName, targetModelName).Inc()
 }
 
+// RecordRequestClass records a request of the given class. The labels without a value are empty.
+func RecordRequestClass(modelName, targetModelName, workloadType, sizeClass, criticality string) {
+	requestClasses.WithLabelValues(modelName, targetModelName, workloadType, sizeClass, criticality).Inc()
+}
+
 // RecordRequestErrCounter records the number of error requests.
 func RecordRequestErrCounter(modelName, targetModelName string, code string) {
 	if code != "" {
@@ -378,6 +815,25 @@ func DecRunningRequests(modelName string) {
 	}
 }
 
+// RunningRequests returns the current running requests of the given model.
+func RunningRequests(modelName string) float64 {
+	m := &dto.Metric{}
+	if err := runningRequests.WithLabelValues(modelName).Write(m); err != nil {
+		return 0
+	}
+	return m.GetGauge().GetValue()
+}
+
+// RecordInferenceModelCapableEndpoints records the number of endpoints that can serve the model.
+func RecordInferenceModelCapableEndpoints(modelName string, count int) {
+	inferenceModelCapableEndpoints.WithLabelValues(modelName).Set(float64(count))
+}
+
+// DeleteInferenceModelCapableEndpoints stops reporting the endpoints that can serve the model.
+func DeleteInferenceModelCapableEndpoints(modelName string) {
+	inferenceModelCapableEndpoints.DeleteLabelValues(modelName)
+}
+
 func RecordInferencePoolAvgKVCache(name string, utilization float64) {
 	inferencePoolAvgKVCache.WithLabelValues(name).Set(utilization)
 }
@@ -390,9 +846,72 @@ func RecordinferencePoolReadyPods(name string, runningPods float64) {
 	inferencePoolReadyPods.WithLabelValues(name).Set(runningPods)
 }
 
-// RecordSchedulerPluginProcessingLatency records the processing latency for a scheduler plugin.
-func RecordSchedulerPluginProcessingLatency(pluginType, pluginName string, duration time.Duration) {
-	SchedulerPluginProcessingLatencies.WithLabelValues(pluginType, pluginName).Observe(duration.Seconds())
+// RecordSchedulerPluginProcessingLatency records the processing latency for a scheduler plugin. The
+// profile name is empty for the plugins that run outside of the profile cycles.
+func RecordSchedulerPluginProcessingLatency(profileName, pluginType, pluginName string, duration time.Duration) {
+	SchedulerPluginProcessingLatencies.WithLabelValues(profileName, pluginType, pluginName).Observe(duration.Seconds())
+}
+
+// RecordSchedulerExtensionFailure records a failed call of the given method of the scheduler
+// extension at the given address, e.g. because it timed out.
+func RecordSchedulerExtensionFailure(address, method, reason string) {
+	SchedulerExtensionFailures.WithLabelValues(address, method, reason).Inc()
+}
+
+// RecordSchedulerWasmFailure records a failed call of the WebAssembly module of the given
+// scheduler plugin, e.g. because it exceeded its time limit.
+func RecordSchedulerWasmFailure(pluginName, reason string) {
+	SchedulerWasmFailures.WithLabelValues(pluginName, reason).Inc()
+}
+
+// RecordSchedulerResponseAnomaly records a degenerate response flagged by the given detector.
+func RecordSchedulerResponseAnomaly(detector string) {
+	SchedulerResponseAnomalies.WithLabelValues(detector).Inc()
+}
+
+// RecordSchedulerAnomalyCooldown records a pod put in cooldown for producing degenerate responses.
+func RecordSchedulerAnomalyCooldown() {
+	SchedulerAnomalyCooldowns.WithLabelValues().Inc()
+}
+
+// RecordSchedulerPluginBudgetViolation records a plugin run that was skipped or aborted because the
+// scheduling cycle exceeded its time budget.
+func RecordSchedulerPluginBudgetViolation(profileName, pluginType, pluginName string) {
+	SchedulerPluginBudgetViolations.WithLabelValues(profileName, pluginType, pluginName).Inc()
+}
+
+// RecordSchedulerFilterRemainingPods records the number of candidate pods remaining after a filter
+// plugin.
+func RecordSchedulerFilterRemainingPods(profileName, pluginName string, pods int) {
+	SchedulerFilterRemainingPods.WithLabelValues(profileName, pluginName).Observe(float64(pods))
+}
+
+// RecordSchedulerScorerScore records the score given to a candidate pod by a scorer plugin.
+func RecordSchedulerScorerScore(profileName, pluginName string, score float64) {
+	SchedulerScorerScores.WithLabelValues(profileName, pluginName).Observe(score)
+}
+
+// RecordSchedulerPick records the pod picked by a scheduler profile cycle, as "namespace/name".
+func RecordSchedulerPick(profileName, pod string) {
+	SchedulerPicks.WithLabelValues(profileName, pod).Inc()
+}
+
+// RecordSchedulerRejection records a scheduler profile cycle that shed or rejected the request for
+// the given reason.
+func RecordSchedulerRejection(profileName, reason string) {
+	SchedulerRejections.WithLabelValues(profileName, reason).Inc()
+}
+
+// RecordSchedulerPluginPanic records a scheduler plugin run that panicked.
+func RecordSchedulerPluginPanic(profileName, pluginType, pluginName string) {
+	SchedulerPluginPanics.WithLabelValues(profileName, pluginType, pluginName).Inc()
+}
+
+// RecordSchedulerProfileDecision records the latency and the outcome of a scheduler profile cycle,
+// which is one of "scheduled", "shed" or "failed".
+func RecordSchedulerProfileDecision(profileName, outcome string, duration time.Duration) {
+	SchedulerProfileLatency.WithLabelValues(profileName).Observe(duration.Seconds())
+	SchedulerProfileDecisions.WithLabelValues(profileName, outcome).Inc()
 }
 
 // RecordSchedulerE2ELatency records the end-to-end scheduling latency.
@@ -400,6 +919,42 @@ func RecordSchedulerE2ELatency(duration time.Duration) {
 	SchedulerE2ELatency.WithLabelValues().Observe(duration.Seconds())
 }
 
+// RecordSchedulerReusedDecision records a request scheduled by reusing a recent decision.
+func RecordSchedulerReusedDecision(modelName string) {
+	SchedulerReusedDecisions.WithLabelValues(modelName).Inc()
+}
+
+// RecordSchedulerScoreCacheLookup records a lookup of the cached scores of identical requests.
+func RecordSchedulerScoreCacheLookup(profileName string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	SchedulerScoreCacheLookups.WithLabelValues(profileName, result).Inc()
+}
+
+// RecordSchedulerProfileFailure records a failed cycle of a scheduler profile, whose outcome is
+// "fallback" if its fallback profile ran instead, "skipped" if the profile is optional, or "failed"
+// if the scheduling of the request failed.
+func RecordSchedulerProfileFailure(profileName, outcome string) {
+	SchedulerProfileFailures.WithLabelValues(profileName, outcome).Inc()
+}
+
+// RecordSchedulerConfigReload records a reload of the scheduler configuration, which either
+// succeeded or failed (in which case the previous configuration is kept).
+func RecordSchedulerConfigReload(succeeded bool) {
+	status := "success"
+	if !succeeded {
+		status = "failure"
+	}
+	SchedulerConfigReloads.WithLabelValues(status).Inc()
+}
+
+// RecordSchedulerScorerWeight records the current weight of a scorer plugin.
+func RecordSchedulerScorerWeight(pluginName string, weight int) {
+	SchedulerScorerWeight.WithLabelValues(pluginName).Set(float64(weight))
+}
+
 // RecordPrefixCacheSize records the size of the prefix indexer in megabytes.
 func RecordPrefixCacheSize(size int64) {
 	PrefixCacheSize.WithLabelValues().Set(float64(size))
@@ -418,6 +973,122 @@ func RecordPrefixCacheMatch(matchedLength, totalLength int) {
 	}
 }
 
+// RecordPoolFailoverActive records whether the traffic of the pool is routed to its standby pool.
+func RecordPoolFailoverActive(name, standbyName string, active bool) {
+	value := 0.0
+	if active {
+		value = 1.0
+	}
+	inferencePoolFailoverActive.WithLabelValues(name, standbyName).Set(value)
+}
+
+// RecordPoolFailover records an automatic failover of the pool to its standby pool.
+func RecordPoolFailover(name, standbyName string) {
+	inferencePoolFailovers.WithLabelValues(name, standbyName).Inc()
+}
+
+// RecordPoolPrewarmRequest records the outcome of a prewarm request sent to a pod joining the pool.
+func RecordPoolPrewarmRequest(name string, succeeded bool) {
+	outcome := "success"
+	if !succeeded {
+		outcome = "failure"
+	}
+	inferencePoolPrewarmRequests.WithLabelValues(name, outcome).Inc()
+}
+
+// RecordSLORequest records a request with a time to first token SLO.
+func RecordSLORequest(modelName string, met bool) {
+	sloRequests.WithLabelValues(modelName, strconv.FormatBool(met)).Inc()
+}
+
+// RecordSLOBurnRate records the error budget burn rate of a model over the given window.
+func RecordSLOBurnRate(modelName, window string, burnRate float64) {
+	sloBurnRate.WithLabelValues(modelName, window).Set(burnRate)
+}
+
+// RecordBlueGreenRequest records a request scheduled to the given color of a blue/green deployment.
+func RecordBlueGreenRequest(color string) {
+	blueGreenRequests.WithLabelValues(color).Inc()
+}
+
+// RecordBlueGreenWeight records the percentage of requests targeted to the given color.
+func RecordBlueGreenWeight(color string, weight int) {
+	blueGreenWeight.WithLabelValues(color).Set(float64(weight))
+}
+
 func RecordInferenceExtensionInfo() {
 	InferenceExtensionInfo.WithLabelValues(CommitSHA, BuildRef).Set(1)
 }
+
+// RecordAdapterShedRequest records a request for the given LoRA adapter that was shed.
+func RecordAdapterShedRequest(targetModelName string) {
+	adapterShedRequests.WithLabelValues(targetModelName).Inc()
+}
+
+// RecordFlowControlQueueSize records the number of requests waiting in the flow control queue of
+// the given priority.
+func RecordFlowControlQueueSize(priority string, size int) {
+	flowControlQueueSize.WithLabelValues(priority).Set(float64(size))
+}
+
+// RecordFlowControlRejectedRequest records a request of the given priority rejected by flow
+// control for the given reason.
+func RecordFlowControlRejectedRequest(priority, reason string) {
+	flowControlRejectedRequests.WithLabelValues(priority, reason).Inc()
+}
+
+// RecordRateLimitedRequest records a request of the given model rejected because the model
+// exceeded the given rate limit.
+func RecordRateLimitedRequest(modelName, limit string) {
+	rateLimitedRequests.WithLabelValues(modelName, limit).Inc()
+}
+
+// RecordQuotaRejectedRequest records a request rejected because its client exceeded the given
+// quota. The client is left out, as the number of clients is unbounded.
+func RecordQuotaRejectedRequest(limit string) {
+	quotaRejectedRequests.WithLabelValues(limit).Inc()
+}
+
+// RecordNamespaceQuotaRejectedRequest records a request rejected because its namespace exceeded
+// the given aggregate quota.
+func RecordNamespaceQuotaRejectedRequest(namespace, limit string) {
+	namespaceQuotaRejectedRequests.WithLabelValues(namespace, limit).Inc()
+}
+
+// RecordNamespaceQuotaRequestsInFlight records the number of requests of the namespace in flight.
+func RecordNamespaceQuotaRequestsInFlight(namespace string, inFlight int64) {
+	namespaceQuotaRequestsInFlight.WithLabelValues(namespace).Set(float64(inFlight))
+}
+
+// RecordNamespaceQuotaTokens records the tokens charged to the quota of the namespace.
+func RecordNamespaceQuotaTokens(namespace string, tokens int) {
+	namespaceQuotaTokens.WithLabelValues(namespace).Add(float64(tokens))
+}
+
+// RecordCancelledRequest records a request whose client disconnected at the given stage, before
+// its response completed.
+func RecordCancelledRequest(modelName, targetModelName, stage string) {
+	cancelledRequests.WithLabelValues(modelName, targetModelName, stage).Inc()
+}
+
+// RecordWastedOutputTokens records output tokens generated for a request without being delivered
+// for the given reason.
+func RecordWastedOutputTokens(modelName, targetModelName, reason string, tokens int) {
+	if tokens > 0 {
+		wastedOutputTokens.WithLabelValues(modelName, targetModelName, reason).Add(float64(tokens))
+	}
+}
+
+// RecordFlowControlShedAfterQueue records a request of the given priority shed for the given reason
+// after it waited in the flow control queue for the given duration.
+func RecordFlowControlShedAfterQueue(priority, reason string, waited time.Duration) {
+	flowControlShedQueueDuration.WithLabelValues(priority, reason).Observe(waited.Seconds())
+}
+
+// RecordListenerOutputTokens records the output tokens generated for a request received on the
+// given gateway listener.
+func RecordListenerOutputTokens(listener string, tokens int) {
+	if tokens > 0 {
+		listenerOutputTokens.WithLabelValues(listener).Add(float64(tokens))
+	}
+}
@@ -19,6 +19,7 @@ package metrics
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -560,9 +561,10 @@ func TestInferencePoolMetrics(t *testing.T) {
 
 func TestSchedulerPluginProcessingLatencies(t *testing.T) {
 	type pluginLatency struct {
-		pluginType string
-		pluginName string
-		duration   time.Duration
+		profileName string
+		pluginType  string
+		pluginName  string
+		duration    time.Duration
 	}
 	scenarios := []struct {
 		name      string
@@ -572,24 +574,28 @@ func TestSchedulerPluginProcessingLatencies(t *testing.T) {
 			name: "multiple plugins",
 			latencies: []pluginLatency{
 				{
-					pluginType: "PostSchedule",
-					pluginName: "PluginB",
-					duration:   200 * time.Millisecond,
+					profileName: "",
+					pluginType:  "PostSchedule",
+					pluginName:  "PluginB",
+					duration:    200 * time.Millisecond,
 				},
 				{
-					pluginType: "Filter",
-					pluginName: "PluginC",
-					duration:   50 * time.Millisecond,
+					profileName: "default",
+					pluginType:  "Filter",
+					pluginName:  "PluginC",
+					duration:    50 * time.Millisecond,
 				},
 				{
-					pluginType: "Scorer",
-					pluginName: "PluginD",
-					duration:   10 * time.Millisecond,
+					profileName: "default",
+					pluginType:  "Scorer",
+					pluginName:  "PluginD",
+					duration:    10 * time.Millisecond,
 				},
 				{
-					pluginType: "Picker",
-					pluginName: "PluginE",
-					duration:   10 * time.Microsecond,
+					profileName: "decode",
+					pluginType:  "Picker",
+					pluginName:  "PluginE",
+					duration:    10 * time.Microsecond,
 				},
 			},
 		},
@@ -598,7 +604,7 @@ func TestSchedulerPluginProcessingLatencies(t *testing.T) {
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
 			for _, latency := range scenario.latencies {
-				RecordSchedulerPluginProcessingLatency(latency.pluginType, latency.pluginName, latency.duration)
+				RecordSchedulerPluginProcessingLatency(latency.profileName, latency.pluginType, latency.pluginName, latency.duration)
 			}
 
 			wantPluginLatencies, err := os.Open("testdata/scheduler_plugin_processing_latencies_metric")
@@ -762,3 +768,48 @@ func TestPrefixCacheMetrics(t *testing.T) {
 		}
 	})
 }
+
+func TestPluginMetrics(t *testing.T) {
+	const sizeMetric = InferenceExtension + "_plugin_session_affinity_sessions"
+	const lookupsMetric = InferenceExtension + "_plugin_session_affinity_lookups_total"
+
+	first := NewPluginMetrics("session-affinity")
+	first.NewGaugeFunc("sessions", "Number of sessions.", func() float64 { return 3 })
+	first.NewCounter("lookups_total", "Counter of lookups.").Add(2)
+	if err := SetPluginMetrics(first); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+# HELP inference_extension_plugin_session_affinity_sessions [ALPHA] Number of sessions.
+# TYPE inference_extension_plugin_session_affinity_sessions gauge
+inference_extension_plugin_session_affinity_sessions 3
+# HELP inference_extension_plugin_session_affinity_lookups_total [ALPHA] Counter of lookups.
+# TYPE inference_extension_plugin_session_affinity_lookups_total counter
+inference_extension_plugin_session_affinity_lookups_total 2
+`
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(want), sizeMetric, lookupsMetric); err != nil {
+		t.Error(err)
+	}
+
+	// The metrics of the same names are replaced, and the others are unregistered.
+	second := NewPluginMetrics("session-affinity")
+	second.NewGaugeFunc("sessions", "Number of sessions.", func() float64 { return 5 })
+	if err := SetPluginMetrics(second); err != nil {
+		t.Fatal(err)
+	}
+	want = `
+# HELP inference_extension_plugin_session_affinity_sessions [ALPHA] Number of sessions.
+# TYPE inference_extension_plugin_session_affinity_sessions gauge
+inference_extension_plugin_session_affinity_sessions 5
+`
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(want), sizeMetric, lookupsMetric); err != nil {
+		t.Error(err)
+	}
+
+	if err := SetPluginMetrics(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.GatherAndCompare(metrics.Registry, strings.NewReader(""), sizeMetric, lookupsMetric); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	metricsutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/metrics"
+)
+
+var (
+	pluginMetricsMu sync.Mutex
+	// registeredPluginMetrics are the plugin metrics registered by SetPluginMetrics, by name.
+	registeredPluginMetrics = map[string]prometheus.Collector{}
+)
+
+// PluginMetrics creates the metrics of a scheduler plugin. The metrics are named
+// inference_extension_plugin_<plugin name>_<metric name>, where the plugin name is lowercased and
+// its characters other than letters, digits and underscores are replaced with underscores. They
+// are registered with the metrics registry by SetPluginMetrics.
+type PluginMetrics struct {
+	prefix     string
+	collectors map[string]prometheus.Collector
+}
+
+// NewPluginMetrics returns a new PluginMetrics for the plugin of the given name.
+func NewPluginMetrics(pluginName string) *PluginMetrics {
+	return &PluginMetrics{
+		prefix: "plugin_" + strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+				return r
+			}
+			return '_'
+		}, strings.ToLower(pluginName)) + "_",
+		collectors: map[string]prometheus.Collector{},
+	}
+}
+
+// NewCounter creates a counter of the plugin.
+func (m *PluginMetrics) NewCounter(name, help string) prometheus.Counter {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: InferenceExtension,
+		Name:      m.prefix + name,
+		Help:      metricsutil.HelpMsgWithStability(help, compbasemetrics.ALPHA),
+	})
+	m.collectors[m.prefix+name] = counter
+	return counter
+}
+
+// NewCounterVec creates a counter of the plugin partitioned by the given labels.
+func (m *PluginMetrics) NewCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: InferenceExtension,
+		Name:      m.prefix + name,
+		Help:      metricsutil.HelpMsgWithStability(help, compbasemetrics.ALPHA),
+	}, labels)
+	m.collectors[m.prefix+name] = counter
+	return counter
+}
+
+// NewGauge creates a gauge of the plugin.
+func (m *PluginMetrics) NewGauge(name, help string) prometheus.Gauge {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: InferenceExtension,
+		Name:      m.prefix + name,
+		Help:      metricsutil.HelpMsgWithStability(help, compbasemetrics.ALPHA),
+	})
+	m.collectors[m.prefix+name] = gauge
+	return gauge
+}
+
+// NewGaugeFunc creates a gauge of the plugin whose value is read from the given function on every
+// collection, e.g. the size of a table of the plugin. The function must be safe to call
+// concurrently with the plugin.
+func (m *PluginMetrics) NewGaugeFunc(name, help string, function func() float64) {
+	m.collectors[m.prefix+name] = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Subsystem: InferenceExtension,
+		Name:      m.prefix + name,
+		Help:      metricsutil.HelpMsgWithStability(help, compbasemetrics.ALPHA),
+	}, function)
+}
+
+// SetPluginMetrics registers the metrics created by the given PluginMetrics with the metrics
+// registry, replacing the registered metrics of the same names, and unregisters the previously
+// registered plugin metrics that aren't among them. It's called whenever the scheduler
+// configuration changes, so the metrics reflect the plugins currently in use.
+func SetPluginMetrics(all ...*PluginMetrics) error {
+	pluginMetricsMu.Lock()
+	defer pluginMetricsMu.Unlock()
+
+	current := map[string]prometheus.Collector{}
+	for _, m := range all {
+		for name, collector := range m.collectors {
+			current[name] = collector
+		}
+	}
+	for name, collector := range registeredPluginMetrics {
+		metrics.Registry.Unregister(collector)
+		delete(registeredPluginMetrics, name)
+	}
+	var errs []error
+	for name, collector := range current {
+		if err := metrics.Registry.Register(collector); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		registeredPluginMetrics[name] = collector
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricspush receives the metrics pushed by the model servers, or sidecars of them, so the
+// scheduling reflects their queue and KV cache state right away instead of at the next scrape. The
+// metrics scraped at an interval are stale after a burst of requests, during which all the requests
+// are routed to the pods that were the least loaded at the last scrape.
+//
+// A pod pushes its metrics with a POST request of a JSON object, e.g.:
+//
+//	{"waitingQueueSize": 3, "runningQueueSize": 12, "kvCacheUsagePercent": 0.42}
+//
+// The pod is identified by the source address of the request, so the metrics must be pushed from
+// the pod, by the model server or a sidecar. The pods whose metrics aren't pushed are still scraped.
+package metricspush
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// Path is the path of the metrics push endpoint.
+const Path = "/v1/metrics"
+
+// maxPushSize is the maximum size of a push.
+const maxPushSize = 64 * 1024
+
+// Push is the body of a push request.
+type Push struct {
+	// Pod is the name of the pushing pod. It's only needed to tell apart the pods sharing the
+	// address the metrics are pushed from, e.g. the pods on the host network.
+	Pod string `json:"pod,omitempty"`
+	backendmetrics.PushedMetrics
+}
+
+// Receiver serves the metrics push endpoint.
+type Receiver struct {
+	datastores func() []datastore.Datastore
+}
+
+// NewReceiver returns a Receiver setting the pushed metrics of the pods of the given datastores.
+func NewReceiver(datastores func() []datastore.Datastore) *Receiver {
+	return &Receiver{datastores: datastores}
+}
+
+// ServeHTTP serves the metrics push endpoint, which accepts POST requests with a Push.
+func (rcv *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	push := &Push{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(push); err != nil {
+		http.Error(w, fmt.Sprintf("invalid metrics: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := push.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid metrics: %v", err), http.StatusBadRequest)
+		return
+	}
+	address, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		address = r.RemoteAddr
+	}
+
+	pods := rcv.pods(address, push.Pod)
+	names := map[string]bool{}
+	for _, pod := range pods {
+		names[pod.GetPod().NamespacedName.Name] = true
+	}
+	switch {
+	case len(names) == 0:
+		http.Error(w, fmt.Sprintf("no pod with address %s", address), http.StatusNotFound)
+		return
+	case len(names) > 1:
+		http.Error(w, fmt.Sprintf("several pods with address %s, the pod must be set", address), http.StatusConflict)
+		return
+	}
+	// A pod is in several datastores when it's in both the primary and the standby pools.
+	for _, pod := range pods {
+		pod.PushMetrics(&push.PushedMetrics)
+	}
+	log.FromContext(r.Context()).V(logutil.TRACE).Info("Received pushed metrics", "pod", pods[0].GetPod().NamespacedName)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pods returns the pods of the datastores with the given address, and the given name if not empty.
+func (rcv *Receiver) pods(address, name string) []backendmetrics.PodMetrics {
+	var pods []backendmetrics.PodMetrics
+	for _, ds := range rcv.datastores() {
+		pods = append(pods, ds.PodList(func(pm backendmetrics.PodMetrics) bool {
+			pod := pm.GetPod()
+			return pod.Address == address && (name == "" || pod.NamespacedName.Name == name)
+		})...)
+	}
+	return pods
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricspush
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+func newTestDatastore(t *testing.T) datastore.Datastore {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	// The metrics aren't scraped during the test.
+	ds := datastore.NewDatastore(t.Context(), backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Hour))
+	pool := testutil.MakeInferencePool("pool").Namespace("default").Selector(map[string]string{"app": "vllm"}).ObjRef()
+	if err := ds.PoolSet(t.Context(), fakeClient, pool); err != nil {
+		t.Fatalf("Failed to set pool: %v", err)
+	}
+	for name, ip := range map[string]string{"pod-0": "10.0.0.1", "host-0": "192.168.0.1", "host-1": "192.168.0.1"} {
+		ds.PodUpdateOrAddIfNotExist(testutil.MakePod(name).Namespace("default").IP(ip).
+			Labels(map[string]string{"app": "vllm"}).ReadyCondition().ObjRef())
+	}
+	return ds
+}
+
+func TestReceiver(t *testing.T) {
+	ds := newTestDatastore(t)
+	receiver := NewReceiver(func() []datastore.Datastore { return []datastore.Datastore{ds} })
+
+	tests := []struct {
+		name       string
+		method     string
+		remoteAddr string
+		body       string
+		wantCode   int
+		wantPod    string
+		wantQueue  int
+	}{
+		{
+			name:       "push from the pod",
+			method:     http.MethodPost,
+			remoteAddr: "10.0.0.1:43210",
+			body:       `{"waitingQueueSize": 4, "kvCacheUsagePercent": 0.5}`,
+			wantCode:   http.StatusNoContent,
+			wantPod:    "pod-0",
+			wantQueue:  4,
+		},
+		{
+			name:       "pod sharing its address",
+			method:     http.MethodPost,
+			remoteAddr: "192.168.0.1:43210",
+			body:       `{"pod": "host-1", "waitingQueueSize": 2}`,
+			wantCode:   http.StatusNoContent,
+			wantPod:    "host-1",
+			wantQueue:  2,
+		},
+		{
+			name:       "ambiguous address",
+			method:     http.MethodPost,
+			remoteAddr: "192.168.0.1:43210",
+			body:       `{"waitingQueueSize": 2}`,
+			wantCode:   http.StatusConflict,
+		},
+		{
+			name:       "unknown address",
+			method:     http.MethodPost,
+			remoteAddr: "10.0.0.9:43210",
+			body:       `{"waitingQueueSize": 2}`,
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "pod name not matching the address",
+			method:     http.MethodPost,
+			remoteAddr: "10.0.0.1:43210",
+			body:       `{"pod": "host-0", "waitingQueueSize": 2}`,
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "out of range metrics",
+			method:     http.MethodPost,
+			remoteAddr: "10.0.0.1:43210",
+			body:       `{"kvCacheUsagePercent": 42}`,
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:       "unknown field",
+			method:     http.MethodPost,
+			remoteAddr: "10.0.0.1:43210",
+			body:       `{"queue": 2}`,
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:       "not a POST",
+			method:     http.MethodGet,
+			remoteAddr: "10.0.0.1:43210",
+			wantCode:   http.StatusMethodNotAllowed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, Path, strings.NewReader(test.body))
+			req.RemoteAddr = test.remoteAddr
+			rec := httptest.NewRecorder()
+			receiver.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.wantCode, rec.Code, rec.Body.String())
+			if test.wantPod != "" {
+				pods := ds.PodList(func(pm backendmetrics.PodMetrics) bool {
+					return pm.GetPod().NamespacedName == types.NamespacedName{Namespace: "default", Name: test.wantPod}
+				})
+				if assert.Len(t, pods, 1) {
+					assert.Equal(t, test.wantQueue, pods[0].GetMetrics().WaitingQueueSize)
+				}
+			}
+		})
+	}
+}
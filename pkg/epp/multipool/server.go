@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multipool
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
+)
+
+// Server is an ext-proc server dispatching every request to the handler of the pool of the Set
+// identified by the gateway, in the requtil.PoolKey key of the filter metadata of the ext-proc
+// request, under the given metadata namespace, or else in the requtil.PoolKey request header if the
+// gateway overwrites it. The requests that don't identify their pool are dispatched to the only
+// pool of the Set, if it has a single pool.
+type Server struct {
+	pools               *Set
+	metadataNamespace   string
+	newHandler          HandlerFactory
+	trustGatewayHeaders bool
+}
+
+// NewServer returns a Server dispatching the requests to the pools of the given Set, whose handlers
+// are created with the given factory.
+func NewServer(pools *Set, metadataNamespace string, newHandler HandlerFactory) *Server {
+	return &Server{
+		pools:             pools,
+		metadataNamespace: metadataNamespace,
+		newHandler:        newHandler,
+	}
+}
+
+// WithGatewayHeaders makes the server read the pool of the requests from the requtil.PoolKey
+// request header when it's missing from the filter metadata. It must only be set if the gateway
+// overwrites the header, as the clients could otherwise choose their pool.
+func (s *Server) WithGatewayHeaders(trusted bool) *Server {
+	s.trustGatewayHeaders = trusted
+	return s
+}
+
+// Process implements extProcPb.ExternalProcessorServer.
+func (s *Server) Process(srv extProcPb.ExternalProcessor_ProcessServer) error {
+	ctx := srv.Context()
+	logger := log.FromContext(ctx)
+
+	// The pool is resolved from the first message of the stream, the request headers, which is then
+	// replayed to the handler of the pool.
+	first, err := srv.Recv()
+	if err == io.EOF || status.Code(err) == codes.Canceled {
+		return nil
+	}
+	if err != nil {
+		return status.Errorf(codes.Unknown, "cannot receive stream request: %v", err)
+	}
+
+	handler, err := s.handler(first)
+	if err != nil {
+		logger.V(logutil.DEFAULT).Error(err, "Failed to dispatch the request to an InferencePool")
+		resp, err := handlers.BuildErrResponse(err)
+		if err != nil {
+			return err
+		}
+		if err := srv.Send(resp); err != nil {
+			return status.Errorf(codes.Unknown, "failed to send response back to Envoy: %v", err)
+		}
+		return nil
+	}
+	return handler.Process(&replayStream{ExternalProcessor_ProcessServer: srv, first: first})
+}
+
+// handler returns the handler of the pool of the given first request of a stream.
+func (s *Server) handler(req *extProcPb.ProcessingRequest) (extProcPb.ExternalProcessorServer, error) {
+	metadata := req.GetMetadataContext().GetFilterMetadata()[s.metadataNamespace]
+	value := metadata.GetFields()[requtil.PoolKey].GetStringValue()
+	if headers, ok := req.Request.(*extProcPb.ProcessingRequest_RequestHeaders); ok && value == "" && s.trustGatewayHeaders {
+		value = requtil.ExtractHeaderValue(headers, requtil.PoolKey)
+	}
+
+	var pool *Pool
+	if value == "" {
+		pools := s.pools.Pools()
+		if len(pools) != 1 {
+			return nil, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Sprintf("the InferencePool of the request is not identified, among %d pools", len(pools))}
+		}
+		pool = pools[0]
+	} else {
+		name := types.NamespacedName{Namespace: s.pools.Namespace(), Name: value}
+		if namespace, poolName, ok := strings.Cut(value, "/"); ok {
+			name = types.NamespacedName{Namespace: namespace, Name: poolName}
+		}
+		if pool = s.pools.Get(name); pool == nil {
+			return nil, errutil.Error{Code: errutil.BadRequest, Msg: fmt.Sprintf("unknown InferencePool %s", name)}
+		}
+	}
+
+	handler, err := pool.Handler(s.newHandler)
+	if err != nil {
+		return nil, errutil.Error{Code: errutil.Internal, Msg: fmt.Sprintf("failed to create the handler of InferencePool %s: %v", pool.Name, err)}
+	}
+	return handler, nil
+}
+
+// replayStream is an ext-proc stream whose first message was already received, and is received
+// again.
+type replayStream struct {
+	extProcPb.ExternalProcessor_ProcessServer
+	first *extProcPb.ProcessingRequest
+}
+
+func (s *replayStream) Recv() (*extProcPb.ProcessingRequest, error) {
+	if first := s.first; first != nil {
+		s.first = nil
+		return first, nil
+	}
+	return s.ExternalProcessor_ProcessServer.Recv()
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multipool
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	envoyTypePb "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
+)
+
+func TestSet(t *testing.T) {
+	ctx := context.Background()
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	selector, _ := labels.Parse("tier=shared")
+	set := NewSet(ctx, "ns", selector, func(ctx context.Context) datastore.Datastore {
+		return datastore.NewDatastore(ctx, pmf)
+	})
+
+	poolA := types.NamespacedName{Namespace: "ns", Name: "a"}
+	poolB := types.NamespacedName{Namespace: "ns", Name: "b"}
+	assert.True(t, set.Selects(poolA, map[string]string{"tier": "shared"}))
+	assert.False(t, set.Selects(poolA, map[string]string{"tier": "dedicated"}))
+	assert.False(t, set.Selects(types.NamespacedName{Namespace: "other", Name: "a"}, map[string]string{"tier": "shared"}))
+
+	a := set.GetOrAdd(poolA)
+	assert.Same(t, a, set.GetOrAdd(poolA))
+	set.GetOrAdd(poolB)
+	assert.Len(t, set.Datastores(), 2)
+	assert.False(t, set.PoolHasSynced())
+
+	assert.True(t, set.Remove(poolA))
+	assert.False(t, set.Remove(poolA))
+	assert.Nil(t, set.Get(poolA))
+	assert.Error(t, a.ctx.Err(), "the context of a removed pool is canceled")
+	assert.Equal(t, []*Pool{set.Get(poolB)}, set.Pools())
+}
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name           string
+		pools          []string
+		headers        map[string]string
+		trustedHeaders bool
+		metadata       map[string]string
+		wantPool       string
+		wantStatus     envoyTypePb.StatusCode
+	}{
+		{
+			name:           "pool in the trusted header",
+			pools:          []string{"a", "b"},
+			headers:        map[string]string{requtil.PoolKey: "b"},
+			trustedHeaders: true,
+			wantPool:       "b",
+		},
+		{
+			name:           "namespaced pool in the trusted header",
+			pools:          []string{"a", "b"},
+			headers:        map[string]string{requtil.PoolKey: "ns/a"},
+			trustedHeaders: true,
+			wantPool:       "a",
+		},
+		{
+			name:       "pool in the untrusted header",
+			pools:      []string{"a", "b"},
+			headers:    map[string]string{requtil.PoolKey: "b"},
+			wantStatus: envoyTypePb.StatusCode_BadRequest,
+		},
+		{
+			name:     "pool in the metadata",
+			pools:    []string{"a", "b"},
+			metadata: map[string]string{requtil.PoolKey: "b"},
+			wantPool: "b",
+		},
+		{
+			name:           "metadata takes precedence over the header",
+			pools:          []string{"a", "b"},
+			headers:        map[string]string{requtil.PoolKey: "a"},
+			trustedHeaders: true,
+			metadata:       map[string]string{requtil.PoolKey: "b"},
+			wantPool:       "b",
+		},
+		{
+			name:     "single pool",
+			pools:    []string{"a"},
+			wantPool: "a",
+		},
+		{
+			name:       "unidentified pool",
+			pools:      []string{"a", "b"},
+			wantStatus: envoyTypePb.StatusCode_BadRequest,
+		},
+		{
+			name:           "unknown pool",
+			pools:          []string{"a", "b"},
+			headers:        map[string]string{requtil.PoolKey: "other/a"},
+			trustedHeaders: true,
+			wantStatus:     envoyTypePb.StatusCode_BadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+			set := NewSet(context.Background(), "ns", labels.Everything(), func(ctx context.Context) datastore.Datastore {
+				return datastore.NewDatastore(ctx, pmf)
+			})
+			for _, pool := range test.pools {
+				set.GetOrAdd(types.NamespacedName{Namespace: "ns", Name: pool})
+			}
+			handled := map[string]int{}
+			server := NewServer(set, "envoy.lb", func(_ context.Context, name types.NamespacedName, _ datastore.Datastore) (extProcPb.ExternalProcessorServer, error) {
+				return &testHandler{name: name.Name, handled: handled}, nil
+			}).WithGatewayHeaders(test.trustedHeaders)
+
+			stream := newTestStream(test.headers, test.metadata)
+			assert.NoError(t, server.Process(stream))
+			if test.wantPool != "" {
+				assert.Equal(t, map[string]int{test.wantPool: 2}, handled, "the handler of the pool receives all the messages")
+				return
+			}
+			assert.Empty(t, handled)
+			if assert.Len(t, stream.sent, 1) {
+				assert.Equal(t, test.wantStatus, stream.sent[0].GetImmediateResponse().GetStatus().GetCode())
+			}
+		})
+	}
+}
+
+// testHandler counts the messages of the streams it processes.
+type testHandler struct {
+	name    string
+	handled map[string]int
+}
+
+func (h *testHandler) Process(srv extProcPb.ExternalProcessor_ProcessServer) error {
+	for {
+		if _, err := srv.Recv(); err != nil {
+			return nil
+		}
+		h.handled[h.name]++
+	}
+}
+
+// testStream is an ext-proc stream of the request headers and body of a request.
+type testStream struct {
+	grpc.ServerStream
+	requests []*extProcPb.ProcessingRequest
+	sent     []*extProcPb.ProcessingResponse
+}
+
+func newTestStream(headers, metadata map[string]string) *testStream {
+	headerValues := []*corev3.HeaderValue{}
+	for key, value := range headers {
+		headerValues = append(headerValues, &corev3.HeaderValue{Key: key, RawValue: []byte(value)})
+	}
+	fields := map[string]*structpb.Value{}
+	for key, value := range metadata {
+		fields[key] = structpb.NewStringValue(value)
+	}
+	return &testStream{
+		requests: []*extProcPb.ProcessingRequest{
+			{
+				Request: &extProcPb.ProcessingRequest_RequestHeaders{
+					RequestHeaders: &extProcPb.HttpHeaders{Headers: &corev3.HeaderMap{Headers: headerValues}},
+				},
+				MetadataContext: &corev3.Metadata{
+					FilterMetadata: map[string]*structpb.Struct{"envoy.lb": {Fields: fields}},
+				},
+			},
+			{
+				Request: &extProcPb.ProcessingRequest_RequestBody{
+					RequestBody: &extProcPb.HttpBody{Body: []byte("{}"), EndOfStream: true},
+				},
+			},
+		},
+	}
+}
+
+func (s *testStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *testStream) Recv() (*extProcPb.ProcessingRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+	return req, nil
+}
+
+func (s *testStream) Send(resp *extProcPb.ProcessingResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
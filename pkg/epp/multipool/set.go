@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multipool lets one Endpoint Picker serve several InferencePools: the pools selected by a
+// label selector in a namespace each get their own datastore, kept in sync by the reconcilers of
+// the controller package, and their own ext-proc handler, to which the requests identified by the
+// gateway as targeting the pool are dispatched.
+package multipool
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	extProcPb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+)
+
+// Pool is an InferencePool of a Set.
+type Pool struct {
+	Name      types.NamespacedName
+	Datastore datastore.Datastore
+
+	// ctx is done once the pool is removed from the Set.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// The handler of the pool is created on its first request.
+	handlerOnce sync.Once
+	handler     extProcPb.ExternalProcessorServer
+	handlerErr  error
+}
+
+// Handler returns the ext-proc handler of the requests of the pool, creating it with the given
+// factory on the first call.
+func (p *Pool) Handler(newHandler HandlerFactory) (extProcPb.ExternalProcessorServer, error) {
+	p.handlerOnce.Do(func() {
+		p.handler, p.handlerErr = newHandler(p.ctx, p.Name, p.Datastore)
+	})
+	return p.handler, p.handlerErr
+}
+
+// HandlerFactory creates the ext-proc handler of the requests of the given pool, e.g. a
+// handlers.StreamingServer with a scheduler of its own, reading the pods of the given datastore.
+// The given context is done once the pool is removed from its Set.
+type HandlerFactory func(ctx context.Context, name types.NamespacedName, ds datastore.Datastore) (extProcPb.ExternalProcessorServer, error)
+
+// Set holds the InferencePools served by the Endpoint Picker, keyed by namespaced name.
+type Set struct {
+	ctx          context.Context
+	namespace    string
+	selector     labels.Selector
+	newDatastore func(ctx context.Context) datastore.Datastore
+
+	mu    sync.RWMutex
+	pools map[types.NamespacedName]*Pool
+}
+
+// NewSet returns an empty Set of the InferencePools of the given namespace whose labels match the
+// given selector. The datastores of the pools are created with the given function, with a context
+// derived from the given one and done once the pool is removed from the Set.
+func NewSet(ctx context.Context, namespace string, selector labels.Selector, newDatastore func(ctx context.Context) datastore.Datastore) *Set {
+	return &Set{
+		ctx:          ctx,
+		namespace:    namespace,
+		selector:     selector,
+		newDatastore: newDatastore,
+		pools:        map[types.NamespacedName]*Pool{},
+	}
+}
+
+// Namespace returns the namespace of the pools of the Set.
+func (s *Set) Namespace() string {
+	return s.namespace
+}
+
+// Selects returns whether the pool of the given namespaced name and labels belongs to the Set.
+func (s *Set) Selects(name types.NamespacedName, poolLabels map[string]string) bool {
+	return name.Namespace == s.namespace && s.selector.Matches(labels.Set(poolLabels))
+}
+
+// GetOrAdd returns the pool of the given name, adding it with a new datastore if it's not in the
+// Set.
+func (s *Set) GetOrAdd(name types.NamespacedName) *Pool {
+	if pool := s.Get(name); pool != nil {
+		return pool
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pool, ok := s.pools[name]; ok {
+		return pool
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	pool := &Pool{Name: name, Datastore: s.newDatastore(ctx), ctx: ctx, cancel: cancel}
+	s.pools[name] = pool
+	return pool
+}
+
+// Get returns the pool of the given name, or nil if it's not in the Set.
+func (s *Set) Get(name types.NamespacedName) *Pool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pools[name]
+}
+
+// Remove removes the pool of the given name from the Set, clears its datastore, which stops
+// refreshing the metrics of its pods, and cancels its context. The requests being handled by the
+// pool run to completion.
+// It returns false if the pool is not in the Set.
+func (s *Set) Remove(name types.NamespacedName) bool {
+	s.mu.Lock()
+	pool, ok := s.pools[name]
+	delete(s.pools, name)
+	s.mu.Unlock()
+	if ok {
+		pool.Datastore.Clear()
+		pool.cancel()
+	}
+	return ok
+}
+
+// Pools returns the pools of the Set, sorted by name.
+func (s *Set) Pools() []*Pool {
+	s.mu.RLock()
+	pools := make([]*Pool, 0, len(s.pools))
+	for _, pool := range s.pools {
+		pools = append(pools, pool)
+	}
+	s.mu.RUnlock()
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].Name.String() < pools[j].Name.String()
+	})
+	return pools
+}
+
+// Datastores returns the datastores of the pools of the Set, sorted by pool name.
+func (s *Set) Datastores() []datastore.Datastore {
+	pools := s.Pools()
+	datastores := make([]datastore.Datastore, 0, len(pools))
+	for _, pool := range pools {
+		datastores = append(datastores, pool.Datastore)
+	}
+	return datastores
+}
+
+// PoolHasSynced returns whether at least one pool of the Set is synced, i.e. whether the Endpoint
+// Picker can serve requests.
+func (s *Set) PoolHasSynced() bool {
+	for _, pool := range s.Pools() {
+		if pool.Datastore.PoolHasSynced() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poolstatus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// DefaultReportInterval is how often the health of the pool is evaluated.
+	DefaultReportInterval = 10 * time.Second
+	// DefaultMetricsFreshness is how recently the metrics of an endpoint must have been updated
+	// for the endpoint to be ready.
+	DefaultMetricsFreshness = 5 * time.Second
+)
+
+// Datastore provides access to the pool and its pods.
+type Datastore interface {
+	PoolGet() (*v1alpha2.InferencePool, error)
+	PodGetAll() []backendmetrics.PodMetrics
+}
+
+// SaturationDetector tells whether the pool has capacity for new requests.
+type SaturationDetector interface {
+	IsSaturated(ctx context.Context) bool
+}
+
+// Reporter periodically evaluates the health of the pool, and reports it through the conditions
+// of the endpoint picker parent of the InferencePool status.
+type Reporter struct {
+	client    client.Client
+	datastore Datastore
+	detector  SaturationDetector
+	interval  time.Duration
+	freshness time.Duration
+
+	written []metav1.Condition // the last conditions written
+}
+
+// NewReporter creates a new Reporter. If the detector is nil, the Saturated condition isn't
+// reported.
+func NewReporter(c client.Client, datastore Datastore, detector SaturationDetector, interval time.Duration) *Reporter {
+	if interval <= 0 {
+		interval = DefaultReportInterval
+	}
+	return &Reporter{
+		client:    c,
+		datastore: datastore,
+		detector:  detector,
+		interval:  interval,
+		freshness: DefaultMetricsFreshness,
+	}
+}
+
+// SetupWithManager registers the periodic evaluation with the given manager.
+func (r *Reporter) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The replicas may see the pods
+// differently, so only the leader writes the status, rather than the replicas overriding each
+// other.
+func (r *Reporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start evaluates the pool every interval until the context is done.
+func (r *Reporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Report(ctx); err != nil {
+				log.FromContext(ctx).V(logutil.DEFAULT).Error(err, "Failed to update the InferencePool status")
+			}
+		}
+	}
+}
+
+// Report evaluates the health of the pool, and updates the InferencePool status if it changed.
+func (r *Reporter) Report(ctx context.Context) error {
+	pool, err := r.datastore.PoolGet()
+	if err != nil {
+		// The pool isn't synced yet, or it was deleted.
+		return nil
+	}
+	conditions := r.Evaluate(ctx, pool.Generation, time.Now())
+
+	parent := endpointPickerParent(pool.Status.Parents)
+	if parent != nil && sameConditions(parent.Conditions, conditions) {
+		r.written = conditions
+		return nil
+	}
+	if sameConditions(r.written, conditions) {
+		// The conditions were written, but the datastore doesn't hold the updated pool yet.
+		return nil
+	}
+
+	updated := pool.DeepCopy()
+	parent = endpointPickerParent(updated.Status.Parents)
+	if parent == nil {
+		updated.Status.Parents = append(updated.Status.Parents, v1alpha2.PoolStatus{
+			GatewayRef: corev1.ObjectReference{
+				Kind: v1alpha2.PoolStatusEndpointPickerKind,
+				Name: v1alpha2.PoolStatusEndpointPickerName,
+			},
+		})
+		parent = &updated.Status.Parents[len(updated.Status.Parents)-1]
+	}
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&parent.Conditions, condition)
+	}
+	if err := r.client.Status().Patch(ctx, updated, client.MergeFromWithOptions(pool, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	r.written = conditions
+	return nil
+}
+
+// Evaluate returns the conditions of the pool, with the given generation, at the given time. The
+// messages are fixed per reason, so the status is only patched when the conditions change, not
+// with the number of endpoints.
+func (r *Reporter) Evaluate(ctx context.Context, generation int64, now time.Time) []metav1.Condition {
+	pods := r.datastore.PodGetAll()
+	ready := 0
+	for _, pod := range pods {
+		if metrics := pod.GetMetrics(); metrics != nil && now.Sub(metrics.UpdateTime) <= r.freshness {
+			ready++
+		}
+	}
+
+	conditions := []metav1.Condition{{
+		Type:               string(v1alpha2.InferencePoolConditionResolvedRefs),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             string(v1alpha2.InferencePoolReasonResolvedRefs),
+		Message:            "The endpoint picker resolved the pool",
+	}}
+
+	endpointsReady := metav1.Condition{
+		Type:               string(v1alpha2.InferencePoolConditionEndpointsReady),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             string(v1alpha2.InferencePoolReasonEndpointsReady),
+		Message:            "At least one endpoint reported its metrics",
+	}
+	switch {
+	case len(pods) == 0:
+		endpointsReady.Status = metav1.ConditionFalse
+		endpointsReady.Reason = string(v1alpha2.InferencePoolReasonNoEndpoints)
+		endpointsReady.Message = "The pool selects no ready endpoint"
+	case ready == 0:
+		endpointsReady.Status = metav1.ConditionFalse
+		endpointsReady.Reason = string(v1alpha2.InferencePoolReasonMetricsUnavailable)
+		endpointsReady.Message = fmt.Sprintf("None of the endpoints reported their metrics in the last %s", r.freshness)
+	}
+	conditions = append(conditions, endpointsReady)
+
+	if r.detector == nil {
+		return conditions
+	}
+	saturated := metav1.Condition{
+		Type:               string(v1alpha2.InferencePoolConditionSaturated),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             string(v1alpha2.InferencePoolReasonCapacityAvailable),
+		Message:            "At least one endpoint has capacity for new requests",
+	}
+	switch {
+	case len(pods) == 0:
+		saturated.Status = metav1.ConditionUnknown
+		saturated.Reason = string(v1alpha2.InferencePoolReasonNoEndpoints)
+		saturated.Message = "The pool selects no ready endpoint"
+	case r.detector.IsSaturated(ctx):
+		saturated.Status = metav1.ConditionTrue
+		saturated.Reason = string(v1alpha2.InferencePoolReasonSaturated)
+		saturated.Message = "All the endpoints are above the saturation thresholds, or their metrics are stale"
+	}
+	return append(conditions, saturated)
+}
+
+// endpointPickerParent returns the parent holding the conditions of the endpoint picker, or nil if
+// there is none.
+func endpointPickerParent(parents []v1alpha2.PoolStatus) *v1alpha2.PoolStatus {
+	for i := range parents {
+		ref := parents[i].GatewayRef
+		if ref.Kind == v1alpha2.PoolStatusEndpointPickerKind && ref.Name == v1alpha2.PoolStatusEndpointPickerName {
+			return &parents[i]
+		}
+	}
+	return nil
+}
+
+// sameConditions returns whether the existing conditions hold all the given conditions.
+func sameConditions(existing []metav1.Condition, conditions []metav1.Condition) bool {
+	for _, condition := range conditions {
+		found := meta.FindStatusCondition(existing, condition.Type)
+		if found == nil || found.Status != condition.Status || found.Reason != condition.Reason ||
+			found.Message != condition.Message || found.ObservedGeneration != condition.ObservedGeneration {
+			return false
+		}
+	}
+	return true
+}
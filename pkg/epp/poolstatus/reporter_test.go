@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poolstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
+)
+
+type fakeDatastore struct {
+	pool *v1alpha2.InferencePool
+	pods []backendmetrics.PodMetrics
+}
+
+func (ds *fakeDatastore) PoolGet() (*v1alpha2.InferencePool, error) { return ds.pool, nil }
+func (ds *fakeDatastore) PodGetAll() []backendmetrics.PodMetrics    { return ds.pods }
+
+type fakeDetector struct {
+	saturated bool
+}
+
+func (d *fakeDetector) IsSaturated(context.Context) bool { return d.saturated }
+
+func fakePod(name string, updated time.Time) backendmetrics.PodMetrics {
+	return &backendmetrics.FakePodMetrics{
+		Pod:     &backend.Pod{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}},
+		Metrics: &backendmetrics.MetricsState{UpdateTime: updated},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		pods      []backendmetrics.PodMetrics
+		saturated bool
+		want      map[v1alpha2.InferencePoolConditionType]v1alpha2.InferencePoolReason
+	}{
+		{
+			name: "no endpoints",
+			want: map[v1alpha2.InferencePoolConditionType]v1alpha2.InferencePoolReason{
+				v1alpha2.InferencePoolConditionResolvedRefs:   v1alpha2.InferencePoolReasonResolvedRefs,
+				v1alpha2.InferencePoolConditionEndpointsReady: v1alpha2.InferencePoolReasonNoEndpoints,
+				v1alpha2.InferencePoolConditionSaturated:      v1alpha2.InferencePoolReasonNoEndpoints,
+			},
+		},
+		{
+			name: "stale metrics",
+			pods: []backendmetrics.PodMetrics{fakePod("p1", now.Add(-time.Minute))},
+			want: map[v1alpha2.InferencePoolConditionType]v1alpha2.InferencePoolReason{
+				v1alpha2.InferencePoolConditionResolvedRefs:   v1alpha2.InferencePoolReasonResolvedRefs,
+				v1alpha2.InferencePoolConditionEndpointsReady: v1alpha2.InferencePoolReasonMetricsUnavailable,
+				v1alpha2.InferencePoolConditionSaturated:      v1alpha2.InferencePoolReasonCapacityAvailable,
+			},
+		},
+		{
+			name:      "saturated",
+			pods:      []backendmetrics.PodMetrics{fakePod("p1", now.Add(-time.Minute)), fakePod("p2", now)},
+			saturated: true,
+			want: map[v1alpha2.InferencePoolConditionType]v1alpha2.InferencePoolReason{
+				v1alpha2.InferencePoolConditionResolvedRefs:   v1alpha2.InferencePoolReasonResolvedRefs,
+				v1alpha2.InferencePoolConditionEndpointsReady: v1alpha2.InferencePoolReasonEndpointsReady,
+				v1alpha2.InferencePoolConditionSaturated:      v1alpha2.InferencePoolReasonSaturated,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewReporter(nil, &fakeDatastore{pods: test.pods}, &fakeDetector{saturated: test.saturated}, 0)
+			got := map[v1alpha2.InferencePoolConditionType]v1alpha2.InferencePoolReason{}
+			for _, condition := range r.Evaluate(t.Context(), 1, now) {
+				got[v1alpha2.InferencePoolConditionType(condition.Type)] = v1alpha2.InferencePoolReason(condition.Reason)
+				assert.Equal(t, int64(1), condition.ObservedGeneration)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestReporter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha2.Install(scheme)
+	pool := testutil.MakeInferencePool("pool").Namespace("default").ObjRef()
+	gatewayStatus := v1alpha2.PoolStatus{
+		Conditions: []metav1.Condition{{Type: string(v1alpha2.InferencePoolConditionAccepted), Status: metav1.ConditionTrue, Reason: "Accepted"}},
+	}
+	gatewayStatus.GatewayRef.Name = "gateway"
+	pool.Status.Parents = []v1alpha2.PoolStatus{gatewayStatus}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).WithStatusSubresource(pool).Build()
+	ds := &fakeDatastore{}
+	detector := &fakeDetector{}
+	r := NewReporter(fakeClient, ds, detector, 0)
+
+	// report evaluates the pool, and returns the parents written in the status.
+	report := func() []v1alpha2.PoolStatus {
+		got := &v1alpha2.InferencePool{}
+		if err := fakeClient.Get(t.Context(), client.ObjectKeyFromObject(pool), got); err != nil {
+			t.Fatalf("Failed to get the pool: %v", err)
+		}
+		// The datastore holds the latest pool, as kept by the InferencePool reconciler.
+		ds.pool = got
+		assert.NoError(t, r.Report(t.Context()))
+		if err := fakeClient.Get(t.Context(), client.ObjectKeyFromObject(pool), got); err != nil {
+			t.Fatalf("Failed to get the pool: %v", err)
+		}
+		return got.Status.Parents
+	}
+
+	parents := report()
+	if assert.Len(t, parents, 2) {
+		assert.Equal(t, gatewayStatus, parents[0], "the status reported by a gateway is kept")
+		assert.Equal(t, v1alpha2.PoolStatusEndpointPickerKind, parents[1].GatewayRef.Kind)
+		assert.Equal(t, v1alpha2.PoolStatusEndpointPickerName, parents[1].GatewayRef.Name)
+		condition := meta.FindStatusCondition(parents[1].Conditions, string(v1alpha2.InferencePoolConditionEndpointsReady))
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		}
+	}
+
+	ds.pods = []backendmetrics.PodMetrics{fakePod("p1", time.Now())}
+	detector.saturated = true
+	parents = report()
+	if assert.Len(t, parents, 2) {
+		assert.True(t, meta.IsStatusConditionTrue(parents[1].Conditions, string(v1alpha2.InferencePoolConditionEndpointsReady)))
+		assert.True(t, meta.IsStatusConditionTrue(parents[1].Conditions, string(v1alpha2.InferencePoolConditionSaturated)))
+	}
+
+	// An unchanged evaluation doesn't update the status.
+	got := &v1alpha2.InferencePool{}
+	assert.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(pool), got))
+	resourceVersion := got.ResourceVersion
+	report()
+	assert.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(pool), got))
+	assert.Equal(t, resourceVersion, got.ResourceVersion)
+
+	// Nor does a change of the number of endpoints that leaves the conditions unchanged.
+	ds.pods = append(ds.pods, fakePod("p2", time.Now().Add(-time.Minute)))
+	report()
+	assert.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(pool), got))
+	assert.Equal(t, resourceVersion, got.ResourceVersion)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prewarm
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultRequests and DefaultHintWindow are zero, i.e. prewarming is disabled by default.
+	DefaultRequests   = 0
+	DefaultHintWindow = 0 * time.Second
+	DefaultPath       = "/v1/completions"
+	DefaultBody       = `{"prompt":"Hello","max_tokens":1}`
+	DefaultTimeout    = 30 * time.Second
+)
+
+// Environment variable names for prewarming configuration
+const (
+	EnvRequests   = "PREWARM_REQUESTS"
+	EnvPath       = "PREWARM_PATH"
+	EnvBody       = "PREWARM_BODY"
+	EnvTimeout    = "PREWARM_TIMEOUT"
+	EnvHintWindow = "PREWARM_HINT_WINDOW"
+)
+
+// Config holds the configuration of the Prewarmer.
+type Config struct {
+	// Requests is the number of tiny requests sent concurrently to each pod that joins the pool,
+	// so the connections are established and the model is warmed up before user requests arrive.
+	// Zero disables the prewarm requests.
+	Requests int
+	// Path and Body are the path and the JSON body of the prewarm requests, which are POSTed to
+	// the target port of the pool. Model servers serving several models usually require the body to
+	// name one of them.
+	Path string
+	Body string
+	// Timeout bounds each prewarm request.
+	Timeout time.Duration
+	// HintWindow is how long after joining the pool a pod is hinted to the gateway as an endpoint to
+	// open connections to, in the dynamic metadata of the responses to the requests. Zero disables
+	// the hints.
+	HintWindow time.Duration
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Requests:   DefaultRequests,
+		Path:       DefaultPath,
+		Body:       DefaultBody,
+		Timeout:    DefaultTimeout,
+		HintWindow: DefaultHintWindow,
+	}
+}
+
+// Enabled returns true if either the prewarm requests or the hints are enabled.
+func (c *Config) Enabled() bool {
+	return c.Requests > 0 || c.HintWindow > 0
+}
+
+// LoadConfigFromEnv loads the prewarming Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("prewarm-config")
+
+	cfg := &Config{}
+
+	cfg.Requests = envutil.GetEnvInt(EnvRequests, DefaultRequests, logger)
+	if cfg.Requests < 0 {
+		cfg.Requests = DefaultRequests
+	}
+
+	cfg.Path = envutil.GetEnvString(EnvPath, DefaultPath, logger)
+	cfg.Body = envutil.GetEnvString(EnvBody, DefaultBody, logger)
+
+	cfg.Timeout = envutil.GetEnvDuration(EnvTimeout, DefaultTimeout, logger)
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	cfg.HintWindow = envutil.GetEnvDuration(EnvHintWindow, DefaultHintWindow, logger)
+	if cfg.HintWindow < 0 {
+		cfg.HintWindow = DefaultHintWindow
+	}
+
+	logger.Info("Prewarming configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
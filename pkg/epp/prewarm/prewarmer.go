@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prewarm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// DefaultCheckInterval is how often the pool is checked for new pods.
+const DefaultCheckInterval = time.Second
+
+// Datastore provides access to the pool and its pods.
+type Datastore interface {
+	PoolGet() (*v1alpha2.InferencePool, error)
+	PodGetAll() []backendmetrics.PodMetrics
+}
+
+// Prewarmer detects the pods joining the pool after a scale-up, and warms them up before they
+// take their first user requests, so these requests don't pay for the connection setup and the
+// model warm-up at the same time. A new pod is sent a few tiny requests, and hinted to the gateway
+// for a while as an endpoint to open connections to.
+// The pods already in the pool when the Prewarmer starts are assumed to be warm.
+type Prewarmer struct {
+	config    *Config
+	datastore Datastore
+	interval  time.Duration
+	client    *http.Client
+
+	mu     sync.RWMutex
+	synced bool                               // whether the pods in the pool at startup are known
+	joined map[types.NamespacedName]time.Time // when each pod of the pool was first seen
+	hints  map[types.NamespacedName]string    // the endpoints of the pods joined within the hint window
+	now    func() time.Time
+}
+
+// NewPrewarmer creates a new Prewarmer.
+func NewPrewarmer(config *Config, datastore Datastore, interval time.Duration) *Prewarmer {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	return &Prewarmer{
+		config:    config,
+		datastore: datastore,
+		interval:  interval,
+		client:    &http.Client{Timeout: config.Timeout},
+		joined:    map[types.NamespacedName]time.Time{},
+		hints:     map[types.NamespacedName]string{},
+		now:       time.Now,
+	}
+}
+
+// SetupWithManager registers the periodic check with the given manager.
+func (p *Prewarmer) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(p)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica holds its own
+// connections to the pods, so every replica warms them up.
+func (p *Prewarmer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the pool for new pods every interval until the context is done.
+func (p *Prewarmer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.Check(ctx)
+		}
+	}
+}
+
+// Check detects the pods that joined the pool since the last check, and starts warming them up.
+// The prewarm requests are sent in the background.
+func (p *Prewarmer) Check(ctx context.Context) {
+	pool, err := p.datastore.PoolGet()
+	if err != nil {
+		return
+	}
+	now := p.now()
+
+	current := map[types.NamespacedName]bool{}
+	joined := []*backend.Pod{}
+	p.mu.Lock()
+	for _, pm := range p.datastore.PodGetAll() {
+		pod := pm.GetPod()
+		current[pod.NamespacedName] = true
+		if _, ok := p.joined[pod.NamespacedName]; ok {
+			continue
+		}
+		p.joined[pod.NamespacedName] = now
+		if p.synced {
+			joined = append(joined, pod)
+			if p.config.HintWindow > 0 {
+				p.hints[pod.NamespacedName] = pod.Address + ":" + strconv.Itoa(int(pod.TargetPort(pool.Spec.TargetPortNumber)))
+			}
+		}
+	}
+	for name, at := range p.joined {
+		if !current[name] {
+			delete(p.joined, name)
+			delete(p.hints, name)
+		} else if now.Sub(at) > p.config.HintWindow {
+			delete(p.hints, name)
+		}
+	}
+	p.synced = true
+	p.mu.Unlock()
+
+	if p.config.Requests == 0 {
+		return
+	}
+	for _, pod := range joined {
+		log.FromContext(ctx).V(logutil.DEFAULT).Info("Prewarming a pod that joined the pool", "pod", pod.NamespacedName, "requests", p.config.Requests)
+		for range p.config.Requests {
+			go p.prewarm(ctx, pool.Name, "http://"+pod.Address+":"+strconv.Itoa(int(pod.TargetPort(pool.Spec.TargetPortNumber)))+p.config.Path)
+		}
+	}
+}
+
+// HintedEndpoints returns the endpoints of the pods that joined the pool within the hint window,
+// in a stable order.
+func (p *Prewarmer) HintedEndpoints() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.hints) == 0 {
+		return nil
+	}
+	endpoints := make([]string, 0, len(p.hints))
+	for _, endpoint := range p.hints {
+		endpoints = append(endpoints, endpoint)
+	}
+	slices.Sort(endpoints)
+	return endpoints
+}
+
+func (p *Prewarmer) prewarm(ctx context.Context, poolName, url string) {
+	err := p.send(ctx, url)
+	if err != nil {
+		log.FromContext(ctx).V(logutil.VERBOSE).Info("Prewarm request failed", "url", url, "err", err)
+	}
+	metrics.RecordPoolPrewarmRequest(poolName, err == nil)
+}
+
+func (p *Prewarmer) send(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(p.config.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prewarm
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+)
+
+type fakeDatastore struct {
+	pool *v1alpha2.InferencePool
+	pods []backendmetrics.PodMetrics
+}
+
+func (ds *fakeDatastore) PoolGet() (*v1alpha2.InferencePool, error) { return ds.pool, nil }
+func (ds *fakeDatastore) PodGetAll() []backendmetrics.PodMetrics    { return ds.pods }
+
+func fakePod(name, address string) backendmetrics.PodMetrics {
+	return &backendmetrics.FakePodMetrics{
+		Pod:     &backend.Pod{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}, Address: address},
+		Metrics: &backendmetrics.MetricsState{},
+	}
+}
+
+func TestPrewarmer(t *testing.T) {
+	var mu sync.Mutex
+	bodies := []string{}
+	received := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+string(body))
+		mu.Unlock()
+		received <- struct{}{}
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	ds := &fakeDatastore{
+		pool: &v1alpha2.InferencePool{Spec: v1alpha2.InferencePoolSpec{TargetPortNumber: int32(portNumber)}},
+		pods: []backendmetrics.PodMetrics{fakePod("pod1", "10.0.0.1")},
+	}
+	config := &Config{Requests: 2, Path: "/v1/completions", Body: `{"max_tokens":1}`, Timeout: time.Second, HintWindow: time.Minute}
+	p := NewPrewarmer(config, ds, 0)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// The pods in the pool at startup are neither warmed up nor hinted.
+	p.Check(ctx)
+	assert.Empty(t, p.HintedEndpoints())
+
+	ds.pods = append(ds.pods, fakePod("pod2", host))
+	p.Check(ctx)
+	assert.Equal(t, []string{host + ":" + port}, p.HintedEndpoints())
+	for range config.Requests {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for the prewarm requests")
+		}
+	}
+	mu.Lock()
+	assert.Equal(t, []string{`POST /v1/completions {"max_tokens":1}`, `POST /v1/completions {"max_tokens":1}`}, bodies)
+	mu.Unlock()
+
+	// A pod is warmed up once, and hinted until the hint window elapses.
+	p.Check(ctx)
+	assert.Len(t, p.HintedEndpoints(), 1)
+	now = now.Add(2 * time.Minute)
+	p.Check(ctx)
+	assert.Empty(t, p.HintedEndpoints())
+	select {
+	case <-received:
+		t.Errorf("Unexpected prewarm request for a pod already warmed up")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultClientHeader is the default header identifying the client of a request.
+	DefaultClientHeader = "x-api-key"
+	// DefaultTokenBudgetWindow is the default window of the token budgets.
+	DefaultTokenBudgetWindow = time.Hour
+	// DefaultRequestTTL is the default time after which a request in flight is forgotten, so the
+	// requests of a replica that stopped while they were in flight don't count forever. It's longer
+	// than any request.
+	DefaultRequestTTL = 10 * time.Minute
+	// DefaultRedisKeyPrefix is the default prefix of the keys of the counters in Redis.
+	DefaultRedisKeyPrefix = "epp-quota:"
+	// DefaultRedisTimeout is the default timeout of the Redis commands.
+	DefaultRedisTimeout = 100 * time.Millisecond
+)
+
+// Environment variable names for quota configuration
+const (
+	EnvClientHeader          = "QUOTA_CLIENT_HEADER"
+	EnvMaxConcurrentRequests = "QUOTA_MAX_CONCURRENT_REQUESTS"
+	EnvTokenBudget           = "QUOTA_TOKEN_BUDGET"
+	EnvTokenBudgetWindow     = "QUOTA_TOKEN_BUDGET_WINDOW"
+	EnvClientLimits          = "QUOTA_CLIENT_LIMITS"
+	EnvRequestTTL            = "QUOTA_REQUEST_TTL"
+	EnvRedisAddress          = "QUOTA_REDIS_ADDRESS"
+	EnvRedisKeyPrefix        = "QUOTA_REDIS_KEY_PREFIX"
+	EnvRedisTimeout          = "QUOTA_REDIS_TIMEOUT"
+	EnvRedisUsername         = "QUOTA_REDIS_USERNAME"
+	EnvRedisPassword         = "QUOTA_REDIS_PASSWORD"
+	EnvRedisTLS              = "QUOTA_REDIS_TLS"
+
+	EnvNamespaceHeader                = "NAMESPACE_QUOTA_HEADER"
+	EnvNamespaceMaxConcurrentRequests = "NAMESPACE_QUOTA_MAX_CONCURRENT_REQUESTS"
+	EnvNamespaceTokensPerMinute       = "NAMESPACE_QUOTA_TOKENS_PER_MINUTE"
+	EnvNamespaceLimits                = "NAMESPACE_QUOTA_LIMITS"
+)
+
+// Limits are the quotas of a client, or the aggregate quotas of a namespace.
+type Limits struct {
+	// MaxConcurrentRequests is the maximum number of requests of the client in flight, zero if not
+	// limited.
+	MaxConcurrentRequests int
+	// TokenBudget is the maximum number of tokens the requests of the client may use per window,
+	// zero if not limited. The window of the namespaces is a minute.
+	TokenBudget int64
+}
+
+// Config holds the configuration of the Quota.
+type Config struct {
+	// ClientHeader is the header identifying the client of a request, e.g. its API key or user ID.
+	// The requests without it are not subject to the quotas.
+	ClientHeader string
+	// Default are the limits of the clients without their own limits.
+	Default Limits
+	// Clients are the limits of specific clients, keyed by the value of their header.
+	Clients map[string]Limits
+	// TokenBudgetWindow is the window of the token budgets: the tokens used by a client are reset
+	// at the start of every window.
+	TokenBudgetWindow time.Duration
+	// RequestTTL is the time after which a request in flight is forgotten if it didn't end.
+	RequestTTL time.Duration
+	// RedisAddress is the address of the Redis server keeping the counters, so the quotas are
+	// shared by the replicas of the EPP. The counters are kept in memory if empty.
+	RedisAddress string
+	// RedisKeyPrefix is the prefix of the keys of the counters in Redis.
+	RedisKeyPrefix string
+	// RedisTimeout is the timeout of the Redis commands.
+	RedisTimeout time.Duration
+	// RedisUsername and RedisPassword authenticate the EPP to the Redis server, if set.
+	RedisUsername string
+	RedisPassword string
+	// RedisTLS is true if the connections to the Redis server use TLS.
+	RedisTLS bool
+
+	// NamespaceHeader is the header identifying the namespace, or tenant, of a request for the
+	// namespace quotas. The namespace of the route of the request is used if empty.
+	NamespaceHeader string
+	// NamespaceDefault are the aggregate limits of the namespaces without their own limits, across
+	// all the models of the pool. These namespaces are counted together, as the OtherNamespace
+	// bucket.
+	NamespaceDefault Limits
+	// Namespaces are the aggregate limits of specific namespaces, the only ones counted on their
+	// own.
+	Namespaces map[string]Limits
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		ClientHeader:      DefaultClientHeader,
+		Clients:           map[string]Limits{},
+		Namespaces:        map[string]Limits{},
+		TokenBudgetWindow: DefaultTokenBudgetWindow,
+		RequestTTL:        DefaultRequestTTL,
+		RedisKeyPrefix:    DefaultRedisKeyPrefix,
+		RedisTimeout:      DefaultRedisTimeout,
+	}
+}
+
+// Enabled returns true if any client or namespace has a quota.
+func (c *Config) Enabled() bool {
+	return (c.ClientHeader != "" && anyLimits(c.Default, c.Clients)) || c.NamespacesEnabled()
+}
+
+// NamespacesEnabled returns true if any namespace has a quota.
+func (c *Config) NamespacesEnabled() bool {
+	return anyLimits(c.NamespaceDefault, c.Namespaces)
+}
+
+func anyLimits(defaultLimits Limits, limits map[string]Limits) bool {
+	if defaultLimits != (Limits{}) {
+		return true
+	}
+	for _, l := range limits {
+		if l != (Limits{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadConfigFromEnv loads the Quota Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("quota-config")
+
+	cfg := NewDefaultConfig()
+
+	cfg.ClientHeader = strings.ToLower(envutil.GetEnvString(EnvClientHeader, DefaultClientHeader, logger))
+
+	cfg.Default.MaxConcurrentRequests = envutil.GetEnvInt(EnvMaxConcurrentRequests, 0, logger)
+	if cfg.Default.MaxConcurrentRequests < 0 {
+		cfg.Default.MaxConcurrentRequests = 0
+	}
+	cfg.Default.TokenBudget = int64(envutil.GetEnvInt(EnvTokenBudget, 0, logger))
+	if cfg.Default.TokenBudget < 0 {
+		cfg.Default.TokenBudget = 0
+	}
+
+	clients, err := ParseClientLimits(envutil.GetEnvString(EnvClientLimits, "", logger))
+	if err != nil {
+		logger.Error(err, "Ignoring invalid client limits", "env", EnvClientLimits)
+	} else {
+		cfg.Clients = clients
+	}
+
+	cfg.TokenBudgetWindow = envutil.GetEnvDuration(EnvTokenBudgetWindow, DefaultTokenBudgetWindow, logger)
+	if cfg.TokenBudgetWindow <= 0 {
+		cfg.TokenBudgetWindow = DefaultTokenBudgetWindow
+	}
+
+	cfg.RequestTTL = envutil.GetEnvDuration(EnvRequestTTL, DefaultRequestTTL, logger)
+	if cfg.RequestTTL <= 0 {
+		cfg.RequestTTL = DefaultRequestTTL
+	}
+
+	cfg.NamespaceHeader = strings.ToLower(envutil.GetEnvString(EnvNamespaceHeader, "", logger))
+	cfg.NamespaceDefault.MaxConcurrentRequests = max(envutil.GetEnvInt(EnvNamespaceMaxConcurrentRequests, 0, logger), 0)
+	cfg.NamespaceDefault.TokenBudget = int64(max(envutil.GetEnvInt(EnvNamespaceTokensPerMinute, 0, logger), 0))
+	namespaces, err := ParseClientLimits(envutil.GetEnvString(EnvNamespaceLimits, "", logger))
+	if err != nil {
+		logger.Error(err, "Ignoring invalid namespace limits", "env", EnvNamespaceLimits)
+	} else {
+		cfg.Namespaces = namespaces
+	}
+
+	cfg.RedisAddress = envutil.GetEnvString(EnvRedisAddress, "", logger)
+	cfg.RedisKeyPrefix = envutil.GetEnvString(EnvRedisKeyPrefix, DefaultRedisKeyPrefix, logger)
+	cfg.RedisTimeout = envutil.GetEnvDuration(EnvRedisTimeout, DefaultRedisTimeout, logger)
+	if cfg.RedisTimeout <= 0 {
+		cfg.RedisTimeout = DefaultRedisTimeout
+	}
+	cfg.RedisUsername = envutil.GetEnvString(EnvRedisUsername, "", logger)
+	// The password is read directly, as the env helpers log the values they read.
+	cfg.RedisPassword = os.Getenv(EnvRedisPassword)
+	cfg.RedisTLS = envutil.GetEnvString(EnvRedisTLS, "false", logger) == "true"
+
+	// The client limits and the Redis password are left out of the log, as they are secrets.
+	logger.Info("Quota configuration loaded from env", "clientHeader", cfg.ClientHeader, "default", cfg.Default,
+		"clients", len(cfg.Clients), "tokenBudgetWindow", cfg.TokenBudgetWindow, "requestTTL", cfg.RequestTTL,
+		"redisAddress", cfg.RedisAddress, "redisKeyPrefix", cfg.RedisKeyPrefix, "redisTimeout", cfg.RedisTimeout,
+		"redisUsername", cfg.RedisUsername, "redisTLS", cfg.RedisTLS,
+		"namespaceHeader", cfg.NamespaceHeader, "namespaceDefault", cfg.NamespaceDefault, "namespaces", cfg.Namespaces)
+	return cfg
+}
+
+// ParseClientLimits parses a comma separated list of client (or namespace) limits, each formatted
+// as <client>=<max concurrent requests>[:<token budget>], where zero means no limit, e.g.
+// "team-a=10:1000000,team-b=0:50000".
+func ParseClientLimits(s string) (map[string]Limits, error) {
+	clients := map[string]Limits{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		client, rest, ok := strings.Cut(item, "=")
+		if !ok || client == "" {
+			return nil, fmt.Errorf("invalid client limits %q, expected <client>=<max concurrent requests>[:<token budget>]", item)
+		}
+		requests, tokens, _ := strings.Cut(rest, ":")
+		limits := Limits{}
+		var err error
+		if limits.MaxConcurrentRequests, err = parseLimit[int](requests); err != nil {
+			return nil, fmt.Errorf("invalid max concurrent requests of client %q: %w", client, err)
+		}
+		if limits.TokenBudget, err = parseLimit[int64](tokens); err != nil {
+			return nil, fmt.Errorf("invalid token budget of client %q: %w", client, err)
+		}
+		clients[client] = limits
+	}
+	return clients, nil
+}
+
+func parseLimit[T int | int64](s string) (T, error) {
+	if s == "" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if limit < 0 {
+		return 0, fmt.Errorf("negative limit %v", limit)
+	}
+	return T(limit), nil
+}
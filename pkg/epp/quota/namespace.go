@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+const (
+	// limitTokensPerMinute is the token quota of the namespaces, as named in the metrics and the
+	// error responses.
+	limitTokensPerMinute = "tokens_per_minute"
+	// namespaceTokenWindow is the window of the token quotas of the namespaces.
+	namespaceTokenWindow = time.Minute
+	// OtherNamespace is the bucket of the namespaces without their own limits.
+	OtherNamespace = "other"
+)
+
+// Namespace returns the namespace of a request with the given headers, received on the given route
+// ("namespace/name[/rule]"), for the namespace quotas: the value of the namespace header if one is
+// configured, the namespace of the route otherwise. Empty if it has none. As the header and the
+// route may be set by the clients, the namespaces without their own limits share the
+// OtherNamespace bucket, so they can't evade the default limits nor explode the metrics.
+func (q *Quota) Namespace(headers map[string]string, route string) string {
+	var namespace string
+	if q.config.NamespaceHeader != "" {
+		namespace = headers[q.config.NamespaceHeader]
+	} else {
+		namespace, _, _ = strings.Cut(route, "/")
+	}
+	if _, ok := q.config.Namespaces[namespace]; namespace != "" && !ok {
+		return OtherNamespace
+	}
+	return namespace
+}
+
+// AdmitNamespace returns the error the request of the given namespace is rejected with if the
+// namespace exceeds its aggregate quotas. Otherwise, it returns the lease of the request if it was
+// counted in flight, in which case NamespaceDone must be called with it once the request ended. The
+// requests without namespace are always admitted.
+func (q *Quota) AdmitNamespace(ctx context.Context, namespace string) (string, error) {
+	if namespace == "" {
+		return "", nil
+	}
+	logger := log.FromContext(ctx)
+	limits := q.namespaceLimits(namespace)
+	if limits.TokenBudget > 0 {
+		now := q.now()
+		// As for the clients, the tokens of the request are charged once it completed.
+		used, err := q.store.Add(ctx, q.namespaceTokensKey(namespace, now), 0, namespaceTokenWindow)
+		if err != nil {
+			logger.Error(err, "Failed to read the tokens used by the namespace, admitting the request")
+		} else if used >= limits.TokenBudget {
+			metrics.RecordNamespaceQuotaRejectedRequest(namespace, limitTokensPerMinute)
+			retryAfter := time.Unix(0, (now.UnixNano()/int64(namespaceTokenWindow)+1)*int64(namespaceTokenWindow)).Sub(now)
+			return "", namespaceQuotaError(namespace, limitTokensPerMinute, limits.TokenBudget, retryAfter,
+				fmt.Sprintf("the namespace %s used its quota of %d tokens per minute", namespace, limits.TokenBudget))
+		}
+	}
+	if limits.MaxConcurrentRequests <= 0 {
+		return "", nil
+	}
+	key, lease := q.namespaceRequestsKey(namespace), uuid.NewString()
+	inFlight, err := q.store.Acquire(ctx, key, lease, q.config.RequestTTL)
+	if err != nil {
+		logger.Error(err, "Failed to count the request of the namespace in flight, admitting the request")
+		return "", nil
+	}
+	if inFlight > int64(limits.MaxConcurrentRequests) {
+		if inFlight, err = q.store.Release(ctx, key, lease); err != nil {
+			logger.Error(err, "Failed to uncount the rejected request of the namespace")
+		} else {
+			metrics.RecordNamespaceQuotaRequestsInFlight(namespace, inFlight)
+		}
+		metrics.RecordNamespaceQuotaRejectedRequest(namespace, limitConcurrentRequests)
+		return "", namespaceQuotaError(namespace, limitConcurrentRequests, int64(limits.MaxConcurrentRequests), 0,
+			fmt.Sprintf("the namespace %s has its maximum of %d requests in flight", namespace, limits.MaxConcurrentRequests))
+	}
+	metrics.RecordNamespaceQuotaRequestsInFlight(namespace, inFlight)
+	return lease, nil
+}
+
+// NamespaceDone uncounts the request of the given namespace with the given lease, that was counted
+// in flight.
+func (q *Quota) NamespaceDone(ctx context.Context, namespace, lease string) {
+	inFlight, err := q.store.Release(ctx, q.namespaceRequestsKey(namespace), lease)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to uncount the request of the namespace")
+		return
+	}
+	metrics.RecordNamespaceQuotaRequestsInFlight(namespace, inFlight)
+}
+
+// ChargeNamespace charges the given number of tokens used by a request of the given namespace to
+// its quota.
+func (q *Quota) ChargeNamespace(ctx context.Context, namespace string, tokens int) {
+	if namespace == "" || tokens <= 0 || q.namespaceLimits(namespace).TokenBudget <= 0 {
+		return
+	}
+	metrics.RecordNamespaceQuotaTokens(namespace, tokens)
+	if _, err := q.store.Add(ctx, q.namespaceTokensKey(namespace, q.now()), int64(tokens), namespaceTokenWindow); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to charge the tokens of the request to the namespace")
+	}
+}
+
+// namespaceLimits returns the aggregate limits of the given namespace.
+func (q *Quota) namespaceLimits(namespace string) Limits {
+	if limits, ok := q.config.Namespaces[namespace]; ok {
+		return limits
+	}
+	return q.config.NamespaceDefault
+}
+
+// The namespaces are not secrets, so they are not hashed in the keys unlike the clients.
+func (q *Quota) namespaceRequestsKey(namespace string) string {
+	return "namespace-requests:" + namespace
+}
+
+// namespaceTokensKey returns the key of the tokens used by the given namespace in the minute of the
+// given time.
+func (q *Quota) namespaceTokensKey(namespace string, now time.Time) string {
+	return "namespace-tokens:" + namespace + ":" + strconv.FormatInt(now.UnixNano()/int64(namespaceTokenWindow), 10)
+}
+
+// quotaErrorBody is the body of the responses to the requests rejected by the namespace quotas, in
+// the shape of the OpenAI errors, so the clients can tell which quota they exceeded.
+type quotaErrorBody struct {
+	Error quotaErrorDetails `json:"error"`
+}
+
+type quotaErrorDetails struct {
+	Type      string `json:"type"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Namespace string `json:"namespace"`
+	Limit     int64  `json:"limit"`
+	// RetryAfterSeconds mirrors the Retry-After header, if any.
+	RetryAfterSeconds int64 `json:"retry_after_seconds,omitempty"`
+}
+
+// namespaceQuotaError returns the error of a request rejected because its namespace exceeded the
+// given quota.
+func namespaceQuotaError(namespace, quota string, limit int64, retryAfter time.Duration, msg string) errutil.Error {
+	details := quotaErrorDetails{
+		Type:              "quota_exceeded",
+		Code:              quota,
+		Message:           msg,
+		Namespace:         namespace,
+		Limit:             limit,
+		RetryAfterSeconds: int64(math.Ceil(retryAfter.Seconds())),
+	}
+	body, _ := json.Marshal(quotaErrorBody{Error: details}) // can't fail
+	return errutil.Error{
+		Code:       errutil.RateLimited,
+		Msg:        msg,
+		RetryAfter: retryAfter,
+		Headers:    &errutil.Headers{"content-type": "application/json"},
+		Body:       string(body),
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+func TestAdmitNamespaceConcurrentRequests(t *testing.T) {
+	config := NewDefaultConfig()
+	config.NamespaceDefault = Limits{MaxConcurrentRequests: 2}
+	config.Namespaces = map[string]Limits{"team-a": {MaxConcurrentRequests: 2}, "batch": {MaxConcurrentRequests: 1}}
+	q, _ := newTestQuota(config)
+	ctx := context.Background()
+
+	leases := []string{}
+	for range 2 {
+		lease, err := q.AdmitNamespace(ctx, "team-a")
+		if err != nil || lease == "" {
+			t.Fatalf("AdmitNamespace() = %q, %v, want a lease", lease, err)
+		}
+		leases = append(leases, lease)
+	}
+	_, err := q.AdmitNamespace(ctx, "team-a")
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.RateLimited {
+		t.Fatalf("Got error %v, want a rate limited error", err)
+	}
+	var body quotaErrorBody
+	if err := json.Unmarshal([]byte(e.Body), &body); err != nil {
+		t.Fatalf("Invalid error body %q: %v", e.Body, err)
+	}
+	want := quotaErrorDetails{
+		Type:      "quota_exceeded",
+		Code:      "concurrent_requests",
+		Message:   "the namespace team-a has its maximum of 2 requests in flight",
+		Namespace: "team-a",
+		Limit:     2,
+	}
+	if diff := cmp.Diff(want, body.Error); diff != "" {
+		t.Errorf("Unexpected error body (-want +got): %v", diff)
+	}
+	// The rejected request isn't counted in flight.
+	q.NamespaceDone(ctx, "team-a", leases[0])
+	if _, err := q.AdmitNamespace(ctx, "team-a"); err != nil {
+		t.Errorf("Unexpected error once a request ended: %v", err)
+	}
+
+	// The namespaces have their own limits.
+	if _, err := q.AdmitNamespace(ctx, "batch"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := q.AdmitNamespace(ctx, "batch"); err == nil {
+		t.Errorf("Expected the second request of the namespace to be rejected")
+	}
+	// The requests without namespace are not limited.
+	if lease, err := q.AdmitNamespace(ctx, ""); err != nil || lease != "" {
+		t.Errorf("AdmitNamespace() = %q, %v, want no lease", lease, err)
+	}
+}
+
+func TestAdmitNamespaceTokensPerMinute(t *testing.T) {
+	config := NewDefaultConfig()
+	config.NamespaceDefault = Limits{TokenBudget: 1000}
+	q, now := newTestQuota(config)
+	ctx := context.Background()
+
+	q.ChargeNamespace(ctx, "team-a", 600)
+	if _, err := q.AdmitNamespace(ctx, "team-a"); err != nil {
+		t.Fatalf("Unexpected error within the quota: %v", err)
+	}
+	q.ChargeNamespace(ctx, "team-a", 600)
+
+	*now = now.Add(15 * time.Second)
+	_, err := q.AdmitNamespace(ctx, "team-a")
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.RateLimited {
+		t.Fatalf("Got error %v, want a rate limited error", err)
+	}
+	if e.RetryAfter != 45*time.Second {
+		t.Errorf("Got Retry-After %v, want the end of the minute in 45s", e.RetryAfter)
+	}
+	// The quotas are per namespace.
+	if _, err := q.AdmitNamespace(ctx, "team-b"); err != nil {
+		t.Errorf("Unexpected error for another namespace: %v", err)
+	}
+
+	// The quota is reset in the next minute.
+	*now = now.Add(45 * time.Second)
+	if _, err := q.AdmitNamespace(ctx, "team-a"); err != nil {
+		t.Errorf("Unexpected error in the next minute: %v", err)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	headers := map[string]string{"x-tenant-id": "tenant-a"}
+	config := NewDefaultConfig()
+	config.Namespaces = map[string]Limits{"team-a": {MaxConcurrentRequests: 1}, "tenant-a": {}}
+	q := NewQuota(config)
+	if got := q.Namespace(headers, "team-a/chat-route/rule-0"); got != "team-a" {
+		t.Errorf("Namespace() = %q, want the namespace of the route", got)
+	}
+	if got := q.Namespace(headers, ""); got != "" {
+		t.Errorf("Namespace() = %q, want none", got)
+	}
+	// The namespaces without their own limits share a bucket.
+	if got := q.Namespace(headers, "team-b/chat-route"); got != OtherNamespace {
+		t.Errorf("Namespace() = %q, want %q", got, OtherNamespace)
+	}
+
+	config.NamespaceHeader = "x-tenant-id"
+	if got := q.Namespace(headers, "team-a/chat-route"); got != "tenant-a" {
+		t.Errorf("Namespace() = %q, want the namespace of the header", got)
+	}
+	if got := q.Namespace(map[string]string{"x-tenant-id": "spoofed"}, "team-a/chat-route"); got != OtherNamespace {
+		t.Errorf("Namespace() = %q, want %q", got, OtherNamespace)
+	}
+}
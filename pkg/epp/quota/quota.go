@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota enforces quotas per client of the inference gateway, the client of a request
+// being identified by a configurable header such as its API key or user ID: a maximum number of
+// requests in flight, and a budget of tokens per window. The requests exceeding the quotas of
+// their client are rejected with a 429.
+//
+// It also enforces aggregate quotas per namespace, or tenant, across all the models of the pool: a
+// maximum number of requests in flight, and a number of tokens per minute. The requests exceeding
+// the quotas of their namespace are rejected with a 429 whose JSON body describes the quota.
+//
+// The counters of the quotas are kept in a Store, in the memory of the EPP by default, or in Redis
+// so the quotas are shared by the replicas of the EPP. The quotas fail open: the requests are
+// admitted if the Store can't be reached.
+package quota
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+// The quotas, as named in the metrics.
+const (
+	limitConcurrentRequests = "concurrent_requests"
+	limitTokenBudget        = "token_budget"
+)
+
+// Quota enforces the quotas of the clients.
+type Quota struct {
+	config *Config
+	store  Store
+	now    func() time.Time
+}
+
+// NewQuota creates a new Quota keeping its counters in Redis if an address is configured, in
+// memory otherwise.
+func NewQuota(config *Config) *Quota {
+	if config.RedisAddress != "" {
+		return NewQuotaWithStore(config, newRedisStore(config))
+	}
+	return NewQuotaWithStore(config, newMemoryStore())
+}
+
+// NewQuotaWithStore creates a new Quota keeping its counters in the given Store.
+func NewQuotaWithStore(config *Config, store Store) *Quota {
+	return &Quota{config: config, store: store, now: time.Now}
+}
+
+// Client returns the client of a request with the given headers, empty if it has none.
+func (q *Quota) Client(headers map[string]string) string {
+	return headers[q.config.ClientHeader]
+}
+
+// Admit returns the error the request of the given client is rejected with if the client exceeds
+// its quotas. Otherwise, it returns the lease of the request if it was counted in flight, in which
+// case Done must be called with it once the request ended. The requests without client are always
+// admitted.
+func (q *Quota) Admit(ctx context.Context, client string) (string, error) {
+	if client == "" {
+		return "", nil
+	}
+	logger := log.FromContext(ctx)
+	limits := q.limits(client)
+	if limits.TokenBudget > 0 {
+		now := q.now()
+		// The tokens of the request are charged once it completed, so only the clients that
+		// exhausted their budget are rejected.
+		used, err := q.store.Add(ctx, q.tokensKey(client, now), 0, q.config.TokenBudgetWindow)
+		if err != nil {
+			logger.Error(err, "Failed to read the tokens used by the client, admitting the request")
+		} else if used >= limits.TokenBudget {
+			metrics.RecordQuotaRejectedRequest(limitTokenBudget)
+			return "", errutil.Error{
+				Code:       errutil.RateLimited,
+				Msg:        fmt.Sprintf("the client used its budget of %d tokens", limits.TokenBudget),
+				RetryAfter: time.Unix(0, (q.window(now)+1)*int64(q.config.TokenBudgetWindow)).Sub(now),
+			}
+		}
+	}
+	if limits.MaxConcurrentRequests <= 0 {
+		return "", nil
+	}
+	key, lease := q.requestsKey(client), uuid.NewString()
+	inFlight, err := q.store.Acquire(ctx, key, lease, q.config.RequestTTL)
+	if err != nil {
+		logger.Error(err, "Failed to count the request of the client in flight, admitting the request")
+		return "", nil
+	}
+	if inFlight > int64(limits.MaxConcurrentRequests) {
+		if _, err := q.store.Release(ctx, key, lease); err != nil {
+			logger.Error(err, "Failed to uncount the rejected request of the client")
+		}
+		metrics.RecordQuotaRejectedRequest(limitConcurrentRequests)
+		return "", errutil.Error{
+			Code: errutil.RateLimited,
+			Msg:  fmt.Sprintf("the client has its maximum of %d requests in flight", limits.MaxConcurrentRequests),
+		}
+	}
+	return lease, nil
+}
+
+// Done uncounts the request of the given client with the given lease, that was counted in flight.
+func (q *Quota) Done(ctx context.Context, client, lease string) {
+	if _, err := q.store.Release(ctx, q.requestsKey(client), lease); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to uncount the request of the client")
+	}
+}
+
+// Charge charges the given number of tokens used by a request of the given client to its budget.
+func (q *Quota) Charge(ctx context.Context, client string, tokens int) {
+	if client == "" || tokens <= 0 || q.limits(client).TokenBudget <= 0 {
+		return
+	}
+	if _, err := q.store.Add(ctx, q.tokensKey(client, q.now()), int64(tokens), q.config.TokenBudgetWindow); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to charge the tokens of the request to the client")
+	}
+}
+
+// limits returns the limits of the given client.
+func (q *Quota) limits(client string) Limits {
+	if limits, ok := q.config.Clients[client]; ok {
+		return limits
+	}
+	return q.config.Default
+}
+
+// requestsKey returns the key of the leases of the requests of the given client in flight.
+func (q *Quota) requestsKey(client string) string {
+	return "requests:" + clientKey(client)
+}
+
+// tokensKey returns the key of the tokens used by the given client in the window of the given time.
+func (q *Quota) tokensKey(client string, now time.Time) string {
+	return "tokens:" + clientKey(client) + ":" + strconv.FormatInt(q.window(now), 10)
+}
+
+// window returns the index of the token budget window of the given time since the epoch.
+func (q *Quota) window(now time.Time) int64 {
+	return now.UnixNano() / int64(q.config.TokenBudgetWindow)
+}
+
+// clientKey returns the key of a client in the Store, a hash as the clients may be identified by
+// secrets.
+func clientKey(client string) string {
+	sum := sha256.Sum256([]byte(client))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+func newTestQuota(config *Config) (*Quota, *time.Time) {
+	now := time.Unix(3600, 0)
+	store := newMemoryStore()
+	store.now = func() time.Time { return now }
+	q := NewQuotaWithStore(config, store)
+	q.now = store.now
+	return q, &now
+}
+
+func TestAdmitConcurrentRequests(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Default = Limits{MaxConcurrentRequests: 2}
+	config.Clients = map[string]Limits{"batch": {MaxConcurrentRequests: 1}, "admin": {}}
+	q, _ := newTestQuota(config)
+	ctx := context.Background()
+
+	leases := []string{}
+	for range 2 {
+		lease, err := q.Admit(ctx, "team-a")
+		if err != nil || lease == "" {
+			t.Fatalf("Admit() = %q, %v, want a lease", lease, err)
+		}
+		leases = append(leases, lease)
+	}
+	_, err := q.Admit(ctx, "team-a")
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.RateLimited {
+		t.Fatalf("Got error %v, want a rate limited error", err)
+	}
+	// The rejected request isn't counted in flight.
+	q.Done(ctx, "team-a", leases[0])
+	if _, err := q.Admit(ctx, "team-a"); err != nil {
+		t.Errorf("Unexpected error once a request ended: %v", err)
+	}
+
+	// The clients have their own limits, and a client can be exempted.
+	if _, err := q.Admit(ctx, "batch"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := q.Admit(ctx, "batch"); err == nil {
+		t.Errorf("Expected the second request of the client to be rejected")
+	}
+	for range 5 {
+		if lease, err := q.Admit(ctx, "admin"); err != nil || lease != "" {
+			t.Fatalf("Admit() = %q, %v, want no lease", lease, err)
+		}
+	}
+	// The requests without client are not limited.
+	if lease, err := q.Admit(ctx, ""); err != nil || lease != "" {
+		t.Errorf("Admit() = %q, %v, want no lease", lease, err)
+	}
+}
+
+func TestAdmitExpiredLeases(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Default = Limits{MaxConcurrentRequests: 1}
+	q, now := newTestQuota(config)
+	ctx := context.Background()
+
+	// A request whose end was never handled counts until its lease expires, even if the client
+	// keeps sending requests.
+	if _, err := q.Admit(ctx, "team-a"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for range 3 {
+		*now = now.Add(config.RequestTTL / 4)
+		if _, err := q.Admit(ctx, "team-a"); err == nil {
+			t.Fatalf("Expected the request to be rejected while the first one is in flight")
+		}
+	}
+	*now = now.Add(config.RequestTTL / 2)
+	if _, err := q.Admit(ctx, "team-a"); err != nil {
+		t.Errorf("Unexpected error once the lease of the first request expired: %v", err)
+	}
+}
+
+func TestAdmitTokenBudget(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Default = Limits{TokenBudget: 1000}
+	q, now := newTestQuota(config)
+	ctx := context.Background()
+
+	if _, err := q.Admit(ctx, "team-a"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.Charge(ctx, "team-a", 600)
+	if _, err := q.Admit(ctx, "team-a"); err != nil {
+		t.Fatalf("Unexpected error within the budget: %v", err)
+	}
+	q.Charge(ctx, "team-a", 600)
+
+	*now = now.Add(15 * time.Minute)
+	_, err := q.Admit(ctx, "team-a")
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.RateLimited {
+		t.Fatalf("Got error %v, want a rate limited error", err)
+	}
+	if e.RetryAfter != 45*time.Minute {
+		t.Errorf("Got Retry-After %v, want the end of the window in 45m", e.RetryAfter)
+	}
+	// The budgets are per client.
+	if _, err := q.Admit(ctx, "team-b"); err != nil {
+		t.Errorf("Unexpected error for another client: %v", err)
+	}
+
+	// The budget is reset in the next window.
+	*now = now.Add(45 * time.Minute)
+	if _, err := q.Admit(ctx, "team-a"); err != nil {
+		t.Errorf("Unexpected error in the next window: %v", err)
+	}
+}
+
+func TestClient(t *testing.T) {
+	q := NewQuota(NewDefaultConfig())
+	if got := q.Client(map[string]string{"x-api-key": "secret", "x-user-id": "alice"}); got != "secret" {
+		t.Errorf("Client() = %q, want %q", got, "secret")
+	}
+	if got := q.Client(map[string]string{}); got != "" {
+		t.Errorf("Client() = %q, want none", got)
+	}
+}
+
+func TestParseClientLimits(t *testing.T) {
+	clients, err := ParseClientLimits("team-a=10:1000000, team-b=0:50000,admin=0,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]Limits{
+		"team-a": {MaxConcurrentRequests: 10, TokenBudget: 1000000},
+		"team-b": {TokenBudget: 50000},
+		"admin":  {},
+	}
+	if diff := cmp.Diff(want, clients); diff != "" {
+		t.Errorf("Unexpected limits (-want +got): %v", diff)
+	}
+
+	for _, invalid := range []string{"team-a", "=10", "team-a=ten", "team-a=10:-1", "team-a=1.5"} {
+		if _, err := ParseClientLimits(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
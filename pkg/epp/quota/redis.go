@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// addScript adds to a counter and extends its expiry atomically.
+var addScript = redis.NewScript(`local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return value`)
+
+// acquireScript removes the expired leases of a sorted set scored by their expiry, adds a lease to
+// it and returns its size atomically. The set itself expires with its last lease.
+var acquireScript = redis.NewScript(`redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[3])
+if tonumber(redis.call('PTTL', KEYS[1])) < tonumber(ARGV[4]) then
+  redis.call('PEXPIRE', KEYS[1], ARGV[4])
+end
+return redis.call('ZCARD', KEYS[1])`)
+
+// releaseScript removes a lease and the expired leases of a sorted set scored by their expiry, and
+// returns its size atomically.
+var releaseScript = redis.NewScript(`redis.call('ZREM', KEYS[1], ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+return redis.call('ZCARD', KEYS[1])`)
+
+// redisStore is a Store keeping the counters and the leases in Redis, so they are shared by the
+// replicas of the EPP.
+type redisStore struct {
+	client    *redis.Client
+	keyPrefix string
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+func newRedisStore(config *Config) *redisStore {
+	options := &redis.Options{
+		Addr:                  config.RedisAddress,
+		Username:              config.RedisUsername,
+		Password:              config.RedisPassword,
+		DialTimeout:           config.RedisTimeout,
+		ReadTimeout:           config.RedisTimeout,
+		WriteTimeout:          config.RedisTimeout,
+		ContextTimeoutEnabled: true,
+	}
+	if config.RedisTLS {
+		options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return &redisStore{client: redis.NewClient(options), keyPrefix: config.RedisKeyPrefix, timeout: config.RedisTimeout, now: time.Now}
+}
+
+func (s *redisStore) Add(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return addScript.Run(ctx, s.client, []string{s.keyPrefix + key}, delta, max(ttl.Milliseconds(), 1)).Int64()
+}
+
+func (s *redisStore) Acquire(ctx context.Context, key, lease string, ttl time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	now := s.now()
+	return acquireScript.Run(ctx, s.client, []string{s.keyPrefix + key},
+		now.UnixMilli(), now.Add(ttl).UnixMilli(), lease, max(ttl.Milliseconds(), 1)).Int64()
+}
+
+func (s *redisStore) Release(ctx context.Context, key, lease string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return releaseScript.Run(ctx, s.client, []string{s.keyPrefix + key}, s.now().UnixMilli(), lease).Int64()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisStore(t *testing.T) (*redisStore, *miniredis.Miniredis, *time.Time) {
+	server := miniredis.RunT(t)
+	config := NewDefaultConfig()
+	config.RedisAddress = server.Addr()
+	config.RedisTimeout = time.Second
+	store := newRedisStore(config)
+	t.Cleanup(func() { store.client.Close() })
+	now := time.Unix(3600, 0)
+	store.now = func() time.Time { return now }
+	return store, server, &now
+}
+
+func TestRedisStoreAdd(t *testing.T) {
+	store, server, _ := newTestRedisStore(t)
+	ctx := context.Background()
+
+	for i, delta := range []int64{1, 1, -1, 0} {
+		got, err := store.Add(ctx, "tokens:client", delta, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error at add %d: %v", i, err)
+		}
+		want := []int64{1, 2, 1, 1}[i]
+		if got != want {
+			t.Errorf("Add %d = %d, want %d", i, got, want)
+		}
+	}
+	if ttl := server.TTL("epp-quota:tokens:client"); ttl != time.Minute {
+		t.Errorf("Got TTL %v, want 1m", ttl)
+	}
+}
+
+func TestRedisStoreLeases(t *testing.T) {
+	store, server, now := newTestRedisStore(t)
+	ctx := context.Background()
+	key := "requests:client"
+
+	for i, lease := range []string{"a", "b", "c"} {
+		got, err := store.Acquire(ctx, key, lease, time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error acquiring lease %q: %v", lease, err)
+		}
+		if want := int64(i + 1); got != want {
+			t.Errorf("Acquire(%q) = %d, want %d", lease, got, want)
+		}
+		*now = now.Add(10 * time.Second)
+	}
+	if got, err := store.Release(ctx, key, "b"); err != nil || got != 2 {
+		t.Errorf("Release() = %d, %v, want 2, nil", got, err)
+	}
+	// The set expires with its last lease.
+	if ttl := server.TTL("epp-quota:" + key); ttl != time.Minute {
+		t.Errorf("Got TTL %v, want 1m", ttl)
+	}
+
+	// The leases that were never released expire.
+	*now = now.Add(35 * time.Second)
+	if got, err := store.Acquire(ctx, key, "d", time.Minute); err != nil || got != 2 {
+		t.Errorf("Acquire() = %d, %v, want 2, nil once the lease a expired", got, err)
+	}
+	*now = now.Add(time.Minute)
+	if got, err := store.Release(ctx, key, "d"); err != nil || got != 0 {
+		t.Errorf("Release() = %d, %v, want 0, nil once all the leases expired", got, err)
+	}
+}
+
+func TestRedisStoreAuth(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireUserAuth("epp", "secret")
+	config := NewDefaultConfig()
+	config.RedisAddress = server.Addr()
+	config.RedisUsername = "epp"
+
+	config.RedisPassword = "wrong"
+	if _, err := NewQuota(config).store.Add(context.Background(), "tokens:client", 1, time.Minute); err == nil {
+		t.Errorf("Expected an error with a wrong password")
+	}
+	config.RedisPassword = "secret"
+	if _, err := NewQuota(config).store.Add(context.Background(), "tokens:client", 1, time.Minute); err != nil {
+		t.Errorf("Unexpected error with the right password: %v", err)
+	}
+}
+
+func TestRedisStoreUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	config := NewDefaultConfig()
+	config.RedisAddress = listener.Addr().String()
+	listener.Close()
+
+	store := newRedisStore(config)
+	if _, err := store.Acquire(context.Background(), "requests:client", "a", time.Minute); err == nil {
+		t.Errorf("Expected an error for an unreachable server")
+	}
+
+	// The quotas fail open.
+	config.Default = Limits{MaxConcurrentRequests: 1, TokenBudget: 1}
+	q := NewQuotaWithStore(config, store)
+	if lease, err := q.Admit(context.Background(), "client"); err != nil || lease != "" {
+		t.Errorf("Admit() = %q, %v, want no lease", lease, err)
+	}
+}
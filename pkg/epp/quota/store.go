@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the counters and the leases of the quotas. It's the extension point to share the
+// quotas across the replicas of the EPP.
+type Store interface {
+	// Add adds delta to the counter of the given key, and returns its new value. The counter is
+	// removed once it wasn't added to for ttl.
+	Add(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// Acquire adds the lease of the given ID to the leases of the given key, and returns the number
+	// of leases of the key, including it. The lease expires after ttl if it isn't released before,
+	// so the leases of the requests a replica lost track of don't count forever.
+	Acquire(ctx context.Context, key, lease string, ttl time.Duration) (int64, error)
+	// Release removes the lease of the given ID from the leases of the given key, and returns the
+	// number of leases left.
+	Release(ctx context.Context, key, lease string) (int64, error)
+}
+
+// sweepInterval is the interval at which the memory store removes its expired counters and leases.
+const sweepInterval = time.Minute
+
+// memoryStore is a Store keeping the counters and the leases in memory, for a single replica of
+// the EPP.
+type memoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	counters  map[string]*counter
+	leases    map[string]map[string]time.Time
+	nextSweep time.Time
+}
+
+type counter struct {
+	value   int64
+	expires time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{now: time.Now, counters: map[string]*counter{}, leases: map[string]map[string]time.Time{}}
+}
+
+func (s *memoryStore) Add(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+	c, ok := s.counters[key]
+	if !ok || now.After(c.expires) {
+		c = &counter{}
+		s.counters[key] = c
+	}
+	c.value += delta
+	c.expires = now.Add(ttl)
+	return c.value, nil
+}
+
+func (s *memoryStore) Acquire(_ context.Context, key, lease string, ttl time.Duration) (int64, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+	leases, ok := s.leases[key]
+	if !ok {
+		leases = map[string]time.Time{}
+		s.leases[key] = leases
+	}
+	expireLeases(leases, now)
+	leases[lease] = now.Add(ttl)
+	return int64(len(leases)), nil
+}
+
+func (s *memoryStore) Release(_ context.Context, key, lease string) (int64, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leases := s.leases[key]
+	delete(leases, lease)
+	expireLeases(leases, now)
+	if len(leases) == 0 {
+		delete(s.leases, key)
+	}
+	return int64(len(leases)), nil
+}
+
+// sweep removes the expired counters and leases, at most once per sweepInterval.
+func (s *memoryStore) sweep(now time.Time) {
+	if !now.After(s.nextSweep) {
+		return
+	}
+	for k, c := range s.counters {
+		if now.After(c.expires) {
+			delete(s.counters, k)
+		}
+	}
+	for k, leases := range s.leases {
+		if expireLeases(leases, now); len(leases) == 0 {
+			delete(s.leases, k)
+		}
+	}
+	s.nextSweep = now.Add(sweepInterval)
+}
+
+// expireLeases removes the expired leases from the given ones.
+func expireLeases(leases map[string]time.Time, now time.Time) {
+	for lease, expires := range leases {
+		if now.After(expires) {
+			delete(leases, lease)
+		}
+	}
+}
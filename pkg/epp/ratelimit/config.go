@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultBurst is the default duration of traffic at the limited rate a model may receive at
+	// once, after it was idle.
+	DefaultBurst = time.Second
+)
+
+// Environment variable names for rate limiting configuration
+const (
+	EnvLimits = "RATE_LIMITS"
+	EnvBurst  = "RATE_LIMIT_BURST"
+)
+
+// Limit is the rate limit of a model.
+type Limit struct {
+	// RequestsPerSecond is the rate of requests of the model, zero if not limited.
+	RequestsPerSecond float64
+	// TokensPerSecond is the rate of tokens of the model, zero if not limited. The tokens of a
+	// request are its estimated prompt and output tokens.
+	TokensPerSecond float64
+}
+
+// Config holds the configuration of the Limiter.
+type Config struct {
+	// Limits are the rate limits, keyed by the model name of the InferenceModels.
+	Limits map[string]Limit
+	// Burst is the duration of traffic at the limited rate a model may receive at once, i.e. the
+	// capacity of the token buckets.
+	Burst time.Duration
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Limits: map[string]Limit{},
+		Burst:  DefaultBurst,
+	}
+}
+
+// Enabled returns true if any model is rate limited.
+func (c *Config) Enabled() bool {
+	return len(c.Limits) > 0
+}
+
+// LoadConfigFromEnv loads the Limiter Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("rate-limit-config")
+
+	cfg := NewDefaultConfig()
+
+	limits, err := ParseLimits(envutil.GetEnvString(EnvLimits, "", logger))
+	if err != nil {
+		logger.Error(err, "Ignoring invalid rate limits", "env", EnvLimits)
+	} else {
+		cfg.Limits = limits
+	}
+
+	cfg.Burst = envutil.GetEnvDuration(EnvBurst, DefaultBurst, logger)
+	if cfg.Burst <= 0 {
+		cfg.Burst = DefaultBurst
+	}
+
+	logger.Info("Rate limit configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
+
+// ParseLimits parses a comma separated list of rate limits, each formatted as
+// <model name>=<requests per second>[:<tokens per second>], where zero means no limit, e.g.
+// "chatbot=10:20000,summarizer=0:5000".
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		model, rest, ok := strings.Cut(item, "=")
+		if !ok || model == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, expected <model name>=<requests per second>[:<tokens per second>]", item)
+		}
+		requests, tokens, _ := strings.Cut(rest, ":")
+		limit := Limit{}
+		var err error
+		if limit.RequestsPerSecond, err = parseRate(requests); err != nil {
+			return nil, fmt.Errorf("invalid requests per second of model %q: %w", model, err)
+		}
+		if limit.TokensPerSecond, err = parseRate(tokens); err != nil {
+			return nil, fmt.Errorf("invalid tokens per second of model %q: %w", model, err)
+		}
+		if limit.RequestsPerSecond == 0 && limit.TokensPerSecond == 0 {
+			continue
+		}
+		limits[model] = limit
+	}
+	return limits, nil
+}
+
+func parseRate(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 {
+		return 0, fmt.Errorf("negative rate %v", rate)
+	}
+	return rate, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit limits the rate of the requests of the models, in requests and in tokens per
+// second, with a token bucket per model and per limit. The requests exceeding the limits of their
+// model are rejected with a 429 and the rate limit headers of the OpenAI API, which the common
+// clients back off on.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+// The limits, as named in the rate limit headers and in the metrics.
+const (
+	limitRequests = "requests"
+	limitTokens   = "tokens"
+)
+
+// Limiter admits the requests of the models within their rate limits.
+type Limiter struct {
+	config *Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*modelBuckets // key: model name
+}
+
+type modelBuckets struct {
+	requests *bucket // nil if the requests are not limited
+	tokens   *bucket // nil if the tokens are not limited
+}
+
+// bucket is a token bucket refilled at rate per second, up to capacity.
+type bucket struct {
+	rate     float64
+	capacity float64
+	level    float64
+	updated  time.Time
+}
+
+// NewLimiter creates a new rate Limiter.
+func NewLimiter(config *Config) *Limiter {
+	return &Limiter{
+		config:  config,
+		now:     time.Now,
+		buckets: map[string]*modelBuckets{},
+	}
+}
+
+// Admit takes a request of the given model and of the given number of tokens from the buckets of
+// the model, or returns the error the request is rejected with if any of them doesn't have enough.
+// The requests of the models without limits are always admitted.
+func (l *Limiter) Admit(model string, tokens int) error {
+	limit, ok := l.config.Limits[model]
+	if !ok {
+		return nil
+	}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	buckets, ok := l.buckets[model]
+	if !ok {
+		buckets = &modelBuckets{
+			requests: newBucket(limit.RequestsPerSecond, l.config.Burst, now),
+			tokens:   newBucket(limit.TokensPerSecond, l.config.Burst, now),
+		}
+		l.buckets[model] = buckets
+	}
+	// The request is taken from a bucket only once all of them have enough.
+	for _, b := range []struct {
+		name   string
+		bucket *bucket
+		cost   float64
+	}{{limitRequests, buckets.requests, 1}, {limitTokens, buckets.tokens, float64(max(tokens, 1))}} {
+		if b.bucket == nil {
+			continue
+		}
+		if wait := b.bucket.wait(b.cost, now); wait > 0 {
+			metrics.RecordRateLimitedRequest(model, b.name)
+			headers := buckets.headers()
+			return errutil.Error{
+				Code:       errutil.RateLimited,
+				Msg:        fmt.Sprintf("the %s of model %s exceed its rate limit", b.name, model),
+				RetryAfter: wait,
+				Headers:    &headers,
+			}
+		}
+	}
+	buckets.requests.take(1)
+	buckets.tokens.take(float64(max(tokens, 1)))
+	return nil
+}
+
+// headers returns the rate limit headers of the buckets: the capacity of each bucket, the whole
+// number of requests or tokens left in it, and the time until it's full again.
+func (b *modelBuckets) headers() errutil.Headers {
+	headers := errutil.Headers{}
+	for name, bucket := range map[string]*bucket{limitRequests: b.requests, limitTokens: b.tokens} {
+		if bucket == nil {
+			continue
+		}
+		headers["x-ratelimit-limit-"+name] = strconv.FormatInt(int64(bucket.capacity), 10)
+		headers["x-ratelimit-remaining-"+name] = strconv.FormatInt(int64(max(math.Floor(bucket.level), 0)), 10)
+		reset := time.Duration((bucket.capacity - bucket.level) / bucket.rate * float64(time.Second))
+		headers["x-ratelimit-reset-"+name] = reset.Round(time.Millisecond).String()
+	}
+	return headers
+}
+
+// newBucket returns a full bucket of the given rate, holding burst worth of it but at least one, or
+// nil if the rate is zero.
+func newBucket(rate float64, burst time.Duration, now time.Time) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	capacity := max(rate*burst.Seconds(), 1)
+	return &bucket{rate: rate, capacity: capacity, level: capacity, updated: now}
+}
+
+// wait refills the bucket, and returns how long until it has enough for the given cost, zero if it
+// has. A cost above the capacity of the bucket only needs a full bucket, so the bucket goes into
+// debt rather than rejecting the cost forever.
+func (b *bucket) wait(cost float64, now time.Time) time.Duration {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.level = min(b.level+elapsed.Seconds()*b.rate, b.capacity)
+		b.updated = now
+	}
+	needed := min(cost, b.capacity)
+	if b.level >= needed {
+		return 0
+	}
+	return time.Duration((needed - b.level) / b.rate * float64(time.Second))
+}
+
+func (b *bucket) take(cost float64) {
+	if b != nil {
+		b.level -= cost
+	}
+}
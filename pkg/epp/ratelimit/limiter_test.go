@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+)
+
+func newTestLimiter(limits map[string]Limit) (*Limiter, *time.Time) {
+	now := time.Unix(1000, 0)
+	limiter := NewLimiter(&Config{Limits: limits, Burst: time.Second})
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestAdmitRequests(t *testing.T) {
+	limiter, now := newTestLimiter(map[string]Limit{"chatbot": {RequestsPerSecond: 2}})
+
+	for range 2 {
+		if err := limiter.Admit("chatbot", 100); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	err := limiter.Admit("chatbot", 100)
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.RateLimited {
+		t.Fatalf("Got error %v, want a rate limited error", err)
+	}
+	if e.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Got Retry-After %v, want 500ms", e.RetryAfter)
+	}
+	wantHeaders := errutil.Headers{
+		"x-ratelimit-limit-requests":     "2",
+		"x-ratelimit-remaining-requests": "0",
+		"x-ratelimit-reset-requests":     "1s",
+	}
+	if diff := cmp.Diff(wantHeaders, *e.Headers); diff != "" {
+		t.Errorf("Unexpected headers (-want +got): %s", diff)
+	}
+
+	// The models without limits are not limited.
+	if err := limiter.Admit("summarizer", 100); err != nil {
+		t.Errorf("Unexpected error for a model without limits: %v", err)
+	}
+
+	// The bucket refills over time.
+	*now = now.Add(500 * time.Millisecond)
+	if err := limiter.Admit("chatbot", 100); err != nil {
+		t.Errorf("Unexpected error after the bucket refilled: %v", err)
+	}
+}
+
+func TestAdmitTokens(t *testing.T) {
+	limiter, now := newTestLimiter(map[string]Limit{"chatbot": {RequestsPerSecond: 10, TokensPerSecond: 1000}})
+
+	// A request costlier than the bucket is admitted from a full bucket, which goes into debt.
+	if err := limiter.Admit("chatbot", 1500); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err := limiter.Admit("chatbot", 10)
+	var e errutil.Error
+	if !errors.As(err, &e) || e.Code != errutil.RateLimited {
+		t.Fatalf("Got error %v, want a rate limited error", err)
+	}
+	if e.RetryAfter != 510*time.Millisecond {
+		t.Errorf("Got Retry-After %v, want 510ms", e.RetryAfter)
+	}
+	if got := (*e.Headers)["x-ratelimit-remaining-tokens"]; got != "0" {
+		t.Errorf("Got %q remaining tokens, want 0", got)
+	}
+	// The rejected request didn't take from the requests bucket.
+	if got := (*e.Headers)["x-ratelimit-remaining-requests"]; got != "9" {
+		t.Errorf("Got %q remaining requests, want 9", got)
+	}
+
+	*now = now.Add(510 * time.Millisecond)
+	if err := limiter.Admit("chatbot", 10); err != nil {
+		t.Errorf("Unexpected error after the bucket refilled: %v", err)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("chatbot=10:20000, summarizer=0:5000,translator=2.5,idle=0,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]Limit{
+		"chatbot":    {RequestsPerSecond: 10, TokensPerSecond: 20000},
+		"summarizer": {TokensPerSecond: 5000},
+		"translator": {RequestsPerSecond: 2.5},
+	}
+	if diff := cmp.Diff(want, limits); diff != "" {
+		t.Errorf("Unexpected limits (-want +got): %v", diff)
+	}
+
+	for _, invalid := range []string{"chatbot", "=10", "chatbot=ten", "chatbot=10:-1"} {
+		if _, err := ParseLimits(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
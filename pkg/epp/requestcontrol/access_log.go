@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/accesslog"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// observedRequestsTTL is how long the requests observed through ext-proc are remembered, i.e. the
+// maximum delay of the access-log records of the requests for them to be skipped.
+const observedRequestsTTL = 10 * time.Minute
+
+// compile-time type assertion
+var _ accesslog.Recorder = &Director{}
+
+// WithAccessLogFeedback makes the Director remember the requests whose responses it observed, so
+// that it records the outcome of the other requests from the access-log records of the gateway.
+func (d *Director) WithAccessLogFeedback() *Director {
+	d.observedRequests = newObservedRequests(observedRequestsTTL)
+	return d
+}
+
+// RecordAccessLog records the outcome of the request of the given access-log record in the error
+// and latency metrics and in the SLO tracker, unless its response was observed through ext-proc
+// already. The request counter is not updated, since the request was counted if it was processed
+// by ext-proc before the gateway failed open.
+func (d *Director) RecordAccessLog(ctx context.Context, record *accesslog.Record) bool {
+	if d.observedRequests != nil && record.RequestID != "" && d.observedRequests.contains(record.RequestID) {
+		return false
+	}
+	if record.Model == "" {
+		return false
+	}
+	targetModel := record.TargetModel
+	if targetModel == "" {
+		targetModel = record.Model
+	}
+	log.FromContext(ctx).V(logutil.TRACE).Info("Recording access-log record", "record", record)
+
+	if !record.Succeeded() {
+		metrics.RecordRequestErrCounter(record.Model, targetModel, errutil.ModelServerError)
+		return true
+	}
+	complete := time.Now()
+	metrics.RecordRequestLatencies(ctx, record.Model, targetModel, complete.Add(-record.Duration()), complete)
+	metrics.RecordResponseSizes(record.Model, targetModel, int(record.BytesSent))
+	if record.ResponseDuration() > 0 {
+		d.sloTracker.Record(ctx, record.Model, record.ResponseDuration())
+	}
+	return true
+}
+
+// observedRequests is a set of request IDs whose entries expire after a TTL.
+type observedRequests struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	ids       map[string]time.Time // request ID -> time observed
+	lastPrune time.Time
+}
+
+func newObservedRequests(ttl time.Duration) *observedRequests {
+	return &observedRequests{
+		ttl:       ttl,
+		ids:       map[string]time.Time{},
+		lastPrune: time.Now(),
+	}
+}
+
+func (o *observedRequests) add(requestID string) {
+	now := time.Now()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ids[requestID] = now
+	if now.Sub(o.lastPrune) < o.ttl {
+		return
+	}
+	for id, observed := range o.ids {
+		if now.Sub(observed) >= o.ttl {
+			delete(o.ids, id)
+		}
+	}
+	o.lastPrune = now
+}
+
+func (o *observedRequests) contains(requestID string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	observed, ok := o.ids[requestID]
+	return ok && time.Since(observed) < o.ttl
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestcontrol
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/adaptershedding"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backoff"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/quota"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/ratelimit"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/schedulingtrace"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/slo"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tokenestimate"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultSessionIDJSONPaths are the request body paths commonly used by agent frameworks and
+	// OpenAI Assistants-style clients to carry a thread/conversation identifier.
+	DefaultSessionIDJSONPaths = "metadata.thread_id,metadata.conversation_id,metadata.session_id,thread_id,conversation_id"
+	// DefaultSessionHeader is the request header carrying the session identifier of the requests,
+	// which takes precedence over the one of their body.
+	DefaultSessionHeader = "x-session-id"
+)
+
+// Environment variable names for Director configuration
+const (
+	EnvSessionIDJSONPaths = "SESSION_ID_JSON_PATHS"
+	EnvSessionHeader      = "SESSION_AFFINITY_HEADER"
+)
+
+// Config holds the configuration for the Director.
+type Config struct {
+	// SessionIDJSONPaths is an ordered list of dot separated request body paths to read the
+	// session identifier from when the session header isn't set. The first path holding a
+	// non-empty string wins.
+	SessionIDJSONPaths []string
+	// SessionHeader is the request header to read the session identifier from. The requests of a
+	// session are sent to the same target model of their InferenceModel, and the session affinity
+	// scorer sends them to the same pod. Empty disables reading the session identifier from headers.
+	SessionHeader string
+	// SLO is the configuration of the per-model SLO compliance tracking.
+	SLO *slo.Config
+	// AdapterShedding is the configuration of the shedding of LoRA adapter requests while the pool
+	// is saturated.
+	AdapterShedding *adaptershedding.Config
+	// TokenEstimation is the configuration of the estimation of the prompt and output tokens of the
+	// requests.
+	TokenEstimation *tokenestimate.Config
+	// FlowControl is the configuration of the queueing of the requests while the pool is saturated.
+	FlowControl *flowcontrol.Config
+	// RateLimit is the configuration of the rate limits of the models.
+	RateLimit *ratelimit.Config
+	// Backoff is the configuration of the Retry-After curves of the shed requests.
+	Backoff *backoff.Config
+	// Quota is the configuration of the quotas of the clients.
+	Quota *quota.Config
+	// SchedulingTrace is the configuration of the scheduling traces of the requests.
+	SchedulingTrace *schedulingtrace.Config
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		SessionIDJSONPaths: parseList(DefaultSessionIDJSONPaths),
+		SessionHeader:      DefaultSessionHeader,
+		SLO:                slo.NewDefaultConfig(),
+		AdapterShedding:    adaptershedding.NewDefaultConfig(),
+		TokenEstimation:    tokenestimate.NewDefaultConfig(),
+		FlowControl:        flowcontrol.NewDefaultConfig(),
+		RateLimit:          ratelimit.NewDefaultConfig(),
+		Backoff:            backoff.NewDefaultConfig(),
+		Quota:              quota.NewDefaultConfig(),
+		SchedulingTrace:    schedulingtrace.NewDefaultConfig(),
+	}
+}
+
+// LoadConfigFromEnv loads Director Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("director-config")
+
+	cfg := NewDefaultConfig()
+	cfg.SessionIDJSONPaths = parseList(envutil.GetEnvString(EnvSessionIDJSONPaths, DefaultSessionIDJSONPaths, logger))
+	cfg.SessionHeader = strings.ToLower(envutil.GetEnvString(EnvSessionHeader, DefaultSessionHeader, logger))
+	cfg.SLO = slo.LoadConfigFromEnv()
+	cfg.AdapterShedding = adaptershedding.LoadConfigFromEnv()
+	cfg.TokenEstimation = tokenestimate.LoadConfigFromEnv()
+	cfg.FlowControl = flowcontrol.LoadConfigFromEnv()
+	cfg.RateLimit = ratelimit.LoadConfigFromEnv()
+	cfg.Backoff = backoff.LoadConfigFromEnv()
+	cfg.Quota = quota.LoadConfigFromEnv()
+	cfg.SchedulingTrace = schedulingtrace.LoadConfigFromEnv()
+
+	logger.Info("Director configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
+
+// parseList splits a comma separated list, dropping empty entries.
+func parseList(s string) []string {
+	res := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
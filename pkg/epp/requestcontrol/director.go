@@ -19,16 +19,33 @@ package requestcontrol
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/google/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/adaptershedding"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backoff"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/capability"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/flowcontrol"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/quota"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/ratelimit"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/routepolicy"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
 	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/schedulingtrace"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/slo"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/timeline"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tokenestimate"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
@@ -37,18 +54,111 @@ import (
 type Scheduler interface {
 	Schedule(ctx context.Context, b *schedulingtypes.LLMRequest) (result map[string]*schedulingtypes.Result, err error)
 	OnResponse(ctx context.Context, resp *schedulingtypes.LLMResponse, targetPodName string)
+	OnResponseComplete(ctx context.Context, resp *schedulingtypes.LLMResponse, targetPodName string)
+	OnRequestEnd(ctx context.Context, resp *schedulingtypes.LLMResponse, targetPodName string)
 }
 
 type Director struct {
-	datastore datastore.Datastore
-	scheduler Scheduler
+	datastore        datastore.Datastore
+	scheduler        Scheduler
+	config           *Config
+	sloTracker       *slo.Tracker
+	tokenEstimator   *tokenestimate.Estimator
+	capabilities     *capability.Cache
+	adapterShedder   *adaptershedding.Shedder // nil if adapter shedding is disabled
+	flowController   *flowcontrol.Controller  // nil if flow control is disabled
+	rateLimiter      *ratelimit.Limiter       // nil if no model is rate limited
+	backoff          *backoff.Backoff         // nil unless Retry-After curves are configured
+	quota            *quota.Quota             // nil if no client has a quota
+	observedRequests *observedRequests        // nil unless access-log feedback is enabled
+	prewarmHints     PrewarmHints             // nil unless prewarm hints are enabled
+	routePolicies    *routepolicy.Store       // nil unless route policies are enabled
+	loadReports      bool                     // whether the load reports of the responses are read
 }
 
+// PrewarmHints provides the endpoints that recently joined the pool, which the gateway is hinted
+// to open connections to before requests are routed to them.
+type PrewarmHints interface {
+	HintedEndpoints() []string
+}
+
+// WithPrewarmHints makes the Director hint the endpoints provided by the given PrewarmHints to the
+// gateway along with the target endpoint of every request.
+func (d *Director) WithPrewarmHints(hints PrewarmHints) *Director {
+	d.prewarmHints = hints
+	return d
+}
+
+// WithRoutePolicies makes the Director apply the InferenceRoutePolicy of the route of every
+// request, resolved from the given Store.
+func (d *Director) WithRoutePolicies(policies *routepolicy.Store) *Director {
+	d.routePolicies = policies
+	return d
+}
+
+// WithLoadReports makes the Director ask the model servers for an ORCA load report in every
+// response, and merge the metrics it reports into the metrics of the pods, so they reflect the
+// load of the pods as of their latest responses instead of their latest scrapes.
+func (d *Director) WithLoadReports() *Director {
+	d.loadReports = true
+	return d
+}
+
+// NewDirector returns a new Director with the default configuration.
 func NewDirector(datastore datastore.Datastore, scheduler Scheduler) *Director {
-	return &Director{
-		datastore: datastore,
-		scheduler: scheduler,
+	return NewDirectorWithConfig(datastore, scheduler, NewDefaultConfig())
+}
+
+// NewDirectorWithConfig returns a new Director with the given configuration.
+func NewDirectorWithConfig(datastore datastore.Datastore, scheduler Scheduler, config *Config) *Director {
+	if config.SLO == nil {
+		config.SLO = slo.NewDefaultConfig()
+	}
+	if config.TokenEstimation == nil {
+		config.TokenEstimation = tokenestimate.NewDefaultConfig()
+	}
+	sloTracker, err := slo.NewTracker(config.SLO, datastore)
+	if err != nil {
+		log.Log.Error(err, "Failed to create SLO tracker, using the default SLO tracking config")
+		sloTracker, _ = slo.NewTracker(slo.NewDefaultConfig(), datastore)
+	}
+	d := &Director{
+		datastore:      datastore,
+		scheduler:      scheduler,
+		config:         config,
+		sloTracker:     sloTracker,
+		tokenEstimator: tokenestimate.NewEstimator(config.TokenEstimation),
+		capabilities:   capability.NewCache(),
+	}
+	if config.AdapterShedding != nil && config.AdapterShedding.Enabled() {
+		shedder, err := adaptershedding.NewShedder(config.AdapterShedding, datastore, log.Log)
+		if err != nil {
+			log.Log.Error(err, "Failed to create adapter shedder, adapter shedding is disabled")
+		}
+		d.adapterShedder = shedder
 	}
+	if config.FlowControl != nil && config.FlowControl.Enabled() {
+		detector, err := saturationdetector.NewDetector(config.FlowControl.SaturationDetector, datastore, log.Log)
+		if err != nil {
+			log.Log.Error(err, "Failed to create saturation detector, flow control is disabled")
+		} else {
+			d.flowController = flowcontrol.NewController(config.FlowControl, detector)
+		}
+	}
+	if config.RateLimit != nil && config.RateLimit.Enabled() {
+		d.rateLimiter = ratelimit.NewLimiter(config.RateLimit)
+	}
+	if config.Backoff != nil && config.Backoff.Enabled() {
+		b, err := backoff.NewBackoff(config.Backoff, datastore, log.Log)
+		if err != nil {
+			log.Log.Error(err, "Failed to create backoff, the Retry-After curves are disabled")
+		}
+		d.backoff = b
+	}
+	if config.Quota != nil && config.Quota.Enabled() {
+		d.quota = quota.NewQuota(config.Quota)
+	}
+	return d
 }
 
 // HandleRequest always returns the requestContext even in the error case, as the request context is used in error handling.
@@ -59,7 +169,14 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 	var ok bool
 	requestBodyMap := reqCtx.Request.Body
 	reqCtx.Model, ok = requestBodyMap["model"].(string)
-	if !ok {
+	// An InferenceModel matching the request by its other attributes takes precedence over the model
+	// field of the body, for the clients that can't set the model name.
+	matched := d.datastore.ModelMatch(reqCtx.Request.Headers)
+	if matched != nil {
+		logger.V(logutil.DEBUG).Info("Request matched an InferenceModel", "model", matched.Spec.ModelName, "requestedModel", reqCtx.Model)
+		reqCtx.Model = matched.Spec.ModelName
+		requestBodyMap["model"] = reqCtx.Model
+	} else if !ok {
 		return reqCtx, errutil.Error{Code: errutil.BadRequest, Msg: "model not found in request"}
 	}
 	prompt, err := requtil.ExtractPromptFromRequestBody(requestBodyMap)
@@ -70,47 +187,227 @@ func (d *Director) HandleRequest(ctx context.Context, reqCtx *handlers.RequestCo
 	// NOTE: The nil checking for the modelObject means that we DO allow passthrough currently.
 	// This might be a security risk in the future where adapters not registered in the InferenceModel
 	// are able to be requested by using their distinct name.
-	modelObj := d.datastore.ModelGet(reqCtx.Model)
+	modelObj := matched
+	if modelObj == nil {
+		modelObj = d.datastore.ModelGet(reqCtx.Model)
+	}
 	if modelObj == nil {
 		return reqCtx, errutil.Error{Code: errutil.BadConfiguration, Msg: fmt.Sprintf("error finding a model object in InferenceModel for input %v", reqCtx.Model)}
 	}
+	// A request for an alias is handled as a request for the model name of its InferenceModel.
+	if modelName := modelObj.Spec.ModelName; modelName != reqCtx.Model && !datastore.IsModelNamePattern(modelName) {
+		logger.V(logutil.DEBUG).Info("Rewriting the alias of a model", "model", modelName, "alias", reqCtx.Model)
+		reqCtx.Model = modelName
+		requestBodyMap["model"] = modelName
+	}
+	if evaluation, active := capability.EvaluateLifecycle(modelObj, time.Now()); !active {
+		logger.V(logutil.DEBUG).Info("Rejecting the request for an inactive model", "model", reqCtx.Model, "reason", evaluation.Reason)
+		return reqCtx, capability.InactiveModelError(modelObj, evaluation)
+	}
+	policy := d.routePolicy(reqCtx)
+	if policy != nil {
+		logger.V(logutil.DEBUG).Info("Applying the policy of the route", "route", reqCtx.Route, "policy", policy)
+		if err := applyRoutePolicy(policy, requestBodyMap); err != nil {
+			return reqCtx, err
+		}
+	}
+	criticality := modelObj.Spec.Criticality
+	if criticality == nil && policy != nil {
+		criticality = policy.DefaultCriticality
+	}
 
+	sessionID := requtil.ExtractSessionID(reqCtx.Request.Headers, d.config.SessionHeader, requestBodyMap, d.config.SessionIDJSONPaths)
 	reqCtx.ResolvedTargetModel = reqCtx.Model
 	if len(modelObj.Spec.TargetModels) > 0 {
-		reqCtx.ResolvedTargetModel = RandomWeightedDraw(logger, modelObj, 0)
+		// The requests of a session are sent to the same target model, so a multi-turn conversation
+		// isn't split across the versions of a model.
+		if sessionID != "" {
+			reqCtx.ResolvedTargetModel = SessionWeightedDraw(logger, modelObj, sessionID)
+		} else {
+			reqCtx.ResolvedTargetModel = RandomWeightedDraw(logger, modelObj, 0)
+		}
 		if reqCtx.ResolvedTargetModel == "" {
 			return reqCtx, errutil.Error{Code: errutil.BadConfiguration, Msg: fmt.Sprintf("error getting target model name for model %v", modelObj.Name)}
 		}
 		reqCtx.Request.Body["model"] = reqCtx.ResolvedTargetModel // Update target model in the body.
 	}
+	// Fail fast with the reason when no pod can serve the target model, rather than with a generic
+	// scheduling failure.
+	if evaluation := d.capabilities.EvaluateTargetModel(d.datastore.PodSnapshot(), reqCtx.ResolvedTargetModel); !evaluation.Servable() {
+		return reqCtx, errutil.Error{Code: errutil.NoCapableEndpoints, Msg: fmt.Sprintf("%s: %s", evaluation.Reason, evaluation.Message)}
+	}
 
 	llmReq := &schedulingtypes.LLMRequest{
-		TargetModel: reqCtx.ResolvedTargetModel,
-		RequestId:   reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
-		Critical:    modelObj.Spec.Criticality != nil && *modelObj.Spec.Criticality == v1alpha2.Critical,
-		Prompt:      prompt,
-		Headers:     reqCtx.Request.Headers,
+		TargetModel:    reqCtx.ResolvedTargetModel,
+		RequestId:      reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Critical:       criticality != nil && *criticality == v1alpha2.Critical,
+		Sheddable:      criticality != nil && *criticality == v1alpha2.Sheddable,
+		Prompt:         prompt,
+		Headers:        reqCtx.Request.Headers,
+		SessionID:      sessionID,
+		SLOBurningFast: d.sloTracker.IsBurningFast(reqCtx.Model),
 	}
+	if policy != nil && policy.SchedulingProfile != nil {
+		llmReq.SchedulingProfile = *policy.SchedulingProfile
+	}
+	// The max tokens are read after the route policy capped them.
+	estimate := d.tokenEstimator.Estimate(llmReq.TargetModel, prompt, requtil.ExtractMaxTokensFromRequestBody(requestBodyMap))
+	llmReq.PromptTokens, llmReq.EstimatedOutputTokens = estimate.PromptTokens, estimate.OutputTokens
+	reqCtx.EstimatedPromptTokens, reqCtx.EstimatedOutputTokens = estimate.PromptTokens, estimate.OutputTokens
 	logger.V(logutil.DEBUG).Info("LLM request assembled", "request", llmReq)
+	// The requests over the quotas of their client or the rate limits of their model are rejected
+	// before they wait in a queue.
+	if d.quota != nil {
+		client := d.quota.Client(reqCtx.Request.Headers)
+		lease, err := d.quota.Admit(ctx, client)
+		if err != nil {
+			return reqCtx, err
+		}
+		if lease != "" {
+			reqCtx.QuotaClient, reqCtx.QuotaLease = client, lease
+		}
+		namespace := d.quota.Namespace(reqCtx.Request.Headers, reqCtx.Route)
+		lease, err = d.quota.AdmitNamespace(ctx, namespace)
+		if err != nil {
+			return reqCtx, err
+		}
+		if lease != "" {
+			reqCtx.QuotaNamespace, reqCtx.QuotaNamespaceLease = namespace, lease
+		}
+	}
+	if d.rateLimiter != nil {
+		if err := d.rateLimiter.Admit(modelObj.Spec.ModelName, llmReq.PromptTokens+llmReq.EstimatedOutputTokens); err != nil {
+			return reqCtx, err
+		}
+	}
+	if d.flowController != nil {
+		queued := time.Now()
+		err := d.flowController.Admit(ctx, flowRequest(modelObj, llmReq))
+		reqCtx.QueueTime = time.Since(queued)
+		if err != nil {
+			return reqCtx, d.shed(ctx, llmReq, err)
+		}
+	}
+	if d.adapterShedder != nil {
+		if !d.adapterShedder.Admit(ctx, llmReq.TargetModel, llmReq.Critical) {
+			return reqCtx, d.shed(ctx, llmReq, errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: fmt.Sprintf("requests for adapter %s exceed their share of the saturated pool", llmReq.TargetModel)})
+		}
+	}
+	var trace *schedulingtrace.Trace
+	if d.config.SchedulingTrace != nil && d.config.SchedulingTrace.Enabled {
+		trace = schedulingtrace.New(traceID(llmReq.RequestId), llmReq.TargetModel)
+		ctx = schedulingtrace.NewContext(ctx, trace)
+	}
 	results, err := d.Dispatch(ctx, llmReq)
+	if trace != nil {
+		d.endSchedulingTrace(ctx, reqCtx, trace, err)
+	}
 	if err != nil {
-		return reqCtx, err
+		d.adapterRequestDone(reqCtx)
+		return reqCtx, d.shed(ctx, llmReq, err)
+	}
+	// The tenant label is left out of the metrics, as the number of tenants is unbounded.
+	if len(llmReq.Labels) > 0 {
+		metrics.RecordRequestClass(reqCtx.Model, llmReq.TargetModel, llmReq.Labels[schedulingtypes.LabelWorkloadType],
+			llmReq.Labels[schedulingtypes.LabelSizeClass], llmReq.Labels[schedulingtypes.LabelCriticality])
+		if tl := timeline.FromContext(ctx); tl != nil {
+			tl.Labels = llmReq.Labels
+		}
 	}
 
 	// Insert target endpoint to instruct Envoy to route requests to the specified target pod.
 	// Attach the port number
 	reqCtx, err = d.PostDispatch(ctx, reqCtx, results)
 	if err != nil {
+		d.adapterRequestDone(reqCtx)
 		return reqCtx, err
 	}
 
 	return reqCtx, nil
 }
 
+// flowRequest returns the flow control request of the given request, whose flow is its
+// InferenceModel and whose cost is its estimated number of tokens.
+func flowRequest(modelObj *v1alpha2.InferenceModel, llmReq *schedulingtypes.LLMRequest) flowcontrol.Request {
+	req := flowcontrol.Request{
+		Priority: requestPriority(llmReq),
+		Flow:     modelObj.Name,
+		Cost:     llmReq.PromptTokens + llmReq.EstimatedOutputTokens,
+	}
+	if modelObj.Spec.FairShareWeight != nil {
+		req.Weight = int(*modelObj.Spec.FairShareWeight)
+	}
+	return req
+}
+
+// shed advises the client of the given shed request to retry it after the delay of the Retry-After
+// curve of its priority, if any. The errors other than shedding are returned as is.
+func (d *Director) shed(ctx context.Context, llmReq *schedulingtypes.LLMRequest, err error) error {
+	e, ok := err.(errutil.Error)
+	if d.backoff == nil || !ok || (e.Code != errutil.InferencePoolResourceExhausted && e.Code != errutil.ServiceUnavailable) {
+		return err
+	}
+	if retryAfter, ok := d.backoff.RetryAfter(ctx, requestPriority(llmReq)); ok {
+		e.RetryAfter = retryAfter
+	}
+	return e
+}
+
+// requestPriority returns the flow control priority of the given request. The requests of the
+// models without criticality are standard.
+func requestPriority(llmReq *schedulingtypes.LLMRequest) flowcontrol.Priority {
+	switch {
+	case llmReq.Critical:
+		return flowcontrol.PriorityCritical
+	case llmReq.Sheddable:
+		return flowcontrol.PrioritySheddable
+	default:
+		return flowcontrol.PriorityStandard
+	}
+}
+
+// routePolicy returns the spec of the InferenceRoutePolicy applying to the route of the request, or
+// nil if none applies.
+func (d *Director) routePolicy(reqCtx *handlers.RequestContext) *v1alpha2.InferenceRoutePolicySpec {
+	if d.routePolicies == nil || reqCtx.Route == "" {
+		return nil
+	}
+	route, ok := routepolicy.ParseRoute(reqCtx.Route)
+	if !ok {
+		return nil
+	}
+	return d.routePolicies.Get(route)
+}
+
+// applyRoutePolicy rejects the request if the policy of its route forbids it, and caps the number
+// of tokens it may generate.
+func applyRoutePolicy(policy *v1alpha2.InferenceRoutePolicySpec, requestBodyMap map[string]any) error {
+	if policy.Streaming != nil && *policy.Streaming == v1alpha2.StreamingDisabled {
+		if stream, _ := requestBodyMap["stream"].(bool); stream {
+			return errutil.Error{Code: errutil.BadRequest, Msg: "streamed responses are disabled on the route of the request"}
+		}
+	}
+	if policy.MaxOutputTokens != nil {
+		maxTokens := float64(*policy.MaxOutputTokens)
+		limited := false
+		for _, key := range []string{"max_tokens", "max_completion_tokens"} {
+			if value, ok := requestBodyMap[key].(float64); ok {
+				limited = true
+				requestBodyMap[key] = min(value, maxTokens)
+			}
+		}
+		if !limited {
+			requestBodyMap["max_tokens"] = maxTokens
+		}
+	}
+	return nil
+}
+
 // Dispatch runs one or many scheduling cycles.
 func (d *Director) Dispatch(ctx context.Context, llmReq *schedulingtypes.LLMRequest) (map[string]*schedulingtypes.Result, error) {
 	var err error
 	res, err := d.scheduler.Schedule(ctx, llmReq)
+	timeline.FromContext(ctx).Mark(timeline.EventScheduled)
 	if err != nil {
 		return nil, errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: fmt.Errorf("failed to find target pod: %w", err).Error()}
 	}
@@ -124,22 +421,46 @@ func (d *Director) PostDispatch(ctx context.Context, reqCtx *handlers.RequestCon
 	if len(results) == 0 {
 		return reqCtx, errutil.Error{Code: errutil.Internal, Msg: "results must be greater than zero"}
 	}
-	var targetPod *backend.Pod
+	var target schedulingtypes.Pod
+	var fallbackPods []schedulingtypes.Pod
 	// TODO should handle multi cycle results, this should be pluggable logic
 	for _, result := range results {
-		targetPod = result.TargetPod.GetPod()
+		target = result.TargetPod
+		fallbackPods = result.FallbackPods
 	}
+	targetPod := target.GetPod()
 
 	pool, err := d.datastore.PoolGet()
 	if err != nil {
 		return reqCtx, err
 	}
 
-	endpoint := targetPod.Address + ":" + strconv.Itoa(int(pool.Spec.TargetPortNumber))
+	endpoint := targetPod.Address + ":" + strconv.Itoa(int(targetPod.TargetPort(pool.Spec.TargetPortNumber)))
+	fallbackEndpoints := make([]string, 0, len(fallbackPods))
+	for _, pod := range fallbackPods {
+		fallbackEndpoints = append(fallbackEndpoints, pod.GetPod().Address+":"+strconv.Itoa(int(pod.GetPod().TargetPort(pool.Spec.TargetPortNumber))))
+	}
 	logger.V(logutil.DEFAULT).Info("Request handled", "model", reqCtx.Model, "targetModel", reqCtx.ResolvedTargetModel, "endpoint", targetPod)
+	if len(fallbackEndpoints) > 0 {
+		logger.V(logutil.VERBOSE).Info("Fallback endpoints", "endpoints", fallbackEndpoints)
+	}
 
 	reqCtx.TargetPod = targetPod.NamespacedName.String()
 	reqCtx.TargetEndpoint = endpoint
+	// The request is counted in flight for its target pod until it ends.
+	requestsInFlightDone(reqCtx)
+	if inFlight := target.GetRequestsInFlight(); inFlight != nil {
+		inFlight.Inc()
+		reqCtx.TargetPodRequestsInFlight = inFlight
+		updateOutstandingTokens(reqCtx)
+	}
+	if d.loadReports {
+		reqCtx.Request.Headers[backendmetrics.LoadReportFormatHeader] = backendmetrics.LoadReportFormatText
+	}
+	reqCtx.FallbackEndpoints = fallbackEndpoints
+	if d.prewarmHints != nil {
+		reqCtx.PrewarmEndpoints = d.prewarmHints.HintedEndpoints()
+	}
 
 	return reqCtx, nil
 }
@@ -152,14 +473,158 @@ func (d *Director) HandleResponse(ctx context.Context, reqCtx *handlers.RequestC
 		Headers:   reqCtx.Response.Headers,
 	}
 	logger.V(logutil.DEBUG).Info("LLM response assembled", "response", llmResp)
+	if d.observedRequests != nil && llmResp.RequestId != "" {
+		d.observedRequests.add(llmResp.RequestId)
+	}
 
 	d.scheduler.OnResponse(ctx, llmResp, reqCtx.TargetPod)
+	if d.loadReports && reqCtx.TargetPod != "" {
+		d.applyLoadReport(ctx, reqCtx)
+	}
 
 	return reqCtx, nil
 }
 
+// applyLoadReport merges the metrics of the load report of the response into the metrics of the pod
+// that served it, if the response has one.
+func (d *Director) applyLoadReport(ctx context.Context, reqCtx *handlers.RequestContext) {
+	pushed, err := backendmetrics.ParseLoadReport(reqCtx.Response.Headers)
+	if err != nil {
+		log.FromContext(ctx).V(logutil.VERBOSE).Error(err, "Failed to parse the load report of the response", "pod", reqCtx.TargetPod)
+		return
+	}
+	if pushed == nil {
+		return
+	}
+	for _, pod := range d.datastore.PodList(func(pm backendmetrics.PodMetrics) bool {
+		return pm.GetPod().NamespacedName.String() == reqCtx.TargetPod
+	}) {
+		pod.PushMetrics(pushed)
+	}
+}
+
+// HandleResponseProgress is invoked after every chunk of a streamed response.
+func (d *Director) HandleResponseProgress(_ context.Context, reqCtx *handlers.RequestContext) {
+	updateOutstandingTokens(reqCtx)
+}
+
+// HandleResponseComplete is invoked once the full response was received from the model server.
+func (d *Director) HandleResponseComplete(ctx context.Context, reqCtx *handlers.RequestContext) {
+	if d.quota != nil {
+		d.quota.Charge(ctx, d.quota.Client(reqCtx.Request.Headers), reqCtx.Usage.TotalTokens)
+		d.quota.ChargeNamespace(ctx, d.quota.Namespace(reqCtx.Request.Headers, reqCtx.Route), reqCtx.Usage.TotalTokens)
+	}
+	if reqCtx.TargetPod != "" {
+		d.scheduler.OnResponseComplete(ctx, completedResponse(reqCtx), reqCtx.TargetPod)
+	}
+	if reqCtx.ResponseStatusCode == errutil.ModelServerError || reqCtx.ResponseFirstChunkTimestamp.IsZero() {
+		return
+	}
+	d.tokenEstimator.Observe(reqCtx.ResolvedTargetModel, reqCtx.Usage.CompletionTokens)
+	d.sloTracker.Record(ctx, reqCtx.Model, reqCtx.ResponseFirstChunkTimestamp.Sub(reqCtx.RequestReceivedTimestamp))
+}
+
+// completedResponse assembles the response passed to the plugins once it completed.
+func completedResponse(reqCtx *handlers.RequestContext) *schedulingtypes.LLMResponse {
+	resp := &schedulingtypes.LLMResponse{
+		RequestId:        reqCtx.Request.Headers[requtil.RequestIdHeaderKey],
+		Headers:          reqCtx.Response.Headers,
+		IsStreaming:      reqCtx.IsResponseStreaming(),
+		EndOfStream:      true,
+		PromptTokens:     reqCtx.Usage.PromptTokens,
+		CompletionTokens: reqCtx.Usage.CompletionTokens,
+		FinishReason:     reqCtx.FinishReason,
+		Latency:          reqCtx.ResponseCompleteTimestamp.Sub(reqCtx.RequestReceivedTimestamp),
+		Failed:           reqCtx.ResponseStatusCode == errutil.ModelServerError,
+		Text:             reqCtx.ResponseText,
+		TextCaptured:     reqCtx.ResponseTextCaptured,
+	}
+	if !reqCtx.ResponseFirstChunkTimestamp.IsZero() {
+		resp.TimeToFirstToken = reqCtx.ResponseFirstChunkTimestamp.Sub(reqCtx.RequestReceivedTimestamp)
+	}
+	return resp
+}
+
+// HandleRequestEnd is invoked once the processing of the request ended, whatever its outcome.
+func (d *Director) HandleRequestEnd(ctx context.Context, reqCtx *handlers.RequestContext) {
+	// The target pod is only set once the request was admitted and scheduled.
+	if reqCtx.TargetPod != "" {
+		d.adapterRequestDone(reqCtx)
+		d.scheduler.OnRequestEnd(ctx, &schedulingtypes.LLMResponse{RequestId: reqCtx.Request.Headers[requtil.RequestIdHeaderKey]}, reqCtx.TargetPod)
+	}
+	requestsInFlightDone(reqCtx)
+	d.quotaDone(ctx, reqCtx)
+}
+
+// requestsInFlightDone uncounts the request from the requests in flight of its target pod, if it's
+// counted.
+func requestsInFlightDone(reqCtx *handlers.RequestContext) {
+	if reqCtx.TargetPodRequestsInFlight != nil {
+		reqCtx.TargetPodRequestsInFlight.Dec()
+		reqCtx.TargetPodRequestsInFlight.AddTokens(-reqCtx.TargetPodOutstandingTokens)
+		reqCtx.TargetPodRequestsInFlight = nil
+		reqCtx.TargetPodOutstandingTokens = 0
+	}
+}
+
+// updateOutstandingTokens updates the tokens of the request counted as outstanding for its target
+// pod: its prompt tokens until the first chunk of the response, which ends the prefill, and its
+// estimated output tokens that weren't generated yet.
+func updateOutstandingTokens(reqCtx *handlers.RequestContext) {
+	if reqCtx.TargetPodRequestsInFlight == nil {
+		return
+	}
+	outstanding := max(reqCtx.EstimatedOutputTokens-reqCtx.GeneratedTokens(), 0)
+	if reqCtx.ResponseFirstChunkTimestamp.IsZero() {
+		outstanding += reqCtx.EstimatedPromptTokens
+	}
+	reqCtx.TargetPodRequestsInFlight.AddTokens(outstanding - reqCtx.TargetPodOutstandingTokens)
+	reqCtx.TargetPodOutstandingTokens = outstanding
+}
+
+// quotaDone uncounts the request from the requests in flight of its client and namespace, if it
+// was counted.
+func (d *Director) quotaDone(ctx context.Context, reqCtx *handlers.RequestContext) {
+	if reqCtx.QuotaLease != "" {
+		d.quota.Done(ctx, reqCtx.QuotaClient, reqCtx.QuotaLease)
+		reqCtx.QuotaClient, reqCtx.QuotaLease = "", ""
+	}
+	if reqCtx.QuotaNamespaceLease != "" {
+		d.quota.NamespaceDone(ctx, reqCtx.QuotaNamespace, reqCtx.QuotaNamespaceLease)
+		reqCtx.QuotaNamespace, reqCtx.QuotaNamespaceLease = "", ""
+	}
+}
+
+// endSchedulingTrace logs the given scheduling trace of the request, and stamps its summary into
+// the response headers if enabled.
+func (d *Director) endSchedulingTrace(ctx context.Context, reqCtx *handlers.RequestContext, trace *schedulingtrace.Trace, err error) {
+	if err != nil {
+		trace.Fail(err)
+	}
+	log.FromContext(ctx).V(logutil.DEBUG).Info("Scheduling trace", "trace", trace)
+	if d.config.SchedulingTrace.Header {
+		reqCtx.SchedulingTrace = trace.Summary()
+	}
+}
+
+// traceID returns the ID of the scheduling trace of the request of the given ID, which is the ID of
+// the request if it has one.
+func traceID(requestID string) string {
+	if requestID != "" {
+		return requestID
+	}
+	return uuid.NewString()
+}
+
+// adapterRequestDone ends the tracking of an admitted request by the adapter shedder.
+func (d *Director) adapterRequestDone(reqCtx *handlers.RequestContext) {
+	if d.adapterShedder != nil {
+		d.adapterShedder.Done(reqCtx.ResolvedTargetModel)
+	}
+}
+
 func (d *Director) GetRandomPod() *backend.Pod {
-	pods := d.datastore.PodGetAll()
+	pods := d.datastore.PodList(func(pm backendmetrics.PodMetrics) bool { return !pm.GetPod().Cordoned })
 	if len(pods) == 0 {
 		return nil
 	}
@@ -176,10 +641,27 @@ func RandomWeightedDraw(logger logr.Logger, model *v1alpha2.InferenceModel, seed
 		source = rand.NewSource(seed)
 	}
 	r := rand.New(source)
+	return weightedDraw(logger, model, r.Int31n)
+}
+
+// SessionWeightedDraw draws the target model of the given InferenceModel for the given session.
+// The same session always draws the same target model while the target models and their weights
+// don't change, and the sessions are split across the target models according to their weights.
+func SessionWeightedDraw(logger logr.Logger, model *v1alpha2.InferenceModel, session string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(model.Spec.ModelName))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(session))
+	sum := h.Sum64()
+	return weightedDraw(logger, model, func(n int32) int32 { return int32(sum % uint64(n)) })
+}
 
+// weightedDraw draws the target model of the given InferenceModel, using the given function to
+// draw a number in [0, n).
+func weightedDraw(logger logr.Logger, model *v1alpha2.InferenceModel, draw func(n int32) int32) string {
 	// all the weight values are nil, then we should return random model name
 	if model.Spec.TargetModels[0].Weight == nil {
-		index := r.Int31n(int32(len(model.Spec.TargetModels)))
+		index := draw(int32(len(model.Spec.TargetModels)))
 		return model.Spec.TargetModels[index].Name
 	}
 
@@ -188,7 +670,7 @@ func RandomWeightedDraw(logger logr.Logger, model *v1alpha2.InferenceModel, seed
 		weights += *model.Weight
 	}
 	logger.V(logutil.TRACE).Info("Weights for model computed", "model", model.Name, "weights", weights)
-	randomVal := r.Int31n(weights)
+	randomVal := draw(weights)
 	// TODO: optimize this without using loop
 	for _, model := range model.Spec.TargetModels {
 		if randomVal < *model.Weight {
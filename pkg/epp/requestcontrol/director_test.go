@@ -18,6 +18,7 @@ package requestcontrol
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -27,17 +28,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/accesslog"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/routepolicy"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
+	schedulingtypes "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tokenestimate"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
 	testutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/testing"
 )
 
@@ -53,10 +62,17 @@ func TestHandleRequest(t *testing.T) {
 	model2 := testutil.MakeInferenceModel("model2").
 		CreationTimestamp(metav1.Unix(1000, 0)).
 		ModelName(modelWithTarget).ObjRef()
+	matchedModel := "food-review-matched"
+	model3 := testutil.MakeInferenceModel("model3").
+		CreationTimestamp(metav1.Unix(1000, 0)).
+		ModelName(matchedModel).
+		Aliases("gpt-4o-mini").
+		Match(v1alpha2.ModelMatch{Headers: []v1alpha2.HeaderMatch{{Name: "x-model-alias", Value: "food"}}}).ObjRef()
 	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
 	ds := datastore.NewDatastore(t.Context(), pmf)
 	ds.ModelSetIfOlder(model1)
 	ds.ModelSetIfOlder(model2)
+	ds.ModelSetIfOlder(model3)
 
 	pool := &v1alpha2.InferencePool{
 		Spec: v1alpha2.InferencePoolSpec{
@@ -80,6 +96,7 @@ func TestHandleRequest(t *testing.T) {
 	tests := []struct {
 		name         string
 		reqBodyMap   map[string]interface{}
+		headers      map[string]string
 		wantErrCode  string
 		wantReqCtx   *handlers.RequestContext
 		wantRespBody map[string]interface{}
@@ -180,6 +197,58 @@ func TestHandleRequest(t *testing.T) {
 				"prompt": "test prompt",
 			},
 		},
+		{
+			name: "request matched by header",
+			reqBodyMap: map[string]interface{}{
+				"model":  "unknown",
+				"prompt": "test prompt",
+			},
+			headers: map[string]string{"x-model-alias": "food"},
+			wantReqCtx: &handlers.RequestContext{
+				Model:               matchedModel,
+				ResolvedTargetModel: matchedModel,
+				TargetPod:           "/pod1",
+				TargetEndpoint:      "address-1:8000",
+			},
+			wantRespBody: map[string]interface{}{
+				"model":  matchedModel,
+				"prompt": "test prompt",
+			},
+		},
+		{
+			name: "request for an alias",
+			reqBodyMap: map[string]interface{}{
+				"model":  "gpt-4o-mini",
+				"prompt": "test prompt",
+			},
+			wantReqCtx: &handlers.RequestContext{
+				Model:               matchedModel,
+				ResolvedTargetModel: matchedModel,
+				TargetPod:           "/pod1",
+				TargetEndpoint:      "address-1:8000",
+			},
+			wantRespBody: map[string]interface{}{
+				"model":  matchedModel,
+				"prompt": "test prompt",
+			},
+		},
+		{
+			name: "request without model matched by header",
+			reqBodyMap: map[string]interface{}{
+				"prompt": "test prompt",
+			},
+			headers: map[string]string{"x-model-alias": "food"},
+			wantReqCtx: &handlers.RequestContext{
+				Model:               matchedModel,
+				ResolvedTargetModel: matchedModel,
+				TargetPod:           "/pod1",
+				TargetEndpoint:      "address-1:8000",
+			},
+			wantRespBody: map[string]interface{}{
+				"model":  matchedModel,
+				"prompt": "test prompt",
+			},
+		},
 		{
 			name:        "no model defined, expect err",
 			wantErrCode: errutil.BadRequest,
@@ -222,7 +291,8 @@ func TestHandleRequest(t *testing.T) {
 			server := NewDirector(ds, scheduling.NewScheduler(ds))
 			reqCtx := &handlers.RequestContext{
 				Request: &handlers.Request{
-					Body: test.reqBodyMap,
+					Body:    test.reqBodyMap,
+					Headers: test.headers,
 				},
 			}
 			reqCtx, err := server.HandleRequest(ctx, reqCtx)
@@ -259,6 +329,255 @@ func TestHandleRequest(t *testing.T) {
 	}
 }
 
+func TestHandleRequestNoCapableEndpoints(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	ds.ModelSetIfOlder(testutil.MakeInferenceModel("model1").ModelName("food-review").ObjRef())
+
+	server := NewDirector(ds, scheduling.NewScheduler(ds))
+	reqCtx := &handlers.RequestContext{
+		Request: &handlers.Request{
+			Body: map[string]interface{}{
+				"model":  "food-review",
+				"prompt": "critical prompt",
+			},
+		},
+	}
+	_, err := server.HandleRequest(ctx, reqCtx)
+	if errutil.CanonicalCode(err) != errutil.NoCapableEndpoints {
+		t.Fatalf("HandleRequest returned error '%v', want code %s", err, errutil.NoCapableEndpoints)
+	}
+	if !strings.Contains(err.Error(), string(v1alpha2.ModelReasonNoEndpoints)) {
+		t.Errorf("HandleRequest returned error '%v', which does not contain the reason %s", err, v1alpha2.ModelReasonNoEndpoints)
+	}
+}
+
+func TestHandleRequestInactiveModel(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	ds.ModelSetIfOlder(testutil.MakeInferenceModel("model1").ModelName("food-review").Lifecycle(v1alpha2.ModelLifecycle{Paused: true}).ObjRef())
+
+	server := NewDirector(ds, scheduling.NewScheduler(ds))
+	reqCtx := &handlers.RequestContext{
+		Request: &handlers.Request{
+			Body: map[string]interface{}{
+				"model":  "food-review",
+				"prompt": "critical prompt",
+			},
+		},
+	}
+	_, err := server.HandleRequest(ctx, reqCtx)
+	if errutil.CanonicalCode(err) != errutil.ModelInactive {
+		t.Fatalf("HandleRequest returned error '%v', want code %s", err, errutil.ModelInactive)
+	}
+}
+
+// recordingScheduler schedules every request to the same pod and records the last request,
+// completed response and ended request.
+type recordingScheduler struct {
+	pod       *backend.Pod
+	request   *schedulingtypes.LLMRequest
+	completed *schedulingtypes.LLMResponse
+	ended     *schedulingtypes.LLMResponse
+}
+
+func (s *recordingScheduler) Schedule(_ context.Context, req *schedulingtypes.LLMRequest) (map[string]*schedulingtypes.Result, error) {
+	s.request = req
+	return map[string]*schedulingtypes.Result{"default": {TargetPod: &schedulingtypes.PodMetrics{Pod: s.pod}}}, nil
+}
+
+func (s *recordingScheduler) OnResponse(context.Context, *schedulingtypes.LLMResponse, string) {}
+
+func (s *recordingScheduler) OnResponseComplete(_ context.Context, resp *schedulingtypes.LLMResponse, _ string) {
+	s.completed = resp
+}
+
+func (s *recordingScheduler) OnRequestEnd(_ context.Context, resp *schedulingtypes.LLMResponse, _ string) {
+	s.ended = resp
+}
+
+func TestHandleResponseComplete(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	scheduler := &recordingScheduler{}
+	director := NewDirector(datastore.NewDatastore(t.Context(), pmf), scheduler)
+
+	received := time.Now()
+	reqCtx := &handlers.RequestContext{
+		TargetPod:                   "default/pod1",
+		RequestReceivedTimestamp:    received,
+		ResponseFirstChunkTimestamp: received.Add(100 * time.Millisecond),
+		ResponseCompleteTimestamp:   received.Add(2 * time.Second),
+		Usage:                       handlers.Usage{PromptTokens: 12, CompletionTokens: 34, TotalTokens: 46},
+		FinishReason:                "length",
+		Request:                     &handlers.Request{Headers: map[string]string{requtil.RequestIdHeaderKey: "req-1"}},
+		Response:                    &handlers.Response{Headers: map[string]string{}},
+	}
+	director.HandleResponseComplete(ctx, reqCtx)
+
+	want := &schedulingtypes.LLMResponse{
+		RequestId:        "req-1",
+		Headers:          map[string]string{},
+		EndOfStream:      true,
+		PromptTokens:     12,
+		CompletionTokens: 34,
+		FinishReason:     "length",
+		Latency:          2 * time.Second,
+		TimeToFirstToken: 100 * time.Millisecond,
+	}
+	if diff := cmp.Diff(want, scheduler.completed); diff != "" {
+		t.Errorf("Unexpected completed response (-want +got): %s", diff)
+	}
+
+	director.HandleRequestEnd(ctx, reqCtx)
+	if diff := cmp.Diff(&schedulingtypes.LLMResponse{RequestId: "req-1"}, scheduler.ended); diff != "" {
+		t.Errorf("Unexpected ended request (-want +got): %s", diff)
+	}
+}
+
+func TestHandleRequestRoutePolicy(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	ds.ModelSetIfOlder(testutil.MakeInferenceModel("model1").ModelName("food-review").ObjRef())
+	ds.ModelSetIfOlder(testutil.MakeInferenceModel("model2").ModelName("sheddable").Criticality(v1alpha2.Sheddable).ObjRef())
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := ds.PoolSet(ctx, fakeClient, &v1alpha2.InferencePool{Spec: v1alpha2.InferencePoolSpec{TargetPortNumber: 8000}}); err != nil {
+		t.Fatalf("Error while setting inference pool: %v", err)
+	}
+	ds.PodUpdateOrAddIfNotExist(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{PodIP: "address-1"}})
+
+	policies := routepolicy.NewStore()
+	policies.Set(&v1alpha2.InferenceRoutePolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "batch"},
+		Spec: v1alpha2.InferenceRoutePolicySpec{
+			TargetRef:          v1alpha2.RouteTargetReference{Name: "batch"},
+			DefaultCriticality: ptr.To(v1alpha2.Critical),
+			SchedulingProfile:  ptr.To("batch"),
+			Streaming:          ptr.To(v1alpha2.StreamingDisabled),
+			MaxOutputTokens:    ptr.To[int32](100),
+		},
+	})
+
+	tests := []struct {
+		name            string
+		route           string
+		body            map[string]interface{}
+		wantErrCode     string
+		wantCritical    bool
+		wantProfile     string
+		wantMaxTokens   any
+		wantMaxComplete any
+	}{
+		{
+			name:  "route without a policy",
+			route: "ns/chat",
+			body:  map[string]interface{}{"model": "food-review", "prompt": "test prompt", "stream": true},
+		},
+		{
+			name:          "policy applied",
+			route:         "ns/batch",
+			body:          map[string]interface{}{"model": "food-review", "prompt": "test prompt"},
+			wantCritical:  true,
+			wantProfile:   "batch",
+			wantMaxTokens: float64(100),
+		},
+		{
+			name:            "max tokens lowered",
+			route:           "ns/batch/rule",
+			body:            map[string]interface{}{"model": "food-review", "prompt": "test prompt", "max_tokens": float64(50), "max_completion_tokens": float64(500)},
+			wantCritical:    true,
+			wantProfile:     "batch",
+			wantMaxTokens:   float64(50),
+			wantMaxComplete: float64(100),
+		},
+		{
+			name:          "criticality of the model takes precedence",
+			route:         "ns/batch",
+			body:          map[string]interface{}{"model": "sheddable", "prompt": "test prompt"},
+			wantProfile:   "batch",
+			wantMaxTokens: float64(100),
+		},
+		{
+			name:        "streaming disabled",
+			route:       "ns/batch",
+			body:        map[string]interface{}{"model": "food-review", "prompt": "test prompt", "stream": true},
+			wantErrCode: errutil.BadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheduler := &recordingScheduler{pod: &backend.Pod{Address: "address-1"}}
+			director := NewDirector(ds, scheduler).WithRoutePolicies(policies)
+			reqCtx := &handlers.RequestContext{Route: test.route, Request: &handlers.Request{Body: test.body}}
+			_, err := director.HandleRequest(ctx, reqCtx)
+			if test.wantErrCode != "" {
+				if errutil.CanonicalCode(err) != test.wantErrCode {
+					t.Fatalf("HandleRequest returned error '%v', want code %s", err, test.wantErrCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleRequest returned unexpected error: %v", err)
+			}
+			if scheduler.request.Critical != test.wantCritical || scheduler.request.SchedulingProfile != test.wantProfile {
+				t.Errorf("Scheduled request %s, want Critical: %t and SchedulingProfile: %s", scheduler.request, test.wantCritical, test.wantProfile)
+			}
+			if got := test.body["max_tokens"]; got != test.wantMaxTokens {
+				t.Errorf("Unexpected max_tokens %v, want %v", got, test.wantMaxTokens)
+			}
+			if got := test.body["max_completion_tokens"]; got != test.wantMaxComplete {
+				t.Errorf("Unexpected max_completion_tokens %v, want %v", got, test.wantMaxComplete)
+			}
+		})
+	}
+}
+
+func TestHandleRequestTokenEstimate(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	ds.ModelSetIfOlder(testutil.MakeInferenceModel("model1").ModelName("food-review").ObjRef())
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := ds.PoolSet(ctx, fakeClient, &v1alpha2.InferencePool{Spec: v1alpha2.InferencePoolSpec{TargetPortNumber: 8000}}); err != nil {
+		t.Fatalf("Error while setting inference pool: %v", err)
+	}
+	ds.PodUpdateOrAddIfNotExist(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}, Status: corev1.PodStatus{PodIP: "address-1"}})
+
+	config := NewDefaultConfig()
+	config.TokenEstimation = &tokenestimate.Config{CharsPerToken: 4, OutputTokens: 200, OutputTokensWindow: 10}
+	scheduler := &recordingScheduler{pod: &backend.Pod{Address: "address-1"}}
+	director := NewDirectorWithConfig(ds, scheduler, config)
+	handle := func(body map[string]interface{}) *handlers.RequestContext {
+		reqCtx := &handlers.RequestContext{Request: &handlers.Request{Body: body}, Response: &handlers.Response{}}
+		if _, err := director.HandleRequest(ctx, reqCtx); err != nil {
+			t.Fatalf("HandleRequest returned unexpected error: %v", err)
+		}
+		return reqCtx
+	}
+
+	// The estimated output tokens are bounded by the max tokens of the request.
+	reqCtx := handle(map[string]interface{}{"model": "food-review", "prompt": "test prompt", "max_tokens": float64(50)})
+	if scheduler.request.PromptTokens != 3 || scheduler.request.EstimatedOutputTokens != 50 {
+		t.Errorf("Scheduled request %s, want PromptTokens: 3 and EstimatedOutputTokens: 50", scheduler.request)
+	}
+
+	// The output tokens of the completed responses are learned.
+	reqCtx.ResponseFirstChunkTimestamp = time.Now()
+	reqCtx.Usage.CompletionTokens = 120
+	director.HandleResponseComplete(ctx, reqCtx)
+	handle(map[string]interface{}{"model": "food-review", "prompt": "test prompt"})
+	if scheduler.request.EstimatedOutputTokens != 120 {
+		t.Errorf("Scheduled request %s, want EstimatedOutputTokens: 120", scheduler.request)
+	}
+}
+
 func TestRandomWeightedDraw(t *testing.T) {
 	logger := logutil.NewTestLogger()
 	tests := []struct {
@@ -362,6 +681,117 @@ func TestRandomWeightedDraw(t *testing.T) {
 	}
 }
 
+func TestSessionWeightedDraw(t *testing.T) {
+	logger := logutil.NewTestLogger()
+	model := &v1alpha2.InferenceModel{
+		Spec: v1alpha2.InferenceModelSpec{
+			ModelName: "food-review",
+			TargetModels: []v1alpha2.TargetModel{
+				{Name: "canary", Weight: pointer(10)},
+				{Name: "v1", Weight: pointer(90)},
+			},
+		},
+	}
+
+	counts := map[string]int{}
+	for i := range 10000 {
+		session := "session-" + strconv.Itoa(i)
+		target := SessionWeightedDraw(logger, model, session)
+		for range 3 {
+			if got := SessionWeightedDraw(logger, model, session); got != target {
+				t.Fatalf("Session %s drew %s, then %s", session, target, got)
+			}
+		}
+		counts[target]++
+	}
+	// The sessions are split according to the weights.
+	if counts["canary"] < 800 || counts["canary"] > 1200 {
+		t.Errorf("Sessions drawing the canary: %d, want about 1000", counts["canary"])
+	}
+	if counts["canary"]+counts["v1"] != 10000 {
+		t.Errorf("Sessions drawing unknown target models: %v", counts)
+	}
+}
+
+func TestRequestsInFlight(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Second)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	pool := &v1alpha2.InferencePool{Spec: v1alpha2.InferencePoolSpec{TargetPortNumber: 8000}}
+	if err := ds.PoolSet(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(), pool); err != nil {
+		t.Fatalf("Failed to set pool: %v", err)
+	}
+	director := NewDirector(ds, &recordingScheduler{})
+
+	pod1 := &schedulingtypes.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, InFlight: &backendmetrics.RequestsInFlight{}}
+	pod2 := &schedulingtypes.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, InFlight: &backendmetrics.RequestsInFlight{}}
+	reqCtx := &handlers.RequestContext{
+		Request:               &handlers.Request{Headers: map[string]string{}},
+		EstimatedPromptTokens: 100,
+		EstimatedOutputTokens: 50,
+	}
+	if _, err := director.PostDispatch(ctx, reqCtx, map[string]*schedulingtypes.Result{"default": {TargetPod: pod1}}); err != nil {
+		t.Fatalf("PostDispatch failed: %v", err)
+	}
+	if pod1.InFlight.Count() != 1 || pod1.InFlight.OutstandingTokens() != 150 {
+		t.Errorf("Expected 1 request and 150 tokens in flight for pod1, got %d and %d", pod1.InFlight.Count(), pod1.InFlight.OutstandingTokens())
+	}
+
+	// A request scheduled again is only counted for its new pod.
+	if _, err := director.PostDispatch(ctx, reqCtx, map[string]*schedulingtypes.Result{"default": {TargetPod: pod2}}); err != nil {
+		t.Fatalf("PostDispatch failed: %v", err)
+	}
+	if pod1.InFlight.Count() != 0 || pod2.InFlight.Count() != 1 {
+		t.Errorf("Expected 0 and 1 requests in flight for pod1 and pod2, got %d and %d", pod1.InFlight.Count(), pod2.InFlight.Count())
+	}
+	if pod1.InFlight.OutstandingTokens() != 0 || pod2.InFlight.OutstandingTokens() != 150 {
+		t.Errorf("Expected 0 and 150 outstanding tokens for pod1 and pod2, got %d and %d", pod1.InFlight.OutstandingTokens(), pod2.InFlight.OutstandingTokens())
+	}
+
+	// The prompt tokens are processed once the response started, and the output tokens as they're
+	// generated.
+	reqCtx.ResponseFirstChunkTimestamp = time.Now()
+	reqCtx.Usage.CompletionTokens = 20
+	director.HandleResponseProgress(ctx, reqCtx)
+	if pod2.InFlight.OutstandingTokens() != 30 {
+		t.Errorf("Expected 30 outstanding tokens for pod2, got %d", pod2.InFlight.OutstandingTokens())
+	}
+
+	director.HandleRequestEnd(ctx, reqCtx)
+	if pod2.InFlight.Count() != 0 || pod2.InFlight.OutstandingTokens() != 0 {
+		t.Errorf("Expected no request nor token in flight for pod2 once the request ended, got %d and %d", pod2.InFlight.Count(), pod2.InFlight.OutstandingTokens())
+	}
+}
+
+func TestHandleResponseLoadReport(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(context.Background())
+	pmf := backendmetrics.NewPodMetricsFactory(&backendmetrics.FakePodMetricsClient{}, time.Hour)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	ds.PodUpdateOrAddIfNotExist(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+	director := NewDirector(ds, &recordingScheduler{}).WithLoadReports()
+
+	reqCtx := &handlers.RequestContext{
+		TargetPod: "default/pod1",
+		Request:   &handlers.Request{Headers: map[string]string{}},
+		Response: &handlers.Response{Headers: map[string]string{
+			backendmetrics.LoadReportHeader: "TEXT named_metrics.num_requests_waiting=3, named_metrics.kv_cache_usage_perc=0.6",
+		}},
+	}
+	if _, err := director.HandleResponse(ctx, reqCtx); err != nil {
+		t.Fatalf("HandleResponse failed: %v", err)
+	}
+
+	pods := ds.PodGetAll()
+	if len(pods) != 1 {
+		t.Fatalf("Expected 1 pod, got %d", len(pods))
+	}
+	if got := pods[0].GetMetrics(); got.WaitingQueueSize != 3 || got.KVCacheUsagePercent != 0.6 {
+		t.Errorf("Expected the reported queue size 3 and KV cache usage 0.6, got %d and %v", got.WaitingQueueSize, got.KVCacheUsagePercent)
+	}
+}
+
 func TestGetRandomPod(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -411,6 +841,49 @@ func TestGetRandomPod(t *testing.T) {
 	}
 }
 
+func TestRecordAccessLog(t *testing.T) {
+	ctx := logutil.NewTestLoggerIntoContext(t.Context())
+	pmf := metrics.NewPodMetricsFactory(&metrics.FakePodMetricsClient{}, time.Millisecond)
+	ds := datastore.NewDatastore(t.Context(), pmf)
+	d := NewDirectorWithConfig(ds, nil, NewDefaultConfig()).WithAccessLogFeedback()
+
+	d.observedRequests.add("observed")
+
+	tests := []struct {
+		name   string
+		record *accesslog.Record
+		want   bool
+	}{
+		{
+			name:   "observed through ext-proc",
+			record: &accesslog.Record{RequestID: "observed", Model: "m", ResponseCode: 200},
+			want:   false,
+		},
+		{
+			name:   "failed open with an error",
+			record: &accesslog.Record{RequestID: "failed", Model: "m", ResponseCode: 503, DurationMillis: 10},
+			want:   true,
+		},
+		{
+			name:   "failed open with a success",
+			record: &accesslog.Record{RequestID: "succeeded", Model: "m", TargetModel: "m-v2", ResponseCode: 200, BytesSent: 100, DurationMillis: 200, ResponseDurationMillis: 50},
+			want:   true,
+		},
+		{
+			name:   "without model",
+			record: &accesslog.Record{RequestID: "unknown", ResponseCode: 200},
+			want:   false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := d.RecordAccessLog(ctx, test.record); got != test.want {
+				t.Errorf("RecordAccessLog() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 func pointer(v int32) *int32 {
 	return &v
 }
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestlookup
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultCapacity is zero, i.e. the requests are not retained by default.
+	DefaultCapacity = 0
+)
+
+// Environment variable names for the request lookup configuration
+const (
+	EnvCapacity = "REQUEST_LOOKUP_CAPACITY"
+)
+
+// Config holds the configuration of the Store.
+type Config struct {
+	// Capacity is the number of the most recent requests whose records are retained. Zero disables
+	// the lookup.
+	Capacity int
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Capacity: DefaultCapacity,
+	}
+}
+
+// Enabled returns true if the records of the requests are retained.
+func (c *Config) Enabled() bool {
+	return c.Capacity > 0
+}
+
+// LoadConfigFromEnv loads the request lookup Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("request-lookup-config")
+
+	cfg := &Config{}
+
+	cfg.Capacity = envutil.GetEnvInt(EnvCapacity, DefaultCapacity, logger)
+	if cfg.Capacity < 0 {
+		cfg.Capacity = DefaultCapacity
+	}
+
+	logger.Info("Request lookup configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requestlookup retains the records of the recent requests, so that the operators can look
+// up why a given request was slow or shed by its ID: how long it was queued, the scheduling decision
+// of each profile with the scores that led to it, the outcome of the request and its response.
+package requestlookup
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Path is the path of the debug endpoint serving the records, e.g. GET /debug/requests?id=<id>.
+// It's served along with the metrics, so it requires the same authentication and authorization.
+const Path = "/debug/requests"
+
+// Record is what is retained of a request.
+type Record struct {
+	RequestID   string    `json:"requestId"`
+	Model       string    `json:"model,omitempty"`
+	TargetModel string    `json:"targetModel,omitempty"`
+	Arrival     time.Time `json:"arrival"`
+	// QueueTime is the time the request waited in the flow control queue.
+	QueueTime time.Duration `json:"queueTime,omitempty"`
+	TargetPod string        `json:"targetPod,omitempty"`
+	// Decisions are the scheduling decisions of the profiles run for the request, keyed by profile
+	// name. Only recorded for the profiles configured with a DecisionPlugin.
+	Decisions map[string]*Decision `json:"decisions,omitempty"`
+	// StatusCode is the error code of the request, empty if it succeeded, and Error the message of
+	// the error the endpoint picker rejected it with, e.g. why it was shed.
+	StatusCode string `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Response is nil if the request wasn't sent to a pod.
+	Response *Response `json:"response,omitempty"`
+}
+
+// Decision is the scheduling decision of a profile.
+type Decision struct {
+	TargetPod string `json:"targetPod,omitempty"`
+	// Scores are the scores of the pods given by each scorer, keyed by scorer name then pod name,
+	// before the weights of the scorers are applied.
+	Scores  map[string]map[string]float64 `json:"scores,omitempty"`
+	Weights map[string]int                `json:"weights,omitempty"`
+}
+
+// Response holds the statistics of the response to a request.
+type Response struct {
+	// Latency is the time from the arrival of the request to the completion of its response, zero
+	// if the response didn't complete.
+	Latency          time.Duration `json:"latency,omitempty"`
+	TimeToFirstToken time.Duration `json:"timeToFirstToken,omitempty"`
+	PromptTokens     int           `json:"promptTokens,omitempty"`
+	CompletionTokens int           `json:"completionTokens,omitempty"`
+	FinishReason     string        `json:"finishReason,omitempty"`
+	Size             int           `json:"size,omitempty"`
+	// CancelledStage is the stage the request reached before its client disconnected, empty if it
+	// didn't.
+	CancelledStage string `json:"cancelledStage,omitempty"`
+}
+
+// Store retains the records of the most recent requests, and serves them by request ID.
+type Store struct {
+	capacity int
+
+	mu      sync.Mutex
+	records map[string]*Record
+	order   []string // ring buffer of the IDs of the records, in the order they were added
+	next    int      // index of the next ID to write in order
+	// pending holds the decisions of the requests in flight, until their record is added.
+	pending map[string]map[string]*Decision
+}
+
+// NewStore returns a new Store with the given configuration.
+func NewStore(config *Config) *Store {
+	capacity := max(config.Capacity, 1)
+	return &Store{
+		capacity: capacity,
+		records:  make(map[string]*Record, capacity),
+		order:    make([]string, capacity),
+		pending:  map[string]map[string]*Decision{},
+	}
+}
+
+// RecordDecision records the scheduling decision of the given profile for the request in flight with
+// the given ID, which is added to its record once the request ended. The decisions are dropped if
+// as many requests as the capacity of the store are pending already, as the records of the requests
+// that never end would otherwise leak.
+func (s *Store) RecordDecision(requestID, profile string, decision *Decision) {
+	if s == nil || requestID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	decisions, ok := s.pending[requestID]
+	if !ok {
+		if len(s.pending) >= s.capacity {
+			return
+		}
+		decisions = map[string]*Decision{}
+		s.pending[requestID] = decisions
+	}
+	decisions[profile] = decision
+}
+
+// Add retains the record of an ended request, along with its pending decisions, and evicts the
+// oldest record if the store is full. The record must not be modified afterwards.
+func (s *Store) Add(record *Record) {
+	if s == nil || record.RequestID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if decisions, ok := s.pending[record.RequestID]; ok {
+		record.Decisions = decisions
+		delete(s.pending, record.RequestID)
+	}
+	if _, ok := s.records[record.RequestID]; !ok {
+		// The record of a request retried with the same ID replaces the previous one in place.
+		if evicted := s.order[s.next]; evicted != "" {
+			delete(s.records, evicted)
+		}
+		s.order[s.next] = record.RequestID
+		s.next = (s.next + 1) % s.capacity
+	}
+	s.records[record.RequestID] = record
+}
+
+// Get returns the record of the request with the given ID, if retained.
+func (s *Store) Get(requestID string) (*Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[requestID]
+	return record, ok
+}
+
+// ServeHTTP serves the record of the request whose ID is given by the "id" query parameter as JSON.
+func (s *Store) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	requestID := req.URL.Query().Get("id")
+	if requestID == "" {
+		http.Error(w, "missing 'id' query parameter", http.StatusBadRequest)
+		return
+	}
+	record, ok := s.Get(requestID)
+	if !ok {
+		http.Error(w, "request not found, it may have been evicted", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(record)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestlookup
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(&Config{Capacity: 2})
+
+	store.RecordDecision("req-1", "default", &Decision{TargetPod: "default/pod1"})
+	store.Add(&Record{RequestID: "req-1", TargetPod: "default/pod1"})
+	store.Add(&Record{RequestID: "req-2", StatusCode: "InferencePoolResourceExhausted"})
+	record, ok := store.Get("req-1")
+	if !ok {
+		t.Fatal("Record of req-1 not found")
+	}
+	if diff := cmp.Diff(map[string]*Decision{"default": {TargetPod: "default/pod1"}}, record.Decisions); diff != "" {
+		t.Errorf("Unexpected decisions (-want +got): %s", diff)
+	}
+
+	// The oldest record is evicted once the store is full.
+	store.Add(&Record{RequestID: "req-3"})
+	if _, ok := store.Get("req-1"); ok {
+		t.Error("Record of req-1 retained beyond the capacity of the store")
+	}
+	for _, id := range []string{"req-2", "req-3"} {
+		if _, ok := store.Get(id); !ok {
+			t.Errorf("Record of %s not found", id)
+		}
+	}
+
+	// A retried request replaces its record without evicting another one.
+	store.Add(&Record{RequestID: "req-3", TargetPod: "default/pod2"})
+	if record, _ := store.Get("req-3"); record.TargetPod != "default/pod2" {
+		t.Errorf("Got target pod %q for the retried request, want default/pod2", record.TargetPod)
+	}
+	if _, ok := store.Get("req-2"); !ok {
+		t.Error("Record of req-2 evicted by a retried request")
+	}
+
+	// The pending decisions are bounded by the capacity of the store.
+	store.RecordDecision("req-4", "default", &Decision{})
+	store.RecordDecision("req-5", "default", &Decision{})
+	store.RecordDecision("req-6", "default", &Decision{})
+	if len(store.pending) != 2 {
+		t.Errorf("Got %d pending requests, want 2", len(store.pending))
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	store := NewStore(&Config{Capacity: 10})
+	store.Add(&Record{RequestID: "req-1", TargetPod: "default/pod1", QueueTime: time.Second})
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantRecord *Record
+	}{
+		{name: "found", method: http.MethodGet, target: Path + "?id=req-1", wantStatus: http.StatusOK,
+			wantRecord: &Record{RequestID: "req-1", TargetPod: "default/pod1", QueueTime: time.Second}},
+		{name: "not found", method: http.MethodGet, target: Path + "?id=req-2", wantStatus: http.StatusNotFound},
+		{name: "missing id", method: http.MethodGet, target: Path, wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodPost, target: Path + "?id=req-1", wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			store.ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+			if w.Code != test.wantStatus {
+				t.Fatalf("Got status %d, want %d", w.Code, test.wantStatus)
+			}
+			if test.wantRecord == nil {
+				return
+			}
+			var record Record
+			if err := json.Unmarshal(w.Body.Bytes(), &record); err != nil {
+				t.Fatalf("Error decoding the record: %v", err)
+			}
+			if diff := cmp.Diff(test.wantRecord, &record); diff != "" {
+				t.Errorf("Unexpected record (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+type testScorer struct{}
+
+func (s *testScorer) Name() string { return "test-scorer" }
+
+func (s *testScorer) Score(*types.SchedulingContext, []types.Pod) map[types.Pod]float64 { return nil }
+
+func TestDecisionPlugin(t *testing.T) {
+	store := NewStore(&Config{Capacity: 10})
+	scorer := framework.NewWeightedScorer(&testScorer{}, 2)
+	plugin := NewDecisionPlugin(store, scorer)
+
+	pod := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}}
+	ctx := types.NewSchedulingContext(t.Context(), &types.LLMRequest{RequestId: "req-1"}, nil, []types.Pod{pod})
+	ctx.ProfileName = "decode"
+	framework.ScorerScoresStateKey(scorer.Name()).Write(ctx.CycleState, framework.ScorerScores{pod: 0.5})
+	plugin.PostCycle(ctx, &types.Result{TargetPod: pod})
+
+	store.Add(&Record{RequestID: "req-1"})
+	record, _ := store.Get("req-1")
+	want := map[string]*Decision{"decode": {
+		TargetPod: "default/pod1",
+		Scores:    map[string]map[string]float64{"test-scorer": {"default/pod1": 0.5}},
+		Weights:   map[string]int{"test-scorer": 2},
+	}}
+	if diff := cmp.Diff(want, record.Decisions); diff != "" {
+		t.Errorf("Unexpected decisions (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestlookup
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.PostCycle = &DecisionPlugin{}
+
+// NewDecisionPlugin initializes a new DecisionPlugin and returns its pointer.
+// The scores of the given scorers are included in the recorded decisions.
+func NewDecisionPlugin(store *Store, scorers ...*framework.WeightedScorer) *DecisionPlugin {
+	return &DecisionPlugin{
+		store:   store,
+		scorers: scorers,
+	}
+}
+
+// DecisionPlugin records the scheduling decisions in the Store, along with the scores that led to
+// them, so they can be looked up by request ID.
+type DecisionPlugin struct {
+	store   *Store
+	scorers []*framework.WeightedScorer
+}
+
+// Name returns the name of the plugin.
+func (p *DecisionPlugin) Name() string {
+	return "request-lookup"
+}
+
+// PostCycle records the decision of the scheduling cycle of the request.
+func (p *DecisionPlugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	if ctx.Req == nil || ctx.Req.RequestId == "" {
+		return
+	}
+	decision := &Decision{}
+	if res != nil && res.TargetPod != nil {
+		decision.TargetPod = res.TargetPod.GetPod().NamespacedName.String()
+	}
+	decision.Scores, decision.Weights = framework.CaptureScores(ctx.CycleState, p.scorers)
+	p.store.RecordDecision(ctx.Req.RequestId, ctx.ProfileName, decision)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package routepolicy keeps the InferenceRoutePolicies attached to the routes of the pool, and
+// resolves the policy applying to the route of a request.
+package routepolicy
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+)
+
+const (
+	routeGroup = "gateway.networking.k8s.io"
+	routeKind  = "HTTPRoute"
+)
+
+// Route identifies an HTTPRoute, and the rule of the route a request matched if known.
+type Route struct {
+	Namespace   string
+	Name        string
+	SectionName string
+}
+
+// ParseRoute parses a route given as "namespace/name" or "namespace/name/rule". It returns false if
+// the route is malformed.
+func ParseRoute(s string) (Route, bool) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Route{}, false
+	}
+	route := Route{Namespace: parts[0], Name: parts[1]}
+	if len(parts) == 3 {
+		route.SectionName = parts[2]
+		if route.SectionName == "" {
+			return Route{}, false
+		}
+	}
+	if route.Namespace == "" || route.Name == "" {
+		return Route{}, false
+	}
+	return route, true
+}
+
+func (r Route) String() string {
+	if r.SectionName == "" {
+		return r.Namespace + "/" + r.Name
+	}
+	return r.Namespace + "/" + r.Name + "/" + r.SectionName
+}
+
+// Store holds the InferenceRoutePolicies. It's safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	policies map[types.NamespacedName]*v1alpha2.InferenceRoutePolicy
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{policies: make(map[types.NamespacedName]*v1alpha2.InferenceRoutePolicy)}
+}
+
+// Set adds or replaces the given policy.
+func (s *Store) Set(policy *v1alpha2.InferenceRoutePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies[types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}] = policy
+}
+
+// Delete removes the policy of the given name, if any.
+func (s *Store) Delete(name types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.policies, name)
+}
+
+// Get returns the spec of the policy applying to the given route, or nil if none applies. A policy
+// targeting the rule of the route takes precedence over a policy targeting the whole route, and
+// the oldest policy wins among the policies with the same target. A nil Store has no policies.
+func (s *Store) Get(route Route) *v1alpha2.InferenceRoutePolicySpec {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var best *v1alpha2.InferenceRoutePolicy
+	bestOnRule := false
+	for _, policy := range s.policies {
+		onRule, ok := targets(policy, route)
+		if !ok {
+			continue
+		}
+		if best == nil || (onRule && !bestOnRule) || (onRule == bestOnRule && older(policy, best)) {
+			best, bestOnRule = policy, onRule
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &best.Spec
+}
+
+// targets returns whether the policy applies to the route, and if so whether it targets the rule
+// of the route rather than the whole route.
+func targets(policy *v1alpha2.InferenceRoutePolicy, route Route) (onRule bool, ok bool) {
+	ref := policy.Spec.TargetRef
+	if policy.Namespace != route.Namespace || string(ref.Name) != route.Name {
+		return false, false
+	}
+	if (ref.Group != "" && ref.Group != routeGroup) || (ref.Kind != "" && ref.Kind != routeKind) {
+		return false, false
+	}
+	if ref.SectionName == nil {
+		return false, true
+	}
+	return true, string(*ref.SectionName) == route.SectionName
+}
+
+// older returns whether policy a was created before policy b, breaking the ties by name so that
+// the same policy always wins.
+func older(a, b *v1alpha2.InferenceRoutePolicy) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
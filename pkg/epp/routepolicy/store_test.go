@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routepolicy
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+)
+
+func makePolicy(namespace, name string, created int64, route string, rule string) *v1alpha2.InferenceRoutePolicy {
+	policy := &v1alpha2.InferenceRoutePolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.Unix(created, 0),
+		},
+		Spec: v1alpha2.InferenceRoutePolicySpec{
+			TargetRef: v1alpha2.RouteTargetReference{
+				Group: routeGroup,
+				Kind:  routeKind,
+				Name:  v1alpha2.ObjectName(route),
+			},
+			SchedulingProfile: ptr.To(name),
+		},
+	}
+	if rule != "" {
+		policy.Spec.TargetRef.SectionName = ptr.To(v1alpha2.SectionName(rule))
+	}
+	return policy
+}
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Route
+		wantOk bool
+	}{
+		{in: "ns/route", want: Route{Namespace: "ns", Name: "route"}, wantOk: true},
+		{in: "ns/route/rule", want: Route{Namespace: "ns", Name: "route", SectionName: "rule"}, wantOk: true},
+		{in: "route"},
+		{in: "ns/"},
+		{in: "/route"},
+		{in: "ns/route/"},
+		{in: "ns/route/rule/extra"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, ok := ParseRoute(test.in)
+			if ok != test.wantOk || got != test.want {
+				t.Errorf("ParseRoute(%q) = %v, %t, want %v, %t", test.in, got, ok, test.want, test.wantOk)
+			}
+			if ok && got.String() != test.in {
+				t.Errorf("String() = %q, want %q", got.String(), test.in)
+			}
+		})
+	}
+}
+
+func TestStoreGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []*v1alpha2.InferenceRoutePolicy
+		route    Route
+		want     string // the scheduling profile of the expected policy, which is its name
+	}{
+		{
+			name:  "no policy",
+			route: Route{Namespace: "ns", Name: "route"},
+		},
+		{
+			name:     "policy of the route",
+			policies: []*v1alpha2.InferenceRoutePolicy{makePolicy("ns", "p1", 1, "route", "")},
+			route:    Route{Namespace: "ns", Name: "route", SectionName: "rule"},
+			want:     "p1",
+		},
+		{
+			name:     "policy of another namespace",
+			policies: []*v1alpha2.InferenceRoutePolicy{makePolicy("other", "p1", 1, "route", "")},
+			route:    Route{Namespace: "ns", Name: "route"},
+		},
+		{
+			name:     "policy of another rule",
+			policies: []*v1alpha2.InferenceRoutePolicy{makePolicy("ns", "p1", 1, "route", "other")},
+			route:    Route{Namespace: "ns", Name: "route", SectionName: "rule"},
+		},
+		{
+			name: "policy of the rule takes precedence over the older policy of the route",
+			policies: []*v1alpha2.InferenceRoutePolicy{
+				makePolicy("ns", "p1", 1, "route", ""),
+				makePolicy("ns", "p2", 2, "route", "rule"),
+			},
+			route: Route{Namespace: "ns", Name: "route", SectionName: "rule"},
+			want:  "p2",
+		},
+		{
+			name: "oldest policy of the same target wins",
+			policies: []*v1alpha2.InferenceRoutePolicy{
+				makePolicy("ns", "p1", 2, "route", ""),
+				makePolicy("ns", "p2", 1, "route", ""),
+				makePolicy("ns", "p3", 3, "route", ""),
+			},
+			route: Route{Namespace: "ns", Name: "route"},
+			want:  "p2",
+		},
+		{
+			name: "name breaks the ties",
+			policies: []*v1alpha2.InferenceRoutePolicy{
+				makePolicy("ns", "p2", 1, "route", ""),
+				makePolicy("ns", "p1", 1, "route", ""),
+			},
+			route: Route{Namespace: "ns", Name: "route"},
+			want:  "p1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewStore()
+			for _, policy := range test.policies {
+				store.Set(policy)
+			}
+			got := ""
+			if spec := store.Get(test.route); spec != nil {
+				got = *spec.SchedulingProfile
+			}
+			if got != test.want {
+				t.Errorf("Get(%v) = policy %q, want %q", test.route, got, test.want)
+			}
+		})
+	}
+}
+
+func TestStoreDelete(t *testing.T) {
+	store := NewStore()
+	store.Set(makePolicy("ns", "p1", 1, "route", ""))
+	store.Set(makePolicy("ns", "p2", 2, "route", ""))
+	store.Delete(types.NamespacedName{Namespace: "ns", Name: "p1"})
+
+	spec := store.Get(Route{Namespace: "ns", Name: "route"})
+	if spec == nil || *spec.SchedulingProfile != "p2" {
+		t.Errorf("Get() = %v, want policy p2", spec)
+	}
+	if (*Store)(nil).Get(Route{Namespace: "ns", Name: "route"}) != nil {
+		t.Error("Get() on a nil Store returned a policy")
+	}
+}
@@ -124,45 +124,72 @@ func (d *Detector) IsSaturated(ctx context.Context) bool {
 	}
 
 	for _, podMetric := range allPodsMetrics {
-		metrics := podMetric.GetMetrics()
-		podNn := "unknown-pod"
-		if podMetric.GetPod() != nil {
-			podNn = podMetric.GetPod().NamespacedName.String()
+		if d.hasGoodCapacity(logger, podMetric) {
+			return false // Found at least one pod with good capacity, so system is NOT saturated.
 		}
+	}
 
-		if metrics == nil {
-			logger.V(logutil.TRACE).Info("Pod has nil metrics, skipping for saturation check",
-				"pod", podNn)
-			continue
-		}
+	logger.V(logutil.VERBOSE).Info("No pods found with good capacity; system is considered SATURATED.")
+	return true
+}
 
-		// Check for metric staleness
-		if time.Since(metrics.UpdateTime) > d.config.MetricsStalenessThreshold {
-			logger.V(logutil.TRACE).Info("Pod metrics are stale, considered as not having good capacity",
-				"pod", podNn, "updateTime", metrics.UpdateTime, "stalenessThreshold", d.config.MetricsStalenessThreshold)
-			continue
-		}
+// Saturation returns the fraction [0, 1] of pods that don't have "good capacity", as defined by
+// IsSaturated. Unlike IsSaturated, it grows gradually with the load, which makes it usable as
+// an autoscaling signal.
+// If no pods are found in the datastore, the saturation is 1.
+func (d *Detector) Saturation(ctx context.Context) float64 {
+	logger := log.FromContext(ctx).WithName(loggerName)
+	allPodsMetrics := d.datastore.PodGetAll()
+	if len(allPodsMetrics) == 0 {
+		return 1
+	}
 
-		// Check queue depth
-		if metrics.WaitingQueueSize > d.config.QueueDepthThreshold {
-			logger.V(logutil.TRACE).Info("Pod WaitingQueueSize is above threshold, considered as not having good capacity",
-				"pod", podNn, "waitingQueueSize", metrics.WaitingQueueSize, "threshold", d.config.QueueDepthThreshold)
-			continue // WaitingQueueSize is above threshold, considered saturated.
+	saturatedPods := 0
+	for _, podMetric := range allPodsMetrics {
+		if !d.hasGoodCapacity(logger, podMetric) {
+			saturatedPods++
 		}
+	}
+	return float64(saturatedPods) / float64(len(allPodsMetrics))
+}
 
-		// Check KV cache utilization
-		if metrics.KVCacheUsagePercent > d.config.KVCacheUtilThreshold {
-			logger.V(logutil.TRACE).Info("Pod KVCacheUsagePercent is above threshold, considered as not having good capacity",
-				"pod", podNn, "kvCacheUsagePercent", metrics.KVCacheUsagePercent, "threshold", d.config.KVCacheUtilThreshold)
-			continue // KVCacheUsagePercent is above threshold, considered saturated.
-		}
+// hasGoodCapacity checks if the given pod has fresh metrics below the queue depth and KV cache
+// utilization thresholds.
+func (d *Detector) hasGoodCapacity(logger logr.Logger, podMetric backendmetrics.PodMetrics) bool {
+	metrics := podMetric.GetMetrics()
+	podNn := "unknown-pod"
+	if podMetric.GetPod() != nil {
+		podNn = podMetric.GetPod().NamespacedName.String()
+	}
+
+	if metrics == nil {
+		logger.V(logutil.TRACE).Info("Pod has nil metrics, skipping for saturation check",
+			"pod", podNn)
+		return false
+	}
 
-		logger.V(logutil.TRACE).Info("Found pod with good capacity", "pod", podNn, "waitingQueue", metrics.WaitingQueueSize,
-			"queueThreshold", d.config.QueueDepthThreshold, "kvCacheUtil", metrics.KVCacheUsagePercent, "kvCacheThreshold", d.config.KVCacheUtilThreshold)
+	// Check for metric staleness
+	if time.Since(metrics.UpdateTime) > d.config.MetricsStalenessThreshold {
+		logger.V(logutil.TRACE).Info("Pod metrics are stale, considered as not having good capacity",
+			"pod", podNn, "updateTime", metrics.UpdateTime, "stalenessThreshold", d.config.MetricsStalenessThreshold)
+		return false
+	}
 
-		return false // Found at least one pod with good capacity, so system is NOT saturated.
+	// Check queue depth
+	if metrics.WaitingQueueSize > d.config.QueueDepthThreshold {
+		logger.V(logutil.TRACE).Info("Pod WaitingQueueSize is above threshold, considered as not having good capacity",
+			"pod", podNn, "waitingQueueSize", metrics.WaitingQueueSize, "threshold", d.config.QueueDepthThreshold)
+		return false // WaitingQueueSize is above threshold, considered saturated.
 	}
 
-	logger.V(logutil.VERBOSE).Info("No pods found with good capacity; system is considered SATURATED.")
+	// Check KV cache utilization
+	if metrics.KVCacheUsagePercent > d.config.KVCacheUtilThreshold {
+		logger.V(logutil.TRACE).Info("Pod KVCacheUsagePercent is above threshold, considered as not having good capacity",
+			"pod", podNn, "kvCacheUsagePercent", metrics.KVCacheUsagePercent, "threshold", d.config.KVCacheUtilThreshold)
+		return false // KVCacheUsagePercent is above threshold, considered saturated.
+	}
+
+	logger.V(logutil.TRACE).Info("Found pod with good capacity", "pod", podNn, "waitingQueue", metrics.WaitingQueueSize,
+		"queueThreshold", d.config.QueueDepthThreshold, "kvCacheUtil", metrics.KVCacheUsagePercent, "kvCacheThreshold", d.config.KVCacheUtilThreshold)
 	return true
 }
@@ -341,3 +341,55 @@ func TestDetector_IsSaturated(t *testing.T) {
 		})
 	}
 }
+
+func TestDetector_Saturation(t *testing.T) {
+	baseTime := time.Now()
+	config := &Config{
+		QueueDepthThreshold:       5,
+		KVCacheUtilThreshold:      0.90,
+		MetricsStalenessThreshold: time.Minute,
+	}
+
+	tests := []struct {
+		name               string
+		pods               []*backendmetrics.FakePodMetrics
+		expectedSaturation float64
+	}{
+		{
+			name:               "No pods in datastore",
+			pods:               []*backendmetrics.FakePodMetrics{},
+			expectedSaturation: 1,
+		},
+		{
+			name: "All pods with good capacity",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 1, KVCacheUsagePercent: 0.1}),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 2, KVCacheUsagePercent: 0.2}),
+			},
+			expectedSaturation: 0,
+		},
+		{
+			name: "Some pods saturated",
+			pods: []*backendmetrics.FakePodMetrics{
+				newMockPodMetrics("pod1", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 1, KVCacheUsagePercent: 0.1}),
+				newMockPodMetrics("pod2", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 10, KVCacheUsagePercent: 0.1}),
+				newMockPodMetrics("pod3", &backendmetrics.MetricsState{UpdateTime: baseTime, WaitingQueueSize: 1, KVCacheUsagePercent: 0.95}),
+				newMockPodMetrics("pod4", nil),
+			},
+			expectedSaturation: 0.75,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector, err := NewDetector(config, &mockDatastore{pods: test.pods}, logr.Discard())
+			if err != nil {
+				t.Fatalf("NewDetector() failed: %v", err)
+			}
+
+			if got := detector.Saturation(context.Background()); got != test.expectedSaturation {
+				t.Errorf("Saturation() = %v, want %v", got, test.expectedSaturation)
+			}
+		})
+	}
+}
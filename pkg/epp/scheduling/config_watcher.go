@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// DefaultConfigFileCheckInterval is the default interval between two checks of the scheduler
+// config file.
+const DefaultConfigFileCheckInterval = 5 * time.Second
+
+// ConfigLoader builds a SchedulerConfig from the content of a config file.
+type ConfigLoader func(data []byte) (*SchedulerConfig, error)
+
+// NewConfigFileWatcher initializes a new ConfigFileWatcher and returns its pointer.
+// data is the content of the file the current config of the scheduler was built from.
+func NewConfigFileWatcher(path string, data []byte, interval time.Duration, scheduler *Scheduler, load ConfigLoader) *ConfigFileWatcher {
+	return &ConfigFileWatcher{
+		path:      path,
+		data:      data,
+		interval:  interval,
+		scheduler: scheduler,
+		load:      load,
+	}
+}
+
+// ConfigFileWatcher reloads the scheduler config when its file changes, without restarting EPP.
+// The file is polled rather than watched with inotify, since a ConfigMap volume updates its files
+// by swapping symlinks.
+// A config that fails to load is reported and ignored, i.e. the scheduler keeps its current config.
+// Note the plugins are rebuilt on every reload, so the state of stateful plugins (e.g. the prefix
+// cache indexer) starts over.
+type ConfigFileWatcher struct {
+	path      string
+	data      []byte
+	interval  time.Duration
+	scheduler *Scheduler
+	load      ConfigLoader
+}
+
+// Start checks the config file periodically until the context is done.
+func (w *ConfigFileWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false, since every EPP replica schedules requests.
+func (w *ConfigFileWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Check reloads the scheduler config if the content of the config file changed.
+func (w *ConfigFileWatcher) Check(ctx context.Context) {
+	logger := log.FromContext(ctx).WithValues("path", w.path)
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		logger.V(logutil.DEFAULT).Error(err, "Failed to read scheduler config file")
+		return
+	}
+	if bytes.Equal(data, w.data) {
+		return
+	}
+	w.data = data
+
+	config, err := w.load(data)
+	if err != nil {
+		logger.V(logutil.DEFAULT).Error(err, "Failed to reload scheduler config, keeping the current config")
+		metrics.RecordSchedulerConfigReload(false)
+		return
+	}
+	w.scheduler.UpdateConfig(config)
+	logger.V(logutil.DEFAULT).Info("Scheduler config reloaded")
+	metrics.RecordSchedulerConfigReload(true)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFileWatcher(t *testing.T) {
+	writeConfig := func(path string, profileNames ...string) []byte {
+		data := "profilePicker: all-profiles\nprofiles:\n"
+		for _, name := range profileNames {
+			data += "- name: " + name + "\n  plugins:\n  - name: queue\n    weight: 1\n  - name: max_score\n"
+		}
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		return []byte(data)
+	}
+	load := func(data []byte) (*SchedulerConfig, error) {
+		pluginsConfig, err := LoadPluginsConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		return NewSchedulerConfigFromPlugins(pluginsConfig, nil)
+	}
+	profileNames := func(s *Scheduler) []string {
+		names := []string{}
+		for name := range s.profiles.Load().profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := writeConfig(path, "default")
+	config, err := load(data)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{}, config)
+	watcher := NewConfigFileWatcher(path, data, time.Second, scheduler, load)
+
+	// The file didn't change.
+	previous := scheduler.profiles.Load()
+	watcher.Check(t.Context())
+	assert.Same(t, previous, scheduler.profiles.Load())
+
+	// The file changed.
+	writeConfig(path, "decode", "prefill")
+	watcher.Check(t.Context())
+	assert.Equal(t, []string{"decode", "prefill"}, profileNames(scheduler))
+
+	// The file changed to an invalid config, the current config is kept.
+	if err := os.WriteFile(path, []byte("profiles: []\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	watcher.Check(t.Context())
+	assert.Equal(t, []string{"decode", "prefill"}, profileNames(scheduler))
+
+	// The file was removed, the current config is kept.
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove config file: %v", err)
+	}
+	watcher.Check(t.Context())
+	assert.Equal(t, []string{"decode", "prefill"}, profileNames(scheduler))
+}
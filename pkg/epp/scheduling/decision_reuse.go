@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	DefaultDecisionReuseFreshness   = time.Second
+	DefaultDecisionReuseHistorySize = 64
+)
+
+// DecisionReuseConfig configures the reuse of recent scheduling decisions (stale-while-revalidate).
+// When the rate of scheduling cycles for a model exceeds MaxCyclesPerSecond, the excess requests
+// are not scheduled by running the profiles. Instead, one of the recent decisions for the model
+// is replayed, so the distribution of the recent picks is preserved. Full cycles keep running at
+// MaxCyclesPerSecond, which keeps the recent decisions fresh.
+// Note that replayed decisions don't invoke the PostCycle plugins.
+type DecisionReuseConfig struct {
+	// MaxCyclesPerSecond is the rate of full scheduling cycles per model above which decisions are
+	// reused.
+	MaxCyclesPerSecond int
+	// Freshness is the maximum age of a decision that can be reused.
+	Freshness time.Duration
+	// HistorySize is the number of recent decisions kept per model.
+	HistorySize int
+}
+
+// decisionCache keeps the recent scheduling decisions per model.
+type decisionCache struct {
+	config DecisionReuseConfig
+
+	mu     sync.Mutex
+	models map[string]*modelDecisions
+	now    func() time.Time
+	rand   *framework.Rand
+}
+
+type modelDecisions struct {
+	windowStart time.Time
+	cycles      int
+	history     []decision // ring buffer
+	next        int
+}
+
+type decision struct {
+	at      time.Time
+	results map[string]*types.Result
+}
+
+func newDecisionCache(config DecisionReuseConfig, env framework.Environment) *decisionCache {
+	if config.Freshness <= 0 {
+		config.Freshness = DefaultDecisionReuseFreshness
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = DefaultDecisionReuseHistorySize
+	}
+	return &decisionCache{
+		config: config,
+		models: make(map[string]*modelDecisions),
+		now:    env.Clock.Now,
+		rand:   env.Rand,
+	}
+}
+
+// allowCycle returns true if a full scheduling cycle can run for the model without exceeding the
+// configured rate, and counts it.
+func (c *decisionCache) allowCycle(model string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	decisions := c.get(model)
+	now := c.now()
+	if now.Sub(decisions.windowStart) >= time.Second {
+		decisions.windowStart = now
+		decisions.cycles = 0
+	}
+	if decisions.cycles >= c.config.MaxCyclesPerSecond {
+		return false
+	}
+	decisions.cycles++
+	return true
+}
+
+// replay returns a random fresh decision for the model whose target pods are all still available,
+// or nil if there is none.
+func (c *decisionCache) replay(model string, available map[k8stypes.NamespacedName]bool) map[string]*types.Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	decisions, ok := c.models[model]
+	if !ok {
+		return nil
+	}
+	now := c.now()
+	candidates := []map[string]*types.Result{}
+	for _, d := range decisions.history {
+		if d.results != nil && now.Sub(d.at) <= c.config.Freshness && targetsAvailable(d.results, available) {
+			candidates = append(candidates, d.results)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	picked := candidates[c.rand.Intn(len(candidates))]
+	results := make(map[string]*types.Result, len(picked))
+	for name, result := range picked {
+		results[name] = result
+	}
+	return results
+}
+
+// record adds a decision for the model to its history.
+func (c *decisionCache) record(model string, results map[string]*types.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	decisions := c.get(model)
+	if len(decisions.history) < c.config.HistorySize {
+		decisions.history = append(decisions.history, decision{})
+	}
+	decisions.history[decisions.next] = decision{at: c.now(), results: results}
+	decisions.next = (decisions.next + 1) % c.config.HistorySize
+}
+
+// get returns the decisions of the model, creating them if needed. Must be called with the lock held.
+func (c *decisionCache) get(model string) *modelDecisions {
+	decisions, ok := c.models[model]
+	if !ok {
+		decisions = &modelDecisions{}
+		c.models[model] = decisions
+	}
+	return decisions
+}
+
+func targetsAvailable(results map[string]*types.Result, available map[k8stypes.NamespacedName]bool) bool {
+	for _, result := range results {
+		if result == nil || result.TargetPod == nil || !available[result.TargetPod.GetPod().NamespacedName] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestDecisionCache(t *testing.T) {
+	clock := testingclock.NewFakePassiveClock(time.Now())
+	env := framework.Environment{Clock: clock, Rand: framework.NewRand(1)}
+	cache := newDecisionCache(DecisionReuseConfig{MaxCyclesPerSecond: 2, Freshness: time.Second, HistorySize: 2}, env)
+
+	pod1Name := k8stypes.NamespacedName{Name: "pod1"}
+	pod2Name := k8stypes.NamespacedName{Name: "pod2"}
+	result := func(name k8stypes.NamespacedName) map[string]*types.Result {
+		return map[string]*types.Result{"default": {TargetPod: &types.PodMetrics{Pod: &backend.Pod{NamespacedName: name}, MetricsState: &backendmetrics.MetricsState{}}}}
+	}
+	available := map[k8stypes.NamespacedName]bool{pod1Name: true, pod2Name: true}
+
+	// Nothing to replay yet.
+	assert.Nil(t, cache.replay("model", available))
+
+	// Cycles are allowed up to the configured rate.
+	assert.True(t, cache.allowCycle("model"))
+	cache.record("model", result(pod1Name))
+	assert.True(t, cache.allowCycle("model"))
+	cache.record("model", result(pod1Name))
+	assert.False(t, cache.allowCycle("model"))
+	assert.True(t, cache.allowCycle("other-model"))
+
+	// Recent decisions are replayed, only if their target pods are still available.
+	replayed := cache.replay("model", available)
+	assert.Equal(t, pod1Name, replayed["default"].TargetPod.GetPod().NamespacedName)
+	assert.Nil(t, cache.replay("model", map[k8stypes.NamespacedName]bool{pod2Name: true}))
+	assert.Nil(t, cache.replay("other-model", available))
+
+	// The history is bounded.
+	cache.record("model", result(pod2Name))
+	cache.record("model", result(pod2Name))
+	replayed = cache.replay("model", available)
+	assert.Equal(t, pod2Name, replayed["default"].TargetPod.GetPod().NamespacedName)
+
+	// The rate window resets, and stale decisions are not replayed.
+	clock.SetTime(clock.Now().Add(2 * time.Second))
+	assert.True(t, cache.allowCycle("model"))
+	assert.Nil(t, cache.replay("model", available))
+}
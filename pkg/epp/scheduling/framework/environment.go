@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Environment is the source of the time and of the randomness of the scheduler, of its profiles
+// and of their plugins, in place of time.Now and math/rand, so they can be controlled: stepped and
+// seeded in the tests, simulated when replaying decisions, or tuned in production. The latencies
+// recorded in the metrics are always measured in real time.
+type Environment struct {
+	Clock clock.PassiveClock
+	Rand  *Rand
+}
+
+// NewEnvironment returns the Environment of production: the real clock and a randomly seeded
+// source of random numbers.
+func NewEnvironment() Environment {
+	return Environment{Clock: clock.RealClock{}, Rand: NewRand(time.Now().UnixNano())}
+}
+
+// EnvironmentAware is implemented by the plugins that read the time or draw random numbers. The
+// scheduler sets its Environment on the plugins of its configuration before they are used, and
+// the plugins fall back to the real time and to math/rand until it's set.
+type EnvironmentAware interface {
+	Plugin
+	SetEnvironment(env Environment)
+}
+
+// Rand is a source of pseudo-random numbers safe for concurrent use. A nil Rand draws from the
+// global source of math/rand.
+type Rand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewRand returns a Rand seeded with the given seed, whose sequence is deterministic.
+func NewRand(seed int64) *Rand {
+	return &Rand{r: rand.New(rand.NewSource(seed))}
+}
+
+// Intn returns a random number in [0,n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Intn(n)
+}
+
+// Float64 returns a random number in [0.0,1.0).
+func (r *Rand) Float64() float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Float64()
+}
+
+// Shuffle shuffles n elements with the given swap function.
+func (r *Rand) Shuffle(n int, swap func(i, j int)) {
+	if r == nil {
+		rand.Shuffle(n, swap)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r.Shuffle(n, swap)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"strconv"
+	"sync"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// filterCache holds the results of the CacheableFilters of a profile for the latest snapshot
+// generation. A result is keyed by the position of the filter in the profile and by the cache keys
+// of the filter and of the filters before it, which determine its input. Only the leading
+// CacheableFilters of a profile are cached, since the input of the filters after a non-cacheable
+// one varies across requests.
+type filterCache struct {
+	mu         sync.Mutex
+	generation uint64
+	results    map[string]map[k8stypes.NamespacedName]bool // key -> pods kept by the filter
+}
+
+func newFilterCache() *filterCache {
+	return &filterCache{results: map[string]map[k8stypes.NamespacedName]bool{}}
+}
+
+// get returns the given pods filtered by the cached result for the given generation and key, and
+// whether the result was found.
+func (c *filterCache) get(generation uint64, key string, pods []types.Pod) ([]types.Pod, bool) {
+	c.mu.Lock()
+	kept, ok := c.results[key]
+	ok = ok && c.generation == generation
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	filteredPods := []types.Pod{}
+	for _, pod := range pods {
+		if kept[pod.GetPod().NamespacedName] {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	return filteredPods, true
+}
+
+// put caches the result of a filter for the given generation and key. The results of the older
+// generations are dropped, and the results of a generation older than the cached one are ignored.
+func (c *filterCache) put(generation uint64, key string, filteredPods []types.Pod) {
+	kept := make(map[k8stypes.NamespacedName]bool, len(filteredPods))
+	for _, pod := range filteredPods {
+		kept[pod.GetPod().NamespacedName] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation < c.generation {
+		return
+	}
+	if generation > c.generation {
+		c.generation = generation
+		c.results = map[string]map[k8stypes.NamespacedName]bool{}
+	}
+	c.results[key] = kept
+}
+
+// filterCacheKey appends the cache key of a filter to the cache key of the filters before it.
+func filterCacheKey(previousKey string, filterIndex int, filterKey string) string {
+	return previousKey + "/" + strconv.Itoa(filterIndex) + ":" + filterKey
+}
@@ -17,16 +17,28 @@ limitations under the License.
 package framework
 
 import (
+	"encoding/json"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 )
 
 const (
 	ProfilePickerType      = "ProfilePicker"
+	PreCyclePluginType     = "PreCycle"
 	FilterPluginType       = "Filter"
 	ScorerPluginType       = "Scorer"
 	PickerPluginType       = "Picker"
 	PostCyclePluginType    = "PostCycle"
+	PostSchedulePluginType = "PostSchedule"
 	PostResponsePluginType = "PostResponse"
+	// PostResponseCompletePluginType is the type of the PostResponse plugins when run on the
+	// completion of the response.
+	PostResponseCompletePluginType = "PostResponseComplete"
+	// RequestEndPluginType is the type of the PostResponse plugins when run at the end of the
+	// requests.
+	RequestEndPluginType = "RequestEnd"
+	ClassifierPluginType = "Classifier"
 )
 
 // Plugin defines the interface for scheduler plugins, combining scoring, filtering,
@@ -36,19 +48,74 @@ type Plugin interface {
 	Name() string
 }
 
+// Observable is implemented by the plugins that export metrics of their own, e.g. the size of
+// their state or their cache hit rate. The scheduler calls RegisterMetrics whenever its
+// configuration is set, and registers the created metrics with the metrics registry of the EPP
+// under names namespaced by the name of the plugin (see metrics.PluginMetrics). The metrics of
+// the plugins removed from the configuration are unregistered.
+type Observable interface {
+	Plugin
+	// RegisterMetrics creates the metrics of the plugin with the given PluginMetrics.
+	RegisterMetrics(m *metrics.PluginMetrics)
+}
+
+// Stateful is implemented by the plugins whose state takes time to rebuild, e.g. session affinity
+// tables or latency models learned from the responses. The state is exported by an EPP being
+// replaced and imported by its successor (see the handoff package), so the routing quality doesn't
+// degrade while the new EPP relearns it.
+type Stateful interface {
+	Plugin
+	// ExportState returns the state of the plugin, serialized as JSON.
+	ExportState() (json.RawMessage, error)
+	// ImportState merges the given state, exported by a plugin of the same name, into the state of
+	// the plugin.
+	ImportState(state json.RawMessage) error
+}
+
+// Classifier is called by the scheduler before the profiles are picked, to classify the request
+// with labels (see LLMRequest.Labels). The classifiers run in order and their labels are merged,
+// so a classifier can read the labels of the previous ones, and the last one wins when several
+// set the same label. An empty value removes the label.
+type Classifier interface {
+	Plugin
+	Classify(ctx *types.SchedulingContext) map[string]string
+}
+
 // ProfilePicker selects the SchedulingProfiles to run from a list of candidate profiles, while taking into consideration the request properties
-// and the previously executed SchedluderProfile cycles along with their results.
+// and the previously executed SchedluderProfile cycles along with their results. The optional profiles that failed have a result without
+// a TargetPod (see SchedulerProfile.WithFailurePolicy).
 type ProfilePicker interface {
 	Plugin
 	Pick(request *types.LLMRequest, profiles map[string]*SchedulerProfile, executionResults map[string]*types.Result) map[string]*SchedulerProfile
 }
 
+// PreCycle is called by the scheduler at the start of a SchedulerProfile cycle, after the pods
+// snapshot is taken and before the filters run. It lets plugins compute what depends on the request
+// only once (e.g. tokenize the prompt or estimate the output length) and share it with the filters
+// and scorers through the CycleState. The CycleState is shared by all the profiles run for a
+// request, so a plugin registered in several profiles can check it to skip the work already done.
+type PreCycle interface {
+	Plugin
+	PreCycle(ctx *types.SchedulingContext)
+}
+
 // Filter defines the interface for filtering a list of pods based on context.
 type Filter interface {
 	Plugin
 	Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod
 }
 
+// CacheableFilter is a Filter whose result only depends on the pods and their metrics, and
+// optionally on a part of the request, so the result can be reused across the requests scheduled
+// with the same snapshot generation (see types.SchedulingContext). Filters that are random,
+// stateful or depend on the time must not implement it.
+type CacheableFilter interface {
+	Filter
+	// FilterCacheKey returns the part of the request the result of the filter depends on (e.g. its
+	// criticality), or an empty string if it only depends on the pods.
+	FilterCacheKey(req *types.LLMRequest) string
+}
+
 // Scorer defines the interface for scoring a list of pods based on context.
 // Scorers must score pods with a value within the range of [0,1] where 1 is the highest score.
 type Scorer interface {
@@ -57,6 +124,8 @@ type Scorer interface {
 }
 
 // Picker picks the final pod(s) to send the request to.
+// Besides the target pod, a picker may return an ordered list of fallback pods among the candidates,
+// which the gateway retries on connection failure.
 type Picker interface {
 	Plugin
 	Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result
@@ -68,9 +137,36 @@ type PostCycle interface {
 	PostCycle(ctx *types.SchedulingContext, res *types.Result)
 }
 
+// PostSchedule is called by the scheduler after all the profiles ran, before the results are
+// returned. It may rewrite the results, e.g. override the target pod with a pinned pod for
+// debugging, or veto them by returning an error, e.g. when the target pod fails an external policy
+// check, in which case the request fails. The given results are a copy owned by the call, so they
+// may be modified in place. It's also called for the decisions replayed under overload.
+type PostSchedule interface {
+	Plugin
+	PostSchedule(ctx *types.SchedulingContext, results map[string]*types.Result) error
+}
+
 // PostResponse is called by the scheduler after a successful response was sent.
 // The given pod argument is the pod that served the request.
 type PostResponse interface {
 	Plugin
 	PostResponse(ctx *types.SchedulingContext, pod types.Pod)
 }
+
+// PostResponseComplete is optionally implemented by the PostResponse plugins to be called once the
+// response was fully received, streamed or not. Unlike PostResponse, which is called on the response
+// headers, the response of the context then holds its usage, finish reason and latency.
+type PostResponseComplete interface {
+	PostResponse
+	PostResponseComplete(ctx *types.SchedulingContext, pod types.Pod)
+}
+
+// RequestEnd is optionally implemented by the PostResponse plugins to be called once the
+// processing of a scheduled request ended, whatever its outcome: its response completed or failed,
+// or its client disconnected. It's meant to release what the plugin accounted to the request, and
+// the response of the context only holds the ID of the request.
+type RequestEnd interface {
+	PostResponse
+	RequestEnd(ctx *types.SchedulingContext, pod types.Pod)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package classifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestClassifiers(t *testing.T) {
+	tests := []struct {
+		name       string
+		classifier framework.Classifier
+		req        *types.LLMRequest
+		want       map[string]string
+	}{
+		{
+			name:       "chat completions",
+			classifier: NewWorkloadTypeClassifier(),
+			req:        &types.LLMRequest{Headers: map[string]string{":path": "/v1/chat/completions"}},
+			want:       map[string]string{types.LabelWorkloadType: WorkloadTypeChat},
+		},
+		{
+			name:       "completions with a query",
+			classifier: NewWorkloadTypeClassifier(),
+			req:        &types.LLMRequest{Headers: map[string]string{":path": "/v1/completions?stream=true"}},
+			want:       map[string]string{types.LabelWorkloadType: WorkloadTypeCompletion},
+		},
+		{
+			name:       "embeddings",
+			classifier: NewWorkloadTypeClassifier(),
+			req:        &types.LLMRequest{Headers: map[string]string{":path": "/v1/embeddings/"}},
+			want:       map[string]string{types.LabelWorkloadType: WorkloadTypeEmbedding},
+		},
+		{
+			name:       "no path",
+			classifier: NewWorkloadTypeClassifier(),
+			req:        &types.LLMRequest{},
+			want:       map[string]string{types.LabelWorkloadType: WorkloadTypeOther},
+		},
+		{
+			name:       "small request",
+			classifier: NewSizeClassClassifier(100, 1000),
+			req:        &types.LLMRequest{PromptTokens: 50, EstimatedOutputTokens: 49},
+			want:       map[string]string{types.LabelSizeClass: SizeClassSmall},
+		},
+		{
+			name:       "medium request",
+			classifier: NewSizeClassClassifier(100, 1000),
+			req:        &types.LLMRequest{PromptTokens: 50, EstimatedOutputTokens: 50},
+			want:       map[string]string{types.LabelSizeClass: SizeClassMedium},
+		},
+		{
+			name:       "large request",
+			classifier: NewSizeClassClassifier(100, 1000),
+			req:        &types.LLMRequest{PromptTokens: 900, EstimatedOutputTokens: 256},
+			want:       map[string]string{types.LabelSizeClass: SizeClassLarge},
+		},
+		{
+			name:       "request without estimate",
+			classifier: NewSizeClassClassifier(100, 1000),
+			req:        &types.LLMRequest{},
+			want:       nil,
+		},
+		{
+			name:       "tenant",
+			classifier: NewTenantClassifier(DefaultTenantHeader),
+			req:        &types.LLMRequest{Headers: map[string]string{DefaultTenantHeader: "team-a"}},
+			want:       map[string]string{types.LabelTenant: "team-a"},
+		},
+		{
+			name:       "no tenant",
+			classifier: NewTenantClassifier(DefaultTenantHeader),
+			req:        &types.LLMRequest{Headers: map[string]string{"x-other": "team-a"}},
+			want:       nil,
+		},
+		{
+			name:       "critical request",
+			classifier: NewCriticalityClassifier(),
+			req:        &types.LLMRequest{Critical: true},
+			want:       map[string]string{types.LabelCriticality: CriticalityCritical},
+		},
+		{
+			name:       "non-critical request",
+			classifier: NewCriticalityClassifier(),
+			req:        &types.LLMRequest{},
+			want:       map[string]string{types.LabelCriticality: CriticalityNonCritical},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), test.req, nil, nil)
+			got := test.classifier.Classify(ctx)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected labels (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package classifier
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	CriticalityCritical    = "critical"
+	CriticalityNonCritical = "non-critical"
+)
+
+// compile-time type assertion
+var _ framework.Classifier = &CriticalityClassifier{}
+
+// NewCriticalityClassifier initializes a new CriticalityClassifier and returns its pointer.
+func NewCriticalityClassifier() *CriticalityClassifier {
+	return &CriticalityClassifier{}
+}
+
+// CriticalityClassifier labels the requests with their criticality, as resolved from the
+// InferenceModel and the route policy of the request.
+type CriticalityClassifier struct{}
+
+// Name returns the name of the classifier.
+func (c *CriticalityClassifier) Name() string {
+	return "criticality"
+}
+
+// Classify returns the criticality label of the request.
+func (c *CriticalityClassifier) Classify(ctx *types.SchedulingContext) map[string]string {
+	if ctx.Req.Critical {
+		return map[string]string{types.LabelCriticality: CriticalityCritical}
+	}
+	return map[string]string{types.LabelCriticality: CriticalityNonCritical}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package classifier
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	SizeClassSmall  = "small"
+	SizeClassMedium = "medium"
+	SizeClassLarge  = "large"
+
+	// DefaultMediumSizeTokens is the default number of tokens from which the requests are medium.
+	DefaultMediumSizeTokens = 1024
+	// DefaultLargeSizeTokens is the default number of tokens from which the requests are large.
+	DefaultLargeSizeTokens = 8192
+)
+
+// compile-time type assertion
+var _ framework.Classifier = &SizeClassClassifier{}
+
+// NewSizeClassClassifier initializes a new SizeClassClassifier and returns its pointer.
+// The requests of at least mediumTokens tokens are medium, and the ones of at least largeTokens
+// tokens are large.
+func NewSizeClassClassifier(mediumTokens, largeTokens int) *SizeClassClassifier {
+	return &SizeClassClassifier{
+		mediumTokens: mediumTokens,
+		largeTokens:  largeTokens,
+	}
+}
+
+// SizeClassClassifier labels the requests with the class of their estimated number of tokens,
+// prompt and output together: small, medium or large. The requests whose number of tokens wasn't
+// estimated are not labeled.
+type SizeClassClassifier struct {
+	mediumTokens int
+	largeTokens  int
+}
+
+// Name returns the name of the classifier.
+func (c *SizeClassClassifier) Name() string {
+	return "size-class"
+}
+
+// Classify returns the size class label of the request.
+func (c *SizeClassClassifier) Classify(ctx *types.SchedulingContext) map[string]string {
+	tokens := ctx.Req.PromptTokens + ctx.Req.EstimatedOutputTokens
+	if tokens == 0 {
+		return nil
+	}
+
+	sizeClass := SizeClassSmall
+	switch {
+	case tokens >= c.largeTokens:
+		sizeClass = SizeClassLarge
+	case tokens >= c.mediumTokens:
+		sizeClass = SizeClassMedium
+	}
+	return map[string]string{types.LabelSizeClass: sizeClass}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package classifier
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// DefaultTenantHeader is the default request header holding the tenant of the requests.
+const DefaultTenantHeader = "x-tenant-id"
+
+// compile-time type assertion
+var _ framework.Classifier = &TenantClassifier{}
+
+// NewTenantClassifier initializes a new TenantClassifier and returns its pointer.
+func NewTenantClassifier(header string) *TenantClassifier {
+	return &TenantClassifier{header: header}
+}
+
+// TenantClassifier labels the requests with the tenant found in the given request header. The
+// requests without the header are not labeled.
+type TenantClassifier struct {
+	header string
+}
+
+// Name returns the name of the classifier.
+func (c *TenantClassifier) Name() string {
+	return "tenant"
+}
+
+// Classify returns the tenant label of the request.
+func (c *TenantClassifier) Classify(ctx *types.SchedulingContext) map[string]string {
+	tenant := ctx.Req.Headers[c.header]
+	if tenant == "" {
+		return nil
+	}
+	return map[string]string{types.LabelTenant: tenant}
+}
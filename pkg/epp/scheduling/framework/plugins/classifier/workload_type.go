@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package classifier
+
+import (
+	"strings"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	WorkloadTypeChat       = "chat"
+	WorkloadTypeCompletion = "completion"
+	WorkloadTypeEmbedding  = "embedding"
+	WorkloadTypeOther      = "other"
+
+	// pathHeader is the pseudo-header of the path of the request, as sent by Envoy.
+	pathHeader = ":path"
+)
+
+// compile-time type assertion
+var _ framework.Classifier = &WorkloadTypeClassifier{}
+
+// NewWorkloadTypeClassifier initializes a new WorkloadTypeClassifier and returns its pointer.
+func NewWorkloadTypeClassifier() *WorkloadTypeClassifier {
+	return &WorkloadTypeClassifier{}
+}
+
+// WorkloadTypeClassifier labels the requests with the OpenAI API they are sent to, based on their
+// path: chat completions, completions or embeddings. The requests to any other path are labeled
+// "other".
+type WorkloadTypeClassifier struct{}
+
+// Name returns the name of the classifier.
+func (c *WorkloadTypeClassifier) Name() string {
+	return "workload-type"
+}
+
+// Classify returns the workload type label of the request.
+func (c *WorkloadTypeClassifier) Classify(ctx *types.SchedulingContext) map[string]string {
+	path, _, _ := strings.Cut(ctx.Req.Headers[pathHeader], "?")
+	path = strings.TrimSuffix(path, "/")
+
+	workloadType := WorkloadTypeOther
+	switch {
+	case strings.HasSuffix(path, "/chat/completions"):
+		workloadType = WorkloadTypeChat
+	case strings.HasSuffix(path, "/completions"):
+		workloadType = WorkloadTypeCompletion
+	case strings.HasSuffix(path, "/embeddings"):
+		workloadType = WorkloadTypeEmbedding
+	}
+	return map[string]string{types.LabelWorkloadType: workloadType}
+}
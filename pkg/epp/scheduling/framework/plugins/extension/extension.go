@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package extension lets operators run custom scorers and filters as separate gRPC processes, so
+// that business logic can be added to the scheduling without forking the endpoint picker.
+//
+// The extension serves the SchedulerExtension gRPC service, whose Score and Filter methods take
+// and return JSON documents wrapped in google.protobuf.BytesValue messages, so an extension can be
+// written in any language without generated code: Score is given a Request and returns a
+// ScoreResponse, Filter is given a Request and returns a FilterResponse. Extensions written in Go
+// implement Extension and are served with RegisterServer.
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	// ServiceName is the name of the gRPC service of the scheduler extensions.
+	ServiceName = "inference.networking.x-k8s.io.extension.v1.SchedulerExtension"
+
+	MethodScore  = "Score"
+	MethodFilter = "Filter"
+)
+
+// Request is the request of the Score and Filter methods: the request being scheduled and its
+// candidate pods.
+type Request struct {
+	RequestID   string `json:"requestId,omitempty"`
+	TargetModel string `json:"targetModel"`
+	Critical    bool   `json:"critical,omitempty"`
+	// Profile is the name of the scheduler profile running the extension.
+	Profile string `json:"profile,omitempty"`
+	// Labels are the labels of the request set by the classifiers.
+	Labels  map[string]string `json:"labels,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Prompt is only sent to the extensions configured to receive it.
+	Prompt string `json:"prompt,omitempty"`
+	Pods   []Pod  `json:"pods"`
+}
+
+// Pod is a candidate pod of a Request.
+type Pod struct {
+	// Name is the namespace and name of the pod, "namespace/name", by which the responses refer to
+	// it.
+	Name    string            `json:"name"`
+	Address string            `json:"address"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Metrics *PodMetrics       `json:"metrics,omitempty"`
+}
+
+// PodMetrics are the latest metrics of a Pod.
+type PodMetrics struct {
+	WaitingQueueSize    int     `json:"waitingQueueSize"`
+	RunningQueueSize    int     `json:"runningQueueSize"`
+	KVCacheUsagePercent float64 `json:"kvCacheUsagePercent"`
+	// ActiveModels are the adapters loaded on the pod.
+	ActiveModels []string `json:"activeModels,omitempty"`
+}
+
+// ScoreResponse is the response of the Score method.
+type ScoreResponse struct {
+	// Scores are the scores of the pods in [0, 1] by pod name. The pods without a score get 0, and
+	// the scores out of range are clamped.
+	Scores map[string]float64 `json:"scores"`
+}
+
+// FilterResponse is the response of the Filter method.
+type FilterResponse struct {
+	// Pods are the names of the pods passing the filter.
+	Pods []string `json:"pods"`
+}
+
+// Extension is a scheduler extension written in Go. An extension only implementing one of the
+// methods returns an error with the codes.Unimplemented status code from the other one.
+type Extension interface {
+	Score(ctx context.Context, req *Request) (*ScoreResponse, error)
+	Filter(ctx context.Context, req *Request) (*FilterResponse, error)
+}
+
+// RegisterServer registers the SchedulerExtension service served by the given Extension with the
+// given gRPC server.
+func RegisterServer(srv *grpc.Server, ext Extension) {
+	srv.RegisterService(&serviceDesc, ext)
+}
+
+// serviceDesc is the description of the gRPC service, which has no generated code as its messages
+// are well-known types.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Extension)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: MethodScore,
+			Handler: methodHandler(MethodScore, func(ext Extension, ctx context.Context, req *Request) (any, error) {
+				return ext.Score(ctx, req)
+			}),
+		},
+		{
+			MethodName: MethodFilter,
+			Handler: methodHandler(MethodFilter, func(ext Extension, ctx context.Context, req *Request) (any, error) {
+				return ext.Filter(ctx, req)
+			}),
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func methodHandler(method string, call func(ext Extension, ctx context.Context, req *Request) (any, error)) grpc.MethodHandler {
+	fullMethod := "/" + ServiceName + "/" + method
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := &wrapperspb.BytesValue{}
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, in any) (any, error) {
+			req := &Request{}
+			if err := json.Unmarshal(in.(*wrapperspb.BytesValue).GetValue(), req); err != nil {
+				return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid request: %v", err))
+			}
+			resp, err := call(srv.(Extension), ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			return wrapperspb.Bytes(data), nil
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, handler)
+	}
+}
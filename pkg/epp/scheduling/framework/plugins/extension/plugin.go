@@ -0,0 +1,328 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// ScorerName and FilterName are the default names of the extension plugins.
+	ScorerName = "extension-scorer"
+	FilterName = "extension-filter"
+
+	// DefaultTimeout is the default timeout of the calls of an extension. The calls are on the
+	// scheduling path of every request, so the extensions are expected to respond in milliseconds.
+	DefaultTimeout = 100 * time.Millisecond
+
+	// FailurePolicyIgnore goes on without the extension when a call fails: the pods are scored 0
+	// by a scorer, and pass a filter.
+	FailurePolicyIgnore FailurePolicy = "ignore"
+	// FailurePolicyFail filters out the pods when a call of a filter fails, so the request fails
+	// if none is left. The scorers always ignore the failures, as the pods can still be ranked by
+	// the other scorers.
+	FailurePolicyFail FailurePolicy = "fail"
+)
+
+// FailurePolicy is what happens when a call of an extension fails or times out.
+type FailurePolicy string
+
+// Config is the configuration of an extension scorer or filter.
+type Config struct {
+	// Name is the name of the plugin, which tells the extensions of a profile apart in the
+	// metrics and logs of the plugins. Defaults to "extension-scorer" or "extension-filter".
+	Name string
+	// Address is the gRPC address of the extension, e.g. "localhost:9010" for a sidecar.
+	Address string
+	// Timeout bounds every call of the extension, DefaultTimeout if not positive.
+	Timeout time.Duration
+	// FailurePolicy is FailurePolicyIgnore if empty.
+	FailurePolicy FailurePolicy
+	// MaxPodsPerCall, if positive, splits the candidate pods into batches of at most that many
+	// pods sent to the extension concurrently, whose responses are merged, so that the latency of
+	// the extension doesn't grow with the size of the pool.
+	MaxPodsPerCall int
+	// SendPrompt sends the prompt of the requests to the extension.
+	SendPrompt bool
+}
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Address == "" {
+		errs = append(errs, errors.New("address is required"))
+	}
+	if c.FailurePolicy != "" && c.FailurePolicy != FailurePolicyIgnore && c.FailurePolicy != FailurePolicyFail {
+		errs = append(errs, fmt.Errorf("unknown failure policy '%s'", c.FailurePolicy))
+	}
+	if c.MaxPodsPerCall < 0 {
+		errs = append(errs, fmt.Errorf("negative max pods per call %d", c.MaxPodsPerCall))
+	}
+	return errors.Join(errs...)
+}
+
+// conns are the connections to the extensions by address, shared by the plugins calling the same
+// extension and kept across the reloads of the scheduler config, which create new plugins.
+var conns = struct {
+	sync.Mutex
+	byAddress map[string]*grpc.ClientConn
+}{byAddress: map[string]*grpc.ClientConn{}}
+
+func connect(address string) (*grpc.ClientConn, error) {
+	conns.Lock()
+	defer conns.Unlock()
+	if conn, ok := conns.byAddress[address]; ok {
+		return conn, nil
+	}
+	// The connection is established lazily, so an extension that isn't up yet doesn't fail the
+	// creation of the plugin.
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of the extension at %s: %w", address, err)
+	}
+	conns.byAddress[address] = conn
+	return conn, nil
+}
+
+// client calls the methods of an extension.
+type client struct {
+	Config
+	conn *grpc.ClientConn
+}
+
+func newClient(config Config) (*client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.FailurePolicy == "" {
+		config.FailurePolicy = FailurePolicyIgnore
+	}
+	conn, err := connect(config.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &client{Config: config, conn: conn}, nil
+}
+
+// fanOut calls the given method of the extension with the given pods, in batches of at most
+// MaxPodsPerCall pods called concurrently, and passes the response of every batch to merge, or
+// nil if the call failed. merge is not called concurrently.
+func (c *client) fanOut(ctx *types.SchedulingContext, method string, pods []types.Pod, newResp func() any, merge func(batch []types.Pod, resp any)) {
+	batchSize := len(pods)
+	if c.MaxPodsPerCall > 0 && c.MaxPodsPerCall < batchSize {
+		batchSize = c.MaxPodsPerCall
+	}
+	if batchSize == 0 {
+		return
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for start := 0; start < len(pods); start += batchSize {
+		batch := pods[start:min(start+batchSize, len(pods))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := newResp()
+			if err := c.call(ctx, method, batch, resp); err != nil {
+				ctx.Logger.V(logutil.DEFAULT).Error(err, "Scheduler extension call failed", "address", c.Address, "method", method, "pods", len(batch))
+				resp = nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			merge(batch, resp)
+		}()
+	}
+	wg.Wait()
+}
+
+func (c *client) call(ctx *types.SchedulingContext, method string, pods []types.Pod, resp any) error {
+	data, err := json.Marshal(NewRequest(ctx, pods, c.SendPrompt))
+	if err != nil {
+		metrics.RecordSchedulerExtensionFailure(c.Address, method, "error")
+		return err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	out := &wrapperspb.BytesValue{}
+	if err := c.conn.Invoke(callCtx, "/"+ServiceName+"/"+method, wrapperspb.Bytes(data), out); err != nil {
+		reason := "error"
+		if callCtx.Err() != nil {
+			reason = "timeout"
+		}
+		metrics.RecordSchedulerExtensionFailure(c.Address, method, reason)
+		return err
+	}
+	if err := json.Unmarshal(out.GetValue(), resp); err != nil {
+		metrics.RecordSchedulerExtensionFailure(c.Address, method, "invalid-response")
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// NewRequest returns the Request of the given scheduling context and candidate pods, with the
+// prompt of the request if sendPrompt is set.
+func NewRequest(ctx *types.SchedulingContext, pods []types.Pod, sendPrompt bool) *Request {
+	req := &Request{
+		RequestID:   ctx.Req.RequestId,
+		TargetModel: ctx.Req.TargetModel,
+		Critical:    ctx.Req.Critical,
+		Profile:     ctx.ProfileName,
+		Labels:      ctx.Req.Labels,
+		Headers:     ctx.Req.Headers,
+		Pods:        make([]Pod, 0, len(pods)),
+	}
+	if sendPrompt {
+		req.Prompt = ctx.Req.Prompt
+	}
+	for _, pod := range pods {
+		p := Pod{
+			Name:    pod.GetPod().NamespacedName.String(),
+			Address: pod.GetPod().Address,
+			Labels:  pod.GetPod().Labels,
+		}
+		if m := pod.GetMetrics(); m != nil {
+			p.Metrics = &PodMetrics{
+				WaitingQueueSize:    m.WaitingQueueSize,
+				RunningQueueSize:    m.RunningQueueSize,
+				KVCacheUsagePercent: m.KVCacheUsagePercent,
+			}
+			for model := range m.ActiveModels {
+				p.Metrics.ActiveModels = append(p.Metrics.ActiveModels, model)
+			}
+			sort.Strings(p.Metrics.ActiveModels)
+		}
+		req.Pods = append(req.Pods, p)
+	}
+	return req
+}
+
+// compile-time type assertion
+var _ framework.Scorer = &Scorer{}
+var _ framework.Filter = &Filter{}
+
+// Scorer scores the candidate pods by calling the Score method of an extension.
+type Scorer struct {
+	*client
+}
+
+// NewScorer returns a new Scorer calling the extension of the given config.
+func NewScorer(config Config) (*Scorer, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if c.Name == "" {
+		c.Name = ScorerName
+	}
+	return &Scorer{client: c}, nil
+}
+
+// Name returns the name of the scorer.
+func (s *Scorer) Name() string {
+	return s.client.Name
+}
+
+// Score returns the scores returned by the extension, clamped to [0, 1]. The pods the extension
+// didn't score, or whose batch failed, get 0.
+func (s *Scorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scores[pod] = 0
+	}
+	s.fanOut(ctx, MethodScore, pods, func() any { return &ScoreResponse{} }, func(batch []types.Pod, resp any) {
+		if resp == nil {
+			return
+		}
+		returned := resp.(*ScoreResponse).Scores
+		for _, pod := range batch {
+			score := returned[pod.GetPod().NamespacedName.String()]
+			if math.IsNaN(score) {
+				score = 0
+			}
+			scores[pod] = math.Max(0, math.Min(1, score))
+		}
+	})
+	return scores
+}
+
+// Filter filters the candidate pods by calling the Filter method of an extension.
+type Filter struct {
+	*client
+}
+
+// NewFilter returns a new Filter calling the extension of the given config.
+func NewFilter(config Config) (*Filter, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if c.Name == "" {
+		c.Name = FilterName
+	}
+	return &Filter{client: c}, nil
+}
+
+// Name returns the name of the filter.
+func (f *Filter) Name() string {
+	return f.client.Name
+}
+
+// Filter keeps the pods returned by the extension, in their original order. The pods of the
+// batches that failed are kept or filtered out according to the failure policy.
+func (f *Filter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	keep := make(map[types.Pod]bool, len(pods))
+	f.fanOut(ctx, MethodFilter, pods, func() any { return &FilterResponse{} }, func(batch []types.Pod, resp any) {
+		if resp == nil {
+			for _, pod := range batch {
+				keep[pod] = f.FailurePolicy == FailurePolicyIgnore
+			}
+			return
+		}
+		returned := make(map[string]bool, len(resp.(*FilterResponse).Pods))
+		for _, name := range resp.(*FilterResponse).Pods {
+			returned[name] = true
+		}
+		for _, pod := range batch {
+			keep[pod] = returned[pod.GetPod().NamespacedName.String()]
+		}
+	})
+	filtered := make([]types.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if keep[pod] {
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered
+}
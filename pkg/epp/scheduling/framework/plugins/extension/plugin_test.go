@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// fakeExtension scores the pods by name, filters out pod2, and records the requests it receives.
+type fakeExtension struct {
+	delay time.Duration
+
+	mu       sync.Mutex
+	requests []*Request
+}
+
+func (e *fakeExtension) record(req *Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, req)
+}
+
+func (e *fakeExtension) Score(ctx context.Context, req *Request) (*ScoreResponse, error) {
+	e.record(req)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(e.delay):
+	}
+	return &ScoreResponse{Scores: map[string]float64{
+		"default/pod1": 0.8,
+		"default/pod2": 3,
+		"default/pod9": 1,
+	}}, nil
+}
+
+func (e *fakeExtension) Filter(_ context.Context, req *Request) (*FilterResponse, error) {
+	e.record(req)
+	return &FilterResponse{Pods: []string{"default/pod1", "default/pod3"}}, nil
+}
+
+// unimplementedExtension only implements Filter.
+type unimplementedExtension struct {
+	fakeExtension
+}
+
+func (e *unimplementedExtension) Filter(_ context.Context, _ *Request) (*FilterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "filter is not implemented")
+}
+
+// serve serves the given extension on a local port, and returns its address.
+func serve(t *testing.T, ext Extension) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	RegisterServer(srv, ext)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func newPods() []types.Pod {
+	pods := make([]types.Pod, 0, 3)
+	for _, name := range []string{"pod1", "pod2", "pod3"} {
+		pods = append(pods, &types.PodMetrics{
+			Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: name}, Address: name + "-ip"},
+			MetricsState: &backendmetrics.MetricsState{
+				WaitingQueueSize: 2,
+				ActiveModels:     map[string]int{"lora-b": 1, "lora-a": 1},
+			},
+		})
+	}
+	return pods
+}
+
+func newContext(pods []types.Pod) *types.SchedulingContext {
+	req := &types.LLMRequest{RequestId: "req1", TargetModel: "llama", Prompt: "hello", Labels: map[string]string{"tenant": "a"}}
+	return types.NewSchedulingContext(context.Background(), req, nil, pods)
+}
+
+func TestScorer(t *testing.T) {
+	ext := &fakeExtension{}
+	address := serve(t, ext)
+	pods := newPods()
+
+	scorer, err := NewScorer(Config{Address: address, Timeout: time.Second})
+	assert.NoError(t, err)
+	assert.Equal(t, ScorerName, scorer.Name())
+	scores := scorer.Score(newContext(pods), pods)
+	assert.Equal(t, map[types.Pod]float64{pods[0]: 0.8, pods[1]: 1, pods[2]: 0}, scores)
+
+	assert.Len(t, ext.requests, 1)
+	req := ext.requests[0]
+	assert.Equal(t, "req1", req.RequestID)
+	assert.Equal(t, "llama", req.TargetModel)
+	assert.Equal(t, map[string]string{"tenant": "a"}, req.Labels)
+	assert.Empty(t, req.Prompt, "the prompt isn't sent by default")
+	assert.Equal(t, Pod{
+		Name:    "default/pod1",
+		Address: "pod1-ip",
+		Metrics: &PodMetrics{WaitingQueueSize: 2, ActiveModels: []string{"lora-a", "lora-b"}},
+	}, req.Pods[0])
+
+	// The pods are fanned out in batches.
+	ext.requests = nil
+	scorer, err = NewScorer(Config{Name: "batched", Address: address, Timeout: time.Second, MaxPodsPerCall: 2, SendPrompt: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "batched", scorer.Name())
+	scores = scorer.Score(newContext(pods), pods)
+	assert.Equal(t, map[types.Pod]float64{pods[0]: 0.8, pods[1]: 1, pods[2]: 0}, scores)
+	assert.Len(t, ext.requests, 2)
+	podsSent := 0
+	for _, req := range ext.requests {
+		assert.LessOrEqual(t, len(req.Pods), 2)
+		assert.Equal(t, "hello", req.Prompt)
+		podsSent += len(req.Pods)
+	}
+	assert.Equal(t, 3, podsSent)
+
+	// The pods are scored 0 when the extension times out.
+	slow := serve(t, &fakeExtension{delay: time.Second})
+	scorer, err = NewScorer(Config{Address: slow, Timeout: 50 * time.Millisecond})
+	assert.NoError(t, err)
+	start := time.Now()
+	scores = scorer.Score(newContext(pods), pods)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, map[types.Pod]float64{pods[0]: 0, pods[1]: 0, pods[2]: 0}, scores)
+}
+
+func TestFilter(t *testing.T) {
+	address := serve(t, &fakeExtension{})
+	failing := serve(t, &unimplementedExtension{})
+	pods := newPods()
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []types.Pod
+	}{
+		{name: "filtered", config: Config{Address: address}, want: []types.Pod{pods[0], pods[2]}},
+		{name: "batched", config: Config{Address: address, MaxPodsPerCall: 1}, want: []types.Pod{pods[0], pods[2]}},
+		{name: "failure ignored", config: Config{Address: failing}, want: pods},
+		{name: "failure fails", config: Config{Address: failing, FailurePolicy: FailurePolicyFail}, want: []types.Pod{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.Timeout = time.Second
+			filter, err := NewFilter(test.config)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, filter.Filter(newContext(pods), pods))
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	err := Config{FailurePolicy: "sometimes", MaxPodsPerCall: -1}.Validate()
+	assert.ErrorContains(t, err, "address is required")
+	assert.ErrorContains(t, err, "unknown failure policy 'sometimes'")
+	assert.ErrorContains(t, err, "negative max pods per call -1")
+
+	_, err = NewScorer(Config{})
+	assert.ErrorContains(t, err, "address is required")
+}
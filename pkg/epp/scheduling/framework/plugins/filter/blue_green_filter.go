@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	ColorBlue  = "blue"
+	ColorGreen = "green"
+
+	// GreenWeightAnnotationKey is the InferencePool annotation holding the percentage [0, 100] of
+	// requests to send to the green pods.
+	GreenWeightAnnotationKey = "inference.networking.x-k8s.io/green-weight"
+
+	// DefaultBlueSelector and DefaultGreenSelector are the default label selectors of the pods of
+	// the blue and green stacks.
+	DefaultBlueSelector  = "stack=blue"
+	DefaultGreenSelector = "stack=green"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &BlueGreenFilter{}
+var _ framework.EnvironmentAware = &BlueGreenFilter{}
+
+// NewBlueGreenFilter initializes a new BlueGreenFilter and returns its pointer.
+// greenWeight is invoked for every request, so the split can be changed at runtime.
+func NewBlueGreenFilter(blue, green labels.Selector, greenWeight func() int) *BlueGreenFilter {
+	return &BlueGreenFilter{
+		blue:        blue,
+		green:       green,
+		greenWeight: greenWeight,
+	}
+}
+
+// BlueGreenFilter splits the pods of a pool into two serving stacks (blue and green), selected
+// by label, and sends each request to one of them based on the green weight. This allows
+// switching an entire serving stack (e.g. a new model server version) gradually behind a single
+// InferencePool.
+// If the drawn color has no pods, the other color is used. Pods that match neither selector are
+// never filtered out.
+type BlueGreenFilter struct {
+	blue        labels.Selector
+	green       labels.Selector
+	greenWeight func() int
+	rand        *framework.Rand
+}
+
+// SetEnvironment sets the source of the random numbers of the filter.
+func (f *BlueGreenFilter) SetEnvironment(env framework.Environment) {
+	f.rand = env.Rand
+}
+
+// Name returns the name of the filter.
+func (f *BlueGreenFilter) Name() string {
+	return "blue-green"
+}
+
+// Filter filters out the pods of the color that was not drawn for the request.
+func (f *BlueGreenFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	greenWeight := min(max(f.greenWeight(), 0), 100)
+	metrics.RecordBlueGreenWeight(ColorBlue, 100-greenWeight)
+	metrics.RecordBlueGreenWeight(ColorGreen, greenWeight)
+
+	bluePods, greenPods, uncoloredPods := []types.Pod{}, []types.Pod{}, []types.Pod{}
+	for _, pod := range pods {
+		podLabels := labels.Set(pod.GetPod().Labels)
+		switch {
+		case f.blue.Matches(podLabels):
+			bluePods = append(bluePods, pod)
+		case f.green.Matches(podLabels):
+			greenPods = append(greenPods, pod)
+		default:
+			uncoloredPods = append(uncoloredPods, pod)
+		}
+	}
+
+	color, filteredPods := ColorBlue, bluePods
+	if f.rand.Intn(100) < greenWeight {
+		color, filteredPods = ColorGreen, greenPods
+	}
+	if len(filteredPods) == 0 {
+		color, filteredPods = otherColor(color), append(bluePods, greenPods...)
+	}
+	if len(filteredPods) == 0 {
+		return pods
+	}
+
+	ctx.Logger.V(logutil.TRACE).Info("Picked blue/green color", "color", color, "greenWeight", greenWeight)
+	metrics.RecordBlueGreenRequest(color)
+	return append(filteredPods, uncoloredPods...)
+}
+
+func otherColor(color string) string {
+	if color == ColorBlue {
+		return ColorGreen
+	}
+	return ColorBlue
+}
+
+// GreenWeightFromAnnotations parses the green weight from the given annotations, returning
+// defaultWeight if it's missing or invalid.
+func GreenWeightFromAnnotations(annotations map[string]string, defaultWeight int) int {
+	weight, err := strconv.Atoi(annotations[GreenWeightAnnotationKey])
+	if err != nil {
+		return defaultWeight
+	}
+	return weight
+}
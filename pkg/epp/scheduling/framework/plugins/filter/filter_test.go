@@ -19,9 +19,11 @@ package filter
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/labels"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
@@ -134,6 +136,75 @@ func TestFilter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "least kv cache keeps queue depth only pods",
+			filter: NewLeastKVCacheFilter(),
+			input: []types.Pod{
+				&types.PodMetrics{
+					MetricsState: &backendmetrics.MetricsState{KVCacheUsagePercent: 0.2},
+				},
+				&types.PodMetrics{
+					MetricsState: &backendmetrics.MetricsState{KVCacheUsagePercent: 0.9},
+				},
+				&types.PodMetrics{
+					MetricsState: &backendmetrics.MetricsState{QueueDepthOnly: true},
+				},
+			},
+			output: []types.Pod{
+				&types.PodMetrics{
+					MetricsState: &backendmetrics.MetricsState{KVCacheUsagePercent: 0.2},
+				},
+				&types.PodMetrics{
+					MetricsState: &backendmetrics.MetricsState{QueueDepthOnly: true},
+				},
+			},
+		},
+		{
+			name:   "least queuing compares pods of the same role",
+			filter: NewLeastQueueFilter(),
+			input: []types.Pod{
+				&types.PodMetrics{
+					Pod:          &backend.Pod{Role: backend.PodRolePrefill},
+					MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: 10},
+				},
+				&types.PodMetrics{
+					Pod:          &backend.Pod{Role: backend.PodRolePrefill},
+					MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: 30},
+				},
+				&types.PodMetrics{
+					Pod:          &backend.Pod{Role: backend.PodRoleDecode},
+					MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: 0},
+				},
+				&types.PodMetrics{
+					Pod:          &backend.Pod{Role: backend.PodRoleDecode},
+					MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: 5},
+				},
+			},
+			output: []types.Pod{
+				&types.PodMetrics{
+					Pod:          &backend.Pod{Role: backend.PodRolePrefill},
+					MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: 10},
+				},
+				&types.PodMetrics{
+					Pod:          &backend.Pod{Role: backend.PodRoleDecode},
+					MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: 0},
+				},
+			},
+		},
+		{
+			name:   "role",
+			filter: NewRoleFilter(backend.PodRoleGeneral, backend.PodRoleDecode),
+			input: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "unlabeled"}}},
+				&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "prefill"}, Role: backend.PodRolePrefill}},
+				&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "decode"}, Role: backend.PodRoleDecode}},
+				&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "draft"}, Role: backend.PodRoleDraft}},
+			},
+			output: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "unlabeled"}}},
+				&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "decode"}, Role: backend.PodRoleDecode}},
+			},
+		},
 		{
 			name:   "SheddableCapacityFilter, sheddable request",
 			req:    &types.LLMRequest{Critical: false},
@@ -286,3 +357,370 @@ func TestLoRASoftAffinityDistribution(t *testing.T) {
 			actualAvailablePercent, availableLowerBound, availableUpperBound)
 	}
 }
+
+func TestBlueGreenFilter(t *testing.T) {
+	newPod := func(name, color string) types.Pod {
+		return &types.PodMetrics{
+			Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}, Labels: map[string]string{"stack": color}},
+			MetricsState: &backendmetrics.MetricsState{},
+		}
+	}
+	blue := newPod("blue", ColorBlue)
+	green := newPod("green", ColorGreen)
+	other := newPod("other", "")
+	blueSelector := labels.SelectorFromSet(labels.Set{"stack": ColorBlue})
+	greenSelector := labels.SelectorFromSet(labels.Set{"stack": ColorGreen})
+
+	tests := []struct {
+		name        string
+		greenWeight int
+		input       []types.Pod
+		output      []types.Pod
+	}{
+		{
+			name:        "all traffic to blue",
+			greenWeight: 0,
+			input:       []types.Pod{blue, green, other},
+			output:      []types.Pod{blue, other},
+		},
+		{
+			name:        "all traffic to green",
+			greenWeight: 100,
+			input:       []types.Pod{blue, green, other},
+			output:      []types.Pod{green, other},
+		},
+		{
+			name:        "weight out of range is clamped",
+			greenWeight: 150,
+			input:       []types.Pod{blue, green},
+			output:      []types.Pod{green},
+		},
+		{
+			name:        "fall back to the other color",
+			greenWeight: 100,
+			input:       []types.Pod{blue, other},
+			output:      []types.Pod{blue, other},
+		},
+		{
+			name:        "no colored pods",
+			greenWeight: 50,
+			input:       []types.Pod{other},
+			output:      []types.Pod{other},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := NewBlueGreenFilter(blueSelector, greenSelector, func() int { return test.greenWeight })
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, test.input)
+			got := filter.Filter(ctx, test.input)
+
+			if diff := cmp.Diff(test.output, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestMetricsFreshnessFilter(t *testing.T) {
+	now := time.Now()
+	newPod := func(name string, age time.Duration) types.Pod {
+		return &types.PodMetrics{
+			Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}},
+			MetricsState: &backendmetrics.MetricsState{UpdateTime: now.Add(-age)},
+		}
+	}
+	fresh := newPod("fresh", time.Second)
+	atThreshold := newPod("at-threshold", 5*time.Second)
+	stale := newPod("stale", time.Minute)
+	neverRefreshed := &types.PodMetrics{
+		Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "never-refreshed"}},
+		MetricsState: &backendmetrics.MetricsState{},
+	}
+
+	tests := []struct {
+		name   string
+		input  []types.Pod
+		output []types.Pod
+	}{
+		{
+			name:   "stale pods are filtered out",
+			input:  []types.Pod{fresh, atThreshold, stale, neverRefreshed},
+			output: []types.Pod{fresh, atThreshold},
+		},
+		{
+			name:   "all pods stale",
+			input:  []types.Pod{stale},
+			output: []types.Pod{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := NewMetricsFreshnessFilter(5 * time.Second)
+			filter.now = func() time.Time { return now }
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, test.input)
+			got := filter.Filter(ctx, test.input)
+
+			if diff := cmp.Diff(test.output, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestKVCacheHysteresisFilter(t *testing.T) {
+	newPod := func(name string, utilization float64) types.Pod {
+		return &types.PodMetrics{
+			Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}},
+			MetricsState: &backendmetrics.MetricsState{KVCacheUsagePercent: utilization},
+		}
+	}
+	names := func(pods []types.Pod) []string {
+		res := []string{}
+		for _, pod := range pods {
+			res = append(res, pod.GetPod().NamespacedName.Name)
+		}
+		return res
+	}
+
+	filter := NewKVCacheHysteresisFilter(0.9, 0.7)
+	steps := []struct {
+		name         string
+		utilizations map[string]float64 // pod-a, pod-b
+		want         []string
+	}{
+		{name: "both under the high watermark", utilizations: map[string]float64{"pod-a": 0.85, "pod-b": 0.5}, want: []string{"pod-a", "pod-b"}},
+		{name: "pod-a over the high watermark", utilizations: map[string]float64{"pod-a": 0.95, "pod-b": 0.5}, want: []string{"pod-b"}},
+		{name: "pod-a between the watermarks stays out", utilizations: map[string]float64{"pod-a": 0.8, "pod-b": 0.5}, want: []string{"pod-b"}},
+		{name: "pod-a under the low watermark is back", utilizations: map[string]float64{"pod-a": 0.6, "pod-b": 0.5}, want: []string{"pod-a", "pod-b"}},
+		{name: "pod-a between the watermarks stays in", utilizations: map[string]float64{"pod-a": 0.8, "pod-b": 0.5}, want: []string{"pod-a", "pod-b"}},
+		{name: "all pods over the high watermark", utilizations: map[string]float64{"pod-a": 0.95, "pod-b": 1}, want: []string{}},
+	}
+	for _, step := range steps {
+		pods := []types.Pod{newPod("pod-a", step.utilizations["pod-a"]), newPod("pod-b", step.utilizations["pod-b"])}
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, pods)
+		if diff := cmp.Diff(step.want, names(filter.Filter(ctx, pods))); diff != "" {
+			t.Errorf("%s: unexpected output (-want +got): %v", step.name, diff)
+		}
+	}
+}
+
+func TestSaturationFilter(t *testing.T) {
+	newPod := func(name string, queue int, utilization float64) types.Pod {
+		return &types.PodMetrics{
+			Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}},
+			MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: queue, KVCacheUsagePercent: utilization},
+		}
+	}
+	pods := []types.Pod{
+		newPod("idle", 0, 0.1),
+		newPod("busy", 5, 0.6),
+		newPod("saturated", 20, 0.95),
+	}
+	filter := NewSaturationFilter(SaturationTiers{
+		Critical:  SaturationThresholds{QueueThreshold: -1, KVCacheThreshold: -1},
+		Standard:  SaturationThresholds{QueueThreshold: 10, KVCacheThreshold: 0.8},
+		Sheddable: SaturationThresholds{QueueThreshold: 2, KVCacheThreshold: -1},
+	})
+
+	tests := []struct {
+		name string
+		req  *types.LLMRequest
+		want []string
+	}{
+		{name: "critical request", req: &types.LLMRequest{Critical: true}, want: []string{"idle", "busy", "saturated"}},
+		{name: "standard request", req: &types.LLMRequest{}, want: []string{"idle", "busy"}},
+		{name: "sheddable request", req: &types.LLMRequest{Sheddable: true}, want: []string{"idle"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), test.req, nil, pods)
+			got := []string{}
+			for _, pod := range filter.Filter(ctx, pods) {
+				got = append(got, pod.GetPod().NamespacedName.Name)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestLoraCapacityFilter(t *testing.T) {
+	newPod := func(name string, maxAdapters int, loaded, active, waiting []string) types.Pod {
+		metrics := &backendmetrics.MetricsState{
+			ActiveModels:    map[string]int{},
+			WaitingModels:   map[string]int{},
+			LoadedModels:    map[string]time.Time{},
+			MaxActiveModels: maxAdapters,
+		}
+		for _, adapter := range loaded {
+			metrics.LoadedModels[adapter] = time.Now()
+		}
+		for _, adapter := range active {
+			metrics.ActiveModels[adapter] = 0
+		}
+		for _, adapter := range waiting {
+			metrics.WaitingModels[adapter] = 0
+		}
+		return &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}}, MetricsState: metrics}
+	}
+	names := func(pods []types.Pod) []string {
+		res := []string{}
+		for _, pod := range pods {
+			res = append(res, pod.GetPod().NamespacedName.Name)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name        string
+		targetModel string
+		pods        []types.Pod
+		want        []string
+	}{
+		{
+			name:        "pods with the adapter loaded or a free slot are kept",
+			targetModel: "sql-lora",
+			pods: []types.Pod{
+				newPod("loaded", 2, []string{"sql-lora", "tweet-lora"}, nil, nil),
+				newPod("free", 2, []string{"tweet-lora"}, nil, nil),
+				newPod("full", 2, []string{"tweet-lora", "chat-lora"}, nil, nil),
+			},
+			want: []string{"loaded", "free"},
+		},
+		{
+			name:        "waiting adapters take a slot",
+			targetModel: "sql-lora",
+			pods: []types.Pod{
+				newPod("waiting", 2, []string{"tweet-lora"}, nil, []string{"chat-lora"}),
+				newPod("active", 2, nil, []string{"sql-lora"}, nil),
+			},
+			want: []string{"active"},
+		},
+		{
+			name:        "pods not reporting their adapter slots are kept",
+			targetModel: "sql-lora",
+			pods: []types.Pod{
+				newPod("unknown", 0, nil, nil, nil),
+				newPod("full", 1, []string{"sql-lora"}, nil, nil),
+				newPod("other", 1, []string{"tweet-lora"}, nil, nil),
+			},
+			want: []string{"unknown", "full"},
+		},
+		{
+			name:        "all pods full",
+			targetModel: "sql-lora",
+			pods: []types.Pod{
+				newPod("pod1", 1, []string{"tweet-lora"}, nil, nil),
+				newPod("pod2", 1, []string{"chat-lora"}, nil, nil),
+				newPod("pod3", 1, []string{"sql-lora"}, nil, nil),
+			},
+			want: []string{"pod3"},
+		},
+		{
+			name:        "base model",
+			targetModel: "base",
+			pods: []types.Pod{
+				newPod("pod1", 1, []string{"tweet-lora"}, nil, nil),
+				newPod("pod2", 2, nil, nil, nil),
+			},
+			want: []string{"pod1", "pod2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: test.targetModel}, nil, test.pods)
+			if diff := cmp.Diff(test.want, names(NewLoraCapacityFilter().Filter(ctx, test.pods))); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+
+	// The pods are all kept if they would all have to evict an adapter.
+	pods := []types.Pod{newPod("pod1", 1, []string{"tweet-lora"}, nil, nil), newPod("pod2", 1, []string{"chat-lora"}, nil, nil)}
+	snapshot := append([]types.Pod{newPod("pod3", 1, []string{"sql-lora"}, nil, nil)}, pods...)
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "sql-lora"}, nil, snapshot)
+	if diff := cmp.Diff([]string{"pod1", "pod2"}, names(NewLoraCapacityFilter().Filter(ctx, pods))); diff != "" {
+		t.Errorf("Unexpected output when all the pods are full (-want +got): %v", diff)
+	}
+}
+
+func TestPinnedPodFilter(t *testing.T) {
+	pods := []types.Pod{
+		&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}, Address: "10.0.0.1"}},
+		&types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}, Address: "10.0.0.2"}},
+	}
+	tests := []struct {
+		name   string
+		hint   string
+		strict bool
+		want   []string
+	}{
+		{name: "no hint", want: []string{"pod1", "pod2"}},
+		{name: "pinned by name", hint: "pod2", want: []string{"pod2"}},
+		{name: "pinned by namespaced name", hint: "default/pod2", want: []string{"pod2"}},
+		{name: "pinned by address", hint: "10.0.0.1", want: []string{"pod1"}},
+		{name: "pinned by address and port", hint: "10.0.0.1:8000", want: []string{"pod1"}},
+		{name: "pinned pod not a candidate", hint: "pod3", want: []string{"pod1", "pod2"}},
+		{name: "pinned pod not a candidate, strict", hint: "pod3", strict: true, want: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := &types.LLMRequest{Headers: map[string]string{}}
+			if test.hint != "" {
+				req.Headers["x-gateway-destination-endpoint-hint"] = test.hint
+			}
+			ctx := types.NewSchedulingContext(context.Background(), req, nil, pods)
+			got := []string{}
+			for _, pod := range NewPinnedPodFilter(test.strict).Filter(ctx, pods) {
+				got = append(got, pod.GetPod().NamespacedName.Name)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestPrefillAffinityFilter(t *testing.T) {
+	const groupLabel = "kv-transfer-group"
+	newPod := func(name string, labels map[string]string) types.Pod {
+		return &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}, Labels: labels}}
+	}
+	pods := []types.Pod{
+		newPod("decode-a", map[string]string{groupLabel: "a"}),
+		newPod("decode-b", map[string]string{groupLabel: "b"}),
+		newPod("decode-none", nil),
+	}
+	tests := []struct {
+		name       string
+		prefillPod types.Pod
+		strict     bool
+		want       []string
+	}{
+		{name: "no prefill pod", want: []string{"decode-a", "decode-b", "decode-none"}},
+		{name: "prefill pod without the label", prefillPod: newPod("prefill", nil), want: []string{"decode-a", "decode-b", "decode-none"}},
+		{name: "same group", prefillPod: newPod("prefill", map[string]string{groupLabel: "b"}), want: []string{"decode-b"}},
+		{name: "no pod in the group", prefillPod: newPod("prefill", map[string]string{groupLabel: "c"}), want: []string{"decode-a", "decode-b", "decode-none"}},
+		{name: "no pod in the group, strict", prefillPod: newPod("prefill", map[string]string{groupLabel: "c"}), strict: true, want: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, pods)
+			if test.prefillPod != nil {
+				ctx.ProfileResults = map[string]*types.Result{"prefill": {TargetPod: &types.ScoredPod{Pod: test.prefillPod}}}
+			}
+			got := []string{}
+			for _, pod := range NewPrefillAffinityFilter("prefill", groupLabel, test.strict).Filter(ctx, pods) {
+				got = append(got, pod.GetPod().NamespacedName.Name)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
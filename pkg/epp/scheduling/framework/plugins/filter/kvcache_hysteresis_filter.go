@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"sync"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	DefaultKVCacheHighWatermark = 0.9
+	DefaultKVCacheLowWatermark  = 0.8
+
+	// kvCacheHysteresisPruneInterval is the number of filter runs after which the excluded pods that
+	// were not candidates since the previous pruning are forgotten.
+	kvCacheHysteresisPruneInterval = 1000
+)
+
+// compile-time type assertion
+var _ framework.Filter = &KVCacheHysteresisFilter{}
+
+// NewKVCacheHysteresisFilter initializes a new KVCacheHysteresisFilter and returns its pointer.
+// The watermarks are KV cache utilizations in [0, 1]; the defaults are used if they are out of
+// range or if the low watermark is above the high watermark.
+func NewKVCacheHysteresisFilter(highWatermark, lowWatermark float64) *KVCacheHysteresisFilter {
+	if highWatermark <= 0 || highWatermark > 1 || lowWatermark < 0 || lowWatermark > highWatermark {
+		highWatermark, lowWatermark = DefaultKVCacheHighWatermark, DefaultKVCacheLowWatermark
+	}
+	return &KVCacheHysteresisFilter{
+		highWatermark: highWatermark,
+		lowWatermark:  lowWatermark,
+		excluded:      map[k8stypes.NamespacedName]uint64{},
+	}
+}
+
+// KVCacheHysteresisFilter filters out the pods whose KV cache utilization exceeds the high
+// watermark, and keeps filtering them out until their utilization falls below the low watermark.
+// Unlike LeastKVCacheFilter, which ranks the pods relative to each other, it's an absolute cutoff,
+// and the gap between the watermarks keeps a pod hovering around the cutoff from flapping in and
+// out of rotation on every metrics refresh.
+// If all the pods are filtered out, the request fails, so the filter is usually combined with
+// other filters (e.g. in a DecisionTreeFilter) when the requests must still be served.
+type KVCacheHysteresisFilter struct {
+	highWatermark float64
+	lowWatermark  float64
+
+	mu        sync.Mutex
+	excluded  map[k8stypes.NamespacedName]uint64 // pod -> the run it was last a candidate of
+	runs      uint64
+	lastPrune uint64
+}
+
+// Name returns the name of the filter.
+func (f *KVCacheHysteresisFilter) Name() string {
+	return "kv-cache-hysteresis"
+}
+
+// Filter filters out the pods over the high watermark, and the pods not yet back under the low
+// watermark.
+func (f *KVCacheHysteresisFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs++
+
+	filteredPods := []types.Pod{}
+	for _, pod := range pods {
+		name := pod.GetPod().NamespacedName
+		utilization := pod.GetMetrics().KVCacheUsagePercent
+		_, excluded := f.excluded[name]
+		switch {
+		case excluded && utilization < f.lowWatermark:
+			delete(f.excluded, name)
+			ctx.Logger.V(logutil.VERBOSE).Info("Pod back under the KV cache low watermark", "pod", name, "utilization", utilization)
+			filteredPods = append(filteredPods, pod)
+		case excluded:
+			f.excluded[name] = f.runs
+		case utilization > f.highWatermark:
+			f.excluded[name] = f.runs
+			ctx.Logger.V(logutil.VERBOSE).Info("Pod over the KV cache high watermark", "pod", name, "utilization", utilization)
+		default:
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+
+	if f.runs-f.lastPrune >= kvCacheHysteresisPruneInterval {
+		for name, lastCandidate := range f.excluded {
+			if lastCandidate <= f.lastPrune {
+				delete(f.excluded, name)
+			}
+		}
+		f.lastPrune = f.runs
+	}
+	return filteredPods
+}
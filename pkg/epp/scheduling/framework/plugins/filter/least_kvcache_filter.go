@@ -19,12 +19,13 @@ package filter
 import (
 	"math"
 
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 )
 
 // compile-time type assertion
-var _ framework.Filter = &LeastKVCacheFilter{}
+var _ framework.CacheableFilter = &LeastKVCacheFilter{}
 
 // NewLeastKVCacheFilter initializes a new LeastKVCacheFilter and returns its pointer.
 func NewLeastKVCacheFilter() *LeastKVCacheFilter {
@@ -36,6 +37,9 @@ func NewLeastKVCacheFilter() *LeastKVCacheFilter {
 // The intuition is that if there are multiple pods that share similar KV cache in the low range, we
 // should consider them all instead of the absolute minimum one. This worked better than picking the
 // least one as it gives more choices for the next filter, which on aggregate gave better results.
+// The pods of different roles aren't compared to each other, so the filter keeps the pods in the
+// first range of every role. The pods whose KV cache utilization isn't scraped (see
+// MetricsState.QueueDepthOnly) are kept.
 type LeastKVCacheFilter struct{}
 
 // Name returns the name of the filter.
@@ -43,24 +47,39 @@ func (f *LeastKVCacheFilter) Name() string {
 	return "least-KV-cache"
 }
 
+// FilterCacheKey returns an empty string, since the filter only depends on the pods.
+func (f *LeastKVCacheFilter) FilterCacheKey(req *types.LLMRequest) string {
+	return ""
+}
+
 // Filter filters out pods that doesn't meet the filter criteria.
 func (f *LeastKVCacheFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
-	filteredPods := []types.Pod{}
+	thresholds := map[backend.PodRole]float64{}
+	for role, rolePods := range podsByRole(pods) {
+		min := math.MaxFloat64
+		var max float64 = 0
 
-	min := math.MaxFloat64
-	var max float64 = 0
-
-	for _, pod := range pods {
-		if pod.GetMetrics().KVCacheUsagePercent <= min {
-			min = pod.GetMetrics().KVCacheUsagePercent
+		known := 0
+		for _, pod := range rolePods {
+			if pod.GetMetrics().QueueDepthOnly {
+				continue
+			}
+			known++
+			if pod.GetMetrics().KVCacheUsagePercent <= min {
+				min = pod.GetMetrics().KVCacheUsagePercent
+			}
+			if pod.GetMetrics().KVCacheUsagePercent >= max {
+				max = pod.GetMetrics().KVCacheUsagePercent
+			}
 		}
-		if pod.GetMetrics().KVCacheUsagePercent >= max {
-			max = pod.GetMetrics().KVCacheUsagePercent
+		if known > 0 {
+			thresholds[role] = min + (max-min)/float64(known)
 		}
 	}
 
+	filteredPods := []types.Pod{}
 	for _, pod := range pods {
-		if pod.GetMetrics().KVCacheUsagePercent >= min && pod.GetMetrics().KVCacheUsagePercent <= min+(max-min)/float64(len(pods)) {
+		if pod.GetMetrics().QueueDepthOnly || pod.GetMetrics().KVCacheUsagePercent <= thresholds[pod.GetPod().GetRole()] {
 			filteredPods = append(filteredPods, pod)
 		}
 	}
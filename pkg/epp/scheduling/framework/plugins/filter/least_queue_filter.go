@@ -19,12 +19,13 @@ package filter
 import (
 	"math"
 
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 )
 
 // compile-time type assertion
-var _ framework.Filter = &LeastQueueFilter{}
+var _ framework.CacheableFilter = &LeastQueueFilter{}
 
 // NewLeastQueueFilter initializes a new LeastQueueFilter and returns its pointer.
 func NewLeastQueueFilter() *LeastQueueFilter {
@@ -36,6 +37,8 @@ func NewLeastQueueFilter() *LeastQueueFilter {
 // The intuition is that if there are multiple pods that share similar queue size in the low range,
 // we should consider them all instead of the absolute minimum one. This worked better than picking
 // the least one as it gives more choices for the next filter, which on aggregate gave better results.
+// The pods of different roles aren't compared to each other, so the filter keeps the pods in the
+// first range of every role.
 type LeastQueueFilter struct{}
 
 // Name returns the name of the filter.
@@ -43,24 +46,32 @@ func (f *LeastQueueFilter) Name() string {
 	return "least-queue"
 }
 
+// FilterCacheKey returns an empty string, since the filter only depends on the pods.
+func (f *LeastQueueFilter) FilterCacheKey(req *types.LLMRequest) string {
+	return ""
+}
+
 // Filter filters out pods that doesn't meet the filter criteria.
 func (f *LeastQueueFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
-	filteredPods := []types.Pod{}
-
-	min := math.MaxInt
-	max := 0
+	thresholds := map[backend.PodRole]int{}
+	for role, rolePods := range podsByRole(pods) {
+		min := math.MaxInt
+		max := 0
 
-	for _, pod := range pods {
-		if pod.GetMetrics().WaitingQueueSize <= min {
-			min = pod.GetMetrics().WaitingQueueSize
-		}
-		if pod.GetMetrics().WaitingQueueSize >= max {
-			max = pod.GetMetrics().WaitingQueueSize
+		for _, pod := range rolePods {
+			if pod.GetMetrics().WaitingQueueSize <= min {
+				min = pod.GetMetrics().WaitingQueueSize
+			}
+			if pod.GetMetrics().WaitingQueueSize >= max {
+				max = pod.GetMetrics().WaitingQueueSize
+			}
 		}
+		thresholds[role] = min + (max-min)/len(rolePods)
 	}
 
+	filteredPods := []types.Pod{}
 	for _, pod := range pods {
-		if pod.GetMetrics().WaitingQueueSize >= min && pod.GetMetrics().WaitingQueueSize <= min+(max-min)/len(pods) {
+		if pod.GetMetrics().WaitingQueueSize <= thresholds[pod.GetPod().GetRole()] {
 			filteredPods = append(filteredPods, pod)
 		}
 	}
@@ -17,9 +17,6 @@ limitations under the License.
 package filter
 
 import (
-	"math/rand"
-	"time"
-
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/config"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
@@ -27,6 +24,7 @@ import (
 
 // compile-time type assertion
 var _ framework.Filter = &LoraAffinityFilter{}
+var _ framework.EnvironmentAware = &LoraAffinityFilter{}
 
 // NewLoraAffinityFilter initializes a new LoraAffinityFilter and returns its pointer.
 func NewLoraAffinityFilter() *LoraAffinityFilter {
@@ -44,6 +42,12 @@ func NewLoraAffinityFilter() *LoraAffinityFilter {
 // 3. Falling back to whatever group has pods if one group is empty
 type LoraAffinityFilter struct {
 	loraAffinityThreshold float64
+	rand                  *framework.Rand
+}
+
+// SetEnvironment sets the source of the random numbers of the filter.
+func (f *LoraAffinityFilter) SetEnvironment(env framework.Environment) {
+	f.rand = env.Rand
 }
 
 // Name returns the name of the filter.
@@ -69,13 +73,9 @@ func (f *LoraAffinityFilter) Filter(ctx *types.SchedulingContext, pods []types.P
 		}
 	}
 
-	// Use crypto/rand for better randomization in production environments
-	randSource := rand.NewSource(time.Now().UnixNano())
-	randGen := rand.New(randSource)
-
 	// If both groups have pods, use probability to select which group to return
 	if len(filtered_affinity) > 0 && len(filtered_available) > 0 {
-		if randGen.Float64() < f.loraAffinityThreshold {
+		if f.rand.Float64() < f.loraAffinityThreshold {
 			return filtered_affinity
 		}
 		return filtered_available
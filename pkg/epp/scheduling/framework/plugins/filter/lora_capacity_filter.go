@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &LoraCapacityFilter{}
+
+// NewLoraCapacityFilter initializes a new LoraCapacityFilter and returns its pointer.
+func NewLoraCapacityFilter() *LoraCapacityFilter {
+	return &LoraCapacityFilter{}
+}
+
+// LoraCapacityFilter filters out the pods that would have to evict a LoRA adapter to serve the
+// request, i.e. the pods that haven't loaded the target adapter and whose adapter slots, as
+// reported by their maximum number of adapters, are all taken by the adapters they loaded or are
+// waiting to load. The adapters a pod loaded are tracked across the metrics refreshes, since the
+// model servers keep the idle adapters loaded while they only report the active ones.
+// Unlike LoraAffinityFilter, which prefers the pods that have the adapter, it accounts for the
+// adapter slots of the pods that don't. If all the pods would have to evict an adapter, none is
+// filtered out.
+// The target model is assumed to be the base model, and the pods aren't filtered, unless it's
+// reported as an adapter by any pod of the snapshot.
+type LoraCapacityFilter struct{}
+
+// Name returns the name of the filter.
+func (f *LoraCapacityFilter) Name() string {
+	return "lora-capacity"
+}
+
+// Filter filters out the pods that would have to evict an adapter to serve the request, unless
+// all of them would.
+func (f *LoraCapacityFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	if ctx.Req == nil || !isAdapter(ctx.PodsSnapshot, ctx.Req.TargetModel) {
+		return pods
+	}
+
+	filteredPods := make([]types.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if !needsEviction(pod.GetMetrics(), ctx.Req.TargetModel) {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	if len(filteredPods) == 0 {
+		ctx.Logger.V(logutil.DEBUG).Info("All the pods would have to evict an adapter", "adapter", ctx.Req.TargetModel)
+		return pods
+	}
+	return filteredPods
+}
+
+// isAdapter returns whether any of the given pods reports the target model as an adapter.
+func isAdapter(pods []types.Pod, targetModel string) bool {
+	for _, pod := range pods {
+		if hasAdapter(pod.GetMetrics(), targetModel) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAdapter(metrics *backendmetrics.MetricsState, adapter string) bool {
+	if metrics == nil {
+		return false
+	}
+	_, active := metrics.ActiveModels[adapter]
+	_, waiting := metrics.WaitingModels[adapter]
+	_, loaded := metrics.LoadedModels[adapter]
+	return active || waiting || loaded
+}
+
+// needsEviction returns whether the pod would have to evict an adapter to load the given one. The
+// pods that don't report their maximum number of adapters never need to.
+func needsEviction(metrics *backendmetrics.MetricsState, adapter string) bool {
+	if metrics == nil || metrics.MaxActiveModels <= 0 || hasAdapter(metrics, adapter) {
+		return false
+	}
+	slots := make(map[string]bool, len(metrics.LoadedModels)+len(metrics.ActiveModels)+len(metrics.WaitingModels))
+	for model := range metrics.LoadedModels {
+		slots[model] = true
+	}
+	for model := range metrics.ActiveModels {
+		slots[model] = true
+	}
+	for model := range metrics.WaitingModels {
+		slots[model] = true
+	}
+	return len(slots) >= metrics.MaxActiveModels
+}
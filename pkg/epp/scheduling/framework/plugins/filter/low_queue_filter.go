@@ -23,7 +23,7 @@ import (
 )
 
 // compile-time type assertion
-var _ framework.Filter = &LowQueueFilter{}
+var _ framework.CacheableFilter = &LowQueueFilter{}
 
 // NewLowQueueFilter initializes a new LowQueueFilter and returns its pointer.
 func NewLowQueueFilter() *LowQueueFilter {
@@ -42,6 +42,11 @@ func (f *LowQueueFilter) Name() string {
 	return "low-queue"
 }
 
+// FilterCacheKey returns an empty string, since the filter only depends on the pods.
+func (f *LowQueueFilter) FilterCacheKey(req *types.LLMRequest) string {
+	return ""
+}
+
 // Filter filters out pods that doesn't meet the filter criteria.
 func (f *LowQueueFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
 	filteredPods := []types.Pod{}
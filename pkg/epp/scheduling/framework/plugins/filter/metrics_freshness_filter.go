@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// DefaultMetricsStalenessThreshold is the default age above which the metrics of a pod are stale.
+const DefaultMetricsStalenessThreshold = 5 * time.Second
+
+// compile-time type assertion
+var _ framework.Filter = &MetricsFreshnessFilter{}
+var _ framework.EnvironmentAware = &MetricsFreshnessFilter{}
+
+// NewMetricsFreshnessFilter initializes a new MetricsFreshnessFilter and returns its pointer.
+// A non-positive stalenessThreshold defaults to DefaultMetricsStalenessThreshold.
+func NewMetricsFreshnessFilter(stalenessThreshold time.Duration) *MetricsFreshnessFilter {
+	if stalenessThreshold <= 0 {
+		stalenessThreshold = DefaultMetricsStalenessThreshold
+	}
+	return &MetricsFreshnessFilter{
+		stalenessThreshold: stalenessThreshold,
+		now:                time.Now,
+	}
+}
+
+// MetricsFreshnessFilter filters out the pods whose metrics were not refreshed within the staleness
+// threshold, e.g. because the metrics endpoint of the model server stopped responding while the
+// pod is still Ready. Such pods are likely unhealthy, and their metrics can't be trusted by the
+// other plugins either. The pods whose metrics were never refreshed are filtered out as well.
+type MetricsFreshnessFilter struct {
+	stalenessThreshold time.Duration
+	now                func() time.Time
+}
+
+// SetEnvironment sets the clock of the filter.
+func (f *MetricsFreshnessFilter) SetEnvironment(env framework.Environment) {
+	f.now = env.Clock.Now
+}
+
+// Name returns the name of the filter.
+func (f *MetricsFreshnessFilter) Name() string {
+	return "metrics-freshness"
+}
+
+// Filter filters out the pods with stale metrics.
+func (f *MetricsFreshnessFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	now := f.now()
+	filteredPods := []types.Pod{}
+	for _, pod := range pods {
+		metrics := pod.GetMetrics()
+		if metrics == nil || now.Sub(metrics.UpdateTime) > f.stalenessThreshold {
+			var updateTime time.Time
+			if metrics != nil {
+				updateTime = metrics.UpdateTime
+			}
+			ctx.Logger.V(logutil.DEBUG).Info("Filtered out pod with stale metrics", "pod", pod.GetPod().NamespacedName,
+				"updateTime", updateTime, "stalenessThreshold", f.stalenessThreshold)
+			continue
+		}
+		filteredPods = append(filteredPods, pod)
+	}
+	return filteredPods
+}
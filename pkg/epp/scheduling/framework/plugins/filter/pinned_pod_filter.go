@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"net"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &PinnedPodFilter{}
+
+// NewPinnedPodFilter initializes a new PinnedPodFilter and returns its pointer.
+// If strict, the requests pinned to a pod that isn't a candidate fail, otherwise the hint is
+// ignored for them.
+func NewPinnedPodFilter(strict bool) *PinnedPodFilter {
+	return &PinnedPodFilter{strict: strict}
+}
+
+// PinnedPodFilter keeps only the pod the request is pinned to by the
+// x-gateway-destination-endpoint-hint header, e.g. for debugging a pod or for a prefill pod to
+// send the decode of its request to a given pod. The pod is given by name ("name" or
+// "namespace/name") or address ("ip" or "ip:port").
+// The filters before it still apply, so the pinned pod must still pass e.g. the saturation
+// filters, while the scoring is bypassed since a single candidate is left.
+// The header is trusted, so the gateway must remove it from the requests of untrusted clients.
+type PinnedPodFilter struct {
+	strict bool
+}
+
+// Name returns the name of the filter.
+func (f *PinnedPodFilter) Name() string {
+	return "pinned-pod"
+}
+
+// Filter keeps only the pinned pod if the request is pinned to one of the given pods.
+func (f *PinnedPodFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	if ctx.Req == nil {
+		return pods
+	}
+	hint := ctx.Req.Headers[requtil.DestinationEndpointHintHeaderKey]
+	if hint == "" {
+		return pods
+	}
+
+	for _, pod := range pods {
+		if podMatchesHint(pod, hint) {
+			ctx.Logger.V(logutil.VERBOSE).Info("Request pinned to a pod", "pod", pod.GetPod().NamespacedName)
+			return []types.Pod{pod}
+		}
+	}
+	ctx.Logger.V(logutil.DEFAULT).Info("Pinned pod is not a candidate for the request", "hint", hint, "strict", f.strict)
+	if f.strict {
+		return []types.Pod{}
+	}
+	return pods
+}
+
+func podMatchesHint(pod types.Pod, hint string) bool {
+	p := pod.GetPod()
+	if hint == p.NamespacedName.Name || hint == p.NamespacedName.String() || hint == p.Address {
+		return true
+	}
+	host, _, err := net.SplitHostPort(hint)
+	return err == nil && p.Address != "" && host == p.Address
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// compile-time type assertion
+var _ framework.Filter = &PrefillAffinityFilter{}
+
+// NewPrefillAffinityFilter initializes a new PrefillAffinityFilter and returns its pointer.
+// prefillProfile is the name of the profile picking the prefill pod, and labelKey the pod label the
+// decode pods must share with it, e.g. a node or a KV transfer group label. If strict, the requests
+// fail when no candidate shares the label, otherwise the affinity is ignored for them.
+func NewPrefillAffinityFilter(prefillProfile, labelKey string, strict bool) *PrefillAffinityFilter {
+	return &PrefillAffinityFilter{prefillProfile: prefillProfile, labelKey: labelKey, strict: strict}
+}
+
+// PrefillAffinityFilter keeps the pods having the same value of a label as the pod picked by the
+// prefill profile of the request, so the KV cache is transferred between close pods. It's meant for
+// the decode profile of the PrefillDecodeProfilePicker, and keeps all the pods if the prefill
+// profile didn't run before or if its pod lacks the label.
+type PrefillAffinityFilter struct {
+	prefillProfile string
+	labelKey       string
+	strict         bool
+}
+
+// Name returns the name of the filter.
+func (f *PrefillAffinityFilter) Name() string {
+	return "prefill-affinity"
+}
+
+// Filter keeps the pods sharing the label value of the prefill pod.
+func (f *PrefillAffinityFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	prefill := ctx.ProfileResults[f.prefillProfile]
+	if prefill == nil || prefill.TargetPod == nil {
+		return pods
+	}
+	value, ok := prefill.TargetPod.GetPod().Labels[f.labelKey]
+	if !ok {
+		return pods
+	}
+
+	filtered := []types.Pod{}
+	for _, pod := range pods {
+		if podValue, ok := pod.GetPod().Labels[f.labelKey]; ok && podValue == value {
+			filtered = append(filtered, pod)
+		}
+	}
+	if len(filtered) == 0 && !f.strict {
+		ctx.Logger.V(logutil.DEBUG).Info("No candidate shares the label of the prefill pod", "label", f.labelKey, "value", value)
+		return pods
+	}
+	return filtered
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"slices"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.CacheableFilter = &RoleFilter{}
+
+// NewRoleFilter initializes a new RoleFilter keeping the pods of the given roles and returns its
+// pointer.
+func NewRoleFilter(roles ...backend.PodRole) *RoleFilter {
+	return &RoleFilter{roles: roles}
+}
+
+// RoleFilter keeps the pods of the given roles (see backend.RoleLabel), e.g. the prefill pods in the
+// prefill profile of a disaggregated prefill/decode pool.
+type RoleFilter struct {
+	roles []backend.PodRole
+}
+
+// Name returns the name of the filter.
+func (f *RoleFilter) Name() string {
+	return "role"
+}
+
+// FilterCacheKey returns an empty string, since the filter only depends on the pods.
+func (f *RoleFilter) FilterCacheKey(req *types.LLMRequest) string {
+	return ""
+}
+
+// Filter filters out the pods whose role isn't one of the roles of the filter.
+func (f *RoleFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	filteredPods := []types.Pod{}
+	for _, pod := range pods {
+		if slices.Contains(f.roles, pod.GetPod().GetRole()) {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	return filteredPods
+}
+
+// podsByRole groups the given pods by role, so the filters comparing the pods to each other only
+// compare pods of the same role, whose load differs in nature (e.g. prefill pods have longer queues
+// and lower KV cache utilization than decode pods).
+func podsByRole(pods []types.Pod) map[backend.PodRole][]types.Pod {
+	res := map[backend.PodRole][]types.Pod{}
+	for _, pod := range pods {
+		role := pod.GetPod().GetRole()
+		res[role] = append(res[role], pod)
+	}
+	return res
+}
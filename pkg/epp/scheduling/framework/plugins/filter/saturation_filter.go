@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/config"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// The criticality tiers of the requests, matching the criticalities of the InferenceModels.
+const (
+	SaturationTierCritical  = "critical"
+	SaturationTierStandard  = "standard"
+	SaturationTierSheddable = "sheddable"
+)
+
+// SaturationThresholds are the limits a pod must be within to serve the requests of a tier.
+// A negative threshold doesn't limit the pods.
+type SaturationThresholds struct {
+	// QueueThreshold is the maximum number of waiting requests of the pods.
+	QueueThreshold int
+	// KVCacheThreshold is the maximum KV cache utilization of the pods, in [0, 1].
+	KVCacheThreshold float64
+}
+
+// saturated returns whether the given pod exceeds the thresholds.
+func (t SaturationThresholds) saturated(pod types.Pod) bool {
+	metrics := pod.GetMetrics()
+	return (t.QueueThreshold >= 0 && metrics.WaitingQueueSize > t.QueueThreshold) ||
+		(t.KVCacheThreshold >= 0 && metrics.KVCacheUsagePercent > t.KVCacheThreshold)
+}
+
+// SaturationTiers are the thresholds of every criticality tier.
+type SaturationTiers struct {
+	Critical  SaturationThresholds
+	Standard  SaturationThresholds
+	Sheddable SaturationThresholds
+}
+
+// DefaultSaturationTiers returns the tiers of the SheddableCapacityFilter: the critical requests
+// are never limited, and the other requests are limited by the thresholds of the scheduler config.
+func DefaultSaturationTiers() SaturationTiers {
+	limited := SaturationThresholds{
+		QueueThreshold:   config.Conf.QueueThresholdCritical,
+		KVCacheThreshold: config.Conf.KVCacheThreshold,
+	}
+	return SaturationTiers{
+		Critical:  SaturationThresholds{QueueThreshold: -1, KVCacheThreshold: -1},
+		Standard:  limited,
+		Sheddable: limited,
+	}
+}
+
+// compile-time type assertion
+var _ framework.CacheableFilter = &SaturationFilter{}
+
+// NewSaturationFilter initializes a new SaturationFilter and returns its pointer.
+func NewSaturationFilter(tiers SaturationTiers) *SaturationFilter {
+	return &SaturationFilter{tiers: tiers}
+}
+
+// SaturationFilter filters out the pods too saturated to serve the requests of the criticality
+// tier of the request, so the requests of lower criticality are shed first when the pool gets
+// saturated. Unlike the SheddableCapacityFilter, which only tells critical requests from the
+// others, the thresholds are configured per tier, e.g. to let standard requests queue deeper
+// than sheddable ones.
+// If all the pods are filtered out, the request fails and is shed.
+type SaturationFilter struct {
+	tiers SaturationTiers
+}
+
+// Name returns the name of the filter.
+func (f *SaturationFilter) Name() string {
+	return "saturation"
+}
+
+// FilterCacheKey returns the criticality tier of the request, as the filter only depends on it.
+func (f *SaturationFilter) FilterCacheKey(req *types.LLMRequest) string {
+	return saturationTier(req)
+}
+
+// Filter filters out the pods exceeding the thresholds of the tier of the request.
+func (f *SaturationFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	thresholds := f.tiers.Standard
+	switch saturationTier(ctx.Req) {
+	case SaturationTierCritical:
+		thresholds = f.tiers.Critical
+	case SaturationTierSheddable:
+		thresholds = f.tiers.Sheddable
+	}
+
+	filteredPods := []types.Pod{}
+	for _, pod := range pods {
+		if !thresholds.saturated(pod) {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	return filteredPods
+}
+
+// saturationTier returns the criticality tier of the given request. The requests of models without
+// criticality are standard.
+func saturationTier(req *types.LLMRequest) string {
+	switch {
+	case req.Critical:
+		return SaturationTierCritical
+	case req.Sheddable:
+		return SaturationTierSheddable
+	default:
+		return SaturationTierStandard
+	}
+}
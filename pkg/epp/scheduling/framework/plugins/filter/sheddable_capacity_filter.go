@@ -17,13 +17,15 @@ limitations under the License.
 package filter
 
 import (
+	"strconv"
+
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/config"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 )
 
 // compile-time type assertion
-var _ framework.Filter = &SheddableCapacityFilter{}
+var _ framework.CacheableFilter = &SheddableCapacityFilter{}
 
 // NewSheddableCapacityFilter initializes a new SheddableCapacityFilter and returns its pointer.
 func NewSheddableCapacityFilter() *SheddableCapacityFilter {
@@ -44,6 +46,12 @@ func (f *SheddableCapacityFilter) Name() string {
 	return "sheddable-capacity"
 }
 
+// FilterCacheKey returns the criticality of the request, since all the pods pass the filter for
+// critical requests.
+func (f *SheddableCapacityFilter) FilterCacheKey(req *types.LLMRequest) string {
+	return strconv.FormatBool(req.Critical)
+}
+
 // Filter filters out pods that doesn't meet the filter criteria.
 func (f *SheddableCapacityFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
 	if ctx.Req.Critical {
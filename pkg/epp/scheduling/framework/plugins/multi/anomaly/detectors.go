@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	// DefaultRepetitionMinLength is the number of characters below which the text of a response
+	// isn't analyzed for repetitions.
+	DefaultRepetitionMinLength = 200
+	// DefaultRepetitionMinDistinctRatio is the fraction of distinct n-grams below which the text of
+	// a response is repetitive. A sentence looping over the whole text has a fraction of about its
+	// length divided by the length of the text.
+	DefaultRepetitionMinDistinctRatio = 0.3
+
+	// repetitionNGramLength is the number of characters of the n-grams the text is split into.
+	repetitionNGramLength = 8
+)
+
+// Detector flags the degenerate responses of the model servers.
+type Detector interface {
+	// Name is the name of the detector, as recorded in the metrics.
+	Name() string
+	// Detect returns true if the completed response is degenerate.
+	Detect(resp *types.LLMResponse) bool
+}
+
+// DefaultDetectors returns the built-in detectors, with their default configuration.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		EmptyCompletion{},
+		Repetition{MinLength: DefaultRepetitionMinLength, MinDistinctRatio: DefaultRepetitionMinDistinctRatio},
+		Truncation{},
+	}
+}
+
+// NewDetector returns the built-in detector of the given name, with its default configuration.
+func NewDetector(name string) (Detector, error) {
+	for _, detector := range DefaultDetectors() {
+		if detector.Name() == name {
+			return detector, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown response anomaly detector %q", name)
+}
+
+// EmptyCompletion flags the successful responses without any generated text. If the text of the
+// response wasn't captured, the responses reporting no completion tokens are flagged instead. The
+// responses calling tools are ignored, as they have no text.
+type EmptyCompletion struct{}
+
+// Name returns the name of the detector.
+func (EmptyCompletion) Name() string {
+	return "empty-completion"
+}
+
+// Detect returns true if the response generated nothing.
+func (EmptyCompletion) Detect(resp *types.LLMResponse) bool {
+	if resp.Failed || resp.FinishReason == "tool_calls" || resp.FinishReason == "function_call" {
+		return false
+	}
+	if resp.TextCaptured {
+		return strings.TrimSpace(resp.Text) == ""
+	}
+	// The usage isn't reported if the prompt tokens aren't.
+	return resp.PromptTokens > 0 && resp.CompletionTokens == 0
+}
+
+// Repetition flags the responses whose text loops over the same characters, e.g. a sentence or a
+// token repeated until the maximum number of tokens, from the fraction of distinct n-grams of
+// characters in the text. It only analyzes the captured text of the responses.
+type Repetition struct {
+	// MinLength is the number of characters below which the text isn't analyzed, as the short texts
+	// don't repeat enough to tell.
+	MinLength int
+	// MinDistinctRatio is the fraction of distinct n-grams below which the text is repetitive.
+	MinDistinctRatio float64
+}
+
+// Name returns the name of the detector.
+func (Repetition) Name() string {
+	return "repetition"
+}
+
+// Detect returns true if the text of the response is repetitive.
+func (r Repetition) Detect(resp *types.LLMResponse) bool {
+	if resp.Failed || !resp.TextCaptured {
+		return false
+	}
+	runes := []rune(resp.Text)
+	if len(runes) < max(r.MinLength, repetitionNGramLength) {
+		return false
+	}
+	total := len(runes) - repetitionNGramLength + 1
+	distinct := make(map[string]struct{}, total)
+	for i := 0; i < total; i++ {
+		distinct[string(runes[i:i+repetitionNGramLength])] = struct{}{}
+	}
+	return float64(len(distinct))/float64(total) < r.MinDistinctRatio
+}
+
+// Truncation flags the successful responses that generated tokens but ended without a finish
+// reason, e.g. because the model server dropped the stream. The model servers that never report a
+// finish reason must not be checked with it.
+type Truncation struct{}
+
+// Name returns the name of the detector.
+func (Truncation) Name() string {
+	return "truncation"
+}
+
+// Detect returns true if the response ended without a finish reason.
+func (Truncation) Detect(resp *types.LLMResponse) bool {
+	return !resp.Failed && resp.FinishReason == "" && (resp.CompletionTokens > 0 || resp.Text != "")
+}
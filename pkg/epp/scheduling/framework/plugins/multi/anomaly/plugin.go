@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package anomaly flags the pods producing degenerate responses, e.g. empty, looping or truncated
+// ones, and keeps them out of the scheduling for a while. These failures are invisible to the
+// latency and queue metrics, as the degenerate responses are often the fastest ones.
+package anomaly
+
+import (
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// DefaultThreshold is the number of degenerate responses within the window that puts a pod in
+	// cooldown.
+	DefaultThreshold = 3
+	// DefaultWindow is the window the degenerate responses of a pod are counted over.
+	DefaultWindow = time.Minute
+	// DefaultCooldown is how long a pod is kept out of the scheduling once put in cooldown.
+	DefaultCooldown = 30 * time.Second
+)
+
+type Config struct {
+	// Detectors flag the degenerate responses. Empty for the built-in detectors.
+	Detectors []Detector
+	// Threshold is the number of degenerate responses within Window that puts a pod in cooldown.
+	Threshold int
+	// Window is the window the degenerate responses of a pod are counted over.
+	Window time.Duration
+	// Cooldown is how long a pod is kept out of the scheduling once put in cooldown.
+	Cooldown time.Duration
+}
+
+// compile-time type assertion
+var _ framework.Filter = &Plugin{}
+var _ framework.PostResponseComplete = &Plugin{}
+var _ framework.EnvironmentAware = &Plugin{}
+
+// Plugin runs its detectors on every completed response (PostResponseComplete), and puts the pods
+// whose responses were flagged Threshold times within Window in cooldown, during which they are
+// filtered out. A pod leaves its cooldown with a clean record, so it's put back in cooldown only if
+// it keeps producing degenerate responses.
+//
+// The pods are only filtered out while other pods are left, as a degenerate response is better than
+// none. The detectors analyzing the text of the responses need the endpoint picker to capture it
+// (see --responseTextCaptureLimit).
+type Plugin struct {
+	Config
+
+	mu        sync.Mutex
+	pods      map[k8stypes.NamespacedName]*podRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// podRecord is the record of the degenerate responses of a pod.
+type podRecord struct {
+	anomalies     []time.Time // within the window, oldest first
+	cooldownUntil time.Time
+}
+
+// New initializes a new response anomaly Plugin and returns its pointer.
+func New(config Config) *Plugin {
+	if len(config.Detectors) == 0 {
+		config.Detectors = DefaultDetectors()
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultWindow
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultCooldown
+	}
+	return &Plugin{
+		Config:    config,
+		pods:      make(map[k8stypes.NamespacedName]*podRecord),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// SetEnvironment sets the clock of the plugin.
+func (p *Plugin) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+	p.lastSweep = p.now()
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "response-anomaly"
+}
+
+// Filter filters out the pods in cooldown, unless all the pods are.
+func (p *Plugin) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	filteredPods := make([]types.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if record, ok := p.pods[pod.GetPod().NamespacedName]; !ok || !now.Before(record.cooldownUntil) {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	if len(filteredPods) == 0 && len(pods) > 0 {
+		ctx.Logger.V(logutil.DEBUG).Info("All candidate pods are in cooldown for producing degenerate responses, keeping them", "pods", len(pods))
+		return pods
+	}
+	return filteredPods
+}
+
+// PostResponse does nothing, the responses are analyzed once they completed.
+func (p *Plugin) PostResponse(*types.SchedulingContext, types.Pod) {}
+
+// PostResponseComplete runs the detectors on the response, and records it against its pod if any
+// flagged it.
+func (p *Plugin) PostResponseComplete(ctx *types.SchedulingContext, pod types.Pod) {
+	if pod == nil || ctx.Resp == nil {
+		return
+	}
+	flagged := false
+	for _, detector := range p.Detectors {
+		if detector.Detect(ctx.Resp) {
+			metrics.RecordSchedulerResponseAnomaly(detector.Name())
+			ctx.Logger.V(logutil.DEBUG).Info("Degenerate response", "pod", pod.GetPod().NamespacedName, "detector", detector.Name(), "requestId", ctx.Resp.RequestId)
+			flagged = true
+		}
+	}
+	if !flagged {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	name := pod.GetPod().NamespacedName
+	record, ok := p.pods[name]
+	if !ok {
+		record = &podRecord{}
+		p.pods[name] = record
+	}
+	if now.Before(record.cooldownUntil) {
+		// The responses scheduled before the cooldown don't extend it.
+		return
+	}
+	record.anomalies = append(record.pruned(now, p.Window), now)
+	if len(record.anomalies) >= p.Threshold {
+		record.anomalies = nil
+		record.cooldownUntil = now.Add(p.Cooldown)
+		metrics.RecordSchedulerAnomalyCooldown()
+		ctx.Logger.V(logutil.DEFAULT).Info("Pod put in cooldown for producing degenerate responses", "pod", name, "cooldown", p.Cooldown)
+	}
+	p.sweep(now)
+}
+
+// pruned returns the anomalies of the record within the window.
+func (r *podRecord) pruned(now time.Time, window time.Duration) []time.Time {
+	i := 0
+	for i < len(r.anomalies) && now.Sub(r.anomalies[i]) > window {
+		i++
+	}
+	return r.anomalies[i:]
+}
+
+// sweep forgets the pods without recent degenerate responses nor cooldown, e.g. because they left
+// the pool. They are swept at most once per window. p.mu must be held.
+func (p *Plugin) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) <= p.Window {
+		return
+	}
+	for name, record := range p.pods {
+		if record.anomalies = record.pruned(now, p.Window); len(record.anomalies) == 0 && !now.Before(record.cooldownUntil) {
+			delete(p.pods, name)
+		}
+	}
+	p.lastSweep = now
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package anomaly
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const prose = "The endpoint picker schedules every request on the pod expected to serve it best, " +
+	"from the metrics its model server reports, the prefixes it recently served, and the " +
+	"adapters it has loaded. The responses it streams back are analyzed as they complete."
+
+func TestDetectors(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *types.LLMResponse
+		want []string
+	}{
+		{
+			name: "healthy response",
+			resp: &types.LLMResponse{Text: prose, TextCaptured: true, FinishReason: "stop", PromptTokens: 10, CompletionTokens: 40},
+		},
+		{
+			name: "empty text",
+			resp: &types.LLMResponse{Text: " \n", TextCaptured: true, FinishReason: "stop", PromptTokens: 10, CompletionTokens: 1},
+			want: []string{"empty-completion"},
+		},
+		{
+			name: "no completion tokens without text",
+			resp: &types.LLMResponse{FinishReason: "stop", PromptTokens: 10},
+			want: []string{"empty-completion"},
+		},
+		{
+			name: "no usage without text",
+			resp: &types.LLMResponse{FinishReason: "stop"},
+		},
+		{
+			name: "tool call",
+			resp: &types.LLMResponse{TextCaptured: true, FinishReason: "tool_calls", PromptTokens: 10, CompletionTokens: 20},
+		},
+		{
+			name: "failed response",
+			resp: &types.LLMResponse{TextCaptured: true, Failed: true},
+		},
+		{
+			name: "repeated sentence",
+			resp: &types.LLMResponse{Text: strings.Repeat("I am sorry, I cannot do that. ", 20), TextCaptured: true, FinishReason: "length", PromptTokens: 10, CompletionTokens: 200},
+			want: []string{"repetition"},
+		},
+		{
+			name: "repeated token",
+			resp: &types.LLMResponse{Text: strings.Repeat("!", 300), TextCaptured: true, FinishReason: "length", PromptTokens: 10, CompletionTokens: 300},
+			want: []string{"repetition"},
+		},
+		{
+			name: "short repetition",
+			resp: &types.LLMResponse{Text: strings.Repeat("ha", 20), TextCaptured: true, FinishReason: "stop", PromptTokens: 10, CompletionTokens: 20},
+		},
+		{
+			name: "no finish reason",
+			resp: &types.LLMResponse{Text: prose, TextCaptured: true, PromptTokens: 10, CompletionTokens: 40},
+			want: []string{"truncation"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, detector := range DefaultDetectors() {
+				if detector.Detect(test.resp) {
+					got = append(got, detector.Name())
+				}
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	plugin := New(Config{Threshold: 2, Window: time.Minute, Cooldown: 30 * time.Second})
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2}
+
+	complete := func(pod types.Pod, text string) {
+		resp := &types.LLMResponse{Text: text, TextCaptured: true, FinishReason: "stop", PromptTokens: 10, CompletionTokens: 10}
+		plugin.PostResponseComplete(types.NewSchedulingContext(context.Background(), nil, resp, pods), pod)
+	}
+	filter := func() []types.Pod {
+		return plugin.Filter(types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model"}, nil, pods), pods)
+	}
+
+	// The degenerate responses are only counted within the window.
+	complete(pod1, "")
+	now = now.Add(2 * time.Minute)
+	complete(pod1, "")
+	complete(pod1, prose)
+	assert.Equal(t, pods, filter())
+
+	// The pod is put in cooldown once the threshold is reached within the window.
+	now = now.Add(10 * time.Second)
+	complete(pod1, "")
+	assert.Equal(t, []types.Pod{pod2}, filter())
+
+	// The pods are kept if all are in cooldown.
+	complete(pod2, "")
+	complete(pod2, "")
+	assert.Equal(t, pods, filter())
+
+	// The pods leave their cooldown with a clean record.
+	now = now.Add(31 * time.Second)
+	assert.Equal(t, pods, filter())
+	complete(pod1, "")
+	assert.Equal(t, pods, filter())
+
+	// The pods without recent degenerate responses are forgotten.
+	now = now.Add(2 * time.Minute)
+	complete(pod2, "")
+	assert.Len(t, plugin.pods, 1)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrencylimit
+
+import (
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// DefaultRequestTTL is how long a request is accounted to its pod at most, in case its end is
+	// never observed, e.g. if the endpoint picker lost track of its stream. It must cover the
+	// longest requests, as the requests running longer are no longer counted.
+	DefaultRequestTTL = 30 * time.Minute
+)
+
+type Config struct {
+	// DefaultMaxConcurrency is the maximum number of concurrent requests of the pods that neither
+	// are annotated with nor report one. Zero doesn't limit them.
+	DefaultMaxConcurrency int
+	// RequestTTL is the duration after which a request whose end wasn't observed is no longer
+	// accounted to its pod.
+	RequestTTL time.Duration
+}
+
+// compile-time type assertion
+var _ framework.Filter = &Plugin{}
+var _ framework.PostCycle = &Plugin{}
+var _ framework.RequestEnd = &Plugin{}
+var _ framework.EnvironmentAware = &Plugin{}
+
+// Plugin caps the number of concurrent requests sent to every pod, to protect the model servers
+// whose latency degrades sharply past a known concurrency, even while their queue metrics look
+// fine: the queue metrics lag behind by a refresh interval, and some model servers admit all the
+// requests as running sequences without queuing them.
+//
+// The cap of a pod is, in order of precedence, the one it's annotated with
+// (inference.networking.x-k8s.io/max-concurrency), the one its model server reports (see
+// --maxConcurrencyMetric), or DefaultMaxConcurrency. The requests are accounted to the pod picked
+// for them in PostCycle until they end (RequestEnd), so the accounting is local to the endpoint
+// picker, and the requests scheduled concurrently may exceed the cap by a few. The pods at their
+// cap are filtered out, and the request fails if no pod is left.
+type Plugin struct {
+	Config
+
+	mu        sync.Mutex
+	inFlight  map[k8stypes.NamespacedName]int
+	requests  map[string]*requestEntry // key: request ID
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type requestEntry struct {
+	pod       k8stypes.NamespacedName
+	scheduled time.Time
+}
+
+// New initializes a new concurrency limit Plugin and returns its pointer.
+func New(config Config) *Plugin {
+	if config.RequestTTL <= 0 {
+		config.RequestTTL = DefaultRequestTTL
+	}
+	return &Plugin{
+		Config:    config,
+		inFlight:  make(map[k8stypes.NamespacedName]int),
+		requests:  make(map[string]*requestEntry),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// SetEnvironment sets the clock of the plugin.
+func (p *Plugin) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+	p.lastSweep = p.now()
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "max-concurrency"
+}
+
+// maxConcurrency returns the cap of the given pod, zero if it's not capped.
+func (p *Plugin) maxConcurrency(pod types.Pod) int {
+	if limit := pod.GetPod().MaxConcurrency; limit > 0 {
+		return limit
+	}
+	if metrics := pod.GetMetrics(); metrics != nil && metrics.MaxConcurrency > 0 {
+		return metrics.MaxConcurrency
+	}
+	return p.DefaultMaxConcurrency
+}
+
+// Filter filters out the pods whose in-flight requests reached their cap.
+func (p *Plugin) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sweep()
+	filteredPods := make([]types.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if limit := p.maxConcurrency(pod); limit == 0 || p.inFlight[pod.GetPod().NamespacedName] < limit {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	if len(filteredPods) == 0 && len(pods) > 0 {
+		ctx.Logger.V(logutil.DEBUG).Info("All candidate pods reached their maximum concurrency", "pods", len(pods))
+	}
+	return filteredPods
+}
+
+// PostCycle accounts the request to the pod picked for it. A request scheduled again, e.g. when
+// the gateway retries it, is accounted to its new pod only.
+func (p *Plugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	if ctx.Req == nil || ctx.Req.RequestId == "" || res == nil || res.TargetPod == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.release(ctx.Req.RequestId)
+	pod := res.TargetPod.GetPod().NamespacedName
+	p.requests[ctx.Req.RequestId] = &requestEntry{pod: pod, scheduled: p.now()}
+	p.inFlight[pod]++
+}
+
+// PostResponse does nothing, the requests are released when they end.
+func (p *Plugin) PostResponse(*types.SchedulingContext, types.Pod) {}
+
+// RequestEnd releases the request from its pod.
+func (p *Plugin) RequestEnd(ctx *types.SchedulingContext, _ types.Pod) {
+	if ctx.Resp == nil || ctx.Resp.RequestId == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.release(ctx.Resp.RequestId)
+}
+
+// release releases the given request from its pod, if it's accounted. p.mu must be held.
+func (p *Plugin) release(requestID string) {
+	entry, ok := p.requests[requestID]
+	if !ok {
+		return
+	}
+	delete(p.requests, requestID)
+	if p.inFlight[entry.pod] <= 1 {
+		delete(p.inFlight, entry.pod)
+	} else {
+		p.inFlight[entry.pod]--
+	}
+}
+
+// sweep releases the requests accounted for longer than the TTL. It only runs once per TTL, as the
+// requests normally end long before. p.mu must be held.
+func (p *Plugin) sweep() {
+	now := p.now()
+	if now.Sub(p.lastSweep) <= p.RequestTTL {
+		return
+	}
+	for id, entry := range p.requests {
+		if now.Sub(entry.scheduled) > p.RequestTTL {
+			p.release(id)
+		}
+	}
+	p.lastSweep = now
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrencylimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestConcurrencyLimitPlugin(t *testing.T) {
+	plugin := New(Config{DefaultMaxConcurrency: 2, RequestTTL: time.Minute})
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	// pod1 is annotated with a cap of 1, pod2 reports a cap of 3, pod3 has the default cap.
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}, MaxConcurrency: 1}, MetricsState: &backendmetrics.MetricsState{MaxConcurrency: 5}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{MaxConcurrency: 3}}
+	pod3 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2, pod3}
+
+	schedule := func(requestID string, target types.Pod) []types.Pod {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", RequestId: requestID}, nil, pods)
+		filtered := plugin.Filter(ctx, pods)
+		plugin.PostCycle(ctx, &types.Result{TargetPod: target})
+		return filtered
+	}
+	end := func(requestID string) {
+		ctx := types.NewSchedulingContext(context.Background(), nil, &types.LLMResponse{RequestId: requestID}, pods)
+		plugin.RequestEnd(ctx, nil)
+	}
+
+	assert.Equal(t, pods, schedule("req-1", pod1))
+	assert.Equal(t, []types.Pod{pod2, pod3}, schedule("req-2", pod3))
+	assert.Equal(t, []types.Pod{pod2, pod3}, schedule("req-3", pod3))
+	assert.Equal(t, []types.Pod{pod2}, schedule("req-4", pod2))
+
+	// A request scheduled again is only accounted to its new pod.
+	assert.Equal(t, []types.Pod{pod2}, schedule("req-3", pod2))
+	assert.Equal(t, map[k8stypes.NamespacedName]int{{Name: "pod1"}: 1, {Name: "pod2"}: 2, {Name: "pod3"}: 1}, plugin.inFlight)
+
+	// Ended requests release their pod, unknown ones are ignored.
+	end("req-1")
+	end("req-1")
+	end("unknown")
+	assert.Equal(t, map[k8stypes.NamespacedName]int{{Name: "pod2"}: 2, {Name: "pod3"}: 1}, plugin.inFlight)
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", RequestId: "req-5"}, nil, pods)
+	assert.Equal(t, pods, plugin.Filter(ctx, pods))
+
+	// Requests whose end is never observed are released after the TTL.
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, pods, plugin.Filter(ctx, pods))
+	assert.Empty(t, plugin.inFlight)
+	assert.Empty(t, plugin.requests)
+}
+
+func TestUnlimitedByDefault(t *testing.T) {
+	plugin := New(Config{})
+	pod := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod}
+	for i := 0; i < 10; i++ {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", RequestId: string(rune('a' + i))}, nil, pods)
+		assert.Equal(t, pods, plugin.Filter(ctx, pods))
+		plugin.PostCycle(ctx, &types.Result{TargetPod: pod})
+	}
+	assert.Equal(t, 10, plugin.inFlight[pod.GetPod().NamespacedName])
+}
@@ -76,6 +76,36 @@ func (i *indexer) Add(hashes []BlockHash, server ServerID) {
 	}
 }
 
+// Entries returns the entries of the indexer, from the least to the most recently used.
+func (i *indexer) Entries() []IndexerEntry {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	entries := make([]IndexerEntry, 0, i.ll.Len())
+	for e := i.ll.Front(); e != nil; e = e.Next() {
+		v := e.Value.(*value)
+		entries = append(entries, IndexerEntry{Hash: v.hash, Server: v.server})
+	}
+	return entries
+}
+
+// Restore adds the given entries, ordered from the least to the most recently used, as less
+// recently used than the entries already in the indexer. The entries already in the indexer are
+// kept, and the least recently used of the given entries are dropped once the indexer is full.
+func (i *indexer) Restore(entries []IndexerEntry) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for j := len(entries) - 1; j >= 0 && i.ll.Len() < i.maxCacheSize; j-- {
+		hash, server := entries[j].Hash, entries[j].Server
+		if _, exists := i.check(hash, server); exists {
+			continue
+		}
+		if _, ok := i.table[hash]; !ok {
+			i.table[hash] = make(map[ServerID]*list.Element)
+		}
+		i.table[hash][server] = i.ll.PushFront(&value{server: server, hash: hash})
+	}
+}
+
 func (i *indexer) check(hash BlockHash, server ServerID) (*list.Element, bool) {
 	servers, ok := i.table[hash]
 	if !ok {
@@ -43,3 +43,22 @@ func TestIndexer_AddAndGet(t *testing.T) {
 	cache.Add([]BlockHash{BlockHash(3)}, server)
 	assert.Equal(t, 2, cache.ll.Len(), "Cache size should still be 2 after adding an entry")
 }
+
+func TestIndexer_EntriesAndRestore(t *testing.T) {
+	server1 := ServerID{Namespace: "default", Name: "server1"}
+	server2 := ServerID{Namespace: "default", Name: "server2"}
+	old := newIndexer(10)
+	old.Add([]BlockHash{1, 2}, server1)
+	old.Add([]BlockHash{1}, server2)
+	entries := old.Entries()
+	assert.Equal(t, []IndexerEntry{{Hash: 1, Server: server1}, {Hash: 2, Server: server1}, {Hash: 1, Server: server2}}, entries)
+
+	// The restored entries are less recently used than the existing ones, and the least recently
+	// used of them are dropped once the indexer is full.
+	cache := newIndexer(3)
+	cache.Add([]BlockHash{3}, server1)
+	cache.Add([]BlockHash{1}, server2)
+	cache.Restore(entries)
+	assert.Equal(t, []IndexerEntry{{Hash: 2, Server: server1}, {Hash: 3, Server: server1}, {Hash: 1, Server: server2}}, cache.Entries())
+	assert.Equal(t, map[ServerID]bool{server2: true}, cache.Get(1))
+}
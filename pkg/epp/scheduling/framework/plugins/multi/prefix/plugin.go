@@ -18,7 +18,9 @@ package prefix
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/cespare/xxhash/v2"
 	k8stypes "k8s.io/apimachinery/pkg/types"
@@ -77,6 +79,17 @@ type Plugin struct {
 type Indexer interface {
 	Get(hash BlockHash) map[ServerID]bool
 	Add(hashes []BlockHash, server ServerID)
+	// Entries returns the entries of the indexer, from the least to the most recently used.
+	Entries() []IndexerEntry
+	// Restore adds the given entries, ordered from the least to the most recently used, as less
+	// recently used than the entries already in the indexer.
+	Restore(entries []IndexerEntry)
+}
+
+// IndexerEntry is a prefix hash cached by a server.
+type IndexerEntry struct {
+	Hash   BlockHash
+	Server ServerID
 }
 
 // BlockHash is a hash of the block of request body.
@@ -116,6 +129,16 @@ func (s *schedulingContextState) Clone() types.StateData {
 // compile-time type assertion
 var _ framework.Scorer = &Plugin{}
 var _ framework.PostCycle = &Plugin{}
+var _ framework.Stateful = &Plugin{}
+
+// exportedIndex is the serialized form of the entries of the indexer. The servers are listed once,
+// and referenced by index from the entries, which are ordered from the least to the most recently
+// used.
+type exportedIndex struct {
+	Servers       []string    `json:"servers"`
+	Hashes        []BlockHash `json:"hashes"`
+	ServerIndexes []int       `json:"serverIndexes"`
+}
 
 // New initializes a new prefix Plugin and returns its pointer.
 func New(config Config) *Plugin {
@@ -145,6 +168,58 @@ func (m *Plugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
 	metrics.RecordPrefixCacheMatch(matchLen*m.HashBlockSize, total*m.HashBlockSize)
 }
 
+// ExportState exports the prefix hashes cached by the servers.
+func (m *Plugin) ExportState() (json.RawMessage, error) {
+	entries := m.indexer.Entries()
+	index := exportedIndex{
+		Servers:       []string{},
+		Hashes:        make([]BlockHash, 0, len(entries)),
+		ServerIndexes: make([]int, 0, len(entries)),
+	}
+	serverIndexes := map[ServerID]int{}
+	for _, entry := range entries {
+		i, ok := serverIndexes[entry.Server]
+		if !ok {
+			i = len(index.Servers)
+			serverIndexes[entry.Server] = i
+			index.Servers = append(index.Servers, entry.Server.String())
+		}
+		index.Hashes = append(index.Hashes, entry.Hash)
+		index.ServerIndexes = append(index.ServerIndexes, i)
+	}
+	return json.Marshal(index)
+}
+
+// ImportState imports the prefix hashes cached by the servers, as less recently used than the
+// hashes recorded since the plugin started.
+func (m *Plugin) ImportState(state json.RawMessage) error {
+	index := exportedIndex{}
+	if err := json.Unmarshal(state, &index); err != nil {
+		return err
+	}
+	if len(index.Hashes) != len(index.ServerIndexes) {
+		return fmt.Errorf("got %d hashes for %d server indexes", len(index.Hashes), len(index.ServerIndexes))
+	}
+	servers := make([]ServerID, 0, len(index.Servers))
+	for _, server := range index.Servers {
+		namespace, name, ok := strings.Cut(server, "/")
+		if !ok {
+			return fmt.Errorf("invalid server %q", server)
+		}
+		servers = append(servers, ServerID{Namespace: namespace, Name: name})
+	}
+	entries := make([]IndexerEntry, 0, len(index.Hashes))
+	for i, hash := range index.Hashes {
+		serverIndex := index.ServerIndexes[i]
+		if serverIndex < 0 || serverIndex >= len(servers) {
+			return fmt.Errorf("invalid server index %d", serverIndex)
+		}
+		entries = append(entries, IndexerEntry{Hash: hash, Server: servers[serverIndex]})
+	}
+	m.indexer.Restore(entries)
+	return nil
+}
+
 // Score returns the scoring result for the given list of pods based on context.
 func (m *Plugin) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
 	// pre score step, hashing prompt and find longest prefix match.
@@ -157,7 +232,7 @@ func (m *Plugin) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types
 		PrefixHashes:       hashes,
 		PrefixCacheServers: m.matchLongestPrefix(ctx, hashes, numServers),
 	}
-	ctx.CycleState.Write(types.StateKey(m.Name()), state)
+	m.stateKey().Write(ctx.CycleState, state)
 	ctx.Logger.V(logutil.TRACE).Info(fmt.Sprintf("cached servers: %+v", state.PrefixCacheServers), "hashes", state.PrefixHashes)
 	// calculate the scores of pods
 	scores := make(map[types.Pod]float64, len(pods))
@@ -199,20 +274,18 @@ func (m *Plugin) matchLongestPrefix(ctx *types.SchedulingContext, hashes []Block
 	return res
 }
 
+// stateKey returns the key of the schedulingContextState of the plugin in the cycle state.
+func (m *Plugin) stateKey() types.TypedStateKey[*schedulingContextState] {
+	return types.TypedStateKey[*schedulingContextState](m.Name())
+}
+
 // getPrefixState returns the cycle state as a schedulingContextState.
 func (m *Plugin) getPrefixState(cycleState *types.CycleState) (*schedulingContextState, error) {
-	prefixStateKey := types.StateKey(m.Name())
-	state, err := cycleState.Read(prefixStateKey)
+	state, err := m.stateKey().Read(cycleState)
 	if err != nil {
-		return nil, fmt.Errorf("failed reading %q from CycleState: %w", prefixStateKey, err)
+		return nil, fmt.Errorf("failed reading %q from CycleState: %w", m.stateKey(), err)
 	}
-
-	prefixSchedulingState, ok := state.(*schedulingContextState)
-	if !ok {
-		return nil, fmt.Errorf("invalid Prefix state, got type %T", state)
-	}
-
-	return prefixSchedulingState, nil
+	return state, nil
 }
 
 // hashPrompt divides the prompt into blocks and calculate the prefix cache for each block.
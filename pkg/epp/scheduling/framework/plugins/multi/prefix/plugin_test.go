@@ -120,3 +120,34 @@ func TestPrefixPlugin(t *testing.T) {
 
 	plugin.PostCycle(ctx, &types.Result{TargetPod: pod1})
 }
+
+func TestPrefixPluginState(t *testing.T) {
+	config := Config{
+		HashBlockSize:          4,
+		MaxPrefixBlocksToMatch: DefaultMaxPrefixBlocks,
+		LRUIndexerCapacity:     DefaultLRUIndexerCapacity,
+	}
+	oldPlugin := New(config)
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}}
+	pods := []types.Pod{pod1, pod2}
+
+	req := &types.LLMRequest{TargetModel: "test-model1", Prompt: "aaaabbbb"}
+	ctx := types.NewSchedulingContext(context.Background(), req, nil, pods)
+	oldPlugin.Score(ctx, pods)
+	oldPlugin.PostCycle(ctx, &types.Result{TargetPod: pod2})
+
+	state, err := oldPlugin.ExportState()
+	assert.NoError(t, err)
+	newPlugin := New(config)
+	assert.NoError(t, newPlugin.ImportState(state))
+	assert.Equal(t, oldPlugin.indexer.Entries(), newPlugin.indexer.Entries())
+
+	// The new plugin finds the prefix cached by the old one.
+	ctx = types.NewSchedulingContext(context.Background(), req, nil, pods)
+	scores := newPlugin.Score(ctx, pods)
+	assert.Equal(t, float64(0), scores[pod1], "score for pod1")
+	assert.Equal(t, float64(1), scores[pod2], "score for pod2")
+
+	assert.Error(t, newPlugin.ImportState([]byte(`{"servers": ["default/pod1"], "hashes": [1], "serverIndexes": [1]}`)))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retryantiaffinity
+
+import (
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/ttlcache"
+)
+
+const (
+	// DefaultAttemptTTL is how long the pods picked for a request ID are remembered. Retries are
+	// issued by the gateway within the lifetime of the original request, so this only needs to
+	// cover the request timeout configured on the route.
+	DefaultAttemptTTL = 5 * time.Minute
+
+	// maxRequests bounds the number of request IDs the tried pods are remembered for.
+	maxRequests = 100000
+)
+
+type Config struct {
+	// AttemptTTL is the duration after which the pods tried for a request ID are forgotten.
+	AttemptTTL time.Duration
+}
+
+// compile-time type assertion
+var _ framework.Filter = &Plugin{}
+var _ framework.PostCycle = &Plugin{}
+var _ framework.EnvironmentAware = &Plugin{}
+
+// Plugin excludes the pods that previous attempts of the same request were sent to, so a retry
+// issued by the gateway (signaled by the x-envoy-attempt-count header) lands on a different
+// replica instead of the one that just failed.
+// The anti-affinity is soft: if every candidate pod was already tried, the candidates are returned
+// unfiltered rather than failing the retry.
+type Plugin struct {
+	Config
+
+	mu       sync.Mutex
+	attempts *ttlcache.Cache[string, map[k8stypes.NamespacedName]bool] // key: request ID
+	now      func() time.Time
+}
+
+// New initializes a new retry anti-affinity Plugin and returns its pointer.
+func New(config Config) *Plugin {
+	if config.AttemptTTL <= 0 {
+		config.AttemptTTL = DefaultAttemptTTL
+	}
+	return &Plugin{
+		Config:   config,
+		attempts: ttlcache.New[string, map[k8stypes.NamespacedName]bool](config.AttemptTTL, maxRequests),
+		now:      time.Now,
+	}
+}
+
+// SetEnvironment sets the clock of the plugin.
+func (p *Plugin) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "retry-anti-affinity"
+}
+
+// Filter filters out the pods that were already tried for this request, if the request is a retry.
+func (p *Plugin) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	if ctx.Req == nil || ctx.Req.RequestId == "" || !isRetry(ctx.Req) {
+		return pods
+	}
+	tried := p.triedPods(ctx.Req.RequestId)
+	if len(tried) == 0 {
+		return pods
+	}
+
+	filteredPods := []types.Pod{}
+	for _, pod := range pods {
+		if !tried[pod.GetPod().NamespacedName] {
+			filteredPods = append(filteredPods, pod)
+		}
+	}
+	if len(filteredPods) == 0 {
+		ctx.Logger.V(logutil.DEBUG).Info("All candidate pods were already tried for this request, ignoring retry anti-affinity",
+			"requestId", ctx.Req.RequestId)
+		return pods
+	}
+	return filteredPods
+}
+
+// PostCycle records the pod picked for the request, so following attempts can avoid it.
+func (p *Plugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	if ctx.Req == nil || ctx.Req.RequestId == "" || res == nil || res.TargetPod == nil {
+		return
+	}
+	p.recordAttempt(ctx.Req.RequestId, res.TargetPod.GetPod().NamespacedName)
+}
+
+// isRetry returns true if the gateway signaled that this is not the first attempt of the request.
+func isRetry(req *types.LLMRequest) bool {
+	attempt, err := strconv.Atoi(req.Headers[requtil.AttemptCountHeaderKey])
+	return err == nil && attempt > 1
+}
+
+func (p *Plugin) triedPods(requestID string) map[k8stypes.NamespacedName]bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pods, _ := p.attempts.Get(requestID, p.now())
+	return maps.Clone(pods)
+}
+
+func (p *Plugin) recordAttempt(requestID string, pod k8stypes.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	pods, ok := p.attempts.Get(requestID, now)
+	if !ok {
+		pods = make(map[k8stypes.NamespacedName]bool)
+	}
+	pods[pod] = true
+	p.attempts.Set(requestID, pods, now)
+}
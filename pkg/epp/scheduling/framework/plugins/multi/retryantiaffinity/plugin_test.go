@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retryantiaffinity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
+)
+
+func TestRetryAntiAffinityPlugin(t *testing.T) {
+	plugin := New(Config{AttemptTTL: time.Minute})
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2}
+
+	newCtx := func(attempt string) *types.SchedulingContext {
+		req := &types.LLMRequest{
+			TargetModel: "test-model",
+			RequestId:   "req-1",
+			Headers:     map[string]string{requtil.AttemptCountHeaderKey: attempt},
+		}
+		return types.NewSchedulingContext(context.Background(), req, nil, pods)
+	}
+
+	// The first attempt is not filtered.
+	ctx := newCtx("1")
+	assert.Equal(t, pods, plugin.Filter(ctx, pods))
+	plugin.PostCycle(ctx, &types.Result{TargetPod: pod1})
+
+	// The first retry avoids the pod of the first attempt.
+	ctx = newCtx("2")
+	assert.Equal(t, []types.Pod{pod2}, plugin.Filter(ctx, pods))
+	plugin.PostCycle(ctx, &types.Result{TargetPod: pod2})
+
+	// Once all pods were tried, the anti-affinity is ignored.
+	ctx = newCtx("3")
+	assert.Equal(t, pods, plugin.Filter(ctx, pods))
+
+	// Other requests are not affected.
+	otherReq := &types.LLMRequest{
+		TargetModel: "test-model",
+		RequestId:   "req-2",
+		Headers:     map[string]string{requtil.AttemptCountHeaderKey: "2"},
+	}
+	assert.Equal(t, pods, plugin.Filter(types.NewSchedulingContext(context.Background(), otherReq, nil, pods), pods))
+
+	// Tried pods are forgotten after the TTL.
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, pods, plugin.Filter(newCtx("2"), pods))
+	assert.Zero(t, plugin.attempts.Len())
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sessionaffinity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/ttlcache"
+)
+
+const (
+	// DefaultSessionTTL is how long a session keeps its affinity to a pod after the last request
+	// of that session was scheduled. Multi-turn conversations usually have think time of seconds
+	// to minutes between turns, and the KV-cache of an idle session gets evicted eventually anyway.
+	DefaultSessionTTL = 10 * time.Minute
+
+	// maxSessions bounds the number of sessions with an affinity.
+	maxSessions = 100000
+)
+
+type Config struct {
+	// SessionTTL is the duration of inactivity after which a session to pod mapping expires.
+	SessionTTL time.Duration
+}
+
+// compile-time type assertion
+var _ framework.Scorer = &Plugin{}
+var _ framework.PostCycle = &Plugin{}
+var _ framework.Observable = &Plugin{}
+var _ framework.Stateful = &Plugin{}
+var _ framework.EnvironmentAware = &Plugin{}
+
+// Plugin routes requests of the same session to the pod that served the previous request of
+// that session, so multi-turn conversations can reuse the KV-cache already built on that pod.
+// The session of a request is LLMRequest.SessionID, extracted from its session header or body
+// by the director.
+type Plugin struct {
+	Config
+
+	mu       sync.Mutex
+	sessions *ttlcache.Cache[string, k8stypes.NamespacedName] // key: session ID
+	now      func() time.Time
+	lookups  *prometheus.CounterVec // nil until the metrics are registered
+}
+
+// exportedSession is the serialized form of a session.
+type exportedSession struct {
+	Pod      string    `json:"pod"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// New initializes a new session affinity Plugin and returns its pointer.
+func New(config Config) *Plugin {
+	if config.SessionTTL <= 0 {
+		config.SessionTTL = DefaultSessionTTL
+	}
+	return &Plugin{
+		Config:   config,
+		sessions: ttlcache.New[string, k8stypes.NamespacedName](config.SessionTTL, maxSessions),
+		now:      time.Now,
+	}
+}
+
+// SetEnvironment sets the clock of the plugin.
+func (p *Plugin) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "session-affinity"
+}
+
+// RegisterMetrics exports the number of sessions with an affinity and the outcome of the lookups
+// of the sessions of the requests.
+func (p *Plugin) RegisterMetrics(m *metrics.PluginMetrics) {
+	m.NewGaugeFunc("sessions", "Number of sessions with an affinity to a pod, including the expired ones not dropped yet.", func() float64 {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return float64(p.sessions.Len())
+	})
+	lookups := m.NewCounterVec("lookups_total", "Counter of the lookups of the sessions of the requests, by whether the session had an affinity.", "result")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lookups = lookups
+}
+
+// ExportState exports the sessions that haven't expired.
+func (p *Plugin) ExportState() (json.RawMessage, error) {
+	p.mu.Lock()
+	sessions := make(map[string]exportedSession, p.sessions.Len())
+	p.sessions.Range(p.now(), func(id string, pod k8stypes.NamespacedName, lastUsed time.Time) {
+		sessions[id] = exportedSession{Pod: pod.String(), LastUsed: lastUsed}
+	})
+	p.mu.Unlock()
+	return json.Marshal(sessions)
+}
+
+// ImportState imports the sessions that haven't expired. A session already known keeps the pod it
+// was most recently scheduled to.
+func (p *Plugin) ImportState(state json.RawMessage) error {
+	sessions := map[string]exportedSession{}
+	if err := json.Unmarshal(state, &sessions); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for id, session := range sessions {
+		namespace, name, ok := strings.Cut(session.Pod, "/")
+		if !ok {
+			return fmt.Errorf("invalid pod %q of session %q", session.Pod, id)
+		}
+		if now.Sub(session.LastUsed) > p.SessionTTL {
+			continue
+		}
+		if lastUsed, ok := p.sessions.LastSet(id, now); ok && !lastUsed.Before(session.LastUsed) {
+			continue
+		}
+		p.sessions.Set(id, k8stypes.NamespacedName{Namespace: namespace, Name: name}, session.LastUsed)
+	}
+	return nil
+}
+
+// Score gives the highest score to the pod that served the previous request of the session and
+// zero to all others. Requests without a session, or with an expired one, get a neutral zero score.
+func (p *Plugin) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scores[pod] = 0
+	}
+
+	sessionID := p.sessionID(ctx)
+	if sessionID == "" {
+		return scores
+	}
+	target, ok := p.get(sessionID)
+	if !ok {
+		return scores
+	}
+	for _, pod := range pods {
+		if pod.GetPod().NamespacedName == target {
+			ctx.Logger.V(logutil.TRACE).Info("Found pod with session affinity", "session", sessionID, "pod", target)
+			scores[pod] = 1
+		}
+	}
+	return scores
+}
+
+// PostCycle records the pod that was picked for the request's session.
+func (p *Plugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	sessionID := p.sessionID(ctx)
+	if sessionID == "" || res == nil || res.TargetPod == nil {
+		return
+	}
+	p.set(sessionID, res.TargetPod.GetPod().NamespacedName)
+}
+
+func (p *Plugin) sessionID(ctx *types.SchedulingContext) string {
+	if ctx.Req == nil {
+		return ""
+	}
+	return ctx.Req.SessionID
+}
+
+func (p *Plugin) get(sessionID string) (k8stypes.NamespacedName, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pod, ok := p.sessions.Get(sessionID, p.now())
+	if p.lookups != nil {
+		result := "miss"
+		if ok {
+			result = "hit"
+		}
+		p.lookups.WithLabelValues(result).Inc()
+	}
+	return pod, ok
+}
+
+func (p *Plugin) set(sessionID string, pod k8stypes.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sessions.Set(sessionID, pod, p.now())
+}
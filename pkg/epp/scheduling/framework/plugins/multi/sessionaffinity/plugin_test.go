@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sessionaffinity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestSessionAffinityPlugin(t *testing.T) {
+	plugin := New(Config{SessionTTL: time.Minute})
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2}
+
+	// First request of the session has no affinity yet.
+	req := &types.LLMRequest{TargetModel: "test-model", SessionID: "thread_1"}
+	ctx := types.NewSchedulingContext(context.Background(), req, nil, pods)
+	scores := plugin.Score(ctx, pods)
+	assert.Equal(t, float64(0), scores[pod1])
+	assert.Equal(t, float64(0), scores[pod2])
+
+	plugin.PostCycle(ctx, &types.Result{TargetPod: pod2})
+
+	// Follow-up request of the same session prefers pod2.
+	scores = plugin.Score(ctx, pods)
+	assert.Equal(t, float64(0), scores[pod1])
+	assert.Equal(t, float64(1), scores[pod2])
+
+	// A different session has no affinity.
+	otherCtx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", SessionID: "thread_2"}, nil, pods)
+	scores = plugin.Score(otherCtx, pods)
+	assert.Equal(t, float64(0), scores[pod2])
+
+	// Requests without a session are never recorded.
+	noSessionCtx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model"}, nil, pods)
+	plugin.PostCycle(noSessionCtx, &types.Result{TargetPod: pod1})
+	assert.Equal(t, 1, plugin.sessions.Len())
+
+	// The affinity expires after the TTL.
+	now = now.Add(2 * time.Minute)
+	scores = plugin.Score(ctx, pods)
+	assert.Equal(t, float64(0), scores[pod2])
+	assert.Zero(t, plugin.sessions.Len())
+}
+
+func TestSessionAffinityPluginMetrics(t *testing.T) {
+	plugin := New(Config{SessionTTL: time.Minute})
+	plugin.RegisterMetrics(metrics.NewPluginMetrics(plugin.Name()))
+
+	pod := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod}
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", SessionID: "thread_1"}, nil, pods)
+
+	plugin.Score(ctx, pods)
+	plugin.PostCycle(ctx, &types.Result{TargetPod: pod})
+	plugin.Score(ctx, pods)
+	plugin.Score(ctx, pods)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(plugin.lookups.WithLabelValues("hit")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(plugin.lookups.WithLabelValues("miss")))
+}
+
+func TestSessionAffinityPluginState(t *testing.T) {
+	now := time.Now()
+	oldPlugin := New(Config{SessionTTL: time.Minute})
+	oldPlugin.now = func() time.Time { return now.Add(-2 * time.Minute) }
+
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2}
+	schedule := func(plugin *Plugin, sessionID string, pod types.Pod) *types.SchedulingContext {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", SessionID: sessionID}, nil, pods)
+		plugin.PostCycle(ctx, &types.Result{TargetPod: pod})
+		return ctx
+	}
+
+	// thread_1 expires before the export.
+	schedule(oldPlugin, "thread_1", pod1)
+	oldPlugin.now = func() time.Time { return now }
+	thread2 := schedule(oldPlugin, "thread_2", pod2)
+	thread3 := schedule(oldPlugin, "thread_3", pod2)
+
+	state, err := oldPlugin.ExportState()
+	assert.NoError(t, err)
+
+	newPlugin := New(Config{SessionTTL: time.Minute})
+	newPlugin.now = func() time.Time { return now.Add(time.Second) }
+	// thread_3 was scheduled by the new EPP since, which wins over the imported state.
+	schedule(newPlugin, "thread_3", pod1)
+	assert.NoError(t, newPlugin.ImportState(state))
+
+	assert.Equal(t, 2, newPlugin.sessions.Len())
+	assert.Equal(t, float64(1), newPlugin.Score(thread2, pods)[pod2])
+	assert.Equal(t, float64(1), newPlugin.Score(thread3, pods)[pod1])
+
+	assert.Error(t, newPlugin.ImportState([]byte(`{"thread_4": {"pod": "pod1"}}`)))
+}
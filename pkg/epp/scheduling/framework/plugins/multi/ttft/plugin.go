@@ -0,0 +1,252 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttft
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// DefaultThroughputWindow is the time constant of the exponentially decaying average of the
+	// throughput of the pods. It's long enough to smooth the bursts of responses, and short enough
+	// to follow the changes of the load.
+	DefaultThroughputWindow = 30 * time.Second
+	// DefaultRunningSequenceWeight is the delay added by every running sequence to the first token
+	// of a request, as a fraction of the time the pod takes to start a request. The running
+	// sequences share the batches with the prefill of the request, which slows it down.
+	DefaultRunningSequenceWeight = 0.1
+
+	// minObservationWindow bounds the throughput of the pods observed for a short time, which
+	// would otherwise be overestimated from a few responses.
+	minObservationWindow = time.Second
+	// fallbackThroughput is the throughput assumed when none of the pods was observed yet, in
+	// requests per second. Its value doesn't matter, as all the pods have it.
+	fallbackThroughput = 1.0
+)
+
+type Config struct {
+	// ThroughputWindow is the time constant of the average of the throughput of the pods.
+	ThroughputWindow time.Duration
+	// RunningSequenceWeight is the delay added by every running sequence to the first token of a
+	// request, as a fraction of the time the pod takes to start a request.
+	RunningSequenceWeight float64
+}
+
+// compile-time type assertion
+var _ framework.Scorer = &Plugin{}
+var _ framework.PostResponse = &Plugin{}
+var _ framework.Stateful = &Plugin{}
+var _ framework.EnvironmentAware = &Plugin{}
+
+// Plugin scores the pods inversely to the estimated time to first token of the request on them.
+// The request waits for the requests queued ahead of it to start, at the rate the pod starts
+// requests, and its prefill is slowed down by the sequences running in the same batches:
+//
+//	TTFT = (waiting + 1 + RunningSequenceWeight * running) / throughput
+//
+// The throughput of a pod is the rate of the responses it started recently, observed from the
+// responses the endpoint picker processes. A pod without waiting requests isn't saturated, so its
+// throughput is at least the average throughput of the pods, since its recent responses only
+// reflect the traffic it was sent. The scores are the ratio of the lowest estimate to the
+// estimate of the pod, which keeps the proportions of the estimates, unlike the min-max
+// normalization of the queue and KV cache scorers.
+type Plugin struct {
+	Config
+
+	mu         sync.Mutex
+	throughput map[k8stypes.NamespacedName]*decayingRate
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+// decayingRate is an exponentially decaying average of the rate of events.
+type decayingRate struct {
+	count float64 // decayed count of the events, as of last
+	first time.Time
+	last  time.Time
+}
+
+// exportedRate is the serialized form of a decayingRate.
+type exportedRate struct {
+	Count float64   `json:"count"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// New initializes a new TTFT estimate Plugin and returns its pointer.
+func New(config Config) *Plugin {
+	if config.ThroughputWindow <= 0 {
+		config.ThroughputWindow = DefaultThroughputWindow
+	}
+	if config.RunningSequenceWeight < 0 {
+		config.RunningSequenceWeight = DefaultRunningSequenceWeight
+	}
+	return &Plugin{
+		Config:     config,
+		throughput: make(map[k8stypes.NamespacedName]*decayingRate),
+		lastSweep:  time.Now(),
+		now:        time.Now,
+	}
+}
+
+// SetEnvironment sets the clock of the plugin.
+func (p *Plugin) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+	p.lastSweep = p.now()
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "ttft-estimate"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (p *Plugin) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	throughputs := p.throughputs(pods)
+	average, observed := 0.0, 0
+	for _, throughput := range throughputs {
+		if throughput > 0 {
+			average += throughput
+			observed++
+		}
+	}
+	if observed > 0 {
+		average /= float64(observed)
+	} else {
+		average = fallbackThroughput
+	}
+
+	estimates := make(map[types.Pod]float64, len(pods))
+	lowest := math.Inf(1)
+	for i, pod := range pods {
+		metrics := pod.GetMetrics()
+		throughput := throughputs[i]
+		if throughput <= 0 || (metrics.WaitingQueueSize == 0 && throughput < average) {
+			throughput = average
+		}
+		estimates[pod] = (float64(metrics.WaitingQueueSize) + 1 + p.RunningSequenceWeight*float64(metrics.RunningQueueSize)) / throughput
+		lowest = min(lowest, estimates[pod])
+	}
+
+	scores := make(map[types.Pod]float64, len(pods))
+	for pod, estimate := range estimates {
+		ctx.Logger.V(logutil.TRACE).Info("Estimated time to first token", "pod", pod.GetPod().NamespacedName, "seconds", estimate)
+		scores[pod] = lowest / estimate
+	}
+	return scores
+}
+
+// PostResponse records that the pod started a response.
+func (p *Plugin) PostResponse(ctx *types.SchedulingContext, pod types.Pod) {
+	if pod == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	name := pod.GetPod().NamespacedName
+	rate, ok := p.throughput[name]
+	if !ok {
+		rate = &decayingRate{first: now, last: now}
+		p.throughput[name] = rate
+	}
+	rate.count = rate.decayed(now, p.ThroughputWindow) + 1
+	rate.last = now
+
+	// The pods that stopped responding, e.g. because they left the pool, are forgotten once their
+	// throughput decayed away. They are swept at most once per window.
+	if now.Sub(p.lastSweep) > p.ThroughputWindow {
+		for name, rate := range p.throughput {
+			if now.Sub(rate.last) > 10*p.ThroughputWindow {
+				delete(p.throughput, name)
+			}
+		}
+		p.lastSweep = now
+	}
+}
+
+// ExportState exports the throughput of the pods.
+func (p *Plugin) ExportState() (json.RawMessage, error) {
+	p.mu.Lock()
+	rates := make(map[string]exportedRate, len(p.throughput))
+	for name, rate := range p.throughput {
+		rates[name.String()] = exportedRate{Count: rate.count, First: rate.first, Last: rate.last}
+	}
+	p.mu.Unlock()
+	return json.Marshal(rates)
+}
+
+// ImportState imports the throughput of the pods that weren't observed yet.
+func (p *Plugin) ImportState(state json.RawMessage) error {
+	rates := map[string]exportedRate{}
+	if err := json.Unmarshal(state, &rates); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for pod, rate := range rates {
+		namespace, name, ok := strings.Cut(pod, "/")
+		if !ok {
+			return fmt.Errorf("invalid pod %q", pod)
+		}
+		key := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+		if _, ok := p.throughput[key]; !ok {
+			p.throughput[key] = &decayingRate{count: rate.Count, first: rate.First, last: rate.Last}
+		}
+	}
+	return nil
+}
+
+// throughputs returns the throughput of the given pods, in requests per second, or zero for the
+// pods that weren't observed.
+func (p *Plugin) throughputs(pods []types.Pod) []float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	throughputs := make([]float64, len(pods))
+	for i, pod := range pods {
+		if rate, ok := p.throughput[pod.GetPod().NamespacedName]; ok {
+			throughputs[i] = rate.perSecond(now, p.ThroughputWindow)
+		}
+	}
+	return throughputs
+}
+
+// decayed returns the count decayed until the given time.
+func (r *decayingRate) decayed(now time.Time, window time.Duration) float64 {
+	return r.count * math.Exp(-now.Sub(r.last).Seconds()/window.Seconds())
+}
+
+// perSecond returns the rate of the events. The events are averaged over the time they were
+// observed for, while it's shorter than the window, so the rate isn't underestimated at first.
+func (r *decayingRate) perSecond(now time.Time, window time.Duration) float64 {
+	observed := max(now.Sub(r.first), minObservationWindow).Seconds()
+	effectiveWindow := window.Seconds() * (1 - math.Exp(-observed/window.Seconds()))
+	return r.decayed(now, window) / effectiveWindow
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func makePod(name string, waiting, running int) *types.PodMetrics {
+	return &types.PodMetrics{
+		Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}},
+		MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: waiting, RunningQueueSize: running},
+	}
+}
+
+func TestTTFTEstimateWithoutThroughput(t *testing.T) {
+	plugin := New(Config{RunningSequenceWeight: 0.1})
+	idle := makePod("idle", 0, 0)
+	busy := makePod("busy", 0, 10)
+	queued := makePod("queued", 3, 0)
+	pods := []types.Pod{idle, busy, queued}
+
+	// All the pods get the same throughput, so the estimates are proportional to the queues.
+	scores := plugin.Score(types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, pods), pods)
+	assert.InDelta(t, 1.0, scores[idle], 1e-9)
+	assert.InDelta(t, 0.5, scores[busy], 1e-9)
+	assert.InDelta(t, 0.25, scores[queued], 1e-9)
+}
+
+func TestTTFTEstimateWithThroughput(t *testing.T) {
+	plugin := New(Config{ThroughputWindow: 10 * time.Second, RunningSequenceWeight: 0.1})
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	fast := makePod("fast", 4, 0)
+	slow := makePod("slow", 4, 0)
+	idle := makePod("idle", 0, 0)
+	pods := []types.Pod{fast, slow, idle}
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, pods)
+
+	// The fast pod starts 4 requests per second and the slow one 1 request per second.
+	for i := range 120 {
+		now = now.Add(250 * time.Millisecond)
+		plugin.PostResponse(ctx, fast)
+		if i%4 == 0 {
+			plugin.PostResponse(ctx, slow)
+		}
+	}
+	throughputs := plugin.throughputs(pods)
+	assert.InDelta(t, 4, throughputs[0], 0.5)
+	assert.InDelta(t, 1, throughputs[1], 0.15)
+	assert.Zero(t, throughputs[2])
+
+	scores := plugin.Score(ctx, pods)
+	// The idle pod isn't saturated and gets the average throughput: 1/2.5 s vs 5/4 s and 5/1 s.
+	assert.InDelta(t, 1.0, scores[idle], 1e-9)
+	assert.InDelta(t, 0.32, scores[fast], 0.05)
+	assert.InDelta(t, 0.08, scores[slow], 0.02)
+	assert.Greater(t, scores[fast], scores[slow])
+
+	// The throughput decays once the pod stops responding, and the pod is forgotten eventually.
+	now = now.Add(11 * 10 * time.Second)
+	plugin.PostResponse(ctx, fast)
+	assert.Len(t, plugin.throughput, 1)
+}
+
+func TestTTFTEstimateState(t *testing.T) {
+	now := time.Now()
+	oldPlugin := New(Config{ThroughputWindow: 10 * time.Second})
+	oldPlugin.now = func() time.Time { return now }
+	fast := makePod("fast", 4, 0)
+	slow := makePod("slow", 4, 0)
+	pods := []types.Pod{fast, slow}
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, pods)
+	for i := range 40 {
+		now = now.Add(250 * time.Millisecond)
+		oldPlugin.PostResponse(ctx, fast)
+		if i%4 == 0 {
+			oldPlugin.PostResponse(ctx, slow)
+		}
+	}
+
+	state, err := oldPlugin.ExportState()
+	assert.NoError(t, err)
+
+	newPlugin := New(Config{ThroughputWindow: 10 * time.Second})
+	newPlugin.now = func() time.Time { return now }
+	// The slow pod was observed by the new EPP since, which wins over the imported state.
+	newPlugin.PostResponse(ctx, slow)
+	assert.NoError(t, newPlugin.ImportState(state))
+
+	assert.Equal(t, oldPlugin.throughputs([]types.Pod{fast}), newPlugin.throughputs([]types.Pod{fast}))
+	assert.Equal(t, 1.0, newPlugin.throughput[slow.GetPod().NamespacedName].count)
+
+	assert.Error(t, newPlugin.ImportState([]byte(`{"fast": {"count": 1}}`)))
+}
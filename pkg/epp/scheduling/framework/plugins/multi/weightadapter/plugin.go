@@ -0,0 +1,288 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weightadapter
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/ttlcache"
+)
+
+const (
+	DefaultMinWeight         = 1
+	DefaultMaxWeight         = 10
+	DefaultAdjustmentSamples = 100
+	DefaultTolerance         = 0.1
+
+	// pendingTTL is how long a scheduled request waits for its response to be observed.
+	pendingTTL = 5 * time.Minute
+	// maxPending bounds the number of scheduled requests waiting for their response.
+	maxPending = 100000
+)
+
+type Config struct {
+	// MinWeight and MaxWeight bound the weights of the adapted scorers.
+	MinWeight int
+	MaxWeight int
+	// AdjustmentSamples is the number of observed requests that followed the scorer preference, and
+	// of requests that did not, required before the weight of the scorer is adjusted.
+	AdjustmentSamples int
+	// Tolerance is the relative latency difference between the two groups of requests below which
+	// the weight is left unchanged.
+	Tolerance float64
+}
+
+// compile-time type assertion
+var _ framework.PostCycle = &Plugin{}
+var _ framework.PostResponse = &Plugin{}
+var _ framework.Stateful = &Plugin{}
+var _ framework.EnvironmentAware = &Plugin{}
+
+// Plugin adjusts the weights of the given scorers online, based on the observed outcome of the
+// requests. For every scorer, the latency until the response starts is tracked separately for the
+// requests sent to the pod the scorer preferred and for the requests sent elsewhere. When following
+// the scorer leads to lower latency, its weight is increased, and when it leads to higher latency
+// its weight is decreased, one step at a time and within the configured bounds.
+// The current weights are exported through the scheduler scorer weight metric.
+type Plugin struct {
+	Config
+
+	mu      sync.Mutex
+	scorers []*adaptedScorer
+	pending *ttlcache.Cache[string, *pendingRequest] // key: request ID
+	now     func() time.Time
+}
+
+type adaptedScorer struct {
+	*framework.WeightedScorer
+	followed latencyStats
+	ignored  latencyStats
+}
+
+type latencyStats struct {
+	count int
+	sum   time.Duration
+}
+
+func (s *latencyStats) mean() time.Duration {
+	return s.sum / time.Duration(s.count)
+}
+
+// exportedScorer is the serialized form of the state of an adaptedScorer.
+type exportedScorer struct {
+	Weight   int           `json:"weight"`
+	Followed exportedStats `json:"followed"`
+	Ignored  exportedStats `json:"ignored"`
+}
+
+type exportedStats struct {
+	Count int           `json:"count"`
+	Sum   time.Duration `json:"sum"`
+}
+
+type pendingRequest struct {
+	scheduledAt time.Time
+	followed    []*adaptedScorer
+	ignored     []*adaptedScorer
+}
+
+// New initializes a new weight adapter Plugin adapting the given scorers, and returns its pointer.
+func New(config Config, scorers ...*framework.WeightedScorer) *Plugin {
+	if config.MinWeight <= 0 {
+		config.MinWeight = DefaultMinWeight
+	}
+	if config.MaxWeight < config.MinWeight {
+		config.MaxWeight = max(DefaultMaxWeight, config.MinWeight)
+	}
+	if config.AdjustmentSamples <= 0 {
+		config.AdjustmentSamples = DefaultAdjustmentSamples
+	}
+	if config.Tolerance < 0 {
+		config.Tolerance = DefaultTolerance
+	}
+	p := &Plugin{
+		Config:  config,
+		pending: ttlcache.New[string, *pendingRequest](pendingTTL, maxPending),
+		now:     time.Now,
+	}
+	for _, scorer := range scorers {
+		scorer.SetWeight(min(max(scorer.Weight(), config.MinWeight), config.MaxWeight))
+		p.scorers = append(p.scorers, &adaptedScorer{WeightedScorer: scorer})
+	}
+	return p
+}
+
+// SetEnvironment sets the clock of the plugin.
+func (p *Plugin) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "weight-adapter"
+}
+
+// PostCycle records which of the adapted scorers preferred the picked pod.
+func (p *Plugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	if ctx.Req == nil || ctx.Req.RequestId == "" || res == nil || res.TargetPod == nil {
+		return
+	}
+	pending := &pendingRequest{}
+	for _, scorer := range p.scorers {
+		scores, err := framework.ScorerScoresStateKey(scorer.Name()).Read(ctx.CycleState)
+		if err != nil {
+			continue // the scorer is not part of the profile that ran.
+		}
+		followed, ok := preferred(scores, res.TargetPod)
+		if !ok {
+			continue
+		}
+		if followed {
+			pending.followed = append(pending.followed, scorer)
+		} else {
+			pending.ignored = append(pending.ignored, scorer)
+		}
+	}
+	if len(pending.followed) == 0 && len(pending.ignored) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending.scheduledAt = p.now()
+	p.pending.Set(ctx.Req.RequestId, pending, pending.scheduledAt)
+}
+
+// PostResponse records the latency of the request, and adjusts the weights of the scorers that
+// gathered enough samples.
+func (p *Plugin) PostResponse(ctx *types.SchedulingContext, pod types.Pod) {
+	if ctx.Resp == nil || ctx.Resp.RequestId == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	pending, ok := p.pending.Get(ctx.Resp.RequestId, now)
+	if !ok {
+		return
+	}
+	p.pending.Delete(ctx.Resp.RequestId)
+
+	latency := now.Sub(pending.scheduledAt)
+	for _, scorer := range pending.followed {
+		scorer.followed.count++
+		scorer.followed.sum += latency
+		p.adjust(ctx, scorer)
+	}
+	for _, scorer := range pending.ignored {
+		scorer.ignored.count++
+		scorer.ignored.sum += latency
+		p.adjust(ctx, scorer)
+	}
+}
+
+// adjust moves the weight of the scorer one step towards the group of requests with the lower
+// latency. Must be called with the lock held.
+func (p *Plugin) adjust(ctx *types.SchedulingContext, scorer *adaptedScorer) {
+	if scorer.followed.count < p.AdjustmentSamples || scorer.ignored.count < p.AdjustmentSamples {
+		return
+	}
+	followedMean, ignoredMean := scorer.followed.mean(), scorer.ignored.mean()
+	scorer.followed, scorer.ignored = latencyStats{}, latencyStats{}
+
+	weight := scorer.Weight()
+	switch {
+	case float64(followedMean) < float64(ignoredMean)*(1-p.Tolerance):
+		weight = min(weight+1, p.MaxWeight)
+	case float64(followedMean) > float64(ignoredMean)*(1+p.Tolerance):
+		weight = max(weight-1, p.MinWeight)
+	}
+	if weight != scorer.Weight() {
+		ctx.Logger.V(logutil.DEFAULT).Info("Adjusted scorer weight", "scorer", scorer.Name(), "weight", weight,
+			"followedMeanLatency", followedMean, "ignoredMeanLatency", ignoredMean)
+		scorer.SetWeight(weight)
+	}
+}
+
+// ExportState exports the weights of the adapted scorers and the latencies observed since their
+// last adjustment.
+func (p *Plugin) ExportState() (json.RawMessage, error) {
+	p.mu.Lock()
+	scorers := make(map[string]exportedScorer, len(p.scorers))
+	for _, scorer := range p.scorers {
+		scorers[scorer.Name()] = exportedScorer{
+			Weight:   scorer.Weight(),
+			Followed: exportedStats{Count: scorer.followed.count, Sum: scorer.followed.sum},
+			Ignored:  exportedStats{Count: scorer.ignored.count, Sum: scorer.ignored.sum},
+		}
+	}
+	p.mu.Unlock()
+	return json.Marshal(scorers)
+}
+
+// ImportState imports the weights and the latencies of the adapted scorers of the same name. The
+// imported weights are bounded by the configured ones.
+func (p *Plugin) ImportState(state json.RawMessage) error {
+	scorers := map[string]exportedScorer{}
+	if err := json.Unmarshal(state, &scorers); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, scorer := range p.scorers {
+		exported, ok := scorers[scorer.Name()]
+		if !ok {
+			continue
+		}
+		scorer.SetWeight(min(max(exported.Weight, p.MinWeight), p.MaxWeight))
+		scorer.followed = latencyStats{count: exported.Followed.Count, sum: exported.Followed.Sum}
+		scorer.ignored = latencyStats{count: exported.Ignored.Count, sum: exported.Ignored.Sum}
+	}
+	return nil
+}
+
+// preferred returns whether the target pod got the highest score of the scorer. The second return
+// value is false if the scorer had no preference, i.e. all pods got the same score.
+func preferred(scores framework.ScorerScores, target types.Pod) (bool, bool) {
+	targetName := target.GetPod().NamespacedName
+	var targetScore, maxScore float64
+	first, uniform := true, true
+	for pod, score := range scores {
+		if first {
+			maxScore = score
+		} else if score != maxScore {
+			uniform = false
+		}
+		maxScore = max(maxScore, score)
+		if pod.GetPod().NamespacedName == targetName {
+			targetScore = score
+		}
+		first = false
+	}
+	if uniform {
+		return false, false
+	}
+	return targetScore == maxScore, true
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weightadapter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+type fakeScorer struct {
+	name string
+}
+
+func (s *fakeScorer) Name() string { return s.name }
+
+func (s *fakeScorer) Score(_ *types.SchedulingContext, _ []types.Pod) map[types.Pod]float64 {
+	return nil
+}
+
+func TestWeightAdapterPlugin(t *testing.T) {
+	good := framework.NewWeightedScorer(&fakeScorer{name: "good"}, 5)
+	bad := framework.NewWeightedScorer(&fakeScorer{name: "bad"}, 5)
+	plugin := New(Config{MinWeight: 1, MaxWeight: 6, AdjustmentSamples: 2, Tolerance: 0.1}, good, bad)
+	now := time.Now()
+	plugin.now = func() time.Time { return now }
+
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2}
+
+	// The "good" scorer prefers pod1 and the "bad" scorer prefers pod2. Requests sent to pod1 are
+	// faster than the ones sent to pod2.
+	schedule := func(i int, target types.Pod, latency time.Duration) {
+		requestID := fmt.Sprintf("req-%d", i)
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: requestID}, nil, pods)
+		framework.ScorerScoresStateKey("good").Write(ctx.CycleState, framework.ScorerScores{pod1: 1, pod2: 0})
+		framework.ScorerScoresStateKey("bad").Write(ctx.CycleState, framework.ScorerScores{pod1: 0, pod2: 1})
+		plugin.PostCycle(ctx, &types.Result{TargetPod: target})
+
+		now = now.Add(latency)
+		respCtx := types.NewSchedulingContext(context.Background(), nil, &types.LLMResponse{RequestId: requestID}, pods)
+		plugin.PostResponse(respCtx, target)
+	}
+
+	schedule(0, pod1, 100*time.Millisecond)
+	schedule(1, pod2, time.Second)
+	schedule(2, pod1, 100*time.Millisecond)
+	// Not enough samples yet.
+	assert.Equal(t, 5, good.Weight())
+	assert.Equal(t, 5, bad.Weight())
+
+	schedule(3, pod2, time.Second)
+	assert.Equal(t, 6, good.Weight())
+	assert.Equal(t, 4, bad.Weight())
+	assert.Zero(t, plugin.pending.Len())
+
+	// The weights are bounded.
+	for i := 4; i < 20; i++ {
+		schedule(i, []types.Pod{pod1, pod2}[i%2], []time.Duration{100 * time.Millisecond, time.Second}[i%2])
+	}
+	assert.Equal(t, 6, good.Weight())
+	assert.Equal(t, 1, bad.Weight())
+}
+
+func TestWeightAdapterPluginState(t *testing.T) {
+	oldPlugin := New(Config{MinWeight: 1, MaxWeight: 10}, framework.NewWeightedScorer(&fakeScorer{name: "good"}, 8))
+	oldPlugin.scorers[0].followed = latencyStats{count: 3, sum: 3 * time.Second}
+	state, err := oldPlugin.ExportState()
+	assert.NoError(t, err)
+
+	good := framework.NewWeightedScorer(&fakeScorer{name: "good"}, 2)
+	other := framework.NewWeightedScorer(&fakeScorer{name: "other"}, 2)
+	newPlugin := New(Config{MinWeight: 1, MaxWeight: 6}, good, other)
+	assert.NoError(t, newPlugin.ImportState(state))
+
+	// The imported weight is bounded by the configured maximum, and the scorer the old plugin didn't
+	// adapt keeps its weight.
+	assert.Equal(t, 6, good.Weight())
+	assert.Equal(t, latencyStats{count: 3, sum: 3 * time.Second}, newPlugin.scorers[0].followed)
+	assert.Equal(t, 2, other.Weight())
+}
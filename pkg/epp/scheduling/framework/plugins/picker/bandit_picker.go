@@ -0,0 +1,295 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	DefaultBanditExplorationFactor = 0.5
+	DefaultBanditDiscount          = 0.99
+
+	// banditPendingTTL is how long a picked request waits for its response to be observed, and how
+	// long the pods that are no longer candidates are remembered.
+	banditPendingTTL = 5 * time.Minute
+)
+
+type BanditPickerConfig struct {
+	// ExplorationFactor scales the exploration bonus of the pods with few observations. Defaults to
+	// DefaultBanditExplorationFactor if not positive.
+	ExplorationFactor float64
+	// Discount in (0, 1] is the factor the observations of all the pods are multiplied by on every
+	// new observation, so that the old observations fade out and the pods whose latency changed
+	// are explored again. One means no discounting. Defaults to DefaultBanditDiscount if out of range.
+	Discount float64
+}
+
+// compile-time type assertion
+var _ framework.Picker = &BanditPicker{}
+var _ framework.PostResponse = &BanditPicker{}
+var _ framework.Stateful = &BanditPicker{}
+var _ framework.EnvironmentAware = &BanditPicker{}
+
+// NewBanditPicker initializes a new BanditPicker and returns its pointer.
+func NewBanditPicker(config BanditPickerConfig) *BanditPicker {
+	if config.ExplorationFactor <= 0 {
+		config.ExplorationFactor = DefaultBanditExplorationFactor
+	}
+	if config.Discount <= 0 || config.Discount > 1 {
+		config.Discount = DefaultBanditDiscount
+	}
+	return &BanditPicker{
+		BanditPickerConfig: config,
+		arms:               map[k8stypes.NamespacedName]*banditArm{},
+		pending:            map[string]*banditPending{},
+		lastSweep:          time.Now(),
+		now:                time.Now,
+	}
+}
+
+// BanditPicker treats the candidates as the arms of a multi-armed bandit, and picks them with the
+// discounted UCB (upper confidence bound) algorithm, where the reward of a pod is its observed
+// latency until the response starts, normalized by the highest latency of the candidates and
+// negated. It mostly picks the pod with the lowest latency while continually exploring the others
+// slightly, without relying on the scraped metrics, which makes it useful when the metrics are
+// stale, poor or missing. The scores of the candidates only break ties.
+// The requests that are in flight to a pod count as observations with an unknown latency, so the
+// requests arriving before the responses are observed are spread over the pods.
+// The picker learns from the responses, so it must be added to the profile with AddPlugins to be
+// registered as a PostResponse plugin as well.
+type BanditPicker struct {
+	BanditPickerConfig
+
+	mu        sync.Mutex
+	arms      map[k8stypes.NamespacedName]*banditArm
+	pending   map[string]*banditPending // key: request ID
+	lastSweep time.Time
+	now       func() time.Time
+	rand      *framework.Rand
+}
+
+type banditArm struct {
+	count         float64 // discounted number of observations
+	latencySum    float64 // discounted sum of the observed latencies, in seconds
+	inFlight      int
+	lastCandidate time.Time
+}
+
+func (a *banditArm) meanLatency() float64 {
+	return a.latencySum / a.count
+}
+
+// exportedBanditArm is the serialized form of the observations of a banditArm. The requests in
+// flight aren't exported, as their responses are observed by the endpoint picker that sent them.
+type exportedBanditArm struct {
+	Count         float64   `json:"count"`
+	LatencySum    float64   `json:"latencySum"`
+	LastCandidate time.Time `json:"lastCandidate"`
+}
+
+type banditPending struct {
+	pod      k8stypes.NamespacedName
+	pickedAt time.Time
+}
+
+// SetEnvironment sets the clock and the source of the random numbers of the plugin.
+func (p *BanditPicker) SetEnvironment(env framework.Environment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = env.Clock.Now
+	p.lastSweep = p.now()
+	p.rand = env.Rand
+}
+
+// Name returns the name of the picker.
+func (p *BanditPicker) Name() string {
+	return "bandit"
+}
+
+// Pick selects the candidate with the highest upper confidence bound of its reward. The candidates
+// that were never observed are picked first.
+func (p *BanditPicker) Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result {
+	ctx.Logger.V(logutil.DEBUG).Info(fmt.Sprintf("Selecting a pod by UCB from %d candidates: %+v", len(scoredPods), scoredPods))
+
+	candidates := slices.Clone(scoredPods)
+	slices.SortFunc(candidates, func(a, b *types.ScoredPod) int {
+		return strings.Compare(a.GetPod().NamespacedName.String(), b.GetPod().NamespacedName.String())
+	})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	unobserved := []*types.ScoredPod{}
+	total, maxLatency := 0.0, 0.0
+	for _, pod := range candidates {
+		arm, ok := p.arms[pod.GetPod().NamespacedName]
+		if !ok {
+			arm = &banditArm{}
+			p.arms[pod.GetPod().NamespacedName] = arm
+		}
+		arm.lastCandidate = now
+		if arm.count == 0 {
+			unobserved = append(unobserved, pod)
+			continue
+		}
+		total += arm.count
+		maxLatency = max(maxLatency, arm.meanLatency())
+	}
+
+	var picked *types.ScoredPod
+	if len(unobserved) > 0 {
+		// The unobserved candidates with the fewest requests in flight are tried first, at random.
+		leastInFlight := []*types.ScoredPod{}
+		minInFlight := math.MaxInt
+		for _, pod := range unobserved {
+			inFlight := p.arms[pod.GetPod().NamespacedName].inFlight
+			if inFlight < minInFlight {
+				leastInFlight, minInFlight = []*types.ScoredPod{}, inFlight
+			}
+			if inFlight == minInFlight {
+				leastInFlight = append(leastInFlight, pod)
+			}
+		}
+		picked = leastInFlight[p.rand.Intn(len(leastInFlight))]
+	} else {
+		bestBound := math.Inf(-1)
+		for _, pod := range candidates {
+			arm := p.arms[pod.GetPod().NamespacedName]
+			reward := 0.0
+			if maxLatency > 0 {
+				reward = 1 - arm.meanLatency()/maxLatency
+			}
+			bound := reward + p.ExplorationFactor*math.Sqrt(max(math.Log(total), 0)/(arm.count+float64(arm.inFlight)))
+			if bound > bestBound || (bound == bestBound && pod.Score > picked.Score) {
+				picked, bestBound = pod, bound
+			}
+		}
+	}
+
+	if ctx.Req != nil && ctx.Req.RequestId != "" {
+		p.arms[picked.GetPod().NamespacedName].inFlight++
+		p.pending[ctx.Req.RequestId] = &banditPending{pod: picked.GetPod().NamespacedName, pickedAt: now}
+	}
+	p.sweep(now)
+	return &types.Result{TargetPod: picked}
+}
+
+// PostResponse records the latency of the request as an observation of the pod it was sent to.
+func (p *BanditPicker) PostResponse(ctx *types.SchedulingContext, pod types.Pod) {
+	if ctx.Resp == nil || ctx.Resp.RequestId == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending, ok := p.pending[ctx.Resp.RequestId]
+	if !ok {
+		return
+	}
+	delete(p.pending, ctx.Resp.RequestId)
+	arm, ok := p.arms[pending.pod]
+	if !ok {
+		return
+	}
+	arm.inFlight--
+
+	latency := p.now().Sub(pending.pickedAt)
+	for _, other := range p.arms {
+		other.count *= p.Discount
+		other.latencySum *= p.Discount
+	}
+	arm.count++
+	arm.latencySum += latency.Seconds()
+	ctx.Logger.V(logutil.TRACE).Info("Observed pod latency", "pod", pending.pod, "latency", latency, "meanLatency", arm.meanLatency())
+}
+
+// ExportState exports the observations of the pods.
+func (p *BanditPicker) ExportState() (json.RawMessage, error) {
+	p.mu.Lock()
+	arms := make(map[string]exportedBanditArm, len(p.arms))
+	for pod, arm := range p.arms {
+		if arm.count > 0 {
+			arms[pod.String()] = exportedBanditArm{Count: arm.count, LatencySum: arm.latencySum, LastCandidate: arm.lastCandidate}
+		}
+	}
+	p.mu.Unlock()
+	return json.Marshal(arms)
+}
+
+// ImportState imports the observations of the pods that weren't observed yet.
+func (p *BanditPicker) ImportState(state json.RawMessage) error {
+	arms := map[string]exportedBanditArm{}
+	if err := json.Unmarshal(state, &arms); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for pod, exported := range arms {
+		namespace, name, ok := strings.Cut(pod, "/")
+		if !ok {
+			return fmt.Errorf("invalid pod %q", pod)
+		}
+		key := k8stypes.NamespacedName{Namespace: namespace, Name: name}
+		arm, ok := p.arms[key]
+		if !ok {
+			arm = &banditArm{}
+			p.arms[key] = arm
+		}
+		if arm.count == 0 {
+			arm.count = exported.Count
+			arm.latencySum = exported.LatencySum
+		}
+		if exported.LastCandidate.After(arm.lastCandidate) {
+			arm.lastCandidate = exported.LastCandidate
+		}
+	}
+	return nil
+}
+
+// sweep drops the requests whose response was never observed, and the pods that are no longer
+// candidates. Must be called with the lock held.
+func (p *BanditPicker) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) <= banditPendingTTL {
+		return
+	}
+	for id, pending := range p.pending {
+		if now.Sub(pending.pickedAt) > banditPendingTTL {
+			delete(p.pending, id)
+			if arm, ok := p.arms[pending.pod]; ok {
+				arm.inFlight--
+			}
+		}
+	}
+	for pod, arm := range p.arms {
+		if now.Sub(arm.lastCandidate) > banditPendingTTL {
+			delete(p.arms, pod)
+		}
+	}
+	p.lastSweep = now
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package picker
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
@@ -26,6 +28,7 @@ import (
 
 // compile-time type assertion
 var _ framework.Picker = &MaxScorePicker{}
+var _ framework.EnvironmentAware = &MaxScorePicker{}
 
 // NewMaxScorePicker initializes a new MaxScorePicker and returns its pointer.
 func NewMaxScorePicker() *MaxScorePicker {
@@ -36,7 +39,20 @@ func NewMaxScorePicker() *MaxScorePicker {
 
 // MaxScorePicker picks the pod with the maximum score from the list of candidates.
 type MaxScorePicker struct {
-	random *RandomPicker
+	random       *RandomPicker
+	maxFallbacks int
+}
+
+// WithFallbacks makes the picker return up to maxFallbacks fallback pods besides the target pod,
+// the ones with the highest scores first.
+func (p *MaxScorePicker) WithFallbacks(maxFallbacks int) *MaxScorePicker {
+	p.maxFallbacks = maxFallbacks
+	return p
+}
+
+// SetEnvironment sets the source of the random numbers of the picker.
+func (p *MaxScorePicker) SetEnvironment(env framework.Environment) {
+	p.random.SetEnvironment(env)
 }
 
 // Name returns the name of the picker.
@@ -59,9 +75,33 @@ func (p *MaxScorePicker) Pick(ctx *types.SchedulingContext, scoredPods []*types.
 		}
 	}
 
+	var result *types.Result
 	if len(highestScorePods) > 1 {
-		return p.random.Pick(ctx, highestScorePods) // pick randomly from the highest score pods
+		result = p.random.Pick(ctx, highestScorePods) // pick randomly from the highest score pods
+	} else {
+		result = &types.Result{TargetPod: highestScorePods[0]}
+	}
+	if p.maxFallbacks > 0 {
+		result.FallbackPods = fallbackPods(p.random.rand, scoredPods, result.TargetPod, p.maxFallbacks)
 	}
+	return result
+}
+
+// fallbackPods returns up to n candidates other than the target pod, ordered by decreasing score.
+// The candidates with the same score are ordered randomly.
+func fallbackPods(rand *framework.Rand, scoredPods []*types.ScoredPod, target types.Pod, n int) []types.Pod {
+	candidates := slices.Clone(scoredPods)
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	slices.SortStableFunc(candidates, func(a, b *types.ScoredPod) int { return cmp.Compare(b.Score, a.Score) })
 
-	return &types.Result{TargetPod: highestScorePods[0]}
+	fallbacks := []types.Pod{}
+	for _, pod := range candidates {
+		if len(fallbacks) == n {
+			break
+		}
+		if pod.GetPod().NamespacedName != target.GetPod().NamespacedName {
+			fallbacks = append(fallbacks, pod)
+		}
+	}
+	return fallbacks
 }
@@ -0,0 +1,296 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func newScoredPods(scores map[string]float64) []*types.ScoredPod {
+	pods := make([]*types.ScoredPod, 0, len(scores))
+	for name, score := range scores {
+		pods = append(pods, &types.ScoredPod{
+			Pod: &types.PodMetrics{
+				Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}},
+				MetricsState: &backendmetrics.MetricsState{},
+			},
+			Score: score,
+		})
+	}
+	return pods
+}
+
+func TestRoundRobinPicker(t *testing.T) {
+	p := NewRoundRobinPicker()
+	pick := func(model string, pods []*types.ScoredPod) string {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: model}, nil, nil)
+		return p.Pick(ctx, pods).TargetPod.GetPod().NamespacedName.Name
+	}
+
+	// The scores are ignored, and each model has its own rotation.
+	pods := newScoredPods(map[string]float64{"pod-a": 0.1, "pod-b": 0.9, "pod-c": 0.5})
+	got := []string{}
+	for range 4 {
+		got = append(got, pick("model-1", pods))
+	}
+	got = append(got, pick("model-2", pods))
+	want := []string{"pod-a", "pod-b", "pod-c", "pod-a", "pod-a"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected picks (-want +got): %s", diff)
+	}
+
+	// The cursor of the model carries on when the candidates change.
+	pods = newScoredPods(map[string]float64{"pod-a": 0.1, "pod-c": 0.5})
+	if got := pick("model-1", pods); got != "pod-a" {
+		t.Errorf("Unexpected pick: %s, want pod-a", got)
+	}
+}
+
+func TestWeightedRoundRobinPicker(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores map[string]float64
+		picks  int
+		want   []string
+	}{
+		{
+			name:   "picks interleaved in proportion to the scores",
+			scores: map[string]float64{"pod-a": 0.5, "pod-b": 0.25, "pod-c": 0.25},
+			picks:  8,
+			want:   []string{"pod-a", "pod-b", "pod-c", "pod-a", "pod-a", "pod-b", "pod-c", "pod-a"},
+		},
+		{
+			name:   "zero score is never picked",
+			scores: map[string]float64{"pod-a": 0.6, "pod-b": 0.3, "pod-c": 0},
+			picks:  3,
+			want:   []string{"pod-a", "pod-b", "pod-a"},
+		},
+		{
+			name:   "all zero scores are picked in turn",
+			scores: map[string]float64{"pod-a": 0, "pod-b": 0},
+			picks:  4,
+			want:   []string{"pod-a", "pod-b", "pod-a", "pod-b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewWeightedRoundRobinPicker()
+			pods := newScoredPods(test.scores)
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, nil)
+			got := []string{}
+			for range test.picks {
+				got = append(got, p.Pick(ctx, pods).TargetPod.GetPod().NamespacedName.Name)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected picks (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPowerOfTwoPicker(t *testing.T) {
+	newPod := func(name string, score float64, queue int) *types.ScoredPod {
+		return &types.ScoredPod{
+			Pod: &types.PodMetrics{
+				Pod:          &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}},
+				MetricsState: &backendmetrics.MetricsState{WaitingQueueSize: queue},
+			},
+			Score: score,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		pods  []*types.ScoredPod
+		want  string
+		never string
+	}{
+		{
+			name: "single candidate",
+			pods: []*types.ScoredPod{newPod("pod-a", 0.1, 0)},
+			want: "pod-a",
+		},
+		{
+			name: "higher score wins",
+			pods: []*types.ScoredPod{newPod("pod-a", 0.1, 0), newPod("pod-b", 0.9, 10)},
+			want: "pod-b",
+		},
+		{
+			name: "shorter queue breaks score ties",
+			pods: []*types.ScoredPod{newPod("pod-a", 0.5, 3), newPod("pod-b", 0.5, 1)},
+			want: "pod-b",
+		},
+		{
+			name:  "lowest score is never picked",
+			pods:  []*types.ScoredPod{newPod("pod-a", 0.1, 0), newPod("pod-b", 0.5, 0), newPod("pod-c", 0.9, 0)},
+			never: "pod-a",
+		},
+	}
+
+	p := NewPowerOfTwoPicker()
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for range 100 {
+				got := p.Pick(ctx, test.pods).TargetPod.GetPod().NamespacedName.Name
+				if test.want != "" && got != test.want {
+					t.Fatalf("Unexpected pick: %s, want %s", got, test.want)
+				}
+				if got == test.never {
+					t.Fatalf("Unexpected pick: %s", got)
+				}
+			}
+		})
+	}
+}
+
+func TestBanditPicker(t *testing.T) {
+	latencies := map[string]time.Duration{"pod-a": 100 * time.Millisecond, "pod-b": time.Second}
+	now := time.Now()
+	p := NewBanditPicker(BanditPickerConfig{})
+	p.now = func() time.Time { return now }
+	pods := newScoredPods(map[string]float64{"pod-a": 0, "pod-b": 0})
+
+	picks := map[string]int{}
+	for i := range 100 {
+		requestID := fmt.Sprintf("request-%d", i)
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: requestID}, nil, nil)
+		picked := p.Pick(ctx, pods).TargetPod
+		name := picked.GetPod().NamespacedName.Name
+		picks[name]++
+
+		now = now.Add(latencies[name])
+		ctx = types.NewSchedulingContext(context.Background(), nil, &types.LLMResponse{RequestId: requestID}, nil)
+		p.PostResponse(ctx, picked)
+
+		if i == 1 && picks["pod-a"] != 1 {
+			t.Fatalf("Expected every pod to be tried first, got picks %v", picks)
+		}
+	}
+
+	// The faster pod is exploited, while the slower pod is still explored.
+	if picks["pod-a"] < 80 || picks["pod-b"] < 2 {
+		t.Errorf("Unexpected picks: %v", picks)
+	}
+}
+
+func TestBanditPickerSpreadsInFlightRequests(t *testing.T) {
+	p := NewBanditPicker(BanditPickerConfig{})
+	pods := newScoredPods(map[string]float64{"pod-a": 0, "pod-b": 0})
+
+	// Without any response, the requests in flight spread the picks over the pods.
+	picks := map[string]int{}
+	for i := range 10 {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: fmt.Sprintf("request-%d", i)}, nil, nil)
+		picks[p.Pick(ctx, pods).TargetPod.GetPod().NamespacedName.Name]++
+	}
+	if picks["pod-a"] != 5 || picks["pod-b"] != 5 {
+		t.Errorf("Unexpected picks: %v", picks)
+	}
+}
+
+func TestBanditPickerState(t *testing.T) {
+	now := time.Now()
+	oldPicker := NewBanditPicker(BanditPickerConfig{})
+	oldPicker.now = func() time.Time { return now }
+	pods := newScoredPods(map[string]float64{"pod-a": 0, "pod-b": 0})
+	latencies := map[string]time.Duration{"pod-a": 100 * time.Millisecond, "pod-b": time.Second}
+	for i := range 20 {
+		requestID := fmt.Sprintf("request-%d", i)
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: requestID}, nil, nil)
+		picked := oldPicker.Pick(ctx, pods).TargetPod
+		now = now.Add(latencies[picked.GetPod().NamespacedName.Name])
+		ctx = types.NewSchedulingContext(context.Background(), nil, &types.LLMResponse{RequestId: requestID}, nil)
+		oldPicker.PostResponse(ctx, picked)
+	}
+
+	state, err := oldPicker.ExportState()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	newPicker := NewBanditPicker(BanditPickerConfig{})
+	if err := newPicker.ImportState(state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The new picker doesn't try every pod first, and exploits the faster pod right away.
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, nil)
+	if picked := newPicker.Pick(ctx, pods).TargetPod.GetPod().NamespacedName.Name; picked != "pod-a" {
+		t.Errorf("Expected pod-a to be picked, got %s", picked)
+	}
+	if err := newPicker.ImportState([]byte(`{"pod-a": {"count": 1}}`)); err == nil {
+		t.Error("Expected an error for an invalid pod")
+	}
+}
+
+func TestMaxScorePickerFallbacks(t *testing.T) {
+	pods := newScoredPods(map[string]float64{"pod-a": 0.2, "pod-b": 0.9, "pod-c": 0.5, "pod-d": 0.7})
+	names := func(pods []types.Pod) []string {
+		got := []string{}
+		for _, pod := range pods {
+			got = append(got, pod.GetPod().NamespacedName.Name)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name          string
+		maxFallbacks  int
+		wantFallbacks []string
+	}{
+		{
+			name:          "no fallbacks by default",
+			wantFallbacks: nil,
+		},
+		{
+			name:          "fallbacks ordered by score",
+			maxFallbacks:  2,
+			wantFallbacks: []string{"pod-d", "pod-c"},
+		},
+		{
+			name:          "fallbacks limited by the candidates",
+			maxFallbacks:  5,
+			wantFallbacks: []string{"pod-d", "pod-c", "pod-a"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, nil)
+			result := NewMaxScorePicker().WithFallbacks(test.maxFallbacks).Pick(ctx, pods)
+			if got := result.TargetPod.GetPod().NamespacedName.Name; got != "pod-b" {
+				t.Errorf("Unexpected target pod: %s, want pod-b", got)
+			}
+			var got []string
+			if result.FallbackPods != nil {
+				got = names(result.FallbackPods)
+			}
+			if diff := cmp.Diff(test.wantFallbacks, got); diff != "" {
+				t.Errorf("Unexpected fallback pods (-want +got): %s", diff)
+			}
+		})
+	}
+}
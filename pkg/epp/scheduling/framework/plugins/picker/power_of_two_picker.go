@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// compile-time type assertion
+var _ framework.Picker = &PowerOfTwoPicker{}
+var _ framework.EnvironmentAware = &PowerOfTwoPicker{}
+
+// NewPowerOfTwoPicker initializes a new PowerOfTwoPicker and returns its pointer.
+func NewPowerOfTwoPicker() *PowerOfTwoPicker {
+	return &PowerOfTwoPicker{}
+}
+
+// PowerOfTwoPicker samples two distinct candidates uniformly at random and picks the one with the
+// higher score, or the one with the shorter waiting queue if their scores are equal.
+// Unlike MaxScorePicker, it doesn't send all the requests to the max score pod until the next
+// metrics refresh, which avoids herding under high QPS, while still avoiding the worst pods.
+type PowerOfTwoPicker struct {
+	rand *framework.Rand
+}
+
+// SetEnvironment sets the source of the random numbers of the picker.
+func (p *PowerOfTwoPicker) SetEnvironment(env framework.Environment) {
+	p.rand = env.Rand
+}
+
+// Name returns the name of the picker.
+func (p *PowerOfTwoPicker) Name() string {
+	return "power-of-two"
+}
+
+// Pick selects the better of two random pods from the list of candidates.
+func (p *PowerOfTwoPicker) Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result {
+	ctx.Logger.V(logutil.DEBUG).Info(fmt.Sprintf("Selecting the better of two random pods from %d candidates: %+v", len(scoredPods), scoredPods))
+	if len(scoredPods) == 1 {
+		return &types.Result{TargetPod: scoredPods[0]}
+	}
+
+	i := p.rand.Intn(len(scoredPods))
+	j := p.rand.Intn(len(scoredPods) - 1)
+	if j >= i { // j is drawn among the candidates other than i
+		j++
+	}
+	first, second := scoredPods[i], scoredPods[j]
+	if second.Score > first.Score ||
+		(second.Score == first.Score && second.GetMetrics().WaitingQueueSize < first.GetMetrics().WaitingQueueSize) {
+		first = second
+	}
+	return &types.Result{TargetPod: first}
+}
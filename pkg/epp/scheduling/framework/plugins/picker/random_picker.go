@@ -18,7 +18,6 @@ package picker
 
 import (
 	"fmt"
-	"math/rand"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
@@ -27,6 +26,7 @@ import (
 
 // compile-time type assertion
 var _ framework.Picker = &RandomPicker{}
+var _ framework.EnvironmentAware = &RandomPicker{}
 
 // NewRandomPicker initializes a new RandomPicker and returns its pointer.
 func NewRandomPicker() *RandomPicker {
@@ -34,7 +34,14 @@ func NewRandomPicker() *RandomPicker {
 }
 
 // RandomPicker picks a random pod from the list of candidates.
-type RandomPicker struct{}
+type RandomPicker struct {
+	rand *framework.Rand
+}
+
+// SetEnvironment sets the source of the random numbers of the picker.
+func (p *RandomPicker) SetEnvironment(env framework.Environment) {
+	p.rand = env.Rand
+}
 
 // Name returns the name of the picker.
 func (p *RandomPicker) Name() string {
@@ -44,6 +51,6 @@ func (p *RandomPicker) Name() string {
 // Pick selects a random pod from the list of candidates.
 func (p *RandomPicker) Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result {
 	ctx.Logger.V(logutil.DEBUG).Info(fmt.Sprintf("Selecting a random pod from %d candidates: %+v", len(scoredPods), scoredPods))
-	i := rand.Intn(len(scoredPods))
+	i := p.rand.Intn(len(scoredPods))
 	return &types.Result{TargetPod: scoredPods[i]}
 }
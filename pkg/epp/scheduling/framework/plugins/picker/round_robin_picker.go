@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// compile-time type assertion
+var _ framework.Picker = &RoundRobinPicker{}
+
+// NewRoundRobinPicker initializes a new RoundRobinPicker and returns its pointer.
+func NewRoundRobinPicker() *RoundRobinPicker {
+	return &RoundRobinPicker{
+		cursors: map[string]uint64{},
+	}
+}
+
+// RoundRobinPicker picks the candidates in turn, regardless of their scores, keeping a separate
+// cursor for each target model. This spreads the requests of each model perfectly evenly over its
+// candidates, which is useful for evaluation or benchmark traffic.
+// The candidates are ordered by name, so the rotation is stable as long as the candidates don't
+// change.
+type RoundRobinPicker struct {
+	mu      sync.Mutex
+	cursors map[string]uint64 // target model -> number of requests picked so far
+}
+
+// Name returns the name of the picker.
+func (p *RoundRobinPicker) Name() string {
+	return "round-robin"
+}
+
+// Pick selects the next pod of the rotation of the target model of the request.
+func (p *RoundRobinPicker) Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result {
+	ctx.Logger.V(logutil.DEBUG).Info(fmt.Sprintf("Selecting the next pod in turn from %d candidates: %+v", len(scoredPods), scoredPods))
+
+	candidates := slices.Clone(scoredPods)
+	slices.SortFunc(candidates, func(a, b *types.ScoredPod) int {
+		return strings.Compare(a.GetPod().NamespacedName.String(), b.GetPod().NamespacedName.String())
+	})
+
+	model := ""
+	if ctx.Req != nil {
+		model = ctx.Req.TargetModel
+	}
+	p.mu.Lock()
+	cursor := p.cursors[model]
+	p.cursors[model] = cursor + 1
+	p.mu.Unlock()
+
+	return &types.Result{TargetPod: candidates[cursor%uint64(len(candidates))]}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package picker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+// weightedRoundRobinPruneInterval is the number of picks after which the pods that were not
+// candidates since the previous pruning are forgotten.
+const weightedRoundRobinPruneInterval = 1000
+
+// compile-time type assertion
+var _ framework.Picker = &WeightedRoundRobinPicker{}
+
+// NewWeightedRoundRobinPicker initializes a new WeightedRoundRobinPicker and returns its pointer.
+func NewWeightedRoundRobinPicker() *WeightedRoundRobinPicker {
+	return &WeightedRoundRobinPicker{
+		pods: map[k8stypes.NamespacedName]*weightedPod{},
+	}
+}
+
+// WeightedRoundRobinPicker picks the candidates in proportion to their scores, using smooth
+// weighted round-robin: every pick, the current weight of each candidate grows by its score, the
+// candidate with the highest current weight is picked, and its current weight is reduced by the
+// sum of the scores. Unlike picking randomly in proportion to the scores, the picks of a pod are
+// evenly interleaved with the picks of the others, e.g. scores of 0.5, 0.25 and 0.25 result in
+// the sequence a, b, c, a.
+// If all the candidates have a zero score, they are picked in turn.
+type WeightedRoundRobinPicker struct {
+	mu    sync.Mutex
+	pods  map[k8stypes.NamespacedName]*weightedPod
+	picks uint64
+}
+
+type weightedPod struct {
+	currentWeight float64
+	lastCandidate uint64 // the pick the pod was last a candidate of
+}
+
+// Name returns the name of the picker.
+func (p *WeightedRoundRobinPicker) Name() string {
+	return "weighted-round-robin"
+}
+
+// Pick selects a pod from the list of candidates, in proportion to their scores.
+func (p *WeightedRoundRobinPicker) Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result {
+	ctx.Logger.V(logutil.DEBUG).Info(fmt.Sprintf("Selecting a pod by weighted round-robin from %d candidates: %+v", len(scoredPods), scoredPods))
+
+	// The candidates are ordered by name, so the pods with the same current weight are picked in a
+	// stable order.
+	candidates := slices.Clone(scoredPods)
+	slices.SortFunc(candidates, func(a, b *types.ScoredPod) int {
+		return strings.Compare(a.GetPod().NamespacedName.String(), b.GetPod().NamespacedName.String())
+	})
+	allZero := !slices.ContainsFunc(candidates, func(pod *types.ScoredPod) bool { return pod.Score > 0 })
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.picks++
+
+	var picked *types.ScoredPod
+	var pickedState *weightedPod
+	totalWeight := float64(0)
+	for _, candidate := range candidates {
+		weight := max(candidate.Score, 0)
+		if allZero {
+			weight = 1
+		}
+		totalWeight += weight
+
+		name := candidate.GetPod().NamespacedName
+		state, ok := p.pods[name]
+		if !ok {
+			state = &weightedPod{}
+			p.pods[name] = state
+		}
+		state.currentWeight += weight
+		state.lastCandidate = p.picks
+		if pickedState == nil || state.currentWeight > pickedState.currentWeight {
+			picked, pickedState = candidate, state
+		}
+	}
+	pickedState.currentWeight -= totalWeight
+
+	if p.picks%weightedRoundRobinPruneInterval == 0 {
+		for name, state := range p.pods {
+			if p.picks-state.lastCandidate >= weightedRoundRobinPruneInterval {
+				delete(p.pods, name)
+			}
+		}
+	}
+
+	return &types.Result{TargetPod: picked}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profilepicker
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.ProfilePicker = &ModelProfilePicker{}
+
+// NewModelProfilePicker initializes a new ModelProfilePicker and returns its pointer.
+// modelProfiles maps target models to the name of the profile overriding the default profile for
+// the model.
+func NewModelProfilePicker(defaultProfile string, modelProfiles map[string]string) *ModelProfilePicker {
+	return &ModelProfilePicker{
+		defaultProfile: defaultProfile,
+		modelProfiles:  modelProfiles,
+	}
+}
+
+// ModelProfilePicker picks a single profile per request: the profile overriding the default
+// profile for the target model of the request if there is one, or the default profile otherwise.
+type ModelProfilePicker struct {
+	defaultProfile string
+	modelProfiles  map[string]string
+}
+
+// Name returns the name of the Profiles Picker.
+func (p *ModelProfilePicker) Name() string {
+	return "model-profiles"
+}
+
+// Pick selects the SchedulingProfiles to run from the list of candidate profiles, while taking into consideration the request properties and the
+// previously executed cycles along with their results.
+func (p *ModelProfilePicker) Pick(request *types.LLMRequest, profiles map[string]*framework.SchedulerProfile, executionResults map[string]*types.Result) map[string]*framework.SchedulerProfile {
+	if request == nil { // not scheduling a request, e.g. when running the PostResponse plugins of all profiles
+		return profiles
+	}
+	if len(executionResults) > 0 { // the profile has been executed already in previous call
+		return map[string]*framework.SchedulerProfile{}
+	}
+
+	name, ok := p.modelProfiles[request.TargetModel]
+	if !ok || profiles[name] == nil {
+		name = p.defaultProfile
+	}
+	if profile := profiles[name]; profile != nil {
+		return map[string]*framework.SchedulerProfile{name: profile}
+	}
+	return map[string]*framework.SchedulerProfile{}
+}
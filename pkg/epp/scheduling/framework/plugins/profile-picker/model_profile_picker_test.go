@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profilepicker
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestModelProfilePicker(t *testing.T) {
+	profiles := map[string]*framework.SchedulerProfile{
+		"default":     framework.NewSchedulerProfile(),
+		"round-robin": framework.NewSchedulerProfile(),
+	}
+
+	tests := []struct {
+		name             string
+		modelProfiles    map[string]string
+		request          *types.LLMRequest
+		executionResults map[string]*types.Result
+		want             []string
+	}{
+		{
+			name:          "model with an override",
+			modelProfiles: map[string]string{"eval": "round-robin"},
+			request:       &types.LLMRequest{TargetModel: "eval"},
+			want:          []string{"round-robin"},
+		},
+		{
+			name:          "model without an override",
+			modelProfiles: map[string]string{"eval": "round-robin"},
+			request:       &types.LLMRequest{TargetModel: "chat"},
+			want:          []string{"default"},
+		},
+		{
+			name:          "override with an unknown profile",
+			modelProfiles: map[string]string{"eval": "unknown"},
+			request:       &types.LLMRequest{TargetModel: "eval"},
+			want:          []string{"default"},
+		},
+		{
+			name:             "profile executed already",
+			modelProfiles:    map[string]string{"eval": "round-robin"},
+			request:          &types.LLMRequest{TargetModel: "eval"},
+			executionResults: map[string]*types.Result{"round-robin": {}},
+			want:             []string{},
+		},
+		{
+			name:          "no request",
+			modelProfiles: map[string]string{"eval": "round-robin"},
+			want:          []string{"default", "round-robin"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewModelProfilePicker("default", test.modelProfiles)
+			got := []string{}
+			for name := range p.Pick(test.request, profiles, test.executionResults) {
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected profiles (-want +got): %s", diff)
+			}
+		})
+	}
+}
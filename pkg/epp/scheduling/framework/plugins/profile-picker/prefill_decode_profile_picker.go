@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profilepicker
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	DefaultPrefillProfile = "prefill"
+	DefaultDecodeProfile  = "decode"
+)
+
+// compile-time type assertion
+var _ framework.ProfilePicker = &PrefillDecodeProfilePicker{}
+
+// NewPrefillDecodeProfilePicker initializes a new PrefillDecodeProfilePicker and returns its pointer.
+// The profile names default to DefaultPrefillProfile and DefaultDecodeProfile if empty.
+func NewPrefillDecodeProfilePicker(prefillProfile, decodeProfile string) *PrefillDecodeProfilePicker {
+	if prefillProfile == "" {
+		prefillProfile = DefaultPrefillProfile
+	}
+	if decodeProfile == "" {
+		decodeProfile = DefaultDecodeProfile
+	}
+	return &PrefillDecodeProfilePicker{
+		prefillProfile: prefillProfile,
+		decodeProfile:  decodeProfile,
+	}
+}
+
+// PrefillDecodeProfilePicker picks the profiles of a disaggregated serving deployment in sequence:
+// the prefill profile first to pick the prefill worker of the request, then the decode profile,
+// whose plugins can read the picked prefill pod from the ProfileResults of the SchedulingContext,
+// e.g. to keep the decode workers sharing a node or a KV transfer group with it (see
+// filter.PrefillAffinityFilter). If the prefill profile doesn't exist, only the decode profile runs.
+type PrefillDecodeProfilePicker struct {
+	prefillProfile string
+	decodeProfile  string
+}
+
+// Name returns the name of the Profiles Picker.
+func (p *PrefillDecodeProfilePicker) Name() string {
+	return "prefill-decode"
+}
+
+// Pick selects the SchedulingProfiles to run from the list of candidate profiles, while taking into consideration the request properties and the
+// previously executed cycles along with their results.
+func (p *PrefillDecodeProfilePicker) Pick(request *types.LLMRequest, profiles map[string]*framework.SchedulerProfile, executionResults map[string]*types.Result) map[string]*framework.SchedulerProfile {
+	if request == nil { // not scheduling a request, e.g. when running the PostResponse plugins of all profiles
+		return profiles
+	}
+	if _, ok := executionResults[p.decodeProfile]; ok { // the decode profile ran, so the request is scheduled
+		return map[string]*framework.SchedulerProfile{}
+	}
+	if prefill := profiles[p.prefillProfile]; prefill != nil && executionResults[p.prefillProfile] == nil {
+		return map[string]*framework.SchedulerProfile{p.prefillProfile: prefill}
+	}
+	if decode := profiles[p.decodeProfile]; decode != nil {
+		return map[string]*framework.SchedulerProfile{p.decodeProfile: decode}
+	}
+	return map[string]*framework.SchedulerProfile{}
+}
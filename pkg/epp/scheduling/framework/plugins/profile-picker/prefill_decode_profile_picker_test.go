@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profilepicker
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestPrefillDecodeProfilePicker(t *testing.T) {
+	profiles := map[string]*framework.SchedulerProfile{
+		"prefill": framework.NewSchedulerProfile(),
+		"decode":  framework.NewSchedulerProfile(),
+	}
+	decodeOnly := map[string]*framework.SchedulerProfile{"decode": profiles["decode"]}
+
+	tests := []struct {
+		name             string
+		request          *types.LLMRequest
+		profiles         map[string]*framework.SchedulerProfile
+		executionResults map[string]*types.Result
+		want             []string
+	}{
+		{
+			name:     "prefill first",
+			request:  &types.LLMRequest{},
+			profiles: profiles,
+			want:     []string{"prefill"},
+		},
+		{
+			name:             "decode after prefill",
+			request:          &types.LLMRequest{},
+			profiles:         profiles,
+			executionResults: map[string]*types.Result{"prefill": {}},
+			want:             []string{"decode"},
+		},
+		{
+			name:             "done after decode",
+			request:          &types.LLMRequest{},
+			profiles:         profiles,
+			executionResults: map[string]*types.Result{"prefill": {}, "decode": {}},
+			want:             []string{},
+		},
+		{
+			name:     "decode only without a prefill profile",
+			request:  &types.LLMRequest{},
+			profiles: decodeOnly,
+			want:     []string{"decode"},
+		},
+		{
+			name:     "no request",
+			profiles: profiles,
+			want:     []string{"decode", "prefill"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewPrefillDecodeProfilePicker("", "")
+			got := []string{}
+			for name := range p.Pick(test.request, test.profiles, test.executionResults) {
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected profiles (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profilepicker
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.ProfilePicker = &SingleProfilePicker{}
+
+// NewSingleProfilePicker initializes a new SingleProfilePicker picking the given profile and returns its pointer.
+func NewSingleProfilePicker(profileName string) *SingleProfilePicker {
+	return &SingleProfilePicker{profileName: profileName}
+}
+
+// SingleProfilePicker picks a single profile, once. It picks no profile if there is no profile with its name.
+type SingleProfilePicker struct {
+	profileName string
+}
+
+// Name returns the name of the Profiles Picker.
+func (p *SingleProfilePicker) Name() string {
+	return "single-profile"
+}
+
+// Pick selects the SchedulingProfiles to run from the list of candidate profiles, while taking into consideration the request properties and the
+// previously executed cycles along with their results.
+func (p *SingleProfilePicker) Pick(_ *types.LLMRequest, profiles map[string]*framework.SchedulerProfile, executionResults map[string]*types.Result) map[string]*framework.SchedulerProfile {
+	profile, ok := profiles[p.profileName]
+	if _, executed := executionResults[p.profileName]; executed || !ok {
+		return map[string]*framework.SchedulerProfile{}
+	}
+	return map[string]*framework.SchedulerProfile{p.profileName: profile}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profilepicker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestSingleProfilePicker(t *testing.T) {
+	profiles := map[string]*framework.SchedulerProfile{
+		"default":     framework.NewSchedulerProfile(),
+		"round-robin": framework.NewSchedulerProfile(),
+	}
+
+	tests := []struct {
+		name             string
+		profile          string
+		executionResults map[string]*types.Result
+		want             []string
+	}{
+		{
+			name:    "profile picked",
+			profile: "round-robin",
+			want:    []string{"round-robin"},
+		},
+		{
+			name:             "profile executed already",
+			profile:          "round-robin",
+			executionResults: map[string]*types.Result{"round-robin": {}},
+			want:             []string{},
+		},
+		{
+			name:    "unknown profile",
+			profile: "unknown",
+			want:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewSingleProfilePicker(test.profile)
+			got := []string{}
+			for name := range p.Pick(&types.LLMRequest{}, profiles, test.executionResults) {
+				got = append(got, name)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected profiles (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/config"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	// idlePodScore is the score of pods that are not serving any request. It's lower than the score
+	// of any active pod with spare capacity, and higher than the score of saturated pods.
+	idlePodScore = 0.1
+	// minActivePodScore is the score of an active pod with idle GPUs, or an empty KV-cache.
+	minActivePodScore = 0.2
+	// defaultGPUUtilizationThreshold is the GPU utilization above which a pod is saturated.
+	defaultGPUUtilizationThreshold = 0.9
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &BinPackingScorer{}
+
+// NewBinPackingScorer initializes a new BinPackingScorer and returns its pointer.
+func NewBinPackingScorer() *BinPackingScorer {
+	return &BinPackingScorer{
+		kvCacheThreshold:        config.Conf.KVCacheThreshold,
+		gpuUtilizationThreshold: defaultGPUUtilizationThreshold,
+	}
+}
+
+// BinPackingScorer scores list of candidate pods to fill already active pods to high (but not
+// saturated) utilization before sending requests to idle ones. This is the opposite of the
+// load-spreading QueueScorer and KVCacheScorer: it optimizes for energy efficiency and lets
+// autoscalers scale the idle pods down.
+// Pods are scored in the following order:
+//  1. Active pods with spare capacity, the more utilized the higher.
+//  2. Idle pods.
+//  3. Saturated pods, that have waiting requests or reached the KV-cache or GPU utilization
+//     threshold.
+//
+// The utilization is the GPU utilization of the pods whose GPU metrics are scraped, and their
+// KV-cache usage otherwise.
+type BinPackingScorer struct {
+	kvCacheThreshold        float64
+	gpuUtilizationThreshold float64
+}
+
+// Name returns the name of the scorer.
+func (s *BinPackingScorer) Name() string {
+	return "bin-packing"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *BinPackingScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scores[pod] = s.podScore(pod.GetMetrics())
+	}
+	return scores
+}
+
+func (s *BinPackingScorer) podScore(metrics *backendmetrics.MetricsState) float64 {
+	utilization, threshold := metrics.KVCacheUsagePercent, s.kvCacheThreshold
+	if !metrics.GPUMetricsUpdateTime.IsZero() {
+		utilization, threshold = metrics.GPUUtilization, s.gpuUtilizationThreshold
+	}
+	switch {
+	case metrics.WaitingQueueSize > 0 || metrics.KVCacheUsagePercent >= s.kvCacheThreshold || utilization >= threshold:
+		return 0
+	case metrics.RunningQueueSize == 0 && utilization == 0:
+		return idlePodScore
+	default:
+		return minActivePodScore + (1-minActivePodScore)*utilization/threshold
+	}
+}
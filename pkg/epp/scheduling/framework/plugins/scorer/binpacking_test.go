@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestBinPackingScorer(t *testing.T) {
+	tests := []struct {
+		name              string
+		pods              []types.Pod
+		expectedScoresPod map[int]float64 // Map of pod index to expected score
+	}{
+		{
+			name: "Active pods are preferred over idle and saturated pods",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 2, KVCacheUsagePercent: 0.4}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 2, KVCacheUsagePercent: 0.9}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 2, WaitingQueueSize: 1, KVCacheUsagePercent: 0.2}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.1, // Idle pod
+				1: 0.6, // Active pod at half of the KV cache threshold (0.2+0.8*0.5)
+				2: 0.0, // Above the KV cache threshold
+				3: 0.0, // Has waiting requests
+			},
+		},
+		{
+			name: "More utilized active pods get higher score",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 1}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 4, KVCacheUsagePercent: 0.6}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.2, // Active pod with empty KV cache
+				1: 0.8, // 0.2+0.8*0.75
+			},
+		},
+		{
+			name: "GPU utilization is used when the GPU metrics are scraped",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{GPUMetricsUpdateTime: time.Now()}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 1, KVCacheUsagePercent: 0.1, GPUUtilization: 0.45, GPUMetricsUpdateTime: time.Now()}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 1, KVCacheUsagePercent: 0.1, GPUUtilization: 0.95, GPUMetricsUpdateTime: time.Now()}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{RunningQueueSize: 1, KVCacheUsagePercent: 0.4}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.1, // Idle GPUs
+				1: 0.6, // Half of the GPU utilization threshold (0.2+0.8*0.5)
+				2: 0.0, // Above the GPU utilization threshold
+				3: 0.6, // No GPU metrics, half of the KV cache threshold
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, tt.pods)
+			scorer := &BinPackingScorer{kvCacheThreshold: 0.8, gpuUtilizationThreshold: 0.9}
+			scores := scorer.Score(ctx, tt.pods)
+
+			for i, pod := range tt.pods {
+				expectedScore := tt.expectedScoresPod[i]
+				assert.InDelta(t, expectedScore, scores[pod], 0.0001, "Pod %d should have score %f", i, expectedScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &GPUHeadroomScorer{}
+
+// NewGPUHeadroomScorer initializes a new GPUHeadroomScorer and returns its pointer.
+func NewGPUHeadroomScorer() *GPUHeadroomScorer {
+	return &GPUHeadroomScorer{}
+}
+
+// GPUHeadroomScorer scores list of candidate pods based on the headroom of their GPUs: the average
+// of their idle fraction and of the free fraction of their memory. It catches the saturation that
+// the queue and KV cache metrics miss, e.g. the compute of long-context prefills or the memory used
+// outside the KV cache. It requires the GPU metrics to be scraped. The pods whose GPU metrics
+// weren't scraped get the average score of the other pods, so they are neither favored nor
+// avoided.
+type GPUHeadroomScorer struct{}
+
+// Name returns the name of the scorer.
+func (s *GPUHeadroomScorer) Name() string {
+	return "gpu-headroom"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *GPUHeadroomScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	var unscored []types.Pod
+	total := 0.0
+	for _, pod := range pods {
+		metrics := pod.GetMetrics()
+		if metrics.GPUMetricsUpdateTime.IsZero() {
+			unscored = append(unscored, pod)
+			continue
+		}
+		scores[pod] = (1 - metrics.GPUUtilization + metrics.GPUMemoryHeadroom) / 2
+		total += scores[pod]
+	}
+	average := 0.5
+	if len(scores) > 0 {
+		average = total / float64(len(scores))
+	}
+	for _, pod := range unscored {
+		scores[pod] = average
+	}
+	return scores
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestGPUHeadroomScorer(t *testing.T) {
+	scraped := time.Now()
+	tests := []struct {
+		name              string
+		pods              []types.Pod
+		expectedScoresPod map[int]float64 // Map of pod index to expected score
+	}{
+		{
+			name: "Pods with more GPU headroom get higher score",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{GPUUtilization: 0.2, GPUMemoryHeadroom: 0.6, GPUMetricsUpdateTime: scraped}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{GPUUtilization: 1, GPUMemoryHeadroom: 0.2, GPUMetricsUpdateTime: scraped}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.7, // (0.8+0.6)/2
+				1: 0.1, // (0+0.2)/2
+				2: 0.4, // No GPU metrics, average of the other pods
+			},
+		},
+		{
+			name: "Pods without GPU metrics get a neutral score",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.5,
+				1: 0.5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, tt.pods)
+			scores := NewGPUHeadroomScorer().Score(ctx, tt.pods)
+
+			for i, pod := range tt.pods {
+				expectedScore := tt.expectedScoresPod[i]
+				assert.InDelta(t, expectedScore, scores[pod], 0.0001, "Pod %d should have score %f", i, expectedScore)
+			}
+		})
+	}
+}
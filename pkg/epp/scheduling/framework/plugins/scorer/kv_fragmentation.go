@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	// DefaultKVCacheBlockSize is the default number of tokens per KV cache block, the default of
+	// vLLM.
+	DefaultKVCacheBlockSize = 16
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &KVFragmentationScorer{}
+
+// NewKVFragmentationScorer initializes a new KVFragmentationScorer with the given number of tokens
+// per KV cache block and returns its pointer.
+func NewKVFragmentationScorer(blockSize int) *KVFragmentationScorer {
+	if blockSize <= 0 {
+		blockSize = DefaultKVCacheBlockSize
+	}
+	return &KVFragmentationScorer{blockSize: blockSize}
+}
+
+// KVFragmentationScorer scores list of candidate pods based on the KV cache blocks left for their
+// running sequences once the request is admitted. The usable blocks of a pod are its free blocks
+// that aren't fragmented, and the request needs the blocks of its prompt and estimated output
+// tokens. A pod without enough usable blocks would have to preempt running sequences to serve the
+// request, which shows up as latency spikes, so it gets the lowest score. The other pods get the
+// fraction of their usable blocks left once the request is admitted. It requires the KV cache block
+// metrics to be scraped. The pods whose blocks weren't scraped get the average score of the other
+// pods, so they are neither favored nor avoided.
+type KVFragmentationScorer struct {
+	blockSize int
+}
+
+// Name returns the name of the scorer.
+func (s *KVFragmentationScorer) Name() string {
+	return "kv-fragmentation"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *KVFragmentationScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	needed := 0
+	if ctx.Req != nil {
+		tokens := ctx.Req.PromptTokens + ctx.Req.EstimatedOutputTokens
+		needed = (tokens + s.blockSize - 1) / s.blockSize
+	}
+	scores := make(map[types.Pod]float64, len(pods))
+	var unscored []types.Pod
+	total := 0.0
+	for _, pod := range pods {
+		metrics := pod.GetMetrics()
+		if metrics.KVCacheBlocksUpdateTime.IsZero() {
+			unscored = append(unscored, pod)
+			continue
+		}
+		usable := float64(metrics.KVCacheFreeBlocks) * (1 - metrics.KVCacheFragmentation)
+		if left := usable - float64(needed); left > 0 {
+			scores[pod] = left / usable
+		} else {
+			scores[pod] = 0
+		}
+		total += scores[pod]
+	}
+	average := 0.5
+	if len(scores) > 0 {
+		average = total / float64(len(scores))
+	}
+	for _, pod := range unscored {
+		scores[pod] = average
+	}
+	return scores
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestKVFragmentationScorer(t *testing.T) {
+	scraped := time.Now()
+	tests := []struct {
+		name              string
+		req               *types.LLMRequest
+		pods              []types.Pod
+		expectedScoresPod map[int]float64 // Map of pod index to expected score
+	}{
+		{
+			name: "Pods with more usable blocks left get higher score",
+			// 160 tokens, i.e. 10 blocks of 16 tokens.
+			req: &types.LLMRequest{PromptTokens: 100, EstimatedOutputTokens: 60},
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{KVCacheFreeBlocks: 100, KVCacheBlocksUpdateTime: scraped}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{KVCacheFreeBlocks: 100, KVCacheFragmentation: 0.5, KVCacheBlocksUpdateTime: scraped}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{KVCacheFreeBlocks: 40, KVCacheFragmentation: 0.8, KVCacheBlocksUpdateTime: scraped}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.9,    // 90 of 100 usable blocks left
+				1: 0.8,    // 40 of 50 usable blocks left
+				2: 0,      // 8 usable blocks, the request would preempt running sequences
+				3: 0.5667, // No block metrics, average of the other pods
+			},
+		},
+		{
+			name: "Pods without block metrics get a neutral score",
+			req:  &types.LLMRequest{PromptTokens: 100},
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.5,
+				1: 0.5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), tt.req, nil, tt.pods)
+			scores := NewKVFragmentationScorer(DefaultKVCacheBlockSize).Score(ctx, tt.pods)
+
+			for i, pod := range tt.pods {
+				expectedScore := tt.expectedScoresPod[i]
+				assert.InDelta(t, expectedScore, scores[pod], 0.0001, "Pod %d should have score %f", i, expectedScore)
+			}
+		})
+	}
+}
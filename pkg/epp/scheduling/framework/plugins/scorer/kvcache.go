@@ -29,6 +29,8 @@ const (
 var _ framework.Scorer = &KVCacheScorer{}
 
 // KVCacheScorer scores list of candidate pods based on KV cache utilization.
+// The pods whose KV cache utilization isn't scraped (see MetricsState.QueueDepthOnly) get the
+// average score of the other pods, so they are neither favored nor penalized.
 type KVCacheScorer struct{}
 
 // Name returns the name of the scorer.
@@ -39,8 +41,25 @@ func (s *KVCacheScorer) Name() string {
 // Score returns the scoring result for the given list of pods based on context.
 func (s *KVCacheScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
 	scores := make(map[types.Pod]float64, len(pods))
+	total, known := 0.0, 0
 	for _, pod := range pods {
+		if pod.GetMetrics().QueueDepthOnly {
+			continue
+		}
 		scores[pod] = 1 - pod.GetMetrics().KVCacheUsagePercent
+		total += scores[pod]
+		known++
+	}
+	if known < len(pods) {
+		average := 0.0
+		if known > 0 {
+			average = total / float64(known)
+		}
+		for _, pod := range pods {
+			if pod.GetMetrics().QueueDepthOnly {
+				scores[pod] = average
+			}
+		}
 	}
 	return scores
 }
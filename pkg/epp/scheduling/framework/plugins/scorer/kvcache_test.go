@@ -78,6 +78,19 @@ func TestKvCacheScorer(t *testing.T) {
 				1: 0.5, // Half KV cache (0.5) gets medium score (1-0.5=0.5)
 			},
 		},
+		{
+			name: "Queue depth only",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{KVCacheUsagePercent: 0.8}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{KVCacheUsagePercent: 0.4}},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{QueueDepthOnly: true}},
+			},
+			expectedScoresPod: map[int]float64{
+				0: 0.2,
+				1: 0.6,
+				2: 0.4, // The unknown KV cache utilization gets the average score of the other pods
+			},
+		},
 	}
 
 	for _, tt := range tests {
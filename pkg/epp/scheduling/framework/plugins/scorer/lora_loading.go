@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	// loadingOtherAdapterScore is the score of pods loading adapters other than the requested one.
+	// It's low, as the request stalls behind the loads, but higher than the score of the pods
+	// loading the requested adapter, where the request also waits for the adapter.
+	loadingOtherAdapterScore = 0.1
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &LoraLoadingScorer{}
+
+// NewLoraLoadingScorer initializes a new LoraLoadingScorer and returns its pointer.
+func NewLoraLoadingScorer() *LoraLoadingScorer {
+	return &LoraLoadingScorer{}
+}
+
+// LoraLoadingScorer penalizes the pods that are loading LoRA adapters, i.e. reporting waiting
+// adapters in their metrics, as the requests routed to them stall behind the adapter loads.
+// Pods are scored in the following order:
+//  1. Pods not loading any adapter.
+//  2. Pods loading other adapters than the requested one.
+//  3. Pods loading the requested adapter.
+type LoraLoadingScorer struct{}
+
+// Name returns the name of the scorer.
+func (s *LoraLoadingScorer) Name() string {
+	return "lora-loading"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *LoraLoadingScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scores[pod] = podLoraLoadingScore(pod.GetMetrics(), ctx.Req.TargetModel)
+	}
+	return scores
+}
+
+func podLoraLoadingScore(metrics *backendmetrics.MetricsState, targetModel string) float64 {
+	if len(metrics.WaitingModels) == 0 {
+		return 1
+	}
+	if _, ok := metrics.WaitingModels[targetModel]; ok {
+		return 0
+	}
+	return loadingOtherAdapterScore
+}
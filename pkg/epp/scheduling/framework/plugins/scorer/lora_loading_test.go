@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestLoraLoadingScorer(t *testing.T) {
+	pods := []types.Pod{
+		&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+		&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{
+			ActiveModels:  map[string]int{"lora1": 0},
+			WaitingModels: map[string]int{},
+		}},
+		&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{
+			ActiveModels:  map[string]int{"lora1": 0},
+			WaitingModels: map[string]int{"lora2": 0},
+		}},
+		&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{
+			WaitingModels: map[string]int{"lora1": 0, "lora2": 0},
+		}},
+	}
+	expectedScores := map[int]float64{
+		0: 1,   // No LoRA metrics
+		1: 1,   // Not loading any adapter
+		2: 0.1, // Loading another adapter
+		3: 0,   // Loading the requested adapter
+	}
+
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "lora1"}, nil, pods)
+	scores := NewLoraLoadingScorer().Score(ctx, pods)
+
+	for i, pod := range pods {
+		assert.InDelta(t, expectedScores[i], scores[pod], 0.0001, "Pod %d should have score %f", i, expectedScores[i])
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &OutstandingTokensScorer{}
+
+// NewOutstandingTokensScorer initializes a new OutstandingTokensScorer and returns its pointer.
+func NewOutstandingTokensScorer() *OutstandingTokensScorer {
+	return &OutstandingTokensScorer{}
+}
+
+// OutstandingTokensScorer scores list of candidate pods based on the estimated number of tokens the
+// requests in flight to them have yet to process: the prompt tokens of the requests without a
+// response yet, and the output tokens yet to be generated. Unlike the number of requests in flight,
+// it weighs a long-context request more than a short one. The fewer outstanding tokens the pod has,
+// the higher score it will get.
+type OutstandingTokensScorer struct{}
+
+// Name returns the name of the scorer.
+func (s *OutstandingTokensScorer) Name() string {
+	return "outstanding-tokens"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *OutstandingTokensScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	return leastLoadScores(pods, func(pod types.Pod) int { return pod.GetRequestsInFlight().OutstandingTokens() })
+}
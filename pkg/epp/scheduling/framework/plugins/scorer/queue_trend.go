@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"time"
+
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+const (
+	// DefaultQueueTrendWindow is the duration over which the trend of the queues is measured. With
+	// the default refresh interval of the metrics, the metrics history covers about a second.
+	DefaultQueueTrendWindow = time.Second
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &QueueTrendScorer{}
+
+// NewQueueTrendScorer initializes a new QueueTrendScorer measuring the trend of the queues over
+// the given window, or DefaultQueueTrendWindow if not positive, and returns its pointer.
+func NewQueueTrendScorer(window time.Duration) *QueueTrendScorer {
+	if window <= 0 {
+		window = DefaultQueueTrendWindow
+	}
+	return &QueueTrendScorer{window: window}
+}
+
+// QueueTrendScorer scores list of candidate pods based on the rate of change of their waiting
+// queue size over the recent metrics samples: the faster the queue of a pod drains, the higher
+// score it will get, and the faster it grows, the lower. The absolute queue size lags behind rapid
+// load shifts between two scrapes, while the trend shows where the load is heading, so it's meant
+// to complement the queue scorer rather than replace it. The pods without enough samples to
+// measure a trend get the average score of the other pods, so they are neither favored nor
+// avoided.
+type QueueTrendScorer struct {
+	window time.Duration
+}
+
+// Name returns the name of the scorer.
+func (s *QueueTrendScorer) Name() string {
+	return "queue-trend"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *QueueTrendScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	rates := make(map[types.Pod]float64, len(pods))
+	var minRate, maxRate float64
+	for _, pod := range pods {
+		rate, ok := pod.GetMetricsHistory().Rate(backendmetrics.WaitingQueueSizeMetric, s.window)
+		if !ok {
+			continue
+		}
+		if len(rates) == 0 || rate < minRate {
+			minRate = rate
+		}
+		if len(rates) == 0 || rate > maxRate {
+			maxRate = rate
+		}
+		rates[pod] = rate
+	}
+
+	scores := make(map[types.Pod]float64, len(pods))
+	total := 0.0
+	for pod, rate := range rates {
+		if maxRate == minRate {
+			// If all queues change at the same rate, return a neutral score.
+			scores[pod] = 1.0
+		} else {
+			scores[pod] = (maxRate - rate) / (maxRate - minRate)
+		}
+		total += scores[pod]
+	}
+	average := 1.0
+	if len(rates) > 0 {
+		average = total / float64(len(rates))
+	}
+	for _, pod := range pods {
+		if _, ok := rates[pod]; !ok {
+			scores[pod] = average
+		}
+	}
+	return scores
+}
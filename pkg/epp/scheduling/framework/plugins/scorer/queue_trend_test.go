@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// podWithQueues returns a pod whose waiting queue had the given sizes, one sample every 100ms.
+func podWithQueues(queues ...int) types.Pod {
+	history := backendmetrics.NewMetricsHistory(0)
+	start := time.Now()
+	var last *backendmetrics.MetricsState
+	for i, queue := range queues {
+		last = &backendmetrics.MetricsState{WaitingQueueSize: queue, UpdateTime: start.Add(time.Duration(i) * 100 * time.Millisecond)}
+		history.Add(last)
+	}
+	if last == nil {
+		last = &backendmetrics.MetricsState{}
+	}
+	return &types.PodMetrics{Pod: &backend.Pod{}, MetricsState: last, History: history}
+}
+
+func TestQueueTrendScorer(t *testing.T) {
+	tests := []struct {
+		name              string
+		pods              []types.Pod
+		window            time.Duration
+		expectedScoresPod map[int]float64 // Map of pod index to expected score
+	}{
+		{
+			name: "Pods with draining queues get higher score",
+			pods: []types.Pod{
+				podWithQueues(10, 8, 6),  // -20/s
+				podWithQueues(2, 2, 2),   // 0/s
+				podWithQueues(0, 5, 10),  // +50/s
+				podWithQueues(20, 20, 6), // -70/s
+			},
+			expectedScoresPod: map[int]float64{
+				0: 70.0 / 120,
+				1: 50.0 / 120,
+				2: 0,
+				3: 1,
+			},
+		},
+		{
+			name: "Pods without a trend get the average score",
+			pods: []types.Pod{
+				podWithQueues(4, 2),
+				podWithQueues(0, 4),
+				podWithQueues(3),
+				podWithQueues(),
+			},
+			expectedScoresPod: map[int]float64{
+				0: 1,
+				1: 0,
+				2: 0.5,
+				3: 0.5,
+			},
+		},
+		{
+			name: "Pods with the same trend get a neutral score",
+			pods: []types.Pod{
+				podWithQueues(1, 2),
+				podWithQueues(5, 6),
+			},
+			expectedScoresPod: map[int]float64{
+				0: 1,
+				1: 1,
+			},
+		},
+		{
+			name: "The trend is measured over the window",
+			pods: []types.Pod{
+				podWithQueues(0, 10, 5), // -50/s over the last 100ms
+				podWithQueues(10, 0, 0), // 0/s over the last 100ms
+			},
+			window: 100 * time.Millisecond,
+			expectedScoresPod: map[int]float64{
+				0: 1,
+				1: 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, tt.pods)
+			scores := NewQueueTrendScorer(tt.window).Score(ctx, tt.pods)
+
+			for i, pod := range tt.pods {
+				expectedScore := tt.expectedScoresPod[i]
+				assert.InDelta(t, expectedScore, scores[pod], 0.0001, "Pod %d should have score %f", i, expectedScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"math"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// compile-time type assertion
+var _ framework.Scorer = &RequestsInFlightScorer{}
+
+// NewRequestsInFlightScorer initializes a new RequestsInFlightScorer and returns its pointer.
+func NewRequestsInFlightScorer() *RequestsInFlightScorer {
+	return &RequestsInFlightScorer{}
+}
+
+// RequestsInFlightScorer scores list of candidate pods based on the number of requests the endpoint
+// picker routed to them that didn't end yet. Unlike the scraped queue sizes, the count is
+// instantaneous, so a burst of requests is spread across the pods before their next scrape. The
+// fewer requests in flight the pod has, the higher score it will get.
+type RequestsInFlightScorer struct{}
+
+// Name returns the name of the scorer.
+func (s *RequestsInFlightScorer) Name() string {
+	return "requests-in-flight"
+}
+
+// Score returns the scoring result for the given list of pods based on context.
+func (s *RequestsInFlightScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	return leastLoadScores(pods, func(pod types.Pod) int { return pod.GetRequestsInFlight().Count() })
+}
+
+// leastLoadScores scores the given pods from 0 for the most loaded ones to 1 for the least loaded
+// ones, linearly in between, or 1 if they're all equally loaded. The load of every pod is read once,
+// so the live loads score all the pods consistently.
+func leastLoadScores(pods []types.Pod, load func(types.Pod) int) map[types.Pod]float64 {
+	loads := make(map[types.Pod]int, len(pods))
+	minLoad, maxLoad := math.MaxInt, math.MinInt
+	for _, pod := range pods {
+		podLoad := load(pod)
+		loads[pod] = podLoad
+		minLoad = min(minLoad, podLoad)
+		maxLoad = max(maxLoad, podLoad)
+	}
+
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		if maxLoad == minLoad {
+			scores[pod] = 1.0
+			continue
+		}
+		scores[pod] = float64(maxLoad-loads[pod]) / float64(maxLoad-minLoad)
+	}
+	return scores
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scorer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestRequestsInFlightScorer(t *testing.T) {
+	inFlight := func(count int) *backendmetrics.RequestsInFlight {
+		r := &backendmetrics.RequestsInFlight{}
+		for range count {
+			r.Inc()
+		}
+		return r
+	}
+	tests := []struct {
+		name           string
+		pods           []types.Pod
+		expectedScores map[int]float64 // Map of pod index to expected score
+	}{
+		{
+			name: "Different requests in flight",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}, InFlight: inFlight(4)},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}, InFlight: inFlight(2)},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}, InFlight: inFlight(0)},
+			},
+			expectedScores: map[int]float64{0: 0.0, 1: 0.5, 2: 1.0},
+		},
+		{
+			name: "Same requests in flight",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}, InFlight: inFlight(3)},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}, InFlight: inFlight(3)},
+			},
+			expectedScores: map[int]float64{0: 1.0, 1: 1.0},
+		},
+		{
+			name: "Uncounted pod",
+			pods: []types.Pod{
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}, InFlight: inFlight(2)},
+				&types.PodMetrics{Pod: &backend.Pod{}, MetricsState: &backendmetrics.MetricsState{}},
+			},
+			expectedScores: map[int]float64{0: 0.0, 1: 1.0},
+		},
+	}
+
+	scorer := NewRequestsInFlightScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, tt.pods)
+			scores := scorer.Score(ctx, tt.pods)
+			for i, pod := range tt.pods {
+				assert.InDelta(t, tt.expectedScores[i], scores[pod], 0.0001, "Pod %d should have score %v", i, tt.expectedScores[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wasm lets operators supply scoring logic as WebAssembly modules run in a sandbox within
+// the endpoint picker, so that platform teams can add scorers without rebuilding it or running
+// a scheduler extension next to it (see the extension package).
+//
+// A module exports its linear memory as "memory" and two functions:
+//
+//	alloc(size i32) i32            returns the address of a buffer of size bytes for the input
+//	score(ptr i32, len i32) i64    scores the pods of the input at [ptr, ptr+len)
+//
+// The input of score is an extension.Request encoded as JSON, and its result packs the address
+// of its output in its upper 32 bits and the length of its output in its lower 32 bits. The
+// output is an extension.ScoreResponse encoded as JSON. The module is called with WASI
+// (wasi_snapshot_preview1) without access to the file system, the network or the environment,
+// and is initialized by its "_initialize" export if any, i.e. modules built as WASI reactors.
+//
+// The instances of a module are reused across the requests, but not concurrently: an instance
+// is only called by one request at a time. An instance whose call fails, e.g. because it trapped
+// or exceeded its time limit, is discarded.
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	allocFunction = "alloc"
+	scoreFunction = "score"
+	memoryExport  = "memory"
+
+	// pagesPerMiB is the number of WebAssembly memory pages (64KiB) in a MiB.
+	pagesPerMiB = 16
+)
+
+// module is a compiled WebAssembly module and the pool of its instances.
+type module struct {
+	key      moduleKey
+	refs     int // guarded by the lock of modules
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	// slots holds a token per instance in use, so that at most its capacity are.
+	slots chan struct{}
+	// idle holds the instances not in use.
+	idle chan api.Module
+}
+
+type moduleKey struct {
+	hash         [sha256.Size]byte
+	maxMemoryMiB int
+	maxInstances int
+}
+
+// modules are the compiled modules by code and limits, shared by the plugins running the same
+// module, so that the reloads of the scheduler config, which create new plugins before the previous
+// ones are dropped, neither compile nor instantiate the modules again. A module is closed once the
+// last plugin running it is released.
+var modules = struct {
+	sync.Mutex
+	byKey map[moduleKey]*module
+}{byKey: map[moduleKey]*module{}}
+
+// loadModule compiles the given WebAssembly code, whose instances get at most maxMemoryMiB of
+// memory and are called at most maxInstances at a time. The module must be released with
+// releaseModule once no longer used.
+func loadModule(code []byte, maxMemoryMiB, maxInstances int) (*module, error) {
+	key := moduleKey{hash: sha256.Sum256(code), maxMemoryMiB: maxMemoryMiB, maxInstances: maxInstances}
+	modules.Lock()
+	defer modules.Unlock()
+	if m, ok := modules.byKey[key]; ok {
+		m.refs++
+		return m, nil
+	}
+
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(maxMemoryMiB*pagesPerMiB)).
+		WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, code)
+	if err == nil {
+		err = validateExports(compiled)
+	}
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("invalid module: %w", err)
+	}
+	m := &module{
+		key:      key,
+		refs:     1,
+		runtime:  runtime,
+		compiled: compiled,
+		slots:    make(chan struct{}, maxInstances),
+		idle:     make(chan api.Module, maxInstances),
+	}
+	modules.byKey[key] = m
+	return m, nil
+}
+
+// releaseModule releases a module returned by loadModule, and closes its instances and its runtime
+// if it was the last use of the module.
+func releaseModule(m *module) {
+	modules.Lock()
+	m.refs--
+	if m.refs > 0 {
+		modules.Unlock()
+		return
+	}
+	delete(modules.byKey, m.key)
+	modules.Unlock()
+	_ = m.runtime.Close(context.Background())
+}
+
+// validateExports returns an error if the given module doesn't export the memory and functions
+// of the ABI of the scorers.
+func validateExports(compiled wazero.CompiledModule) error {
+	var errs []error
+	if _, ok := compiled.ExportedMemories()[memoryExport]; !ok {
+		errs = append(errs, fmt.Errorf("no exported memory '%s'", memoryExport))
+	}
+	functions := compiled.ExportedFunctions()
+	for _, expected := range []struct {
+		name    string
+		params  []api.ValueType
+		results []api.ValueType
+	}{
+		{name: allocFunction, params: []api.ValueType{api.ValueTypeI32}, results: []api.ValueType{api.ValueTypeI32}},
+		{name: scoreFunction, params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, results: []api.ValueType{api.ValueTypeI64}},
+	} {
+		function, ok := functions[expected.name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no exported function '%s'", expected.name))
+			continue
+		}
+		if !bytes.Equal(function.ParamTypes(), expected.params) || !bytes.Equal(function.ResultTypes(), expected.results) {
+			errs = append(errs, fmt.Errorf("function '%s' has the signature (%s) -> (%s), expected (%s) -> (%s)", expected.name,
+				valueTypeNames(function.ParamTypes()), valueTypeNames(function.ResultTypes()),
+				valueTypeNames(expected.params), valueTypeNames(expected.results)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func valueTypeNames(valueTypes []api.ValueType) string {
+	names := make([]byte, 0, 4*len(valueTypes))
+	for i, valueType := range valueTypes {
+		if i > 0 {
+			names = append(names, ", "...)
+		}
+		names = append(names, api.ValueTypeName(valueType)...)
+	}
+	return string(names)
+}
+
+// call calls the given function of an idle instance of the module, or of a new one if none is
+// idle, with the given input, and returns its output. It waits for an instance until the context
+// is done if maxInstances are in use.
+func (m *module) call(ctx context.Context, function string, input []byte) ([]byte, error) {
+	select {
+	case m.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-m.slots }()
+
+	var instance api.Module
+	select {
+	case instance = <-m.idle:
+	default:
+		var err error
+		instance, err = m.runtime.InstantiateModule(ctx, m.compiled, wazero.NewModuleConfig().
+			WithName("").
+			WithStartFunctions("_initialize"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate the module: %w", err)
+		}
+	}
+	output, err := invoke(ctx, instance, function, input)
+	if err != nil {
+		// The state of the instance is unknown, e.g. its memory may be left corrupted by a trap.
+		_ = instance.Close(context.Background())
+		return nil, err
+	}
+	// The instances, idle or not, are at most as many as the capacity of idle.
+	m.idle <- instance
+	return output, nil
+}
+
+func invoke(ctx context.Context, instance api.Module, function string, input []byte) ([]byte, error) {
+	results, err := instance.ExportedFunction(allocFunction).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", allocFunction, err)
+	}
+	ptr := uint32(results[0])
+	if !instance.Memory().Write(ptr, input) {
+		return nil, fmt.Errorf("%s returned a buffer out of the memory of the module", allocFunction)
+	}
+	results, err = instance.ExportedFunction(function).Call(ctx, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", function, err)
+	}
+	output, ok := instance.Memory().Read(uint32(results[0]>>32), uint32(results[0]))
+	if !ok {
+		return nil, fmt.Errorf("%s returned an output out of the memory of the module", function)
+	}
+	// The output is a view of the memory of the instance, which is reused by the next calls.
+	return bytes.Clone(output), nil
+}
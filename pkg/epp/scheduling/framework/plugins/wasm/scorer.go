@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/extension"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	// ScorerName is the default name of the WebAssembly scorers.
+	ScorerName = "wasm-scorer"
+
+	// DefaultTimeout is the default time limit of the calls of a module, including the wait for
+	// an instance. The modules run on the scheduling path of every request.
+	DefaultTimeout = 20 * time.Millisecond
+	// DefaultMaxMemoryMiB is the default memory limit of the instances of a module.
+	DefaultMaxMemoryMiB = 16
+)
+
+// Config is the configuration of a WebAssembly scorer.
+type Config struct {
+	// Name is the name of the plugin, which tells the WebAssembly scorers of a profile apart in
+	// the metrics and logs of the plugins. Defaults to "wasm-scorer".
+	Name string
+	// Module is the path of the .wasm file of the module, e.g. mounted from a ConfigMap.
+	Module string
+	// Timeout bounds every call of the module, DefaultTimeout if not positive. A call exceeding
+	// it is aborted.
+	Timeout time.Duration
+	// MaxMemoryMiB bounds the memory of every instance of the module, DefaultMaxMemoryMiB if not
+	// positive.
+	MaxMemoryMiB int
+	// MaxInstances bounds the number of instances of the module called concurrently, GOMAXPROCS
+	// if not positive.
+	MaxInstances int
+	// SendPrompt sends the prompt of the requests to the module.
+	SendPrompt bool
+}
+
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	var errs []error
+	if c.Module == "" {
+		errs = append(errs, errors.New("module is required"))
+	}
+	if c.MaxMemoryMiB < 0 {
+		errs = append(errs, fmt.Errorf("negative max memory %dMiB", c.MaxMemoryMiB))
+	}
+	if c.MaxInstances < 0 {
+		errs = append(errs, fmt.Errorf("negative max instances %d", c.MaxInstances))
+	}
+	return errors.Join(errs...)
+}
+
+// compile-time type assertion
+var _ framework.Scorer = &Scorer{}
+
+// Scorer scores the candidate pods by calling the score function of a WebAssembly module.
+type Scorer struct {
+	Config
+	module *module
+}
+
+// NewScorer returns a new Scorer running the module of the given config, which is compiled
+// before it returns.
+func NewScorer(config Config) (*Scorer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Name == "" {
+		config.Name = ScorerName
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxMemoryMiB == 0 {
+		config.MaxMemoryMiB = DefaultMaxMemoryMiB
+	}
+	if config.MaxInstances == 0 {
+		config.MaxInstances = runtime.GOMAXPROCS(0)
+	}
+	code, err := os.ReadFile(config.Module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the module: %w", err)
+	}
+	m, err := loadModule(code, config.MaxMemoryMiB, config.MaxInstances)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the module %s: %w", config.Module, err)
+	}
+	s := &Scorer{Config: config, module: m}
+	// The module is released once the scorer is garbage collected, e.g. after a reload of the
+	// scheduler config replaced it.
+	runtime.AddCleanup(s, releaseModule, m)
+	return s, nil
+}
+
+// Name returns the name of the scorer.
+func (s *Scorer) Name() string {
+	return s.Config.Name
+}
+
+// Score returns the scores returned by the module, clamped to [0, 1]. The pods the module didn't
+// score get 0, as do all the pods if the call failed.
+func (s *Scorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scores := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scores[pod] = 0
+	}
+	resp, err := s.score(ctx, pods)
+	if err != nil {
+		ctx.Logger.V(logutil.DEFAULT).Error(err, "WebAssembly scorer failed", "plugin", s.Config.Name, "module", s.Module)
+		return scores
+	}
+	for _, pod := range pods {
+		score := resp.Scores[pod.GetPod().NamespacedName.String()]
+		if math.IsNaN(score) {
+			score = 0
+		}
+		scores[pod] = math.Max(0, math.Min(1, score))
+	}
+	return scores
+}
+
+func (s *Scorer) score(ctx *types.SchedulingContext, pods []types.Pod) (*extension.ScoreResponse, error) {
+	input, err := json.Marshal(extension.NewRequest(ctx, pods, s.SendPrompt))
+	if err != nil {
+		metrics.RecordSchedulerWasmFailure(s.Config.Name, "error")
+		return nil, err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+	output, err := s.module.call(callCtx, scoreFunction, input)
+	// The module must not be released while it's called.
+	runtime.KeepAlive(s)
+	if err != nil {
+		reason := "error"
+		if callCtx.Err() != nil {
+			reason = "timeout"
+		}
+		metrics.RecordSchedulerWasmFailure(s.Config.Name, reason)
+		return nil, err
+	}
+	resp := &extension.ScoreResponse{}
+	if err := json.Unmarshal(output, resp); err != nil {
+		metrics.RecordSchedulerWasmFailure(s.Config.Name, "invalid-response")
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return resp, nil
+}
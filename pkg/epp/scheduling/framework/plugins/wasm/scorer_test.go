@@ -0,0 +1,266 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wasm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// outputAddress is the address of the output of the test modules.
+const outputAddress = 16
+
+var (
+	// constantScore returns the output at outputAddress.
+	constantScore = func(output string) []byte {
+		return append(append([]byte{0x42}, sleb128(outputAddress<<32|int64(len(output)))...), 0x0b)
+	}
+	// infiniteLoop never returns.
+	infiniteLoop = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00, 0x0b}
+	// trap traps.
+	trap = []byte{0x00, 0x0b}
+)
+
+// testModule assembles a module exporting the memory and functions of the ABI, whose score
+// function has the given body, and whose memory has the given pages and holds the given output at
+// outputAddress.
+func testModule(scoreBody []byte, output string, pages byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append(append([]byte{id}, uleb128(uint64(len(content)))...), content...)
+	}
+	name := func(name string) []byte {
+		return append([]byte{byte(len(name))}, name...)
+	}
+	body := func(code []byte) []byte {
+		code = append([]byte{0x00}, code...) // no locals
+		return append(uleb128(uint64(len(code))), code...)
+	}
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	// types: (i32) -> i32, (i32, i32) -> i64
+	module = append(module, section(0x01, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e)...)
+	// functions: alloc, score
+	module = append(module, section(0x03, 0x02, 0x00, 0x01)...)
+	// memory
+	module = append(module, section(0x05, 0x01, 0x00, pages)...)
+	// exports
+	exports := []byte{0x03}
+	exports = append(append(exports, name(memoryExport)...), 0x02, 0x00)
+	exports = append(append(exports, name(allocFunction)...), 0x00, 0x00)
+	exports = append(append(exports, name(scoreFunction)...), 0x00, 0x01)
+	module = append(module, section(0x07, exports...)...)
+	// code: alloc returns 1024
+	code := []byte{0x02}
+	code = append(code, body([]byte{0x41, 0x80, 0x08, 0x0b})...)
+	code = append(code, body(scoreBody)...)
+	module = append(module, section(0x0a, code...)...)
+	// data: the output at outputAddress
+	data := []byte{0x01, 0x00, 0x41, outputAddress, 0x0b}
+	data = append(append(data, uleb128(uint64(len(output)))...), output...)
+	return append(module, section(0x0b, data...)...)
+}
+
+func uleb128(v uint64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func sleb128(v int64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func writeModule(t *testing.T, code []byte) string {
+	path := filepath.Join(t.TempDir(), "scorer.wasm")
+	require.NoError(t, os.WriteFile(path, code, 0o600))
+	return path
+}
+
+func TestScore(t *testing.T) {
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pod3 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod3"}}, MetricsState: &backendmetrics.MetricsState{}}
+	pods := []types.Pod{pod1, pod2, pod3}
+
+	tests := []struct {
+		name      string
+		scoreBody []byte
+		output    string
+		want      map[types.Pod]float64
+	}{
+		{
+			name:      "scores are clamped",
+			scoreBody: constantScore(`{"scores":{"default/pod1":0.5,"default/pod2":7}}`),
+			output:    `{"scores":{"default/pod1":0.5,"default/pod2":7}}`,
+			want:      map[types.Pod]float64{pod1: 0.5, pod2: 1, pod3: 0},
+		},
+		{
+			name:      "invalid response",
+			scoreBody: constantScore(`{"scores":`),
+			output:    `{"scores":`,
+			want:      map[types.Pod]float64{pod1: 0, pod2: 0, pod3: 0},
+		},
+		{
+			name:      "trap",
+			scoreBody: trap,
+			want:      map[types.Pod]float64{pod1: 0, pod2: 0, pod3: 0},
+		},
+		{
+			name:      "timeout",
+			scoreBody: infiniteLoop,
+			want:      map[types.Pod]float64{pod1: 0, pod2: 0, pod3: 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scorer, err := NewScorer(Config{
+				Module:  writeModule(t, testModule(test.scoreBody, test.output, 1)),
+				Timeout: 50 * time.Millisecond,
+			})
+			require.NoError(t, err)
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{TargetModel: "test-model", RequestId: "req-1"}, nil, pods)
+			// The second call reuses the instance of the first one, or a new one if it failed.
+			for range 2 {
+				assert.Equal(t, test.want, scorer.Score(ctx, pods))
+			}
+		})
+	}
+}
+
+func TestNewScorer(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		code    []byte
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			config: Config{},
+			code:   testModule(trap, "", 1),
+		},
+		{
+			name:    "no module",
+			config:  Config{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid module",
+			config:  Config{},
+			code:    []byte("not wasm"),
+			wantErr: true,
+		},
+		{
+			name:    "memory over the limit",
+			config:  Config{MaxMemoryMiB: 1},
+			code:    testModule(trap, "", 2*pagesPerMiB),
+			wantErr: true,
+		},
+		{
+			name:    "negative max instances",
+			config:  Config{MaxInstances: -1},
+			code:    testModule(trap, "", 1),
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.code != nil {
+				test.config.Module = writeModule(t, test.code)
+			}
+			_, err := NewScorer(test.config)
+			assert.Equal(t, test.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestModuleRelease(t *testing.T) {
+	code := testModule(constantScore(`{"scores": {}}`), `{"scores": {}}`, 1)
+	loaded := func(m *module) bool {
+		modules.Lock()
+		defer modules.Unlock()
+		return modules.byKey[m.key] == m
+	}
+
+	m, err := loadModule(code, 1, 1)
+	require.NoError(t, err)
+	shared, err := loadModule(code, 1, 1)
+	require.NoError(t, err)
+	assert.Same(t, m, shared)
+
+	releaseModule(shared)
+	assert.True(t, loaded(m), "the module is released by one of its users only")
+	_, err = m.call(context.Background(), scoreFunction, []byte("{}"))
+	assert.NoError(t, err)
+
+	releaseModule(m)
+	assert.False(t, loaded(m))
+	_, err = m.call(context.Background(), scoreFunction, []byte("{}"))
+	assert.Error(t, err, "the runtime of the module is closed")
+
+	// The module of a scorer is released once the scorer is garbage collected.
+	m = func() *module {
+		scorer, err := NewScorer(Config{Module: writeModule(t, code), MaxMemoryMiB: 1, MaxInstances: 1})
+		require.NoError(t, err)
+		return scorer.module
+	}()
+	assert.True(t, loaded(m))
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return !loaded(m)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestValidateExports(t *testing.T) {
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	defer func() { _ = runtime.Close(ctx) }()
+
+	// A module without exports.
+	compiled, err := runtime.CompileModule(ctx, []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00})
+	require.NoError(t, err)
+	err = validateExports(compiled)
+	assert.ErrorContains(t, err, "no exported memory 'memory'")
+	assert.ErrorContains(t, err, "no exported function 'alloc'")
+	assert.ErrorContains(t, err, "no exported function 'score'")
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FactoryFunc creates a new instance of a plugin from its parameters, the raw JSON of the
+// "parameters" of the plugin in the scheduler plugins config, nil if there are none. Every profile
+// referencing a plugin gets its own instance.
+type FactoryFunc func(parameters json.RawMessage, handle Handle) (Plugin, error)
+
+// Handle gives the factories of the plugins access to the Endpoint Picker instantiating them.
+type Handle interface {
+	// DefaultParameters returns the parameters the plugin of the given name is configured with
+	// outside of the scheduler plugins config, e.g. from environment variables, nil if none. The
+	// parameters of the plugins config override them field by field.
+	DefaultParameters(name string) json.RawMessage
+	// PoolAnnotations returns the annotations of the InferencePool the requests are scheduled to,
+	// nil if unknown.
+	PoolAnnotations() map[string]string
+}
+
+// NoHandle is the Handle of the plugins instantiated outside of an Endpoint Picker, without
+// default parameters nor InferencePool.
+var NoHandle Handle = noHandle{}
+
+type noHandle struct{}
+
+func (noHandle) DefaultParameters(string) json.RawMessage { return nil }
+
+func (noHandle) PoolAnnotations() map[string]string { return nil }
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]FactoryFunc
+}{factories: make(map[string]FactoryFunc)}
+
+// Register registers the factory of the plugin of the given name, so that it can be instantiated by
+// name from the scheduler plugins config. It's meant to be called from the init function of the
+// package of the plugin, in-tree or out-of-tree alike, and panics if the name is empty or already
+// registered.
+func Register(name string, factory FactoryFunc) {
+	if name == "" {
+		panic("plugin name is required")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("plugin '%s' has no factory", name))
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.factories[name]; ok {
+		panic(fmt.Sprintf("plugin '%s' is already registered", name))
+	}
+	registry.factories[name] = factory
+}
+
+// LookupFactory returns the factory of the plugin registered under the given name, if any.
+func LookupFactory(name string) (FactoryFunc, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	factory, ok := registry.factories[name]
+	return factory, ok
+}
+
+// RegisteredPlugins returns the sorted names of the registered plugins.
+func RegisteredPlugins() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecodeParameters decodes the given parameters of a plugin into the given pointer, typically to a
+// struct holding the defaults of the plugin beforehand. Unknown fields are rejected, and empty or
+// null parameters leave the value as it is.
+func DecodeParameters(parameters json.RawMessage, into any) error {
+	if len(parameters) == 0 || bytes.Equal(bytes.TrimSpace(parameters), []byte("null")) {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(parameters))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(into); err != nil {
+		return fmt.Errorf("invalid parameters: %w", err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("invalid parameters: unexpected data after the parameters")
+	}
+	return nil
+}
+
+// NoParameters wraps the given constructor of a plugin without parameters into a FactoryFunc that
+// rejects any parameters.
+func NoParameters[P Plugin](newPlugin func() P) FactoryFunc {
+	return func(parameters json.RawMessage, _ Handle) (Plugin, error) {
+		if err := DecodeParameters(parameters, &struct{}{}); err != nil {
+			return nil, err
+		}
+		return newPlugin(), nil
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryTestPlugin struct {
+	Threshold int `json:"threshold"`
+}
+
+func (p *registryTestPlugin) Name() string {
+	return "registry-test"
+}
+
+func TestRegister(t *testing.T) {
+	Register("registry-test", func(parameters json.RawMessage, _ Handle) (Plugin, error) {
+		plugin := &registryTestPlugin{Threshold: 10}
+		if err := DecodeParameters(parameters, plugin); err != nil {
+			return nil, err
+		}
+		return plugin, nil
+	})
+	Register("registry-test-no-parameters", NoParameters(func() *registryTestPlugin { return &registryTestPlugin{} }))
+
+	assert.Contains(t, RegisteredPlugins(), "registry-test")
+	assert.PanicsWithValue(t, "plugin 'registry-test' is already registered", func() {
+		Register("registry-test", NoParameters(func() *registryTestPlugin { return &registryTestPlugin{} }))
+	})
+	assert.PanicsWithValue(t, "plugin name is required", func() {
+		Register("", NoParameters(func() *registryTestPlugin { return &registryTestPlugin{} }))
+	})
+	_, ok := LookupFactory("does-not-exist")
+	assert.False(t, ok)
+
+	factory, ok := LookupFactory("registry-test")
+	assert.True(t, ok)
+	tests := []struct {
+		name       string
+		parameters string
+		want       Plugin
+		wantErr    string
+	}{
+		{name: "no parameters", want: &registryTestPlugin{Threshold: 10}},
+		{name: "null", parameters: `null`, want: &registryTestPlugin{Threshold: 10}},
+		{name: "empty", parameters: `{}`, want: &registryTestPlugin{Threshold: 10}},
+		{name: "override", parameters: `{"threshold": 3}`, want: &registryTestPlugin{Threshold: 3}},
+		{name: "unknown field", parameters: `{"limit": 3}`, wantErr: `unknown field "limit"`},
+		{name: "wrong type", parameters: `{"threshold": "3"}`, wantErr: "invalid parameters"},
+		{name: "trailing data", parameters: `{} {}`, wantErr: "unexpected data after the parameters"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var parameters json.RawMessage
+			if test.parameters != "" {
+				parameters = json.RawMessage(test.parameters)
+			}
+			got, err := factory(parameters, NoHandle)
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	noParameters, _ := LookupFactory("registry-test-no-parameters")
+	_, err := noParameters(json.RawMessage(`{}`), NoHandle)
+	assert.NoError(t, err)
+	_, err = noParameters(json.RawMessage(`{"threshold": 3}`), NoHandle)
+	assert.ErrorContains(t, err, `unknown field "threshold"`)
+}
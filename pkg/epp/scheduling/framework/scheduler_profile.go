@@ -17,33 +17,123 @@ limitations under the License.
 package framework
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/schedulingtrace"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/timeline"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tracing"
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
+// ErrNoPodsAvailable is returned by a profile cycle when the filters leave no candidate pod.
+var ErrNoPodsAvailable = errutil.Error{Code: errutil.Internal, Msg: "no pods available for the given request"}
+
 // NewSchedulerProfile creates a new SchedulerProfile object and returns its pointer.
 func NewSchedulerProfile() *SchedulerProfile {
 	return &SchedulerProfile{
+		preCyclePlugins:     []PreCycle{},
 		filters:             []Filter{},
 		scorers:             []*WeightedScorer{},
 		postCyclePlugins:    []PostCycle{},
 		PostResponsePlugins: []PostResponse{},
+		filterCache:         newFilterCache(),
+		failurePolicy:       ProfileRequired,
+		pluginPanicPolicy:   PluginPanicSkip,
 		// picker remains nil since profile doesn't support multiple pickers
 	}
 }
 
 // SchedulerProfile provides a profile configuration for the scheduler which influence routing decisions.
 type SchedulerProfile struct {
+	preCyclePlugins     []PreCycle
 	filters             []Filter
 	scorers             []*WeightedScorer
 	picker              Picker
 	postCyclePlugins    []PostCycle
 	PostResponsePlugins []PostResponse // TODO this field should get out of the scheduler
+	timeout             time.Duration  // zero if the cycle has no time budget
+	candidateSampleSize int            // zero if all the filtered pods are scored
+	filterCache         *filterCache   // results of the leading CacheableFilters, nil if not cached
+	scoreFloor          float64        // zero if all the scored pods are passed to the picker
+	scoreFloorPolicy    ScoreFloorPolicy
+	scoreCache          *scoreCache // nil if the scores of identical requests are not reused
+	failurePolicy       ProfileFailurePolicy
+	fallbackProfile     string // empty if no profile runs instead of this one when its cycle fails
+	pluginPanicPolicy   PluginPanicPolicy
+	rand                *Rand // nil until the environment is set
+}
+
+// ProfileFailurePolicy is what the scheduler does when a cycle of a SchedulerProfile fails.
+type ProfileFailurePolicy string
+
+const (
+	// ProfileRequired fails the scheduling of the request.
+	ProfileRequired ProfileFailurePolicy = "required"
+	// ProfileOptional goes on scheduling the request without a result for the profile.
+	ProfileOptional ProfileFailurePolicy = "optional"
+)
+
+// Valid returns whether the policy is one of the supported ones.
+func (p ProfileFailurePolicy) Valid() bool {
+	return p == ProfileRequired || p == ProfileOptional
+}
+
+// ScoreFloorPolicy is what a SchedulerProfile does when none of the scored pods clears its score floor.
+type ScoreFloorPolicy string
+
+const (
+	// ScoreFloorPickBest passes the pods with the highest score to the picker.
+	ScoreFloorPickBest ScoreFloorPolicy = "pick-best"
+	// ScoreFloorIgnore passes all the scored pods to the picker, as if there was no floor.
+	ScoreFloorIgnore ScoreFloorPolicy = "ignore"
+	// ScoreFloorReject fails the cycle, so the request is rejected rather than sent to a poor pod.
+	ScoreFloorReject ScoreFloorPolicy = "reject"
+)
+
+// Valid returns whether the policy is one of the supported ones.
+func (p ScoreFloorPolicy) Valid() bool {
+	switch p {
+	case ScoreFloorPickBest, ScoreFloorIgnore, ScoreFloorReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// PluginPanicPolicy is what a SchedulerProfile does when one of its plugins panics.
+type PluginPanicPolicy string
+
+const (
+	// PluginPanicSkip goes on with the cycle as if the plugin was skipped: the pods are not filtered
+	// by a panicking filter, nor scored by a panicking scorer. The cycle still fails if the picker
+	// panics, as there is no pick.
+	PluginPanicSkip PluginPanicPolicy = "skip"
+	// PluginPanicFail fails the cycle.
+	PluginPanicFail PluginPanicPolicy = "fail"
+)
+
+// Valid returns whether the policy is one of the supported ones.
+func (p PluginPanicPolicy) Valid() bool {
+	return p == PluginPanicSkip || p == PluginPanicFail
+}
+
+// WithPreCyclePlugins sets the given plugins as the PreCycle plugins.
+// If the SchedulerProfile has PreCycle plugins, this call replaces the existing plugins with the given ones.
+func (p *SchedulerProfile) WithPreCyclePlugins(plugins ...PreCycle) *SchedulerProfile {
+	p.preCyclePlugins = plugins
+	return p
 }
 
 // WithFilters sets the given filter plugins as the Filter plugins.
@@ -74,6 +164,160 @@ func (p *SchedulerProfile) WithPostCyclePlugins(plugins ...PostCycle) *Scheduler
 	return p
 }
 
+// WithTimeout sets the time budget of a cycle of the SchedulerProfile. Filters and scorers that are
+// still running when the budget is exhausted are aborted, and the ones that didn't start yet are
+// skipped. The results and the CycleState writes of aborted and skipped plugins are ignored, i.e.
+// the cycle continues with the pods filtered and scored so far, and the context of the aborted
+// plugins is cancelled. The PreCycle plugins, the picker and the PostCycle plugins always run.
+// Zero means no time budget.
+func (p *SchedulerProfile) WithTimeout(timeout time.Duration) *SchedulerProfile {
+	p.timeout = timeout
+	return p
+}
+
+// WithCandidateSampling bounds the number of pods scored in a cycle of the SchedulerProfile: when
+// more pods than sampleSize pass the filters, a uniform random sample of sampleSize pods is scored
+// and passed to the picker instead. This bounds the cost of scoring in very large pools, while the
+// best pod of a random sample is still likely among the best pods of the pool, e.g. the best of 64
+// pods is within the top 5% of the pool with a probability above 96%.
+// Zero means all the filtered pods are scored.
+func (p *SchedulerProfile) WithCandidateSampling(sampleSize int) *SchedulerProfile {
+	p.candidateSampleSize = sampleSize
+	return p
+}
+
+// WithScoreFloor sets the minimum acceptable score of the pods in a cycle of the SchedulerProfile:
+// the scored pods whose weighted score, divided by the sum of the weights of the scorers, is below
+// the floor are dropped before picking, so a pod that passed lenient filters is not picked while
+// every scorer rates it poorly. The policy decides what happens when no pod clears the floor,
+// defaulting to ScoreFloorPickBest if empty.
+// The floor is ignored if the profile has no scorers, and zero means no floor.
+func (p *SchedulerProfile) WithScoreFloor(floor float64, policy ScoreFloorPolicy) *SchedulerProfile {
+	if policy == "" {
+		policy = ScoreFloorPickBest
+	}
+	p.scoreFloor = floor
+	p.scoreFloorPolicy = policy
+	return p
+}
+
+// WithScoreCache enables reusing the ranking of the pods across the identical requests of a burst:
+// the weighted scores of the candidate pods are cached for the given TTL, keyed by the fingerprint
+// of the request (its target model, criticality, session, the first promptPrefixLength bytes of its
+// prompt, or all of it if zero, and its headers except the request scoped ones), and the cycles of
+// the identical requests within the TTL only run the PreCycle plugins, the picker and the PostCycle
+// plugins on the cached scores of the pods still in the snapshot. The cached pods aren't filtered
+// again and the scores of the scorers are not written to the CycleState, so the TTL should be
+// short, typically tens of milliseconds.
+// Zero disables the cache.
+func (p *SchedulerProfile) WithScoreCache(ttl time.Duration, promptPrefixLength int) *SchedulerProfile {
+	p.scoreCache = nil
+	if ttl > 0 {
+		p.scoreCache = newScoreCache(ttl, promptPrefixLength)
+	}
+	return p
+}
+
+// WithFailurePolicy sets what the scheduler does when a cycle of the SchedulerProfile fails,
+// defaulting to ProfileRequired if empty. If fallbackProfile is not empty, the profile of that name
+// runs first instead of the failed one and its result stands for the failed profile, so the policy
+// only applies if the fallback profile fails too (fallbacks are not chained).
+func (p *SchedulerProfile) WithFailurePolicy(policy ProfileFailurePolicy, fallbackProfile string) *SchedulerProfile {
+	if policy == "" {
+		policy = ProfileRequired
+	}
+	p.failurePolicy = policy
+	p.fallbackProfile = fallbackProfile
+	return p
+}
+
+// WithPluginPanicPolicy sets what the SchedulerProfile does when one of its PreCycle, Filter,
+// Scorer, Picker or PostCycle plugins panics, defaulting to PluginPanicSkip if empty. The panics are
+// recovered in any case, so a buggy plugin doesn't take the EPP down.
+func (p *SchedulerProfile) WithPluginPanicPolicy(policy PluginPanicPolicy) *SchedulerProfile {
+	if policy == "" {
+		policy = PluginPanicSkip
+	}
+	p.pluginPanicPolicy = policy
+	return p
+}
+
+// SetEnvironment sets the Environment of the SchedulerProfile, and of its plugins that implement
+// EnvironmentAware. It's called by the scheduler before the profile is used, after it's built.
+func (p *SchedulerProfile) SetEnvironment(env Environment) {
+	p.rand = env.Rand
+	if p.scoreCache != nil {
+		p.scoreCache.setClock(env.Clock)
+	}
+	for _, plugin := range p.plugins() {
+		if aware, ok := plugin.(EnvironmentAware); ok {
+			aware.SetEnvironment(env)
+		}
+	}
+}
+
+// FailurePolicy returns the failure policy and the fallback profile of the SchedulerProfile.
+func (p *SchedulerProfile) FailurePolicy() (ProfileFailurePolicy, string) {
+	return p.failurePolicy, p.fallbackProfile
+}
+
+// Scorers returns the weighted scorers of the SchedulerProfile.
+func (p *SchedulerProfile) Scorers() []*WeightedScorer {
+	return p.scorers
+}
+
+// ObservablePlugins returns the plugins of the SchedulerProfile that implement Observable, once
+// per plugin name.
+func (p *SchedulerProfile) ObservablePlugins() []Observable {
+	observables := []Observable{}
+	seen := map[string]bool{}
+	for _, plugin := range p.plugins() {
+		if observable, ok := plugin.(Observable); ok && !seen[plugin.Name()] {
+			seen[plugin.Name()] = true
+			observables = append(observables, observable)
+		}
+	}
+	return observables
+}
+
+// StatefulPlugins returns the plugins of the SchedulerProfile that implement Stateful, once
+// per plugin name.
+func (p *SchedulerProfile) StatefulPlugins() []Stateful {
+	statefuls := []Stateful{}
+	seen := map[string]bool{}
+	for _, plugin := range p.plugins() {
+		if stateful, ok := plugin.(Stateful); ok && !seen[plugin.Name()] {
+			seen[plugin.Name()] = true
+			statefuls = append(statefuls, stateful)
+		}
+	}
+	return statefuls
+}
+
+// plugins returns all the plugins of the SchedulerProfile, in the order of the scheduling cycle.
+func (p *SchedulerProfile) plugins() []Plugin {
+	plugins := []Plugin{}
+	for _, plugin := range p.preCyclePlugins {
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range p.filters {
+		plugins = append(plugins, plugin)
+	}
+	for _, scorer := range p.scorers {
+		plugins = append(plugins, scorer.Scorer)
+	}
+	if p.picker != nil {
+		plugins = append(plugins, p.picker)
+	}
+	for _, plugin := range p.postCyclePlugins {
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range p.PostResponsePlugins {
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
 // AddPlugins adds the given plugins to all scheduler plugins according to the interfaces each plugin implements.
 // A plugin may implement more than one scheduler plugin interface.
 // Special Case: In order to add a scorer, one must use the scorer.NewWeightedScorer function in order to provide a weight.
@@ -87,6 +331,9 @@ func (p *SchedulerProfile) AddPlugins(pluginObjects ...Plugin) error {
 		} else if scorer, ok := plugin.(Scorer); ok { // if we got a Scorer instead of WeightedScorer that's an error.
 			return fmt.Errorf("failed to register scorer '%s' without a weight. follow function documentation to register a scorer", scorer.Name())
 		}
+		if preCyclePlugin, ok := plugin.(PreCycle); ok {
+			p.preCyclePlugins = append(p.preCyclePlugins, preCyclePlugin)
+		}
 		if filter, ok := plugin.(Filter); ok {
 			p.filters = append(p.filters, filter)
 		}
@@ -107,56 +354,238 @@ func (p *SchedulerProfile) AddPlugins(pluginObjects ...Plugin) error {
 }
 
 // RunCycle runs a SchedulerProfile cycle. In other words, it invokes all the SchedulerProfile plugins in this
-// order - Filters, Scorers, Picker, PostCyclePlugins. After completing all, it returns the result.
+// order - PreCyclePlugins, Filters, Scorers, Picker, PostCyclePlugins. After completing all, it returns the result.
 func (p *SchedulerProfile) RunCycle(ctx *types.SchedulingContext) (*types.Result, error) {
-	pods := p.runFilterPlugins(ctx)
+	result, err := p.RunCycleUntilPick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.RunPostCycle(ctx, result)
+}
+
+// RunCycleUntilPick runs a SchedulerProfile cycle up to its Picker, without its PostCyclePlugins, so
+// a caller that may abandon the cycle only runs them with RunPostCycle once it keeps the result.
+func (p *SchedulerProfile) RunCycleUntilPick(ctx *types.SchedulingContext) (*types.Result, error) {
+	if p.timeout > 0 {
+		cycleCtx := *ctx
+		var cancel context.CancelFunc
+		cycleCtx.Context, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+		ctx = &cycleCtx
+	}
+
+	if err := p.runPreCyclePlugins(ctx); err != nil {
+		return nil, rejected(ctx, RejectionPluginPanic, err)
+	}
+
+	trace := schedulingtrace.FromContext(ctx).Profile(ctx.ProfileName)
+	trace.Start(ctx.PodsSnapshot)
+
+	fingerprint := ""
+	if p.scoreCache != nil && ctx.Req != nil {
+		fingerprint = p.scoreCache.fingerprint(ctx.Req)
+		weightedScorePerPod, ok := p.scoreCache.get(fingerprint, ctx.PodsSnapshot)
+		metrics.RecordSchedulerScoreCacheLookup(ctx.ProfileName, ok)
+		if ok {
+			ctx.Logger.V(logutil.DEBUG).Info("Reusing the cached scores of an identical request", "pods", len(weightedScorePerPod))
+			result, err := p.runPickerPlugin(ctx, weightedScorePerPod)
+			if err != nil {
+				return nil, rejected(ctx, RejectionPluginPanic, err)
+			}
+			trace.Pick(p.picker.Name(), weightedScorePerPod, true, result)
+			return result, nil
+		}
+	}
+
+	pods, err := p.runFilterPlugins(ctx)
+	if err != nil {
+		return nil, rejected(ctx, RejectionPluginPanic, err)
+	}
 	if len(pods) == 0 {
-		return nil, errutil.Error{Code: errutil.Internal, Msg: "no pods available for the given request"}
+		return nil, rejected(ctx, RejectionNoPodsAvailable, ErrNoPodsAvailable)
 	}
+	pods = p.sampleCandidates(ctx, pods)
 	// if we got here, there is at least one pod to score
-	weightedScorePerPod := p.runScorerPlugins(ctx, pods)
+	weightedScorePerPod, complete, err := p.runScorerPlugins(ctx, pods)
+	if err != nil {
+		return nil, rejected(ctx, RejectionPluginPanic, err)
+	}
+	weightedScorePerPod, err = p.applyScoreFloor(ctx, weightedScorePerPod)
+	if err != nil {
+		return nil, rejected(ctx, RejectionScoreFloor, err)
+	}
+	// the scores of a cycle that exceeded its time budget, or whose scorers panicked, may be missing
+	// plugins, so they aren't cached
+	if fingerprint != "" && complete && ctx.Err() == nil {
+		p.scoreCache.put(fingerprint, weightedScorePerPod)
+	}
 
-	result := p.runPickerPlugin(ctx, weightedScorePerPod)
+	result, err := p.runPickerPlugin(ctx, weightedScorePerPod)
+	if err != nil {
+		return nil, rejected(ctx, RejectionPluginPanic, err)
+	}
+	trace.Pick(p.picker.Name(), weightedScorePerPod, false, result)
 
-	p.runPostCyclePlugins(ctx, result)
+	return result, nil
+}
 
+// RunPostCycle runs the post-cycle plugins on the result of the cycle, and records the picked pod.
+func (p *SchedulerProfile) RunPostCycle(ctx *types.SchedulingContext, result *types.Result) (*types.Result, error) {
+	if err := p.runPostCyclePlugins(ctx, result); err != nil {
+		return result, rejected(ctx, RejectionPluginPanic, err)
+	}
+	if result != nil && result.TargetPod != nil {
+		metrics.RecordSchedulerPick(ctx.ProfileName, result.TargetPod.GetPod().NamespacedName.String())
+	}
 	return result, nil
 }
 
-func (p *SchedulerProfile) runFilterPlugins(ctx *types.SchedulingContext) []types.Pod {
+// The reasons of the scheduler profile cycles that shed or rejected the request, as recorded in the
+// metrics.
+const (
+	RejectionNoPodsAvailable = "no-pods-available"
+	RejectionScoreFloor      = "score-floor"
+	RejectionPluginPanic     = "plugin-panic"
+)
+
+// rejected records that the cycle shed or rejected the request for the given reason, and returns
+// the error of the cycle.
+func rejected(ctx *types.SchedulingContext, reason string, err error) error {
+	metrics.RecordSchedulerRejection(ctx.ProfileName, reason)
+	return err
+}
+
+func (p *SchedulerProfile) runPreCyclePlugins(ctx *types.SchedulingContext) error {
+	for _, plugin := range p.preCyclePlugins {
+		ctx.Logger.V(logutil.DEBUG).Info("Running pre-cycle plugin", "plugin", plugin.Name())
+		before := time.Now()
+		_, err := runRecovered(ctx, PreCyclePluginType, plugin.Name(), func() any { plugin.PreCycle(ctx); return nil })
+		RecordPluginLatency(ctx, ctx.ProfileName, PreCyclePluginType, plugin.Name(), before)
+		if err != nil && p.pluginPanicPolicy == PluginPanicFail {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *SchedulerProfile) runFilterPlugins(ctx *types.SchedulingContext) ([]types.Pod, error) {
 	loggerDebug := ctx.Logger.V(logutil.DEBUG)
 	filteredPods := ctx.PodsSnapshot
 	loggerDebug.Info("Before running filter plugins", "pods", filteredPods)
+	trace := schedulingtrace.FromContext(ctx).Profile(ctx.ProfileName)
+
+	// The results of the leading CacheableFilters are reused across the requests of the same snapshot
+	// generation, as long as the filters before them were cached too.
+	cacheable := p.filterCache != nil && ctx.SnapshotGeneration != 0
+	cacheKey := ""
+	for i, filter := range p.filters {
+		candidates := filteredPods
+		if cacheableFilter, ok := filter.(CacheableFilter); ok && cacheable && ctx.Req != nil {
+			cacheKey = filterCacheKey(cacheKey, i, cacheableFilter.FilterCacheKey(ctx.Req))
+			if pods, ok := p.filterCache.get(ctx.SnapshotGeneration, cacheKey, candidates); ok {
+				filteredPods = pods
+				loggerDebug.Info("Cached filter plugin result", "plugin", filter.Name(), "pods", filteredPods)
+				metrics.RecordSchedulerFilterRemainingPods(ctx.ProfileName, filter.Name(), len(filteredPods))
+				trace.Filter(filter.Name(), candidates, filteredPods, true)
+				if len(filteredPods) == 0 {
+					break
+				}
+				continue
+			}
+		} else {
+			cacheable = false
+		}
 
-	for _, filter := range p.filters {
 		loggerDebug.Info("Running filter plugin", "plugin", filter.Name())
 		before := time.Now()
-		filteredPods = filter.Filter(ctx, filteredPods)
-		metrics.RecordSchedulerPluginProcessingLatency(FilterPluginType, filter.Name(), time.Since(before))
+		pods, ok, err := runWithinBudget(ctx, FilterPluginType, filter.Name(), func(ctx *types.SchedulingContext) []types.Pod {
+			return filter.Filter(ctx, candidates)
+		})
+		RecordPluginLatency(ctx, ctx.ProfileName, FilterPluginType, filter.Name(), before)
+		if err != nil {
+			if p.pluginPanicPolicy == PluginPanicFail {
+				return nil, err
+			}
+			cacheable = false
+			trace.SkipFilter(filter.Name(), len(candidates))
+			continue
+		}
+		if !ok {
+			ctx.Logger.V(logutil.DEFAULT).Info("Skipped filter plugin, the scheduling cycle exceeded its time budget", "plugin", filter.Name())
+			cacheable = false // the result of the next filters depends on the skipped filter
+			trace.SkipFilter(filter.Name(), len(candidates))
+			continue
+		}
+		if cacheable {
+			p.filterCache.put(ctx.SnapshotGeneration, cacheKey, pods)
+		}
+		filteredPods = pods
 		loggerDebug.Info("Filter plugin result", "plugin", filter.Name(), "pods", filteredPods)
+		metrics.RecordSchedulerFilterRemainingPods(ctx.ProfileName, filter.Name(), len(filteredPods))
+		trace.Filter(filter.Name(), candidates, filteredPods, false)
 		if len(filteredPods) == 0 {
 			break
 		}
 	}
 	loggerDebug.Info("After running filter plugins")
 
-	return filteredPods
+	return filteredPods, nil
 }
 
-func (p *SchedulerProfile) runScorerPlugins(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+// sampleCandidates returns a uniform random sample of the given pods, if candidate sampling is
+// enabled and there are more pods than the sample size.
+func (p *SchedulerProfile) sampleCandidates(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	if p.candidateSampleSize <= 0 || len(pods) <= p.candidateSampleSize {
+		return pods
+	}
+	// partial Fisher-Yates shuffle of a copy, as the given slice may be the pods snapshot.
+	sample := make([]types.Pod, len(pods))
+	copy(sample, pods)
+	for i := range p.candidateSampleSize {
+		j := i + p.rand.Intn(len(sample)-i)
+		sample[i], sample[j] = sample[j], sample[i]
+	}
+	ctx.Logger.V(logutil.DEBUG).Info("Sampled candidates", "candidates", len(pods), "sampleSize", p.candidateSampleSize)
+	return sample[:p.candidateSampleSize]
+}
+
+// runScorerPlugins returns the weighted scores of the given pods, and whether all the scorers
+// scored them.
+func (p *SchedulerProfile) runScorerPlugins(ctx *types.SchedulingContext, pods []types.Pod) (map[types.Pod]float64, bool, error) {
 	loggerDebug := ctx.Logger.V(logutil.DEBUG)
 	loggerDebug.Info("Before running scorer plugins", "pods", pods)
 
+	trace := schedulingtrace.FromContext(ctx).Profile(ctx.ProfileName)
 	weightedScorePerPod := make(map[types.Pod]float64, len(pods))
 	for _, pod := range pods {
 		weightedScorePerPod[pod] = float64(0) // initialize weighted score per pod with 0 value
 	}
+	complete := true
 	// Iterate through each scorer in the chain and accumulate the weighted scores.
 	for _, scorer := range p.scorers {
 		loggerDebug.Info("Running scorer", "scorer", scorer.Name())
 		before := time.Now()
-		scores := scorer.Score(ctx, pods)
-		metrics.RecordSchedulerPluginProcessingLatency(ScorerPluginType, scorer.Name(), time.Since(before))
+		scores, ok, err := runWithinBudget(ctx, ScorerPluginType, scorer.Name(), func(ctx *types.SchedulingContext) map[types.Pod]float64 {
+			return scorer.Score(ctx, pods)
+		})
+		RecordPluginLatency(ctx, ctx.ProfileName, ScorerPluginType, scorer.Name(), before)
+		if err != nil {
+			if p.pluginPanicPolicy == PluginPanicFail {
+				return nil, false, err
+			}
+			complete = false
+			continue
+		}
+		if !ok {
+			ctx.Logger.V(logutil.DEFAULT).Info("Skipped scorer plugin, the scheduling cycle exceeded its time budget", "scorer", scorer.Name())
+			continue
+		}
+		ScorerScoresStateKey(scorer.Name()).Write(ctx.CycleState, ScorerScores(scores))
+		trace.Score(scorer.Name(), scorer.Weight(), scores)
+		for _, score := range scores {
+			metrics.RecordSchedulerScorerScore(ctx.ProfileName, scorer.Name(), score)
+		}
+		scores = normalizeScores(scores, scorer.Normalization())
 		for pod, score := range scores { // weight is relative to the sum of weights
 			weightedScorePerPod[pod] += score * float64(scorer.Weight())
 		}
@@ -164,10 +593,133 @@ func (p *SchedulerProfile) runScorerPlugins(ctx *types.SchedulingContext, pods [
 	}
 	loggerDebug.Info("After running scorer plugins")
 
-	return weightedScorePerPod
+	return weightedScorePerPod, complete, nil
+}
+
+// RecordPluginLatency records the processing latency of a plugin that started running at the given
+// time in the metrics, in the timeline of the request if it's recorded, and as a tracing span.
+func RecordPluginLatency(ctx context.Context, profileName, pluginType, pluginName string, before time.Time) {
+	metrics.RecordSchedulerPluginProcessingLatency(profileName, pluginType, pluginName, time.Since(before))
+	if tl := timeline.FromContext(ctx); tl != nil {
+		name := pluginType + "/" + pluginName
+		if profileName != "" {
+			name = profileName + "/" + name
+		}
+		tl.Span(name, before)
+	}
+	tracing.Span(ctx, pluginType+"/"+pluginName, before, attribute.String("epp.profile", profileName),
+		attribute.String("epp.plugin.type", pluginType), attribute.String("epp.plugin.name", pluginName))
+}
+
+// runWithinBudget runs the given plugin function with the scheduling context and returns its
+// result, unless the deadline of the scheduling context expires first. It returns false if the
+// plugin was skipped because the deadline already expired, or aborted because the deadline expired
+// while it was running. An aborted plugin keeps running in the background with a cancelled context,
+// but its result is discarded, and so are its writes to the CycleState, which are staged until it
+// completes in time. It returns an error if the plugin panicked (see runRecovered).
+func runWithinBudget[T any](ctx *types.SchedulingContext, pluginType, pluginName string, run func(*types.SchedulingContext) T) (T, bool, error) {
+	var zero T
+	if ctx.Err() != nil {
+		recordBudgetViolation(ctx, pluginType, pluginName)
+		return zero, false, nil
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		res, err := runRecovered(ctx, pluginType, pluginName, func() T { return run(ctx) })
+		return res, true, err
+	}
+
+	pluginCtx := *ctx
+	var cancel context.CancelFunc
+	pluginCtx.Context, cancel = context.WithCancel(ctx)
+	defer cancel()
+	pluginCtx.CycleState = ctx.CycleState.Stage()
+
+	type result struct {
+		res T
+		err error
+	}
+	done := make(chan result, 1) // buffered, so an aborted plugin doesn't leak
+	go func() {
+		// the panics of the plugin are recovered in its goroutine, or they would crash the EPP
+		res, err := runRecovered(&pluginCtx, pluginType, pluginName, func() T { return run(&pluginCtx) })
+		done <- result{res: res, err: err}
+	}()
+	select {
+	case r := <-done:
+		pluginCtx.CycleState.Commit()
+		return r.res, true, r.err
+	case <-ctx.Done():
+		recordBudgetViolation(ctx, pluginType, pluginName)
+		return zero, false, nil
+	}
+}
+
+// runRecovered runs the given plugin function and returns its result, or an error if the plugin
+// panicked. The panic is logged with its stack trace and recorded in the metrics.
+func runRecovered[T any](ctx *types.SchedulingContext, pluginType, pluginName string, run func() T) (res T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ctx.Logger.Error(fmt.Errorf("%v", r), "Scheduler plugin panicked", "profile", ctx.ProfileName,
+				"pluginType", pluginType, "plugin", pluginName, "stack", string(debug.Stack()))
+			metrics.RecordSchedulerPluginPanic(ctx.ProfileName, pluginType, pluginName)
+			err = errutil.Error{Code: errutil.Internal, Msg: fmt.Sprintf("%s plugin '%s' panicked", pluginType, pluginName)}
+		}
+	}()
+	return run(), nil
+}
+
+// recordBudgetViolation records a budget violation, unless the context was canceled for another
+// reason than its deadline (e.g. the client went away).
+func recordBudgetViolation(ctx *types.SchedulingContext, pluginType, pluginName string) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		metrics.RecordSchedulerPluginBudgetViolation(ctx.ProfileName, pluginType, pluginName)
+	}
 }
 
-func (p *SchedulerProfile) runPickerPlugin(ctx *types.SchedulingContext, weightedScorePerPod map[types.Pod]float64) *types.Result {
+// applyScoreFloor drops the scored pods below the score floor, applying the score floor policy if
+// none of them clears it.
+func (p *SchedulerProfile) applyScoreFloor(ctx *types.SchedulingContext, weightedScorePerPod map[types.Pod]float64) (map[types.Pod]float64, error) {
+	totalWeight := 0
+	for _, scorer := range p.scorers {
+		totalWeight += scorer.Weight()
+	}
+	if p.scoreFloor == 0 || totalWeight == 0 {
+		return weightedScorePerPod, nil
+	}
+
+	floor := p.scoreFloor * float64(totalWeight)
+	cleared := make(map[types.Pod]float64, len(weightedScorePerPod))
+	bestScore := math.Inf(-1)
+	for pod, score := range weightedScorePerPod {
+		if score >= floor {
+			cleared[pod] = score
+		}
+		bestScore = max(bestScore, score)
+	}
+	if len(cleared) > 0 {
+		ctx.Logger.V(logutil.DEBUG).Info("Dropped the pods below the score floor", "floor", p.scoreFloor, "dropped", len(weightedScorePerPod)-len(cleared))
+		return cleared, nil
+	}
+
+	ctx.Logger.V(logutil.VERBOSE).Info("No pod cleared the score floor", "floor", p.scoreFloor, "bestScore", bestScore/float64(totalWeight), "policy", p.scoreFloorPolicy)
+	switch p.scoreFloorPolicy {
+	case ScoreFloorIgnore:
+		return weightedScorePerPod, nil
+	case ScoreFloorReject:
+		return nil, errutil.Error{Code: errutil.InferencePoolResourceExhausted, Msg: "no pod cleared the score floor for the given request"}
+	default:
+		for pod, score := range weightedScorePerPod {
+			if score == bestScore {
+				cleared[pod] = score
+			}
+		}
+		return cleared, nil
+	}
+}
+
+// runPickerPlugin runs the picker on the given weighted scores. It returns an error if the picker
+// panicked, whatever the plugin panic policy, as there is no pick then.
+func (p *SchedulerProfile) runPickerPlugin(ctx *types.SchedulingContext, weightedScorePerPod map[types.Pod]float64) (*types.Result, error) {
 	loggerDebug := ctx.Logger.V(logutil.DEBUG)
 	scoredPods := make([]*types.ScoredPod, len(weightedScorePerPod))
 	i := 0
@@ -175,21 +727,33 @@ func (p *SchedulerProfile) runPickerPlugin(ctx *types.SchedulingContext, weighte
 		scoredPods[i] = &types.ScoredPod{Pod: pod, Score: score}
 		i++
 	}
+	// The candidates are passed in a stable order, so the picks only depend on the Environment.
+	slices.SortFunc(scoredPods, func(a, b *types.ScoredPod) int {
+		aName, bName := a.GetPod().NamespacedName, b.GetPod().NamespacedName
+		return cmp.Or(strings.Compare(aName.Namespace, bName.Namespace), strings.Compare(aName.Name, bName.Name))
+	})
 
 	loggerDebug.Info("Before running picker plugin", "pods weighted score", fmt.Sprint(weightedScorePerPod))
 	before := time.Now()
-	result := p.picker.Pick(ctx, scoredPods)
-	metrics.RecordSchedulerPluginProcessingLatency(PickerPluginType, p.picker.Name(), time.Since(before))
+	result, err := runRecovered(ctx, PickerPluginType, p.picker.Name(), func() *types.Result { return p.picker.Pick(ctx, scoredPods) })
+	RecordPluginLatency(ctx, ctx.ProfileName, PickerPluginType, p.picker.Name(), before)
+	if err != nil {
+		return nil, err
+	}
 	loggerDebug.Info("After running picker plugin", "result", result)
 
-	return result
+	return result, nil
 }
 
-func (p *SchedulerProfile) runPostCyclePlugins(ctx *types.SchedulingContext, res *types.Result) {
+func (p *SchedulerProfile) runPostCyclePlugins(ctx *types.SchedulingContext, res *types.Result) error {
 	for _, plugin := range p.postCyclePlugins {
 		ctx.Logger.V(logutil.DEBUG).Info("Running post-cycle plugin", "plugin", plugin.Name())
 		before := time.Now()
-		plugin.PostCycle(ctx, res)
-		metrics.RecordSchedulerPluginProcessingLatency(PostCyclePluginType, plugin.Name(), time.Since(before))
+		_, err := runRecovered(ctx, PostCyclePluginType, plugin.Name(), func() any { plugin.PostCycle(ctx, res); return nil })
+		RecordPluginLatency(ctx, ctx.ProfileName, PostCyclePluginType, plugin.Name(), before)
+		if err != nil && p.pluginPanicPolicy == PluginPanicFail {
+			return err
+		}
 	}
+	return nil
 }
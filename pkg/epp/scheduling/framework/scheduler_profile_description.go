@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProfileDescription is the effective configuration of a SchedulerProfile, as printed when the
+// configuration of the Endpoint Picker is validated.
+type ProfileDescription struct {
+	PreCyclePlugins     []string             `json:"preCyclePlugins,omitempty"`
+	Filters             []string             `json:"filters,omitempty"`
+	Scorers             []ScorerDescription  `json:"scorers,omitempty"`
+	Picker              string               `json:"picker,omitempty"`
+	PostCyclePlugins    []string             `json:"postCyclePlugins,omitempty"`
+	PostResponsePlugins []string             `json:"postResponsePlugins,omitempty"`
+	Timeout             string               `json:"timeout,omitempty"`
+	CandidateSampleSize int                  `json:"candidateSampleSize,omitempty"`
+	ScoreFloor          float64              `json:"scoreFloor,omitempty"`
+	ScoreFloorPolicy    ScoreFloorPolicy     `json:"scoreFloorPolicy,omitempty"`
+	ScoreCacheTTL       string               `json:"scoreCacheTTL,omitempty"`
+	FailurePolicy       ProfileFailurePolicy `json:"failurePolicy"`
+	FallbackProfile     string               `json:"fallbackProfile,omitempty"`
+	PluginPanicPolicy   PluginPanicPolicy    `json:"pluginPanicPolicy"`
+}
+
+// ScorerDescription is the effective configuration of a WeightedScorer.
+type ScorerDescription struct {
+	Name          string             `json:"name"`
+	Weight        int                `json:"weight"`
+	Normalization ScoreNormalization `json:"normalization,omitempty"`
+}
+
+// Describe returns the effective configuration of the SchedulerProfile, its plugins being listed
+// by name in the order they run.
+func (p *SchedulerProfile) Describe() ProfileDescription {
+	description := ProfileDescription{
+		PreCyclePlugins:     pluginNames(p.preCyclePlugins),
+		Filters:             pluginNames(p.filters),
+		PostCyclePlugins:    pluginNames(p.postCyclePlugins),
+		PostResponsePlugins: pluginNames(p.PostResponsePlugins),
+		Timeout:             durationString(p.timeout),
+		CandidateSampleSize: p.candidateSampleSize,
+		FailurePolicy:       p.failurePolicy,
+		FallbackProfile:     p.fallbackProfile,
+		PluginPanicPolicy:   p.pluginPanicPolicy,
+	}
+	for _, scorer := range p.scorers {
+		description.Scorers = append(description.Scorers, ScorerDescription{
+			Name:          scorer.Name(),
+			Weight:        scorer.Weight(),
+			Normalization: scorer.Normalization(),
+		})
+	}
+	if p.picker != nil {
+		description.Picker = p.picker.Name()
+	}
+	if p.scoreFloor != 0 {
+		description.ScoreFloor = p.scoreFloor
+		description.ScoreFloorPolicy = p.scoreFloorPolicy
+	}
+	if p.scoreCache != nil {
+		description.ScoreCacheTTL = durationString(p.scoreCache.ttl)
+	}
+	return description
+}
+
+// Validate checks the consistency of the SchedulerProfile. The returned error joins all the
+// problems that would make the profile fail or misbehave, e.g. a missing picker or a negative
+// scorer weight, and the warnings are the settings that are likely mistakes but don't prevent the
+// profile from running, e.g. a scorer with a zero weight.
+func (p *SchedulerProfile) Validate() (warnings []string, err error) {
+	var errs []error
+	if p.picker == nil {
+		errs = append(errs, errors.New("a picker is required"))
+	}
+
+	scorers := map[string]bool{}
+	weights := 0
+	for _, scorer := range p.scorers {
+		switch weight := scorer.Weight(); {
+		case weight < 0:
+			errs = append(errs, fmt.Errorf("scorer '%s' has a negative weight %d", scorer.Name(), weight))
+		case weight == 0:
+			warnings = append(warnings, fmt.Sprintf("scorer '%s' has a zero weight and doesn't affect the picks", scorer.Name()))
+		default:
+			weights += weight
+		}
+		if !scorer.Normalization().Valid() {
+			errs = append(errs, fmt.Errorf("scorer '%s' has an unknown normalization '%s'", scorer.Name(), scorer.Normalization()))
+		}
+		if scorers[scorer.Name()] {
+			warnings = append(warnings, fmt.Sprintf("scorer '%s' is registered more than once, so its scores are counted more than once", scorer.Name()))
+		}
+		scorers[scorer.Name()] = true
+	}
+
+	filters := map[string]bool{}
+	uncached := ""
+	for _, filter := range p.filters {
+		if filters[filter.Name()] {
+			warnings = append(warnings, fmt.Sprintf("filter '%s' is registered more than once", filter.Name()))
+		}
+		filters[filter.Name()] = true
+		// Only the results of the leading CacheableFilters are reused, see filterCache.
+		if _, cacheable := filter.(CacheableFilter); !cacheable && uncached == "" {
+			uncached = filter.Name()
+		} else if cacheable && uncached != "" {
+			warnings = append(warnings, fmt.Sprintf("filter '%s' is cacheable but runs after filter '%s' which isn't, so its results are not cached", filter.Name(), uncached))
+		}
+	}
+
+	if p.timeout < 0 {
+		errs = append(errs, fmt.Errorf("negative timeout %s", p.timeout))
+	}
+	if p.candidateSampleSize < 0 {
+		errs = append(errs, fmt.Errorf("negative candidate sample size %d", p.candidateSampleSize))
+	}
+	if p.scoreFloor != 0 {
+		if !p.scoreFloorPolicy.Valid() {
+			errs = append(errs, fmt.Errorf("unknown score floor policy '%s'", p.scoreFloorPolicy))
+		}
+		switch {
+		case weights == 0:
+			warnings = append(warnings, fmt.Sprintf("score floor %v is ignored as no scorer has a positive weight", p.scoreFloor))
+		case p.scoreFloor < 0 || p.scoreFloor > 1:
+			warnings = append(warnings, fmt.Sprintf("score floor %v is outside of [0, 1], the range of the scores", p.scoreFloor))
+		}
+	}
+	if !p.failurePolicy.Valid() {
+		errs = append(errs, fmt.Errorf("unknown failure policy '%s'", p.failurePolicy))
+	}
+	if !p.pluginPanicPolicy.Valid() {
+		errs = append(errs, fmt.Errorf("unknown plugin panic policy '%s'", p.pluginPanicPolicy))
+	}
+	return warnings, errors.Join(errs...)
+}
+
+func pluginNames[P Plugin](plugins []P) []string {
+	var names []string
+	for _, plugin := range plugins {
+		names = append(names, plugin.Name())
+	}
+	return names
+}
+
+// durationString returns the given duration as a string, or an empty string if it's zero.
+func durationString(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -18,14 +18,23 @@ package framework
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics" // Import config for thresholds
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/schedulingtrace"
 )
 
 func TestSchedulePlugins(t *testing.T) {
@@ -188,12 +197,603 @@ func TestSchedulePlugins(t *testing.T) {
 	}
 }
 
+func TestRunCycleTimeout(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+	}
+	fastScorer := &testPlugin{NameRes: "fast", ScoreRes: 0.8}
+	slowPlugin := &testSlowPlugin{}
+
+	tests := []struct {
+		name            string
+		profile         *SchedulerProfile
+		wantCandidates  int
+		wantWinnerScore float64
+	}{
+		{
+			name: "slow filter is aborted and its result ignored",
+			profile: NewSchedulerProfile().
+				WithFilters(slowPlugin).
+				WithScorers(NewWeightedScorer(fastScorer, 1)).
+				WithPicker(&testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod1"}}).
+				WithTimeout(10 * time.Millisecond),
+			wantCandidates: 2,
+			// the scorer is skipped too, as the budget is exhausted by the filter
+			wantWinnerScore: 0,
+		},
+		{
+			name: "slow scorer is aborted and its scores ignored",
+			profile: NewSchedulerProfile().
+				WithScorers(NewWeightedScorer(fastScorer, 1), NewWeightedScorer(slowPlugin, 1)).
+				WithPicker(&testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod1"}}).
+				WithTimeout(10 * time.Millisecond),
+			wantCandidates:  2,
+			wantWinnerScore: 0.8,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: uuid.NewString()}, nil, types.ToSchedulerPodMetrics(pods))
+			got, err := test.profile.RunCycle(ctx)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.TargetPod.GetPod().NamespacedName.Name != "pod1" {
+				t.Errorf("Unexpected target pod %v", got.TargetPod)
+			}
+			picker := test.profile.picker.(*testPlugin)
+			if picker.NumOfPickerCandidates != test.wantCandidates {
+				t.Errorf("Picker called with %d candidates, expected %d", picker.NumOfPickerCandidates, test.wantCandidates)
+			}
+			if picker.WinnderPodScore != test.wantWinnerScore {
+				t.Errorf("Winner pod score %v, expected %v", picker.WinnderPodScore, test.wantWinnerScore)
+			}
+		})
+	}
+}
+
+func TestRunCycleTimeoutDiscardsLateWrites(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+	}
+	fastScorer := &testStateWriter{key: "fast", wrote: make(chan struct{})}
+	slowScorer := &testStateWriter{key: "slow", wrote: make(chan struct{}), slow: true}
+	profile := NewSchedulerProfile().
+		WithScorers(NewWeightedScorer(fastScorer, 1), NewWeightedScorer(slowScorer, 1)).
+		WithPicker(&testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod1"}}).
+		WithTimeout(10 * time.Millisecond)
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: uuid.NewString()}, nil, types.ToSchedulerPodMetrics(pods))
+	if _, err := profile.RunCycle(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The writes of the scorer that completed in time are kept, and the ones of the aborted scorer
+	// are discarded.
+	<-slowScorer.wrote
+	if _, err := ctx.CycleState.Read("fast"); err != nil {
+		t.Errorf("Unexpected error reading the write of the fast scorer: %v", err)
+	}
+	if _, err := ctx.CycleState.Read("slow"); !errors.Is(err, types.ErrNotFound) {
+		t.Errorf("Got error %v reading the late write of the aborted scorer, want %v", err, types.ErrNotFound)
+	}
+}
+
+func TestRunCycleCandidateSampling(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{}
+	for i := range 10 {
+		pods = append(pods, &backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: fmt.Sprintf("pod%d", i)}}})
+	}
+
+	tests := []struct {
+		name           string
+		sampleSize     int
+		wantCandidates int
+	}{
+		{
+			name:           "sampling disabled",
+			wantCandidates: 10,
+		},
+		{
+			name:           "sample of the candidates is scored",
+			sampleSize:     3,
+			wantCandidates: 3,
+		},
+		{
+			name:           "sample larger than the candidates",
+			sampleSize:     20,
+			wantCandidates: 10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scorer := &testPlugin{NameRes: "scorer", ScoreRes: 0.5}
+			picker := &testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod1"}}
+			profile := NewSchedulerProfile().
+				WithScorers(NewWeightedScorer(scorer, 1)).
+				WithPicker(picker).
+				WithCandidateSampling(test.sampleSize)
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{RequestId: uuid.NewString()}, nil, types.ToSchedulerPodMetrics(pods))
+			if _, err := profile.RunCycle(ctx); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if scorer.NumOfScoredPods != test.wantCandidates {
+				t.Errorf("Scorer called with %d pods, expected %d", scorer.NumOfScoredPods, test.wantCandidates)
+			}
+			if picker.NumOfPickerCandidates != test.wantCandidates {
+				t.Errorf("Picker called with %d candidates, expected %d", picker.NumOfPickerCandidates, test.wantCandidates)
+			}
+			if len(ctx.PodsSnapshot) != len(pods) {
+				t.Errorf("Pods snapshot modified, got %d pods", len(ctx.PodsSnapshot))
+			}
+		})
+	}
+}
+
+func TestRunCycleFilterCache(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}},
+	}
+	cached := &testCacheablePlugin{testPlugin: testPlugin{NameRes: "cached", FilterRes: []k8stypes.NamespacedName{{Name: "pod1"}, {Name: "pod2"}}}}
+	notCached := &testPlugin{NameRes: "not-cached", FilterRes: []k8stypes.NamespacedName{{Name: "pod1"}, {Name: "pod2"}}}
+	afterNotCached := &testCacheablePlugin{testPlugin: testPlugin{NameRes: "after-not-cached", FilterRes: []k8stypes.NamespacedName{{Name: "pod2"}}}}
+	picker := &testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod2"}}
+	profile := NewSchedulerProfile().WithFilters(cached, notCached, afterNotCached).WithPicker(picker)
+
+	cycles := []struct {
+		name           string
+		generation     uint64
+		critical       bool
+		wantFilterRuns int // of the cacheable filters
+	}{
+		{name: "first cycle", generation: 1, wantFilterRuns: 1},
+		{name: "same generation", generation: 1, wantFilterRuns: 1},
+		{name: "other cache key", generation: 1, critical: true, wantFilterRuns: 2},
+		{name: "same generation and cache key", generation: 1, critical: true, wantFilterRuns: 2},
+		{name: "new generation", generation: 2, wantFilterRuns: 3},
+		{name: "unknown generation", generation: 0, wantFilterRuns: 4},
+	}
+	for i, cycle := range cycles {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{Critical: cycle.critical}, nil, types.ToSchedulerPodMetrics(pods))
+		ctx.SnapshotGeneration = cycle.generation
+		result, err := profile.RunCycle(ctx)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", cycle.name, err)
+		}
+		if got := result.TargetPod.GetPod().NamespacedName.Name; got != "pod2" {
+			t.Errorf("%s: got target pod %s, expected pod2", cycle.name, got)
+		}
+		if cached.FilterCallCount != cycle.wantFilterRuns {
+			t.Errorf("%s: cacheable filter ran %d times, expected %d", cycle.name, cached.FilterCallCount, cycle.wantFilterRuns)
+		}
+		// The filters after a filter that isn't cacheable always run.
+		if notCached.FilterCallCount != i+1 || afterNotCached.FilterCallCount != i+1 {
+			t.Errorf("%s: filters after the first ran %d and %d times, expected %d", cycle.name, notCached.FilterCallCount, afterNotCached.FilterCallCount, i+1)
+		}
+	}
+}
+
+func TestRunCycleScoreCache(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}},
+	}
+	filter := &testPlugin{NameRes: "filter", FilterRes: []k8stypes.NamespacedName{{Name: "pod1"}, {Name: "pod2"}}}
+	scorer := &testPlugin{NameRes: "scorer", ScoreRes: 0.8}
+	picker := &testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod2"}}
+	profile := NewSchedulerProfile().
+		WithFilters(filter).
+		WithScorers(NewWeightedScorer(scorer, 1)).
+		WithPicker(picker).
+		WithPostCyclePlugins(picker).
+		WithScoreCache(50*time.Millisecond, 4)
+	now := time.Now()
+	profile.scoreCache.now = func() time.Time { return now }
+
+	cycles := []struct {
+		name           string
+		prompt         string
+		headers        map[string]string
+		elapsed        time.Duration
+		wantScorerRuns int
+	}{
+		{name: "first request", prompt: "abcdef", headers: map[string]string{"x-request-id": "1"}, wantScorerRuns: 1},
+		{name: "identical request", prompt: "abcdef", headers: map[string]string{"x-request-id": "2"}, wantScorerRuns: 1},
+		{name: "same prompt prefix", prompt: "abcdxy", headers: map[string]string{"x-request-id": "3"}, wantScorerRuns: 1},
+		{name: "other prompt prefix", prompt: "xyzdef", headers: map[string]string{"x-request-id": "4"}, wantScorerRuns: 2},
+		{name: "other headers", prompt: "abcdef", headers: map[string]string{"x-request-id": "5", "x-tenant": "a"}, wantScorerRuns: 3},
+		{name: "expired scores", prompt: "abcdef", headers: map[string]string{"x-request-id": "6"}, elapsed: 100 * time.Millisecond, wantScorerRuns: 4},
+	}
+	for i, cycle := range cycles {
+		now = now.Add(cycle.elapsed)
+		req := &types.LLMRequest{TargetModel: "model", Prompt: cycle.prompt, Headers: cycle.headers}
+		result, err := profile.RunCycle(types.NewSchedulingContext(context.Background(), req, nil, types.ToSchedulerPodMetrics(pods)))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", cycle.name, err)
+		}
+		if got := result.TargetPod.GetPod().NamespacedName.Name; got != "pod2" {
+			t.Errorf("%s: got target pod %s, expected pod2", cycle.name, got)
+		}
+		if scorer.ScoreCallCount != cycle.wantScorerRuns || filter.FilterCallCount != cycle.wantScorerRuns {
+			t.Errorf("%s: filter and scorer ran %d and %d times, expected %d", cycle.name, filter.FilterCallCount, scorer.ScoreCallCount, cycle.wantScorerRuns)
+		}
+		// The picker and the PostCycle plugins run on every cycle, with the filtered pods and their scores.
+		if picker.PickCallCount != i+1 || picker.PostScheduleCallCount != i+1 {
+			t.Errorf("%s: picker and PostCycle ran %d and %d times, expected %d", cycle.name, picker.PickCallCount, picker.PostScheduleCallCount, i+1)
+		}
+		if picker.NumOfPickerCandidates != 2 || picker.WinnderPodScore != 0.8 {
+			t.Errorf("%s: picker got %d candidates and winner score %v, expected 2 and 0.8", cycle.name, picker.NumOfPickerCandidates, picker.WinnderPodScore)
+		}
+	}
+}
+
+func TestRunCyclePreCycle(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+	}
+	plugin := &testPreCyclePlugin{}
+	picker := &testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod2"}}
+	profile := NewSchedulerProfile().WithPicker(picker)
+	if err := profile.AddPlugins(plugin); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{Prompt: "pod2"}, nil, types.ToSchedulerPodMetrics(pods))
+	if _, err := profile.RunCycle(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plugin.preCycleCallCount != 1 {
+		t.Errorf("PreCycle() called %d times, expected 1", plugin.preCycleCallCount)
+	}
+	// The filter only keeps the pod named by the state written by PreCycle.
+	if picker.NumOfPickerCandidates != 1 {
+		t.Errorf("Picker called with %d candidates, expected 1", picker.NumOfPickerCandidates)
+	}
+}
+
+func TestRunCycleSchedulingTrace(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod3"}}},
+	}
+	filter := &testPlugin{NameRes: "filter", FilterRes: []k8stypes.NamespacedName{{Namespace: "default", Name: "pod1"}, {Namespace: "default", Name: "pod2"}}}
+	scorer := &testPlugin{NameRes: "scorer", ScoreRes: 0.8}
+	picker := &testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}
+	profile := NewSchedulerProfile().WithFilters(filter).WithScorers(NewWeightedScorer(scorer, 2)).WithPicker(picker)
+
+	trace := schedulingtrace.New("req-1", "model")
+	ctx := types.NewSchedulingContext(schedulingtrace.NewContext(context.Background(), trace), &types.LLMRequest{}, nil, types.ToSchedulerPodMetrics(pods))
+	ctx.ProfileName = "default"
+	if _, err := profile.RunCycle(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []*schedulingtrace.ProfileTrace{{
+		Profile:        "default",
+		Candidates:     3,
+		Filters:        []schedulingtrace.FilterDecision{{Plugin: "filter", FilteredOut: []string{"default/pod3"}, Remaining: 2}},
+		Scores:         map[string]map[string]float64{"scorer": {"default/pod1": 0.8, "default/pod2": 0.8}},
+		Weights:        map[string]int{"scorer": 2},
+		WeightedScores: map[string]float64{"default/pod1": 1.6, "default/pod2": 1.6},
+		Picker:         "picker",
+		TargetPod:      "default/pod2",
+	}}
+	if diff := cmp.Diff(want, trace.Profiles, cmpopts.IgnoreUnexported(schedulingtrace.ProfileTrace{})); diff != "" {
+		t.Errorf("Unexpected scheduling trace (-want +got): %s", diff)
+	}
+}
+
+func TestRunCycleDecisionMetrics(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod3"}}},
+	}
+	filter := &testPlugin{NameRes: "keep-two", FilterRes: []k8stypes.NamespacedName{{Namespace: "default", Name: "pod1"}, {Namespace: "default", Name: "pod2"}}}
+	scorer := &testPlugin{NameRes: "constant", ScoreRes: 0.5}
+	picker := &testPlugin{NameRes: "pick-pod2", PickRes: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}
+	emptyFilter := &testPlugin{NameRes: "keep-none"}
+
+	run := func(profileName string, profile *SchedulerProfile) error {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, types.ToSchedulerPodMetrics(pods))
+		ctx.ProfileName = profileName
+		_, err := profile.RunCycle(ctx)
+		return err
+	}
+	scheduled := NewSchedulerProfile().WithFilters(filter).WithScorers(NewWeightedScorer(scorer, 1)).WithPicker(picker)
+	for range 2 {
+		if err := run("metrics-scheduled", scheduled); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := run("metrics-rejected", NewSchedulerProfile().WithFilters(emptyFilter).WithPicker(picker)); err == nil {
+		t.Fatal("Expected an error, got none")
+	}
+
+	histogram := func(histogram *prometheus.HistogramVec, labels ...string) (uint64, float64) {
+		metric := &dto.Metric{}
+		if err := histogram.WithLabelValues(labels...).(prometheus.Histogram).Write(metric); err != nil {
+			t.Fatalf("Failed to read histogram: %v", err)
+		}
+		return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+	}
+	if count, sum := histogram(metrics.SchedulerFilterRemainingPods, "metrics-scheduled", "keep-two"); count != 2 || sum != 4 {
+		t.Errorf("Unexpected remaining pods of filter keep-two, want 2 samples summing to 4, got %d samples summing to %v", count, sum)
+	}
+	if count, sum := histogram(metrics.SchedulerFilterRemainingPods, "metrics-rejected", "keep-none"); count != 1 || sum != 0 {
+		t.Errorf("Unexpected remaining pods of filter keep-none, want 1 sample summing to 0, got %d samples summing to %v", count, sum)
+	}
+	if count, sum := histogram(metrics.SchedulerScorerScores, "metrics-scheduled", "constant"); count != 4 || sum != 2 {
+		t.Errorf("Unexpected scores of scorer constant, want 4 samples summing to 2, got %d samples summing to %v", count, sum)
+	}
+	if got := testutil.ToFloat64(metrics.SchedulerPicks.WithLabelValues("metrics-scheduled", "default/pod2")); got != 2 {
+		t.Errorf("Unexpected picks of default/pod2, want 2, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.SchedulerRejections.WithLabelValues("metrics-rejected", RejectionNoPodsAvailable)); got != 1 {
+		t.Errorf("Unexpected rejections for no pods available, want 1, got %v", got)
+	}
+}
+
+func TestRunCyclePluginPanic(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+	}
+	tests := []struct {
+		name    string
+		panics  string // the plugin type of the panicking plugin
+		policy  PluginPanicPolicy
+		timeout time.Duration
+		wantErr bool
+	}{
+		{name: "filter skipped", panics: FilterPluginType, policy: PluginPanicSkip},
+		{name: "filter fails the cycle", panics: FilterPluginType, policy: PluginPanicFail, wantErr: true},
+		{name: "scorer skipped", panics: ScorerPluginType, policy: PluginPanicSkip},
+		{name: "scorer skipped within a time budget", panics: ScorerPluginType, policy: PluginPanicSkip, timeout: time.Minute},
+		{name: "scorer fails the cycle within a time budget", panics: ScorerPluginType, policy: PluginPanicFail, timeout: time.Minute, wantErr: true},
+		{name: "picker fails the cycle", panics: PickerPluginType, policy: PluginPanicSkip, wantErr: true},
+		{name: "post-cycle skipped", panics: PostCyclePluginType, policy: PluginPanicSkip},
+		{name: "post-cycle fails the cycle", panics: PostCyclePluginType, policy: PluginPanicFail, wantErr: true},
+		{name: "pre-cycle skipped", panics: PreCyclePluginType, policy: PluginPanicSkip},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin := &testPanickingPlugin{
+				testPlugin: testPlugin{NameRes: "buggy", PickRes: k8stypes.NamespacedName{Name: "pod2"}},
+				panics:     test.panics,
+			}
+			profile := NewSchedulerProfile().
+				WithPreCyclePlugins(plugin).
+				WithFilters(plugin).
+				WithScorers(NewWeightedScorer(plugin, 1)).
+				WithPicker(plugin).
+				WithPostCyclePlugins(plugin).
+				WithTimeout(test.timeout).
+				WithPluginPanicPolicy(test.policy)
+
+			result, err := profile.RunCycle(types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, types.ToSchedulerPodMetrics(pods)))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got result %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := result.TargetPod.GetPod().NamespacedName.Name; got != "pod2" {
+				t.Errorf("Got target pod %s, expected pod2", got)
+			}
+		})
+	}
+}
+
+func TestRunCycleScoreFloor(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}},
+	}
+	scorer := &testScorer{scores: map[string]float64{"pod1": 0.9, "pod2": 0.6, "pod3": 0.2}}
+
+	tests := []struct {
+		name           string
+		floor          float64
+		policy         ScoreFloorPolicy
+		wantCandidates int
+		wantErr        bool
+	}{
+		{
+			name:           "no floor",
+			wantCandidates: 3,
+		},
+		{
+			name:           "pods below the floor are dropped",
+			floor:          0.5,
+			wantCandidates: 2,
+		},
+		{
+			name:           "no pod clears the floor, pick the best",
+			floor:          0.95,
+			wantCandidates: 1,
+		},
+		{
+			name:           "no pod clears the floor, ignore the floor",
+			floor:          0.95,
+			policy:         ScoreFloorIgnore,
+			wantCandidates: 3,
+		},
+		{
+			name:    "no pod clears the floor, reject",
+			floor:   0.95,
+			policy:  ScoreFloorReject,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			picker := &testPlugin{NameRes: "picker", PickRes: k8stypes.NamespacedName{Name: "pod1"}}
+			// The weight doesn't change the result, the floor applies to the average score.
+			profile := NewSchedulerProfile().
+				WithScorers(NewWeightedScorer(scorer, 3)).
+				WithPicker(picker).
+				WithScoreFloor(test.floor, test.policy)
+			ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, types.ToSchedulerPodMetrics(pods))
+			_, err := profile.RunCycle(ctx)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error, got %v, want error %t", err, test.wantErr)
+			}
+			if err == nil && picker.NumOfPickerCandidates != test.wantCandidates {
+				t.Errorf("Picker called with %d candidates, expected %d", picker.NumOfPickerCandidates, test.wantCandidates)
+			}
+		})
+	}
+}
+
+// testSlowPlugin is a filter and scorer that don't return before the scheduling context is done.
+type testSlowPlugin struct{}
+
+func (tp *testSlowPlugin) Name() string { return "slow" }
+
+func (tp *testSlowPlugin) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	<-ctx.Done()
+	return nil
+}
+
+func (tp *testSlowPlugin) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	<-ctx.Done()
+	scoredPods := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scoredPods[pod] = 100
+	}
+	return scoredPods
+}
+
+// testStateWriter is a scorer writing its key to the CycleState, once the scheduling context is
+// done if it's slow.
+type testStateWriter struct {
+	key   types.StateKey
+	slow  bool
+	wrote chan struct{}
+}
+
+func (tw *testStateWriter) Name() string { return string(tw.key) }
+
+func (tw *testStateWriter) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	if tw.slow {
+		<-ctx.Done()
+	}
+	ctx.CycleState.Write(tw.key, testPromptState(tw.key))
+	close(tw.wrote)
+	return nil
+}
+
+// testScorer is a scorer scoring the pods by name.
+type testScorer struct {
+	scores map[string]float64
+}
+
+func (ts *testScorer) Name() string { return "test-scorer" }
+
+func (ts *testScorer) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	scoredPods := make(map[types.Pod]float64, len(pods))
+	for _, pod := range pods {
+		scoredPods[pod] = ts.scores[pod.GetPod().NamespacedName.Name]
+	}
+	return scoredPods
+}
+
+// testPreCyclePlugin is a PreCycle plugin storing the prompt of the request in the CycleState, and a
+// filter keeping the pod named by the stored prompt.
+type testPreCyclePlugin struct {
+	preCycleCallCount int
+}
+
+type testPromptState string
+
+func (s testPromptState) Clone() types.StateData { return s }
+
+func (tp *testPreCyclePlugin) Name() string { return "pre-cycle" }
+
+func (tp *testPreCyclePlugin) PreCycle(ctx *types.SchedulingContext) {
+	tp.preCycleCallCount++
+	ctx.CycleState.Write("prompt", testPromptState(ctx.Req.Prompt))
+}
+
+func (tp *testPreCyclePlugin) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	prompt, err := ctx.CycleState.Read("prompt")
+	if err != nil {
+		return nil
+	}
+	return findPods(ctx, k8stypes.NamespacedName{Name: string(prompt.(testPromptState))})
+}
+
+// testCacheablePlugin is a testPlugin whose filter result depends on the criticality of the request.
+type testCacheablePlugin struct {
+	testPlugin
+}
+
+func (tp *testCacheablePlugin) FilterCacheKey(req *types.LLMRequest) string {
+	return fmt.Sprint(req.Critical)
+}
+
 // compile-time type assertion
 var _ Filter = &testPlugin{}
+var _ CacheableFilter = &testCacheablePlugin{}
+var _ PreCycle = &testPreCyclePlugin{}
 var _ Scorer = &testPlugin{}
 var _ Picker = &testPlugin{}
 var _ PostCycle = &testPlugin{}
 
+// testPanickingPlugin is a testPlugin panicking when run as the plugin of the given type, and
+// keeping all the pods otherwise.
+type testPanickingPlugin struct {
+	testPlugin
+	panics string
+}
+
+func (tp *testPanickingPlugin) PreCycle(ctx *types.SchedulingContext) {
+	if tp.panics == PreCyclePluginType {
+		panic("pre-cycle")
+	}
+}
+
+func (tp *testPanickingPlugin) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	if tp.panics == FilterPluginType {
+		panic("filter")
+	}
+	return pods
+}
+
+func (tp *testPanickingPlugin) Score(ctx *types.SchedulingContext, pods []types.Pod) map[types.Pod]float64 {
+	if tp.panics == ScorerPluginType {
+		var scores map[types.Pod]float64
+		scores[pods[0]] = 1 // assignment to a nil map
+	}
+	return tp.testPlugin.Score(ctx, pods)
+}
+
+func (tp *testPanickingPlugin) Pick(ctx *types.SchedulingContext, scoredPods []*types.ScoredPod) *types.Result {
+	if tp.panics == PickerPluginType {
+		panic("picker")
+	}
+	return tp.testPlugin.Pick(ctx, scoredPods)
+}
+
+func (tp *testPanickingPlugin) PostCycle(ctx *types.SchedulingContext, res *types.Result) {
+	if tp.panics == PostCyclePluginType {
+		panic("post-cycle")
+	}
+}
+
 // testPlugin is an implementation useful in unit tests.
 type testPlugin struct {
 	NameRes               string
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	requtil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/request"
+)
+
+// requestScopedHeaders are the headers that differ between otherwise identical requests, so they
+// are left out of the request fingerprints.
+var requestScopedHeaders = map[string]bool{
+	requtil.RequestIdHeaderKey: true,
+	"content-length":           true,
+	"traceparent":              true,
+	"tracestate":               true,
+}
+
+// scoreCache holds the weighted scores of the candidate pods computed by the recent cycles of a
+// profile, keyed by the fingerprint of the request, so that the identical requests of a burst
+// reuse the ranking of the pods instead of running the filters and scorers again.
+type scoreCache struct {
+	ttl                time.Duration
+	promptPrefixLength int // zero if the whole prompt is part of the fingerprint
+
+	mu        sync.Mutex
+	entries   map[string]*scoreCacheEntry // key: request fingerprint
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type scoreCacheEntry struct {
+	at     time.Time
+	scores map[k8stypes.NamespacedName]float64
+}
+
+func newScoreCache(ttl time.Duration, promptPrefixLength int) *scoreCache {
+	return &scoreCache{
+		ttl:                ttl,
+		promptPrefixLength: promptPrefixLength,
+		entries:            map[string]*scoreCacheEntry{},
+		lastSweep:          time.Now(),
+		now:                time.Now,
+	}
+}
+
+// setClock makes the cache read the time from the given clock.
+func (c *scoreCache) setClock(clk clock.PassiveClock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = clk.Now
+	c.lastSweep = c.now()
+}
+
+// get returns the weighted scores cached for the given fingerprint, mapped to the given pods, and
+// whether fresh scores were found for at least one of the pods.
+func (c *scoreCache) get(fingerprint string, pods []types.Pod) (map[types.Pod]float64, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[fingerprint]
+	ok = ok && c.now().Sub(entry.at) <= c.ttl
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	weightedScorePerPod := map[types.Pod]float64{}
+	for _, pod := range pods {
+		if score, ok := entry.scores[pod.GetPod().NamespacedName]; ok {
+			weightedScorePerPod[pod] = score
+		}
+	}
+	return weightedScorePerPod, len(weightedScorePerPod) > 0
+}
+
+// put caches the weighted scores of a cycle for the given fingerprint, and drops the expired
+// entries once per TTL.
+func (c *scoreCache) put(fingerprint string, weightedScorePerPod map[types.Pod]float64) {
+	scores := make(map[k8stypes.NamespacedName]float64, len(weightedScorePerPod))
+	for pod, score := range weightedScorePerPod {
+		scores[pod.GetPod().NamespacedName] = score
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.entries[fingerprint] = &scoreCacheEntry{at: now, scores: scores}
+	if now.Sub(c.lastSweep) > c.ttl {
+		for key, entry := range c.entries {
+			if now.Sub(entry.at) > c.ttl {
+				delete(c.entries, key)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// fingerprint returns the fingerprint of a request: its target model, criticality, session, prompt
+// prefix and headers, except the request scoped ones.
+func (c *scoreCache) fingerprint(req *types.LLMRequest) string {
+	prompt := req.Prompt
+	if c.promptPrefixLength > 0 && len(prompt) > c.promptPrefixLength {
+		prompt = prompt[:c.promptPrefixLength]
+	}
+
+	hash := sha256.New()
+	write := func(s string) {
+		// length prefixed, so the boundaries between the fields are unambiguous
+		_ = binary.Write(hash, binary.LittleEndian, uint64(len(s)))
+		hash.Write([]byte(s))
+	}
+	write(req.TargetModel)
+	write(strconv.FormatBool(req.Critical))
+	write(strconv.FormatBool(req.SLOBurningFast))
+	write(req.SessionID)
+	write(prompt)
+	keys := make([]string, 0, len(req.Headers))
+	for key := range req.Headers {
+		if lower := strings.ToLower(key); !requestScopedHeaders[lower] && !strings.HasPrefix(lower, "x-envoy-") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		write(key)
+		write(req.Headers[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -16,21 +16,157 @@ limitations under the License.
 
 package framework
 
+import (
+	"math"
+	"sync/atomic"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
 // NewWeightedScorer initializes a new WeightedScorer and returns its pointer.
 func NewWeightedScorer(scorer Scorer, weight int) *WeightedScorer {
-	return &WeightedScorer{
-		Scorer: scorer,
-		weight: weight,
-	}
+	weightedScorer := &WeightedScorer{Scorer: scorer}
+	weightedScorer.SetWeight(weight)
+	return weightedScorer
 }
 
 // WeightedScorer is a struct that encapsulates a scorer with its weight.
 type WeightedScorer struct {
 	Scorer
-	weight int
+	weight        atomic.Int64
+	normalization ScoreNormalization
+}
+
+// ScoreNormalization is how the scores of a scorer are rescaled across the candidate pods before
+// they are weighted.
+type ScoreNormalization string
+
+const (
+	// NoNormalization uses the scores as returned by the scorer.
+	NoNormalization ScoreNormalization = ""
+	// MinMaxNormalization rescales the scores linearly to [0, 1], the lowest score of the candidates
+	// becoming 0 and the highest 1. The scores are all 0 if they are all equal.
+	MinMaxNormalization ScoreNormalization = "min-max"
+	// ZScoreNormalization rescales the scores to their number of standard deviations from the mean
+	// score of the candidates. The scores are all 0 if they are all equal.
+	ZScoreNormalization ScoreNormalization = "z-score"
+)
+
+// Valid returns whether the normalization is one of the supported ones.
+func (n ScoreNormalization) Valid() bool {
+	switch n {
+	case NoNormalization, MinMaxNormalization, ZScoreNormalization:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithNormalization sets how the scores of the scorer are normalized across the candidate pods
+// before they are weighted, so the scores of different scorers are on the same scale and the
+// weights compose predictably. It must be called before the scorer is used.
+func (s *WeightedScorer) WithNormalization(normalization ScoreNormalization) *WeightedScorer {
+	s.normalization = normalization
+	return s
+}
+
+// Normalization returns how the scores of the scorer are normalized.
+func (s *WeightedScorer) Normalization() ScoreNormalization {
+	return s.normalization
 }
 
 // Weight returns the weight of the scorer.
 func (s *WeightedScorer) Weight() int {
-	return s.weight
+	return int(s.weight.Load())
+}
+
+// SetWeight updates the weight of the scorer. It's safe to call concurrently with scheduling cycles.
+func (s *WeightedScorer) SetWeight(weight int) {
+	s.weight.Store(int64(weight))
+	metrics.RecordSchedulerScorerWeight(s.Name(), weight)
+}
+
+// ScorerScores holds the raw (unweighted) scores a scorer gave to the pods in a scheduling cycle.
+type ScorerScores map[types.Pod]float64
+
+// Clone implements types.StateData.
+func (s ScorerScores) Clone() types.StateData {
+	clone := make(ScorerScores, len(s))
+	for pod, score := range s {
+		clone[pod] = score
+	}
+	return clone
+}
+
+// ScorerScoresStateKey returns the CycleState key the ScorerScores of the given scorer are stored
+// under, so later plugins (e.g. PostCycle plugins) can inspect the decision of each scorer.
+func ScorerScoresStateKey(scorerName string) types.TypedStateKey[ScorerScores] {
+	return types.TypedStateKey[ScorerScores]("scorer-scores/" + scorerName)
+}
+
+// CaptureScores returns the raw scores the given scorers gave to the pods in the scheduling cycle
+// of the given CycleState, keyed by scorer and pod name, and the weights of these scorers, for the
+// PostCycle plugins reporting the decisions of the cycles. The scorers that didn't run in the
+// cycle are left out.
+func CaptureScores(state *types.CycleState, scorers []*WeightedScorer) (map[string]map[string]float64, map[string]int) {
+	scores := map[string]map[string]float64{}
+	weights := map[string]int{}
+	for _, scorer := range scorers {
+		scorerScores, err := ScorerScoresStateKey(scorer.Name()).Read(state)
+		if err != nil {
+			continue // the scorer didn't run in this cycle
+		}
+		podScores := make(map[string]float64, len(scorerScores))
+		for pod, score := range scorerScores {
+			podScores[pod.GetPod().NamespacedName.String()] = score
+		}
+		scores[scorer.Name()] = podScores
+		weights[scorer.Name()] = scorer.Weight()
+	}
+	return scores, weights
+}
+
+// normalizeScores returns the given scores normalized across the pods with the given normalization.
+func normalizeScores(scores map[types.Pod]float64, normalization ScoreNormalization) map[types.Pod]float64 {
+	if normalization == NoNormalization || len(scores) == 0 {
+		return scores
+	}
+
+	normalized := make(map[types.Pod]float64, len(scores))
+	switch normalization {
+	case MinMaxNormalization:
+		minScore, maxScore := math.Inf(1), math.Inf(-1)
+		for _, score := range scores {
+			minScore, maxScore = min(minScore, score), max(maxScore, score)
+		}
+		for pod, score := range scores {
+			if maxScore > minScore {
+				normalized[pod] = (score - minScore) / (maxScore - minScore)
+			} else {
+				normalized[pod] = 0
+			}
+		}
+	case ZScoreNormalization:
+		mean := 0.0
+		for _, score := range scores {
+			mean += score
+		}
+		mean /= float64(len(scores))
+		variance := 0.0
+		for _, score := range scores {
+			variance += (score - mean) * (score - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(scores)))
+		for pod, score := range scores {
+			if stddev > 0 {
+				normalized[pod] = (score - mean) / stddev
+			} else {
+				normalized[pod] = 0
+			}
+		}
+	default:
+		return scores
+	}
+	return normalized
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestNormalizeScores(t *testing.T) {
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}}
+	pod2 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}}
+	pod3 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod3"}}}
+
+	tests := []struct {
+		name          string
+		normalization ScoreNormalization
+		scores        map[types.Pod]float64
+		want          map[types.Pod]float64
+	}{
+		{
+			name:          "no normalization",
+			normalization: NoNormalization,
+			scores:        map[types.Pod]float64{pod1: 10, pod2: 20, pod3: 40},
+			want:          map[types.Pod]float64{pod1: 10, pod2: 20, pod3: 40},
+		},
+		{
+			name:          "min-max",
+			normalization: MinMaxNormalization,
+			scores:        map[types.Pod]float64{pod1: 10, pod2: 20, pod3: 40},
+			want:          map[types.Pod]float64{pod1: 0, pod2: 1.0 / 3, pod3: 1},
+		},
+		{
+			name:          "min-max of equal scores",
+			normalization: MinMaxNormalization,
+			scores:        map[types.Pod]float64{pod1: 5, pod2: 5},
+			want:          map[types.Pod]float64{pod1: 0, pod2: 0},
+		},
+		{
+			name:          "z-score",
+			normalization: ZScoreNormalization,
+			scores:        map[types.Pod]float64{pod1: 10, pod2: 20, pod3: 30},
+			want:          map[types.Pod]float64{pod1: -math.Sqrt(1.5), pod2: 0, pod3: math.Sqrt(1.5)},
+		},
+		{
+			name:          "z-score of equal scores",
+			normalization: ZScoreNormalization,
+			scores:        map[types.Pod]float64{pod1: 5, pod2: 5},
+			want:          map[types.Pod]float64{pod1: 0, pod2: 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := normalizeScores(test.scores, test.normalization)
+			if diff := cmp.Diff(test.want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("Unexpected normalized scores (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestCaptureScores(t *testing.T) {
+	pod1 := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}}
+	ran := NewWeightedScorer(&testScorer{}, 2)
+	skipped := NewWeightedScorer(&testPlugin{NameRes: "skipped"}, 1)
+	state := types.NewCycleState()
+	ScorerScoresStateKey(ran.Name()).Write(state, ScorerScores{pod1: 0.5})
+
+	scores, weights := CaptureScores(state, []*WeightedScorer{ran, skipped})
+	if diff := cmp.Diff(map[string]map[string]float64{ran.Name(): {"default/pod1": 0.5}}, scores); diff != "" {
+		t.Errorf("Unexpected scores (-want +got): %v", diff)
+	}
+	if diff := cmp.Diff(map[string]int{ran.Name(): 2}, weights); diff != "" {
+		t.Errorf("Unexpected weights (-want +got): %v", diff)
+	}
+}
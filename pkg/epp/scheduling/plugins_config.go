@@ -0,0 +1,294 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/yaml"
+)
+
+// PluginsConfig is the declarative configuration of the scheduler profiles, e.g.
+//
+//	profilePicker: all-profiles
+//	profiles:
+//	- name: default
+//	  plugins:
+//	  - name: sheddable-capacity
+//	  - name: queue
+//	    weight: 1
+//	  - name: kv-cache
+//	    weight: 1
+//	  - name: kv-fragmentation
+//	    weight: 1
+//	    parameters:
+//	      blockSize: 32
+//	  - name: max_score
+//	classifiers:
+//	- workload-type
+//	- criticality
+//
+// The plugins of a profile are registered in the given order, under every plugin interface they
+// implement (see SchedulerProfile.AddPlugins), so filters run in the order they are listed.
+type PluginsConfig struct {
+	// ProfilePicker is the name of the profile picker. Defaults to "all-profiles".
+	ProfilePicker string          `json:"profilePicker,omitempty"`
+	Profiles      []ProfileConfig `json:"profiles"`
+	// Classifiers are the names of the classifiers labeling the requests, run in the given order
+	// before the profile picker (see SchedulerConfig.WithClassifiers).
+	Classifiers []string `json:"classifiers,omitempty"`
+	// SLOBurningProfile is the name of the profile of the requests whose SLO error budget burns
+	// fast (see SchedulerConfig.WithSLOBurningProfile).
+	SLOBurningProfile string `json:"sloBurningProfile,omitempty"`
+}
+
+// ProfileConfig is the declarative configuration of a scheduler profile.
+type ProfileConfig struct {
+	Name    string         `json:"name"`
+	Plugins []PluginConfig `json:"plugins"`
+	// CandidateSampleSize, if positive, bounds the number of pods scored per request (see
+	// SchedulerProfile.WithCandidateSampling).
+	CandidateSampleSize int `json:"candidateSampleSize,omitempty"`
+	// ScoreFloor, if not zero, is the minimum acceptable score of the pods, and ScoreFloorPolicy is
+	// what happens when no pod clears it: "pick-best" (the default), "ignore" or "reject" (see
+	// SchedulerProfile.WithScoreFloor).
+	ScoreFloor       float64                    `json:"scoreFloor,omitempty"`
+	ScoreFloorPolicy framework.ScoreFloorPolicy `json:"scoreFloorPolicy,omitempty"`
+	// FailurePolicy is what happens when a cycle of the profile fails: "required" (the default)
+	// fails the request, "optional" goes on without the profile. FallbackProfile, if set, is the
+	// name of a profile run instead of the failed one first (see SchedulerProfile.WithFailurePolicy).
+	FailurePolicy   framework.ProfileFailurePolicy `json:"failurePolicy,omitempty"`
+	FallbackProfile string                         `json:"fallbackProfile,omitempty"`
+	// ScoreCacheTTL, if positive, is how long the scores of the pods are reused for the identical
+	// requests, and ScoreCachePromptPrefixLength the length of the prompt prefix they share, the
+	// whole prompt if zero (see SchedulerProfile.WithScoreCache).
+	ScoreCacheTTL                metav1.Duration `json:"scoreCacheTTL,omitempty"`
+	ScoreCachePromptPrefixLength int             `json:"scoreCachePromptPrefixLength,omitempty"`
+	// PluginPanicPolicy is what happens when a plugin of the profile panics: "skip" (the default)
+	// goes on without the plugin, "fail" fails the cycle (see SchedulerProfile.WithPluginPanicPolicy).
+	PluginPanicPolicy framework.PluginPanicPolicy `json:"pluginPanicPolicy,omitempty"`
+}
+
+// PluginConfig references a plugin registered with framework.Register by name.
+type PluginConfig struct {
+	Name string `json:"name"`
+	// Parameters, if set, are passed as raw JSON to the factory of the plugin, over the default
+	// parameters given by the framework.Handle, if any.
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+	// Weight is the weight of the plugin if it's a scorer. It is required for scorers, and must not
+	// be set for other plugins.
+	Weight *int `json:"weight,omitempty"`
+	// Normalization is how the scores of the plugin are normalized across the candidate pods before
+	// they are weighted if it's a scorer: "min-max", "z-score", or empty for no normalization. It
+	// must not be set for other plugins.
+	Normalization framework.ScoreNormalization `json:"normalization,omitempty"`
+}
+
+// LoadPluginsConfig parses the given YAML or JSON PluginsConfig. Unknown fields are rejected.
+func LoadPluginsConfig(data []byte) (*PluginsConfig, error) {
+	config := &PluginsConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse scheduler plugins config: %w", err)
+	}
+	return config, nil
+}
+
+// NewSchedulerConfigFromPlugins instantiates the plugins referenced by the given PluginsConfig with
+// the factories registered with framework.Register, and returns the resulting SchedulerConfig. The
+// factories are given the handle, framework.NoHandle if nil. All the problems of the config are
+// reported at once, e.g. unknown plugin names or profiles without a picker.
+func NewSchedulerConfigFromPlugins(config *PluginsConfig, handle framework.Handle) (*SchedulerConfig, error) {
+	if handle == nil {
+		handle = framework.NoHandle
+	}
+
+	var errs []error
+
+	profilePickerName := config.ProfilePicker
+	if profilePickerName == "" {
+		profilePickerName = "all-profiles"
+	}
+	var profilePicker framework.ProfilePicker
+	if plugin, err := instantiate(profilePickerName, nil, handle); err != nil {
+		errs = append(errs, fmt.Errorf("profile picker: %w", err))
+	} else if pp, ok := plugin.(framework.ProfilePicker); !ok {
+		errs = append(errs, fmt.Errorf("profile picker: plugin '%s' is not a profile picker", profilePickerName))
+	} else {
+		profilePicker = pp
+	}
+
+	if len(config.Profiles) == 0 {
+		errs = append(errs, errors.New("at least one profile is required"))
+	}
+	profiles := make(map[string]*framework.SchedulerProfile, len(config.Profiles))
+	seen := make(map[string]bool, len(config.Profiles))
+	for i, profileConfig := range config.Profiles {
+		if profileConfig.Name == "" {
+			errs = append(errs, fmt.Errorf("profile #%d: name is required", i))
+			continue
+		}
+		if seen[profileConfig.Name] {
+			errs = append(errs, fmt.Errorf("profile '%s': duplicate profile name", profileConfig.Name))
+			continue
+		}
+		seen[profileConfig.Name] = true
+		profile, err := newProfile(profileConfig, handle)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("profile '%s': %w", profileConfig.Name, err))
+			continue
+		}
+		profiles[profileConfig.Name] = profile
+	}
+	for _, profileConfig := range config.Profiles {
+		switch fallback := profileConfig.FallbackProfile; {
+		case fallback == "":
+		case fallback == profileConfig.Name:
+			errs = append(errs, fmt.Errorf("profile '%s': a profile can't be its own fallback", profileConfig.Name))
+		case !seen[fallback]:
+			errs = append(errs, fmt.Errorf("profile '%s': unknown fallback profile '%s'", profileConfig.Name, fallback))
+		}
+	}
+
+	if config.SLOBurningProfile != "" && !seen[config.SLOBurningProfile] {
+		errs = append(errs, fmt.Errorf("unknown SLO burning profile '%s'", config.SLOBurningProfile))
+	}
+
+	classifiers := make([]framework.Classifier, 0, len(config.Classifiers))
+	for _, name := range config.Classifiers {
+		if plugin, err := instantiate(name, nil, handle); err != nil {
+			errs = append(errs, fmt.Errorf("classifier: %w", err))
+		} else if c, ok := plugin.(framework.Classifier); !ok {
+			errs = append(errs, fmt.Errorf("classifier: plugin '%s' is not a classifier", name))
+		} else {
+			classifiers = append(classifiers, c)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return NewSchedulerConfig(profilePicker, profiles).WithClassifiers(classifiers...).WithSLOBurningProfile(config.SLOBurningProfile), nil
+}
+
+func newProfile(config ProfileConfig, handle framework.Handle) (*framework.SchedulerProfile, error) {
+	var errs []error
+	profile := framework.NewSchedulerProfile()
+	hasPicker := false
+	for _, pluginConfig := range config.Plugins {
+		plugin, err := instantiate(pluginConfig.Name, pluginConfig.Parameters, handle)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, isScorer := plugin.(framework.Scorer)
+		_, isPicker := plugin.(framework.Picker)
+		switch {
+		case isScorer && pluginConfig.Weight == nil:
+			errs = append(errs, fmt.Errorf("scorer '%s' requires a weight", pluginConfig.Name))
+			continue
+		case isScorer && *pluginConfig.Weight < 0:
+			errs = append(errs, fmt.Errorf("scorer '%s' has a negative weight %d", pluginConfig.Name, *pluginConfig.Weight))
+			continue
+		case !isScorer && pluginConfig.Weight != nil:
+			errs = append(errs, fmt.Errorf("plugin '%s' is not a scorer and can't have a weight", pluginConfig.Name))
+			continue
+		case !pluginConfig.Normalization.Valid():
+			errs = append(errs, fmt.Errorf("plugin '%s' has an unknown normalization '%s'", pluginConfig.Name, pluginConfig.Normalization))
+			continue
+		case !isScorer && pluginConfig.Normalization != framework.NoNormalization:
+			errs = append(errs, fmt.Errorf("plugin '%s' is not a scorer and can't have a normalization", pluginConfig.Name))
+			continue
+		case isScorer:
+			plugin = framework.NewWeightedScorer(plugin.(framework.Scorer), *pluginConfig.Weight).WithNormalization(pluginConfig.Normalization)
+		}
+		if err := profile.AddPlugins(plugin); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		hasPicker = hasPicker || isPicker
+	}
+	if !hasPicker && len(errs) == 0 {
+		errs = append(errs, errors.New("a picker is required"))
+	}
+	if config.CandidateSampleSize < 0 {
+		errs = append(errs, fmt.Errorf("negative candidate sample size %d", config.CandidateSampleSize))
+	}
+	if config.ScoreFloorPolicy != "" && !config.ScoreFloorPolicy.Valid() {
+		errs = append(errs, fmt.Errorf("unknown score floor policy '%s'", config.ScoreFloorPolicy))
+	}
+	if config.FailurePolicy != "" && !config.FailurePolicy.Valid() {
+		errs = append(errs, fmt.Errorf("unknown failure policy '%s'", config.FailurePolicy))
+	}
+	if config.PluginPanicPolicy != "" && !config.PluginPanicPolicy.Valid() {
+		errs = append(errs, fmt.Errorf("unknown plugin panic policy '%s'", config.PluginPanicPolicy))
+	}
+	if config.ScoreCacheTTL.Duration < 0 {
+		errs = append(errs, fmt.Errorf("negative score cache TTL %s", config.ScoreCacheTTL.Duration))
+	}
+	if config.ScoreCachePromptPrefixLength < 0 {
+		errs = append(errs, fmt.Errorf("negative score cache prompt prefix length %d", config.ScoreCachePromptPrefixLength))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return profile.WithCandidateSampling(config.CandidateSampleSize).
+		WithScoreFloor(config.ScoreFloor, config.ScoreFloorPolicy).
+		WithFailurePolicy(config.FailurePolicy, config.FallbackProfile).
+		WithScoreCache(config.ScoreCacheTTL.Duration, config.ScoreCachePromptPrefixLength).
+		WithPluginPanicPolicy(config.PluginPanicPolicy), nil
+}
+
+func instantiate(name string, parameters json.RawMessage, handle framework.Handle) (framework.Plugin, error) {
+	factory, ok := framework.LookupFactory(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin '%s'", name)
+	}
+	parameters, err := mergeParameters(handle.DefaultParameters(name), parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plugin '%s': %w", name, err)
+	}
+	plugin, err := factory(parameters, handle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plugin '%s': %w", name, err)
+	}
+	return plugin, nil
+}
+
+// mergeParameters returns the given JSON object parameters over the defaults, field by field.
+func mergeParameters(defaults, parameters json.RawMessage) (json.RawMessage, error) {
+	if len(defaults) == 0 {
+		return parameters, nil
+	}
+	if len(parameters) == 0 {
+		return defaults, nil
+	}
+	merged := map[string]json.RawMessage{}
+	if err := json.Unmarshal(defaults, &merged); err != nil {
+		return nil, fmt.Errorf("invalid default parameters: %w", err)
+	}
+	overrides := map[string]json.RawMessage{}
+	if err := json.Unmarshal(parameters, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	maps.Copy(merged, overrides)
+	return json.Marshal(merged)
+}
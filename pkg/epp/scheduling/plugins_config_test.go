@@ -0,0 +1,298 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/classifier"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/filter"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestPluginsConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		wantScorers map[string][]string // profile -> scorer names
+		wantLabels  map[string]string
+		wantErrs    []string
+	}{
+		{
+			name: "valid config",
+			config: `
+profiles:
+- name: prefill
+  plugins:
+  - name: sheddable-capacity
+  - name: queue
+    weight: 2
+  - name: prefix-cache
+    weight: 3
+    normalization: min-max
+    parameters:
+      hashBlockSize: 128
+      lruIndexerCapacity: 1000
+  - name: max_score
+- name: decode
+  candidateSampleSize: 64
+  scoreFloor: 0.2
+  scoreFloorPolicy: reject
+  failurePolicy: optional
+  fallbackProfile: prefill
+  scoreCacheTTL: 50ms
+  scoreCachePromptPrefixLength: 256
+  plugins:
+  - name: kv-cache
+    weight: 1
+  - name: random
+classifiers:
+- workload-type
+- criticality
+`,
+			wantScorers: map[string][]string{
+				"prefill": {"queue", "prefix-cache"},
+				"decode":  {"kv-cache"},
+			},
+			wantLabels: map[string]string{
+				types.LabelWorkloadType: "other",
+				types.LabelCriticality:  "non-critical",
+			},
+		},
+		{
+			name:     "unknown field",
+			config:   `profiles: [{name: default, plugins: [{name: random, params: {}}]}]`,
+			wantErrs: []string{`unknown field "params"`},
+		},
+		{
+			name: "invalid profiles",
+			config: `
+profilePicker: random
+profiles:
+- name: unknown
+  plugins:
+  - name: does-not-exist
+  - name: random
+- name: weights
+  plugins:
+  - name: queue
+  - name: sheddable-capacity
+    weight: 1
+  - name: kv-cache
+    weight: -1
+  - name: random
+- name: no-picker
+  plugins:
+  - name: queue
+    weight: 1
+- name: two-pickers
+  plugins:
+  - name: random
+  - name: max_score
+- name: weights
+  plugins:
+  - name: random
+- name: sampling
+  candidateSampleSize: -1
+  plugins:
+  - name: random
+- name: floor
+  scoreFloor: 0.5
+  scoreFloorPolicy: lowest
+  plugins:
+  - name: random
+- name: normalizations
+  plugins:
+  - name: queue
+    weight: 1
+    normalization: log
+  - name: low-queue
+    normalization: z-score
+  - name: random
+- name: failure
+  failurePolicy: sometimes
+  fallbackProfile: does-not-exist
+  plugins:
+  - name: random
+- name: own-fallback
+  fallbackProfile: own-fallback
+  plugins:
+  - name: random
+- name: cache
+  scoreCacheTTL: -1s
+  scoreCachePromptPrefixLength: -1
+  plugins:
+  - name: random
+- name: parameters
+  plugins:
+  - name: kv-fragmentation
+    weight: 1
+    parameters:
+      blockSize: 0
+  - name: queue
+    weight: 1
+    parameters:
+      window: 1s
+  - name: random
+classifiers:
+- does-not-exist
+- random
+`,
+			wantErrs: []string{
+				"profile picker: plugin 'random' is not a profile picker",
+				"profile 'unknown': unknown plugin 'does-not-exist'",
+				"scorer 'queue' requires a weight",
+				"plugin 'sheddable-capacity' is not a scorer and can't have a weight",
+				"scorer 'kv-cache' has a negative weight -1",
+				"profile 'no-picker': a picker is required",
+				"failed to set 'max_score' as picker",
+				"profile 'weights': duplicate profile name",
+				"profile 'sampling': negative candidate sample size -1",
+				"profile 'floor': unknown score floor policy 'lowest'",
+				"plugin 'queue' has an unknown normalization 'log'",
+				"plugin 'low-queue' is not a scorer and can't have a normalization",
+				"profile 'failure': unknown failure policy 'sometimes'",
+				"profile 'failure': unknown fallback profile 'does-not-exist'",
+				"profile 'own-fallback': a profile can't be its own fallback",
+				"profile 'cache': negative score cache TTL -1s",
+				"negative score cache prompt prefix length -1",
+				"failed to create plugin 'kv-fragmentation': non-positive block size 0",
+				`failed to create plugin 'queue': invalid parameters: json: unknown field "window"`,
+				"classifier: unknown plugin 'does-not-exist'",
+				"classifier: plugin 'random' is not a classifier",
+			},
+		},
+		{
+			name:     "no profiles",
+			config:   `profilePicker: all-profiles`,
+			wantErrs: []string{"at least one profile is required"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := LoadPluginsConfig([]byte(test.config))
+			var schedulerConfig *SchedulerConfig
+			if err == nil {
+				schedulerConfig, err = NewSchedulerConfigFromPlugins(config, nil)
+			}
+			if len(test.wantErrs) > 0 {
+				assert.Error(t, err)
+				for _, wantErr := range test.wantErrs {
+					assert.ErrorContains(t, err, wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+
+			gotScorers := map[string][]string{}
+			for name, profile := range schedulerConfig.profiles {
+				gotScorers[name] = []string{}
+				for _, scorer := range profile.Scorers() {
+					gotScorers[name] = append(gotScorers[name], scorer.Name())
+				}
+			}
+			assert.Equal(t, test.wantScorers, gotScorers)
+
+			// The configured scheduler is functional.
+			pods := []*backendmetrics.FakePodMetrics{
+				{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, Metrics: &backendmetrics.MetricsState{}},
+			}
+			scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+			req := &types.LLMRequest{TargetModel: "model", RequestId: "req"}
+			results, err := scheduler.Schedule(context.Background(), req)
+			assert.NoError(t, err)
+			assert.Len(t, results, len(test.wantScorers))
+			assert.Equal(t, test.wantLabels, req.Labels)
+		})
+	}
+}
+
+type testHandle struct {
+	defaults    map[string]json.RawMessage
+	annotations map[string]string
+}
+
+func (h *testHandle) DefaultParameters(name string) json.RawMessage {
+	return h.defaults[name]
+}
+
+func (h *testHandle) PoolAnnotations() map[string]string {
+	return h.annotations
+}
+
+func TestPluginParameters(t *testing.T) {
+	handle := &testHandle{defaults: map[string]json.RawMessage{
+		"tenant":         json.RawMessage(`{"header": "x-team"}`),
+		"size-class":     json.RawMessage(`{"mediumSizeTokens": 100, "largeSizeTokens": 1000}`),
+		"does-not-exist": json.RawMessage(`{}`),
+	}}
+
+	plugin, err := instantiate("tenant", nil, handle)
+	assert.NoError(t, err)
+	assert.Equal(t, classifier.NewTenantClassifier("x-team"), plugin)
+
+	plugin, err = instantiate("tenant", json.RawMessage(`{"header": "x-org"}`), handle)
+	assert.NoError(t, err)
+	assert.Equal(t, classifier.NewTenantClassifier("x-org"), plugin)
+
+	plugin, err = instantiate("tenant", nil, framework.NoHandle)
+	assert.NoError(t, err)
+	assert.Equal(t, classifier.NewTenantClassifier(classifier.DefaultTenantHeader), plugin)
+
+	// The parameters override the defaults field by field.
+	plugin, err = instantiate("size-class", json.RawMessage(`{"largeSizeTokens": 500}`), handle)
+	assert.NoError(t, err)
+	assert.Equal(t, classifier.NewSizeClassClassifier(100, 500), plugin)
+
+	_, err = instantiate("low-queue", json.RawMessage(`{"threshold": 1}`), handle)
+	assert.ErrorContains(t, err, `failed to create plugin 'low-queue': invalid parameters: json: unknown field "threshold"`)
+
+	_, err = instantiate("tenant", json.RawMessage(`[]`), handle)
+	assert.ErrorContains(t, err, "failed to create plugin 'tenant': invalid parameters")
+
+	_, err = instantiate("does-not-exist", json.RawMessage(`{}`), handle)
+	assert.ErrorContains(t, err, "unknown plugin 'does-not-exist'")
+}
+
+func TestBlueGreenFilterPlugin(t *testing.T) {
+	handle := &testHandle{}
+	plugin, err := instantiate("blue-green", json.RawMessage(`{"defaultGreenWeight": 100}`), handle)
+	assert.NoError(t, err)
+
+	blue := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "blue"}, Labels: map[string]string{"stack": "blue"}}}
+	green := &types.PodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "green"}, Labels: map[string]string{"stack": "green"}}}
+	filterPods := func() []types.Pod {
+		ctx := types.NewSchedulingContext(context.Background(), &types.LLMRequest{}, nil, []types.Pod{blue, green})
+		return plugin.(framework.Filter).Filter(ctx, []types.Pod{blue, green})
+	}
+	assert.Equal(t, []types.Pod{green}, filterPods())
+
+	// The green weight of the InferencePool annotation overrides the default one.
+	handle.annotations = map[string]string{filter.GreenWeightAnnotationKey: "0"}
+	assert.Equal(t, []types.Pod{blue}, filterPods())
+
+	_, err = instantiate("blue-green", json.RawMessage(`{"blueSelector": "stack in (blue"}`), handle)
+	assert.ErrorContains(t, err, "failed to parse blue selector")
+}
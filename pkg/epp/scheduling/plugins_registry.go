@@ -0,0 +1,449 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/classifier"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/extension"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/filter"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/anomaly"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/concurrencylimit"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/prefix"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/retryantiaffinity"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/sessionaffinity"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/multi/ttft"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
+	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/scorer"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/wasm"
+)
+
+// The in-tree plugins that don't need external dependencies register themselves here, so that they
+// can be referenced by name from the scheduler plugins config. The parameters of the configurable
+// ones default to the values of their constructors.
+func init() {
+	// filters
+	framework.Register("sheddable-capacity", framework.NoParameters(filter.NewSheddableCapacityFilter))
+	framework.Register("low-queue", framework.NoParameters(filter.NewLowQueueFilter))
+	framework.Register("least-queue", framework.NoParameters(filter.NewLeastQueueFilter))
+	framework.Register("least-KV-cache", framework.NoParameters(filter.NewLeastKVCacheFilter))
+	framework.Register("lora-affinity", framework.NoParameters(filter.NewLoraAffinityFilter))
+	framework.Register("lora-capacity", framework.NoParameters(filter.NewLoraCapacityFilter))
+	framework.Register("saturation", newSaturationFilter)
+	framework.Register("blue-green", newBlueGreenFilter)
+	framework.Register("metrics-freshness", newMetricsFreshnessFilter)
+	framework.Register("kv-cache-hysteresis", newKVCacheHysteresisFilter)
+	framework.Register("pinned-pod", newPinnedPodFilter)
+	framework.Register("general-role", newRoleFilter(backend.PodRoleGeneral))
+	framework.Register("prefill-role", newRoleFilter(backend.PodRolePrefill))
+	framework.Register("decode-role", newRoleFilter(backend.PodRoleDecode))
+	framework.Register("draft-role", newRoleFilter(backend.PodRoleDraft))
+	// scorers
+	framework.Register("queue", framework.NoParameters(func() *scorer.QueueScorer { return &scorer.QueueScorer{} }))
+	framework.Register("kv-cache", framework.NoParameters(func() *scorer.KVCacheScorer { return &scorer.KVCacheScorer{} }))
+	framework.Register("bin-packing", framework.NoParameters(scorer.NewBinPackingScorer))
+	framework.Register("lora-loading", framework.NoParameters(scorer.NewLoraLoadingScorer))
+	framework.Register("gpu-headroom", framework.NoParameters(scorer.NewGPUHeadroomScorer))
+	framework.Register("requests-in-flight", framework.NoParameters(scorer.NewRequestsInFlightScorer))
+	framework.Register("outstanding-tokens", framework.NoParameters(scorer.NewOutstandingTokensScorer))
+	framework.Register("queue-trend", newQueueTrendScorer)
+	framework.Register("kv-fragmentation", newKVFragmentationScorer)
+	// pickers
+	framework.Register("max_score", framework.NoParameters(picker.NewMaxScorePicker))
+	framework.Register("random", framework.NoParameters(picker.NewRandomPicker))
+	framework.Register("round-robin", framework.NoParameters(picker.NewRoundRobinPicker))
+	framework.Register("power-of-two", framework.NoParameters(picker.NewPowerOfTwoPicker))
+	framework.Register("weighted-round-robin", framework.NoParameters(picker.NewWeightedRoundRobinPicker))
+	framework.Register("bandit", newBanditPicker)
+	// multi-interface plugins
+	framework.Register("prefix-cache", newPrefixCachePlugin)
+	framework.Register("session-affinity", newSessionAffinityPlugin)
+	framework.Register("retry-anti-affinity", newRetryAntiAffinityPlugin)
+	framework.Register("max-concurrency", newConcurrencyLimitPlugin)
+	framework.Register("ttft-estimate", newTTFTEstimatePlugin)
+	framework.Register("response-anomaly", newResponseAnomalyPlugin)
+	// out-of-process plugins
+	framework.Register(extension.ScorerName, newExtensionPlugin(func(c extension.Config) (framework.Plugin, error) { return extension.NewScorer(c) }))
+	framework.Register(extension.FilterName, newExtensionPlugin(func(c extension.Config) (framework.Plugin, error) { return extension.NewFilter(c) }))
+	framework.Register(wasm.ScorerName, newWasmScorer)
+	// classifiers
+	framework.Register("workload-type", framework.NoParameters(classifier.NewWorkloadTypeClassifier))
+	framework.Register("size-class", newSizeClassClassifier)
+	framework.Register("tenant", newTenantClassifier)
+	framework.Register("criticality", framework.NoParameters(classifier.NewCriticalityClassifier))
+	// profile pickers
+	framework.Register("all-profiles", framework.NoParameters(profilepicker.NewAllProfilesPicker))
+	framework.Register("prefill-decode", newPrefillDecodeProfilePicker)
+}
+
+func newMetricsFreshnessFilter(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		StalenessThreshold metav1.Duration `json:"stalenessThreshold"`
+	}{StalenessThreshold: metav1.Duration{Duration: filter.DefaultMetricsStalenessThreshold}}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.StalenessThreshold.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive staleness threshold %s", params.StalenessThreshold.Duration)
+	}
+	return filter.NewMetricsFreshnessFilter(params.StalenessThreshold.Duration), nil
+}
+
+func newSaturationFilter(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	type thresholds struct {
+		QueueThreshold   int     `json:"queueThreshold"`
+		KVCacheThreshold float64 `json:"kvCacheThreshold"`
+	}
+	tiers := filter.DefaultSaturationTiers()
+	params := struct {
+		Critical  thresholds `json:"critical"`
+		Standard  thresholds `json:"standard"`
+		Sheddable thresholds `json:"sheddable"`
+	}{
+		Critical:  thresholds(tiers.Critical),
+		Standard:  thresholds(tiers.Standard),
+		Sheddable: thresholds(tiers.Sheddable),
+	}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	return filter.NewSaturationFilter(filter.SaturationTiers{
+		Critical:  filter.SaturationThresholds(params.Critical),
+		Standard:  filter.SaturationThresholds(params.Standard),
+		Sheddable: filter.SaturationThresholds(params.Sheddable),
+	}), nil
+}
+
+// newBlueGreenFilter creates the blue/green filter. The green weight is read from the annotations
+// of the InferencePool on every request, so the traffic can be shifted at runtime.
+func newBlueGreenFilter(parameters json.RawMessage, handle framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		BlueSelector       string `json:"blueSelector"`
+		GreenSelector      string `json:"greenSelector"`
+		DefaultGreenWeight int    `json:"defaultGreenWeight"`
+	}{BlueSelector: filter.DefaultBlueSelector, GreenSelector: filter.DefaultGreenSelector}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	blueSelector, err := labels.Parse(params.BlueSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse blue selector: %w", err)
+	}
+	greenSelector, err := labels.Parse(params.GreenSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse green selector: %w", err)
+	}
+	return filter.NewBlueGreenFilter(blueSelector, greenSelector, func() int {
+		return filter.GreenWeightFromAnnotations(handle.PoolAnnotations(), params.DefaultGreenWeight)
+	}), nil
+}
+
+func newKVCacheHysteresisFilter(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		HighWatermark float64 `json:"highWatermark"`
+		LowWatermark  float64 `json:"lowWatermark"`
+	}{HighWatermark: filter.DefaultKVCacheHighWatermark, LowWatermark: filter.DefaultKVCacheLowWatermark}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.HighWatermark <= 0 || params.HighWatermark > 1 || params.LowWatermark < 0 || params.LowWatermark > params.HighWatermark {
+		return nil, fmt.Errorf("invalid watermarks: want 0 <= lowWatermark (%v) <= highWatermark (%v) <= 1, highWatermark > 0",
+			params.LowWatermark, params.HighWatermark)
+	}
+	return filter.NewKVCacheHysteresisFilter(params.HighWatermark, params.LowWatermark), nil
+}
+
+func newPinnedPodFilter(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		Strict bool `json:"strict"`
+	}{}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	return filter.NewPinnedPodFilter(params.Strict), nil
+}
+
+func newRoleFilter(role backend.PodRole) framework.FactoryFunc {
+	return framework.NoParameters(func() *filter.RoleFilter { return filter.NewRoleFilter(role) })
+}
+
+func newQueueTrendScorer(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		Window metav1.Duration `json:"window"`
+	}{Window: metav1.Duration{Duration: scorer.DefaultQueueTrendWindow}}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.Window.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive window %s", params.Window.Duration)
+	}
+	return scorer.NewQueueTrendScorer(params.Window.Duration), nil
+}
+
+func newKVFragmentationScorer(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		BlockSize int `json:"blockSize"`
+	}{BlockSize: scorer.DefaultKVCacheBlockSize}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.BlockSize <= 0 {
+		return nil, fmt.Errorf("non-positive block size %d", params.BlockSize)
+	}
+	return scorer.NewKVFragmentationScorer(params.BlockSize), nil
+}
+
+func newBanditPicker(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		ExplorationFactor float64 `json:"explorationFactor"`
+		Discount          float64 `json:"discount"`
+	}{ExplorationFactor: picker.DefaultBanditExplorationFactor, Discount: picker.DefaultBanditDiscount}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.ExplorationFactor <= 0 {
+		return nil, fmt.Errorf("non-positive exploration factor %v", params.ExplorationFactor)
+	}
+	if params.Discount <= 0 || params.Discount > 1 {
+		return nil, fmt.Errorf("discount %v out of range (0, 1]", params.Discount)
+	}
+	return picker.NewBanditPicker(picker.BanditPickerConfig{
+		ExplorationFactor: params.ExplorationFactor,
+		Discount:          params.Discount,
+	}), nil
+}
+
+func newPrefixCachePlugin(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		HashBlockSize          int `json:"hashBlockSize"`
+		MaxPrefixBlocksToMatch int `json:"maxPrefixBlocksToMatch"`
+		LRUIndexerCapacity     int `json:"lruIndexerCapacity"`
+	}{
+		HashBlockSize:          prefix.DefaultHashBlockSize,
+		MaxPrefixBlocksToMatch: prefix.DefaultMaxPrefixBlocks,
+		LRUIndexerCapacity:     prefix.DefaultLRUIndexerCapacity,
+	}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.HashBlockSize <= 0 || params.MaxPrefixBlocksToMatch <= 0 || params.LRUIndexerCapacity <= 0 {
+		return nil, fmt.Errorf("non-positive hashBlockSize (%d), maxPrefixBlocksToMatch (%d) or lruIndexerCapacity (%d)",
+			params.HashBlockSize, params.MaxPrefixBlocksToMatch, params.LRUIndexerCapacity)
+	}
+	return prefix.New(prefix.Config{
+		HashBlockSize:          params.HashBlockSize,
+		MaxPrefixBlocksToMatch: params.MaxPrefixBlocksToMatch,
+		LRUIndexerCapacity:     params.LRUIndexerCapacity,
+	}), nil
+}
+
+func newSessionAffinityPlugin(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		SessionTTL metav1.Duration `json:"sessionTTL"`
+	}{SessionTTL: metav1.Duration{Duration: sessionaffinity.DefaultSessionTTL}}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.SessionTTL.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive session TTL %s", params.SessionTTL.Duration)
+	}
+	return sessionaffinity.New(sessionaffinity.Config{
+		SessionTTL: params.SessionTTL.Duration,
+	}), nil
+}
+
+func newRetryAntiAffinityPlugin(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		AttemptTTL metav1.Duration `json:"attemptTTL"`
+	}{AttemptTTL: metav1.Duration{Duration: retryantiaffinity.DefaultAttemptTTL}}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.AttemptTTL.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive attempt TTL %s", params.AttemptTTL.Duration)
+	}
+	return retryantiaffinity.New(retryantiaffinity.Config{AttemptTTL: params.AttemptTTL.Duration}), nil
+}
+
+func newConcurrencyLimitPlugin(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		DefaultMaxConcurrency int             `json:"defaultMaxConcurrency"`
+		RequestTTL            metav1.Duration `json:"requestTTL"`
+	}{RequestTTL: metav1.Duration{Duration: concurrencylimit.DefaultRequestTTL}}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.DefaultMaxConcurrency < 0 {
+		return nil, fmt.Errorf("negative default max concurrency %d", params.DefaultMaxConcurrency)
+	}
+	if params.RequestTTL.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive request TTL %s", params.RequestTTL.Duration)
+	}
+	return concurrencylimit.New(concurrencylimit.Config{
+		DefaultMaxConcurrency: params.DefaultMaxConcurrency,
+		RequestTTL:            params.RequestTTL.Duration,
+	}), nil
+}
+
+func newResponseAnomalyPlugin(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		Detectors []string        `json:"detectors"`
+		Threshold int             `json:"threshold"`
+		Window    metav1.Duration `json:"window"`
+		Cooldown  metav1.Duration `json:"cooldown"`
+	}{
+		Threshold: anomaly.DefaultThreshold,
+		Window:    metav1.Duration{Duration: anomaly.DefaultWindow},
+		Cooldown:  metav1.Duration{Duration: anomaly.DefaultCooldown},
+	}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.Threshold <= 0 {
+		return nil, fmt.Errorf("non-positive threshold %d", params.Threshold)
+	}
+	if params.Window.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive window %s", params.Window.Duration)
+	}
+	if params.Cooldown.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive cooldown %s", params.Cooldown.Duration)
+	}
+	config := anomaly.Config{Threshold: params.Threshold, Window: params.Window.Duration, Cooldown: params.Cooldown.Duration}
+	for _, name := range params.Detectors {
+		detector, err := anomaly.NewDetector(name)
+		if err != nil {
+			return nil, err
+		}
+		config.Detectors = append(config.Detectors, detector)
+	}
+	return anomaly.New(config), nil
+}
+
+func newTTFTEstimatePlugin(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		ThroughputWindow      metav1.Duration `json:"throughputWindow"`
+		RunningSequenceWeight float64         `json:"runningSequenceWeight"`
+	}{
+		ThroughputWindow:      metav1.Duration{Duration: ttft.DefaultThroughputWindow},
+		RunningSequenceWeight: ttft.DefaultRunningSequenceWeight,
+	}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.ThroughputWindow.Duration <= 0 {
+		return nil, fmt.Errorf("non-positive throughput window %s", params.ThroughputWindow.Duration)
+	}
+	if params.RunningSequenceWeight < 0 {
+		return nil, fmt.Errorf("negative running sequence weight %v", params.RunningSequenceWeight)
+	}
+	return ttft.New(ttft.Config{
+		ThroughputWindow:      params.ThroughputWindow.Duration,
+		RunningSequenceWeight: params.RunningSequenceWeight,
+	}), nil
+}
+
+func newExtensionPlugin(newPlugin func(extension.Config) (framework.Plugin, error)) framework.FactoryFunc {
+	return func(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+		params := struct {
+			Name           string          `json:"name"`
+			Address        string          `json:"address"`
+			Timeout        metav1.Duration `json:"timeout"`
+			FailurePolicy  string          `json:"failurePolicy"`
+			MaxPodsPerCall int             `json:"maxPodsPerCall"`
+			SendPrompt     bool            `json:"sendPrompt"`
+		}{}
+		if err := framework.DecodeParameters(parameters, &params); err != nil {
+			return nil, err
+		}
+		return newPlugin(extension.Config{
+			Name:           params.Name,
+			Address:        params.Address,
+			Timeout:        params.Timeout.Duration,
+			FailurePolicy:  extension.FailurePolicy(params.FailurePolicy),
+			MaxPodsPerCall: params.MaxPodsPerCall,
+			SendPrompt:     params.SendPrompt,
+		})
+	}
+}
+
+func newWasmScorer(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		Name         string          `json:"name"`
+		Module       string          `json:"module"`
+		Timeout      metav1.Duration `json:"timeout"`
+		MaxMemoryMiB int             `json:"maxMemoryMiB"`
+		MaxInstances int             `json:"maxInstances"`
+		SendPrompt   bool            `json:"sendPrompt"`
+	}{}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	return wasm.NewScorer(wasm.Config{
+		Name:         params.Name,
+		Module:       params.Module,
+		Timeout:      params.Timeout.Duration,
+		MaxMemoryMiB: params.MaxMemoryMiB,
+		MaxInstances: params.MaxInstances,
+		SendPrompt:   params.SendPrompt,
+	})
+}
+
+func newSizeClassClassifier(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		MediumSizeTokens int `json:"mediumSizeTokens"`
+		LargeSizeTokens  int `json:"largeSizeTokens"`
+	}{MediumSizeTokens: classifier.DefaultMediumSizeTokens, LargeSizeTokens: classifier.DefaultLargeSizeTokens}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.MediumSizeTokens <= 0 || params.LargeSizeTokens < params.MediumSizeTokens {
+		return nil, fmt.Errorf("invalid sizes: want 0 < mediumSizeTokens (%d) <= largeSizeTokens (%d)",
+			params.MediumSizeTokens, params.LargeSizeTokens)
+	}
+	return classifier.NewSizeClassClassifier(params.MediumSizeTokens, params.LargeSizeTokens), nil
+}
+
+func newTenantClassifier(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		Header string `json:"header"`
+	}{Header: classifier.DefaultTenantHeader}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	if params.Header == "" {
+		return nil, fmt.Errorf("header is required")
+	}
+	return classifier.NewTenantClassifier(params.Header), nil
+}
+
+func newPrefillDecodeProfilePicker(parameters json.RawMessage, _ framework.Handle) (framework.Plugin, error) {
+	params := struct {
+		PrefillProfile string `json:"prefillProfile"`
+		DecodeProfile  string `json:"decodeProfile"`
+	}{PrefillProfile: profilepicker.DefaultPrefillProfile, DecodeProfile: profilepicker.DefaultDecodeProfile}
+	if err := framework.DecodeParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+	return profilepicker.NewPrefillDecodeProfilePicker(params.PrefillProfile, params.DecodeProfile), nil
+}
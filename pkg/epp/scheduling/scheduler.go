@@ -18,11 +18,24 @@ limitations under the License.
 package scheduling
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
@@ -30,11 +43,18 @@ import (
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
 	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/schedulingtrace"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/tracing"
 	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
 )
 
 // NewScheduler returns a new scheduler with default scheduler plugins configuration.
 func NewScheduler(datastore Datastore) *Scheduler {
+	return NewSchedulerWithConfig(datastore, NewDefaultSchedulerConfig())
+}
+
+// NewDefaultSchedulerConfig returns the scheduler config used by NewScheduler.
+func NewDefaultSchedulerConfig() *SchedulerConfig {
 	// When the scheduler is initialized with NewScheduler function, thw below config will be used as default.
 	// it's possible to call NewSchedulerWithConfig to pass a different scheduler config.
 	// For build time plugins changes, it's recommended to call in main.go to NewSchedulerWithConfig.
@@ -70,30 +90,241 @@ func NewScheduler(datastore Datastore) *Scheduler {
 
 	profilePicker := profilepicker.NewAllProfilesPicker()
 
-	return NewSchedulerWithConfig(datastore, NewSchedulerConfig(profilePicker, map[string]*framework.SchedulerProfile{"default": defaultProfile}))
+	return NewSchedulerConfig(profilePicker, map[string]*framework.SchedulerProfile{"default": defaultProfile})
 }
 
 // NewSchedulerWithConfig returns a new scheduler with the given scheduler plugins configuration.
 func NewSchedulerWithConfig(datastore Datastore, config *SchedulerConfig) *Scheduler {
-	return &Scheduler{
-		datastore:     datastore,
-		profilePicker: config.profilePicker,
-		profiles:      config.profiles,
+	scheduler := &Scheduler{
+		datastore:   datastore,
+		environment: framework.NewEnvironment(),
+	}
+	if config.environment != nil {
+		scheduler.environment = *config.environment
+	}
+	scheduler.UpdateConfig(config)
+	if config.decisionReuse != nil && config.decisionReuse.MaxCyclesPerSecond > 0 {
+		scheduler.decisions = newDecisionCache(*config.decisionReuse, scheduler.environment)
+	}
+	if config.sharding != nil && len(config.sharding.Families) > 0 {
+		scheduler.sharding = newModelFamilySharding(*config.sharding)
 	}
+	return scheduler
 }
 
 type Scheduler struct {
-	datastore     Datastore
-	profilePicker framework.ProfilePicker
-	profiles      map[string]*framework.SchedulerProfile
+	datastore   Datastore
+	environment framework.Environment
+	profiles    atomic.Pointer[schedulerProfiles]
+	decisions   *decisionCache       // nil if decision reuse is disabled
+	sharding    *modelFamilySharding // nil if model family sharding is disabled
+	generations snapshotGenerations
+	// snapshot is the last snapshot of a SnapshotDatastore, wrapped for the scheduling contexts.
+	snapshot atomic.Pointer[podsSnapshot]
+}
+
+// schedulerProfiles is the part of the SchedulerConfig that can be updated at runtime.
+type schedulerProfiles struct {
+	profilePicker  framework.ProfilePicker
+	profiles       map[string]*framework.SchedulerProfile
+	profileTimeout time.Duration // zero if profiles have no deadline
+	postSchedule   []framework.PostSchedule
+	classifiers    []framework.Classifier
+	// sloBurningProfile is the profile of the requests whose SLO burns fast, empty if none.
+	sloBurningProfile string
+}
+
+// UpdateConfig atomically replaces the profile picker, the profiles, the profile timeout, the
+// PostSchedule plugins, the classifiers and the SLO burning profile of the scheduler with the ones
+// of the given config. The requests being scheduled keep using the previous profiles, so each
+// request is scheduled with a consistent configuration.
+// The decision reuse and model family sharding configurations and the environment can't be
+// updated, and are ignored: the Environment of the scheduler is set on the profiles and plugins of
+// the given config.
+func (s *Scheduler) UpdateConfig(config *SchedulerConfig) {
+	setEnvironment(config, s.environment)
+	s.profiles.Store(&schedulerProfiles{
+		profilePicker:     config.profilePicker,
+		profiles:          config.profiles,
+		profileTimeout:    config.profileTimeout,
+		postSchedule:      config.postSchedule,
+		classifiers:       config.classifiers,
+		sloBurningProfile: config.sloBurningProfile,
+	})
+	registerPluginMetrics(config)
+}
+
+// setEnvironment sets the given Environment on the profiles of the given config, and on its
+// plugins that implement EnvironmentAware.
+func setEnvironment(config *SchedulerConfig, env framework.Environment) {
+	plugins := []framework.Plugin{config.profilePicker}
+	for _, plugin := range config.postSchedule {
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range config.classifiers {
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range plugins {
+		if aware, ok := plugin.(framework.EnvironmentAware); ok {
+			aware.SetEnvironment(env)
+		}
+	}
+	for _, profile := range config.profiles {
+		profile.SetEnvironment(env)
+	}
+}
+
+// registerPluginMetrics registers the metrics of the Observable plugins of the given config, in
+// place of the metrics of the plugins of the previous config.
+func registerPluginMetrics(config *SchedulerConfig) {
+	plugins := []framework.Plugin{config.profilePicker}
+	for _, plugin := range config.postSchedule {
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range config.classifiers {
+		plugins = append(plugins, plugin)
+	}
+	// The profiles are visited in a stable order, so the same plugin wins when several profiles have
+	// different plugins of the same name.
+	for _, name := range slices.Sorted(maps.Keys(config.profiles)) {
+		for _, plugin := range config.profiles[name].ObservablePlugins() {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	pluginMetrics := []*metrics.PluginMetrics{}
+	seen := map[string]bool{}
+	for _, plugin := range plugins {
+		if observable, ok := plugin.(framework.Observable); ok && !seen[plugin.Name()] {
+			seen[plugin.Name()] = true
+			m := metrics.NewPluginMetrics(plugin.Name())
+			observable.RegisterMetrics(m)
+			pluginMetrics = append(pluginMetrics, m)
+		}
+	}
+	if err := metrics.SetPluginMetrics(pluginMetrics...); err != nil {
+		log.Log.Error(err, "Failed to register the metrics of the scheduler plugins")
+	}
+}
+
+// ExportState returns the state of the Stateful plugins of the scheduler, keyed by plugin name.
+func (s *Scheduler) ExportState() (map[string]json.RawMessage, error) {
+	state := map[string]json.RawMessage{}
+	for name, plugin := range s.profiles.Load().statefulPlugins() {
+		pluginState, err := plugin.ExportState()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export the state of plugin %q: %w", name, err)
+		}
+		state[name] = pluginState
+	}
+	return state, nil
+}
+
+// ImportState imports the given state, as returned by ExportState, into the Stateful plugins of the
+// scheduler of the same name. The state of the plugins the scheduler doesn't have is ignored. The
+// plugins that fail to import their state are reported in the returned error, and don't prevent
+// the other plugins from importing theirs.
+func (s *Scheduler) ImportState(ctx context.Context, state map[string]json.RawMessage) error {
+	logger := log.FromContext(ctx)
+	plugins := s.profiles.Load().statefulPlugins()
+	errs := []error{}
+	for _, name := range slices.Sorted(maps.Keys(state)) {
+		plugin, ok := plugins[name]
+		if !ok {
+			logger.V(logutil.DEFAULT).Info("Ignoring the state of a plugin the scheduler doesn't have", "plugin", name)
+			continue
+		}
+		if err := plugin.ImportState(state[name]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to import the state of plugin %q: %w", name, err))
+			continue
+		}
+		logger.V(logutil.DEFAULT).Info("Imported the state of a plugin", "plugin", name)
+	}
+	return errors.Join(errs...)
+}
+
+// statefulPlugins returns the Stateful plugins of the profiles, keyed by plugin name. As for the
+// metrics, the same plugin wins when several profiles have different plugins of the same name.
+func (p *schedulerProfiles) statefulPlugins() map[string]framework.Stateful {
+	plugins := []framework.Plugin{p.profilePicker}
+	for _, plugin := range p.postSchedule {
+		plugins = append(plugins, plugin)
+	}
+	for _, plugin := range p.classifiers {
+		plugins = append(plugins, plugin)
+	}
+	for _, name := range slices.Sorted(maps.Keys(p.profiles)) {
+		for _, plugin := range p.profiles[name].StatefulPlugins() {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	statefuls := map[string]framework.Stateful{}
+	for _, plugin := range plugins {
+		if stateful, ok := plugin.(framework.Stateful); ok {
+			if _, seen := statefuls[plugin.Name()]; !seen {
+				statefuls[plugin.Name()] = stateful
+			}
+		}
+	}
+	return statefuls
 }
 
 type Datastore interface {
 	PodGetAll() []backendmetrics.PodMetrics
 }
 
+// SnapshotDatastore is a Datastore that keeps an immutable snapshot of its pods. The scheduler
+// reuses the snapshot across requests until the pods change, rather than copying all the pods and
+// their metrics on every request.
+type SnapshotDatastore interface {
+	Datastore
+	PodSnapshot() *backendmetrics.PodSnapshot
+}
+
+// podsSnapshot is the state of the pods a request is scheduled with. It's shared by the requests
+// scheduled with the same snapshot generation, so it must not be modified.
+type podsSnapshot struct {
+	generation uint64
+	all        []types.Pod
+	// uncordoned are the pods new requests can be routed to.
+	uncordoned []types.Pod
+	// families are the uncordoned pods by model family, nil without sharding.
+	families map[string][]types.Pod
+}
+
+func newPodsSnapshot(generation uint64, pods []types.Pod, sharding *modelFamilySharding) *podsSnapshot {
+	uncordoned := make([]types.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if !pod.GetPod().Cordoned {
+			uncordoned = append(uncordoned, pod)
+		}
+	}
+	return &podsSnapshot{generation: generation, all: pods, uncordoned: uncordoned, families: sharding.partition(uncordoned)}
+}
+
+// podsSnapshot returns the current state of the pods of the datastore. With a SnapshotDatastore,
+// it's the last snapshot unless the pods changed since; otherwise the pods are copied.
+func (s *Scheduler) podsSnapshot() *podsSnapshot {
+	sds, ok := s.datastore.(SnapshotDatastore)
+	if !ok {
+		pods := s.datastore.PodGetAll()
+		return newPodsSnapshot(s.generations.of(pods), types.ToSchedulerPodMetrics(pods), s.sharding)
+	}
+	snapshot := sds.PodSnapshot()
+	if cached := s.snapshot.Load(); cached != nil && cached.generation == snapshot.Generation {
+		return cached
+	}
+	// Concurrent requests may wrap the same new snapshot, which is harmless.
+	wrapped := newPodsSnapshot(snapshot.Generation, types.FromPodSnapshot(snapshot.Pods), s.sharding)
+	s.snapshot.Store(wrapped)
+	return wrapped
+}
+
 // Schedule finds the target pod based on metrics and the requested lora adapter.
-func (s *Scheduler) Schedule(ctx context.Context, req *types.LLMRequest) (map[string]*types.Result, error) {
+func (s *Scheduler) Schedule(ctx context.Context, req *types.LLMRequest) (_ map[string]*types.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "Scheduler.Schedule", trace.WithAttributes(attribute.String("epp.target_model", req.TargetModel)))
+	defer func() { tracing.End(span, err) }()
 	logger := log.FromContext(ctx).WithValues("request", req)
 	loggerDebug := logger.V(logutil.DEBUG)
 
@@ -105,44 +336,320 @@ func (s *Scheduler) Schedule(ctx context.Context, req *types.LLMRequest) (map[st
 	// Snapshot pod metrics from the datastore to:
 	// 1. Reduce concurrent access to the datastore.
 	// 2. Ensure consistent data during the scheduling operation of a request between all scheduling cycles.
-	sCtx := types.NewSchedulingContext(ctx, req, nil, types.ToSchedulerPodMetrics(s.datastore.PodGetAll()))
+	// Cordoned pods are excluded from the snapshot, so none of the profiles can pick them.
+	snapshotStart := time.Now()
+	snapshot := s.podsSnapshot()
+	tracing.Span(ctx, "Scheduler.podsSnapshot", snapshotStart,
+		attribute.Int64("epp.snapshot.generation", int64(snapshot.generation)), attribute.Int("epp.pods", len(snapshot.all)))
+	pods := snapshot.uncordoned
+	// The requests for the models of a family only run through the pods of this family.
+	if family := s.sharding.family(req.TargetModel); family != "" {
+		pods = snapshot.families[family]
+		loggerDebug.Info("Scheduling the request on the pods of its model family", "family", family, "pods", len(pods))
+	}
+
+	config := s.profiles.Load()
+	sCtx := types.NewSchedulingContext(ctx, req, nil, pods)
+	sCtx.SnapshotGeneration = snapshot.generation
+	// The request is classified first, so the profile picker and the profiles can rely on its labels.
+	runClassifiers(sCtx, config.classifiers)
+
+	profilePicker := config.profilePicker
+	// A request pinned to a profile only runs this profile, and doesn't share the scheduling
+	// decisions of the requests scheduled by the profile picker. The requests whose SLO burns fast
+	// are pinned to the SLO burning profile, unless their route pins them already.
+	profile := req.SchedulingProfile
+	if _, ok := config.profiles[profile]; !ok && req.SLOBurningFast && config.sloBurningProfile != "" {
+		profile = config.sloBurningProfile
+	}
+	_, pinned := config.profiles[profile]
+	if pinned {
+		profilePicker = profilepicker.NewSingleProfilePicker(profile)
+	}
+
+	// The requests whose SLO burns fast always run a full cycle rather than reuse a stale decision.
+	if s.decisions != nil && !pinned && !req.SLOBurningFast && !s.decisions.allowCycle(req.TargetModel) {
+		available := make(map[k8stypes.NamespacedName]bool, len(pods))
+		for _, pod := range pods {
+			available[pod.GetPod().NamespacedName] = true
+		}
+		if results := s.decisions.replay(req.TargetModel, available); results != nil {
+			loggerDebug.Info("Reusing a recent scheduling decision", "results", results)
+			metrics.RecordSchedulerReusedDecision(req.TargetModel)
+			schedulingtrace.FromContext(ctx).ReuseDecision(results)
+			if postSchedule := config.postSchedule; len(postSchedule) > 0 {
+				return runPostSchedulePlugins(sCtx, postSchedule, results)
+			}
+			return results, nil
+		}
+	}
+
 	loggerDebug.Info(fmt.Sprintf("Scheduling a request, Metrics: %+v", sCtx.PodsSnapshot))
 
 	profileExecutionResults := map[string]*types.Result{}
 
 	for { // get the next set of profiles to run iteratively based on the request and the previous execution results
 		before := time.Now()
-		profiles := s.profilePicker.Pick(req, s.profiles, profileExecutionResults)
-		metrics.RecordSchedulerPluginProcessingLatency(framework.ProfilePickerType, s.profilePicker.Name(), time.Since(before))
+		profiles := profilePicker.Pick(req, config.profiles, profileExecutionResults)
+		framework.RecordPluginLatency(ctx, "", framework.ProfilePickerType, profilePicker.Name(), before)
 		if len(profiles) == 0 { // profile picker didn't pick any profile to run
 			break
 		}
 
+		// the selected profiles are independent of each other, so they run concurrently, while they
+		// can depend on the results of the profiles that ran before
+		sCtx.ProfileResults = maps.Clone(profileExecutionResults)
+		results, err := runProfiles(sCtx, profiles, config.profiles, config.profileTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run all required scheduling profiles - %w", err)
+		}
+		for name, result := range results {
+			profileExecutionResults[name] = result
+		}
+	}
+
+	// the failed optional profiles have an empty result, so the profile picker doesn't pick them again
+	maps.DeleteFunc(profileExecutionResults, func(_ string, result *types.Result) bool { return result.TargetPod == nil })
+	if len(profileExecutionResults) == 0 {
+		return nil, fmt.Errorf("failed to run any SchedulingProfile for the request - %s", req)
+	}
+
+	if s.decisions != nil && !pinned {
+		s.decisions.record(req.TargetModel, profileExecutionResults)
+	}
+
+	if len(config.postSchedule) > 0 {
+		return runPostSchedulePlugins(sCtx, config.postSchedule, profileExecutionResults)
+	}
+	return profileExecutionResults, nil
+}
+
+// runClassifiers merges the labels of the given classifiers into the labels of the request, in
+// order, a later classifier overriding the labels of the previous ones. An empty value removes the
+// label.
+func runClassifiers(sCtx *types.SchedulingContext, classifiers []framework.Classifier) {
+	for _, classifier := range classifiers {
+		before := time.Now()
+		labels := classifier.Classify(sCtx)
+		framework.RecordPluginLatency(sCtx, "", framework.ClassifierPluginType, classifier.Name(), before)
+		for key, value := range labels {
+			if value == "" {
+				delete(sCtx.Req.Labels, key)
+				continue
+			}
+			if sCtx.Req.Labels == nil {
+				sCtx.Req.Labels = map[string]string{}
+			}
+			sCtx.Req.Labels[key] = value
+		}
+	}
+	if len(classifiers) > 0 {
+		sCtx.Logger.V(logutil.DEBUG).Info("Classified the request", "labels", sCtx.Req.Labels)
+	}
+}
+
+// runPostSchedulePlugins runs the given PostSchedule plugins on a copy of the given results, and
+// returns the rewritten copy, or the error of the first plugin vetoing the results.
+func runPostSchedulePlugins(sCtx *types.SchedulingContext, plugins []framework.PostSchedule, results map[string]*types.Result) (map[string]*types.Result, error) {
+	rewritten := make(map[string]*types.Result, len(results))
+	for name, result := range results {
+		resultCopy := *result
+		resultCopy.FallbackPods = slices.Clone(result.FallbackPods)
+		rewritten[name] = &resultCopy
+	}
+	for _, plugin := range plugins {
+		sCtx.Logger.V(logutil.DEBUG).Info("Running post-schedule plugin", "plugin", plugin.Name())
+		before := time.Now()
+		err := plugin.PostSchedule(sCtx, rewritten)
+		framework.RecordPluginLatency(sCtx, "", framework.PostSchedulePluginType, plugin.Name(), before)
+		if err != nil {
+			return nil, fmt.Errorf("scheduling results rejected by '%s' - %w", plugin.Name(), err)
+		}
+	}
+	return rewritten, nil
+}
+
+// runProfiles runs the given profiles concurrently and collects their results. The first profile
+// that fails cancels the context of the others.
+func runProfiles(sCtx *types.SchedulingContext, profiles, allProfiles map[string]*framework.SchedulerProfile, timeout time.Duration) (map[string]*types.Result, error) {
+	if len(profiles) == 1 {
 		for name, profile := range profiles {
-			// run the selected profiles and collect results (current code runs all profiles)
-			profileExecutionResult, err := profile.RunCycle(sCtx)
+			result, err := runProfileWithFailurePolicy(sCtx, sCtx, name, profile, allProfiles, timeout)
 			if err != nil {
-				return nil, fmt.Errorf("failed to run all required scheduling profiles - %w", err)
+				return nil, err
 			}
+			return map[string]*types.Result{name: result}, nil
+		}
+	}
+
+	group, groupCtx := errgroup.WithContext(sCtx)
+	var mu sync.Mutex
+	results := make(map[string]*types.Result, len(profiles))
+	for name, profile := range profiles {
+		group.Go(func() error {
+			result, err := runProfileWithFailurePolicy(groupCtx, sCtx, name, profile, allProfiles, timeout)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
-			profileExecutionResults[name] = profileExecutionResult
+// runProfileWithFailurePolicy runs a single profile cycle, and handles its failure according to the
+// failure policy of the profile: the fallback profile runs instead if there is one, and the failed
+// optional profiles get an empty result.
+func runProfileWithFailurePolicy(ctx context.Context, sCtx *types.SchedulingContext, name string, profile *framework.SchedulerProfile,
+	allProfiles map[string]*framework.SchedulerProfile, timeout time.Duration) (*types.Result, error) {
+	result, err := runProfile(ctx, sCtx, name, profile, timeout)
+	if err == nil {
+		return result, nil
+	}
+
+	policy, fallbackName := profile.FailurePolicy()
+	if fallback := allProfiles[fallbackName]; fallback != nil && fallbackName != name {
+		sCtx.Logger.V(logutil.DEBUG).Info("Profile failed, running its fallback profile", "profile", name, "fallback", fallbackName, "err", err)
+		result, fallbackErr := runProfile(ctx, sCtx, fallbackName, fallback, timeout)
+		if fallbackErr == nil {
+			metrics.RecordSchedulerProfileFailure(name, "fallback")
+			return result, nil
 		}
+		err = fmt.Errorf("%w, and its fallback profile '%s' failed too - %w", err, fallbackName, fallbackErr)
+	}
+	if policy == framework.ProfileOptional {
+		sCtx.Logger.V(logutil.DEBUG).Info("Optional profile failed, skipping it", "profile", name, "err", err)
+		metrics.RecordSchedulerProfileFailure(name, "skipped")
+		return &types.Result{}, nil
 	}
+	metrics.RecordSchedulerProfileFailure(name, "failed")
+	return nil, err
+}
 
-	if len(profileExecutionResults) == 0 {
-		return nil, fmt.Errorf("failed to run any SchedulingProfile for the request - %s", req)
+// runProfile runs a single profile cycle and records its latency and outcome. A failed cycle of a
+// request that isn't critical is recorded as shed if no pod passed the filters.
+func runProfile(ctx context.Context, sCtx *types.SchedulingContext, name string, profile *framework.SchedulerProfile, timeout time.Duration) (*types.Result, error) {
+	before := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, "SchedulerProfile.RunCycle", trace.WithAttributes(attribute.String("epp.profile", name)))
+	result, err := runProfileCycle(ctx, sCtx, name, profile, timeout)
+	outcome := "scheduled"
+	if err != nil {
+		outcome = "failed"
+		if errors.Is(err, framework.ErrNoPodsAvailable) && sCtx.Req != nil && !sCtx.Req.Critical {
+			outcome = "shed"
+		}
 	}
+	metrics.RecordSchedulerProfileDecision(name, outcome, time.Since(before))
+	span.SetAttributes(attribute.String("epp.outcome", outcome))
+	tracing.End(span, err)
+	return result, err
+}
 
-	return profileExecutionResults, nil
+// runProfileCycle runs a single profile cycle with the given context, enforcing the profile
+// deadline if the timeout is positive. The profiles share the CycleState of the scheduling
+// context, which is safe for concurrent use. A cycle that outlives its deadline is abandoned: its
+// context is cancelled so its plugins can stop early, and its post-cycle plugins never run, as
+// they only run once the result of the cycle is kept.
+func runProfileCycle(ctx context.Context, sCtx *types.SchedulingContext, name string, profile *framework.SchedulerProfile, timeout time.Duration) (*types.Result, error) {
+	profileCtx := *sCtx
+	profileCtx.ProfileName = name
+	if timeout <= 0 {
+		profileCtx.Context = ctx
+		return profile.RunCycle(&profileCtx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	profileCtx.Context = timeoutCtx
+
+	type cycleResult struct {
+		result *types.Result
+		err    error
+	}
+	done := make(chan cycleResult, 1) // buffered, so a cycle that outlives its deadline doesn't leak
+	go func() {
+		result, err := profile.RunCycleUntilPick(&profileCtx)
+		done <- cycleResult{result: result, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return profile.RunPostCycle(&profileCtx, res.result)
+	case <-timeoutCtx.Done():
+		return nil, fmt.Errorf("profile '%s' did not complete - %w", name, timeoutCtx.Err())
+	}
+}
+
+// snapshotGenerations numbers the successive states of the pods of a datastore that doesn't keep a
+// snapshot of its pods. The datastore
+// replaces the pod and metrics objects of a pod when they change rather than updating them in place,
+// so the state of the pods is identified by the identity of these objects.
+type snapshotGenerations struct {
+	mu         sync.Mutex
+	generation uint64
+	last       []podState
+}
+
+type podState struct {
+	pod     *backend.Pod
+	metrics *backendmetrics.MetricsState
+}
+
+// of returns the generation of the given pods, which changes whenever a pod is added, removed or
+// updated, or whenever its metrics are refreshed.
+func (g *snapshotGenerations) of(pods []backendmetrics.PodMetrics) uint64 {
+	state := make([]podState, 0, len(pods))
+	for _, pod := range pods {
+		state = append(state, podState{pod: pod.GetPod(), metrics: pod.GetMetrics()})
+	}
+	// The datastore doesn't list the pods in a stable order.
+	slices.SortFunc(state, func(a, b podState) int {
+		return cmp.Or(strings.Compare(a.pod.NamespacedName.Namespace, b.pod.NamespacedName.Namespace),
+			strings.Compare(a.pod.NamespacedName.Name, b.pod.NamespacedName.Name))
+	})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.generation == 0 || !slices.Equal(state, g.last) {
+		g.generation = backendmetrics.NextSnapshotGeneration()
+		g.last = state
+	}
+	return g.generation
 }
 
 // OnResponse is invoked during the processing of a response from an inference pod. It will invoke
 // any defined plugins that process the response.
 func (s *Scheduler) OnResponse(ctx context.Context, resp *types.LLMResponse, targetPodName string) {
+	s.runPostResponse(ctx, resp, targetPodName, s.runPostResponsePlugins)
+}
+
+// OnResponseComplete is invoked once the response from an inference pod was fully received. It will
+// invoke the PostResponse plugins that implement PostResponseComplete.
+func (s *Scheduler) OnResponseComplete(ctx context.Context, resp *types.LLMResponse, targetPodName string) {
+	s.runPostResponse(ctx, resp, targetPodName, s.runPostResponseCompletePlugins)
+}
+
+// OnRequestEnd is invoked once the processing of a scheduled request ended, whatever its outcome. It
+// will invoke the PostResponse plugins that implement RequestEnd.
+func (s *Scheduler) OnRequestEnd(ctx context.Context, resp *types.LLMResponse, targetPodName string) {
+	s.runPostResponse(ctx, resp, targetPodName, s.runRequestEndPlugins)
+}
+
+// runPostResponse runs the given post-response plugins of all the profiles.
+func (s *Scheduler) runPostResponse(ctx context.Context, resp *types.LLMResponse, targetPodName string,
+	run func(ctx *types.SchedulingContext, targetPod types.Pod, profileName string, profile *framework.SchedulerProfile)) {
 	// Snapshot pod metrics from the datastore to:
 	// 1. Reduce concurrent access to the datastore.
 	// 2. Ensure consistent data during the scheduling operation of a request.
-	pods := types.ToSchedulerPodMetrics(s.datastore.PodGetAll())
+	pods := s.podsSnapshot().all
 	var targetPod types.Pod
 	for _, pod := range pods {
 		if pod.GetPod().NamespacedName.String() == targetPodName {
@@ -155,17 +662,44 @@ func (s *Scheduler) OnResponse(ctx context.Context, resp *types.LLMResponse, tar
 
 	// WORKAROUND until PostResponse is out of Scheduler
 	profileExecutionResults := map[string]*types.Result{}
-	profiles := s.profilePicker.Pick(nil, s.profiles, profileExecutionResults) // all profiles
-	for _, profile := range profiles {
-		s.runPostResponsePlugins(sCtx, targetPod, profile)
+	config := s.profiles.Load()
+	profiles := config.profilePicker.Pick(nil, config.profiles, profileExecutionResults) // all profiles
+	for name, profile := range profiles {
+		run(sCtx, targetPod, name, profile)
 	}
 }
 
-func (s *Scheduler) runPostResponsePlugins(ctx *types.SchedulingContext, targetPod types.Pod, profile *framework.SchedulerProfile) {
+func (s *Scheduler) runPostResponsePlugins(ctx *types.SchedulingContext, targetPod types.Pod, profileName string, profile *framework.SchedulerProfile) {
 	for _, plugin := range profile.PostResponsePlugins {
 		ctx.Logger.V(logutil.DEBUG).Info("Running post-response plugin", "plugin", plugin.Name())
 		before := time.Now()
 		plugin.PostResponse(ctx, targetPod)
-		metrics.RecordSchedulerPluginProcessingLatency(framework.PostResponsePluginType, plugin.Name(), time.Since(before))
+		framework.RecordPluginLatency(ctx, profileName, framework.PostResponsePluginType, plugin.Name(), before)
+	}
+}
+
+func (s *Scheduler) runPostResponseCompletePlugins(ctx *types.SchedulingContext, targetPod types.Pod, profileName string, profile *framework.SchedulerProfile) {
+	for _, plugin := range profile.PostResponsePlugins {
+		completePlugin, ok := plugin.(framework.PostResponseComplete)
+		if !ok {
+			continue
+		}
+		ctx.Logger.V(logutil.DEBUG).Info("Running post-response-complete plugin", "plugin", plugin.Name())
+		before := time.Now()
+		completePlugin.PostResponseComplete(ctx, targetPod)
+		framework.RecordPluginLatency(ctx, profileName, framework.PostResponseCompletePluginType, plugin.Name(), before)
+	}
+}
+
+func (s *Scheduler) runRequestEndPlugins(ctx *types.SchedulingContext, targetPod types.Pod, profileName string, profile *framework.SchedulerProfile) {
+	for _, plugin := range profile.PostResponsePlugins {
+		endPlugin, ok := plugin.(framework.RequestEnd)
+		if !ok {
+			continue
+		}
+		ctx.Logger.V(logutil.DEBUG).Info("Running request-end plugin", "plugin", plugin.Name())
+		before := time.Now()
+		endPlugin.RequestEnd(ctx, targetPod)
+		framework.RecordPluginLatency(ctx, profileName, framework.RequestEndPluginType, plugin.Name(), before)
 	}
 }
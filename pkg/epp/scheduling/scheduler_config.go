@@ -16,7 +16,14 @@ limitations under the License.
 
 package scheduling
 
-import "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+)
 
 // NewSchedulerConfig creates a new SchedulerConfig object and returns its pointer.
 func NewSchedulerConfig(profilePicker framework.ProfilePicker, profiles map[string]*framework.SchedulerProfile) *SchedulerConfig {
@@ -28,6 +35,175 @@ func NewSchedulerConfig(profilePicker framework.ProfilePicker, profiles map[stri
 
 // SchedulerConfig provides a configuration for the scheduler which influence routing decisions.
 type SchedulerConfig struct {
-	profilePicker framework.ProfilePicker
-	profiles      map[string]*framework.SchedulerProfile
+	profilePicker  framework.ProfilePicker
+	profiles       map[string]*framework.SchedulerProfile
+	decisionReuse  *DecisionReuseConfig
+	sharding       *ModelFamilyShardingConfig
+	profileTimeout time.Duration
+	postSchedule   []framework.PostSchedule
+	classifiers    []framework.Classifier
+	environment    *framework.Environment
+	// sloBurningProfile is the profile of the requests whose SLO burns fast, empty if none.
+	sloBurningProfile string
+}
+
+// WithSLOBurningProfile sets the profile the requests of the models whose SLO error budget burns
+// fast are scheduled with, e.g. a stricter profile favoring the least loaded pods, unless their
+// route pins them to a profile. Empty schedules them with the profile picker.
+func (c *SchedulerConfig) WithSLOBurningProfile(profile string) *SchedulerConfig {
+	c.sloBurningProfile = profile
+	return c
+}
+
+// WithDecisionReuse enables reusing recent scheduling decisions under overload, see DecisionReuseConfig.
+func (c *SchedulerConfig) WithDecisionReuse(config DecisionReuseConfig) *SchedulerConfig {
+	c.decisionReuse = &config
+	return c
+}
+
+// WithProfileTimeout sets a deadline for running each scheduler profile. A profile that doesn't
+// complete within the timeout fails the scheduling of the request. Zero means no deadline.
+func (c *SchedulerConfig) WithProfileTimeout(timeout time.Duration) *SchedulerConfig {
+	c.profileTimeout = timeout
+	return c
+}
+
+// WithPostSchedulePlugins sets the plugins run in order after all the profiles ran, which may
+// rewrite or veto the results of the scheduling, see framework.PostSchedule.
+func (c *SchedulerConfig) WithPostSchedulePlugins(plugins ...framework.PostSchedule) *SchedulerConfig {
+	c.postSchedule = plugins
+	return c
+}
+
+// WithModelFamilySharding enables partitioning the pods by model family, see
+// ModelFamilyShardingConfig.
+func (c *SchedulerConfig) WithModelFamilySharding(config ModelFamilyShardingConfig) *SchedulerConfig {
+	c.sharding = &config
+	return c
+}
+
+// WithClassifiers sets the plugins run in order before the profiles are picked, to label the
+// requests, see framework.Classifier.
+func (c *SchedulerConfig) WithClassifiers(classifiers ...framework.Classifier) *SchedulerConfig {
+	c.classifiers = classifiers
+	return c
+}
+
+// WithEnvironment sets the source of the time and of the randomness of the scheduler, of its
+// profiles and of their plugins, see framework.Environment. It defaults to
+// framework.NewEnvironment.
+func (c *SchedulerConfig) WithEnvironment(env framework.Environment) *SchedulerConfig {
+	c.environment = &env
+	return c
+}
+
+// ConfigDescription is the effective configuration of a SchedulerConfig, as printed when the
+// configuration of the Endpoint Picker is validated.
+type ConfigDescription struct {
+	ProfilePicker       string                                  `json:"profilePicker,omitempty"`
+	Classifiers         []string                                `json:"classifiers,omitempty"`
+	Profiles            map[string]framework.ProfileDescription `json:"profiles"`
+	ProfileTimeout      string                                  `json:"profileTimeout,omitempty"`
+	PostSchedulePlugins []string                                `json:"postSchedulePlugins,omitempty"`
+	DecisionReuse       *DecisionReuseDescription               `json:"decisionReuse,omitempty"`
+	ModelFamilyLabel    string                                  `json:"modelFamilyLabel,omitempty"`
+	ModelFamilies       map[string][]string                     `json:"modelFamilies,omitempty"`
+}
+
+// DecisionReuseDescription is the effective configuration of the decision reuse, see
+// DecisionReuseConfig.
+type DecisionReuseDescription struct {
+	MaxCyclesPerSecond int    `json:"maxCyclesPerSecond"`
+	Freshness          string `json:"freshness"`
+	HistorySize        int    `json:"historySize"`
+}
+
+// Describe returns the effective configuration of the SchedulerConfig, the plugins being listed by
+// name in the order they run.
+func (c *SchedulerConfig) Describe() ConfigDescription {
+	description := ConfigDescription{
+		Profiles: make(map[string]framework.ProfileDescription, len(c.profiles)),
+	}
+	if c.profileTimeout != 0 {
+		description.ProfileTimeout = c.profileTimeout.String()
+	}
+	if c.profilePicker != nil {
+		description.ProfilePicker = c.profilePicker.Name()
+	}
+	for _, classifier := range c.classifiers {
+		description.Classifiers = append(description.Classifiers, classifier.Name())
+	}
+	for name, profile := range c.profiles {
+		description.Profiles[name] = profile.Describe()
+	}
+	for _, plugin := range c.postSchedule {
+		description.PostSchedulePlugins = append(description.PostSchedulePlugins, plugin.Name())
+	}
+	if c.decisionReuse != nil && c.decisionReuse.MaxCyclesPerSecond > 0 {
+		description.DecisionReuse = &DecisionReuseDescription{
+			MaxCyclesPerSecond: c.decisionReuse.MaxCyclesPerSecond,
+			Freshness:          c.decisionReuse.Freshness.String(),
+			HistorySize:        c.decisionReuse.HistorySize,
+		}
+	}
+	if c.sharding != nil && len(c.sharding.Families) > 0 {
+		description.ModelFamilyLabel = c.sharding.Label
+		description.ModelFamilies = c.sharding.Families
+	}
+	return description
+}
+
+// Validate checks the consistency of the SchedulerConfig and of its profiles, see
+// framework.SchedulerProfile.Validate. The returned error joins all the problems found, and the
+// warnings are the settings that are likely mistakes but don't prevent the scheduler from running.
+func (c *SchedulerConfig) Validate() (warnings []string, err error) {
+	var errs []error
+	if c.profilePicker == nil {
+		errs = append(errs, errors.New("a profile picker is required"))
+	}
+	if len(c.profiles) == 0 {
+		errs = append(errs, errors.New("at least one profile is required"))
+	}
+	if c.profileTimeout < 0 {
+		errs = append(errs, fmt.Errorf("negative profile timeout %s", c.profileTimeout))
+	}
+	if _, ok := c.profiles[c.sloBurningProfile]; c.sloBurningProfile != "" && !ok {
+		errs = append(errs, fmt.Errorf("unknown SLO burning profile '%s'", c.sloBurningProfile))
+	}
+
+	names := make([]string, 0, len(c.profiles))
+	for name := range c.profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		profile := c.profiles[name]
+		if profile == nil {
+			errs = append(errs, fmt.Errorf("profile '%s': the profile is nil", name))
+			continue
+		}
+		profileWarnings, err := profile.Validate()
+		for _, warning := range profileWarnings {
+			warnings = append(warnings, fmt.Sprintf("profile '%s': %s", name, warning))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("profile '%s': %w", name, err))
+		}
+
+		// A failed profile runs its fallback profile, but the fallbacks are not chained.
+		_, fallback := profile.FailurePolicy()
+		switch fallbackProfile, ok := c.profiles[fallback]; {
+		case fallback == "":
+		case fallback == name:
+			errs = append(errs, fmt.Errorf("profile '%s': a profile can't be its own fallback", name))
+		case !ok:
+			errs = append(errs, fmt.Errorf("profile '%s': unknown fallback profile '%s'", name, fallback))
+		case fallbackProfile != nil:
+			if _, next := fallbackProfile.FailurePolicy(); next != "" && next != name {
+				warnings = append(warnings, fmt.Sprintf("profile '%s': the fallback profile '%s' of its fallback profile '%s' "+
+					"doesn't run when both fail, as fallbacks are not chained", name, next, fallback))
+			}
+		}
+	}
+	return warnings, errors.Join(errs...)
 }
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
+	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/scorer"
+)
+
+func TestSchedulerConfigValidate(t *testing.T) {
+	newProfile := func(weight int) *framework.SchedulerProfile {
+		return framework.NewSchedulerProfile().
+			WithScorers(framework.NewWeightedScorer(&scorer.QueueScorer{}, weight)).
+			WithPicker(picker.NewMaxScorePicker())
+	}
+	tests := []struct {
+		name         string
+		config       *SchedulerConfig
+		wantWarnings []string
+		wantErrs     []string
+	}{
+		{
+			name: "valid config",
+			config: NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+				"a": newProfile(1).WithFailurePolicy(framework.ProfileRequired, "b"),
+				"b": newProfile(2),
+			}),
+		},
+		{
+			name:   "default config",
+			config: NewDefaultSchedulerConfig(),
+		},
+		{
+			name:     "no profile picker nor profiles",
+			config:   NewSchedulerConfig(nil, nil),
+			wantErrs: []string{"a profile picker is required", "at least one profile is required"},
+		},
+		{
+			name: "inconsistent profiles",
+			config: NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+				"no-picker": framework.NewSchedulerProfile(),
+				"negative":  newProfile(-1),
+				"zero":      newProfile(0).WithScoreFloor(0.5, framework.ScoreFloorReject),
+				"self":      newProfile(1).WithFailurePolicy(framework.ProfileOptional, "self"),
+				"unknown":   newProfile(1).WithFailurePolicy(framework.ProfileOptional, "missing"),
+				"policy":    newProfile(1).WithPluginPanicPolicy("ignore"),
+			}),
+			wantWarnings: []string{
+				"profile 'zero': scorer 'queue' has a zero weight and doesn't affect the picks",
+				"profile 'zero': score floor 0.5 is ignored as no scorer has a positive weight",
+			},
+			wantErrs: []string{
+				"profile 'negative': scorer 'queue' has a negative weight -1",
+				"profile 'no-picker': a picker is required",
+				"profile 'policy': unknown plugin panic policy 'ignore'",
+				"profile 'self': a profile can't be its own fallback",
+				"profile 'unknown': unknown fallback profile 'missing'",
+			},
+		},
+		{
+			name: "chained fallbacks",
+			config: NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+				"a": newProfile(1).WithFailurePolicy(framework.ProfileRequired, "b"),
+				"b": newProfile(1).WithFailurePolicy(framework.ProfileRequired, "c"),
+				"c": newProfile(1),
+			}),
+			wantWarnings: []string{
+				"profile 'a': the fallback profile 'c' of its fallback profile 'b' doesn't run when both fail, as fallbacks are not chained",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			warnings, err := test.config.Validate()
+			assert.Equal(t, test.wantWarnings, warnings)
+			if len(test.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, wantErr := range test.wantErrs {
+				assert.ErrorContains(t, err, wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedulerConfigDescribe(t *testing.T) {
+	config := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+		"default": framework.NewSchedulerProfile().
+			WithScorers(framework.NewWeightedScorer(&scorer.QueueScorer{}, 2).WithNormalization(framework.MinMaxNormalization)).
+			WithPicker(picker.NewMaxScorePicker()).
+			WithTimeout(10*time.Millisecond).
+			WithFailurePolicy(framework.ProfileOptional, ""),
+	}).WithProfileTimeout(time.Second).
+		WithDecisionReuse(DecisionReuseConfig{MaxCyclesPerSecond: 100, Freshness: 50 * time.Millisecond, HistorySize: 8})
+
+	want := ConfigDescription{
+		ProfilePicker: "all-profiles",
+		Profiles: map[string]framework.ProfileDescription{
+			"default": {
+				Scorers:           []framework.ScorerDescription{{Name: "queue", Weight: 2, Normalization: framework.MinMaxNormalization}},
+				Picker:            "max_score",
+				Timeout:           "10ms",
+				FailurePolicy:     framework.ProfileOptional,
+				PluginPanicPolicy: framework.PluginPanicSkip,
+			},
+		},
+		ProfileTimeout: "1s",
+		DecisionReuse:  &DecisionReuseDescription{MaxCyclesPerSecond: 100, Freshness: "50ms", HistorySize: 8},
+	}
+	if diff := cmp.Diff(want, config.Describe()); diff != "" {
+		t.Errorf("Unexpected description (-want +got): %s", diff)
+	}
+}
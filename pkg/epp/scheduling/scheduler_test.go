@@ -18,14 +18,27 @@ package scheduling
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics" // Import config for thresholds
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/filter"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
 	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
 )
@@ -301,6 +314,471 @@ func TestPostResponse(t *testing.T) {
 	}
 }
 
+func TestPostResponseComplete(t *testing.T) {
+	targetPod := k8stypes.NamespacedName{Name: "pod2"}
+	complete := &testPostResponseComplete{}
+	headersOnly := &testPostResponse{NameRes: "headers-only", ReceivedResponseHeaders: map[string]string{}}
+	schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+		"default": {PostResponsePlugins: []framework.PostResponse{headersOnly, complete}},
+	})
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		{Pod: &backend.Pod{NamespacedName: targetPod}},
+	}}, schedulerConfig)
+
+	resp := &types.LLMResponse{
+		Headers:          map[string]string{"Content-type": "text/event-stream"},
+		IsStreaming:      true,
+		EndOfStream:      true,
+		PromptTokens:     12,
+		CompletionTokens: 34,
+		FinishReason:     "stop",
+		Latency:          2 * time.Second,
+		TimeToFirstToken: 100 * time.Millisecond,
+	}
+	scheduler.OnResponseComplete(context.Background(), resp, targetPod.String())
+
+	if complete.resp != resp {
+		t.Errorf("PostResponseComplete received response %+v, want %+v", complete.resp, resp)
+	}
+	if complete.pod == nil || complete.pod.GetPod().NamespacedName != targetPod {
+		t.Errorf("PostResponseComplete received pod %v, want %s", complete.pod, targetPod)
+	}
+	if complete.onHeaders {
+		t.Error("PostResponse was called on the completion of the response")
+	}
+	if len(headersOnly.ReceivedResponseHeaders) != 0 {
+		t.Errorf("Plugin not implementing PostResponseComplete received headers %v", headersOnly.ReceivedResponseHeaders)
+	}
+}
+
+func TestSchedulerState(t *testing.T) {
+	oldScheduler := NewSchedulerWithConfig(&fakeDataStore{}, NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+		"a": {PostResponsePlugins: []framework.PostResponse{&testStateful{name: "affinity", state: `{"sessions":1}`}}},
+		"b": {PostResponsePlugins: []framework.PostResponse{&testStateful{name: "latency", state: `{"pods":2}`}}},
+	}))
+	state, err := oldScheduler.ExportState()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wantState := map[string]json.RawMessage{"affinity": json.RawMessage(`{"sessions":1}`), "latency": json.RawMessage(`{"pods":2}`)}
+	if diff := cmp.Diff(wantState, state); diff != "" {
+		t.Errorf("Unexpected state (-want +got): %v", diff)
+	}
+
+	// The state of the plugins the new scheduler doesn't have is ignored, and the plugins failing to
+	// import their state don't prevent the others from importing theirs.
+	affinity := &testStateful{name: "affinity"}
+	broken := &testStateful{name: "broken", importErr: errors.New("invalid state")}
+	newScheduler := NewSchedulerWithConfig(&fakeDataStore{}, NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{
+		"a": {PostResponsePlugins: []framework.PostResponse{affinity, broken}},
+	}))
+	state["broken"] = json.RawMessage(`{}`)
+	if err := newScheduler.ImportState(context.Background(), state); err == nil {
+		t.Error("Expected an error for the broken plugin")
+	}
+	if affinity.state != `{"sessions":1}` {
+		t.Errorf("Unexpected imported state: %s", affinity.state)
+	}
+}
+
+func TestScheduleProfilesConcurrently(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}, Labels: map[string]string{}}},
+	}
+	wantRes := map[string]*types.Result{}
+	for _, name := range []string{"prefill", "decode"} {
+		wantRes[name] = &types.Result{TargetPod: &types.ScoredPod{
+			Pod: &types.PodMetrics{Pod: pods[0].Pod, MetricsState: pods[0].Metrics},
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		filter  framework.Filter
+		timeout time.Duration
+		wantRes map[string]*types.Result
+		err     bool
+	}{
+		{
+			// Each profile blocks until the other one runs, so this only succeeds if they run concurrently.
+			name:    "profiles run concurrently",
+			filter:  &testBarrierFilter{barrier: newBarrier(2)},
+			timeout: 5 * time.Second,
+			wantRes: wantRes,
+		},
+		{
+			name:    "profile exceeding the deadline fails the request",
+			filter:  &testBarrierFilter{barrier: newBarrier(3)},
+			timeout: 10 * time.Millisecond,
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profiles := map[string]*framework.SchedulerProfile{}
+			for name := range wantRes {
+				profiles[name] = framework.NewSchedulerProfile().
+					WithFilters(test.filter).
+					WithPicker(&picker.RandomPicker{})
+			}
+			schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), profiles).
+				WithProfileTimeout(test.timeout)
+			scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+
+			got, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()})
+			if test.err != (err != nil) {
+				t.Errorf("Unexpected error, got %v, want %v", err, test.err)
+			}
+			if diff := cmp.Diff(test.wantRes, got); diff != "" {
+				t.Errorf("Unexpected output (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestScheduleAbandonedProfileCycle(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}, Labels: map[string]string{}}},
+	}
+	filter := &testSlowFilter{cancelled: make(chan struct{})}
+	postCycle := &testPostCycle{}
+	profile := framework.NewSchedulerProfile().
+		WithFilters(filter).
+		WithPicker(&picker.RandomPicker{}).
+		WithPostCyclePlugins(postCycle)
+	schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{"default": profile}).
+		WithProfileTimeout(10 * time.Millisecond)
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+
+	if _, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()}); err == nil {
+		t.Fatalf("Expected an error for the profile exceeding the deadline")
+	}
+	// The context of the abandoned cycle is cancelled, and the cycle completes without running
+	// its post-cycle plugins.
+	<-filter.cancelled
+	time.Sleep(50 * time.Millisecond)
+	if got := postCycle.calls.Load(); got != 0 {
+		t.Errorf("Got %d post-cycle calls, want none for an abandoned cycle", got)
+	}
+}
+
+func TestPostSchedule(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}},
+	}
+	pinPod2 := &testPostSchedule{rewrite: func(ctx *types.SchedulingContext, results map[string]*types.Result) error {
+		for _, pod := range ctx.PodsSnapshot {
+			if pod.GetPod().NamespacedName.Name == "pod2" {
+				results["default"].TargetPod = pod
+			}
+		}
+		return nil
+	}}
+	veto := &testPostSchedule{rewrite: func(*types.SchedulingContext, map[string]*types.Result) error {
+		return errors.New("policy check failed")
+	}}
+
+	tests := []struct {
+		name          string
+		plugins       []framework.PostSchedule
+		wantTargetPod string
+		wantErr       bool
+	}{
+		{
+			name:          "no plugins",
+			wantTargetPod: "pod1",
+		},
+		{
+			name:          "results rewritten",
+			plugins:       []framework.PostSchedule{pinPod2},
+			wantTargetPod: "pod2",
+		},
+		{
+			name:    "results vetoed",
+			plugins: []framework.PostSchedule{pinPod2, veto},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile := framework.NewSchedulerProfile().
+				WithFilters(&testPodFilter{name: "pod1"}).
+				WithPicker(picker.NewMaxScorePicker())
+			schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{"default": profile}).
+				WithPostSchedulePlugins(test.plugins...)
+			scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+
+			got, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()})
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got results %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if name := got["default"].TargetPod.GetPod().NamespacedName.Name; name != test.wantTargetPod {
+				t.Errorf("Got target pod %s, expected %s", name, test.wantTargetPod)
+			}
+		})
+	}
+}
+
+func TestClassifiers(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+	}
+	classifiers := []framework.Classifier{
+		&testClassifier{name: "first", labels: map[string]string{"size": "small", "tenant": "a"}},
+		&testClassifier{name: "second", labels: map[string]string{"size": "large", "route": ""}},
+	}
+	var seenSize string
+	recordSize := &testPostSchedule{rewrite: func(ctx *types.SchedulingContext, _ map[string]*types.Result) error {
+		seenSize = ctx.Label("size")
+		return nil
+	}}
+	profile := framework.NewSchedulerProfile().WithPicker(picker.NewMaxScorePicker())
+	schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{"default": profile}).
+		WithPostSchedulePlugins(recordSize).
+		WithClassifiers(classifiers...)
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+
+	req := &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString(), Labels: map[string]string{"route": "r1"}}
+	if _, err := scheduler.Schedule(context.Background(), req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The later classifiers win, and an empty value removes the label.
+	wantLabels := map[string]string{"size": "large", "tenant": "a"}
+	if diff := cmp.Diff(wantLabels, req.Labels); diff != "" {
+		t.Errorf("Unexpected labels (-want +got): %s", diff)
+	}
+	if seenSize != "large" {
+		t.Errorf("Got size label %q in the plugins, expected large", seenSize)
+	}
+}
+
+func TestScheduleSLOBurningProfile(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+	}
+	profiles := map[string]*framework.SchedulerProfile{
+		"default": framework.NewSchedulerProfile().WithPicker(picker.NewMaxScorePicker()),
+		"strict":  framework.NewSchedulerProfile().WithPicker(picker.NewMaxScorePicker()),
+		"route":   framework.NewSchedulerProfile().WithPicker(picker.NewMaxScorePicker()),
+	}
+	schedulerConfig := NewSchedulerConfig(profilepicker.NewSingleProfilePicker("default"), profiles).WithSLOBurningProfile("strict")
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+
+	tests := []struct {
+		name        string
+		req         *types.LLMRequest
+		wantProfile string
+	}{
+		{
+			name:        "SLO not burning",
+			req:         &types.LLMRequest{TargetModel: "model"},
+			wantProfile: "default",
+		},
+		{
+			name:        "SLO burning fast",
+			req:         &types.LLMRequest{TargetModel: "model", SLOBurningFast: true},
+			wantProfile: "strict",
+		},
+		{
+			name:        "profile of the route",
+			req:         &types.LLMRequest{TargetModel: "model", SLOBurningFast: true, SchedulingProfile: "route"},
+			wantProfile: "route",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.req.RequestId = uuid.NewString()
+			results, err := scheduler.Schedule(context.Background(), test.req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, ok := results[test.wantProfile]; !ok || len(results) != 1 {
+				t.Errorf("Got the results of %v, expected the ones of %s", slices.Collect(maps.Keys(results)), test.wantProfile)
+			}
+		})
+	}
+}
+
+func TestScheduleEnvironment(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{}
+	for i := range 10 {
+		pods = append(pods, &backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: fmt.Sprintf("pod%d", i)}}})
+	}
+	// The picks of schedulers with identically seeded environments are the same.
+	picks := func(seed int64) []string {
+		profile := framework.NewSchedulerProfile().WithPicker(picker.NewRandomPicker()).WithCandidateSampling(5)
+		schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{"default": profile}).
+			WithEnvironment(framework.Environment{Clock: clock.RealClock{}, Rand: framework.NewRand(seed)})
+		scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+		picked := []string{}
+		for range 20 {
+			results, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			picked = append(picked, results["default"].TargetPod.GetPod().NamespacedName.Name)
+		}
+		return picked
+	}
+	if diff := cmp.Diff(picks(42), picks(42)); diff != "" {
+		t.Errorf("Unexpected picks with the same seed (-first +second): %s", diff)
+	}
+}
+
+func TestScheduleProfileResults(t *testing.T) {
+	const groupLabel = "kv-transfer-group"
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "prefill-b"}, Role: backend.PodRolePrefill, Labels: map[string]string{groupLabel: "b"}}},
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "decode-a"}, Role: backend.PodRoleDecode, Labels: map[string]string{groupLabel: "a"}}},
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "decode-b"}, Role: backend.PodRoleDecode, Labels: map[string]string{groupLabel: "b"}}},
+	}
+	profiles := map[string]*framework.SchedulerProfile{
+		"prefill": framework.NewSchedulerProfile().
+			WithFilters(filter.NewRoleFilter(backend.PodRolePrefill)).
+			WithPicker(picker.NewRandomPicker()),
+		"decode": framework.NewSchedulerProfile().
+			WithFilters(filter.NewRoleFilter(backend.PodRoleDecode), filter.NewPrefillAffinityFilter("prefill", groupLabel, true)).
+			WithPicker(picker.NewRandomPicker()),
+	}
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, NewSchedulerConfig(profilepicker.NewPrefillDecodeProfilePicker("", ""), profiles))
+
+	for range 10 {
+		got, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if name := got["prefill"].TargetPod.GetPod().NamespacedName.Name; name != "prefill-b" {
+			t.Errorf("Got prefill pod %s, expected prefill-b", name)
+		}
+		if name := got["decode"].TargetPod.GetPod().NamespacedName.Name; name != "decode-b" {
+			t.Errorf("Got decode pod %s, expected decode-b in the group of the prefill pod", name)
+		}
+	}
+}
+
+func TestScheduleProfileFailurePolicy(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+	}
+	failing := func(policy framework.ProfileFailurePolicy, fallback string) *framework.SchedulerProfile {
+		return framework.NewSchedulerProfile().
+			WithFilters(&testPodFilter{name: "no-such-pod"}).
+			WithPicker(picker.NewMaxScorePicker()).
+			WithFailurePolicy(policy, fallback)
+	}
+	succeeding := func() *framework.SchedulerProfile {
+		return framework.NewSchedulerProfile().WithPicker(picker.NewMaxScorePicker())
+	}
+
+	tests := []struct {
+		name        string
+		profiles    map[string]*framework.SchedulerProfile
+		wantResults []string
+		wantErr     bool
+	}{
+		{
+			name:     "required profile fails",
+			profiles: map[string]*framework.SchedulerProfile{"a": failing("", ""), "b": succeeding()},
+			wantErr:  true,
+		},
+		{
+			name:        "required profile falls back",
+			profiles:    map[string]*framework.SchedulerProfile{"a": failing(framework.ProfileRequired, "b"), "b": succeeding()},
+			wantResults: []string{"a", "b"},
+		},
+		{
+			name:     "fallback profile fails too",
+			profiles: map[string]*framework.SchedulerProfile{"a": failing(framework.ProfileRequired, "c"), "b": succeeding(), "c": failing(framework.ProfileOptional, "")},
+			wantErr:  true,
+		},
+		{
+			name:        "optional profile skipped",
+			profiles:    map[string]*framework.SchedulerProfile{"a": failing(framework.ProfileOptional, ""), "b": succeeding()},
+			wantResults: []string{"b"},
+		},
+		{
+			name:     "only optional profiles failed",
+			profiles: map[string]*framework.SchedulerProfile{"a": failing(framework.ProfileOptional, "")},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), test.profiles))
+			got, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()})
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got results %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			gotResults := []string{}
+			for name, result := range got {
+				gotResults = append(gotResults, name)
+				if name := result.TargetPod.GetPod().NamespacedName.Name; name != "pod1" {
+					t.Errorf("Got target pod %s, expected pod1", name)
+				}
+			}
+			slices.Sort(gotResults)
+			if diff := cmp.Diff(test.wantResults, gotResults); diff != "" {
+				t.Errorf("Unexpected results (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+// testPostSchedule is a PostSchedule plugin running the given function.
+type testPostSchedule struct {
+	rewrite func(ctx *types.SchedulingContext, results map[string]*types.Result) error
+}
+
+func (tp *testPostSchedule) Name() string { return "test-post-schedule" }
+
+func (tp *testPostSchedule) PostSchedule(ctx *types.SchedulingContext, results map[string]*types.Result) error {
+	return tp.rewrite(ctx, results)
+}
+
+// testClassifier is a classifier returning the given labels.
+type testClassifier struct {
+	name   string
+	labels map[string]string
+}
+
+func (tc *testClassifier) Name() string { return tc.name }
+
+func (tc *testClassifier) Classify(*types.SchedulingContext) map[string]string { return tc.labels }
+
+// testPodFilter is a filter keeping the pod of the given name.
+type testPodFilter struct {
+	name string
+}
+
+func (tf *testPodFilter) Name() string { return "test-pod-filter" }
+
+func (tf *testPodFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	res := []types.Pod{}
+	for _, pod := range pods {
+		if pod.GetPod().NamespacedName.Name == tf.name {
+			res = append(res, pod)
+		}
+	}
+	return res
+}
+
 type fakeDataStore struct {
 	pods []*backendmetrics.FakePodMetrics
 }
@@ -329,3 +807,181 @@ func (pr *testPostResponse) PostResponse(ctx *types.SchedulingContext, pod types
 		ctx.Resp.Headers[key] = value
 	}
 }
+
+type testPostResponseComplete struct {
+	onHeaders bool
+	resp      *types.LLMResponse
+	pod       types.Pod
+}
+
+func (pr *testPostResponseComplete) Name() string { return "complete" }
+
+func (pr *testPostResponseComplete) PostResponse(*types.SchedulingContext, types.Pod) {
+	pr.onHeaders = true
+}
+
+func (pr *testPostResponseComplete) PostResponseComplete(ctx *types.SchedulingContext, pod types.Pod) {
+	pr.resp = ctx.Resp
+	pr.pod = pod
+}
+
+type testStateful struct {
+	name      string
+	state     string
+	importErr error
+}
+
+func (s *testStateful) Name() string { return s.name }
+
+func (s *testStateful) PostResponse(_ *types.SchedulingContext, _ types.Pod) {}
+
+func (s *testStateful) ExportState() (json.RawMessage, error) {
+	return json.RawMessage(s.state), nil
+}
+
+func (s *testStateful) ImportState(state json.RawMessage) error {
+	if s.importErr != nil {
+		return s.importErr
+	}
+	s.state = string(state)
+	return nil
+}
+
+// testSlowFilter keeps all the pods once the context of the cycle is done.
+type testSlowFilter struct {
+	cancelled chan struct{}
+}
+
+func (f *testSlowFilter) Name() string { return "test-slow" }
+
+func (f *testSlowFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	<-ctx.Done()
+	close(f.cancelled)
+	return pods
+}
+
+type testPostCycle struct {
+	calls atomic.Int32
+}
+
+func (p *testPostCycle) Name() string { return "test-post-cycle" }
+
+func (p *testPostCycle) PostCycle(_ *types.SchedulingContext, _ *types.Result) {
+	p.calls.Add(1)
+}
+
+// newBarrier returns a barrier released once the given number of parties arrived.
+func newBarrier(parties int) *sync.WaitGroup {
+	barrier := &sync.WaitGroup{}
+	barrier.Add(parties)
+	return barrier
+}
+
+type testBarrierFilter struct {
+	barrier *sync.WaitGroup
+}
+
+func (f *testBarrierFilter) Name() string { return "test-barrier" }
+
+func (f *testBarrierFilter) Filter(ctx *types.SchedulingContext, pods []types.Pod) []types.Pod {
+	f.barrier.Done()
+	released := make(chan struct{})
+	go func() {
+		f.barrier.Wait()
+		close(released)
+	}()
+	select {
+	case <-released:
+		return pods
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func TestScheduleProfileDecisions(t *testing.T) {
+	pods := []*backendmetrics.FakePodMetrics{
+		{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}},
+	}
+	profiles := map[string]*framework.SchedulerProfile{
+		"decisions-filtered": framework.NewSchedulerProfile().
+			WithFilters(&testPodFilter{name: "no-such-pod"}).
+			WithPicker(picker.NewMaxScorePicker()).
+			WithFailurePolicy(framework.ProfileOptional, ""),
+		"decisions-scheduled": framework.NewSchedulerProfile().WithPicker(picker.NewMaxScorePicker()),
+	}
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), profiles))
+
+	if _, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString()}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: "model", RequestId: uuid.NewString(), Critical: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[[2]string]float64{
+		{"decisions-filtered", "shed"}:       1,
+		{"decisions-filtered", "failed"}:     1,
+		{"decisions-scheduled", "scheduled"}: 2,
+	}
+	for labels, count := range want {
+		if got := testutil.ToFloat64(metrics.SchedulerProfileDecisions.WithLabelValues(labels[0], labels[1])); got != count {
+			t.Errorf("Unexpected decisions of profile %s with outcome %s, want %v, got %v", labels[0], labels[1], count, got)
+		}
+	}
+}
+
+func TestSnapshotGenerations(t *testing.T) {
+	pod1 := &backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, Metrics: &backendmetrics.MetricsState{}}
+	pod2 := &backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}}, Metrics: &backendmetrics.MetricsState{}}
+
+	var generations snapshotGenerations
+	first := generations.of([]backendmetrics.PodMetrics{pod1, pod2})
+	if first == 0 {
+		t.Fatalf("Got the zero generation for a known snapshot")
+	}
+	if got := generations.of([]backendmetrics.PodMetrics{pod2, pod1}); got != first {
+		t.Errorf("Got generation %d for the same pods in another order, expected %d", got, first)
+	}
+	pod2.Metrics = &backendmetrics.MetricsState{WaitingQueueSize: 1}
+	second := generations.of([]backendmetrics.PodMetrics{pod1, pod2})
+	if second == first {
+		t.Errorf("Got the same generation after the metrics of a pod were refreshed")
+	}
+	if got := generations.of([]backendmetrics.PodMetrics{pod1}); got == second {
+		t.Errorf("Got the same generation after a pod was removed")
+	}
+}
+
+type fakeSnapshotDataStore struct {
+	fakeDataStore
+	snapshot *backendmetrics.PodSnapshot
+}
+
+func (fds *fakeSnapshotDataStore) PodSnapshot() *backendmetrics.PodSnapshot {
+	return fds.snapshot
+}
+
+func TestPodsSnapshot(t *testing.T) {
+	pods := []backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod1"}}, Metrics: &backendmetrics.MetricsState{}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: "pod2"}, Cordoned: true}, Metrics: &backendmetrics.MetricsState{}},
+	}
+	ds := &fakeSnapshotDataStore{snapshot: backendmetrics.NewPodSnapshot(pods)}
+	scheduler := NewScheduler(ds)
+
+	first := scheduler.podsSnapshot()
+	if first.generation != ds.snapshot.Generation {
+		t.Errorf("Got generation %d, expected the generation of the datastore snapshot %d", first.generation, ds.snapshot.Generation)
+	}
+	if len(first.all) != 2 || len(first.uncordoned) != 1 || first.uncordoned[0].GetPod().NamespacedName.Name != "pod1" {
+		t.Errorf("Unexpected pods in the snapshot, all: %v, uncordoned: %v", first.all, first.uncordoned)
+	}
+	if got := scheduler.podsSnapshot(); got != first {
+		t.Errorf("Expected the snapshot to be reused while the datastore snapshot is the same")
+	}
+
+	ds.snapshot = backendmetrics.NewPodSnapshot(pods[:1])
+	if got := scheduler.podsSnapshot(); got == first || len(got.all) != 1 {
+		t.Errorf("Expected a new snapshot after the datastore snapshot changed, got %v", got.all)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// DefaultModelFamilyLabel is the default pod label giving the model family a model server pod
+// serves.
+const DefaultModelFamilyLabel = "inference.networking.x-k8s.io/model-family"
+
+// ModelFamilyShardingConfig configures the static partitioning of the pods of a mixed pool by model
+// family. The pods are grouped by the value of their Label, and the requests for the models of a
+// family are only scheduled on the pods of this family, so the filters only go through these pods
+// and the requests are never routed to the pods of another family. The requests for the models of
+// no family are scheduled on all the pods.
+// The pods are grouped once per snapshot of the pods, i.e. after the pods changed.
+type ModelFamilyShardingConfig struct {
+	// Label is the pod label giving the family of the pods. Defaults to DefaultModelFamilyLabel.
+	Label string
+	// Families are the prefixes of the names of the models of every family, keyed by family name.
+	// The family of a model is the one of its longest matching prefix.
+	Families map[string][]string
+}
+
+// ParseModelFamilies parses model families in the format
+// "<family>=<model prefix>|<model prefix>,...", e.g. "llama=meta-llama/|llama,qwen=Qwen/".
+func ParseModelFamilies(value string) (map[string][]string, error) {
+	families := map[string][]string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, prefixes, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid model family '%s', expected <family>=<model prefix>|...", entry)
+		}
+		for _, prefix := range strings.Split(prefixes, "|") {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				families[name] = append(families[name], prefix)
+			}
+		}
+		if len(families[name]) == 0 {
+			return nil, fmt.Errorf("model family '%s' has no model prefix", name)
+		}
+	}
+	return families, nil
+}
+
+// modelFamilySharding resolves the model families of the requests and the pods.
+type modelFamilySharding struct {
+	label string
+	// prefixes are sorted by decreasing length, so the first match is the longest.
+	prefixes []familyPrefix
+}
+
+type familyPrefix struct {
+	prefix string
+	family string
+}
+
+func newModelFamilySharding(config ModelFamilyShardingConfig) *modelFamilySharding {
+	if config.Label == "" {
+		config.Label = DefaultModelFamilyLabel
+	}
+	sharding := &modelFamilySharding{label: config.Label}
+	for family, prefixes := range config.Families {
+		for _, prefix := range prefixes {
+			sharding.prefixes = append(sharding.prefixes, familyPrefix{prefix: prefix, family: family})
+		}
+	}
+	slices.SortFunc(sharding.prefixes, func(a, b familyPrefix) int {
+		return cmp.Or(cmp.Compare(len(b.prefix), len(a.prefix)), cmp.Compare(a.prefix, b.prefix))
+	})
+	return sharding
+}
+
+// family returns the family of the given model, empty if it's of no family or the sharding is
+// disabled.
+func (m *modelFamilySharding) family(model string) string {
+	if m == nil {
+		return ""
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(model, prefix.prefix) {
+			return prefix.family
+		}
+	}
+	return ""
+}
+
+// partition groups the given pods by family. The pods without family are left out.
+func (m *modelFamilySharding) partition(pods []types.Pod) map[string][]types.Pod {
+	if m == nil {
+		return nil
+	}
+	families := map[string][]types.Pod{}
+	for _, pod := range pods {
+		if family := pod.GetPod().Labels[m.label]; family != "" {
+			families[family] = append(families[family], pod)
+		}
+	}
+	return families
+}
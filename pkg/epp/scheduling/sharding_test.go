@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/picker"
+	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestParseModelFamilies(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string][]string
+		wantErr bool
+	}{
+		{
+			name:  "families",
+			value: "llama=meta-llama/|llama, qwen=Qwen/,",
+			want:  map[string][]string{"llama": {"meta-llama/", "llama"}, "qwen": {"Qwen/"}},
+		},
+		{
+			name:  "empty",
+			value: "",
+			want:  map[string][]string{},
+		},
+		{
+			name:    "missing prefixes",
+			value:   "llama=",
+			wantErr: true,
+		},
+		{
+			name:    "missing family",
+			value:   "meta-llama/",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseModelFamilies(test.value)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected families (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestModelFamilySharding(t *testing.T) {
+	pod := func(name, family string) *backendmetrics.FakePodMetrics {
+		labels := map[string]string{}
+		if family != "" {
+			labels[DefaultModelFamilyLabel] = family
+		}
+		return &backendmetrics.FakePodMetrics{
+			Pod:     &backend.Pod{NamespacedName: k8stypes.NamespacedName{Name: name}, Labels: labels},
+			Metrics: &backendmetrics.MetricsState{},
+		}
+	}
+	pods := []*backendmetrics.FakePodMetrics{pod("llama-1", "llama"), pod("llama-2", "llama"), pod("qwen-1", "qwen"), pod("other", "")}
+	profile := framework.NewSchedulerProfile().WithPicker(picker.NewRandomPicker())
+	schedulerConfig := NewSchedulerConfig(profilepicker.NewAllProfilesPicker(), map[string]*framework.SchedulerProfile{"default": profile}).
+		WithModelFamilySharding(ModelFamilyShardingConfig{Families: map[string][]string{
+			"llama":    {"meta-llama/"},
+			"llama-70": {"meta-llama/Llama-3.1-70B"},
+			"qwen":     {"Qwen/"},
+		}})
+	scheduler := NewSchedulerWithConfig(&fakeDataStore{pods: pods}, schedulerConfig)
+
+	tests := []struct {
+		model    string
+		wantPods map[string]bool
+		wantErr  bool
+	}{
+		{model: "meta-llama/Llama-3.1-8B", wantPods: map[string]bool{"llama-1": true, "llama-2": true}},
+		{model: "Qwen/Qwen2.5-7B", wantPods: map[string]bool{"qwen-1": true}},
+		{model: "mistral", wantPods: map[string]bool{"llama-1": true, "llama-2": true, "qwen-1": true, "other": true}},
+		// The longest prefix wins, and the family has no pods.
+		{model: "meta-llama/Llama-3.1-70B-Instruct", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.model, func(t *testing.T) {
+			for range 20 {
+				got, err := scheduler.Schedule(context.Background(), &types.LLMRequest{TargetModel: test.model, RequestId: uuid.NewString()})
+				if test.wantErr {
+					if err == nil {
+						t.Fatalf("Expected an error, got %v", got)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if name := got["default"].TargetPod.GetPod().NamespacedName.Name; !test.wantPods[name] {
+					t.Errorf("Got target pod %s, expected one of %v", name, test.wantPods)
+				}
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -35,6 +36,33 @@ type StateData interface {
 // StateKey is the type of keys stored in CycleState.
 type StateKey string
 
+// TypedStateKey is a key of the data of type T stored in CycleState, so that the plugins sharing the
+// data read and write it without asserting its type. For instance, a filter can share its results
+// with a scorer under a package-level key:
+//
+//	var matchLengthsKey = types.TypedStateKey[matchLengths]("prefix-match-lengths")
+type TypedStateKey[T StateData] StateKey
+
+// Read retrieves the data of the key from the given CycleState. If the key is not present,
+// ErrNotFound is returned, and an error if the data stored under the key isn't of type T.
+func (k TypedStateKey[T]) Read(c *CycleState) (T, error) {
+	var zero T
+	data, err := c.Read(StateKey(k))
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := data.(T)
+	if !ok {
+		return zero, fmt.Errorf("the state %q is of type %T, not %T", k, data, zero)
+	}
+	return typed, nil
+}
+
+// Write stores the given data in the given CycleState under the key.
+func (k TypedStateKey[T]) Write(c *CycleState, val T) {
+	c.Write(StateKey(k), val)
+}
+
 // NewCycleState initializes a new CycleState and returns its pointer.
 func NewCycleState() *CycleState {
 	return &CycleState{}
@@ -48,8 +76,15 @@ func NewCycleState() *CycleState {
 type CycleState struct {
 	// key: StateKey, value: StateData
 	storage sync.Map
+	// parent is the CycleState a staged CycleState reads through, nil otherwise.
+	parent *CycleState
 }
 
+// deleted marks the keys deleted from a staged CycleState, which are still present in its parent.
+type deleted struct{}
+
+func (deleted) Clone() StateData { return deleted{} }
+
 // Clone creates a copy of CycleState and returns its pointer. Clone returns
 // nil if the context being cloned is nil.
 func (c *CycleState) Clone() *CycleState {
@@ -57,23 +92,59 @@ func (c *CycleState) Clone() *CycleState {
 		return nil
 	}
 	copy := NewCycleState()
+	if c.parent != nil {
+		copy = c.parent.Clone()
+	}
 	// Safe copy storage in case of overwriting.
 	c.storage.Range(func(k, v interface{}) bool {
-		copy.storage.Store(k, v.(StateData).Clone())
+		if _, ok := v.(deleted); ok {
+			copy.storage.Delete(k)
+		} else {
+			copy.storage.Store(k, v.(StateData).Clone())
+		}
 		return true
 	})
 
 	return copy
 }
 
+// Stage returns a CycleState reading through this one, whose writes and deletes are only applied
+// to this one by Commit. It lets a plugin that may be abandoned run on the state of the cycle
+// without its late writes reaching it.
+func (c *CycleState) Stage() *CycleState {
+	return &CycleState{parent: c}
+}
+
+// Commit applies the writes and deletes of a staged CycleState to the CycleState it was staged
+// from. It does nothing if the CycleState isn't staged.
+func (c *CycleState) Commit() {
+	if c.parent == nil {
+		return
+	}
+	c.storage.Range(func(k, v interface{}) bool {
+		if _, ok := v.(deleted); ok {
+			c.parent.Delete(k.(StateKey))
+		} else {
+			c.parent.Write(k.(StateKey), v.(StateData))
+		}
+		return true
+	})
+}
+
 // Read retrieves data with the given "key" from CycleState. If the key is not
 // present, ErrNotFound is returned.
 //
 // See CycleState for notes on concurrency.
 func (c *CycleState) Read(key StateKey) (StateData, error) {
 	if v, ok := c.storage.Load(key); ok {
+		if _, ok := v.(deleted); ok {
+			return nil, ErrNotFound
+		}
 		return v.(StateData), nil
 	}
+	if c.parent != nil {
+		return c.parent.Read(key)
+	}
 	return nil, ErrNotFound
 }
 
@@ -88,5 +159,9 @@ func (c *CycleState) Write(key StateKey, val StateData) {
 //
 // See CycleState for notes on concurrency.
 func (c *CycleState) Delete(key StateKey) {
+	if c.parent != nil {
+		c.storage.Store(key, deleted{})
+		return
+	}
 	c.storage.Delete(key)
 }
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCounter int
+
+func (c testCounter) Clone() StateData { return c }
+
+type testLengths map[string]int
+
+func (l testLengths) Clone() StateData {
+	clone := make(testLengths, len(l))
+	for k, v := range l {
+		clone[k] = v
+	}
+	return clone
+}
+
+func TestTypedStateKey(t *testing.T) {
+	counterKey := TypedStateKey[testCounter]("counter")
+	lengthsKey := TypedStateKey[testLengths]("lengths")
+	state := NewCycleState()
+
+	if _, err := counterKey.Read(state); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Got error %v reading a missing key, want %v", err, ErrNotFound)
+	}
+
+	counterKey.Write(state, 3)
+	if counter, err := counterKey.Read(state); err != nil || counter != 3 {
+		t.Errorf("Got %v, %v, want 3", counter, err)
+	}
+
+	// The data written under the untyped key of another type isn't returned.
+	state.Write("lengths", testCounter(1))
+	if _, err := lengthsKey.Read(state); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Got error %v reading data of another type, want a type error", err)
+	}
+
+	// The clones don't share the data.
+	lengthsKey.Write(state, testLengths{"pod1": 2})
+	clone := state.Clone()
+	lengths, err := lengthsKey.Read(clone)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the clone: %v", err)
+	}
+	lengths["pod1"] = 5
+	if lengths, _ := lengthsKey.Read(state); lengths["pod1"] != 2 {
+		t.Errorf("Got length %d after changing the clone, want 2", lengths["pod1"])
+	}
+}
+
+func TestCycleStateStage(t *testing.T) {
+	state := NewCycleState()
+	state.Write("kept", testCounter(1))
+	state.Write("deleted", testCounter(2))
+
+	staged := state.Stage()
+	staged.Write("written", testCounter(3))
+	staged.Delete("deleted")
+	if v, err := staged.Read("kept"); err != nil || v != testCounter(1) {
+		t.Errorf("Got %v, %v reading through the staged state, want 1", v, err)
+	}
+	if _, err := staged.Read("deleted"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Got error %v reading a key deleted from the staged state, want %v", err, ErrNotFound)
+	}
+	// The writes and deletes aren't applied until committed.
+	if _, err := state.Read("written"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Got error %v reading an uncommitted write, want %v", err, ErrNotFound)
+	}
+	if _, err := state.Read("deleted"); err != nil {
+		t.Errorf("Unexpected error reading an uncommitted delete: %v", err)
+	}
+
+	staged.Commit()
+	if v, err := state.Read("written"); err != nil || v != testCounter(3) {
+		t.Errorf("Got %v, %v reading a committed write, want 3", v, err)
+	}
+	if _, err := state.Read("deleted"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Got error %v reading a committed delete, want %v", err, ErrNotFound)
+	}
+}
@@ -42,7 +42,27 @@ type SchedulingContext struct {
 	Req          *LLMRequest
 	Resp         *LLMResponse
 	PodsSnapshot []Pod
+	// SnapshotGeneration identifies the state of the pods the PodsSnapshot was taken from: the
+	// snapshots of the same generation hold the same pods with the same metrics. Zero if unknown.
+	SnapshotGeneration uint64
 	// CycleState can be used by plugins to store state during a scheduling cycle, to communicate
 	// between different extension points.
 	CycleState *CycleState
+	// ProfileResults holds the results of the profiles that ran before the current ones for the
+	// request, keyed by profile name, so the plugins of a profile can depend on the decisions of the
+	// previous ones (e.g. a decode profile on the prefill pod). Empty while running the first
+	// profiles picked, and must not be modified. The optional profiles that failed have a Result
+	// without a TargetPod.
+	ProfileResults map[string]*Result
+	// ProfileName is the name of the profile whose cycle is running, empty outside of the profile
+	// cycles.
+	ProfileName string
+}
+
+// Label returns the value of the given label of the request, empty if the request doesn't have it.
+func (c *SchedulingContext) Label(key string) string {
+	if c.Req == nil {
+		return ""
+	}
+	return c.Req.Labels[key]
 }
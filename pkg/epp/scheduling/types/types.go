@@ -18,6 +18,7 @@ package types
 
 import (
 	"fmt"
+	"time"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
@@ -31,14 +32,50 @@ type LLMRequest struct {
 	RequestId string
 	// Critical is a boolean that specifies if a request is critical or not.
 	Critical bool
+	// Sheddable is true for the requests of the lowest criticality, shed first when the pool is
+	// saturated. The requests that are neither critical nor sheddable are of standard criticality.
+	Sheddable bool
 	// Prompt is the prompt that was sent in the request body.
 	Prompt string
 	// Headers is a map of the request headers.
 	Headers map[string]string
+	// SessionID identifies the conversation (e.g. an Assistants-style thread) the request belongs to.
+	// Empty when the request doesn't carry a session identifier.
+	SessionID string
+	// SLOBurningFast is true when the SLO error budget of the requested model is burning fast. The
+	// scheduler then tightens the routing of the request: it runs the SLO burning profile, if
+	// configured, and never reuses a recent scheduling decision.
+	SLOBurningFast bool
+	// SchedulingProfile is the name of the profile the request is scheduled with, in place of the
+	// profiles picked by the profile picker. Empty, or unknown to the scheduler, to use the picker.
+	SchedulingProfile string
+	// PromptTokens is the estimated number of tokens of the prompt, and EstimatedOutputTokens the
+	// number of tokens the request is expected to generate. They are estimated once per request
+	// (see the tokenestimate package), so all the plugins use the same numbers. Zero if unknown.
+	PromptTokens          int
+	EstimatedOutputTokens int
+	// Labels classify the request, e.g. by workload type or size class (see the Label* keys). They
+	// are set by the Classifier plugins before the profiles are picked, so the plugins, the metrics
+	// and the logs refer to the same classes instead of each inspecting the request.
+	Labels map[string]string
 }
 
+// The keys of the labels set by the built-in classifiers.
+const (
+	// LabelWorkloadType is the API of the request, e.g. "chat" or "completion".
+	LabelWorkloadType = "workload-type"
+	// LabelSizeClass is the class of the estimated number of tokens of the request: "small",
+	// "medium" or "large".
+	LabelSizeClass = "size-class"
+	// LabelTenant is the tenant the request was sent by.
+	LabelTenant = "tenant"
+	// LabelCriticality is the criticality of the request: "critical" or "non-critical".
+	LabelCriticality = "criticality"
+)
+
 func (r *LLMRequest) String() string {
-	return fmt.Sprintf("TargetModel: %s, Critical: %t, PromptLength: %d, Headers: %v", r.TargetModel, r.Critical, len(r.Prompt), r.Headers)
+	return fmt.Sprintf("TargetModel: %s, Critical: %t, Sheddable: %t, PromptLength: %d, PromptTokens: %d, EstimatedOutputTokens: %d, SessionID: %s, SLOBurningFast: %t, SchedulingProfile: %s, Labels: %v, Headers: %v",
+		r.TargetModel, r.Critical, r.Sheddable, len(r.Prompt), r.PromptTokens, r.EstimatedOutputTokens, r.SessionID, r.SLOBurningFast, r.SchedulingProfile, r.Labels, r.Headers)
 }
 
 // LLMResponse contains information from the response received to be passed to plugins
@@ -53,11 +90,40 @@ type LLMResponse struct {
 	IsStreaming bool
 	// EndOfStream when true indicates that this invocation contains the last chunk of the response
 	EndOfStream bool
+
+	// The fields below are only set once the response completed, see framework.PostResponseComplete.
+
+	// PromptTokens and CompletionTokens are the usage reported by the model server, zero if it
+	// reported none.
+	PromptTokens     int
+	CompletionTokens int
+	// FinishReason is why the model server finished generating the response, e.g. "stop" or
+	// "length". Empty if it reported none.
+	FinishReason string
+	// Latency is the time from the reception of the request to the completion of the response.
+	Latency time.Duration
+	// TimeToFirstToken is the time from the reception of the request to its first response chunk,
+	// zero if the response had no body.
+	TimeToFirstToken time.Duration
+	// Failed is true if the model server responded with an error.
+	Failed bool
+	// Text is the text generated in the first choice of the response, or its end if it's longer
+	// than the endpoint picker captures. TextCaptured is false if the text wasn't captured, e.g.
+	// because the endpoint picker doesn't capture it, in which case Text is empty.
+	Text         string
+	TextCaptured bool
 }
 
 type Pod interface {
 	GetPod() *backend.Pod
 	GetMetrics() *backendmetrics.MetricsState
+	// GetMetricsHistory returns the recent metrics of the pod, or nil if they aren't kept. Unlike
+	// the metrics, the history is not a snapshot, so it may hold samples newer than GetMetrics.
+	GetMetricsHistory() *backendmetrics.MetricsHistory
+	// GetRequestsInFlight returns the live count of the requests routed to the pod by the endpoint
+	// picker that didn't end yet, or nil if they aren't counted. Like the history, it's not a
+	// snapshot.
+	GetRequestsInFlight() *backendmetrics.RequestsInFlight
 	String() string
 }
 
@@ -81,15 +147,35 @@ func (pm *PodMetrics) GetMetrics() *backendmetrics.MetricsState {
 	return pm.MetricsState
 }
 
+func (pm *PodMetrics) GetMetricsHistory() *backendmetrics.MetricsHistory {
+	return pm.History
+}
+
+func (pm *PodMetrics) GetRequestsInFlight() *backendmetrics.RequestsInFlight {
+	return pm.InFlight
+}
+
 type PodMetrics struct {
 	*backend.Pod
 	*backendmetrics.MetricsState
+	History  *backendmetrics.MetricsHistory
+	InFlight *backendmetrics.RequestsInFlight
 }
 
 func ToSchedulerPodMetrics(pods []backendmetrics.PodMetrics) []Pod {
 	pm := make([]Pod, 0, len(pods))
 	for _, pod := range pods {
-		pm = append(pm, &PodMetrics{Pod: pod.GetPod().Clone(), MetricsState: pod.GetMetrics().Clone()})
+		pm = append(pm, &PodMetrics{Pod: pod.GetPod().Clone(), MetricsState: pod.GetMetrics().Clone(), History: pod.GetMetricsHistory(), InFlight: pod.GetRequestsInFlight()})
+	}
+	return pm
+}
+
+// FromPodSnapshot wraps the pods of a datastore snapshot for the scheduler. The pods and their
+// metrics are not copied, as the snapshot is immutable.
+func FromPodSnapshot(pods []backendmetrics.PodState) []Pod {
+	pm := make([]Pod, 0, len(pods))
+	for _, pod := range pods {
+		pm = append(pm, &PodMetrics{Pod: pod.Pod, MetricsState: pod.Metrics, History: pod.History, InFlight: pod.InFlight})
 	}
 	return pm
 }
@@ -97,4 +183,7 @@ func ToSchedulerPodMetrics(pods []backendmetrics.PodMetrics) []Pod {
 // Result captures the scheduler result.
 type Result struct {
 	TargetPod Pod
+	// FallbackPods are the pods to send the request to if the target pod can't be reached, in order
+	// of preference. Empty unless the picker returns more than one candidate.
+	FallbackPods []Pod
 }
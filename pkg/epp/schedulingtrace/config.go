@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtrace
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Environment variable names for the scheduling trace configuration
+const (
+	EnvEnabled = "ENABLE_SCHEDULING_TRACE"
+	EnvHeader  = "SCHEDULING_TRACE_HEADER"
+)
+
+// Config holds the configuration of the scheduling traces.
+type Config struct {
+	// Enabled records the scheduling trace of every request, and logs it at the debug verbosity.
+	Enabled bool
+	// Header stamps the summary of the scheduling trace of a request into the
+	// x-gateway-scheduling-trace-id header of its response.
+	Header bool
+}
+
+// NewDefaultConfig returns a Config populated with the default values, i.e. tracing disabled.
+func NewDefaultConfig() *Config {
+	return &Config{}
+}
+
+// LoadConfigFromEnv loads the scheduling trace Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("scheduling-trace-config")
+
+	cfg := &Config{
+		Enabled: envutil.GetEnvString(EnvEnabled, "false", logger) == "true",
+		Header:  envutil.GetEnvString(EnvHeader, "false", logger) == "true",
+	}
+	// The summary is taken from the trace.
+	cfg.Header = cfg.Header && cfg.Enabled
+
+	logger.Info("Scheduling trace configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
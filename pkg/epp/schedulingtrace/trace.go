@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedulingtrace records the decisions of the scheduling plugins for a request (the pods
+// each filter filtered out, the scores of each scorer and the pick of each profile) in a compact
+// trace, so operators can explain why a pod was chosen without reproducing the request.
+package schedulingtrace
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// Trace is the scheduling trace of a request. All its methods, and the methods of its
+// ProfileTraces, are safe to call on a nil Trace, which records nothing, so the call sites don't
+// depend on the tracing being enabled.
+type Trace struct {
+	// ID identifies the trace in the logs and the response headers. It is the ID of the request if
+	// it has one.
+	ID          string `json:"id"`
+	TargetModel string `json:"targetModel,omitempty"`
+	// ReusedDecision is set when the request reused a recent scheduling decision, in which case no
+	// profile ran.
+	ReusedDecision bool            `json:"reusedDecision,omitempty"`
+	Profiles       []*ProfileTrace `json:"profiles,omitempty"`
+	Error          string          `json:"error,omitempty"`
+
+	// mu guards the trace, as the profiles of a request run concurrently.
+	mu sync.Mutex
+}
+
+// ProfileTrace is the trace of a scheduler profile cycle.
+type ProfileTrace struct {
+	Profile string `json:"profile"`
+	// Candidates is the number of candidate pods of the cycle.
+	Candidates int              `json:"candidates"`
+	Filters    []FilterDecision `json:"filters,omitempty"`
+	// Scores are the scores of the pods by scorer and pod, before their normalization and weighting.
+	Scores  map[string]map[string]float64 `json:"scores,omitempty"`
+	Weights map[string]int                `json:"weights,omitempty"`
+	// CachedScores is set when the cycle reused the scores of an identical request.
+	CachedScores bool `json:"cachedScores,omitempty"`
+	// WeightedScores are the weighted scores of the pods the picker picked from, by pod.
+	WeightedScores map[string]float64 `json:"weightedScores,omitempty"`
+	Picker         string             `json:"picker,omitempty"`
+	TargetPod      string             `json:"targetPod,omitempty"`
+
+	trace *Trace
+}
+
+// FilterDecision is the decision of a filter.
+type FilterDecision struct {
+	Plugin string `json:"plugin"`
+	// FilteredOut are the pods filtered out.
+	FilteredOut []string `json:"filteredOut,omitempty"`
+	// Remaining is the number of pods passing the filter.
+	Remaining int `json:"remaining"`
+	// Cached is set when the result of the filter was cached from a previous request.
+	Cached bool `json:"cached,omitempty"`
+	// Skipped is set when the filter was skipped because the cycle exceeded its time budget.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// New returns a new Trace of the given ID.
+func New(id, targetModel string) *Trace {
+	return &Trace{ID: id, TargetModel: targetModel}
+}
+
+// Profile returns the trace of the cycle of the given profile, which is added if it's not traced
+// yet.
+func (t *Trace) Profile(name string) *ProfileTrace {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, profile := range t.Profiles {
+		if profile.Profile == name {
+			return profile
+		}
+	}
+	profile := &ProfileTrace{Profile: name, trace: t}
+	t.Profiles = append(t.Profiles, profile)
+	return profile
+}
+
+// ReuseDecision records that the request reused the given results of a recent scheduling
+// decision, by profile.
+func (t *Trace) ReuseDecision(results map[string]*types.Result) {
+	if t == nil {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		t.Profile(name).Pick("", nil, false, results[name])
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ReusedDecision = true
+}
+
+// Fail records the error that failed the scheduling of the request.
+func (t *Trace) Fail(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Error = err.Error()
+}
+
+// Summary returns a compact summary of the trace, fit for a header value: the ID of the trace,
+// followed by the pick of every profile, its weighted score and the number of pods filtered out,
+// e.g. "req-1;profile=default;pick=default/pod1;score=1.5;candidates=3;filtered=1".
+func (t *Trace) Summary() string {
+	if t == nil {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var sb strings.Builder
+	sb.WriteString(t.ID)
+	if t.ReusedDecision {
+		sb.WriteString(";reused")
+	}
+	for _, profile := range t.Profiles {
+		filtered := 0
+		for _, filter := range profile.Filters {
+			filtered += len(filter.FilteredOut)
+		}
+		fmt.Fprintf(&sb, ";profile=%s;pick=%s", profile.Profile, profile.TargetPod)
+		if score, ok := profile.WeightedScores[profile.TargetPod]; ok {
+			fmt.Fprintf(&sb, ";score=%s", strconv.FormatFloat(score, 'g', 4, 64))
+		}
+		fmt.Fprintf(&sb, ";candidates=%d;filtered=%d", profile.Candidates, filtered)
+	}
+	if t.Error != "" {
+		sb.WriteString(";failed")
+	}
+	return sb.String()
+}
+
+// Start records the candidate pods of the cycle.
+func (p *ProfileTrace) Start(candidates []types.Pod) {
+	if p == nil {
+		return
+	}
+	p.trace.mu.Lock()
+	defer p.trace.mu.Unlock()
+	p.Candidates = len(candidates)
+}
+
+// Filter records the decision of the given filter, given the pods it was passed and the pods it
+// returned.
+func (p *ProfileTrace) Filter(plugin string, candidates, pods []types.Pod, cached bool) {
+	if p == nil {
+		return
+	}
+	kept := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		kept[podName(pod)] = true
+	}
+	decision := FilterDecision{Plugin: plugin, Remaining: len(pods), Cached: cached}
+	for _, pod := range candidates {
+		if name := podName(pod); !kept[name] {
+			decision.FilteredOut = append(decision.FilteredOut, name)
+		}
+	}
+	slices.Sort(decision.FilteredOut)
+	p.trace.mu.Lock()
+	defer p.trace.mu.Unlock()
+	p.Filters = append(p.Filters, decision)
+}
+
+// SkipFilter records that the given filter was skipped.
+func (p *ProfileTrace) SkipFilter(plugin string, remaining int) {
+	if p == nil {
+		return
+	}
+	p.trace.mu.Lock()
+	defer p.trace.mu.Unlock()
+	p.Filters = append(p.Filters, FilterDecision{Plugin: plugin, Remaining: remaining, Skipped: true})
+}
+
+// Score records the scores of the given scorer.
+func (p *ProfileTrace) Score(scorer string, weight int, scores map[types.Pod]float64) {
+	if p == nil {
+		return
+	}
+	podScores := make(map[string]float64, len(scores))
+	for pod, score := range scores {
+		podScores[podName(pod)] = score
+	}
+	p.trace.mu.Lock()
+	defer p.trace.mu.Unlock()
+	if p.Scores == nil {
+		p.Scores = map[string]map[string]float64{}
+		p.Weights = map[string]int{}
+	}
+	p.Scores[scorer] = podScores
+	p.Weights[scorer] = weight
+}
+
+// Pick records the pick of the given picker among the pods of the given weighted scores, and
+// whether these scores were cached.
+func (p *ProfileTrace) Pick(picker string, weightedScores map[types.Pod]float64, cached bool, result *types.Result) {
+	if p == nil {
+		return
+	}
+	podScores := make(map[string]float64, len(weightedScores))
+	for pod, score := range weightedScores {
+		podScores[podName(pod)] = score
+	}
+	p.trace.mu.Lock()
+	defer p.trace.mu.Unlock()
+	p.Picker = picker
+	p.WeightedScores = podScores
+	p.CachedScores = cached
+	if result != nil && result.TargetPod != nil {
+		p.TargetPod = podName(result.TargetPod)
+	}
+}
+
+func podName(pod types.Pod) string {
+	return pod.GetPod().NamespacedName.String()
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the given context carrying the given Trace.
+func NewContext(ctx context.Context, t *Trace) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the Trace carried by the given context, nil if there is none.
+func FromContext(ctx context.Context) *Trace {
+	t, _ := ctx.Value(contextKey{}).(*Trace)
+	return t
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+func TestSummary(t *testing.T) {
+	pods := types.ToSchedulerPodMetrics([]backendmetrics.PodMetrics{
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod1"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod2"}}},
+		&backendmetrics.FakePodMetrics{Pod: &backend.Pod{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "pod3"}}},
+	})
+
+	tests := []struct {
+		name   string
+		record func(t *Trace)
+		want   string
+	}{
+		{
+			name:   "nothing recorded",
+			record: func(t *Trace) {},
+			want:   "req-1",
+		},
+		{
+			name: "scheduled",
+			record: func(t *Trace) {
+				profile := t.Profile("default")
+				profile.Start(pods)
+				profile.Filter("filter", pods, pods[:2], false)
+				profile.Score("scorer", 1, map[types.Pod]float64{pods[0]: 0.2, pods[1]: 0.8})
+				profile.Pick("picker", map[types.Pod]float64{pods[0]: 0.2, pods[1]: 0.8}, false, &types.Result{TargetPod: pods[1]})
+			},
+			want: "req-1;profile=default;pick=default/pod2;score=0.8;candidates=3;filtered=1",
+		},
+		{
+			name: "reused decision",
+			record: func(t *Trace) {
+				t.ReuseDecision(map[string]*types.Result{"default": {TargetPod: pods[0]}})
+			},
+			want: "req-1;reused;profile=default;pick=default/pod1;candidates=0;filtered=0",
+		},
+		{
+			name: "failed",
+			record: func(t *Trace) {
+				profile := t.Profile("default")
+				profile.Start(pods)
+				profile.Filter("filter", pods, nil, false)
+				t.Fail(errors.New("no pods available"))
+			},
+			want: "req-1;profile=default;pick=;candidates=3;filtered=3;failed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trace := New("req-1", "model")
+			test.record(trace)
+			if got := trace.Summary(); got != test.want {
+				t.Errorf("Summary() = %q, expected %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNilTrace(t *testing.T) {
+	// The call sites record into the trace of their context without checking it's enabled.
+	trace := FromContext(context.Background())
+	if trace != nil {
+		t.Fatalf("FromContext() = %v, expected nil", trace)
+	}
+	profile := trace.Profile("default")
+	profile.Start(nil)
+	profile.Filter("filter", nil, nil, false)
+	profile.SkipFilter("filter", 0)
+	profile.Score("scorer", 1, nil)
+	profile.Pick("picker", nil, false, nil)
+	trace.ReuseDecision(nil)
+	trace.Fail(errors.New("failed"))
+	if got := trace.Summary(); got != "" {
+		t.Errorf("Summary() = %q, expected empty", got)
+	}
+}
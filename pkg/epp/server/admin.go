@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	CordonPath        = "/admin/pods/cordon"
+	UncordonPath      = "/admin/pods/uncordon"
+	AdapterLoadedPath = "/admin/pods/adapter-loaded"
+)
+
+// NewAdminHandlers returns the admin HTTP handlers keyed by path, to be served next to the metrics
+// endpoint (and behind the same authentication and authorization).
+//
+// The cordon handlers accept POST requests with the pod given by the "name" query parameter, and
+// an optional "namespace" query parameter defaulting to the namespace of the pool, e.g.
+// POST /admin/pods/cordon?name=vllm-0. A cordoned pod is excluded from routing new requests
+// while it keeps serving the requests it already has.
+//
+// The adapter-loaded handler accepts POST requests with the pod given in the same way and the
+// adapter given by the "adapter" query parameter, e.g.
+// POST /admin/pods/adapter-loaded?name=vllm-0&adapter=sql-lora. It is meant to be called by
+// whatever loads LoRA adapters on the model servers as soon as the load returns, so the requests
+// for the adapter are routed to the pod before the next metrics refresh reports the adapter.
+func NewAdminHandlers(ds datastore.Datastore, poolNamespace string) map[string]http.Handler {
+	return map[string]http.Handler{
+		CordonPath:        cordonHandler(ds, poolNamespace, true),
+		UncordonPath:      cordonHandler(ds, poolNamespace, false),
+		AdapterLoadedPath: adapterLoadedHandler(ds, poolNamespace),
+	}
+}
+
+func cordonHandler(ds datastore.Datastore, poolNamespace string, cordoned bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespacedName, ok := podFromRequest(w, r, poolNamespace)
+		if !ok {
+			return
+		}
+		if !ds.PodSetCordoned(namespacedName, cordoned) {
+			http.Error(w, fmt.Sprintf("pod %s not found", namespacedName), http.StatusNotFound)
+			return
+		}
+		log.FromContext(r.Context()).V(logutil.DEFAULT).Info("Pod cordon state updated", "pod", namespacedName, "cordoned", cordoned)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func adapterLoadedHandler(ds datastore.Datastore, poolNamespace string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespacedName, ok := podFromRequest(w, r, poolNamespace)
+		if !ok {
+			return
+		}
+		adapter := r.URL.Query().Get("adapter")
+		if adapter == "" {
+			http.Error(w, "missing 'adapter' query parameter", http.StatusBadRequest)
+			return
+		}
+		if !ds.PodRecordAdapterLoaded(namespacedName, adapter) {
+			http.Error(w, fmt.Sprintf("pod %s not found", namespacedName), http.StatusNotFound)
+			return
+		}
+		log.FromContext(r.Context()).V(logutil.VERBOSE).Info("Adapter recorded as loaded", "pod", namespacedName, "adapter", adapter)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// podFromRequest validates an admin POST request and returns the pod it targets. It writes the
+// error response and returns false if the request is invalid.
+func podFromRequest(w http.ResponseWriter, r *http.Request, poolNamespace string) (types.NamespacedName, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return types.NamespacedName{}, false
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "missing 'name' query parameter", http.StatusBadRequest)
+		return types.NamespacedName{}, false
+	}
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = poolNamespace
+	}
+	return types.NamespacedName{Name: name, Namespace: namespace}, true
+}
@@ -42,7 +42,18 @@ func init() {
 }
 
 // defaultManagerOptions returns the default options used to create the manager.
-func defaultManagerOptions(namespacedName types.NamespacedName, metricsServerOptions metricsserver.Options) ctrl.Options {
+// If standbyPoolName is set, or if the pool name is empty as the Endpoint Picker serves several
+// pools, all the InferencePools of the namespace are cached, and the reconcilers are expected to
+// filter the pools they are responsible for.
+func defaultManagerOptions(namespacedName types.NamespacedName, standbyPoolName string, metricsServerOptions metricsserver.Options) ctrl.Options {
+	poolCacheConfig := cache.Config{
+		FieldSelector: fields.SelectorFromSet(fields.Set{
+			"metadata.name": namespacedName.Name,
+		}),
+	}
+	if standbyPoolName != "" || namespacedName.Name == "" {
+		poolCacheConfig = cache.Config{}
+	}
 	return ctrl.Options{
 		Scheme: scheme,
 		Cache: cache.Options{
@@ -54,11 +65,7 @@ func defaultManagerOptions(namespacedName types.NamespacedName, metricsServerOpt
 				},
 				&v1alpha2.InferencePool{}: {
 					Namespaces: map[string]cache.Config{
-						namespacedName.Namespace: {
-							FieldSelector: fields.SelectorFromSet(fields.Set{
-								"metadata.name": namespacedName.Name,
-							}),
-						},
+						namespacedName.Namespace: poolCacheConfig,
 					},
 				},
 				&v1alpha2.InferenceModel{}: {
@@ -66,6 +73,11 @@ func defaultManagerOptions(namespacedName types.NamespacedName, metricsServerOpt
 						namespacedName.Namespace: {},
 					},
 				},
+				&v1alpha2.InferenceRoutePolicy{}: {
+					Namespaces: map[string]cache.Config{
+						namespacedName.Namespace: {},
+					},
+				},
 			},
 		},
 		Metrics: metricsServerOptions,
@@ -74,7 +86,14 @@ func defaultManagerOptions(namespacedName types.NamespacedName, metricsServerOpt
 
 // NewDefaultManager creates a new controller manager with default configuration.
 func NewDefaultManager(namespacedName types.NamespacedName, restConfig *rest.Config, metricsServerOptions metricsserver.Options) (ctrl.Manager, error) {
-	manager, err := ctrl.NewManager(restConfig, defaultManagerOptions(namespacedName, metricsServerOptions))
+	return NewDefaultManagerWithStandbyPool(namespacedName, "", restConfig, metricsServerOptions)
+}
+
+// NewDefaultManagerWithStandbyPool creates a new controller manager with default configuration,
+// which also caches the given standby InferencePool. The standby pool is expected to be in the
+// namespace of the primary pool. An empty standbyPoolName is equivalent to NewDefaultManager.
+func NewDefaultManagerWithStandbyPool(namespacedName types.NamespacedName, standbyPoolName string, restConfig *rest.Config, metricsServerOptions metricsserver.Options) (ctrl.Manager, error) {
+	manager, err := ctrl.NewManager(restConfig, defaultManagerOptions(namespacedName, standbyPoolName, metricsServerOptions))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create controller manager: %v", err)
 	}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/capability"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/multipool"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/poolstatus"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+)
+
+// poolSetReporters runs the capability.Reporter and the poolstatus.Reporter of every pool of a
+// multipool.Set, starting them when a pool is added to the Set and stopping them once it's removed.
+type poolSetReporters struct {
+	client   client.Client
+	pools    *multipool.Set
+	interval time.Duration // how often the pools of the Set are synced
+
+	running map[*multipool.Pool]context.CancelFunc
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, as the reporters write the status
+// of the pools and their models.
+func (r *poolSetReporters) NeedLeaderElection() bool {
+	return true
+}
+
+// Start syncs the reporters with the pools of the Set every interval until the context is done.
+func (r *poolSetReporters) Start(ctx context.Context) error {
+	r.running = map[*multipool.Pool]context.CancelFunc{}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.sync(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync starts the reporters of the pools added to the Set, and stops the ones of the pools removed
+// from it.
+func (r *poolSetReporters) sync(ctx context.Context) error {
+	current := map[*multipool.Pool]bool{}
+	for _, pool := range r.pools.Pools() {
+		current[pool] = true
+		if _, ok := r.running[pool]; ok {
+			continue
+		}
+		logger := log.FromContext(ctx).WithValues("pool", pool.Name)
+		detector, err := saturationdetector.NewDetector(saturationdetector.LoadConfigFromEnv(), pool.Datastore, logger)
+		if err != nil {
+			return fmt.Errorf("failed creating the saturation detector of the pool status of %s: %w", pool.Name, err)
+		}
+		poolCtx, cancel := context.WithCancel(log.IntoContext(ctx, logger))
+		r.running[pool] = cancel
+		go func() {
+			_ = capability.NewReporter(r.client, pool.Datastore, capability.DefaultReportInterval).Start(poolCtx)
+		}()
+		go func() {
+			_ = poolstatus.NewReporter(r.client, pool.Datastore, detector, poolstatus.DefaultReportInterval).Start(poolCtx)
+		}()
+	}
+	for pool, cancel := range r.running {
+		if !current[pool] {
+			cancel()
+			delete(r.running, pool)
+		}
+	}
+	return nil
+}
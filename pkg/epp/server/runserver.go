@@ -19,6 +19,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,14 +29,26 @@ import (
 	"google.golang.org/grpc/credentials"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/gateway-api-inference-extension/internal/runnable"
 	tlsutil "sigs.k8s.io/gateway-api-inference-extension/internal/tls"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/accesslog"
 	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/capability"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/controller"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/datastore"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/failover"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/handlers"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/multipool"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/poolstatus"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/prewarm"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestcontrol"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/requestlookup"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/routepolicy"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/saturationdetector"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/throughput"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/timeline"
 )
 
 // ExtProcServerRunner provides methods to manage an external process server.
@@ -49,6 +62,44 @@ type ExtProcServerRunner struct {
 	CertPath                                 string
 	RefreshPrometheusMetricsInterval         time.Duration
 	Scheduler                                requestcontrol.Scheduler
+	DirectorConfig                           *requestcontrol.Config
+	// StandbyPoolNamespacedName and StandbyDatastore, if set, keep a standby InferencePool and its
+	// pods synced, so that Failover can route the traffic to it when the primary pool is unhealthy.
+	StandbyPoolNamespacedName types.NamespacedName
+	StandbyDatastore          datastore.Datastore
+	Failover                  *failover.Failover
+	// AccessLogIngester, if set, records the outcome of the requests whose responses didn't flow
+	// through ext-proc from the access-log records of the gateway.
+	AccessLogIngester *accesslog.Ingester
+	// Prewarmer, if set, warms up the pods joining the pool and hints them to the gateway.
+	Prewarmer *prewarm.Prewarmer
+	// TimelineRecorder, if set, records the timeline of the requests and retains those of the slow
+	// ones.
+	TimelineRecorder *timeline.Recorder
+	// RequestLookup, if set, retains the records of the recent requests so they can be looked up by
+	// request ID.
+	RequestLookup *requestlookup.Store
+	// ThroughputTracker, if set, tracks the output tokens per second of every gateway listener.
+	ThroughputTracker *throughput.Tracker
+	// RoutePolicies, if set, is kept in sync with the InferenceRoutePolicies of the namespace of the
+	// pool, which are applied to the requests of their routes.
+	RoutePolicies *routepolicy.Store
+	// ResponseTextCaptureLimit is the number of bytes of the text generated in the responses that
+	// is captured for the scheduler plugins analyzing it. Zero doesn't capture the text.
+	ResponseTextCaptureLimit int
+	// TrustGatewayHeaders makes the servers read the attributes of the requests identified by the
+	// gateway, e.g. their route or their pool, from the request headers when they are missing from
+	// the filter metadata. It must only be set if the gateway overwrites those headers.
+	TrustGatewayHeaders bool
+	// LoadReports makes the directors read the ORCA load reports of the responses into the metrics
+	// of the pods that served them.
+	LoadReports bool
+	// Pools, if set, are the InferencePools served instead of the pool of PoolNamespacedName: each
+	// pool has its own datastore and a scheduler created by NewPoolScheduler, and the requests are
+	// dispatched to the pool identified by the gateway (see multipool.Server). The standby pool, the
+	// access-log ingestion and the prewarming only apply to a single pool, and must not be set.
+	Pools            *multipool.Set
+	NewPoolScheduler func(ds datastore.Datastore) (requestcontrol.Scheduler, error)
 
 	// This should only be used in tests. We won't need this once we don't inject metrics in the tests.
 	// TODO:(https://github.com/kubernetes-sigs/gateway-api-inference-extension/issues/432) Cleanup
@@ -65,6 +116,7 @@ const (
 	DefaultRefreshMetricsInterval                   = 50 * time.Millisecond            // default for --refreshMetricsInterval
 	DefaultRefreshPrometheusMetricsInterval         = 5 * time.Second                  // default for --refreshPrometheusMetricsInterval
 	DefaultSecureServing                            = true                             // default for --secureServing
+	DefaultResponseTextCaptureLimit                 = 8192                             // default for --responseTextCaptureLimit with the response anomaly detection
 )
 
 func NewDefaultExtProcServerRunner() *ExtProcServerRunner {
@@ -75,17 +127,23 @@ func NewDefaultExtProcServerRunner() *ExtProcServerRunner {
 		PoolNamespacedName:                       types.NamespacedName{Name: DefaultPoolName, Namespace: DefaultPoolNamespace},
 		SecureServing:                            DefaultSecureServing,
 		RefreshPrometheusMetricsInterval:         DefaultRefreshPrometheusMetricsInterval,
+		DirectorConfig:                           requestcontrol.NewDefaultConfig(),
 		// Datastore can be assigned later.
 	}
 }
 
 // SetupWithManager sets up the runner with the given manager.
 func (r *ExtProcServerRunner) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if r.Pools != nil {
+		return r.setupPoolsWithManager(ctx, mgr)
+	}
+
 	// Create the controllers and register them with the manager
 	if err := (&controller.InferencePoolReconciler{
-		Datastore: r.Datastore,
-		Client:    mgr.GetClient(),
-		Record:    mgr.GetEventRecorderFor("InferencePool"),
+		Datastore:          r.Datastore,
+		Client:             mgr.GetClient(),
+		Record:             mgr.GetEventRecorderFor("InferencePool"),
+		PoolNamespacedName: r.PoolNamespacedName,
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("failed setting up InferencePoolReconciler: %w", err)
 	}
@@ -106,6 +164,105 @@ func (r *ExtProcServerRunner) SetupWithManager(ctx context.Context, mgr ctrl.Man
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("failed setting up PodReconciler: %v", err)
 	}
+
+	if r.StandbyDatastore != nil {
+		if err := (&controller.InferencePoolReconciler{
+			Datastore:          r.StandbyDatastore,
+			Client:             mgr.GetClient(),
+			Record:             mgr.GetEventRecorderFor("InferencePool"),
+			PoolNamespacedName: r.StandbyPoolNamespacedName,
+			ControllerName:     "standby-inferencepool",
+		}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed setting up standby InferencePoolReconciler: %w", err)
+		}
+
+		if err := (&controller.PodReconciler{
+			Datastore:      r.StandbyDatastore,
+			Client:         mgr.GetClient(),
+			Record:         mgr.GetEventRecorderFor("pod"),
+			ControllerName: "standby-pod",
+		}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed setting up standby PodReconciler: %w", err)
+		}
+	}
+
+	if r.Failover != nil {
+		if err := r.Failover.SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed setting up failover: %w", err)
+		}
+	}
+
+	if r.RoutePolicies != nil {
+		if err := (&controller.InferenceRoutePolicyReconciler{
+			Client: mgr.GetClient(),
+			Store:  r.RoutePolicies,
+		}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed setting up InferenceRoutePolicyReconciler: %w", err)
+		}
+	}
+
+	if r.Prewarmer != nil {
+		if err := r.Prewarmer.SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed setting up prewarmer: %w", err)
+		}
+	}
+
+	if err := capability.NewReporter(mgr.GetClient(), r.Datastore, capability.DefaultReportInterval).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed setting up model capability reporter: %w", err)
+	}
+
+	detector, err := saturationdetector.NewDetector(saturationdetector.LoadConfigFromEnv(), r.Datastore, log.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed creating the saturation detector of the pool status: %w", err)
+	}
+	if err := poolstatus.NewReporter(mgr.GetClient(), r.Datastore, detector, poolstatus.DefaultReportInterval).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed setting up pool status reporter: %w", err)
+	}
+	return nil
+}
+
+// setupPoolsWithManager sets up the reconcilers of r.Pools with the given manager.
+func (r *ExtProcServerRunner) setupPoolsWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if r.StandbyDatastore != nil || r.Failover != nil || r.AccessLogIngester != nil || r.Prewarmer != nil {
+		return errors.New("the standby pool, the access-log ingestion and the prewarming are not supported with several pools")
+	}
+
+	if err := (&controller.InferencePoolSetReconciler{
+		Client: mgr.GetClient(),
+		Record: mgr.GetEventRecorderFor("InferencePool"),
+		Pools:  r.Pools,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed setting up InferencePoolSetReconciler: %w", err)
+	}
+
+	if err := (&controller.InferenceModelSetReconciler{
+		Client: mgr.GetClient(),
+		Record: mgr.GetEventRecorderFor("InferenceModel"),
+		Pools:  r.Pools,
+	}).SetupWithManager(ctx, mgr); err != nil {
+		return fmt.Errorf("failed setting up InferenceModelSetReconciler: %w", err)
+	}
+
+	if err := (&controller.PodSetReconciler{
+		Client: mgr.GetClient(),
+		Record: mgr.GetEventRecorderFor("pod"),
+		Pools:  r.Pools,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed setting up PodSetReconciler: %w", err)
+	}
+
+	if r.RoutePolicies != nil {
+		if err := (&controller.InferenceRoutePolicyReconciler{
+			Client: mgr.GetClient(),
+			Store:  r.RoutePolicies,
+		}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed setting up InferenceRoutePolicyReconciler: %w", err)
+		}
+	}
+
+	if err := mgr.Add(&poolSetReporters{client: mgr.GetClient(), pools: r.Pools, interval: capability.DefaultReportInterval}); err != nil {
+		return fmt.Errorf("failed setting up pool reporters: %w", err)
+	}
 	return nil
 }
 
@@ -113,17 +270,13 @@ func (r *ExtProcServerRunner) SetupWithManager(ctx context.Context, mgr ctrl.Man
 // The runnable implements LeaderElectionRunnable with leader election disabled.
 func (r *ExtProcServerRunner) AsRunnable(logger logr.Logger) manager.Runnable {
 	return runnable.NoLeaderElection(manager.RunnableFunc(func(ctx context.Context) error {
-		backendmetrics.StartMetricsLogger(ctx, r.Datastore, r.RefreshPrometheusMetricsInterval)
+		if r.Pools == nil {
+			backendmetrics.StartMetricsLogger(ctx, r.Datastore, r.RefreshPrometheusMetricsInterval)
+		}
 		var srv *grpc.Server
 		if r.SecureServing {
-			var cert tls.Certificate
-			var err error
-			if r.CertPath != "" {
-				cert, err = tls.LoadX509KeyPair(r.CertPath+"/tls.crt", r.CertPath+"/tls.key")
-			} else {
-				// Create tls based credential.
-				cert, err = tlsutil.CreateSelfSignedTLSCertificate(logger)
-			}
+			// Create tls based credential.
+			cert, err := tlsutil.LoadOrCreateTLSCertificate(r.CertPath, logger)
 			if err != nil {
 				logger.Error(err, "Failed to create self signed certificate")
 				return err
@@ -137,7 +290,29 @@ func (r *ExtProcServerRunner) AsRunnable(logger logr.Logger) manager.Runnable {
 		} else {
 			srv = grpc.NewServer()
 		}
-		extProcServer := handlers.NewStreamingServer(r.DestinationEndpointHintMetadataNamespace, r.DestinationEndpointHintKey, r.Datastore, requestcontrol.NewDirector(r.Datastore, r.Scheduler))
+		directorConfig := r.DirectorConfig
+		if directorConfig == nil {
+			directorConfig = requestcontrol.NewDefaultConfig()
+		}
+		var extProcServer extProcPb.ExternalProcessorServer
+		if r.Pools != nil {
+			extProcServer = multipool.NewServer(r.Pools, r.DestinationEndpointHintMetadataNamespace, r.poolHandlerFactory(directorConfig)).
+				WithGatewayHeaders(r.TrustGatewayHeaders)
+		} else {
+			// With a standby pool, requests are routed to the pool selected by the failover.
+			var routingDatastore datastore.Datastore = r.Datastore
+			if r.Failover != nil {
+				routingDatastore = r.Failover
+			}
+			director := r.newDirector(routingDatastore, r.Scheduler, directorConfig)
+			if r.AccessLogIngester != nil {
+				r.AccessLogIngester.SetRecorder(director.WithAccessLogFeedback())
+			}
+			if r.Prewarmer != nil {
+				director.WithPrewarmHints(r.Prewarmer)
+			}
+			extProcServer = r.newStreamingServer(routingDatastore, director)
+		}
 		extProcPb.RegisterExternalProcessorServer(
 			srv,
 			extProcServer,
@@ -147,3 +322,36 @@ func (r *ExtProcServerRunner) AsRunnable(logger logr.Logger) manager.Runnable {
 		return runnable.GRPCServer("ext-proc", srv, r.GrpcPort).Start(ctx)
 	}))
 }
+
+// poolHandlerFactory returns the factory of the handlers of the pools of r.Pools, each with a
+// director and a scheduler of its own.
+func (r *ExtProcServerRunner) poolHandlerFactory(directorConfig *requestcontrol.Config) multipool.HandlerFactory {
+	return func(ctx context.Context, _ types.NamespacedName, ds datastore.Datastore) (extProcPb.ExternalProcessorServer, error) {
+		scheduler, err := r.NewPoolScheduler(ds)
+		if err != nil {
+			return nil, err
+		}
+		backendmetrics.StartMetricsLogger(ctx, ds, r.RefreshPrometheusMetricsInterval)
+		return r.newStreamingServer(ds, r.newDirector(ds, scheduler, directorConfig)), nil
+	}
+}
+
+func (r *ExtProcServerRunner) newDirector(ds datastore.Datastore, scheduler requestcontrol.Scheduler, config *requestcontrol.Config) *requestcontrol.Director {
+	director := requestcontrol.NewDirectorWithConfig(ds, scheduler, config)
+	if r.RoutePolicies != nil {
+		director.WithRoutePolicies(r.RoutePolicies)
+	}
+	if r.LoadReports {
+		director.WithLoadReports()
+	}
+	return director
+}
+
+func (r *ExtProcServerRunner) newStreamingServer(ds datastore.Datastore, director *requestcontrol.Director) *handlers.StreamingServer {
+	return handlers.NewStreamingServer(r.DestinationEndpointHintMetadataNamespace, r.DestinationEndpointHintKey, ds, director).
+		WithTimelineRecorder(r.TimelineRecorder).
+		WithRequestLookup(r.RequestLookup).
+		WithThroughputTracker(r.ThroughputTracker).
+		WithResponseTextCapture(r.ResponseTextCaptureLimit).
+		WithGatewayHeaders(r.TrustGatewayHeaders)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultTTFTTarget is the time to first token target of the models that don't define their own
+	// target. Zero means that SLO compliance is not tracked for those models.
+	DefaultTTFTTarget = time.Duration(0)
+	// DefaultObjective is the fraction of requests that are expected to meet the TTFT target.
+	DefaultObjective = 0.95
+	// DefaultShortWindow and DefaultLongWindow are the windows the burn rate is computed over,
+	// following the common multi-window burn-rate alerting practice.
+	DefaultShortWindow = 5 * time.Minute
+	DefaultLongWindow  = time.Hour
+	// DefaultFastBurnThreshold is the burn rate above which the error budget is considered to be
+	// burning fast. At 14.4, a 30 day budget is exhausted in about 2 days.
+	DefaultFastBurnThreshold = 14.4
+	// MinShortWindow is the shortest short window, as the windows are tracked in buckets of a
+	// fraction of the short window.
+	MinShortWindow = time.Second
+)
+
+// Environment variable names for SLO tracking configuration
+const (
+	EnvSLODefaultTTFTTarget = "SLO_DEFAULT_TTFT_TARGET"
+	EnvSLODefaultObjective  = "SLO_DEFAULT_OBJECTIVE"
+	EnvSLOShortWindow       = "SLO_SHORT_WINDOW"
+	EnvSLOLongWindow        = "SLO_LONG_WINDOW"
+	EnvSLOFastBurnThreshold = "SLO_FAST_BURN_THRESHOLD"
+)
+
+// Config holds the configuration for the SLO Tracker.
+type Config struct {
+	// DefaultTTFTTarget is used for the models without the TTFT target annotation.
+	DefaultTTFTTarget time.Duration
+	// DefaultObjective is used for the models without the objective annotation.
+	DefaultObjective float64
+	// ShortWindow and LongWindow are the windows the error budget burn rate is computed over.
+	ShortWindow time.Duration
+	LongWindow  time.Duration
+	// FastBurnThreshold is the burn rate that both windows need to exceed for the error budget to be
+	// considered burning fast.
+	FastBurnThreshold float64
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		DefaultTTFTTarget: DefaultTTFTTarget,
+		DefaultObjective:  DefaultObjective,
+		ShortWindow:       DefaultShortWindow,
+		LongWindow:        DefaultLongWindow,
+		FastBurnThreshold: DefaultFastBurnThreshold,
+	}
+}
+
+// LoadConfigFromEnv loads SLO Tracker Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("slo-config")
+
+	cfg := &Config{}
+
+	cfg.DefaultTTFTTarget = envutil.GetEnvDuration(EnvSLODefaultTTFTTarget, DefaultTTFTTarget, logger)
+	if cfg.DefaultTTFTTarget < 0 {
+		cfg.DefaultTTFTTarget = DefaultTTFTTarget
+	}
+
+	cfg.DefaultObjective = envutil.GetEnvFloat(EnvSLODefaultObjective, DefaultObjective, logger)
+	if cfg.DefaultObjective <= 0 || cfg.DefaultObjective >= 1 {
+		cfg.DefaultObjective = DefaultObjective
+	}
+
+	cfg.ShortWindow = envutil.GetEnvDuration(EnvSLOShortWindow, DefaultShortWindow, logger)
+	if cfg.ShortWindow < MinShortWindow {
+		cfg.ShortWindow = DefaultShortWindow
+	}
+
+	cfg.LongWindow = envutil.GetEnvDuration(EnvSLOLongWindow, DefaultLongWindow, logger)
+	if cfg.LongWindow < cfg.ShortWindow {
+		cfg.LongWindow = max(DefaultLongWindow, cfg.ShortWindow)
+	}
+
+	cfg.FastBurnThreshold = envutil.GetEnvFloat(EnvSLOFastBurnThreshold, DefaultFastBurnThreshold, logger)
+	if cfg.FastBurnThreshold <= 0 {
+		cfg.FastBurnThreshold = DefaultFastBurnThreshold
+	}
+
+	logger.Info("SLO tracking configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
+
+// Validate returns an error if the Config can't be used by a Tracker.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.DefaultTTFTTarget < 0 {
+		errs = append(errs, fmt.Errorf("negative default TTFT target %s", c.DefaultTTFTTarget))
+	}
+	if c.DefaultObjective <= 0 || c.DefaultObjective >= 1 {
+		errs = append(errs, fmt.Errorf("default objective %v not in (0, 1)", c.DefaultObjective))
+	}
+	if c.ShortWindow < MinShortWindow {
+		errs = append(errs, fmt.Errorf("short window %s shorter than %s", c.ShortWindow, MinShortWindow))
+	}
+	if c.LongWindow < c.ShortWindow {
+		errs = append(errs, fmt.Errorf("long window %s shorter than the short window %s", c.LongWindow, c.ShortWindow))
+	}
+	if c.FastBurnThreshold <= 0 {
+		errs = append(errs, fmt.Errorf("non-positive fast burn threshold %v", c.FastBurnThreshold))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slo tracks the latency SLO compliance of InferenceModels and the burn rate of their
+// error budget.
+//
+// An InferenceModel opts in by setting a time to first token target through the
+// inference.networking.x-k8s.io/ttft-slo annotation (e.g. "500ms"), and optionally the fraction of
+// requests expected to meet it through the inference.networking.x-k8s.io/slo-objective
+// annotation (e.g. "0.99"). Otherwise the defaults of the Config are used.
+//
+// The burn rate is the rate at which the error budget (1 - objective) is consumed: a burn rate of 1
+// consumes exactly the budget over the SLO period, while higher values exhaust it early.
+package slo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/metrics"
+	logutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/logging"
+)
+
+const (
+	TTFTTargetAnnotationKey = "inference.networking.x-k8s.io/ttft-slo"
+	ObjectiveAnnotationKey  = "inference.networking.x-k8s.io/slo-objective"
+
+	// bucketsPerShortWindow is the granularity of the sliding windows.
+	bucketsPerShortWindow = 5
+)
+
+// Datastore provides access to the InferenceModels.
+type Datastore interface {
+	ModelGet(modelName string) *v1alpha2.InferenceModel
+}
+
+// Tracker tracks the SLO compliance of requests per InferenceModel.
+type Tracker struct {
+	config      *Config
+	datastore   Datastore
+	bucketWidth time.Duration
+	numBuckets  int64
+
+	mu     sync.Mutex
+	models map[string][]bucket
+	now    func() time.Time
+}
+
+// bucket counts the requests completed within a bucketWidth interval.
+type bucket struct {
+	// epoch identifies the interval the counts belong to, so stale buckets of the ring are ignored.
+	epoch      int64
+	total      int
+	violations int
+}
+
+// NewTracker creates a new SLO Tracker. It returns an error if the config is invalid.
+func NewTracker(config *Config, datastore Datastore) (*Tracker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid SLO tracking config: %w", err)
+	}
+	bucketWidth := config.ShortWindow / bucketsPerShortWindow
+	return &Tracker{
+		config:      config,
+		datastore:   datastore,
+		bucketWidth: bucketWidth,
+		numBuckets:  int64((config.LongWindow + bucketWidth - 1) / bucketWidth),
+		models:      make(map[string][]bucket),
+		now:         time.Now,
+	}, nil
+}
+
+// Record records the time to first token of a request to the given model, and updates the
+// compliance and burn-rate metrics of the model. Requests to models without a TTFT target are
+// ignored.
+func (t *Tracker) Record(ctx context.Context, modelName string, ttft time.Duration) {
+	target, objective := t.target(modelName)
+	if target <= 0 {
+		return
+	}
+	met := ttft <= target
+	metrics.RecordSLORequest(modelName, met)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buckets, ok := t.models[modelName]
+	if !ok {
+		buckets = make([]bucket, t.numBuckets)
+		t.models[modelName] = buckets
+	}
+	epoch := t.epoch()
+	b := &buckets[epoch%t.numBuckets]
+	if b.epoch != epoch {
+		*b = bucket{epoch: epoch}
+	}
+	b.total++
+	if !met {
+		b.violations++
+	}
+
+	shortBurnRate := t.burnRate(buckets, t.config.ShortWindow, objective)
+	longBurnRate := t.burnRate(buckets, t.config.LongWindow, objective)
+	metrics.RecordSLOBurnRate(modelName, t.config.ShortWindow.String(), shortBurnRate)
+	metrics.RecordSLOBurnRate(modelName, t.config.LongWindow.String(), longBurnRate)
+	log.FromContext(ctx).V(logutil.TRACE).Info("Recorded SLO compliance", "model", modelName, "ttft", ttft, "target", target,
+		"shortBurnRate", shortBurnRate, "longBurnRate", longBurnRate)
+}
+
+// IsBurningFast returns true if the error budget of the model burns faster than the configured
+// threshold over both the short and the long windows.
+func (t *Tracker) IsBurningFast(modelName string) bool {
+	target, objective := t.target(modelName)
+	if target <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buckets, ok := t.models[modelName]
+	if !ok {
+		return false
+	}
+	return t.burnRate(buckets, t.config.ShortWindow, objective) > t.config.FastBurnThreshold &&
+		t.burnRate(buckets, t.config.LongWindow, objective) > t.config.FastBurnThreshold
+}
+
+// target returns the TTFT target and the objective of the model.
+func (t *Tracker) target(modelName string) (time.Duration, float64) {
+	target, objective := t.config.DefaultTTFTTarget, t.config.DefaultObjective
+	model := t.datastore.ModelGet(modelName)
+	if model == nil {
+		return target, objective
+	}
+	if v, err := time.ParseDuration(model.Annotations[TTFTTargetAnnotationKey]); err == nil {
+		target = v
+	}
+	if v, err := strconv.ParseFloat(model.Annotations[ObjectiveAnnotationKey], 64); err == nil && v > 0 && v < 1 {
+		objective = v
+	}
+	return target, objective
+}
+
+func (t *Tracker) epoch() int64 {
+	return t.now().UnixNano() / int64(t.bucketWidth)
+}
+
+// burnRate returns the error budget burn rate over the given window. Must be called with the lock
+// held.
+func (t *Tracker) burnRate(buckets []bucket, window time.Duration, objective float64) float64 {
+	current := t.epoch()
+	oldest := current - int64(window/t.bucketWidth) + 1
+	total, violations := 0, 0
+	for _, b := range buckets {
+		if b.epoch >= oldest && b.epoch <= current {
+			total += b.total
+			violations += b.violations
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(violations) / float64(total) / (1 - objective)
+}
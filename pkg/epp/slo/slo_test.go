@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api-inference-extension/api/v1alpha2"
+)
+
+type fakeDatastore struct {
+	models map[string]*v1alpha2.InferenceModel
+}
+
+func (f *fakeDatastore) ModelGet(modelName string) *v1alpha2.InferenceModel {
+	return f.models[modelName]
+}
+
+func TestTracker(t *testing.T) {
+	ds := &fakeDatastore{models: map[string]*v1alpha2.InferenceModel{
+		"with-slo": {ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			TTFTTargetAnnotationKey: "500ms",
+			ObjectiveAnnotationKey:  "0.9",
+		}}},
+		"without-slo": {},
+	}}
+	tracker, err := NewTracker(&Config{
+		DefaultObjective:  DefaultObjective,
+		ShortWindow:       5 * time.Minute,
+		LongWindow:        time.Hour,
+		FastBurnThreshold: 6,
+	}, ds)
+	assert.NoError(t, err)
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Models without a target are not tracked.
+	tracker.Record(ctx, "without-slo", time.Minute)
+	assert.Empty(t, tracker.models)
+	assert.False(t, tracker.IsBurningFast("without-slo"))
+
+	// A violation ratio of 1/2 with a 10% error budget burns at 5x, not above the threshold.
+	tracker.Record(ctx, "with-slo", 100*time.Millisecond)
+	tracker.Record(ctx, "with-slo", time.Second)
+	assert.InDelta(t, 5, tracker.burnRate(tracker.models["with-slo"], time.Hour, 0.9), 0.0001)
+	assert.False(t, tracker.IsBurningFast("with-slo"))
+
+	// More violations burn the budget faster in both windows.
+	tracker.Record(ctx, "with-slo", time.Second)
+	assert.True(t, tracker.IsBurningFast("with-slo"))
+
+	// Once the violations leave the short window, the budget is no longer burning fast.
+	now = now.Add(10 * time.Minute)
+	tracker.Record(ctx, "with-slo", 100*time.Millisecond)
+	assert.InDelta(t, 0, tracker.burnRate(tracker.models["with-slo"], 5*time.Minute, 0.9), 0.0001)
+	assert.False(t, tracker.IsBurningFast("with-slo"))
+
+	// Violations older than the long window are forgotten.
+	now = now.Add(2 * time.Hour)
+	assert.InDelta(t, 0, tracker.burnRate(tracker.models["with-slo"], time.Hour, 0.9), 0.0001)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, NewDefaultConfig().Validate())
+
+	config := NewDefaultConfig()
+	config.ShortWindow = time.Nanosecond
+	_, err := NewTracker(config, &fakeDatastore{})
+	assert.Error(t, err, "a window too short for its buckets is rejected")
+
+	config = NewDefaultConfig()
+	config.LongWindow = time.Minute
+	assert.Error(t, config.Validate(), "a long window shorter than the short window is rejected")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throughput accounts for the output tokens streamed through every gateway listener, so
+// the capacity of a pool shared by several gateways can be attributed to each of them.
+package throughput
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWindow is the default window over which the throughput is averaged.
+	DefaultWindow = time.Minute
+	// buckets is the number of buckets the window is divided in. The throughput is accurate to a
+	// bucket, i.e. the window slides by a bucket at a time.
+	buckets = 60
+)
+
+// Tracker aggregates the output tokens of the responses per listener over a sliding window.
+type Tracker struct {
+	window      time.Duration
+	bucketWidth time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	listeners map[string]*series
+}
+
+// series is the ring of buckets of a listener.
+type series struct {
+	tokens [buckets]int64
+	// periods are the periods, in bucket widths since the epoch, the buckets hold the tokens of.
+	periods [buckets]int64
+}
+
+// NewTracker creates a new Tracker averaging the throughput over the given window, the default
+// window if it's not positive.
+func NewTracker(window time.Duration) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Tracker{
+		window:      window,
+		bucketWidth: max(window/buckets, time.Millisecond),
+		now:         time.Now,
+		listeners:   map[string]*series{},
+	}
+}
+
+// Record records the given number of output tokens generated for a request received on the given
+// listener.
+func (t *Tracker) Record(listener string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	period := t.period()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.listeners[listener]
+	if !ok {
+		s = &series{}
+		t.listeners[listener] = s
+	}
+	i := period % buckets
+	if s.periods[i] != period {
+		s.periods[i] = period
+		s.tokens[i] = 0
+	}
+	s.tokens[i] += int64(tokens)
+}
+
+// Rate returns the output tokens per second of the given listener over the window.
+func (t *Tracker) Rate(listener string) float64 {
+	period := t.period()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.listeners[listener]
+	if !ok {
+		return 0
+	}
+	return t.rate(s, period)
+}
+
+// Rates returns the output tokens per second of every listener over the window. The listeners
+// without tokens over the window are forgotten.
+func (t *Tracker) Rates() map[string]float64 {
+	period := t.period()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rates := make(map[string]float64, len(t.listeners))
+	for listener, s := range t.listeners {
+		rate := t.rate(s, period)
+		if rate == 0 {
+			delete(t.listeners, listener)
+			continue
+		}
+		rates[listener] = rate
+	}
+	return rates
+}
+
+func (t *Tracker) rate(s *series, period int64) float64 {
+	var tokens int64
+	for i := range buckets {
+		if period-s.periods[i] < buckets {
+			tokens += s.tokens[i]
+		}
+	}
+	return float64(tokens) / t.window.Seconds()
+}
+
+func (t *Tracker) period() int64 {
+	return t.now().UnixNano() / int64(t.bucketWidth)
+}
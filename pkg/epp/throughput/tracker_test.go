@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throughput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := NewTracker(10 * time.Second)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("gw-a/http", 50)
+	tracker.Record("gw-b/http", 20)
+	now = now.Add(5 * time.Second)
+	tracker.Record("gw-a/http", 50)
+	tracker.Record("gw-a/http", 0)
+
+	if diff := cmp.Diff(map[string]float64{"gw-a/http": 10, "gw-b/http": 2}, tracker.Rates()); diff != "" {
+		t.Errorf("Unexpected rates (-want +got): %s", diff)
+	}
+
+	// The tokens of the first records slide out of the window.
+	now = now.Add(6 * time.Second)
+	if got := tracker.Rate("gw-a/http"); got != 5 {
+		t.Errorf("Got rate %v for gw-a/http, want 5", got)
+	}
+	if diff := cmp.Diff(map[string]float64{"gw-a/http": 5}, tracker.Rates()); diff != "" {
+		t.Errorf("Unexpected rates (-want +got): %s", diff)
+	}
+	if got := tracker.Rate("unknown"); got != 0 {
+		t.Errorf("Got rate %v for an unknown listener, want 0", got)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeline
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultThreshold is zero, i.e. the timelines are not recorded by default.
+	DefaultThreshold = 0 * time.Second
+	DefaultCapacity  = 100
+)
+
+// Environment variable names for the slow request timelines configuration
+const (
+	EnvThreshold = "SLOW_REQUEST_THRESHOLD"
+	EnvCapacity  = "SLOW_REQUEST_TIMELINES"
+)
+
+// Config holds the configuration of the Recorder.
+type Config struct {
+	// Threshold is the latency from the arrival of a request to the end of its processing above
+	// which the timeline of the request is retained. Zero disables the recording.
+	Threshold time.Duration
+	// Capacity is the number of the most recent slow request timelines retained.
+	Capacity int
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Threshold: DefaultThreshold,
+		Capacity:  DefaultCapacity,
+	}
+}
+
+// Enabled returns true if the slow request timelines are recorded.
+func (c *Config) Enabled() bool {
+	return c.Threshold > 0 && c.Capacity > 0
+}
+
+// LoadConfigFromEnv loads the slow request timelines Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("timeline-config")
+
+	cfg := &Config{}
+
+	cfg.Threshold = envutil.GetEnvDuration(EnvThreshold, DefaultThreshold, logger)
+	if cfg.Threshold < 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+
+	cfg.Capacity = envutil.GetEnvInt(EnvCapacity, DefaultCapacity, logger)
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = DefaultCapacity
+	}
+
+	logger.Info("Slow request timelines configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeline
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Path is the path of the debug endpoint serving the slow request timelines.
+const Path = "/debug/slow-requests"
+
+// Recorder retains the timelines of the most recent requests slower than the threshold in a ring
+// buffer.
+type Recorder struct {
+	threshold time.Duration
+
+	mu        sync.Mutex
+	timelines []*Timeline // ring buffer of capacity timelines
+	next      int         // index of the next timeline to write
+	full      bool
+}
+
+// NewRecorder returns a new Recorder with the given configuration.
+func NewRecorder(config *Config) *Recorder {
+	return &Recorder{
+		threshold: config.Threshold,
+		timelines: make([]*Timeline, max(config.Capacity, 1)),
+	}
+}
+
+// Start returns a new Timeline of a request arrived now, or nil if the recorder is nil.
+func (r *Recorder) Start() *Timeline {
+	if r == nil {
+		return nil
+	}
+	return New(time.Now())
+}
+
+// Finish ends the given timeline, and retains it if the request was slower than the threshold.
+func (r *Recorder) Finish(t *Timeline) {
+	if r == nil || t == nil {
+		return
+	}
+	t.Latency = time.Since(t.Arrival)
+	if t.Latency < r.threshold {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timelines[r.next] = t
+	r.next = (r.next + 1) % len(r.timelines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Timelines returns the retained timelines, the most recent first.
+func (r *Recorder) Timelines() []*Timeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := r.next
+	if r.full {
+		count = len(r.timelines)
+	}
+	timelines := make([]*Timeline, 0, count)
+	for i := 1; i <= count; i++ {
+		timelines = append(timelines, r.timelines[(r.next-i+len(r.timelines))%len(r.timelines)].snapshot())
+	}
+	return timelines
+}
+
+// ServeHTTP serves the retained timelines as JSON, the most recent first. The optional "limit"
+// query parameter bounds the number of timelines returned, e.g. GET /debug/slow-requests?limit=10.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	timelines := r.Timelines()
+	if limit := req.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+		timelines = timelines[:min(n, len(timelines))]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(timelines)
+}
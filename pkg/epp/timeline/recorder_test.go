@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(&Config{Threshold: time.Second, Capacity: 2})
+
+	finish := func(requestID string, latency time.Duration) {
+		tl := New(time.Now().Add(-latency))
+		tl.RequestID = requestID
+		recorder.Finish(tl)
+	}
+	requestIDs := func() []string {
+		ids := []string{}
+		for _, tl := range recorder.Timelines() {
+			ids = append(ids, tl.RequestID)
+		}
+		return ids
+	}
+
+	finish("fast", 10*time.Millisecond)
+	if diff := cmp.Diff([]string{}, requestIDs()); diff != "" {
+		t.Errorf("Unexpected timelines after a fast request (-want +got): %s", diff)
+	}
+	finish("slow-1", 2*time.Second)
+	finish("slow-2", 3*time.Second)
+	if diff := cmp.Diff([]string{"slow-2", "slow-1"}, requestIDs()); diff != "" {
+		t.Errorf("Unexpected timelines (-want +got): %s", diff)
+	}
+	// The oldest timeline is dropped once the ring buffer is full.
+	finish("slow-3", 2*time.Second)
+	if diff := cmp.Diff([]string{"slow-3", "slow-2"}, requestIDs()); diff != "" {
+		t.Errorf("Unexpected timelines after the buffer wrapped (-want +got): %s", diff)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	recorder := NewRecorder(&Config{Threshold: time.Nanosecond, Capacity: 10})
+	tl := recorder.Start()
+	ctx := NewContext(context.Background(), tl)
+
+	FromContext(ctx).Mark(EventRequestParsed)
+	before := time.Now()
+	time.Sleep(time.Millisecond)
+	FromContext(ctx).Span("default/Filter/least-queue", before)
+	recorder.Finish(tl)
+
+	timelines := recorder.Timelines()
+	if len(timelines) != 1 {
+		t.Fatalf("Expected 1 timeline, got %d", len(timelines))
+	}
+	events := timelines[0].Events
+	if len(events) != 2 || events[0].Name != EventRequestParsed || events[1].Name != "default/Filter/least-queue" {
+		t.Fatalf("Unexpected events: %+v", events)
+	}
+	if events[1].Duration < time.Millisecond || events[1].Offset < events[0].Offset {
+		t.Errorf("Unexpected span: %+v", events[1])
+	}
+	if timelines[0].Latency < events[1].Offset+events[1].Duration {
+		t.Errorf("Latency %v shorter than the events", timelines[0].Latency)
+	}
+
+	// A nil recorder records nothing, and the timelines of its requests can be used regardless.
+	var disabled *Recorder
+	tl = disabled.Start()
+	tl.Mark(EventRequestParsed)
+	tl.Span(EventScheduled, time.Now())
+	disabled.Finish(tl)
+	if FromContext(context.Background()) != nil {
+		t.Errorf("Expected no timeline in a context without one")
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	recorder := NewRecorder(&Config{Threshold: time.Second, Capacity: 10})
+	for _, requestID := range []string{"1", "2", "3"} {
+		tl := New(time.Now().Add(-2 * time.Second))
+		tl.RequestID = requestID
+		recorder.Finish(tl)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{name: "all", method: http.MethodGet, target: Path, wantStatus: http.StatusOK, wantIDs: []string{"3", "2", "1"}},
+		{name: "limit", method: http.MethodGet, target: Path + "?limit=2", wantStatus: http.StatusOK, wantIDs: []string{"3", "2"}},
+		{name: "invalid limit", method: http.MethodGet, target: Path + "?limit=x", wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodPost, target: Path, wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			recorder.ServeHTTP(rec, httptest.NewRequest(test.method, test.target, nil))
+			if rec.Code != test.wantStatus {
+				t.Fatalf("Unexpected status: want %d, got %d", test.wantStatus, rec.Code)
+			}
+			if test.wantStatus != http.StatusOK {
+				return
+			}
+			var timelines []*Timeline
+			if err := json.Unmarshal(rec.Body.Bytes(), &timelines); err != nil {
+				t.Fatalf("Failed to decode the timelines: %v", err)
+			}
+			ids := []string{}
+			for _, tl := range timelines {
+				ids = append(ids, tl.RequestID)
+			}
+			if diff := cmp.Diff(test.wantIDs, ids); diff != "" {
+				t.Errorf("Unexpected timelines (-want +got): %s", diff)
+			}
+		})
+	}
+}
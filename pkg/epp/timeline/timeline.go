@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package timeline records the timeline of the requests taking longer than a threshold (their
+// arrival, parsing, scheduling plugins, pick, time to first token and end of stream) and retains the
+// most recent ones, so slow requests can be investigated after the fact without debug logging.
+package timeline
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Event names recorded along the processing of every request. The scheduling plugins are recorded
+// as [<profile>/]<plugin type>/<plugin name>.
+const (
+	EventRequestHeaders   = "request_headers"
+	EventRequestParsed    = "request_parsed"
+	EventScheduled        = "scheduled"
+	EventResponseHeaders  = "response_headers"
+	EventFirstChunk       = "response_first_chunk"
+	EventResponseComplete = "response_complete"
+	// EventCancelled marks the disconnection of the client before the response completed.
+	EventCancelled = "cancelled"
+)
+
+// Event is a step of the processing of a request.
+type Event struct {
+	Name string `json:"name"`
+	// Offset is the time from the arrival of the request to the start of the event.
+	Offset time.Duration `json:"offset"`
+	// Duration is the duration of the event, zero for the events marking a point in time.
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// Timeline is the timeline of the processing of a request. All its methods are safe to call on a
+// nil Timeline, which records nothing, so the call sites don't depend on the recording being
+// enabled.
+type Timeline struct {
+	RequestID   string `json:"requestId,omitempty"`
+	Model       string `json:"model,omitempty"`
+	TargetModel string `json:"targetModel,omitempty"`
+	TargetPod   string `json:"targetPod,omitempty"`
+	StatusCode  string `json:"statusCode,omitempty"`
+	// Labels are the labels of the request set by the classifiers of the scheduler.
+	Labels  map[string]string `json:"labels,omitempty"`
+	Arrival time.Time         `json:"arrival"`
+	// Latency is the time from the arrival of the request to the end of its processing.
+	Latency time.Duration `json:"latency"`
+	Events  []Event       `json:"events"`
+
+	// mu guards the events, as the plugins aborted by the scheduling budget may end after the
+	// request.
+	mu sync.Mutex
+}
+
+// New returns a new Timeline of a request arrived at the given time.
+func New(arrival time.Time) *Timeline {
+	return &Timeline{Arrival: arrival}
+}
+
+// Mark records an event marking the current time.
+func (t *Timeline) Mark(name string) {
+	if t == nil {
+		return
+	}
+	t.add(Event{Name: name, Offset: time.Since(t.Arrival)})
+}
+
+// Span records an event that started at the given time and ends now.
+func (t *Timeline) Span(name string, start time.Time) {
+	if t == nil {
+		return
+	}
+	t.add(Event{Name: name, Offset: start.Sub(t.Arrival), Duration: time.Since(start)})
+}
+
+func (t *Timeline) add(event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Events = append(t.Events, event)
+}
+
+// snapshot returns a copy of the timeline, safe to read while events are still being added.
+func (t *Timeline) snapshot() *Timeline {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &Timeline{
+		RequestID:   t.RequestID,
+		Model:       t.Model,
+		TargetModel: t.TargetModel,
+		TargetPod:   t.TargetPod,
+		StatusCode:  t.StatusCode,
+		Labels:      maps.Clone(t.Labels),
+		Arrival:     t.Arrival,
+		Latency:     t.Latency,
+		Events:      append([]Event(nil), t.Events...),
+	}
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the given context carrying the given Timeline.
+func NewContext(ctx context.Context, t *Timeline) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the Timeline carried by the given context, nil if there is none.
+func FromContext(ctx context.Context) *Timeline {
+	t, _ := ctx.Value(contextKey{}).(*Timeline)
+	return t
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenestimate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	envutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/env"
+)
+
+// Default configuration values
+const (
+	// DefaultCharsPerToken is the average number of characters per token of English text with the
+	// common BPE tokenizers, used for the models that don't belong to a configured family.
+	DefaultCharsPerToken = 4.0
+	// DefaultOutputTokens is the estimated number of output tokens of the requests for models whose
+	// responses weren't observed yet.
+	DefaultOutputTokens = 256
+	// DefaultOutputTokensWindow is the number of responses the average number of output tokens of a
+	// model is computed over.
+	DefaultOutputTokensWindow = 100
+)
+
+// Environment variable names for token estimation configuration
+const (
+	EnvFamilies           = "TOKEN_ESTIMATE_FAMILIES"
+	EnvCharsPerToken      = "TOKEN_ESTIMATE_CHARS_PER_TOKEN"
+	EnvOutputTokens       = "TOKEN_ESTIMATE_OUTPUT_TOKENS"
+	EnvOutputTokensWindow = "TOKEN_ESTIMATE_OUTPUT_TOKENS_WINDOW"
+)
+
+// Family is a family of models sharing a tokenizer.
+type Family struct {
+	// Name identifies the family, e.g. to register its Tokenizer.
+	Name string
+	// ModelPrefixes are the prefixes of the names of the target models of the family. The family
+	// with the longest matching prefix wins.
+	ModelPrefixes []string
+	// CharsPerToken is the average number of characters per token of the tokenizer of the family,
+	// used to count the tokens when the family has no registered Tokenizer.
+	CharsPerToken float64
+}
+
+// Config holds the configuration of the Estimator.
+type Config struct {
+	// Families are the model families with their own tokenizer.
+	Families []Family
+	// Tokenizers are the Tokenizers of the families, keyed by family name. The families without a
+	// Tokenizer use a heuristic one.
+	Tokenizers map[string]Tokenizer
+	// CharsPerToken is used for the models that don't belong to any family.
+	CharsPerToken float64
+	// OutputTokens is the estimated number of output tokens of the requests for models whose
+	// responses weren't observed yet.
+	OutputTokens int
+	// OutputTokensWindow is the number of responses the average number of output tokens of a model
+	// is computed over.
+	OutputTokensWindow int
+}
+
+// NewDefaultConfig returns a Config populated with the default values.
+func NewDefaultConfig() *Config {
+	return &Config{
+		CharsPerToken:      DefaultCharsPerToken,
+		OutputTokens:       DefaultOutputTokens,
+		OutputTokensWindow: DefaultOutputTokensWindow,
+	}
+}
+
+// LoadConfigFromEnv loads Estimator Config from environment variables.
+func LoadConfigFromEnv() *Config {
+	// Use a default logger for initial configuration loading.
+	logger := log.Log.WithName("token-estimate-config")
+
+	cfg := NewDefaultConfig()
+
+	families, err := ParseFamilies(envutil.GetEnvString(EnvFamilies, "", logger))
+	if err != nil {
+		logger.Error(err, "Ignoring invalid model families", "env", EnvFamilies)
+	} else {
+		cfg.Families = families
+	}
+
+	cfg.CharsPerToken = envutil.GetEnvFloat(EnvCharsPerToken, DefaultCharsPerToken, logger)
+	if cfg.CharsPerToken <= 0 {
+		cfg.CharsPerToken = DefaultCharsPerToken
+	}
+
+	cfg.OutputTokens = envutil.GetEnvInt(EnvOutputTokens, DefaultOutputTokens, logger)
+	if cfg.OutputTokens < 0 {
+		cfg.OutputTokens = DefaultOutputTokens
+	}
+
+	cfg.OutputTokensWindow = envutil.GetEnvInt(EnvOutputTokensWindow, DefaultOutputTokensWindow, logger)
+	if cfg.OutputTokensWindow <= 0 {
+		cfg.OutputTokensWindow = DefaultOutputTokensWindow
+	}
+
+	logger.Info("Token estimation configuration loaded from env", "config", fmt.Sprintf("%+v", cfg))
+	return cfg
+}
+
+// ParseFamilies parses a comma separated list of model families, each formatted as
+// <name>=<model prefix>[|<model prefix>...]:<chars per token>, e.g.
+// "llama=meta-llama/|llama:3.6,qwen=Qwen/:3.3".
+func ParseFamilies(s string) ([]Family, error) {
+	families := []Family{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, rest, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid model family %q, expected <name>=<model prefixes>:<chars per token>", item)
+		}
+		i := strings.LastIndex(rest, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid model family %q, expected <name>=<model prefixes>:<chars per token>", item)
+		}
+		charsPerToken, err := strconv.ParseFloat(rest[i+1:], 64)
+		if err != nil || charsPerToken <= 0 {
+			return nil, fmt.Errorf("invalid chars per token of model family %q", name)
+		}
+		family := Family{Name: name, CharsPerToken: charsPerToken}
+		for _, prefix := range strings.Split(rest[:i], "|") {
+			if prefix != "" {
+				family.ModelPrefixes = append(family.ModelPrefixes, prefix)
+			}
+		}
+		if len(family.ModelPrefixes) == 0 {
+			return nil, fmt.Errorf("model family %q has no model prefix", name)
+		}
+		families = append(families, family)
+	}
+	return families, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tokenestimate estimates the cost of the requests in tokens: the number of tokens of
+// their prompt, and the number of tokens they are expected to generate. The estimates are
+// computed once per request, so all the plugins and the admission control use the same numbers.
+package tokenestimate
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Tokenizer counts the tokens of a text, e.g. with the tokenizer of a model family.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// HeuristicTokenizer approximates the number of tokens of a text without tokenizing it. The ASCII
+// characters are counted at CharsPerToken characters per token, and the other characters (e.g.
+// CJK) at one token each, as the BPE tokenizers rarely merge them.
+type HeuristicTokenizer struct {
+	CharsPerToken float64
+}
+
+// CountTokens returns the approximate number of tokens of the text.
+func (t HeuristicTokenizer) CountTokens(text string) int {
+	ascii, other := 0, 0
+	for i := 0; i < len(text); {
+		if text[i] < utf8.RuneSelf {
+			ascii++
+			i++
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		other++
+		i += size
+	}
+	return int(math.Ceil(float64(ascii)/t.CharsPerToken)) + other
+}
+
+// Estimate is the estimated cost of a request.
+type Estimate struct {
+	// PromptTokens is the number of tokens of the prompt.
+	PromptTokens int
+	// OutputTokens is the expected number of generated tokens.
+	OutputTokens int
+}
+
+// Estimator estimates the cost of the requests. The prompt tokens are counted with the Tokenizer of
+// the family of the target model, and the output tokens are the moving average of the output
+// tokens of the recent responses of the model, bounded by the maximum number of tokens the request
+// allows.
+type Estimator struct {
+	config   *Config
+	fallback Tokenizer
+
+	mu     sync.RWMutex
+	output map[string]float64 // average output tokens, keyed by target model
+}
+
+// NewEstimator returns a new Estimator with the given configuration.
+func NewEstimator(config *Config) *Estimator {
+	if config.CharsPerToken <= 0 {
+		config.CharsPerToken = DefaultCharsPerToken
+	}
+	if config.OutputTokensWindow <= 0 {
+		config.OutputTokensWindow = DefaultOutputTokensWindow
+	}
+	return &Estimator{
+		config:   config,
+		fallback: HeuristicTokenizer{CharsPerToken: config.CharsPerToken},
+		output:   map[string]float64{},
+	}
+}
+
+// Estimate returns the estimated cost of a request for the given target model with the given
+// prompt. maxTokens is the maximum number of tokens the request allows to generate, zero if unset.
+func (e *Estimator) Estimate(model, prompt string, maxTokens int) Estimate {
+	e.mu.RLock()
+	average, ok := e.output[model]
+	e.mu.RUnlock()
+	outputTokens := e.config.OutputTokens
+	if ok {
+		outputTokens = int(math.Round(average))
+	}
+	if maxTokens > 0 {
+		outputTokens = min(outputTokens, maxTokens)
+	}
+	return Estimate{
+		PromptTokens: e.tokenizer(model).CountTokens(prompt),
+		OutputTokens: outputTokens,
+	}
+}
+
+// Observe records the number of tokens generated for a request for the given target model.
+func (e *Estimator) Observe(model string, outputTokens int) {
+	if outputTokens <= 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	average, ok := e.output[model]
+	if !ok {
+		e.output[model] = float64(outputTokens)
+		return
+	}
+	alpha := 2 / (float64(e.config.OutputTokensWindow) + 1)
+	e.output[model] = average + alpha*(float64(outputTokens)-average)
+}
+
+// tokenizer returns the Tokenizer of the family of the model, with the longest matching prefix.
+func (e *Estimator) tokenizer(model string) Tokenizer {
+	var family *Family
+	longest := 0
+	for i := range e.config.Families {
+		for _, prefix := range e.config.Families[i].ModelPrefixes {
+			if len(prefix) > longest && strings.HasPrefix(model, prefix) {
+				family, longest = &e.config.Families[i], len(prefix)
+			}
+		}
+	}
+	if family == nil {
+		return e.fallback
+	}
+	if tokenizer, ok := e.config.Tokenizers[family.Name]; ok {
+		return tokenizer
+	}
+	if family.CharsPerToken <= 0 {
+		return e.fallback
+	}
+	return HeuristicTokenizer{CharsPerToken: family.CharsPerToken}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type wordTokenizer struct{}
+
+func (wordTokenizer) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestHeuristicTokenizer(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "Hello", want: 2},
+		{text: "Hello world!", want: 3},
+		{text: "你好", want: 2},
+		{text: "Hi 你好", want: 3},
+	}
+	tokenizer := HeuristicTokenizer{CharsPerToken: 4}
+	for _, test := range tests {
+		if got := tokenizer.CountTokens(test.text); got != test.want {
+			t.Errorf("CountTokens(%q) = %d, want %d", test.text, got, test.want)
+		}
+	}
+}
+
+func TestEstimatePromptTokens(t *testing.T) {
+	estimator := NewEstimator(&Config{
+		Families: []Family{
+			{Name: "llama", ModelPrefixes: []string{"meta-llama/"}, CharsPerToken: 2},
+			{Name: "llama-instruct", ModelPrefixes: []string{"meta-llama/Llama-3.1-8B-Instruct"}},
+			{Name: "qwen", ModelPrefixes: []string{"Qwen/"}, CharsPerToken: 8},
+		},
+		Tokenizers: map[string]Tokenizer{"llama-instruct": wordTokenizer{}},
+	})
+	prompt := strings.Repeat("abcd ", 8) // 40 characters, 8 words
+
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{model: "meta-llama/Llama-3.1-70B", want: 20},
+		{model: "meta-llama/Llama-3.1-8B-Instruct", want: 8}, // the longest prefix wins
+		{model: "Qwen/Qwen2.5-7B", want: 5},
+		{model: "mistral", want: 10}, // DefaultCharsPerToken
+	}
+	for _, test := range tests {
+		if got := estimator.Estimate(test.model, prompt, 0).PromptTokens; got != test.want {
+			t.Errorf("Prompt tokens of %s = %d, want %d", test.model, got, test.want)
+		}
+	}
+}
+
+func TestEstimateOutputTokens(t *testing.T) {
+	estimator := NewEstimator(&Config{OutputTokens: 100, OutputTokensWindow: 3})
+
+	// The default is used until a response is observed, bounded by the max tokens of the request.
+	if got := estimator.Estimate("model", "", 0).OutputTokens; got != 100 {
+		t.Errorf("Expected the default output tokens, got %d", got)
+	}
+	if got := estimator.Estimate("model", "", 50).OutputTokens; got != 50 {
+		t.Errorf("Expected the max tokens, got %d", got)
+	}
+
+	// The average moves halfway towards every observation with a window of 3.
+	estimator.Observe("model", 40)
+	estimator.Observe("model", 80)
+	estimator.Observe("model", 0) // ignored
+	if got := estimator.Estimate("model", "", 0).OutputTokens; got != 60 {
+		t.Errorf("Expected the average output tokens, got %d", got)
+	}
+	if got := estimator.Estimate("other", "", 0).OutputTokens; got != 100 {
+		t.Errorf("Expected the default output tokens for another model, got %d", got)
+	}
+}
+
+func TestParseFamilies(t *testing.T) {
+	families, err := ParseFamilies("llama=meta-llama/|llama:3.6, qwen=Qwen/:3.3,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []Family{
+		{Name: "llama", ModelPrefixes: []string{"meta-llama/", "llama"}, CharsPerToken: 3.6},
+		{Name: "qwen", ModelPrefixes: []string{"Qwen/"}, CharsPerToken: 3.3},
+	}
+	if diff := cmp.Diff(want, families); diff != "" {
+		t.Errorf("Unexpected families (-want +got): %v", diff)
+	}
+
+	for _, invalid := range []string{"llama", "llama=meta-llama/", "llama=meta-llama/:0", "llama=:3.6", "=meta-llama/:3.6"} {
+		if _, err := ParseFamilies(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing instruments the EPP with OpenTelemetry spans: the ext-proc request and its
+// phases, the scheduling of the request, the snapshot of the pods and the calls of the scheduling
+// plugins. The spans are children of the trace of the gateway, propagated by the traceparent
+// header of the request, and are exported via OTLP, configured by the standard OTEL_* environment
+// variables.
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// instrumentationName is the name of the tracer of the EPP.
+	instrumentationName = "sigs.k8s.io/gateway-api-inference-extension/pkg/epp"
+	// defaultServiceName is the service name of the spans, unless overridden by OTEL_SERVICE_NAME
+	// or OTEL_RESOURCE_ATTRIBUTES.
+	defaultServiceName = "gateway-api-inference-extension-epp"
+)
+
+// Standard environment variables of the OpenTelemetry SDK read by Enabled. The others, e.g.
+// OTEL_EXPORTER_OTLP_HEADERS or OTEL_TRACES_SAMPLER, are read by the SDK itself.
+const (
+	EnvSDKDisabled    = "OTEL_SDK_DISABLED"
+	EnvTracesExporter = "OTEL_TRACES_EXPORTER"
+	EnvEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+)
+
+// Enabled returns true if the spans are exported, i.e. an OTLP endpoint is configured and neither
+// the SDK nor the traces exporter are disabled.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv(EnvSDKDisabled), "true") || os.Getenv(EnvTracesExporter) == "none" {
+		return false
+	}
+	return os.Getenv(EnvEndpoint) != "" || os.Getenv(EnvTracesEndpoint) != ""
+}
+
+// Setup installs the global tracer provider exporting the spans via OTLP over gRPC, and the W3C
+// trace context and baggage propagators, if Enabled. It returns the function flushing the spans
+// and shutting the tracer provider down. The spans are no-ops otherwise.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The attributes from the environment override the default service name.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK())
+	if err != nil {
+		return nil, err
+	}
+	// The sampler is read from OTEL_TRACES_SAMPLER, parent based always on by default.
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	log.FromContext(ctx).Info("OpenTelemetry tracing enabled")
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the EPP.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartRequest starts the span of an ext-proc request, as a child of the span propagated by the
+// given request headers, if any.
+func StartRequest(ctx context.Context, headers propagation.TextMapCarrier) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headers)
+	return Tracer().Start(ctx, "ext_proc.request", trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// Span records a span of the given name that started at the given time and ends now, for the work
+// that is timed rather than wrapped, e.g. the calls of the scheduling plugins. It's only recorded
+// as part of a recorded trace.
+func Span(ctx context.Context, name string, start time.Time, attributes ...attribute.KeyValue) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return
+	}
+	_, span := Tracer().Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attributes...))
+	span.End()
+}
+
+// End ends the given span, recording the given error if any.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestStartRequest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	// The timed spans are only recorded as part of a recorded trace.
+	Span(context.Background(), "orphan", time.Now())
+
+	headers := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx, span := StartRequest(context.Background(), headers)
+	start := time.Now().Add(-time.Millisecond)
+	Span(ctx, "Filter/least-queue", start)
+	End(span, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Got %d spans, want 2", len(spans))
+	}
+	plugin, request := spans[0], spans[1]
+	if got := request.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Got trace ID %s, want the propagated one", got)
+	}
+	if got := request.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Got parent span ID %s, want the propagated one", got)
+	}
+	if plugin.Name() != "Filter/least-queue" || plugin.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Errorf("Got span %s with parent %s, want Filter/least-queue child of the request", plugin.Name(), plugin.Parent().SpanID())
+	}
+	if !plugin.StartTime().Equal(start) {
+		t.Errorf("Got span start %v, want %v", plugin.StartTime(), start)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "no endpoint"},
+		{name: "endpoint", env: map[string]string{EnvEndpoint: "http://collector:4317"}, want: true},
+		{name: "traces endpoint", env: map[string]string{EnvTracesEndpoint: "http://collector:4317"}, want: true},
+		{name: "sdk disabled", env: map[string]string{EnvEndpoint: "http://collector:4317", EnvSDKDisabled: "true"}},
+		{name: "no traces exporter", env: map[string]string{EnvEndpoint: "http://collector:4317", EnvTracesExporter: "none"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, key := range []string{EnvSDKDisabled, EnvTracesExporter, EnvEndpoint, EnvTracesEndpoint} {
+				t.Setenv(key, test.env[key])
+			}
+			if got := Enabled(); got != test.want {
+				t.Errorf("Enabled() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,28 @@ package error
 
 import (
 	"fmt"
+	"time"
 )
 
 // Error is an error struct for errors returned by the epp server.
 type Error struct {
 	Code string
 	Msg  string
+	// RetryAfter, if positive, is the delay after which the request is advised to be retried.
+	RetryAfter time.Duration
+	// Headers, if set, are set on the error response, e.g. the state of the rate limits of the
+	// request. It's a pointer so Error stays comparable, which errors.Is needs to match the sentinels.
+	Headers *Headers
+	// StatusCode, if positive, is the HTTP status of a ModelInactive error response.
+	StatusCode int
+	// Body, if set, is the body of the error response instead of the error, e.g. a JSON description
+	// of the error for the clients to act on.
+	Body string
 }
 
+// Headers are the HTTP headers of an error response, keyed by name.
+type Headers map[string]string
+
 const (
 	Unknown                        = "Unknown"
 	BadRequest                     = "BadRequest"
@@ -17,6 +31,12 @@ const (
 	ModelServerError               = "ModelServerError"
 	BadConfiguration               = "BadConfiguration"
 	InferencePoolResourceExhausted = "InferencePoolResourceExhausted"
+	NoCapableEndpoints             = "NoCapableEndpoints"
+	ServiceUnavailable             = "ServiceUnavailable"
+	RateLimited                    = "RateLimited"
+	// ModelInactive is returned for the requests for a model that isn't active, whose response is
+	// configured by the lifecycle of the model: its message is the body of the response.
+	ModelInactive = "ModelInactive"
 )
 
 // Error returns a string version of the error.
@@ -17,9 +17,47 @@ limitations under the License.
 package pod
 
 import (
+	"strconv"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
+// CordonAnnotationKey is the pod annotation that cordons a pod from EPP routing when set to "true".
+const CordonAnnotationKey = "inference.networking.x-k8s.io/cordoned"
+
+// MaxConcurrencyAnnotationKey is the pod annotation holding the maximum number of concurrent requests
+// the pod is sent, e.g. the point past which its model server degrades sharply.
+const MaxConcurrencyAnnotationKey = "inference.networking.x-k8s.io/max-concurrency"
+
+// TargetPortAnnotationKey is the pod annotation overriding the target port of the pool for the pod,
+// e.g. for the endpoints outside the cluster serving on another port.
+const TargetPortAnnotationKey = "inference.networking.x-k8s.io/target-port"
+
+// IsPodCordoned returns true if the pod is annotated to be excluded from EPP routing.
+func IsPodCordoned(pod *corev1.Pod) bool {
+	return pod.GetAnnotations()[CordonAnnotationKey] == "true"
+}
+
+// MaxConcurrency returns the maximum number of concurrent requests the pod is annotated with, or zero
+// if the annotation is missing or isn't a positive integer.
+func MaxConcurrency(pod *corev1.Pod) int {
+	n, err := strconv.Atoi(pod.GetAnnotations()[MaxConcurrencyAnnotationKey])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// TargetPort returns the port the pod is annotated to serve on, or zero if the annotation is missing
+// or isn't a valid port number.
+func TargetPort(pod *corev1.Pod) int32 {
+	n, err := strconv.ParseInt(pod.GetAnnotations()[TargetPortAnnotationKey], 10, 32)
+	if err != nil || n < 1 || n > 65535 {
+		return 0
+	}
+	return int32(n)
+}
+
 func IsPodReady(pod *corev1.Pod) bool {
 	if !pod.DeletionTimestamp.IsZero() {
 		return false
@@ -18,6 +18,7 @@ package request
 
 import (
 	"fmt"
+	"strings"
 
 	errutil "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/util/error"
 )
@@ -84,3 +85,62 @@ func extractPromptFromMessagesField(body map[string]interface{}) (string, error)
 func constructChatMessage(role string, content string) string {
 	return fmt.Sprintf("<|im_start|>%s\n%s<|im_end|>\n", role, content)
 }
+
+// ExtractSessionID returns the session identifier of a request: the value of the given header if
+// set, or else the first non-empty string found in the body at one of the given paths (see
+// ExtractStringFromBodyPaths). An empty header disables reading the session identifier from headers.
+func ExtractSessionID(headers map[string]string, header string, body map[string]interface{}, paths []string) string {
+	if header != "" {
+		if sessionID := headers[header]; sessionID != "" {
+			return sessionID
+		}
+	}
+	return ExtractStringFromBodyPaths(body, paths)
+}
+
+// ExtractStringFromBodyPaths returns the first non-empty string value found in the request body
+// at one of the given paths. A path is a dot separated list of JSON object keys, e.g.
+// "metadata.thread_id". Paths are tried in order; an empty string is returned if none match.
+func ExtractStringFromBodyPaths(body map[string]interface{}, paths []string) string {
+	for _, path := range paths {
+		if value, ok := extractStringAtPath(body, path); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func extractStringAtPath(body map[string]interface{}, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	keys := strings.Split(path, ".")
+	current := body
+	for i, key := range keys {
+		value, ok := current[key]
+		if !ok {
+			return "", false
+		}
+		if i == len(keys)-1 {
+			str, ok := value.(string)
+			return str, ok
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// ExtractMaxTokensFromRequestBody returns the maximum number of tokens the request allows to
+// generate, from the max_tokens or the max_completion_tokens field, or zero if neither is set.
+// The lowest wins if both are set.
+func ExtractMaxTokensFromRequestBody(body map[string]interface{}) int {
+	maxTokens := 0
+	for _, key := range []string{"max_tokens", "max_completion_tokens"} {
+		if value, ok := body[key].(float64); ok && value > 0 && (maxTokens == 0 || int(value) < maxTokens) {
+			maxTokens = int(value)
+		}
+	}
+	return maxTokens
+}
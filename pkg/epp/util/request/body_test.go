@@ -189,3 +189,136 @@ func TestConstructChatMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractStringFromBodyPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  map[string]interface{}
+		paths []string
+		want  string
+	}{
+		{
+			name: "nested metadata field",
+			body: map[string]interface{}{
+				"model":    "test",
+				"metadata": map[string]interface{}{"thread_id": "thread_abc"},
+			},
+			paths: []string{"metadata.thread_id"},
+			want:  "thread_abc",
+		},
+		{
+			name: "first matching path wins",
+			body: map[string]interface{}{
+				"thread_id": "top-level",
+				"metadata":  map[string]interface{}{"conversation_id": "conv-1"},
+			},
+			paths: []string{"metadata.thread_id", "metadata.conversation_id", "thread_id"},
+			want:  "conv-1",
+		},
+		{
+			name: "non-string value is skipped",
+			body: map[string]interface{}{
+				"metadata":  map[string]interface{}{"thread_id": float64(12)},
+				"thread_id": "fallback",
+			},
+			paths: []string{"metadata.thread_id", "thread_id"},
+			want:  "fallback",
+		},
+		{
+			name: "intermediate key is not an object",
+			body: map[string]interface{}{
+				"metadata": "not-an-object",
+			},
+			paths: []string{"metadata.thread_id"},
+			want:  "",
+		},
+		{
+			name:  "no paths configured",
+			body:  map[string]interface{}{"thread_id": "abc"},
+			paths: nil,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractStringFromBodyPaths(tt.body, tt.paths); got != tt.want {
+				t.Errorf("ExtractStringFromBodyPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractMaxTokensFromRequestBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		want int
+	}{
+		{
+			name: "max_tokens",
+			body: map[string]interface{}{"max_tokens": float64(100)},
+			want: 100,
+		},
+		{
+			name: "max_completion_tokens",
+			body: map[string]interface{}{"max_completion_tokens": float64(50)},
+			want: 50,
+		},
+		{
+			name: "lowest of both",
+			body: map[string]interface{}{"max_tokens": float64(100), "max_completion_tokens": float64(50)},
+			want: 50,
+		},
+		{
+			name: "unset or invalid",
+			body: map[string]interface{}{"max_tokens": "100", "max_completion_tokens": float64(-1)},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractMaxTokensFromRequestBody(tt.body); got != tt.want {
+				t.Errorf("ExtractMaxTokensFromRequestBody() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractSessionID(t *testing.T) {
+	body := map[string]interface{}{
+		"metadata": map[string]interface{}{"thread_id": "thread_abc"},
+	}
+	paths := []string{"metadata.thread_id"}
+	tests := []struct {
+		name    string
+		headers map[string]string
+		header  string
+		want    string
+	}{
+		{
+			name:    "header takes precedence over the body",
+			headers: map[string]string{"x-session-id": "session_1"},
+			header:  "x-session-id",
+			want:    "session_1",
+		},
+		{
+			name:   "body without the header",
+			header: "x-session-id",
+			want:   "thread_abc",
+		},
+		{
+			name:    "header disabled",
+			headers: map[string]string{"x-session-id": "session_1"},
+			want:    "thread_abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSessionID(tt.headers, tt.header, body, paths); got != tt.want {
+				t.Errorf("ExtractSessionID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
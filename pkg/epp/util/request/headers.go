@@ -8,6 +8,27 @@ import (
 
 const (
 	RequestIdHeaderKey = "x-request-id"
+	// AttemptCountHeaderKey is set by Envoy to the number of the current attempt when retries are
+	// configured on the route, starting at 1 for the original request.
+	AttemptCountHeaderKey = "x-envoy-attempt-count"
+	// DestinationEndpointHintHeaderKey can be set by a trusted client or upstream system to pin the
+	// request to a pod, given by name ("name" or "namespace/name") or address ("ip" or "ip:port").
+	DestinationEndpointHintHeaderKey = "x-gateway-destination-endpoint-hint"
+	// RouteKey is the key of the filter metadata of the ext-proc request, or of the request header
+	// if the gateway overwrites it, in which the gateway identifies the route of the request, as
+	// "namespace/name" or "namespace/name/rule".
+	RouteKey = "x-gateway-inference-route"
+	// ListenerKey is the key of the filter metadata of the ext-proc request, or of the request
+	// header if the gateway overwrites it, in which the gateway identifies the listener the request
+	// was received on, as "namespace/gateway/listener".
+	ListenerKey = "x-gateway-inference-listener"
+	// PoolKey is the key of the filter metadata of the ext-proc request, or of the request header
+	// if the gateway overwrites it, in which the gateway identifies the InferencePool targeted by
+	// the request, as "name" or "namespace/name", when the Endpoint Picker serves several pools.
+	PoolKey = "x-gateway-inference-pool"
+	// SchedulingTraceIDHeaderKey is the response header carrying the summary of the scheduling trace
+	// of the request, if enabled.
+	SchedulingTraceIDHeaderKey = "x-gateway-scheduling-trace-id"
 )
 
 func ExtractHeaderValue(req *extProcPb.ProcessingRequest_RequestHeaders, headerKey string) string {
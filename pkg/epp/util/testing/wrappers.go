@@ -155,6 +155,21 @@ func (m *InferenceModelWrapper) Criticality(criticality v1alpha2.Criticality) *I
 	return m
 }
 
+func (m *InferenceModelWrapper) Lifecycle(lifecycle v1alpha2.ModelLifecycle) *InferenceModelWrapper {
+	m.Spec.Lifecycle = &lifecycle
+	return m
+}
+
+func (m *InferenceModelWrapper) Match(match v1alpha2.ModelMatch) *InferenceModelWrapper {
+	m.Spec.Matches = append(m.Spec.Matches, match)
+	return m
+}
+
+func (m *InferenceModelWrapper) Aliases(aliases ...string) *InferenceModelWrapper {
+	m.Spec.Aliases = append(m.Spec.Aliases, aliases...)
+	return m
+}
+
 func (m *InferenceModelWrapper) DeletionTimestamp() *InferenceModelWrapper {
 	now := metav1.Now()
 	m.ObjectMeta.DeletionTimestamp = &now
@@ -198,6 +213,11 @@ func (m *InferencePoolWrapper) Selector(selector map[string]string) *InferencePo
 	return m
 }
 
+func (m *InferencePoolWrapper) Labels(labels map[string]string) *InferencePoolWrapper {
+	m.ObjectMeta.Labels = labels
+	return m
+}
+
 func (m *InferencePoolWrapper) TargetPortNumber(p int32) *InferencePoolWrapper {
 	m.Spec.TargetPortNumber = p
 	return m
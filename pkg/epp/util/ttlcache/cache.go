@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ttlcache provides a bounded map whose entries expire after a period of inactivity, e.g.
+// to keep per-request or per-session state without a sweep loop.
+package ttlcache
+
+import (
+	"container/list"
+	"time"
+)
+
+// Cache is a map whose entries expire once they haven't been set for the TTL. It holds at most
+// maxSize entries, evicting the least recently set ones beyond. The expired entries are dropped
+// when they are looked up or when an entry is set, so the cache does no work in the background.
+// The times are given by the callers, so they can use their own clock. It's not safe for
+// concurrent use.
+type Cache[K comparable, V any] struct {
+	ttl     time.Duration
+	maxSize int
+	entries map[K]*list.Element
+	order   *list.List // of *entry[K, V], least recently set first
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	setAt time.Time
+}
+
+// New returns a new Cache whose entries expire after the given TTL, holding at most maxSize
+// entries, or an unbounded number if maxSize is not positive.
+func New[K comparable, V any](ttl time.Duration, maxSize int) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value of the given key, if it's set and hasn't expired at the given time.
+func (c *Cache[K, V]) Get(key K, now time.Time) (V, bool) {
+	e, ok := c.lookup(key, now)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// LastSet returns when the given key was last set, if it's set and hasn't expired at the given
+// time.
+func (c *Cache[K, V]) LastSet(key K, now time.Time) (time.Time, bool) {
+	e, ok := c.lookup(key, now)
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.setAt, true
+}
+
+// Set sets the value of the given key as of the given time, from which the entry expires. The
+// entries expired at that time are dropped, then the least recently set ones beyond the maximum
+// size.
+func (c *Cache[K, V]) Set(key K, value V, at time.Time) {
+	c.Delete(key)
+	e := &entry[K, V]{key: key, value: value, setAt: at}
+	// The entries are usually set in chronological order, so the position is found right away.
+	mark := c.order.Back()
+	for mark != nil && mark.Value.(*entry[K, V]).setAt.After(at) {
+		mark = mark.Prev()
+	}
+	if mark == nil {
+		c.entries[key] = c.order.PushFront(e)
+	} else {
+		c.entries[key] = c.order.InsertAfter(e, mark)
+	}
+
+	for front := c.order.Front(); front != nil && c.expired(front.Value.(*entry[K, V]), at); front = c.order.Front() {
+		c.remove(front)
+	}
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		c.remove(c.order.Front())
+	}
+}
+
+// Delete deletes the given key, if it's set.
+func (c *Cache[K, V]) Delete(key K) {
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of entries, including the expired ones not dropped yet.
+func (c *Cache[K, V]) Len() int {
+	return c.order.Len()
+}
+
+// Range calls f for every entry that hasn't expired at the given time, least recently set first,
+// with the time it was last set.
+func (c *Cache[K, V]) Range(now time.Time, f func(key K, value V, setAt time.Time)) {
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		if e := element.Value.(*entry[K, V]); !c.expired(e, now) {
+			f(e.key, e.value, e.setAt)
+		}
+	}
+}
+
+func (c *Cache[K, V]) lookup(key K, now time.Time) (*entry[K, V], bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := element.Value.(*entry[K, V])
+	if c.expired(e, now) {
+		c.remove(element)
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V], now time.Time) bool {
+	return now.Sub(e.setAt) > c.ttl
+}
+
+func (c *Cache[K, V]) remove(element *list.Element) {
+	delete(c.entries, element.Value.(*entry[K, V]).key)
+	c.order.Remove(element)
+}
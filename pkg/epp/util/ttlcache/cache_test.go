@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttlcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	keys := func(c *Cache[string, int], now time.Time) []string {
+		got := []string{}
+		c.Range(now, func(key string, _ int, _ time.Time) { got = append(got, key) })
+		return got
+	}
+
+	c := New[string, int](time.Minute, 3)
+	c.Set("a", 1, at(0))
+	c.Set("b", 2, at(10*time.Second))
+	c.Set("c", 3, at(20*time.Second))
+
+	value, ok := c.Get("a", at(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	_, ok = c.Get("a", at(61*time.Second))
+	assert.False(t, ok, "expired entry")
+	assert.Equal(t, 2, c.Len(), "the expired entry is dropped when looked up")
+
+	// Setting an entry again refreshes it.
+	c.Set("b", 20, at(65*time.Second))
+	assert.Equal(t, []string{"c", "b"}, keys(c, at(65*time.Second)))
+	lastSet, ok := c.LastSet("b", at(65*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, at(65*time.Second), lastSet)
+
+	// The entries expired when an entry is set are dropped.
+	c.Set("d", 4, at(90*time.Second))
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, []string{"b", "d"}, keys(c, at(90*time.Second)))
+
+	// The least recently set entries are evicted beyond the maximum size.
+	c.Set("e", 5, at(91*time.Second))
+	c.Set("f", 6, at(92*time.Second))
+	assert.Equal(t, []string{"d", "e", "f"}, keys(c, at(92*time.Second)))
+
+	// An entry set in the past is ordered by the time it was set.
+	c.Set("g", 7, at(90500*time.Millisecond))
+	assert.Equal(t, []string{"g", "e", "f"}, keys(c, at(92*time.Second)))
+
+	c.Delete("e")
+	c.Delete("does-not-exist")
+	assert.Equal(t, []string{"g", "f"}, keys(c, at(92*time.Second)))
+	assert.Equal(t, []string{"f"}, keys(c, at(151*time.Second)))
+}
+
+func TestCacheUnbounded(t *testing.T) {
+	now := time.Now()
+	c := New[int, int](time.Minute, 0)
+	for i := range 1000 {
+		c.Set(i, i, now)
+	}
+	assert.Equal(t, 1000, c.Len())
+}
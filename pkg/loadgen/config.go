@@ -0,0 +1,299 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loadgen generates synthetic OpenAI-compatible traffic against a gateway, with the
+// arrival process, prompt lengths and output lengths drawn from configurable distributions, to
+// validate scheduling changes end to end with realistic LLM traffic shapes.
+//
+// Every request is tagged with an x-request-id made of the ID of the run and its sequence number,
+// so that its result can be correlated with the logs of EPP and its record in the request lookup
+// endpoint (see the requestlookup package).
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIChat sends the requests to /v1/chat/completions.
+	APIChat = "chat"
+	// APICompletions sends the requests to /v1/completions.
+	APICompletions = "completions"
+)
+
+const (
+	// ArrivalPoisson spaces the requests by exponentially distributed intervals, i.e. the requests
+	// arrive independently of each other at the given average rate.
+	ArrivalPoisson = "poisson"
+	// ArrivalConstant spaces the requests evenly at the given rate.
+	ArrivalConstant = "constant"
+	// ArrivalBurst sends the requests in bursts of BurstSize requests, spaced to average the given
+	// rate.
+	ArrivalBurst = "burst"
+)
+
+// The types of Distribution.
+const (
+	DistributionFixed       = "fixed"
+	DistributionUniform     = "uniform"
+	DistributionNormal      = "normal"
+	DistributionLogNormal   = "lognormal"
+	DistributionExponential = "exponential"
+)
+
+const (
+	// RunIDHeader is the header holding the ID of the run on every request.
+	RunIDHeader = "x-loadgen-run-id"
+
+	DefaultTimeout        = 5 * time.Minute
+	DefaultPromptTokens   = 256
+	DefaultMaxTokens      = 128
+	DefaultMaxConcurrency = 1000
+)
+
+// Config is the configuration of a load generation run, e.g.
+//
+//	target: http://gateway.example.com
+//	api: chat
+//	models:
+//	- name: llama
+//	  weight: 3
+//	- name: llama-lora
+//	  weight: 1
+//	arrival:
+//	  type: poisson
+//	  rate: 20
+//	duration: 5m
+//	promptTokens:
+//	  type: lognormal
+//	  mean: 1000
+//	  stddev: 800
+//	  max: 8000
+//	maxTokens:
+//	  type: uniform
+//	  min: 50
+//	  max: 500
+//	stream: true
+type Config struct {
+	// Target is the base URL of the gateway.
+	Target string `json:"target"`
+	// API is the OpenAI API the requests are sent to, "chat" (the default) or "completions".
+	API string `json:"api,omitempty"`
+	// Models are the models the requests are spread over by weight.
+	Models []ModelConfig `json:"models"`
+	// Arrival is the arrival process of the requests.
+	Arrival ArrivalConfig `json:"arrival"`
+	// Duration is how long requests are sent for, and Requests the number of requests sent; the
+	// run ends with whichever is reached first, at least one of them is required.
+	Duration metav1.Duration `json:"duration,omitempty"`
+	Requests int             `json:"requests,omitempty"`
+	// PromptTokens is the distribution of the number of tokens of the prompts, DefaultPromptTokens
+	// if unset. The prompts are made of words approximating a token each.
+	PromptTokens Distribution `json:"promptTokens,omitempty"`
+	// SharedPrefixTokens, if positive, is the number of tokens at the start of every prompt that
+	// are shared by the requests for the same model, e.g. a system prompt, so that the prefix
+	// cache aware scheduling is exercised. It's part of the prompt tokens.
+	SharedPrefixTokens int `json:"sharedPrefixTokens,omitempty"`
+	// MaxTokens is the distribution of the max_tokens of the requests, DefaultMaxTokens if unset.
+	MaxTokens Distribution `json:"maxTokens,omitempty"`
+	// Stream requests streamed responses, whose time to first token is then measured.
+	Stream bool `json:"stream,omitempty"`
+	// Headers are added to every request, e.g. the authorization of the gateway.
+	Headers map[string]string `json:"headers,omitempty"`
+	// MaxConcurrency is the maximum number of requests in flight, DefaultMaxConcurrency if not
+	// positive. The arrivals above it are dropped rather than delayed, so that the arrival process
+	// isn't distorted by a slow gateway, and counted as such.
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+	// Timeout is the timeout of every request, DefaultTimeout if unset.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// Seed seeds the random draws, so that runs with the same seed send the same requests at the
+	// same times. A random seed is used if zero.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// ModelConfig is a model the requests are sent to.
+type ModelConfig struct {
+	Name string `json:"name"`
+	// Weight is the relative share of the requests sent to the model, 1 if not positive.
+	Weight int `json:"weight,omitempty"`
+	// Headers are added to the requests for the model, e.g. to set their criticality.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ArrivalConfig is the arrival process of the requests.
+type ArrivalConfig struct {
+	// Type is "poisson" (the default), "constant" or "burst".
+	Type string `json:"type,omitempty"`
+	// Rate is the average number of requests per second.
+	Rate float64 `json:"rate"`
+	// BurstSize is the number of requests of every burst of the "burst" arrival process.
+	BurstSize int `json:"burstSize,omitempty"`
+}
+
+// Distribution is a distribution of integer values, e.g. of the number of tokens of the prompts.
+// The samples are rounded, and clamped to [Min, Max] (Max is ignored if zero), and to at least 1.
+type Distribution struct {
+	// Type is "fixed" (the default), "uniform", "normal", "lognormal" or "exponential".
+	Type string `json:"type,omitempty"`
+	// Mean is the value of the fixed distribution, and the mean of the other ones but the uniform
+	// one, which draws from [Min, Max].
+	Mean float64 `json:"mean,omitempty"`
+	// StdDev is the standard deviation of the normal and lognormal distributions.
+	StdDev float64 `json:"stddev,omitempty"`
+	Min    int     `json:"min,omitempty"`
+	Max    int     `json:"max,omitempty"`
+}
+
+// LoadConfig parses the given YAML or JSON Config, and sets the defaults of its unset fields.
+// Unknown fields are rejected. The config must then be validated with Validate, once adjusted if
+// needed (e.g. its target overridden).
+func LoadConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse load generator config: %w", err)
+	}
+	config.setDefaults()
+	return config, nil
+}
+
+func (c *Config) setDefaults() {
+	if c.API == "" {
+		c.API = APIChat
+	}
+	if c.Arrival.Type == "" {
+		c.Arrival.Type = ArrivalPoisson
+	}
+	if c.PromptTokens == (Distribution{}) {
+		c.PromptTokens = Distribution{Mean: DefaultPromptTokens}
+	}
+	if c.MaxTokens == (Distribution{}) {
+		c.MaxTokens = Distribution{Mean: DefaultMaxTokens}
+	}
+	if c.MaxConcurrency <= 0 {
+		c.MaxConcurrency = DefaultMaxConcurrency
+	}
+	if c.Timeout.Duration <= 0 {
+		c.Timeout.Duration = DefaultTimeout
+	}
+	for i := range c.Models {
+		if c.Models[i].Weight <= 0 {
+			c.Models[i].Weight = 1
+		}
+	}
+}
+
+// Validate reports all the problems of the config at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Target == "" {
+		errs = append(errs, errors.New("target is required"))
+	}
+	if c.API != APIChat && c.API != APICompletions {
+		errs = append(errs, fmt.Errorf("unknown api '%s'", c.API))
+	}
+	if len(c.Models) == 0 {
+		errs = append(errs, errors.New("at least one model is required"))
+	}
+	for i, model := range c.Models {
+		if model.Name == "" {
+			errs = append(errs, fmt.Errorf("model #%d: name is required", i))
+		}
+	}
+	switch c.Arrival.Type {
+	case ArrivalPoisson, ArrivalConstant:
+	case ArrivalBurst:
+		if c.Arrival.BurstSize <= 0 {
+			errs = append(errs, fmt.Errorf("arrival: non-positive burst size %d", c.Arrival.BurstSize))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("arrival: unknown type '%s'", c.Arrival.Type))
+	}
+	if c.Arrival.Rate <= 0 {
+		errs = append(errs, fmt.Errorf("arrival: non-positive rate %v", c.Arrival.Rate))
+	}
+	if c.Duration.Duration <= 0 && c.Requests <= 0 {
+		errs = append(errs, errors.New("a duration or a number of requests is required"))
+	}
+	if err := c.PromptTokens.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("promptTokens: %w", err))
+	}
+	if c.SharedPrefixTokens < 0 {
+		errs = append(errs, fmt.Errorf("negative shared prefix tokens %d", c.SharedPrefixTokens))
+	}
+	if err := c.MaxTokens.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("maxTokens: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (d Distribution) validate() error {
+	if d.Min < 0 || d.Max < 0 || (d.Max > 0 && d.Min > d.Max) {
+		return fmt.Errorf("invalid bounds: want 0 <= min (%d) <= max (%d)", d.Min, d.Max)
+	}
+	switch d.Type {
+	case "", DistributionFixed, DistributionExponential:
+		if d.Mean <= 0 {
+			return fmt.Errorf("non-positive mean %v", d.Mean)
+		}
+	case DistributionUniform:
+		if d.Max <= 0 {
+			return errors.New("max is required")
+		}
+	case DistributionNormal, DistributionLogNormal:
+		if d.Mean <= 0 || d.StdDev < 0 {
+			return fmt.Errorf("non-positive mean %v or negative stddev %v", d.Mean, d.StdDev)
+		}
+	default:
+		return fmt.Errorf("unknown type '%s'", d.Type)
+	}
+	return nil
+}
+
+// Sample draws a value from the distribution.
+func (d Distribution) Sample(r *rand.Rand) int {
+	var v float64
+	switch d.Type {
+	case DistributionUniform:
+		v = float64(d.Min) + r.Float64()*float64(d.Max-d.Min)
+	case DistributionNormal:
+		v = d.Mean + r.NormFloat64()*d.StdDev
+	case DistributionLogNormal:
+		// The parameters of the underlying normal distribution giving the requested mean and
+		// standard deviation.
+		sigma2 := math.Log(1 + (d.StdDev*d.StdDev)/(d.Mean*d.Mean))
+		mu := math.Log(d.Mean) - sigma2/2
+		v = math.Exp(mu + r.NormFloat64()*math.Sqrt(sigma2))
+	case DistributionExponential:
+		v = r.ExpFloat64() * d.Mean
+	default:
+		v = d.Mean
+	}
+	n := int(math.Round(v))
+	if n < d.Min {
+		n = d.Min
+	}
+	if d.Max > 0 && n > d.Max {
+		n = d.Max
+	}
+	return max(n, 1)
+}
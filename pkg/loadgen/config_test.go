@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig([]byte(`
+target: http://gateway
+models:
+- name: llama
+- name: llama-lora
+  weight: 3
+arrival:
+  rate: 10
+requests: 100
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, APIChat, config.API)
+	assert.Equal(t, ArrivalPoisson, config.Arrival.Type)
+	assert.Equal(t, Distribution{Mean: DefaultPromptTokens}, config.PromptTokens)
+	assert.Equal(t, Distribution{Mean: DefaultMaxTokens}, config.MaxTokens)
+	assert.Equal(t, DefaultMaxConcurrency, config.MaxConcurrency)
+	assert.Equal(t, DefaultTimeout, config.Timeout.Duration)
+	assert.Equal(t, []ModelConfig{{Name: "llama", Weight: 1}, {Name: "llama-lora", Weight: 3}}, config.Models)
+
+	_, err = LoadConfig([]byte(`{target: http://gateway, rates: 1}`))
+	assert.ErrorContains(t, err, `unknown field "rates"`)
+
+	config, err = LoadConfig([]byte(`
+api: embeddings
+models:
+- weight: 1
+arrival:
+  type: burst
+  rate: 0
+promptTokens:
+  type: uniform
+  min: 10
+sharedPrefixTokens: -1
+maxTokens:
+  type: zipf
+  mean: 10
+`))
+	assert.NoError(t, err)
+	err = config.Validate()
+	for _, wantErr := range []string{
+		"target is required",
+		"unknown api 'embeddings'",
+		"model #0: name is required",
+		"arrival: non-positive burst size 0",
+		"arrival: non-positive rate 0",
+		"a duration or a number of requests is required",
+		"promptTokens: max is required",
+		"negative shared prefix tokens -1",
+		"maxTokens: unknown type 'zipf'",
+	} {
+		assert.ErrorContains(t, err, wantErr)
+	}
+}
+
+func TestDistributionSample(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name         string
+		distribution Distribution
+		wantMin      int
+		wantMax      int
+	}{
+		{name: "fixed", distribution: Distribution{Mean: 100}, wantMin: 100, wantMax: 100},
+		{name: "uniform", distribution: Distribution{Type: DistributionUniform, Min: 10, Max: 20}, wantMin: 10, wantMax: 20},
+		{name: "normal", distribution: Distribution{Type: DistributionNormal, Mean: 100, StdDev: 50, Min: 80, Max: 120}, wantMin: 80, wantMax: 120},
+		{name: "lognormal", distribution: Distribution{Type: DistributionLogNormal, Mean: 1000, StdDev: 2000, Max: 4000}, wantMin: 1, wantMax: 4000},
+		{name: "exponential", distribution: Distribution{Type: DistributionExponential, Mean: 5}, wantMin: 1, wantMax: 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for range 1000 {
+				v := test.distribution.Sample(rnd)
+				assert.GreaterOrEqual(t, v, test.wantMin)
+				assert.LessOrEqual(t, v, test.wantMax)
+			}
+		})
+	}
+
+	// The lognormal samples average to the requested mean.
+	d := Distribution{Type: DistributionLogNormal, Mean: 1000, StdDev: 500}
+	sum := 0
+	for range 10000 {
+		sum += d.Sample(rnd)
+	}
+	assert.InDelta(t, 1000, float64(sum)/10000, 50)
+}
+
+func TestPromptGenerator(t *testing.T) {
+	g := newPromptGenerator(rand.New(rand.NewSource(1)), 5)
+	a := strings.Fields(g.generate("llama", 20))
+	b := strings.Fields(g.generate("llama", 20))
+	c := strings.Fields(g.generate("qwen", 3))
+	assert.Len(t, a, 20)
+	assert.Len(t, b, 20)
+	assert.Len(t, c, 3)
+	assert.Equal(t, a[:5], b[:5], "prompts for the same model share their prefix")
+	assert.NotEqual(t, a, b)
+}
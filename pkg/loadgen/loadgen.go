@@ -0,0 +1,371 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxErrorSize is the maximum size of the response body kept as the error of a failed request.
+	maxErrorSize = 256
+	// maxEventSize is the maximum size of a server-sent event of a streamed response.
+	maxEventSize = 1024 * 1024
+)
+
+// Result is the result of a request, written as a line of JSON to the results of the run.
+type Result struct {
+	// RequestID is the x-request-id of the request, made of the ID of the run and the sequence
+	// number of the request.
+	RequestID string    `json:"request_id"`
+	Model     string    `json:"model"`
+	Start     time.Time `json:"start"`
+	// PromptTokens and MaxTokens are the ones drawn for the request, while the usage fields are the
+	// ones reported by the model server, if any.
+	PromptTokens          int `json:"prompt_tokens"`
+	MaxTokens             int `json:"max_tokens"`
+	UsagePromptTokens     int `json:"usage_prompt_tokens,omitempty"`
+	UsageCompletionTokens int `json:"usage_completion_tokens,omitempty"`
+	// Dropped is set if the request wasn't sent because the maximum concurrency was reached.
+	Dropped    bool   `json:"dropped,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// LatencyMillis is the time until the response was complete, and TTFTMillis the time until the
+	// first event of the streamed responses.
+	LatencyMillis float64 `json:"latency_ms,omitempty"`
+	TTFTMillis    float64 `json:"ttft_ms,omitempty"`
+}
+
+// Succeeded returns whether the request was sent and completed successfully.
+func (r *Result) Succeeded() bool {
+	return !r.Dropped && r.Error == "" && r.StatusCode == http.StatusOK
+}
+
+// request is a request drawn by the runner.
+type request struct {
+	id           string
+	model        ModelConfig
+	promptTokens int
+	maxTokens    int
+	prompt       string
+}
+
+// Runner runs the load generation of a Config.
+type Runner struct {
+	config  *Config
+	runID   string
+	client  *http.Client
+	results io.Writer
+}
+
+// NewRunner returns a new Runner sending the requests of the given valid config, tagged with the
+// given run ID. The result of every request is written as a line of JSON to results, if not nil.
+func NewRunner(config *Config, runID string, results io.Writer) *Runner {
+	return &Runner{
+		config:  config,
+		runID:   runID,
+		client:  &http.Client{Timeout: config.Timeout.Duration},
+		results: results,
+	}
+}
+
+// Run sends the requests until the duration or number of requests of the config is reached, or
+// the given context is done, waits for the requests in flight and returns the summary of the run.
+func (r *Runner) Run(ctx context.Context) (*Summary, error) {
+	seed := r.config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	prompts := newPromptGenerator(rnd, r.config.SharedPrefixTokens)
+	totalWeight := 0
+	for _, model := range r.config.Models {
+		totalWeight += model.Weight
+	}
+
+	summary := newSummary(r.runID)
+	results := make(chan *Result)
+	collected := make(chan error)
+	go func() {
+		var writeErr error
+		encoder := (*json.Encoder)(nil)
+		if r.results != nil {
+			encoder = json.NewEncoder(r.results)
+		}
+		for result := range results {
+			summary.add(result)
+			if encoder != nil && writeErr == nil {
+				writeErr = encoder.Encode(result)
+			}
+		}
+		collected <- writeErr
+	}()
+
+	inFlight := make(chan struct{}, r.config.MaxConcurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	next := start
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for seq := 0; r.config.Requests <= 0 || seq < r.config.Requests; seq++ {
+		if seq > 0 {
+			next = next.Add(r.interval(rnd, seq))
+		}
+		if r.config.Duration.Duration > 0 && next.Sub(start) >= r.config.Duration.Duration {
+			break
+		}
+		if !r.waitUntil(ctx, timer, next) {
+			break
+		}
+		req := r.draw(rnd, prompts, totalWeight, seq)
+		select {
+		case inFlight <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results <- r.send(ctx, req)
+				<-inFlight
+			}()
+		default:
+			results <- &Result{
+				RequestID:    req.id,
+				Model:        req.model.Name,
+				Start:        time.Now(),
+				PromptTokens: req.promptTokens,
+				MaxTokens:    req.maxTokens,
+				Dropped:      true,
+			}
+		}
+	}
+	wg.Wait()
+	close(results)
+	err := <-collected
+	summary.finish(time.Since(start))
+	if err != nil {
+		return summary, fmt.Errorf("failed to write the results: %w", err)
+	}
+	return summary, nil
+}
+
+// interval returns the time between the arrival of the request of the given sequence number and
+// the previous one.
+func (r *Runner) interval(rnd *rand.Rand, seq int) time.Duration {
+	arrival := r.config.Arrival
+	var seconds float64
+	switch arrival.Type {
+	case ArrivalConstant:
+		seconds = 1 / arrival.Rate
+	case ArrivalBurst:
+		if seq%arrival.BurstSize != 0 {
+			return 0
+		}
+		seconds = float64(arrival.BurstSize) / arrival.Rate
+	default:
+		seconds = rnd.ExpFloat64() / arrival.Rate
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// waitUntil waits until the given time, and returns false if the context is done first.
+func (r *Runner) waitUntil(ctx context.Context, timer *time.Timer, t time.Time) bool {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(time.Until(t))
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// draw draws the request of the given sequence number. The draws happen in sequence, so that the
+// runs with the same seed send the same requests.
+func (r *Runner) draw(rnd *rand.Rand, prompts *promptGenerator, totalWeight, seq int) *request {
+	model := r.config.Models[0]
+	pick := rnd.Intn(totalWeight)
+	for _, m := range r.config.Models {
+		if pick < m.Weight {
+			model = m
+			break
+		}
+		pick -= m.Weight
+	}
+	promptTokens := r.config.PromptTokens.Sample(rnd)
+	return &request{
+		id:           r.runID + "-" + strconv.Itoa(seq),
+		model:        model,
+		promptTokens: promptTokens,
+		maxTokens:    r.config.MaxTokens.Sample(rnd),
+		prompt:       prompts.generate(model.Name, promptTokens),
+	}
+}
+
+// send sends the given request and returns its result.
+func (r *Runner) send(ctx context.Context, req *request) *Result {
+	result := &Result{
+		RequestID:    req.id,
+		Model:        req.model.Name,
+		Start:        time.Now(),
+		PromptTokens: req.promptTokens,
+		MaxTokens:    req.maxTokens,
+	}
+	httpReq, err := r.newHTTPRequest(ctx, req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorSize))
+		result.Error = strings.TrimSpace(string(body))
+		if result.Error == "" {
+			result.Error = resp.Status
+		}
+		result.LatencyMillis = millisSince(result.Start)
+		return result
+	}
+
+	var usage *usage
+	if r.config.Stream {
+		usage, err = readStream(resp.Body, func() { result.TTFTMillis = millisSince(result.Start) })
+	} else {
+		usage, err = readResponse(resp.Body)
+	}
+	result.LatencyMillis = millisSince(result.Start)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if usage != nil {
+		result.UsagePromptTokens = usage.PromptTokens
+		result.UsageCompletionTokens = usage.CompletionTokens
+	}
+	return result
+}
+
+func (r *Runner) newHTTPRequest(ctx context.Context, req *request) (*http.Request, error) {
+	body := map[string]any{
+		"model":      req.model.Name,
+		"max_tokens": req.maxTokens,
+	}
+	path := "/v1/chat/completions"
+	if r.config.API == APICompletions {
+		path = "/v1/completions"
+		body["prompt"] = req.prompt
+	} else {
+		body["messages"] = []map[string]string{{"role": "user", "content": req.prompt}}
+	}
+	if r.config.Stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]bool{"include_usage": true}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(r.config.Target, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.config.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	for name, value := range req.model.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-request-id", req.id)
+	httpReq.Header.Set(RunIDHeader, r.runID)
+	return httpReq, nil
+}
+
+// usage is the usage of an OpenAI response.
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+func readResponse(body io.Reader) (*usage, error) {
+	var resp struct {
+		Usage *usage `json:"usage"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %w", err)
+	}
+	return resp.Usage, nil
+}
+
+// readStream reads the server-sent events of a streamed response, calling firstEvent on the first
+// one, and returns the usage of the last event reporting it.
+func readStream(body io.Reader, firstEvent func()) (*usage, error) {
+	var last *usage
+	first := true
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		if first {
+			firstEvent()
+			first = false
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var event struct {
+			Usage *usage `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return last, fmt.Errorf("failed to decode the event: %w", err)
+		}
+		if event.Usage != nil {
+			last = event.Usage
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return last, fmt.Errorf("failed to read the response: %w", err)
+	}
+	return last, nil
+}
+
+func millisSince(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeServer is a fake OpenAI server recording the requests it receives.
+type fakeServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []map[string]any
+	delay    time.Duration
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := map[string]any{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, body)
+	s.mu.Unlock()
+	time.Sleep(s.delay)
+
+	if body["model"] == "overloaded" {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+	if body["stream"] == true {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":3}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		return
+	}
+	fmt.Fprint(w, `{"choices":[{"message":{"content":"hi"}}],"usage":{"prompt_tokens":7,"completion_tokens":5}}`)
+}
+
+func newTestConfig(target string) *Config {
+	config := &Config{
+		Target:   target,
+		Models:   []ModelConfig{{Name: "llama", Headers: map[string]string{"x-criticality": "critical"}}},
+		Arrival:  ArrivalConfig{Type: ArrivalConstant, Rate: 1000},
+		Requests: 10,
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+		Seed:     1,
+	}
+	config.setDefaults()
+	return config
+}
+
+func readResults(t *testing.T, data []byte) map[string]*Result {
+	results := map[string]*Result{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		result := &Result{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), result))
+		results[result.RequestID] = result
+	}
+	return results
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		api       string
+		stream    bool
+		wantPath  string
+		wantUsage int
+	}{
+		{name: "chat", api: APIChat, wantPath: "/v1/chat/completions", wantUsage: 5},
+		{name: "completions", api: APICompletions, wantPath: "/v1/completions", wantUsage: 5},
+		{name: "streamed chat", api: APIChat, stream: true, wantPath: "/v1/chat/completions", wantUsage: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &fakeServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+			config := newTestConfig(ts.URL)
+			config.API = test.api
+			config.Stream = test.stream
+			assert.NoError(t, config.Validate())
+
+			var out bytes.Buffer
+			summary, err := NewRunner(config, "run", &out).Run(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 10, summary.Requests)
+			assert.Equal(t, 10, summary.Succeeded)
+			assert.Equal(t, map[int]int{http.StatusOK: 10}, summary.StatusCodes)
+			assert.Equal(t, 10*test.wantUsage, summary.CompletionTokens)
+			assert.Positive(t, summary.Latency.P50)
+			assert.Equal(t, test.stream, summary.TTFT.P50 > 0)
+
+			results := readResults(t, out.Bytes())
+			assert.Len(t, results, 10)
+			assert.Len(t, server.requests, 10)
+			for i, r := range server.requests {
+				assert.Equal(t, test.wantPath, r.URL.Path)
+				assert.Equal(t, "run", r.Header.Get(RunIDHeader))
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				assert.Equal(t, "critical", r.Header.Get("x-criticality"))
+				result := results[r.Header.Get("x-request-id")]
+				if assert.NotNil(t, result, "every request is tagged with the ID of its result") {
+					assert.Equal(t, test.wantUsage, result.UsageCompletionTokens)
+					assert.Equal(t, float64(result.MaxTokens), server.bodies[i]["max_tokens"])
+				}
+			}
+		})
+	}
+}
+
+func TestRunFailuresAndDrops(t *testing.T) {
+	server := &fakeServer{delay: 200 * time.Millisecond}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	config := newTestConfig(ts.URL)
+	config.Models = []ModelConfig{{Name: "overloaded", Weight: 1}}
+	config.Requests = 3
+	config.MaxConcurrency = 1
+
+	var out bytes.Buffer
+	summary, err := NewRunner(config, "run", &out).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, summary.Requests)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 2, summary.Dropped)
+	assert.Equal(t, map[int]int{http.StatusTooManyRequests: 1}, summary.StatusCodes)
+	result := readResults(t, out.Bytes())["run-0"]
+	if assert.NotNil(t, result) {
+		assert.Equal(t, "too many requests", result.Error)
+	}
+}
+
+func TestRunDuration(t *testing.T) {
+	ts := httptest.NewServer(&fakeServer{})
+	defer ts.Close()
+	config := newTestConfig(ts.URL)
+	config.Requests = 0
+	config.Duration = metav1.Duration{Duration: 100 * time.Millisecond}
+	config.Arrival = ArrivalConfig{Type: ArrivalBurst, Rate: 100, BurstSize: 5}
+
+	// Bursts of 5 requests every 50ms.
+	summary, err := NewRunner(config, "run", nil).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 10, summary.Requests)
+
+	// The run stops when the context is done.
+	config.Duration = metav1.Duration{Duration: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	summary, err = NewRunner(config, "run", nil).Run(ctx)
+	assert.NoError(t, err)
+	assert.Less(t, summary.Duration, time.Minute)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// words are the words the prompts are made of, each approximating a token.
+var words = strings.Fields(`the of and to in is was for on that with as by at from his her he she
+it an be this are which or had not but were have their one all they been has there when who more
+would will can if no out so said what up its about into than them only other new some could time
+these two may then do first any my now such like our over man me even most made after also did
+many before must through back years where much your way well down should because each just those
+people how too little state good very make world still own see men work long get here between both
+life being under never day same another know while last might us great old year off come since
+against go came right used take three`)
+
+// promptGenerator generates the prompts of the requests.
+type promptGenerator struct {
+	rand *rand.Rand
+	// prefixes are the shared prefixes of the prompts by model.
+	prefixes map[string][]string
+	// prefixTokens is the number of tokens of the shared prefixes.
+	prefixTokens int
+}
+
+func newPromptGenerator(r *rand.Rand, prefixTokens int) *promptGenerator {
+	return &promptGenerator{rand: r, prefixes: map[string][]string{}, prefixTokens: prefixTokens}
+}
+
+// generate returns a prompt of the given number of tokens for the given model, starting with the
+// shared prefix of the model.
+func (g *promptGenerator) generate(model string, tokens int) string {
+	prompt := make([]string, 0, tokens)
+	if g.prefixTokens > 0 {
+		prefix, ok := g.prefixes[model]
+		if !ok {
+			prefix = g.words(g.prefixTokens)
+			g.prefixes[model] = prefix
+		}
+		prompt = append(prompt, prefix[:min(len(prefix), tokens)]...)
+	}
+	prompt = append(prompt, g.words(tokens-len(prompt))...)
+	return strings.Join(prompt, " ")
+}
+
+func (g *promptGenerator) words(n int) []string {
+	w := make([]string, n)
+	for i := range w {
+		w[i] = words[g.rand.Intn(len(words))]
+	}
+	return w
+}
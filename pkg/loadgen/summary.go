@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Summary summarizes the results of a run.
+type Summary struct {
+	RunID    string
+	Duration time.Duration
+	// Requests is the number of requests drawn, Dropped the ones that weren't sent because the
+	// maximum concurrency was reached, Succeeded and Failed the ones that were sent.
+	Requests  int
+	Dropped   int
+	Succeeded int
+	Failed    int
+	// StatusCodes counts the requests sent by the status code of their response, 0 if they got no
+	// response.
+	StatusCodes map[int]int
+	// CompletionTokens is the total of the completion tokens reported by the successful responses.
+	CompletionTokens int
+	// Latency and TTFT are the percentiles of the latency and time to first token of the
+	// successful requests, in milliseconds. TTFT is only measured for the streamed responses.
+	Latency Percentiles
+	TTFT    Percentiles
+
+	latencies []float64
+	ttfts     []float64
+}
+
+// Percentiles are percentiles of a distribution.
+type Percentiles struct {
+	P50, P90, P99, Max float64
+}
+
+func newSummary(runID string) *Summary {
+	return &Summary{RunID: runID, StatusCodes: map[int]int{}}
+}
+
+func (s *Summary) add(result *Result) {
+	s.Requests++
+	switch {
+	case result.Dropped:
+		s.Dropped++
+		return
+	case result.Succeeded():
+		s.Succeeded++
+		s.CompletionTokens += result.UsageCompletionTokens
+		s.latencies = append(s.latencies, result.LatencyMillis)
+		if result.TTFTMillis > 0 {
+			s.ttfts = append(s.ttfts, result.TTFTMillis)
+		}
+	default:
+		s.Failed++
+	}
+	s.StatusCodes[result.StatusCode]++
+}
+
+// finish computes the percentiles once all the results are added.
+func (s *Summary) finish(duration time.Duration) {
+	s.Duration = duration
+	s.Latency = percentiles(s.latencies)
+	s.TTFT = percentiles(s.ttfts)
+}
+
+func percentiles(values []float64) Percentiles {
+	if len(values) == 0 {
+		return Percentiles{}
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	at := func(q float64) float64 {
+		return sorted[int(q*float64(len(sorted)-1))]
+	}
+	return Percentiles{P50: at(0.5), P90: at(0.9), P99: at(0.99), Max: sorted[len(sorted)-1]}
+}
+
+// Write writes the summary in a human readable form.
+func (s *Summary) Write(w io.Writer) error {
+	codes := make([]int, 0, len(s.StatusCodes))
+	for code := range s.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	statuses := ""
+	for _, code := range codes {
+		statuses += fmt.Sprintf(" %d=%d", code, s.StatusCodes[code])
+	}
+	throughput := 0.0
+	if s.Duration > 0 {
+		throughput = float64(s.CompletionTokens) / s.Duration.Seconds()
+	}
+	_, err := fmt.Fprintf(w, `run:               %s
+duration:          %s
+requests:          %d (succeeded %d, failed %d, dropped %d)
+status codes:     %s
+latency (ms):      p50 %.1f, p90 %.1f, p99 %.1f, max %.1f
+ttft (ms):         p50 %.1f, p90 %.1f, p99 %.1f, max %.1f
+output tokens/s:   %.1f
+`, s.RunID, s.Duration.Round(time.Millisecond), s.Requests, s.Succeeded, s.Failed, s.Dropped, statuses,
+		s.Latency.P50, s.Latency.P90, s.Latency.P99, s.Latency.Max,
+		s.TTFT.P50, s.TTFT.P90, s.TTFT.P99, s.TTFT.Max, throughput)
+	return err
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scheduler is the library surface of the EPP scheduling framework, for projects that
+// embed the scheduler (e.g. custom gateways or batch routers) rather than deploying EPP.
+//
+// The scheduler picks the endpoint to send a request to by running scheduler profiles, each made
+// of PreCycle, Filter, Scorer, Picker and PostCycle plugins, and selected by a ProfilePicker. The endpoints
+// and their metrics are provided by the embedder through an EndpointLister, so no Kubernetes
+// machinery is required:
+//
+//	profile := scheduler.NewProfile()
+//	if err := profile.AddPlugins(scheduler.NewWeightedScorer(myScorer, 1), myPicker); err != nil {
+//		...
+//	}
+//	s := scheduler.New(lister, scheduler.NewConfig(myProfilePicker, map[string]*scheduler.Profile{"default": profile}))
+//	results, err := s.Schedule(ctx, &scheduler.Request{TargetModel: "llama"})
+//
+// Plugins can also be declared in a config file and built with NewConfigFromPlugins, see
+// LoadPluginsConfig. The embedder adds its own plugins with Register, and they can take parameters
+// from the config file like the built-in ones.
+//
+// # Compatibility
+//
+// This package follows the semantic versioning of the module: the identifiers exported by this
+// package, and the methods and fields of the types it exports, are not removed or changed
+// incompatibly within a major version. New methods may be added to the plugin interfaces only
+// with a new major version. The packages under pkg/epp it aliases are implementation details of
+// EPP and carry no such guarantee, so embedders should only import this package.
+package scheduler
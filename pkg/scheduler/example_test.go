@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/scheduler"
+)
+
+func Example() {
+	endpoints := scheduler.EndpointListerFunc(func() []scheduler.Endpoint {
+		return []scheduler.Endpoint{
+			{Name: types.NamespacedName{Name: "busy"}, Address: "10.0.0.1", Metrics: &scheduler.Metrics{WaitingQueueSize: 10}},
+			{Name: types.NamespacedName{Name: "idle"}, Address: "10.0.0.2", Metrics: &scheduler.Metrics{WaitingQueueSize: 1}},
+		}
+	})
+
+	pluginsConfig, err := scheduler.LoadPluginsConfig([]byte(`
+profilePicker: all-profiles
+profiles:
+- name: default
+  plugins:
+  - name: queue
+    weight: 1
+  - name: max_score
+`))
+	if err != nil {
+		panic(err)
+	}
+	config, err := scheduler.NewConfigFromPlugins(pluginsConfig, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	s := scheduler.New(endpoints, config)
+	results, err := s.Schedule(context.Background(), &scheduler.Request{TargetModel: "llama"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(results["default"].TargetPod.GetPod().Address)
+	// Output: 10.0.0.2
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend"
+	backendmetrics "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/backend/metrics"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework"
+	profilepicker "sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/framework/plugins/profile-picker"
+	"sigs.k8s.io/gateway-api-inference-extension/pkg/epp/scheduling/types"
+)
+
+// The scheduler and its configuration.
+type (
+	// Scheduler schedules requests to endpoints by running scheduler profiles.
+	Scheduler = scheduling.Scheduler
+	// Config is the configuration of a Scheduler, see NewConfig.
+	Config = scheduling.SchedulerConfig
+	// DecisionReuseConfig configures the reuse of recent scheduling decisions under overload.
+	DecisionReuseConfig = scheduling.DecisionReuseConfig
+	// Profile is a scheduler profile, i.e. a set of plugins run in a scheduling cycle.
+	Profile = framework.SchedulerProfile
+)
+
+// The plugin interfaces.
+type (
+	Plugin               = framework.Plugin
+	ProfilePicker        = framework.ProfilePicker
+	PreCycle             = framework.PreCycle
+	Filter               = framework.Filter
+	Scorer               = framework.Scorer
+	Picker               = framework.Picker
+	PostCycle            = framework.PostCycle
+	PostSchedule         = framework.PostSchedule
+	PostResponse         = framework.PostResponse
+	PostResponseComplete = framework.PostResponseComplete
+	RequestEnd           = framework.RequestEnd
+	// WeightedScorer is a Scorer with the weight of its scores, see NewWeightedScorer.
+	WeightedScorer = framework.WeightedScorer
+)
+
+// The types the plugins operate on.
+type (
+	Request           = types.LLMRequest
+	Response          = types.LLMResponse
+	Result            = types.Result
+	Pod               = types.Pod
+	ScoredPod         = types.ScoredPod
+	SchedulingContext = types.SchedulingContext
+	CycleState        = types.CycleState
+	StateData         = types.StateData
+	StateKey          = types.StateKey
+	// Metrics are the metrics of an endpoint, as reported by its model server.
+	Metrics = backendmetrics.MetricsState
+)
+
+// TypedStateKey is a key of the data of type T stored in the CycleState.
+type TypedStateKey[T StateData] = types.TypedStateKey[T]
+
+// The declarative plugins configuration.
+type (
+	PluginsConfig = scheduling.PluginsConfig
+	ProfileConfig = scheduling.ProfileConfig
+	PluginConfig  = scheduling.PluginConfig
+	// FactoryFunc creates a plugin from its parameters in the plugins configuration, see Register.
+	FactoryFunc = framework.FactoryFunc
+	// Handle is given to the factories of the plugins, see NewConfigFromPlugins.
+	Handle = framework.Handle
+)
+
+// Endpoint is a model server endpoint requests can be scheduled to.
+type Endpoint struct {
+	// Name identifies the endpoint. It's the name of the pod for Kubernetes endpoints, while the
+	// namespace can be left empty otherwise.
+	Name k8stypes.NamespacedName
+	// Address is the address of the endpoint, e.g. its IP.
+	Address string
+	// Labels are used by the plugins selecting endpoints by label. The role of the endpoint is given by
+	// the inference.networking.x-k8s.io/role label.
+	Labels map[string]string
+	// Metrics are the latest metrics of the endpoint. Nil if they are unknown.
+	Metrics *Metrics
+}
+
+// EndpointLister lists the endpoints requests can be scheduled to. It's invoked for every
+// request, and must be safe for concurrent use.
+type EndpointLister interface {
+	Endpoints() []Endpoint
+}
+
+// EndpointListerFunc is an EndpointLister function.
+type EndpointListerFunc func() []Endpoint
+
+// Endpoints returns the endpoints.
+func (f EndpointListerFunc) Endpoints() []Endpoint {
+	return f()
+}
+
+// New returns a new Scheduler scheduling requests to the endpoints of the given lister with the
+// given configuration.
+func New(endpoints EndpointLister, config *Config) *Scheduler {
+	return scheduling.NewSchedulerWithConfig(&datastore{endpoints: endpoints}, config)
+}
+
+// NewConfig returns a new scheduler Config running the profiles picked by the given profile picker.
+func NewConfig(profilePicker ProfilePicker, profiles map[string]*Profile) *Config {
+	return scheduling.NewSchedulerConfig(profilePicker, profiles)
+}
+
+// NewProfile returns a new empty scheduler Profile.
+func NewProfile() *Profile {
+	return framework.NewSchedulerProfile()
+}
+
+// NewWeightedScorer returns the given scorer with the given weight, to be added to a Profile.
+func NewWeightedScorer(scorer Scorer, weight int) *WeightedScorer {
+	return framework.NewWeightedScorer(scorer, weight)
+}
+
+// NewAllProfilesPicker returns a ProfilePicker running all the profiles of the scheduler once.
+func NewAllProfilesPicker() ProfilePicker {
+	return profilepicker.NewAllProfilesPicker()
+}
+
+// Register registers the factory of a plugin under the given name, so that it can be referenced and
+// given parameters in the plugins configuration. It's meant to
+// be called from an init function, and panics if the name is empty or already registered.
+func Register(name string, factory FactoryFunc) {
+	framework.Register(name, factory)
+}
+
+// DecodeParameters decodes the raw JSON parameters given to a FactoryFunc into the given pointer,
+// rejecting unknown fields. Empty parameters leave the value as it is, e.g. with its defaults.
+func DecodeParameters(parameters json.RawMessage, into any) error {
+	return framework.DecodeParameters(parameters, into)
+}
+
+// NewConfigFromPlugins returns a new scheduler Config with the built-in plugins and the ones given to
+// Register referenced by the given plugins configuration. Their factories are given the handle,
+// which may be nil.
+func NewConfigFromPlugins(config *PluginsConfig, handle Handle) (*Config, error) {
+	return scheduling.NewSchedulerConfigFromPlugins(config, handle)
+}
+
+// LoadPluginsConfig parses a YAML or JSON plugins configuration. The scheduler Config is then
+// built with NewConfigFromPlugins.
+func LoadPluginsConfig(data []byte) (*PluginsConfig, error) {
+	return scheduling.LoadPluginsConfig(data)
+}
+
+// datastore adapts an EndpointLister to the datastore of the scheduler.
+type datastore struct {
+	endpoints EndpointLister
+}
+
+func (d *datastore) PodGetAll() []backendmetrics.PodMetrics {
+	endpoints := d.endpoints.Endpoints()
+	res := make([]backendmetrics.PodMetrics, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		metrics := endpoint.Metrics
+		if metrics == nil {
+			metrics = &Metrics{}
+		}
+		res = append(res, &endpointMetrics{
+			pod: &backend.Pod{
+				NamespacedName: endpoint.Name,
+				Address:        endpoint.Address,
+				Labels:         endpoint.Labels,
+				Role:           backend.PodRoleFromLabels(endpoint.Labels),
+			},
+			metrics: metrics,
+		})
+	}
+	return res
+}
+
+// compile-time type assertion
+var _ backendmetrics.PodMetrics = &endpointMetrics{}
+
+// endpointMetrics is a static backendmetrics.PodMetrics of an Endpoint.
+type endpointMetrics struct {
+	pod     *backend.Pod
+	metrics *Metrics
+}
+
+func (m *endpointMetrics) GetPod() *backend.Pod                                  { return m.pod }
+func (m *endpointMetrics) GetMetrics() *backendmetrics.MetricsState              { return m.metrics }
+func (m *endpointMetrics) GetMetricsHistory() *backendmetrics.MetricsHistory     { return nil }
+func (m *endpointMetrics) GetRequestsInFlight() *backendmetrics.RequestsInFlight { return nil }
+func (m *endpointMetrics) UpdatePod(*corev1.Pod)                                 {}
+func (m *endpointMetrics) SetCordoned(bool)                                      {}
+func (m *endpointMetrics) RecordAdapterLoaded(string)                            {}
+func (m *endpointMetrics) PushMetrics(*backendmetrics.PushedMetrics)             {}
+func (m *endpointMetrics) StopRefreshLoop()                                      {}
+func (m *endpointMetrics) String() string                                        { return m.pod.String() }